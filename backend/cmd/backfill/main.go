@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/tasnint/coinsights/internal/backfill"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+// Build a 12-24 month historical complaint baseline per category by walking
+// YouTube search one calendar month at a time.
+//
+// Usage:
+//
+//	go run ./cmd/backfill -months 12 -queries 5 -yes
+func main() {
+	dataDir := flag.String("data", "data", "data directory")
+	months := flag.Int("months", 12, fmt.Sprintf("calendar months to walk back (max %d)", backfill.MaxMonths))
+	maxQueries := flag.Int("queries", 5, "number of config.SearchQueries to run per month")
+	videos := flag.Int("videos", 5, "videos per query per month")
+	comments := flag.Int("comments", 20, "comments per video")
+	confirm := flag.Bool("yes", false, "skip the quota confirmation")
+	flag.Parse()
+
+	godotenv.Load(".env", "../.env")
+	apiKey := os.Getenv("YOUTUBE_API_KEY")
+	if apiKey == "" {
+		log.Fatal("❌ YOUTUBE_API_KEY not set")
+	}
+
+	queries := config.SearchQueries
+	if *maxQueries > 0 && *maxQueries < len(queries) {
+		queries = queries[:*maxQueries]
+	}
+
+	settings := backfill.Settings{
+		Months:           *months,
+		Queries:          queries,
+		VideosPerQuery:   *videos,
+		CommentsPerVideo: *comments,
+	}
+
+	fmt.Println("🕰️  Coinsights Historical Backfill")
+	fmt.Println("==================================")
+	fmt.Printf("📅 Months:            %d\n", settings.Months)
+	fmt.Printf("🔎 Queries per month: %d\n", len(queries))
+	fmt.Printf("💰 Estimated quota:   ~%d units (out of %d/day)\n", settings.EstimateQuota(), config.YouTubeDailyQuota)
+
+	if !*confirm {
+		fmt.Print("\nProceed? [y/N] ")
+		var answer string
+		fmt.Scanln(&answer)
+		if !strings.HasPrefix(strings.ToLower(answer), "y") {
+			fmt.Println("Aborted.")
+			return
+		}
+	}
+
+	runner := backfill.NewRunner(scrapers.NewYouTubeScraper(apiKey), *dataDir)
+	baseline, err := runner.Run(settings, time.Now())
+	if err != nil {
+		log.Printf("⚠️  Backfill stopped early: %v (re-run to resume)", err)
+	}
+	if baseline != nil {
+		fmt.Printf("\n✅ Baseline has %d months\n", len(baseline.Months))
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+// Classify the comments keyword analysis couldn't categorize with a Gemini batch job.
+// Batches are cheaper but slow, so this is meant to run overnight; if it is interrupted,
+// re-running it collects the pending job instead of submitting a new one. Results are
+// merged into analyzer.ClassificationsFile, which the analyzer reads, so comments
+// classified by an earlier batch aren't sent again.
+//
+// Usage:
+//
+//	go run ./cmd/classify -data data -poll 5m
+func main() {
+	dataDir := flag.String("data", "data", "data directory containing youtube_latest_results.json")
+	poll := flag.Duration("poll", scrapers.DefaultBatchPollInterval, "how often to check on the batch job")
+	flag.Parse()
+
+	godotenv.Load(".env", "../.env")
+	if err := ratelimit.LoadState(*dataDir); err != nil {
+		log.Printf("⚠️  %v - daily request caps start from zero", err)
+	}
+
+	ctx := context.Background()
+	gs, err := scrapers.NewGeminiScraper()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer gs.Close()
+
+	jobPath := filepath.Join(*dataDir, scrapers.BatchJobFile)
+	job, err := scrapers.LoadBatchJob(jobPath)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if job != nil && job.Kind != scrapers.BatchKindClassify {
+		log.Fatalf("❌ a %s batch (%s) is still pending in %s", job.Kind, job.Name, jobPath)
+	}
+
+	saved, err := analyzer.LoadClassifications(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if job == nil {
+		ytAnalyzer := analyzer.NewYouTubeAnalyzer()
+		ytAnalyzer.SetClassifications(saved.Classifications)
+		if _, err := ytAnalyzer.AnalyzeFile(filepath.Join(*dataDir, "youtube_latest_results.json")); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		texts := ytAnalyzer.Uncategorized()
+		if len(texts) == 0 {
+			fmt.Println("✅ Every comment is already categorized")
+			return
+		}
+
+		job, err = gs.SubmitClassifyBatch(ctx, texts)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if err := scrapers.SaveBatchJob(jobPath, job); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	} else {
+		fmt.Printf("📦 Resuming batch %s submitted %s\n", job.Name, job.SubmittedAt.Format("2006-01-02 15:04"))
+	}
+
+	responses, err := gs.WaitBatch(ctx, job, *poll)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	categories := scrapers.ClassifyBatchResults(job, responses)
+
+	maps.Copy(saved.Classifications, categories)
+	saved.RunID = job.RunID
+	saved.GeneratedAt = time.Now()
+	saved.Submitted = len(job.Inputs)
+	if err := saved.Save(*dataDir); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	os.Remove(jobPath)
+
+	fmt.Printf("✅ Classified %d of %d comments, saved to: %s\n", len(categories), len(job.Inputs), filepath.Join(*dataDir, analyzer.ClassificationsFile))
+}
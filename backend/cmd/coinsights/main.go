@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/announcements"
+	"github.com/tasnint/coinsights/internal/api/handlers"
+	"github.com/tasnint/coinsights/internal/api/openapi"
+	"github.com/tasnint/coinsights/internal/api/server"
+	"github.com/tasnint/coinsights/internal/audit"
+	"github.com/tasnint/coinsights/internal/auth"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/ingest"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/ratings"
+	"github.com/tasnint/coinsights/internal/scheduler"
+	"github.com/tasnint/coinsights/internal/scorecards"
+	"github.com/tasnint/coinsights/internal/scrapesettings"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/subscriptions"
+	"github.com/tasnint/coinsights/internal/taxonomy"
+	"github.com/tasnint/coinsights/internal/tenants"
+	"github.com/tasnint/coinsights/internal/trackers"
+	"github.com/tasnint/coinsights/internal/trends"
+)
+
+// The Coinsights API server. serve mounts the data endpoints and the issue,
+// resolution and attestation endpoints on one mux behind the shared middleware:
+// API key authentication, OpenAPI validation, per-route timeouts and request body
+// limits. Without blockchain settings the attestation endpoints answer 503; with
+// MIRROR_PRIMARY_URL set the server is a read-only mirror of that primary. SCRAPE_SCHEDULE_YOUTUBE, _GEMINI and
+// _GOOGLE run those scrapes on cron schedules and reload the data after each run;
+// files written by other processes are picked up every DATA_WATCH_INTERVAL.
+//
+// Usage:
+//
+//	go run ./cmd/coinsights serve -data ../data
+//	go run ./cmd/coinsights serve -data ../data -port 8443 -tls-cert cert.pem -tls-key key.pem
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("usage: coinsights serve [-data dir] [-exchange name] [listener flags]")
+		os.Exit(2)
+	}
+
+	switch cmd := os.Args[1]; cmd {
+	case "serve":
+		serve(os.Args[2:])
+	default:
+		log.Fatalf("❌ unknown command: %s", cmd)
+	}
+}
+
+func serve(args []string) {
+	godotenv.Load(".env", "../.env")
+
+	cfg, err := server.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	dataDir := fs.String("data", "data", "data directory the scraper writes to")
+	exchange := fs.String("exchange", "coinbase", "exchange the scraped data covers")
+	cfg.RegisterFlags(fs)
+	fs.Parse(args)
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	identity, err := httpclient.IdentityFromEnv(config.Identity)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	config.Identity = identity
+	stages, err := analyzer.StagesFromEnv(config.AnalyzerStages)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	config.AnalyzerStages = stages
+	if err := ratelimit.LoadState(*dataDir); err != nil {
+		log.Printf("⚠️  %v - daily request caps start from zero", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("🚀 Coinsights API Starting...")
+	fmt.Println("============================")
+
+	// ================================================
+	// BLOCKCHAIN - optional, attestation endpoints answer 503 without it
+	// ================================================
+	bs, err := services.NewBlockchainService()
+	if err != nil {
+		fmt.Printf("⚠️  Blockchain disabled: %v\n", err)
+		bs = nil
+	} else {
+		defer bs.Close()
+		registry, err := services.NewAttestorRegistry(*dataDir)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if err := bs.SetAttestorRegistry(registry); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Printf("⛓️  Attesting on %s\n", bs.GetChainInfo().Name)
+	}
+
+	rs := services.NewResolutionService(bs)
+	if bs != nil {
+		if explorer := services.NewExplorerClientFromEnv(bs.GetChainInfo()); explorer != nil {
+			rs.SetExplorer(explorer)
+		}
+	}
+	names, err := services.NewNameResolverFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if names != nil {
+		rs.SetNameResolver(names)
+	}
+	prices, err := services.NewPriceFeedFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	rs.SetPriceFeed(prices)
+	evidence, err := services.NewEvidenceStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	pinner, err := services.NewIPFSPinnerFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if pinner != nil {
+		evidence.EnablePinning(pinner)
+	}
+	rs.SetEvidenceStore(evidence)
+
+	mirror, err := services.NewMirrorFromEnv(rs)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	queue, err := services.NewAttestationQueueFromEnv(rs)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	var indexer *services.AttestationIndexer
+	if bs != nil {
+		fromBlock, err := services.IndexFromBlockFromEnv()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if indexer, err = services.NewAttestationIndexer(bs, *dataDir, fromBlock); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+
+	// ================================================
+	// DATA AND STORES
+	// ================================================
+	complaintTrends, err := trends.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	data := handlers.NewDataHandler(*dataDir)
+	data.EnableTrends(complaintTrends)
+	if mirror == nil {
+		// A mirror's issues come from its primary, not its own detection
+		data.EnableIssueDetection(rs, *exchange, config.DefaultDetectionSettings())
+		detector, err := services.NewResolutionDetector(rs, *dataDir)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		data.EnableResolutionDetection(detector)
+	}
+	if err := data.Reload(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	labels, err := audit.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	categories, err := taxonomy.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	scraperSettings, err := scrapesettings.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	subs, err := subscriptions.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	ratingStore, err := ratings.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	ingestStore, err := ingest.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	ingestKeys, err := ingest.KeysFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	scorecardHistory, err := scorecards.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	announcementStore, err := announcements.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	rs.SetAnnouncementLinker(announcementStore)
+	scrapeRuns, err := scheduler.NewRunStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	var scrapes *scheduler.Scheduler
+	if mirror == nil {
+		// A mirror's data comes from its primary, which runs the scrapes
+		if scrapes, err = scheduler.NewFromEnv(*dataDir, scrapeRuns); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
+	signingKey, err := subscriptions.SigningKeyFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	authn, err := auth.FromEnv(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	tenantStore, err := tenants.NewStore(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	for _, tenant := range tenantStore.List() {
+		rs.SetTenantCriteria(tenant.ID, tenant.Criteria)
+	}
+
+	rs.AddObserver(subscriptions.NewFanoutFromEnv(subs))
+	dispatcher, err := trackers.NewDispatcherFromEnv(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if dispatcher != nil {
+		fmt.Printf("🎫 Filing issues in %v\n", dispatcher.Trackers())
+		rs.AddObserver(dispatcher)
+	}
+
+	// ================================================
+	// ROUTES
+	// ================================================
+	mux := http.NewServeMux()
+
+	blockchain := handlers.NewBlockchainHandler(rs, bs)
+	if queue != nil {
+		blockchain.SetAttestationQueue(queue)
+	}
+	if indexer != nil {
+		blockchain.SetAttestationIndexer(indexer)
+	}
+	blockchain.EnableTenants(tenantStore)
+	blockchain.Register(mux)
+	data.Register(mux)
+
+	admin := handlers.NewAdminHandler(*dataDir)
+	admin.EnableAudits(data, labels)
+	admin.EnableTaxonomy(categories, data)
+	admin.EnableScraperSettings(scraperSettings)
+	admin.Register(mux)
+
+	handlers.NewSubscriptionHandler(subs, rs).Register(mux)
+	exchanges := handlers.NewExchangeHandler(rs, ratingStore)
+	exchanges.EnableSentiment(*exchange, data)
+	exchanges.EnableHistory(scorecardHistory)
+	exchanges.Register(mux)
+	handlers.NewDashboardHandler(data, rs).Register(mux)
+	ingestHandler := handlers.NewIngestHandler(ingestStore, ingestKeys, data)
+	ingestHandler.SetTenants(tenantStore)
+	ingestHandler.Register(mux)
+	handlers.NewEvidenceHandler(data, rs).Register(mux)
+	handlers.NewSearchHandler(rs).Register(mux)
+	handlers.NewFeedHandler(rs).Register(mux)
+	handlers.NewSigningKeyHandler(signingKey).Register(mux)
+	handlers.NewAnnouncementHandler(announcementStore, rs).Register(mux)
+	handlers.NewScrapeRunHandler(scrapeRuns, scrapes).Register(mux)
+	handlers.NewTenantHandler(tenantStore, rs).Register(mux)
+	if mirror != nil {
+		handlers.NewMirrorHandler(mirror).Register(mux)
+	}
+
+	validator, err := openapi.NewValidator()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	handler := validator.Middleware(handlers.WithTimeouts(mux, handlers.RouteTimeouts))
+	if mirror != nil {
+		handler = handlers.ReadOnly(handler)
+	}
+	if authn != nil {
+		fmt.Println("🔑 API keys required for writes")
+	}
+	handler = handlers.WithAuth(mux, handler, authn, handlers.DefaultAuthPolicy(tenantStore))
+	limits, err := handlers.BodyLimitsFromEnv(handlers.DefaultBodyLimits())
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	validator.SetMaxBodyBytes(limits.Largest())
+
+	// ================================================
+	// BACKGROUND WORK
+	// ================================================
+	if mirror != nil {
+		interval, err := services.MirrorSyncIntervalFromEnv()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fmt.Println("🪞 Read-only mirror: writes go to the primary")
+		go mirror.Run(ctx, interval)
+	} else {
+		go rs.RunSLAMonitor(ctx, services.DefaultSLACheckInterval)
+	}
+	if queue != nil {
+		go queue.Run(ctx)
+	}
+	if indexer != nil {
+		go indexer.Run(ctx, services.DefaultIndexInterval)
+	}
+	if scrapes != nil {
+		scrapes.OnRun = func(run scheduler.Run) {
+			if run.Status != scheduler.StatusSucceeded {
+				return
+			}
+			if err := data.Reload(); err != nil {
+				log.Printf("⚠️  Failed to reload data after %s: %v", run.ID, err)
+			}
+		}
+		for _, job := range scrapes.Jobs() {
+			fmt.Printf("⏰ Scraping %s on \"%s\" (next %s)\n", job.Name, job.Schedule, job.NextRun.Format(time.RFC3339))
+		}
+		go scrapes.Run(ctx)
+	}
+	go scorecards.RunRecorder(ctx, exchanges.Scorecards(), scorecardHistory, scorecards.DefaultSnapshotInterval)
+	watchInterval, err := handlers.DataWatchIntervalFromEnv()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if watchInterval > 0 {
+		go data.Watch(ctx, watchInterval)
+	}
+
+	if err := server.Run(ctx, cfg, handlers.WithBodyLimits(mux, handler, limits)); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Println("👋 API stopped")
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+)
+
+// Suggest category keywords from frequent phrases in uncategorized comments.
+// Suggestions are written to a review file rather than applied automatically.
+//
+// Usage:
+//
+//	go run ./cmd/keywords -data data -min 5 -top 50
+func main() {
+	dataDir := flag.String("data", "data", "data directory containing youtube_latest_results.json")
+	minOccurrences := flag.Int("min", 5, "minimum uncategorized comments a phrase must appear in")
+	top := flag.Int("top", 50, "maximum number of suggestions to write")
+	flag.Parse()
+
+	ytAnalyzer := analyzer.NewYouTubeAnalyzer()
+	if _, err := ytAnalyzer.AnalyzeFile(filepath.Join(*dataDir, "youtube_latest_results.json")); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	suggestions := ytAnalyzer.SuggestKeywords(*minOccurrences)
+	if len(suggestions) > *top {
+		suggestions = suggestions[:*top]
+	}
+
+	report := analyzer.KeywordSuggestionReport{
+		GeneratedAt:        time.Now(),
+		UncategorizedCount: ytAnalyzer.UncategorizedCount(),
+		Suggestions:        suggestions,
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("❌ failed to marshal suggestions: %v", err)
+	}
+
+	outPath := filepath.Join(*dataDir, analyzer.KeywordSuggestionsFile)
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		log.Fatalf("❌ failed to write suggestions: %v", err)
+	}
+
+	fmt.Printf("\n💡 %d keyword suggestions from %d uncategorized comments\n", len(suggestions), report.UncategorizedCount)
+	for i, s := range suggestions {
+		if i >= 10 {
+			break
+		}
+		fmt.Printf("   %-25s → %-18s (+%d comments, %.0f%% confidence)\n", s.Phrase, s.Category, s.ExpectedImpact, s.Confidence*100)
+	}
+	fmt.Printf("✅ Suggestions saved to: %s\n", outPath)
+}
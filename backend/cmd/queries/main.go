@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/tasnint/coinsights/internal/planner"
+)
+
+// Inspect per-query yield history and suggest queries to drop from config.SearchQueries.
+//
+// Usage:
+//
+//	go run ./cmd/queries stats
+//	go run ./cmd/queries prune -min-runs 3
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("usage: queries <stats|prune> [-data dir] [-min-runs n]")
+		os.Exit(2)
+	}
+
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	dataDir := fs.String("data", "data", "data directory containing "+planner.HistoryFile)
+	minRuns := fs.Int("min-runs", planner.DefaultPruneMinRuns, "runs required before a query can be pruned")
+	fs.Parse(os.Args[2:])
+
+	history, err := planner.LoadHistory(filepath.Join(*dataDir, planner.HistoryFile))
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	switch cmd {
+	case "stats":
+		fmt.Printf("%-40s %5s %8s %8s %10s %6s\n", "QUERY", "RUNS", "VIDEOS", "UNIQUE", "YIELD", "DUP%")
+		for _, y := range history.Stats() {
+			fmt.Printf("%-40s %5d %8d %8d %10.4f %5.0f%%\n",
+				y.Query, y.Runs, y.TotalVideos, y.TotalUniqueComplaints, y.Yield, y.DuplicateRate*100)
+		}
+	case "prune":
+		suggestions := history.PruneSuggestions(*minRuns)
+		if len(suggestions) == 0 {
+			fmt.Println("✅ No queries to prune")
+			return
+		}
+		fmt.Printf("✂️  %d queries suggested for removal from config.SearchQueries:\n", len(suggestions))
+		for _, s := range suggestions {
+			fmt.Printf("   - %q: %s\n", s.Query, s.Reason)
+		}
+	default:
+		log.Fatalf("❌ unknown command: %s", cmd)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/joho/godotenv"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// Rotate the attestation signing key. The current key (BLOCKCHAIN_PRIVATE_KEY) and the
+// new key (NEW_BLOCKCHAIN_PRIVATE_KEY) both sign a rotation statement, which is recorded
+// in the attestor key registry and optionally announced on-chain. Attestations from the
+// old key keep verifying for the blocks it was valid.
+//
+// Usage:
+//
+//	NEW_BLOCKCHAIN_PRIVATE_KEY=0x... go run ./cmd/rotatekey -onchain
+func main() {
+	dataDir := flag.String("data", "data", "data directory containing "+services.AttestorKeysFile)
+	overlap := flag.Uint64("overlap-blocks", services.DefaultRotationOverlapBlocks, "blocks the old key stays valid after the rotation")
+	onChain := flag.Bool("onchain", false, "announce the rotation on the attestation contract")
+	flag.Parse()
+
+	godotenv.Load(".env", "../.env")
+	newKeyHex := os.Getenv("NEW_BLOCKCHAIN_PRIVATE_KEY")
+	if newKeyHex == "" {
+		log.Fatal("❌ NEW_BLOCKCHAIN_PRIVATE_KEY not set")
+	}
+	newKey, err := crypto.HexToECDSA(strings.TrimPrefix(newKeyHex, "0x"))
+	if err != nil {
+		log.Fatalf("❌ invalid new private key: %v", err)
+	}
+
+	bs, err := services.NewBlockchainService()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	defer bs.Close()
+
+	registry, err := services.NewAttestorRegistry(*dataDir)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if err := bs.SetAttestorRegistry(registry); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	rotation, err := bs.PrepareKeyRotation(ctx, newKey, *overlap)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Println("🔑 Attestor Key Rotation")
+	fmt.Println("========================")
+	fmt.Printf("   Previous: %s (valid until block %d)\n", rotation.PreviousAttestor, rotation.PreviousValidUntilBlock)
+	fmt.Printf("   New:      %s (valid from block %d)\n", rotation.NewAttestor, rotation.EffectiveBlock)
+
+	if *onChain {
+		if err := bs.AnnounceKeyRotation(ctx, rotation); err != nil {
+			log.Fatalf("❌ on-chain announcement failed, registry unchanged: %v", err)
+		}
+		fmt.Printf("   ⛓️  Announced: %s/tx/%s\n", bs.GetChainInfo().ExplorerURL, rotation.TransactionHash)
+	}
+
+	if err := registry.ApplyRotation(*rotation); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	fmt.Printf("✅ Rotation recorded in %s\n", services.AttestorKeysFile)
+	fmt.Println("   Set BLOCKCHAIN_PRIVATE_KEY to the new key and restart the server before the overlap ends.")
+}
@@ -6,13 +6,27 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/announcements"
 	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/integrity"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/normalize"
+	"github.com/tasnint/coinsights/internal/planner"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/ratings"
 	"github.com/tasnint/coinsights/internal/scrapers"
+	"github.com/tasnint/coinsights/internal/scrapesettings"
+	"github.com/tasnint/coinsights/internal/textutil"
+	"github.com/tasnint/coinsights/internal/translate"
 )
 
 func main() {
@@ -34,6 +48,20 @@ func main() {
 		log.Println("Warning: .env file not found, using system environment variables")
 	}
 
+	identity, err := httpclient.IdentityFromEnv(config.Identity)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	config.Identity = identity
+	stages, err := analyzer.StagesFromEnv(config.AnalyzerStages)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	config.AnalyzerStages = stages
+	if err := ratelimit.LoadState("../../data"); err != nil {
+		log.Printf("⚠️  %v - daily request caps start from zero", err)
+	}
+
 	youtubeAPIKey := os.Getenv("YOUTUBE_API_KEY")
 	if youtubeAPIKey == "" || youtubeAPIKey == "your_youtube_api_key_here" {
 		log.Fatal("❌ YOUTUBE_API_KEY not set in .env file")
@@ -43,9 +71,14 @@ func main() {
 	fmt.Println("==========================================")
 
 	// ================================================
-	// CONFIGURATION - Edit in config/config.go
+	// CONFIGURATION - Presets in config/config.go, active one picked via PUT /api/admin/scraper-settings
 	// ================================================
-	settings := config.DefaultSettings() // Or use config.AggressiveSettings() or config.LightSettings()
+	presetName, settings := config.PresetDefault, config.DefaultSettings()
+	if presets, err := scrapesettings.NewStore("../../data"); err != nil {
+		log.Printf("⚠️  %v - using the default preset", err)
+	} else {
+		presetName, settings = presets.Active()
+	}
 	queries := config.SearchQueries
 
 	// Limit queries if MaxQueries is set
@@ -53,11 +86,30 @@ func main() {
 		queries = queries[:settings.MaxQueries]
 	}
 
+	// Build the query plan - adaptive mode spends quota where past runs found complaints
+	queryHistoryPath := filepath.Join("../../data", planner.HistoryFile)
+	queryHistory, err := planner.LoadHistory(queryHistoryPath)
+	if err != nil {
+		log.Printf("⚠️  %v", err)
+		queryHistory = planner.NewHistory()
+	}
+	var plan []models.QueryPlan
+	if settings.Adaptive {
+		plan = queryHistory.Plan(config.SearchQueries, settings)
+	} else {
+		for _, q := range queries {
+			plan = append(plan, models.QueryPlan{Query: q, MaxVideos: settings.VideosPerQuery})
+		}
+	}
+
 	// Show configuration
 	fmt.Println("\n⚙️  CONFIGURATION")
 	fmt.Println("-----------------")
+	fmt.Printf("🎛️  Settings preset:         %s\n", presetName)
 	fmt.Printf("📋 Total queries available: %d\n", len(config.SearchQueries))
-	fmt.Printf("🔎 Queries to run:          %d\n", len(queries))
+	fmt.Printf("🔎 Queries to run:          %d\n", len(plan))
+	fmt.Printf("🧠 Adaptive planning:       %v\n", settings.Adaptive)
+	fmt.Printf("🩳 Exclude Shorts:          %v\n", settings.ExcludeShorts)
 	fmt.Printf("📺 Videos per query:        %d\n", settings.VideosPerQuery)
 	fmt.Printf("💬 Comments per video:      %d\n", settings.CommentsPerVideo)
 	fmt.Printf("💰 Estimated quota usage:   ~%d units (out of 10,000/day)\n", settings.CalculateQuota())
@@ -65,38 +117,19 @@ func main() {
 	// Show queries being used
 	fmt.Println("\n🔍 SEARCH QUERIES")
 	fmt.Println("-----------------")
-	for i, q := range queries {
-		fmt.Printf("   %2d. %s\n", i+1, q)
+	for i, p := range plan {
+		fmt.Printf("   %2d. %s (%d videos)\n", i+1, p.Query, p.MaxVideos)
 	}
 
 	// ========================================
-	// YOUTUBE SCRAPING (Commented out to save quota while testing Gemini)
+	// YOUTUBE SCRAPING (off unless SCRAPE_YOUTUBE=true, to save quota while testing Gemini)
 	// ========================================
-	/*
-	// Initialize YouTube scraper
-	youtubeScraper := scrapers.NewYouTubeScraper(youtubeAPIKey)
-
-	// Scrape YouTube
-	fmt.Println("\n📺 SCRAPING YOUTUBE...")
-	fmt.Println("----------------------")
-	result, err := youtubeScraper.ScrapeAll(queries, settings.VideosPerQuery, settings.CommentsPerVideo)
-	if err != nil {
-		log.Printf("YouTube scraping error: %v", err)
-	}
-
-	// Save YouTube results to JSON file
-	fmt.Println("\n💾 SAVING YOUTUBE RESULTS...")
-	fmt.Println("--------------------")
-	err = saveResults(result)
-	if err != nil {
-		log.Printf("Error saving results: %v", err)
+	if enabled, _ := strconv.ParseBool(os.Getenv("SCRAPE_YOUTUBE")); enabled {
+		scrapeYouTube(youtubeAPIKey, settings, plan, queryHistory, queryHistoryPath)
+	} else {
+		fmt.Println("\n📺 YOUTUBE SCRAPING: Skipped (set SCRAPE_YOUTUBE=true to run it)")
 	}
 
-	// Print YouTube summary
-	printSummary(result)
-	*/
-	fmt.Println("\n📺 YOUTUBE SCRAPING: Skipped (commented out to save quota)")
-
 	// ========================================
 	// GEMINI AI SEARCH (Google AI Overview)
 	// ========================================
@@ -113,18 +146,39 @@ func main() {
 		} else {
 			defer geminiScraper.Close()
 
-			// Define AI search queries for Coinbase complaints from different sources
-			aiQueries := []string{
-				// Query 1: Reddit-focused complaints
-				"coinbase user complaints and problems from reddit discussions 2024 2025",
-				// Query 2: Article/website reviews and complaints
-				"coinbase customer complaints reviews from news articles trustpilot bbb consumer reports",
-				// Query 3: YouTube video content analysis (not comments)
-				"coinbase review video analysis problems issues discussed by youtubers crypto reviewers",
+			answers, err := scrapers.NewAnswerCacheFromEnv("../../data")
+			if err != nil {
+				log.Printf("⚠️  Gemini answer cache disabled: %v", err)
+			} else {
+				geminiScraper.EnableAnswerCache(answers)
+			}
+			geminiScraper.EnableExpansion(config.DefaultExpansionSettings())
+			budget, err := scrapers.GeminiBudgetFromEnv(config.DefaultGeminiBudget())
+			if err != nil {
+				log.Printf("⚠️  %v; using the default Gemini budget", err)
+			}
+			geminiScraper.SetBudget(budget)
+			grounding, err := scrapers.GroundingRulesFromEnv(config.DefaultGroundingRules())
+			if err != nil {
+				log.Printf("⚠️  %v; using the default grounding domains", err)
 			}
+			geminiScraper.SetGrounding(grounding)
 
-			ctx := context.Background()
-			aiResults, err := geminiScraper.SearchMultipleQueries(ctx, aiQueries)
+			// AI search queries for Coinbase complaints from different sources
+			aiQueries := config.AISearchQueries
+
+			// Ctrl-C stops after the current query and keeps the answers so far
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			var aiResults []scrapers.AIOverviewResult
+			if os.Getenv("GEMINI_BATCH") == "true" {
+				aiResults, err = searchBatch(ctx, geminiScraper, aiQueries)
+			} else {
+				aiResults, err = geminiScraper.SearchMultipleQueries(ctx, aiQueries)
+				usage := geminiScraper.Usage()
+				fmt.Printf("💸 Gemini usage: %d requests, %d tokens, ~$%.3f\n", usage.Requests, usage.Tokens(), usage.CostUSD)
+			}
+			stop()
+			recordHealth("gemini", err, geminiScraper.RunErrors(), ratelimit.ForSource("gemini").Used(), config.RateLimits["gemini"].DailyCap)
 			if err != nil {
 				log.Printf("⚠️  Gemini search error: %v", err)
 			} else {
@@ -134,12 +188,35 @@ func main() {
 					log.Printf("Error saving AI results: %v", err)
 				}
 
+				// Remember which complaints this run saw, so repeats keep their first-seen time
+				history, err := scrapers.NewComplaintHistory("../../data")
+				if err == nil {
+					err = history.Record(normalize.Gemini(aiResults, time.Now()))
+				}
+				if err != nil {
+					log.Printf("⚠️  Failed to update Gemini complaint history: %v", err)
+				}
+
 				// Print AI summary
 				printAISummary(aiResults)
 			}
 		}
 	}
 
+	// ========================================
+	// REDDIT SEARCH
+	// ========================================
+	fmt.Println("\n👽 SCRAPING REDDIT...")
+	fmt.Println("---------------------")
+	scrapeReddit(settings)
+
+	// ========================================
+	// REVIEW SITES (Trustpilot, BBB complaints)
+	// ========================================
+	fmt.Println("\n⭐ SCRAPING REVIEW SITES...")
+	fmt.Println("---------------------------")
+	scrapeReviews()
+
 	// ========================================
 	// ANALYZE EXISTING YOUTUBE DATA
 	// ========================================
@@ -149,6 +226,11 @@ func main() {
 	youtubeDataPath := "../../data/youtube_latest_results.json"
 	if _, err := os.Stat(youtubeDataPath); err == nil {
 		ytAnalyzer := analyzer.NewYouTubeAnalyzer()
+		if classifications, err := analyzer.LoadClassifications("../../data"); err != nil {
+			log.Printf("⚠️  %v", err)
+		} else {
+			ytAnalyzer.SetClassifications(classifications.Classifications)
+		}
 		analysisResult, err := ytAnalyzer.AnalyzeFile(youtubeDataPath)
 		if err != nil {
 			log.Printf("⚠️  Analysis error: %v", err)
@@ -156,6 +238,13 @@ func main() {
 			// Print summary to console
 			ytAnalyzer.PrintSummary(analysisResult)
 
+			// Annotate category movement since the previous run
+			if history, err := analyzer.NewAnalysisHistory("../../data"); err != nil {
+				log.Printf("⚠️  Failed to load analysis history: %v", err)
+			} else if err := history.Annotate(analysisResult); err != nil {
+				log.Printf("⚠️  Failed to record analysis history: %v", err)
+			}
+
 			// Save analysis results
 			analysisPath := "../../data/youtube_analysis.json"
 			if err := analyzer.SaveAnalysisResults(analysisResult, analysisPath); err != nil {
@@ -166,9 +255,308 @@ func main() {
 		fmt.Println("⚠️  No youtube_latest_results.json found. Run YouTube scraping first.")
 	}
 
+	// ========================================
+	// REVIEW SITE RATINGS (daily snapshot)
+	// ========================================
+	fmt.Println("\n⭐ RECORDING REVIEW SITE RATINGS...")
+	fmt.Println("----------------------------")
+	recordRatings(context.Background())
+
+	// ========================================
+	// EXCHANGE ANNOUNCEMENTS (linked to resolutions)
+	// ========================================
+	fmt.Println("\n📣 FETCHING EXCHANGE ANNOUNCEMENTS...")
+	fmt.Println("----------------------------")
+	recordAnnouncements(context.Background())
+
 	fmt.Println("\n✅ All scraping complete!")
 }
 
+// searchBatch runs the AI queries as one Gemini batch job. A job left pending by an
+// earlier (interrupted) run is collected instead of submitting a new one.
+func searchBatch(ctx context.Context, gs *scrapers.GeminiScraper, queries []string) ([]scrapers.AIOverviewResult, error) {
+	jobPath := filepath.Join("../../data", scrapers.BatchJobFile)
+	job, err := scrapers.LoadBatchJob(jobPath)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil && job.Kind != scrapers.BatchKindSearch {
+		return nil, fmt.Errorf("a %s batch (%s) is still pending in %s", job.Kind, job.Name, jobPath)
+	}
+	var cached []scrapers.AIOverviewResult
+	if job == nil {
+		// Only ask for what the answer cache can't cover
+		cached, queries = gs.CachedAnswers(queries)
+		if len(queries) == 0 {
+			fmt.Printf("♻️  All %d Gemini answers are fresh in the cache\n", len(cached))
+			return cached, nil
+		}
+		job, err = gs.SubmitSearchBatch(ctx, queries)
+		if err != nil {
+			return nil, err
+		}
+		if err := scrapers.SaveBatchJob(jobPath, job); err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Printf("📦 Resuming Gemini batch %s submitted %s\n", job.Name, job.SubmittedAt.Format("2006-01-02 15:04"))
+	}
+
+	responses, err := gs.WaitBatch(ctx, job, scrapers.DefaultBatchPollInterval)
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(jobPath)
+	results := scrapers.SearchBatchResults(job, responses)
+	gs.CacheAnswers(results)
+	for _, result := range cached {
+		result.RunID = job.RunID
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// scrapeYouTube runs the planned YouTube searches, saves the results and folds each
+// query's complaint yield into the history the adaptive planner reads
+func scrapeYouTube(apiKey string, settings config.ScraperSettings, plan []models.QueryPlan, queryHistory *planner.History, queryHistoryPath string) {
+	// Initialize YouTube scraper
+	youtubeScraper := scrapers.NewYouTubeScraper(apiKey)
+	youtubeScraper.Filter = scrapers.VideoFilter{
+		ExcludeShorts: settings.ExcludeShorts,
+		MinDuration:   settings.MinVideoDuration(),
+		MaxDuration:   settings.MaxVideoDuration(),
+	}
+	youtubeScraper.MaxPages = settings.MaxPages
+
+	// Scrape YouTube
+	fmt.Println("\n📺 SCRAPING YOUTUBE...")
+	fmt.Println("----------------------")
+	result, err := youtubeScraper.ScrapePlanned(plan, settings.CommentsPerVideo)
+	if err != nil {
+		log.Printf("YouTube scraping error: %v", err)
+	}
+	youtubeQuota := 0
+	var youtubeErrors []models.RunError
+	if result != nil {
+		for _, stat := range result.QueryStats {
+			youtubeQuota += stat.QuotaUsed
+		}
+		youtubeErrors = result.Errors
+	}
+	recordHealth("youtube", err, youtubeErrors, youtubeQuota, config.YouTubeDailyQuota)
+	if result == nil {
+		return
+	}
+
+	// Translate non-English comments so keyword analysis doesn't undercount them
+	if translate.Enabled() {
+		translateComments(result)
+	}
+
+	// Save YouTube results to JSON file
+	fmt.Println("\n💾 SAVING YOUTUBE RESULTS...")
+	fmt.Println("--------------------")
+	err = saveResults(result)
+	if err != nil {
+		log.Printf("Error saving results: %v", err)
+	}
+
+	// Print YouTube summary
+	printSummary(result)
+
+	// Update per-query yield history for the adaptive planner
+	runAnalyzer := analyzer.NewYouTubeAnalyzer()
+	runAnalyzer.AnalyzeResult(result)
+	queryHistory.Record(result, runAnalyzer.Issues())
+	if err := queryHistory.Save(queryHistoryPath); err != nil {
+		log.Printf("Error saving query history: %v", err)
+	}
+}
+
+// scrapeReddit searches the configured subreddits and saves the posts and comments
+// found, which the API normalizes into complaints alongside the YouTube data. The
+// active preset's thread depth and comment cap decide how much of each thread is read.
+func scrapeReddit(preset config.ScraperSettings) {
+	settings := preset.Forum(scrapers.RedditSettingsFromEnv(config.DefaultRedditSettings()))
+	redditScraper := scrapers.NewRedditScraperFromEnv()
+
+	// Ctrl-C stops the search and keeps the posts so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	result, err := redditScraper.ScrapeAll(ctx, settings)
+	recordHealth("reddit", err, redditScraper.RunErrors(), redditScraper.Limiter.Used(), 0)
+	if err != nil && len(result.Posts) == 0 {
+		log.Printf("⚠️  Reddit scraping error: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Printf("Error saving Reddit results: %v", err)
+		return
+	}
+	filename := filepath.Join("../../data", scrapers.RedditResultsFile)
+	if err := integrity.WriteFile(filename, data); err != nil {
+		log.Printf("Error saving Reddit results: %v", err)
+		return
+	}
+
+	comments := 0
+	for _, post := range result.Posts {
+		comments += len(post.Comments)
+	}
+	fmt.Printf("✅ Reddit: %d posts, %d comments from %v saved to %s\n", len(result.Posts), comments, settings.Subreddits, filename)
+}
+
+// scrapeReviews reads the newest Trustpilot reviews (and BBB complaints, if
+// BBB_PROFILE_URL is set) and saves them for the API to normalize into complaints
+func scrapeReviews() {
+	settings, err := scrapers.ReviewSettingsFromEnv(config.DefaultReviewSettings())
+	if err != nil {
+		log.Printf("⚠️  %v", err)
+		return
+	}
+	reviewScraper := scrapers.NewReviewScraper()
+
+	// Ctrl-C stops the crawl and keeps the reviews so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	result, err := reviewScraper.ScrapeAll(ctx, settings)
+	requests := reviewScraper.TrustpilotLimiter.Used() + reviewScraper.BBBLimiter.Used()
+	recordHealth("reviews", err, reviewScraper.RunErrors(), requests, 0)
+	if err != nil && len(result.Reviews) == 0 {
+		log.Printf("⚠️  Review scraping error: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Printf("Error saving reviews: %v", err)
+		return
+	}
+	filename := filepath.Join("../../data", scrapers.ReviewsResultsFile)
+	if err := integrity.WriteFile(filename, data); err != nil {
+		log.Printf("Error saving reviews: %v", err)
+		return
+	}
+	fmt.Printf("✅ Reviews: %d from Trustpilot/BBB saved to %s\n", len(result.Reviews), filename)
+}
+
+// translateComments replaces non-English comment text with an English translation,
+// keeping the original in OriginalText
+func translateComments(result *models.ScrapeResult) {
+	fmt.Println("\n🌐 TRANSLATING COMMENTS...")
+	fmt.Println("--------------------------")
+
+	ctx := context.Background()
+	translator, err := translate.NewGeminiTranslator(ctx)
+	if err != nil {
+		log.Printf("⚠️  Translation unavailable: %v", err)
+		return
+	}
+
+	count, err := translate.Comments(ctx, translator, result.Comments)
+	if err != nil {
+		log.Printf("⚠️  Translation error: %v", err)
+		return
+	}
+	fmt.Printf("✅ Translated %d comments\n", count)
+}
+
+// recordRatings snapshots the Trustpilot rating (and BBB, if BBB_PROFILE_URL is set)
+// into the rating history. Re-running on the same day overwrites that day's snapshot.
+func recordRatings(ctx context.Context) {
+	store, err := ratings.NewStore("../../data")
+	if err != nil {
+		log.Printf("⚠️  Failed to load rating history: %v", err)
+		return
+	}
+
+	trustpilotSlug := os.Getenv("TRUSTPILOT_SLUG")
+	if trustpilotSlug == "" {
+		trustpilotSlug = "www.coinbase.com"
+	}
+	pages := map[string]string{
+		"trustpilot": ratings.TrustpilotURL(trustpilotSlug),
+	}
+	if bbbURL := os.Getenv("BBB_PROFILE_URL"); bbbURL != "" {
+		pages["bbb"] = bbbURL
+	}
+
+	client := httpclient.New(httpclient.Standard)
+	for source, url := range pages {
+		rating, reviews, err := ratings.FetchAggregateRating(ctx, client, source, url)
+		recordHealth(source, err, nil, 1, 0)
+		if err != nil {
+			log.Printf("⚠️  %s rating unavailable: %v", source, err)
+			continue
+		}
+		snapshot := ratings.Snapshot{
+			Exchange:    "coinbase",
+			Source:      source,
+			Rating:      rating,
+			ReviewCount: reviews,
+			URL:         url,
+		}
+		if err := store.Record(snapshot); err != nil {
+			log.Printf("⚠️  Failed to record %s rating: %v", source, err)
+			continue
+		}
+		fmt.Printf("✅ %s: %.2f/5 from %d reviews\n", source, rating, reviews)
+	}
+}
+
+// recordAnnouncements fetches the exchange's changelog, blog and status feeds
+// (ANNOUNCEMENT_FEEDS, or announcements.DefaultFeeds) into the announcements the
+// API links resolutions to. Refetching updates entries already stored.
+func recordAnnouncements(ctx context.Context) {
+	store, err := announcements.NewStore("../../data")
+	if err != nil {
+		log.Printf("⚠️  Failed to load announcements: %v", err)
+		return
+	}
+	feeds, err := announcements.FeedsFromEnv()
+	if err != nil {
+		log.Printf("⚠️  %v", err)
+		return
+	}
+
+	client := httpclient.New(httpclient.Standard)
+	for _, feed := range feeds {
+		fetched, err := announcements.FetchFeed(ctx, client, "coinbase", feed)
+		recordHealth("announcements:"+feed.Source, err, nil, 1, 0)
+		if err != nil {
+			log.Printf("⚠️  %s announcements unavailable: %v", feed.Source, err)
+			continue
+		}
+		added, err := store.Add(fetched)
+		if err != nil {
+			log.Printf("⚠️  Failed to record %s announcements: %v", feed.Source, err)
+			continue
+		}
+		fmt.Printf("✅ %s: %d announcements (%d new)\n", feed.Source, len(fetched), added)
+	}
+}
+
+// recordHealth records a scraper run in the health file behind GET /api/admin/scrapers/status.
+// quotaUsed counts this run's spend against a daily quotaLimit (0 = unlimited);
+// runErrors are failures the run skipped past.
+func recordHealth(source string, runErr error, runErrors []models.RunError, quotaUsed, quotaLimit int) {
+	store, err := health.NewStore("../../data")
+	if err != nil {
+		log.Printf("⚠️  Failed to load scraper health: %v", err)
+		return
+	}
+	if runErr != nil {
+		err = store.RecordError(source, runErr)
+	} else {
+		err = store.RecordSuccess(source, quotaUsed, quotaLimit, runErrors)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to record %s health: %v", source, err)
+	}
+}
+
 func saveResults(result *models.ScrapeResult) error {
 	// Create data directory if it doesn't exist
 	dataDir := "../../data"
@@ -186,7 +574,7 @@ func saveResults(result *models.ScrapeResult) error {
 	}
 
 	// Write to file
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := integrity.WriteFile(filename, data); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -201,6 +589,9 @@ func printSummary(result *models.ScrapeResult) {
 	fmt.Printf("📺 YouTube Videos:   %d\n", len(result.Videos))
 	fmt.Printf("💬 YouTube Comments: %d\n", len(result.Comments))
 	fmt.Printf("⏰ Scraped at:       %s\n", result.ScrapedAt.Format("2006-01-02 15:04:05"))
+	if len(result.Errors) > 0 {
+		fmt.Printf("⚠️  Errors:           %d %v\n", len(result.Errors), result.ErrorCounts)
+	}
 
 	// Calculate total views and engagement
 	var totalViews, totalLikes int64
@@ -232,10 +623,7 @@ func printSummary(result *models.ScrapeResult) {
 				break
 			}
 			// Truncate long comments
-			text := comment.Text
-			if len(text) > 100 {
-				text = text[:100] + "..."
-			}
+			text := textutil.Truncate(comment.Text, config.Truncation.SummaryComment)
 			fmt.Printf("   %d. %s: \"%s\"\n", i+1, comment.AuthorName, text)
 		}
 	}
@@ -272,7 +660,7 @@ func saveAIResults(results []scrapers.AIOverviewResult) error {
 	}
 
 	// Write to file
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := integrity.WriteFile(filename, data); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -300,11 +688,7 @@ func printAISummary(results []scrapers.AIOverviewResult) {
 	for i, result := range results {
 		fmt.Printf("\n📌 Query %d: \"%s\"\n", i+1, result.Query)
 		if result.Summary != "" {
-			summary := result.Summary
-			if len(summary) > 300 {
-				summary = summary[:300] + "..."
-			}
-			fmt.Printf("   Summary: %s\n", summary)
+			fmt.Printf("   Summary: %s\n", textutil.Truncate(result.Summary, config.Truncation.SummaryOverview))
 		}
 
 		if len(result.KeyComplaints) > 0 {
@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// APP VERSION CORRELATION
+// ============================================
+
+var (
+	appVersionPattern = regexp.MustCompile(`(?i)\b(?:v|ver\.?\s*|version\s+)(\d{1,3}\.\d{1,3}(?:\.\d{1,3})?)\b`)
+	// OS versions ("Android version 12.1", "iOS v17.2") look like app versions and are removed first
+	osVersionPattern = regexp.MustCompile(`(?i)\b(?:android|ios|ipados)\s+(?:v|ver\.?\s*|version\s+)?\d{1,3}(?:\.\d{1,3}){0,2}\b`)
+	iosPattern       = regexp.MustCompile(`(?i)\b(?:ios|iphone|ipad|app store)\b`)
+	androidPattern   = regexp.MustCompile(`(?i)\b(?:android|pixel|samsung|galaxy|play store)\b`)
+)
+
+// UnknownVersion groups complaints that don't state an app version or OS
+const UnknownVersion = "unknown"
+
+// VersionDefects is the complaint volume for one app version on one OS
+type VersionDefects struct {
+	AppVersion string    `json:"app_version"`
+	OS         string    `json:"os"`
+	Count      int       `json:"count"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Links      []string  `json:"links"` // Up to 5 complaint URLs
+}
+
+// ExtractAppVersion finds an app version ("v12.3", "version 12.3.1") and OS family
+// ("ios", "android") mentioned in complaint text. Either may be empty. OS versions
+// ("Android version 12.1") aren't taken for app versions.
+func ExtractAppVersion(text string) (version, os string) {
+	if m := appVersionPattern.FindStringSubmatch(osVersionPattern.ReplaceAllString(text, " ")); m != nil {
+		version = m[1]
+	}
+	switch {
+	case iosPattern.MatchString(text):
+		os = "ios"
+	case androidPattern.MatchString(text):
+		os = "android"
+	}
+	return version, os
+}
+
+// ComplaintVersion is the app version and OS a complaint is about: the ones its source
+// reported, or else those parsed from its text. Either may be empty.
+func ComplaintVersion(c models.Complaint) (version, os string) {
+	if c.AppVersion != "" || c.OS != "" {
+		return c.AppVersion, c.OS
+	}
+	return ExtractAppVersion(c.Description)
+}
+
+// InVersion filters complaints to an app version and OS; an empty one matches any.
+// A version matches its patch releases, so "12.3" takes in "12.3.1".
+func InVersion(complaints []models.Complaint, version, os string) []models.Complaint {
+	if version == "" && os == "" {
+		return complaints
+	}
+	matched := []models.Complaint{}
+	for _, c := range complaints {
+		v, o := ComplaintVersion(c)
+		if version != "" && v != version && !strings.HasPrefix(v, version+".") {
+			continue
+		}
+		if os != "" && !strings.EqualFold(o, os) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	return matched
+}
+
+// VersionBreakdown groups a category's complaints by app version and OS, most complaints first.
+// Complaints from app-store sources carry their version already; others are parsed from text.
+func VersionBreakdown(complaints []models.Complaint, category string) []VersionDefects {
+	groups := make(map[string]*VersionDefects)
+	for _, c := range complaints {
+		if c.Category != category {
+			continue
+		}
+		version, os := ComplaintVersion(c)
+		if version == "" {
+			version = UnknownVersion
+		}
+		if os == "" {
+			os = UnknownVersion
+		}
+
+		key := version + "|" + os
+		g, ok := groups[key]
+		if !ok {
+			g = &VersionDefects{AppVersion: version, OS: os, Links: []string{}}
+			groups[key] = g
+		}
+		g.Count++
+		seen := c.PublishedAt
+		if seen.IsZero() {
+			seen = c.ScrapedAt
+		}
+		if g.FirstSeen.IsZero() || seen.Before(g.FirstSeen) {
+			g.FirstSeen = seen
+		}
+		if seen.After(g.LastSeen) {
+			g.LastSeen = seen
+		}
+		if c.URL != "" && len(g.Links) < 5 && !containsString(g.Links, c.URL) {
+			g.Links = append(g.Links, c.URL)
+		}
+	}
+
+	breakdown := make([]VersionDefects, 0, len(groups))
+	for _, g := range groups {
+		breakdown = append(breakdown, *g)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Count != breakdown[j].Count {
+			return breakdown[i].Count > breakdown[j].Count
+		}
+		if c := compareVersions(breakdown[i].AppVersion, breakdown[j].AppVersion); c != 0 {
+			return c > 0
+		}
+		return breakdown[i].OS < breakdown[j].OS
+	})
+	return breakdown
+}
+
+// compareVersions orders dotted versions numerically; "unknown" sorts last
+func compareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == UnknownVersion {
+		return -1
+	}
+	if b == UnknownVersion {
+		return 1
+	}
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = atoiOrZero(pa[i])
+		}
+		if i < len(pb) {
+			y = atoiOrZero(pb[i])
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func atoiOrZero(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
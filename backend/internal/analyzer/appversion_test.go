@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// TestVersionBreakdownOrder checks groups tied on count and version are ordered by OS,
+// so the breakdown doesn't change between calls with map iteration order
+func TestVersionBreakdownOrder(t *testing.T) {
+	complaints := []models.Complaint{
+		{Category: "app_bugs", AppVersion: "12.3", OS: "ios"},
+		{Category: "app_bugs", AppVersion: "12.3"},
+		{Category: "app_bugs", AppVersion: "12.3", OS: "android"},
+		{Category: "app_bugs", AppVersion: "12.10", OS: "ios"},
+		{Category: "fees", AppVersion: "12.3", OS: "ios"},
+	}
+	want := []string{"12.10/ios", "12.3/android", "12.3/ios", "12.3/unknown"}
+
+	for range 20 {
+		breakdown := VersionBreakdown(complaints, "app_bugs")
+		if len(breakdown) != len(want) {
+			t.Fatalf("%d groups, want %d", len(breakdown), len(want))
+		}
+		for i, g := range breakdown {
+			if got := g.AppVersion + "/" + g.OS; got != want[i] {
+				t.Fatalf("group %d is %s, want %s", i, got, want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ============================================
+// BATCH CLASSIFICATIONS
+// ============================================
+// Comments no keyword matches are sent to Gemini in an overnight batch by
+// cmd/classify, which saves the category of each to ClassificationsFile. The
+// classify stage falls back to those categories, so a batch's results count on the
+// next analysis without any keyword being added.
+
+// ClassificationsFile holds the batch categories of comments, inside the data directory
+const ClassificationsFile = "comment_classifications.json"
+
+// Classifications is the content of ClassificationsFile
+type Classifications struct {
+	RunID           string            `json:"run_id"` // Scrape run of the last batch merged in
+	GeneratedAt     time.Time         `json:"generated_at"`
+	Submitted       int               `json:"submitted"`       // Comments sent in the last batch
+	Classifications map[string]string `json:"classifications"` // Category ID by comment text
+}
+
+// LoadClassifications reads ClassificationsFile from dataDir (empty if it doesn't exist yet)
+func LoadClassifications(dataDir string) (*Classifications, error) {
+	c := &Classifications{Classifications: map[string]string{}}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, ClassificationsFile))
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comment classifications: %w", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse comment classifications: %w", err)
+	}
+	if c.Classifications == nil {
+		c.Classifications = map[string]string{}
+	}
+	return c, nil
+}
+
+// Save writes the classifications to ClassificationsFile in dataDir
+func (c *Classifications) Save(dataDir string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal comment classifications: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, ClassificationsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write comment classifications: %w", err)
+	}
+	return nil
+}
+
+// SetClassifications gives the analyzer categories, by comment text, for comments
+// its keywords don't match. Categories it doesn't track ("other") leave the
+// comment uncategorized.
+func (a *YouTubeAnalyzer) SetClassifications(byText map[string]string) {
+	a.classified = byText
+}
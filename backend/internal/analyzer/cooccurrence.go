@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// CATEGORY CO-OCCURRENCE
+// ============================================
+// A complaint that hits several categories at once ("account locked and support
+// never answered") points at a broken process rather than a single bug. Every
+// analyzer splits a record into one complaint per category, so records are
+// regrouped by source and source ID before counting pairs.
+
+// CategoryPair is how often two categories appear in the same complaint
+type CategoryPair struct {
+	A       string  `json:"a"`
+	B       string  `json:"b"`
+	Count   int     `json:"count"`   // Records with both categories
+	Jaccard float64 `json:"jaccard"` // Count / records with either
+	Lift    float64 `json:"lift"`    // How much likelier together than if independent; > 1 means linked
+}
+
+// CoOccurrence is the category co-occurrence matrix over a set of complaints
+type CoOccurrence struct {
+	Records       int            `json:"records"`        // Distinct source records
+	MultiCategory int            `json:"multi_category"` // Records with two or more categories
+	Categories    []string       `json:"categories"`     // Row and column order of Matrix
+	Matrix        [][]int        `json:"matrix"`         // Matrix[i][j]: records with both; the diagonal is each category's total
+	Pairs         []CategoryPair `json:"pairs"`          // Off-diagonal pairs, most frequent first
+}
+
+// CategoryCoOccurrence counts which categories appear together in the same record.
+// Pairs seen in fewer than minCount records are left out of Pairs (not the matrix).
+func CategoryCoOccurrence(complaints []models.Complaint, minCount int) CoOccurrence {
+	type recordKey struct{ source, id string }
+	records := make(map[recordKey]map[string]bool)
+	for _, c := range complaints {
+		key := recordKey{c.Source, c.SourceID}
+		if c.SourceID == "" { // No raw ID (e.g. Gemini), so the wording identifies the record
+			key.id = c.Description
+		}
+		if records[key] == nil {
+			records[key] = make(map[string]bool)
+		}
+		records[key][c.Category] = true
+	}
+
+	totals := make(map[string]int)
+	for _, categories := range records {
+		for category := range categories {
+			totals[category]++
+		}
+	}
+
+	result := CoOccurrence{Records: len(records), Categories: []string{}, Matrix: [][]int{}, Pairs: []CategoryPair{}}
+	for category := range totals {
+		result.Categories = append(result.Categories, category)
+	}
+	sort.Strings(result.Categories)
+	index := make(map[string]int, len(result.Categories))
+	for i, category := range result.Categories {
+		index[category] = i
+		result.Matrix = append(result.Matrix, make([]int, len(result.Categories)))
+	}
+
+	for _, categories := range records {
+		if len(categories) > 1 {
+			result.MultiCategory++
+		}
+		for a := range categories {
+			for b := range categories {
+				result.Matrix[index[a]][index[b]]++
+			}
+		}
+	}
+
+	n := float64(result.Records)
+	for i, a := range result.Categories {
+		for j := i + 1; j < len(result.Categories); j++ {
+			b := result.Categories[j]
+			count := result.Matrix[i][j]
+			if count == 0 || count < minCount {
+				continue
+			}
+			result.Pairs = append(result.Pairs, CategoryPair{
+				A:       a,
+				B:       b,
+				Count:   count,
+				Jaccard: float64(count) / float64(totals[a]+totals[b]-count),
+				Lift:    float64(count) * n / float64(totals[a]*totals[b]),
+			})
+		}
+	}
+	sort.SliceStable(result.Pairs, func(i, j int) bool {
+		if result.Pairs[i].Count != result.Pairs[j].Count {
+			return result.Pairs[i].Count > result.Pairs[j].Count
+		}
+		return result.Pairs[i].Lift > result.Pairs[j].Lift
+	})
+	return result
+}
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ============================================
+// RUN-OVER-RUN DELTAS
+// ============================================
+// The dashboard shows how each category moved since the previous scrape run.
+// Rather than have the client diff two full analysis payloads, every analysis is
+// annotated against a compact snapshot of the previous run's categories, kept in
+// a small history file alongside the data.
+
+const (
+	// AnalysisHistoryFile holds per-run category snapshots inside the data directory
+	AnalysisHistoryFile = "analysis_history.json"
+	// MaxAnalysisHistory is how many runs the history keeps
+	MaxAnalysisHistory = 30
+)
+
+// CategoryDelta is how a category changed since the previous run
+type CategoryDelta struct {
+	PreviousCount int      `json:"previous_count"`
+	CountChange   int      `json:"count_change"`
+	PreviousRank  int      `json:"previous_rank,omitempty"` // 0 when the category wasn't ranked last run
+	RankChange    int      `json:"rank_change"`             // Positive = moved up
+	New           bool     `json:"new,omitempty"`           // No complaints in the previous run
+	NewExamples   []string `json:"new_examples"`            // Examples the previous run didn't have
+}
+
+// RunSnapshot is what the history keeps of one analysis
+type RunSnapshot struct {
+	RunID      string                      `json:"run_id"`
+	AnalyzedAt time.Time                   `json:"analyzed_at"`
+	Categories map[string]CategorySnapshot `json:"categories"`
+}
+
+// CategorySnapshot is one category's standing in a run
+type CategorySnapshot struct {
+	Count    int      `json:"count"`
+	Rank     int      `json:"rank"` // 1 = most complaints
+	Examples []string `json:"examples"`
+}
+
+// Snapshot reduces an analysis to its category counts, ranks and examples
+func Snapshot(result *AnalysisResult) RunSnapshot {
+	snapshot := RunSnapshot{
+		RunID:      result.RunID,
+		AnalyzedAt: result.AnalyzedAt,
+		Categories: make(map[string]CategorySnapshot, len(result.IssuesByCategory)),
+	}
+	for i, summary := range result.IssuesByCategory {
+		snapshot.Categories[summary.Category] = CategorySnapshot{
+			Count:    summary.Count,
+			Rank:     i + 1,
+			Examples: summary.TopExamples,
+		}
+	}
+	return snapshot
+}
+
+// AnnotateDeltas sets each category summary's Delta relative to previous, and lists
+// categories that had complaints then but none now. A nil previous leaves result as is.
+func AnnotateDeltas(result *AnalysisResult, previous *RunSnapshot) {
+	if previous == nil {
+		return
+	}
+	result.PreviousRunID = previous.RunID
+
+	current := make(map[string]bool, len(result.IssuesByCategory))
+	for i := range result.IssuesByCategory {
+		summary := &result.IssuesByCategory[i]
+		current[summary.Category] = true
+
+		before, ok := previous.Categories[summary.Category]
+		delta := &CategoryDelta{
+			PreviousCount: before.Count,
+			CountChange:   summary.Count - before.Count,
+			PreviousRank:  before.Rank,
+			New:           !ok || before.Count == 0,
+			NewExamples:   []string{},
+		}
+		if ok && before.Rank > 0 {
+			delta.RankChange = before.Rank - (i + 1)
+		}
+		for _, example := range summary.TopExamples {
+			if !slices.Contains(before.Examples, example) {
+				delta.NewExamples = append(delta.NewExamples, example)
+			}
+		}
+		summary.Delta = delta
+	}
+
+	result.DroppedCategories = []string{}
+	for category, before := range previous.Categories {
+		if before.Count > 0 && !current[category] {
+			result.DroppedCategories = append(result.DroppedCategories, category)
+		}
+	}
+	slices.Sort(result.DroppedCategories)
+}
+
+// AnalysisHistory stores RunSnapshots, oldest first, persisted to a JSON file.
+// A nil *AnalysisHistory is valid and annotates nothing.
+type AnalysisHistory struct {
+	path string
+	runs []RunSnapshot
+	mu   sync.Mutex
+}
+
+// NewAnalysisHistory loads the history from dataDir (empty if the file doesn't exist yet)
+func NewAnalysisHistory(dataDir string) (*AnalysisHistory, error) {
+	h := &AnalysisHistory{path: filepath.Join(dataDir, AnalysisHistoryFile)}
+
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read analysis history: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.runs); err != nil {
+		return nil, fmt.Errorf("failed to parse analysis history: %w", err)
+	}
+	return h, nil
+}
+
+// Annotate sets result's deltas against the latest other run in the history, then
+// records result. Analyzing the same run again (e.g. on every API reload) replaces
+// its snapshot, so the deltas stay relative to the run before it.
+func (h *AnalysisHistory) Annotate(result *AnalysisResult) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var previous *RunSnapshot
+	for i := len(h.runs) - 1; i >= 0; i-- {
+		if result.RunID == "" || h.runs[i].RunID != result.RunID {
+			previous = &h.runs[i]
+			break
+		}
+	}
+	AnnotateDeltas(result, previous)
+
+	// Runs without an ID (older scrape files) can't be told apart, so they aren't recorded
+	if result.RunID == "" {
+		return nil
+	}
+	snapshot := Snapshot(result)
+	if i := slices.IndexFunc(h.runs, func(r RunSnapshot) bool { return r.RunID == result.RunID }); i >= 0 {
+		h.runs[i] = snapshot
+	} else {
+		h.runs = append(h.runs, snapshot)
+	}
+	if len(h.runs) > MaxAnalysisHistory {
+		h.runs = h.runs[len(h.runs)-MaxAnalysisHistory:]
+	}
+	return h.save()
+}
+
+// save writes the history to disk. Must be called with h.mu held.
+func (h *AnalysisHistory) save() error {
+	data, err := json.MarshalIndent(h.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal analysis history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write analysis history: %w", err)
+	}
+	return nil
+}
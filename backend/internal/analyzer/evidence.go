@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// RESOLUTION EVIDENCE MEASUREMENTS
+// ============================================
+// The numbers an evidence bundle is built from. Each complaint is placed at its
+// publish time (scrape time when unknown) and scored with SentimentScore, the same
+// way every other series is, so a bundle can be re-derived from its complaints.
+
+// InPeriod returns the complaints in category (any category when empty) placed within [start, end)
+func InPeriod(complaints []models.Complaint, category string, start, end time.Time) []models.Complaint {
+	var out []models.Complaint
+	for _, c := range complaints {
+		if category != "" && c.Category != category {
+			continue
+		}
+		t := complaintTime(c)
+		if !t.Before(start) && t.Before(end) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// SummarizePeriod counts complaints, their mean sentiment and their sources over [start, end)
+func SummarizePeriod(complaints []models.Complaint, start, end time.Time) models.BundlePeriod {
+	period := models.BundlePeriod{Start: start, End: end, Sources: make(map[string]int)}
+	var sum float64
+	for _, c := range complaints {
+		period.Complaints++
+		period.Sources[DataSource(c)]++
+		if score, ok := SentimentScore(c.Title + "\n" + c.Description); ok {
+			sum += score
+			period.Scored++
+		}
+	}
+	if period.Scored > 0 {
+		period.Sentiment = sum / float64(period.Scored)
+	}
+	return period
+}
+
+// SentimentCurve buckets complaints by UTC day from start up to end, including empty days
+func SentimentCurve(complaints []models.Complaint, start, end time.Time) []models.CurvePoint {
+	type bucket struct {
+		count, scored int
+		sum           float64
+	}
+	byDay := make(map[string]*bucket)
+	for _, c := range complaints {
+		date := complaintTime(c).UTC().Format("2006-01-02")
+		b := byDay[date]
+		if b == nil {
+			b = &bucket{}
+			byDay[date] = b
+		}
+		b.count++
+		if score, ok := SentimentScore(c.Title + "\n" + c.Description); ok {
+			b.sum += score
+			b.scored++
+		}
+	}
+
+	curve := []models.CurvePoint{}
+	for day := start.UTC().Truncate(24 * time.Hour); day.Before(end); day = day.AddDate(0, 0, 1) {
+		point := models.CurvePoint{Date: day.Format("2006-01-02")}
+		if b := byDay[point.Date]; b != nil {
+			point.Complaints = b.count
+			point.Scored = b.scored
+			if b.scored > 0 {
+				point.Sentiment = b.sum / float64(b.scored)
+			}
+		}
+		curve = append(curve, point)
+	}
+	return curve
+}
+
+// Velocity measures how many complaints in category were posted a day over the
+// windowDays before now, and over the same span before that
+func Velocity(complaints []models.Complaint, category string, windowDays int, now time.Time) models.ComplaintVelocity {
+	window := time.Duration(windowDays) * 24 * time.Hour
+	start := now.Add(-window)
+	current := len(InPeriod(complaints, category, start, now))
+	previous := len(InPeriod(complaints, category, start.Add(-window), start))
+	return models.ComplaintVelocity{
+		WindowDays:     windowDays,
+		Complaints:     current,
+		PerDay:         float64(current) / float64(windowDays),
+		PreviousPerDay: float64(previous) / float64(windowDays),
+		MeasuredAt:     now,
+	}
+}
+
+// SampleComplaints picks up to n complaints, most-liked first, ties broken by ID
+// so the same complaints always yield the same sample
+func SampleComplaints(complaints []models.Complaint, n int) []models.Complaint {
+	sorted := append([]models.Complaint(nil), complaints...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Likes != sorted[j].Likes {
+			return sorted[i].Likes > sorted[j].Likes
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// DataSource is the data source a complaint came from: the part of Source before
+// any ":" detail ("youtube:comment" -> "youtube", "partner:acme" -> "partner")
+func DataSource(c models.Complaint) string {
+	source, _, _ := strings.Cut(c.Source, ":")
+	return source
+}
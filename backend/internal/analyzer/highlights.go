@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// KEYWORD HIGHLIGHTS
+// ============================================
+// A complaint counted under "security" is more convincing when the dashboard can
+// show the words that put it there. Highlights locate the keywords behind a
+// complaint's category with the same case-insensitive, word-boundary matching
+// the analyzer categorizes with. Complaints that recorded their keywords (YouTube)
+// use those; others use every keyword of their category.
+
+const (
+	// MaxHighlights caps the highlights returned for one complaint
+	MaxHighlights = 10
+	// HighlightContext is how many characters of text a snippet keeps on each side of the match
+	HighlightContext = 40
+)
+
+// highlightPatterns caches compiled keyword patterns (keyword -> *regexp.Regexp)
+var highlightPatterns sync.Map
+
+// Highlights returns where a complaint's categorizing keywords appear in its title
+// and description, in field then text order. Overlapping matches keep the earliest.
+func Highlights(c models.Complaint) []models.Highlight {
+	keywords := c.Keywords
+	if len(keywords) == 0 {
+		keywords = Keywords()[c.Category]
+	}
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	highlights := []models.Highlight{}
+	for _, field := range []struct{ name, text string }{{"title", c.Title}, {"description", c.Description}} {
+		type match struct {
+			keyword    string
+			start, end int // Byte offsets
+		}
+		var matches []match
+		for _, keyword := range keywords {
+			for _, loc := range keywordPattern(keyword).FindAllStringIndex(field.text, -1) {
+				matches = append(matches, match{keyword, loc[0], loc[1]})
+			}
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].start != matches[j].start {
+				return matches[i].start < matches[j].start
+			}
+			return matches[i].end > matches[j].end // Longer phrase first
+		})
+
+		covered := 0
+		for _, m := range matches {
+			if m.start < covered {
+				continue
+			}
+			covered = m.end
+			highlights = append(highlights, models.Highlight{
+				Field:   field.name,
+				Keyword: m.keyword,
+				Start:   utf16Len(field.text[:m.start]),
+				End:     utf16Len(field.text[:m.end]),
+				Snippet: snippet(field.text, m.start, m.end),
+			})
+			if len(highlights) == MaxHighlights {
+				return highlights
+			}
+		}
+	}
+	return highlights
+}
+
+// keywordPattern compiles the analyzer's matching rule for a keyword
+func keywordPattern(keyword string) *regexp.Regexp {
+	if cached, ok := highlightPatterns.Load(keyword); ok {
+		return cached.(*regexp.Regexp)
+	}
+	pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+	highlightPatterns.Store(keyword, pattern)
+	return pattern
+}
+
+// snippet returns text[start:end] with up to HighlightContext characters either
+// side, marking cut ends with an ellipsis
+func snippet(text string, start, end int) string {
+	from := start
+	for n := 0; n < HighlightContext && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := end
+	for n := 0; n < HighlightContext && to < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	s := text[from:to]
+	if from > 0 {
+		s = "…" + s
+	}
+	if to < len(text) {
+		s += "…"
+	}
+	return s
+}
+
+// utf16Len is the length of s in UTF-16 code units, the unit JavaScript indexes strings in
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// KEYWORD EFFECTIVENESS
+// ============================================
+// Suggestions add keywords; this report finds the ones to retire. Every keyword
+// gets credit for the complaints it matched, and audit labels on those complaints
+// estimate how often the match put the complaint in the wrong category. Keywords
+// that never match, or whose matches are mostly wrong (bare "help", "price"),
+// are flagged for removal from the category keyword lists.
+
+const (
+	// KeywordMinLabels is how many labeled matches a keyword needs before its
+	// false-positive rate can flag it for retirement
+	KeywordMinLabels = 5
+	// KeywordRetireFalsePositiveRate flags keywords whose labeled matches are at least this often wrong
+	KeywordRetireFalsePositiveRate = 0.5
+)
+
+// KeywordEffectivenessReport rates every category keyword in use
+type KeywordEffectivenessReport struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Complaints  int            `json:"complaints"` // Keyword-categorized complaints considered
+	Labeled     int            `json:"labeled"`    // Of those, how many carry an audit label
+	Keywords    []KeywordStats `json:"keywords"`   // Retirement candidates first
+}
+
+// KeywordStats is how one category keyword performed
+type KeywordStats struct {
+	Category          string   `json:"category"`
+	Keyword           string   `json:"keyword"`
+	Matches           int      `json:"matches"`             // Complaints the keyword matched
+	SoleMatches       int      `json:"sole_matches"`        // Matches no other keyword of the category backed up
+	Labeled           int      `json:"labeled"`             // Matches with an audit label
+	FalsePositives    int      `json:"false_positives"`     // Labeled matches the auditor marked wrong
+	FalsePositiveRate *float64 `json:"false_positive_rate"` // FalsePositives / Labeled; nil until a match is labeled
+	Retire            bool     `json:"retire"`
+	Reason            string   `json:"reason,omitempty"` // Why Retire is set
+}
+
+// KeywordEffectiveness rates the keywords of every category against complaints
+// that record the keywords they matched. correct maps audited complaint IDs to
+// whether their category was confirmed.
+func KeywordEffectiveness(complaints []models.Complaint, correct map[string]bool) KeywordEffectivenessReport {
+	report := KeywordEffectivenessReport{GeneratedAt: time.Now(), Keywords: []KeywordStats{}}
+
+	stats := make(map[[2]string]*KeywordStats)
+	for category, keywords := range Keywords() {
+		for _, keyword := range keywords {
+			stats[[2]string{category, keyword}] = &KeywordStats{Category: category, Keyword: keyword}
+		}
+	}
+
+	for _, c := range complaints {
+		if len(c.Keywords) == 0 {
+			continue
+		}
+		report.Complaints++
+		verdict, labeled := correct[c.ID]
+		if labeled {
+			report.Labeled++
+		}
+		for _, keyword := range c.Keywords {
+			s, ok := stats[[2]string{c.Category, keyword}]
+			if !ok {
+				// Keyword has since been removed from the category
+				continue
+			}
+			s.Matches++
+			if len(c.Keywords) == 1 {
+				s.SoleMatches++
+			}
+			if labeled {
+				s.Labeled++
+				if !verdict {
+					s.FalsePositives++
+				}
+			}
+		}
+	}
+
+	for _, s := range stats {
+		if s.Labeled > 0 {
+			rate := float64(s.FalsePositives) / float64(s.Labeled)
+			s.FalsePositiveRate = &rate
+		}
+		switch {
+		case s.Matches == 0:
+			s.Retire, s.Reason = true, "no matches"
+		case s.Labeled >= KeywordMinLabels && *s.FalsePositiveRate >= KeywordRetireFalsePositiveRate:
+			s.Retire, s.Reason = true, fmt.Sprintf("%.0f%% of %d labeled matches were miscategorized", *s.FalsePositiveRate*100, s.Labeled)
+		}
+		report.Keywords = append(report.Keywords, *s)
+	}
+
+	sort.Slice(report.Keywords, func(i, j int) bool {
+		a, b := report.Keywords[i], report.Keywords[j]
+		if a.Retire != b.Retire {
+			return a.Retire
+		}
+		if ra, rb := rateOrZero(a.FalsePositiveRate), rateOrZero(b.FalsePositiveRate); ra != rb {
+			return ra > rb
+		}
+		if a.Matches != b.Matches {
+			return a.Matches > b.Matches
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Keyword < b.Keyword
+	})
+	return report
+}
+
+func rateOrZero(rate *float64) float64 {
+	if rate == nil {
+		return 0
+	}
+	return *rate
+}
@@ -0,0 +1,68 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/tasnint/coinsights/internal/jsonstream"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SCRAPE FILE LOADING
+// ============================================
+// The videos and comments of a scrape result are only needed while the analyzer
+// runs over them; everything it keeps is copied out. AnalyzeFile therefore streams
+// them into pooled slices and hands the slices back afterwards, so a reload reuses
+// the previous load's backing arrays instead of growing new ones from scratch.
+
+var (
+	videoPool   jsonstream.SlicePool[models.YouTubeVideo]
+	commentPool jsonstream.SlicePool[models.YouTubeComment]
+)
+
+// loadScrapeResult streams the scrape result at path. Call release once the
+// result's videos and comments are no longer referenced.
+func loadScrapeResult(path string) (result *models.ScrapeResult, release func(), err error) {
+	dec, closeFile, err := jsonstream.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer closeFile()
+
+	result = &models.ScrapeResult{
+		Videos:   videoPool.Get(),
+		Comments: commentPool.Get(),
+	}
+	release = func() {
+		videoPool.Put(result.Videos)
+		commentPool.Put(result.Comments)
+		result.Videos, result.Comments = nil, nil
+	}
+
+	decode := func(v any) func() error {
+		return func() error { return dec.Decode(v) }
+	}
+	err = jsonstream.Object(dec, map[string]func() error{
+		"videos": func() (err error) {
+			result.Videos, err = jsonstream.Array(dec, result.Videos)
+			return err
+		},
+		"comments": func() (err error) {
+			result.Comments, err = jsonstream.Array(dec, result.Comments)
+			return err
+		},
+		"google_results": decode(&result.GoogleResults),
+		"complaints":     decode(&result.Complaints),
+		"query_stats":    decode(&result.QueryStats),
+		"errors":         decode(&result.Errors),
+		"error_counts":   decode(&result.ErrorCounts),
+		"run_id":         decode(&result.RunID),
+		"scraped_at":     decode(&result.ScrapedAt),
+		"query":          decode(&result.Query),
+	})
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return result, release, nil
+}
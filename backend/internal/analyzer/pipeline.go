@@ -0,0 +1,324 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/textutil"
+)
+
+// ============================================
+// ANALYSIS PIPELINE
+// ============================================
+// AnalyzeResult runs a scrape through a pipeline of stages, in the order
+// config.AnalyzerStages names them:
+//   - normalize: videos and comments become Records, one per piece of text
+//   - filter:    Records with no text are dropped
+//   - classify:  each Record becomes an issue per category its keywords match
+//   - sentiment: videos whose comments turn on their framing are flagged
+//   - dedup:     issues extracted twice (a comment scraped under two queries) are merged
+//   - aggregate: category counts, summaries and the top issues
+// Each stage reads what earlier stages left on the Pass and adds its own part, so a
+// new step - spam filtering, translation - is a Stage registered with RegisterStage
+// and named in the order, without touching the stages around it. The translate
+// package registers "translate", which goes after normalize.
+
+// Stage is one step of the analysis pipeline
+type Stage interface {
+	Run(p *Pass)
+}
+
+// StageFunc adapts a function to a Stage
+type StageFunc func(p *Pass)
+
+// Run calls f(p)
+func (f StageFunc) Run(p *Pass) { f(p) }
+
+// Record is one piece of scraped text to analyze: a video's title, description or
+// tags, or a comment
+type Record struct {
+	Text        string
+	Source      string // "video_title", "video_description", "video_tags", "comment"
+	SourceID    string // Video ID, or comment ID for comments
+	SourceURL   string
+	SourceTitle string
+	Likes       int
+	Context     *models.EngagementContext
+	PublishedAt time.Time
+
+	// Set when Text is an English translation of a non-English comment
+	OriginalText string
+	Language     string
+}
+
+// Pass is one scrape moving through the pipeline
+type Pass struct {
+	Scrape        *models.ScrapeResult
+	RunID         string
+	Categories    map[string]*IssueCategory // Keywords to classify by; counts filled by aggregate
+	Classified    map[string]string         // Batch categories by comment text, for comments no keyword matches
+	Records       []Record
+	Issues        []ExtractedIssue
+	Uncategorized []string        // Comment texts that matched no category
+	Result        *AnalysisResult // Totals and effort are set before the first stage runs
+}
+
+// Pipeline runs stages in order
+type Pipeline struct {
+	names  []string
+	stages []Stage
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]func() Stage{
+		"normalize": func() Stage { return StageFunc(normalizeStage) },
+		"filter":    func() Stage { return StageFunc(filterStage) },
+		"classify":  func() Stage { return StageFunc(classifyStage) },
+		"sentiment": func() Stage { return StageFunc(sentimentStage) },
+		"dedup":     func() Stage { return StageFunc(dedupStage) },
+		"aggregate": func() Stage { return StageFunc(aggregateStage) },
+	}
+)
+
+// RegisterStage makes a stage available to pipelines under name, replacing any
+// stage already registered with it. newStage is called once per pipeline built.
+func RegisterStage(name string, newStage func() Stage) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = newStage
+}
+
+// NewPipeline builds a pipeline running the named stages in order
+func NewPipeline(names []string) (*Pipeline, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	p := &Pipeline{names: append([]string(nil), names...)}
+	for _, name := range names {
+		newStage, ok := registry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown analyzer stage %q", name)
+		}
+		p.stages = append(p.stages, newStage())
+	}
+	return p, nil
+}
+
+// defaultPipeline builds the pipeline config.AnalyzerStages names, falling back to
+// the built-in order if it names a stage that isn't registered
+func defaultPipeline() *Pipeline {
+	p, err := NewPipeline(config.AnalyzerStages)
+	if err != nil {
+		fmt.Printf("⚠️  %v - using the built-in analyzer stages\n", err)
+		p, _ = NewPipeline([]string{"normalize", "filter", "classify", "sentiment", "dedup", "aggregate"})
+	}
+	return p
+}
+
+// StagesFromEnv applies the ANALYZER_STAGES override, a comma-separated stage order
+// such as "normalize,translate,filter,classify,sentiment,dedup,aggregate", to base.
+// Every stage it names must be registered.
+func StagesFromEnv(base []string) ([]string, error) {
+	v := os.Getenv("ANALYZER_STAGES")
+	if v == "" {
+		return base, nil
+	}
+	var names []string
+	for _, name := range strings.Split(v, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return base, fmt.Errorf("invalid ANALYZER_STAGES %q: no stages named", v)
+	}
+	if _, err := NewPipeline(names); err != nil {
+		return base, fmt.Errorf("invalid ANALYZER_STAGES %q: %w", v, err)
+	}
+	return names, nil
+}
+
+// Stages returns the names of the stages in the order they run
+func (p *Pipeline) Stages() []string {
+	return p.names
+}
+
+// Run runs every stage on pass
+func (p *Pipeline) Run(pass *Pass) {
+	for _, stage := range p.stages {
+		stage.Run(pass)
+	}
+}
+
+// ============================================
+// BUILT-IN STAGES
+// ============================================
+
+// normalizeStage turns each video's title, start of description and tags, and each
+// comment, into a Record. Comments carry their video's engagement.
+func normalizeStage(p *Pass) {
+	videos := make(map[string]models.YouTubeVideo, len(p.Scrape.Videos))
+	for _, video := range p.Scrape.Videos {
+		context := videoContext(video)
+		record := Record{
+			SourceID:    video.VideoID,
+			SourceURL:   video.URL,
+			SourceTitle: video.Title,
+			Context:     context,
+			PublishedAt: video.PublishedAt,
+		}
+		for _, field := range []struct{ source, text string }{
+			{"video_title", video.Title},
+			{"video_description", textutil.Prefix(video.Description, config.Truncation.DescriptionScan)},
+			{"video_tags", strings.Join(video.Tags, " ")},
+		} {
+			record.Source, record.Text = field.source, field.text
+			p.Records = append(p.Records, record)
+		}
+		if _, ok := videos[video.VideoID]; !ok {
+			videos[video.VideoID] = video
+		}
+	}
+
+	for _, comment := range p.Scrape.Comments {
+		context := &models.EngagementContext{}
+		video, ok := videos[comment.VideoID]
+		if ok {
+			context = videoContext(video)
+		}
+		context.CommentPosition = comment.Position
+		context.ReplyCount = comment.ReplyCount
+
+		p.Records = append(p.Records, Record{
+			Text:        comment.Text,
+			Source:      "comment",
+			SourceID:    comment.CommentID,
+			SourceURL:   video.URL,
+			SourceTitle: video.Title,
+			Likes:       comment.LikeCount,
+			Context:     context,
+			PublishedAt: comment.PublishedAt,
+
+			OriginalText: comment.OriginalText,
+			Language:     comment.Language,
+		})
+	}
+}
+
+// filterStage drops Records with no text - videos without tags, empty comments
+func filterStage(p *Pass) {
+	kept := p.Records[:0]
+	for _, record := range p.Records {
+		if strings.TrimSpace(record.Text) != "" {
+			kept = append(kept, record)
+		}
+	}
+	p.Records = kept
+}
+
+// classifyStage extracts an issue for every category a Record's keywords match.
+// Comments matching none take their batch category if they have one, and are
+// otherwise kept aside for keyword suggestions and the next batch.
+func classifyStage(p *Pass) {
+	for _, record := range p.Records {
+		matches := findIssuesInText(p.Categories, record.Text)
+		if len(matches) == 0 && record.Source == "comment" {
+			if category := p.Classified[record.Text]; p.Categories[category] != nil {
+				matches = append(matches, categoryMatch{category: category})
+			} else {
+				p.Uncategorized = append(p.Uncategorized, record.Text)
+			}
+		}
+		for _, match := range matches {
+			p.Issues = append(p.Issues, ExtractedIssue{
+				ID:          models.ComplaintID("youtube:"+record.Source, record.SourceID, match.category),
+				Category:    match.category,
+				Keywords:    match.keywords,
+				Text:        record.Text,
+				Source:      record.Source,
+				SourceID:    record.SourceID,
+				RunID:       p.RunID,
+				SourceURL:   record.SourceURL,
+				SourceTitle: record.SourceTitle,
+				Likes:       record.Likes,
+				Context:     record.Context,
+				PublishedAt: record.PublishedAt,
+				ExtractedAt: time.Now(),
+
+				OriginalText: record.OriginalText,
+				Language:     record.Language,
+			})
+		}
+	}
+}
+
+// sentimentStage compares each video's framing with its comment section
+func sentimentStage(p *Pass) {
+	p.Result.SentimentDivergence = SentimentDivergence(p.Scrape)
+}
+
+// dedupStage keeps the first issue extracted under each ID
+func dedupStage(p *Pass) {
+	seen := make(map[string]bool, len(p.Issues))
+	kept := p.Issues[:0]
+	for _, issue := range p.Issues {
+		if !seen[issue.ID] {
+			seen[issue.ID] = true
+			kept = append(kept, issue)
+		}
+	}
+	p.Issues = kept
+}
+
+// aggregateStage counts issues per category with up to five examples each, ranks
+// the categories and picks the most-liked issues
+func aggregateStage(p *Pass) {
+	for _, issue := range p.Issues {
+		if cat, exists := p.Categories[issue.Category]; exists {
+			cat.Count++
+			if len(cat.Examples) < 5 {
+				cat.Examples = append(cat.Examples, textutil.Truncate(issue.Text, config.Truncation.CategoryExample))
+			}
+		}
+	}
+	p.Result.TotalIssues = len(p.Issues)
+
+	// Build category summaries sorted by count
+	summaries := []CategorySummary{}
+	for name, cat := range p.Categories {
+		if cat.Count > 0 {
+			percentage := 0.0
+			if len(p.Issues) > 0 {
+				percentage = float64(cat.Count) / float64(len(p.Issues)) * 100
+			}
+			summaries = append(summaries, CategorySummary{
+				Category:    name,
+				Count:       cat.Count,
+				Percentage:  percentage,
+				TopExamples: cat.Examples,
+			})
+		}
+	}
+
+	// Sort by count descending, then by name so ranks are stable between runs
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Category < summaries[j].Category
+	})
+	p.Result.IssuesByCategory = summaries
+
+	// Top 20 issues by likes
+	sort.Slice(p.Issues, func(i, j int) bool {
+		return p.Issues[i].Likes > p.Issues[j].Likes
+	})
+	p.Result.TopIssues = p.Issues[:min(20, len(p.Issues))]
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ENGAGEMENT REACH
+// ============================================
+// Complaint counts say how many people spoke up; reach says how many saw them. A
+// complaint under a video with two million views matters more than ten on a video
+// nobody watched. Reach only counts audiences a source reports: YouTube video views
+// and Reddit thread scores. Other sources add no reach.
+
+// ReachByCategory sums the audiences of the distinct videos and threads each
+// category's complaints appear in
+func ReachByCategory(complaints []models.Complaint) map[string]models.IssueReach {
+	type audience struct {
+		views, upvotes int64
+	}
+	threads := make(map[string]map[string]audience) // category -> thread -> audience
+	for _, c := range complaints {
+		ctx := c.Context
+		if ctx == nil {
+			continue
+		}
+		var key string
+		var a audience
+		switch {
+		case ctx.VideoViewCount > 0 && c.URL != "":
+			key, a.views = c.URL, ctx.VideoViewCount // YouTube complaints link to their video
+		case ctx.ThreadURL != "":
+			key, a.upvotes = ctx.ThreadURL, int64(max(ctx.ThreadScore, 0))
+		default:
+			continue
+		}
+		if threads[c.Category] == nil {
+			threads[c.Category] = make(map[string]audience)
+		}
+		// Keep the largest figure seen for a thread; counts only grow between scrapes
+		prev := threads[c.Category][key]
+		threads[c.Category][key] = audience{max(prev.views, a.views), max(prev.upvotes, a.upvotes)}
+	}
+
+	reach := make(map[string]models.IssueReach, len(threads))
+	for category, byThread := range threads {
+		var r models.IssueReach
+		for _, a := range byThread {
+			r.Views += a.views
+			r.Upvotes += a.upvotes
+		}
+		r.Threads = len(byThread)
+		r.Estimated = r.Views + r.Upvotes
+		reach[category] = r
+	}
+	return reach
+}
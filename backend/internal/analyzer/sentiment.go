@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SENTIMENT DIVERGENCE
+// ============================================
+// A video that frames the exchange positively (often sponsored) while its comment
+// section is furious is a strong complaint signal: viewers are pushing back on
+// the pitch with their own experiences.
+
+const (
+	// DivergenceThreshold is the framing-vs-comments gap that flags a video
+	DivergenceThreshold = 0.6
+	// MinDivergenceComments is how many opinionated comments a video needs before it can be flagged
+	MinDivergenceComments = 5
+)
+
+// VideoDivergence compares a video's framing with its comment section
+type VideoDivergence struct {
+	VideoID          string  `json:"video_id"`
+	Title            string  `json:"title"`
+	URL              string  `json:"url"`
+	VideoSentiment   float64 `json:"video_sentiment"`   // Title and description, -1 (negative) to 1 (positive)
+	CommentSentiment float64 `json:"comment_sentiment"` // Mean over opinionated comments, -1 to 1
+	Comments         int     `json:"comments"`          // Comments that expressed any sentiment
+	Gap              float64 `json:"gap"`               // VideoSentiment - CommentSentiment; positive means comments are angrier
+	Divergent        bool    `json:"divergent"`         // Positive framing, negative comments, gap of at least DivergenceThreshold
+}
+
+var (
+	positiveWords = toSet("good", "great", "love", "best", "easy", "amazing", "awesome", "excellent",
+		"recommend", "safe", "fast", "helpful", "legit", "trust", "trusted", "worth", "perfect",
+		"happy", "reliable", "smooth", "profit", "profitable", "simple")
+	negativeWords = toSet("bad", "terrible", "worst", "awful", "horrible", "hate", "scam", "avoid",
+		"nightmare", "frustrating", "disappointed", "angry", "useless", "fraud", "stolen", "hacked",
+		"locked", "frozen", "stuck", "broken", "ignored", "ripoff", "lost", "robbed", "garbage",
+		"trash", "joke", "liar", "liars", "lies", "shady")
+	negators = toSet("not", "no", "never", "don't", "dont", "isn't", "isnt", "wasn't", "wasnt", "can't", "cant")
+)
+
+// SentimentScore rates text from -1 (negative) to 1 (positive) by counting
+// sentiment words, flipping a word right after a negator ("not safe").
+// ok is false if the text has no sentiment words at all.
+func SentimentScore(text string) (score float64, ok bool) {
+	var pos, neg int
+	negated := false
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if negators[word] {
+			negated = true
+			continue
+		}
+		switch {
+		case positiveWords[word] && !negated, negativeWords[word] && negated:
+			pos++
+		case negativeWords[word] && !negated, positiveWords[word] && negated:
+			neg++
+		}
+		negated = false
+	}
+	if pos+neg == 0 {
+		return 0, false
+	}
+	return float64(pos-neg) / float64(pos+neg), true
+}
+
+// SentimentDivergence scores every video's framing against its comments, most divergent first.
+// Videos whose framing or comments express no sentiment are left out.
+func SentimentDivergence(result *models.ScrapeResult) []VideoDivergence {
+	type commentTotals struct {
+		sum   float64
+		count int
+	}
+	byVideo := make(map[string]*commentTotals)
+	for _, comment := range result.Comments {
+		score, ok := SentimentScore(comment.Text)
+		if !ok {
+			continue
+		}
+		t := byVideo[comment.VideoID]
+		if t == nil {
+			t = &commentTotals{}
+			byVideo[comment.VideoID] = t
+		}
+		t.sum += score
+		t.count++
+	}
+
+	divergence := []VideoDivergence{}
+	for _, video := range result.Videos {
+		framing, ok := SentimentScore(video.Title + "\n" + video.Description)
+		t := byVideo[video.VideoID]
+		if !ok || t == nil {
+			continue
+		}
+
+		d := VideoDivergence{
+			VideoID:          video.VideoID,
+			Title:            video.Title,
+			URL:              video.URL,
+			VideoSentiment:   framing,
+			CommentSentiment: t.sum / float64(t.count),
+			Comments:         t.count,
+		}
+		d.Gap = d.VideoSentiment - d.CommentSentiment
+		d.Divergent = d.VideoSentiment > 0 && d.CommentSentiment < 0 &&
+			d.Gap >= DivergenceThreshold && d.Comments >= MinDivergenceComments
+		divergence = append(divergence, d)
+	}
+
+	sort.SliceStable(divergence, func(i, j int) bool { return divergence[i].Gap > divergence[j].Gap })
+	return divergence
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SOURCE BREAKDOWN
+// ============================================
+// Headline numbers mean little without knowing what they're built from: a thousand
+// complaints from last year's YouTube comments say something different from a
+// thousand Reddit posts from this week. The breakdown groups complaints by the
+// source before the ":" in Complaint.Source (so "youtube:comment" and
+// "youtube:video" are both "youtube") and reports each group's size, when it was
+// last scraped, and the span of time its complaints were posted over.
+
+// SourceStats is one data source's share of the complaints
+type SourceStats struct {
+	Source       string     `json:"source"` // "youtube", "gemini", "google", "reddit", "partner", ...
+	Complaints   int        `json:"complaints"`
+	Categories   int        `json:"categories"`              // Distinct categories its complaints fall in
+	LastUpdated  *time.Time `json:"last_updated,omitempty"`  // Latest scrape of any of its complaints
+	CoverageFrom *time.Time `json:"coverage_from,omitempty"` // Earliest complaint, by when it was posted
+	CoverageTo   *time.Time `json:"coverage_to,omitempty"`   // Latest complaint, by when it was posted
+}
+
+// sourceAliases names source groups whose Complaint.Source prefix reads poorly on a dashboard
+var sourceAliases = map[string]string{
+	"gemini_search": "gemini",
+}
+
+// SourceGroup returns the data source a complaint's Source belongs to
+func SourceGroup(source string) string {
+	group, _, _ := strings.Cut(source, ":")
+	if alias, ok := sourceAliases[group]; ok {
+		return alias
+	}
+	return group
+}
+
+// BySource breaks complaints down by data source, largest first
+func BySource(complaints []models.Complaint) []SourceStats {
+	type accumulator struct {
+		stats      SourceStats
+		categories map[string]bool
+	}
+	groups := make(map[string]*accumulator)
+	for _, c := range complaints {
+		name := SourceGroup(c.Source)
+		acc := groups[name]
+		if acc == nil {
+			acc = &accumulator{stats: SourceStats{Source: name}, categories: make(map[string]bool)}
+			groups[name] = acc
+		}
+		acc.stats.Complaints++
+		acc.categories[c.Category] = true
+
+		updated := c.ScrapedAt
+		if c.LastSeen.After(updated) {
+			updated = c.LastSeen
+		}
+		acc.stats.LastUpdated = later(acc.stats.LastUpdated, updated)
+
+		posted := c.PublishedAt
+		switch {
+		case !posted.IsZero():
+		case !c.FirstSeen.IsZero():
+			posted = c.FirstSeen
+		default:
+			posted = c.ScrapedAt
+		}
+		acc.stats.CoverageFrom = earlier(acc.stats.CoverageFrom, posted)
+		acc.stats.CoverageTo = later(acc.stats.CoverageTo, posted)
+	}
+
+	breakdown := make([]SourceStats, 0, len(groups))
+	for _, acc := range groups {
+		acc.stats.Categories = len(acc.categories)
+		breakdown = append(breakdown, acc.stats)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Complaints != breakdown[j].Complaints {
+			return breakdown[i].Complaints > breakdown[j].Complaints
+		}
+		return breakdown[i].Source < breakdown[j].Source
+	})
+	return breakdown
+}
+
+// earlier and later keep the earlier or later of a running bound and t, ignoring zero times
+func earlier(bound *time.Time, t time.Time) *time.Time {
+	if t.IsZero() || (bound != nil && !t.Before(*bound)) {
+		return bound
+	}
+	return &t
+}
+
+func later(bound *time.Time, t time.Time) *time.Time {
+	if t.IsZero() || (bound != nil && !t.After(*bound)) {
+		return bound
+	}
+	return &t
+}
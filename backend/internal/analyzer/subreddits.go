@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SUBREDDIT TRENDS
+// ============================================
+// Complaint dynamics differ sharply between venues: r/CoinBase is mostly account
+// and support trouble, r/CryptoCurrency reacts to fees and outages. Subreddits
+// come from complaint URLs: the Reddit scraper's permalinks, and Gemini sources and
+// partner ingestion that link back to threads.
+
+// DefaultSubreddits are compared when the caller doesn't pick any
+var DefaultSubreddits = []string{"CoinBase", "CryptoCurrency"}
+
+// SubredditPoint is one bucket of a subreddit's complaint series
+type SubredditPoint struct {
+	Date       string  `json:"date"` // Bucket start, YYYY-MM-DD (UTC)
+	Complaints int     `json:"complaints"`
+	Sentiment  float64 `json:"sentiment"` // Mean over complaints that expressed any sentiment, -1 to 1
+	Scored     int     `json:"scored"`    // Complaints that went into Sentiment
+}
+
+// SubredditTrend is a subreddit's complaint volume and sentiment over time
+type SubredditTrend struct {
+	Subreddit  string           `json:"subreddit"`
+	Complaints int              `json:"complaints"`
+	Sentiment  float64          `json:"sentiment"` // Over the whole range
+	Categories map[string]int   `json:"categories"`
+	Timeline   []SubredditPoint `json:"timeline"`
+}
+
+// Subreddit returns the subreddit a reddit.com URL points into, lower-cased, or "" for other URLs
+func Subreddit(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host != "reddit.com" && !strings.HasSuffix(host, ".reddit.com") {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || strings.ToLower(parts[0]) != "r" || parts[1] == "" {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
+// SubredditTrends builds a series per subreddit (matched case-insensitively) in buckets of
+// interval days. Every series covers the same date range so they can be compared directly.
+func SubredditTrends(complaints []models.Complaint, subreddits []string, interval int) []SubredditTrend {
+	type bucket struct {
+		count, scored int
+		sum           float64
+	}
+
+	bySubreddit := make(map[string]int, len(subreddits)) // Lower-cased name -> index into trends
+	trends := make([]SubredditTrend, 0, len(subreddits))
+	for _, name := range subreddits {
+		key := strings.ToLower(name)
+		if _, ok := bySubreddit[key]; ok {
+			continue
+		}
+		bySubreddit[key] = len(trends)
+		trends = append(trends, SubredditTrend{Subreddit: name, Categories: make(map[string]int), Timeline: []SubredditPoint{}})
+	}
+
+	// Shared range across every compared subreddit
+	var matched []models.Complaint
+	var start, end time.Time
+	for _, c := range complaints {
+		if _, ok := bySubreddit[Subreddit(c.URL)]; !ok {
+			continue
+		}
+		matched = append(matched, c)
+		day := complaintTime(c).UTC().Truncate(24 * time.Hour)
+		if start.IsZero() || day.Before(start) {
+			start = day
+		}
+		if day.After(end) {
+			end = day
+		}
+	}
+	if len(matched) == 0 {
+		return trends
+	}
+
+	buckets := make([]map[string]*bucket, len(trends))
+	sums := make([]bucket, len(trends))
+	for i := range buckets {
+		buckets[i] = make(map[string]*bucket)
+	}
+	for _, c := range matched {
+		i := bySubreddit[Subreddit(c.URL)]
+		day := complaintTime(c).UTC().Truncate(24 * time.Hour)
+		offset := int(day.Sub(start).Hours() / 24)
+		date := start.AddDate(0, 0, offset-offset%interval).Format("2006-01-02")
+
+		b := buckets[i][date]
+		if b == nil {
+			b = &bucket{}
+			buckets[i][date] = b
+		}
+		b.count++
+		trends[i].Complaints++
+		trends[i].Categories[c.Category]++
+		if score, ok := SentimentScore(c.Title + "\n" + c.Description); ok {
+			b.sum += score
+			b.scored++
+			sums[i].sum += score
+			sums[i].scored++
+		}
+	}
+
+	for i := range trends {
+		if sums[i].scored > 0 {
+			trends[i].Sentiment = sums[i].sum / float64(sums[i].scored)
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, interval) {
+			point := SubredditPoint{Date: day.Format("2006-01-02")}
+			if b := buckets[i][point.Date]; b != nil {
+				point.Complaints = b.count
+				point.Scored = b.scored
+				if b.scored > 0 {
+					point.Sentiment = b.sum / float64(b.scored)
+				}
+			}
+			trends[i].Timeline = append(trends[i].Timeline, point)
+		}
+	}
+	return trends
+}
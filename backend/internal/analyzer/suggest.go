@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeywordSuggestionsFile is the review file written by cmd/keywords inside the data directory
+const KeywordSuggestionsFile = "keyword_suggestions.json"
+
+// KeywordSuggestionReport is the reviewable output of a suggestion run
+type KeywordSuggestionReport struct {
+	GeneratedAt        time.Time           `json:"generated_at"`
+	UncategorizedCount int                 `json:"uncategorized_count"`
+	Suggestions        []KeywordSuggestion `json:"suggestions"`
+}
+
+// KeywordSuggestion proposes adding a phrase to a category's keyword list
+type KeywordSuggestion struct {
+	Phrase         string  `json:"phrase"`
+	Category       string  `json:"category"`
+	Occurrences    int     `json:"occurrences"`     // Uncategorized comments containing the phrase
+	ExpectedImpact int     `json:"expected_impact"` // Comments that would newly be categorized
+	Confidence     float64 `json:"confidence"`      // Share of categorized mentions that fall in Category
+}
+
+// stopwords are skipped when building n-grams
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true, "is": true,
+	"are": true, "was": true, "were": true, "be": true, "been": true, "to": true, "of": true,
+	"in": true, "on": true, "at": true, "for": true, "with": true, "it": true, "its": true,
+	"this": true, "that": true, "i": true, "you": true, "he": true, "she": true, "we": true,
+	"they": true, "my": true, "your": true, "me": true, "so": true, "just": true, "have": true,
+	"has": true, "had": true, "do": true, "did": true, "not": true, "no": true, "if": true,
+	"from": true, "as": true, "about": true, "what": true, "can": true, "will": true, "all": true,
+	"coinbase": true, "video": true, "thanks": true, "thank": true, "like": true,
+	"great": true, "good": true, "very": true, "how": true, "new": true, "other": true,
+	"really": true, "get": true, "people": true, "know": true, "one": true, "would": true,
+	"there": true, "more": true, "much": true, "out": true, "up": true, "now": true,
+	"when": true, "why": true, "who": true, "which": true, "them": true, "their": true,
+	"only": true, "also": true, "use": true, "using": true, "crypto": true, "helpful": true,
+	"don't": true, "i'm": true, "it's": true, "bro": true, "man": true, "sir": true,
+}
+
+const (
+	// minCategoryEvidence is how many categorized comments must share a phrase
+	// before it can be attributed to a category
+	minCategoryEvidence = 3
+	// minSuggestionConfidence drops phrases spread evenly across categories
+	minSuggestionConfidence = 0.5
+)
+
+var wordPattern = regexp.MustCompile(`[a-z0-9']+`)
+
+// ngrams returns the distinct 1-3 word phrases in text, skipping phrases
+// that start or end with a stopword
+func ngrams(text string) map[string]bool {
+	words := wordPattern.FindAllString(strings.ToLower(text), -1)
+	phrases := make(map[string]bool)
+	for n := 1; n <= 3; n++ {
+		for i := 0; i+n <= len(words); i++ {
+			first, last := words[i], words[i+n-1]
+			if stopwords[first] || stopwords[last] || len(first) < 3 {
+				continue
+			}
+			phrases[strings.Join(words[i:i+n], " ")] = true
+		}
+	}
+	return phrases
+}
+
+// SuggestKeywords surfaces frequent phrases in uncategorized comments and assigns
+// each to the category whose already-categorized comments use it most.
+// Phrases seen in fewer than minOccurrences uncategorized comments are ignored.
+func (a *YouTubeAnalyzer) SuggestKeywords(minOccurrences int) []KeywordSuggestion {
+	// Phrase frequency in uncategorized comments
+	uncategorizedCounts := make(map[string]int)
+	for _, text := range a.uncategorized {
+		for phrase := range ngrams(text) {
+			uncategorizedCounts[phrase]++
+		}
+	}
+
+	// Phrase -> category association from categorized comments
+	seenText := make(map[string]bool)
+	categoryCounts := make(map[string]map[string]int)
+	for _, issue := range a.issues {
+		if issue.Source != "comment" {
+			continue
+		}
+		key := issue.Category + "\x00" + issue.Text
+		if seenText[key] {
+			continue
+		}
+		seenText[key] = true
+		for phrase := range ngrams(issue.Text) {
+			if uncategorizedCounts[phrase] < minOccurrences {
+				continue
+			}
+			if categoryCounts[phrase] == nil {
+				categoryCounts[phrase] = make(map[string]int)
+			}
+			categoryCounts[phrase][issue.Category]++
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, cat := range a.categories {
+		for _, kw := range cat.Keywords {
+			existing[strings.ToLower(kw)] = true
+		}
+	}
+
+	suggestions := []KeywordSuggestion{}
+	for phrase, count := range uncategorizedCounts {
+		if count < minOccurrences || existing[phrase] {
+			continue
+		}
+
+		bestCategory, bestCount, total := "", 0, 0
+		for category, n := range categoryCounts[phrase] {
+			total += n
+			if n > bestCount || (n == bestCount && category < bestCategory) {
+				bestCategory, bestCount = category, n
+			}
+		}
+		if bestCount < minCategoryEvidence {
+			continue // Not enough evidence which category the phrase belongs to
+		}
+		confidence := float64(bestCount) / float64(total)
+		if confidence < minSuggestionConfidence {
+			continue
+		}
+
+		suggestions = append(suggestions, KeywordSuggestion{
+			Phrase:         phrase,
+			Category:       bestCategory,
+			Occurrences:    count,
+			ExpectedImpact: count,
+			Confidence:     confidence,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		si := float64(suggestions[i].ExpectedImpact) * suggestions[i].Confidence
+		sj := float64(suggestions[j].ExpectedImpact) * suggestions[j].Confidence
+		if si != sj {
+			return si > sj
+		}
+		return suggestions[i].Phrase < suggestions[j].Phrase
+	})
+	return suggestions
+}
+
+// UncategorizedCount returns how many comments matched no category
+func (a *YouTubeAnalyzer) UncategorizedCount() int {
+	return len(a.uncategorized)
+}
+
+// Uncategorized returns the texts of comments that matched no category
+func (a *YouTubeAnalyzer) Uncategorized() []string {
+	return a.uncategorized
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// EMERGENT TOPICS
+// ============================================
+// Topics cluster complaints by a shared multi-word phrase (e.g. "one billing surprise"),
+// so new problems show up before anyone adds them to the fixed category taxonomy.
+
+// MinTopicSize is how many complaints a phrase must cluster before it becomes a topic
+const MinTopicSize = 5
+
+// maxTopicExamples caps the complaint IDs listed on each topic
+const maxTopicExamples = 5
+
+// Topic is a cluster of complaints sharing a distinctive phrase
+type Topic struct {
+	ID         string         `json:"id"`    // URL-safe slug of Label
+	Label      string         `json:"label"` // The shared phrase
+	Size       int            `json:"size"`
+	Categories map[string]int `json:"categories"` // Taxonomy categories the topic cuts across
+	FirstSeen  time.Time      `json:"first_seen"`
+	LastSeen   time.Time      `json:"last_seen"`
+	Examples   []string       `json:"examples"` // Complaint IDs
+
+	complaints []models.Complaint
+}
+
+// TopicPoint is one day's complaint volume for a topic
+type TopicPoint struct {
+	Date  string `json:"date"` // YYYY-MM-DD (UTC)
+	Count int    `json:"count"`
+}
+
+// ClusterTopics groups complaints into topics, largest first. Each complaint joins at most
+// one topic: phrases are claimed greedily, most widespread (then longest) first, and a
+// phrase only becomes a topic if at least minSize unclaimed complaints use it.
+func ClusterTopics(complaints []models.Complaint, minSize int) []Topic {
+	byPhrase := make(map[string][]int)
+	for i, c := range complaints {
+		for phrase := range ngrams(c.Description) {
+			if strings.Contains(phrase, " ") { // Single words are what the taxonomy already covers
+				byPhrase[phrase] = append(byPhrase[phrase], i)
+			}
+		}
+	}
+
+	phrases := make([]string, 0, len(byPhrase))
+	for phrase, members := range byPhrase {
+		if len(members) >= minSize {
+			phrases = append(phrases, phrase)
+		}
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		pi, pj := phrases[i], phrases[j]
+		if len(byPhrase[pi]) != len(byPhrase[pj]) {
+			return len(byPhrase[pi]) > len(byPhrase[pj])
+		}
+		if wi, wj := strings.Count(pi, " "), strings.Count(pj, " "); wi != wj {
+			return wi > wj
+		}
+		return pi < pj
+	})
+
+	claimed := make([]bool, len(complaints))
+	topics := []Topic{}
+	for _, phrase := range phrases {
+		var members []int
+		for _, i := range byPhrase[phrase] {
+			if !claimed[i] {
+				members = append(members, i)
+			}
+		}
+		if len(members) < minSize {
+			continue
+		}
+
+		topic := Topic{
+			ID:         strings.ReplaceAll(strings.ReplaceAll(phrase, "'", ""), " ", "-"),
+			Label:      phrase,
+			Categories: make(map[string]int),
+			Examples:   []string{},
+		}
+		for _, i := range members {
+			claimed[i] = true
+			c := complaints[i]
+			topic.complaints = append(topic.complaints, c)
+			topic.Size++
+			if c.Category != "" {
+				topic.Categories[c.Category]++
+			}
+			if len(topic.Examples) < maxTopicExamples {
+				topic.Examples = append(topic.Examples, c.ID)
+			}
+			seen := complaintTime(c)
+			if topic.FirstSeen.IsZero() || seen.Before(topic.FirstSeen) {
+				topic.FirstSeen = seen
+			}
+			if seen.After(topic.LastSeen) {
+				topic.LastSeen = seen
+			}
+		}
+		topics = append(topics, topic)
+	}
+
+	sort.SliceStable(topics, func(i, j int) bool { return topics[i].Size > topics[j].Size })
+	return topics
+}
+
+// Timeline returns the topic's daily complaint volume from its first to last day, with zero days filled in
+func (t Topic) Timeline() []TopicPoint {
+	counts := make(map[string]int)
+	for _, c := range t.complaints {
+		counts[complaintTime(c).UTC().Format("2006-01-02")]++
+	}
+
+	points := []TopicPoint{}
+	if t.Size == 0 {
+		return points
+	}
+	end := t.LastSeen.UTC().Truncate(24 * time.Hour)
+	for day := t.FirstSeen.UTC().Truncate(24 * time.Hour); !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		points = append(points, TopicPoint{Date: date, Count: counts[date]})
+	}
+	return points
+}
+
+// complaintTime is when a complaint was posted, or found if the source has no post date
+func complaintTime(c models.Complaint) time.Time {
+	if !c.PublishedAt.IsZero() {
+		return c.PublishedAt
+	}
+	return c.ScrapedAt
+}
@@ -3,13 +3,16 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/integrity"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/textutil"
 )
 
 // IssueCategory represents a category of complaints
@@ -23,14 +26,23 @@ type IssueCategory struct {
 
 // ExtractedIssue represents a single extracted issue
 type ExtractedIssue struct {
-	ID          string    `json:"id"`
-	Category    string    `json:"category"`
-	Text        string    `json:"text"`
-	Source      string    `json:"source"`      // "video_title", "video_description", "video_tags", "comment"
-	SourceURL   string    `json:"source_url"`
-	SourceTitle string    `json:"source_title"`
-	Likes       int       `json:"likes"`       // For comments
-	ExtractedAt time.Time `json:"extracted_at"`
+	ID          string                    `json:"id"`
+	Category    string                    `json:"category"`
+	Text        string                    `json:"text"`
+	Source      string                    `json:"source"`    // "video_title", "video_description", "video_tags", "comment"
+	SourceID    string                    `json:"source_id"` // Video ID, or comment ID for comments
+	RunID       string                    `json:"run_id,omitempty"`
+	SourceURL   string                    `json:"source_url"`
+	SourceTitle string                    `json:"source_title"`
+	Likes       int                       `json:"likes"`             // For comments
+	Context     *models.EngagementContext `json:"context,omitempty"` // Video engagement and comment position
+	PublishedAt time.Time                 `json:"published_at"`      // When the video or comment was posted
+	ExtractedAt time.Time                 `json:"extracted_at"`
+	Keywords    []string                  `json:"keywords"` // Category keywords found in Text
+
+	// Set when Text is an English translation of a non-English comment
+	OriginalText string `json:"original_text,omitempty"`
+	Language     string `json:"language,omitempty"`
 }
 
 // AnalysisResult holds the complete analysis
@@ -42,6 +54,19 @@ type AnalysisResult struct {
 	TopIssues        []ExtractedIssue          `json:"top_issues"`
 	IssuesByCategory []CategorySummary         `json:"issues_by_category"`
 	AnalyzedAt       time.Time                 `json:"analyzed_at"`
+
+	// Per-video framing vs comment sentiment, most divergent first
+	SentimentDivergence []VideoDivergence `json:"sentiment_divergence"`
+
+	// How much the scrape covered, for normalizing complaint counts
+	Effort models.ScrapeEffort `json:"effort"`
+
+	// Scrape run analyzed, and the run the category deltas compare against
+	RunID         string `json:"run_id,omitempty"`
+	PreviousRunID string `json:"previous_run_id,omitempty"`
+
+	// Categories with complaints in the previous run but none in this one
+	DroppedCategories []string `json:"dropped_categories,omitempty"`
 }
 
 // CategorySummary provides a summary for each category
@@ -50,317 +75,222 @@ type CategorySummary struct {
 	Count      int      `json:"count"`
 	Percentage float64  `json:"percentage"`
 	TopExamples []string `json:"top_examples"`
+
+	// Movement since the previous run; nil when there is no previous run
+	Delta *CategoryDelta `json:"delta,omitempty"`
 }
 
 // YouTubeAnalyzer analyzes YouTube scrape results
 type YouTubeAnalyzer struct {
-	categories map[string]*IssueCategory
-	issues     []ExtractedIssue
+	categories    map[string]*IssueCategory
+	issues        []ExtractedIssue
+	uncategorized []string          // Comment texts that matched no category
+	classified    map[string]string // Batch categories by comment text, see SetClassifications
+	pipeline      *Pipeline
 }
 
-// NewYouTubeAnalyzer creates a new analyzer with predefined categories
+// NewYouTubeAnalyzer creates a new analyzer with predefined categories, running the
+// stages config.AnalyzerStages names
 func NewYouTubeAnalyzer() *YouTubeAnalyzer {
 	return &YouTubeAnalyzer{
 		categories: initCategories(),
 		issues:     []ExtractedIssue{},
+		pipeline:   defaultPipeline(),
+	}
+}
+
+// SetStages replaces the analyzer's pipeline with the named stages, in order
+func (a *YouTubeAnalyzer) SetStages(names []string) error {
+	pipeline, err := NewPipeline(names)
+	if err != nil {
+		return err
 	}
+	a.pipeline = pipeline
+	return nil
+}
+
+// DefaultKeywords maps taxonomy category IDs to the keywords that signal them
+var DefaultKeywords = map[string][]string{
+	models.CategoryCustomerSupport: {
+		"support", "customer service", "no response", "no reply", "agent",
+		"ticket", "help", "contact", "chat", "email", "phone", "waiting",
+		"ignored", "unhelpful", "terrible support", "worst support",
+	},
+	models.CategoryAccountLocked: {
+		"locked", "frozen", "restricted", "suspended", "blocked", "disabled",
+		"can't access", "cannot access", "locked out", "freeze", "hold",
+		"account closed", "account terminated", "verification hold",
+	},
+	models.CategoryFees: {
+		"fees", "expensive", "high fee", "hidden fee", "spread", "commission",
+		"cost", "charges", "overcharge", "rip off", "ripoff", "too much",
+		"fee structure", "trading fee", "withdrawal fee",
+	},
+	models.CategoryWithdrawal: {
+		"withdraw", "withdrawal", "can't withdraw", "withdrawal pending",
+		"cash out", "transfer out", "send", "move funds", "stuck funds",
+		"withdrawal failed", "withdrawal delayed",
+	},
+	models.CategorySecurity: {
+		"hack", "hacked", "stolen", "scam", "phishing", "unauthorized",
+		"security", "breach", "compromised", "fraud", "theft", "lost crypto",
+		"2fa", "two factor", "sim swap",
+	},
+	models.CategoryVerification: {
+		"verification", "verify", "kyc", "identity", "id verification",
+		"document", "upload", "rejected", "pending verification",
+		"verification failed", "verify identity",
+	},
+	models.CategoryAppBugs: {
+		"bug", "crash", "not working", "glitch", "error", "broken",
+		"app issue", "loading", "slow", "lag", "freeze", "update",
+		"won't load", "won't open", "technical",
+	},
+	models.CategoryDeposits: {
+		"deposit", "deposit pending", "deposit missing", "deposit failed",
+		"bank transfer", "wire transfer", "ach", "funds not showing",
+		"money missing", "payment",
+	},
+	models.CategoryTrading: {
+		"trade", "trading", "order", "limit order", "market order",
+		"execution", "slippage", "price", "spread", "liquidity",
+		"can't buy", "can't sell", "order failed",
+	},
+	models.CategoryGeneralNegative: {
+		"terrible", "worst", "awful", "horrible", "bad", "hate",
+		"never use", "avoid", "stay away", "don't use", "nightmare",
+		"frustrating", "disappointed", "angry", "scam",
+	},
+}
+
+// editedKeywords are the category keywords in use when they have been edited (nil = DefaultKeywords)
+var editedKeywords atomic.Pointer[map[string][]string]
+
+// Keywords returns the category keywords in use: DefaultKeywords unless replaced by
+// SetKeywords. Callers must not modify the returned map.
+func Keywords() map[string][]string {
+	if k := editedKeywords.Load(); k != nil {
+		return *k
+	}
+	return DefaultKeywords
+}
+
+// SetKeywords replaces the category keywords. Analyzers created afterwards use them.
+func SetKeywords(byCategory map[string][]string) {
+	editedKeywords.Store(&byCategory)
 }
 
-// initCategories sets up the complaint categories with keywords
+// initCategories sets up the complaint categories from the shared taxonomy.
+// Categories without keywords (e.g. "other") are never matched by the analyzer.
 func initCategories() map[string]*IssueCategory {
-	return map[string]*IssueCategory{
-		"customer_support": {
-			Name: "Customer Support",
-			Keywords: []string{
-				"support", "customer service", "no response", "no reply", "agent", 
-				"ticket", "help", "contact", "chat", "email", "phone", "waiting",
-				"ignored", "unhelpful", "terrible support", "worst support",
-			},
-			Severity: "high",
-			Examples: []string{},
-		},
-		"account_locked": {
-			Name: "Account Locked/Frozen",
-			Keywords: []string{
-				"locked", "frozen", "restricted", "suspended", "blocked", "disabled",
-				"can't access", "cannot access", "locked out", "freeze", "hold",
-				"account closed", "account terminated", "verification hold",
-			},
-			Severity: "high",
-			Examples: []string{},
-		},
-		"fees": {
-			Name: "High Fees",
-			Keywords: []string{
-				"fees", "expensive", "high fee", "hidden fee", "spread", "commission",
-				"cost", "charges", "overcharge", "rip off", "ripoff", "too much",
-				"fee structure", "trading fee", "withdrawal fee",
-			},
-			Severity: "medium",
-			Examples: []string{},
-		},
-		"withdrawal": {
-			Name: "Withdrawal Problems",
-			Keywords: []string{
-				"withdraw", "withdrawal", "can't withdraw", "withdrawal pending",
-				"cash out", "transfer out", "send", "move funds", "stuck funds",
-				"withdrawal failed", "withdrawal delayed",
-			},
-			Severity: "high",
-			Examples: []string{},
-		},
-		"security": {
-			Name: "Security Issues",
-			Keywords: []string{
-				"hack", "hacked", "stolen", "scam", "phishing", "unauthorized",
-				"security", "breach", "compromised", "fraud", "theft", "lost crypto",
-				"2fa", "two factor", "sim swap",
-			},
-			Severity: "high",
-			Examples: []string{},
-		},
-		"verification": {
-			Name: "Verification Issues",
-			Keywords: []string{
-				"verification", "verify", "kyc", "identity", "id verification",
-				"document", "upload", "rejected", "pending verification",
-				"verification failed", "verify identity",
-			},
-			Severity: "medium",
-			Examples: []string{},
-		},
-		"app_bugs": {
-			Name: "App/Technical Issues",
-			Keywords: []string{
-				"bug", "crash", "not working", "glitch", "error", "broken",
-				"app issue", "loading", "slow", "lag", "freeze", "update",
-				"won't load", "won't open", "technical",
-			},
-			Severity: "medium",
-			Examples: []string{},
-		},
-		"deposits": {
-			Name: "Deposit Problems",
-			Keywords: []string{
-				"deposit", "deposit pending", "deposit missing", "deposit failed",
-				"bank transfer", "wire transfer", "ach", "funds not showing",
-				"money missing", "payment",
-			},
-			Severity: "high",
-			Examples: []string{},
-		},
-		"trading": {
-			Name: "Trading Issues",
-			Keywords: []string{
-				"trade", "trading", "order", "limit order", "market order",
-				"execution", "slippage", "price", "spread", "liquidity",
-				"can't buy", "can't sell", "order failed",
-			},
-			Severity: "medium",
-			Examples: []string{},
-		},
-		"general_negative": {
-			Name: "General Complaints",
-			Keywords: []string{
-				"terrible", "worst", "awful", "horrible", "bad", "hate",
-				"never use", "avoid", "stay away", "don't use", "nightmare",
-				"frustrating", "disappointed", "angry", "scam",
-			},
-			Severity: "low",
+	categoryKeywords := Keywords()
+	categories := make(map[string]*IssueCategory, len(categoryKeywords))
+	for _, c := range models.Categories() {
+		keywords, ok := categoryKeywords[c.ID]
+		if !ok {
+			continue
+		}
+		categories[c.ID] = &IssueCategory{
+			Name:     c.Name,
+			Keywords: keywords,
+			Severity: c.Severity,
 			Examples: []string{},
-		},
+		}
 	}
+	return categories
 }
 
 // AnalyzeFile reads and analyzes a YouTube results JSON file
 func (a *YouTubeAnalyzer) AnalyzeFile(filepath string) (*AnalysisResult, error) {
-	// Read the file
-	data, err := os.ReadFile(filepath)
+	// Stream the file into pooled slices, released once the analysis is built
+	result, release, err := loadScrapeResult(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, err
 	}
+	defer release()
 
-	// Parse JSON
-	var result models.ScrapeResult
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
-	}
+	return a.AnalyzeResult(result), nil
+}
 
+// AnalyzeResult analyzes an in-memory scrape result
+func (a *YouTubeAnalyzer) AnalyzeResult(result *models.ScrapeResult) *AnalysisResult {
 	fmt.Printf("📊 Analyzing %d videos and %d comments...\n", len(result.Videos), len(result.Comments))
 
-	// Analyze videos
-	for _, video := range result.Videos {
-		a.analyzeVideo(video)
-	}
-
-	// Analyze comments
-	for _, comment := range result.Comments {
-		a.analyzeComment(comment, result.Videos)
+	pass := &Pass{
+		Scrape:     result,
+		RunID:      result.RunID,
+		Categories: a.categories,
+		Classified: a.classified,
+		Result: &AnalysisResult{
+			TotalVideos:   len(result.Videos),
+			TotalComments: len(result.Comments),
+			Categories:    a.categories,
+			AnalyzedAt:    time.Now(),
+			RunID:         result.RunID,
+			Effort:        result.Effort(),
+		},
 	}
+	a.pipeline.Run(pass)
 
-	// Build result
-	return a.buildResult(len(result.Videos), len(result.Comments)), nil
+	a.issues = pass.Issues
+	a.uncategorized = pass.Uncategorized
+	return pass.Result
 }
 
-// analyzeVideo extracts issues from a video's title, description, and tags
-func (a *YouTubeAnalyzer) analyzeVideo(video models.YouTubeVideo) {
-	// Analyze title
-	if issues := a.findIssuesInText(video.Title); len(issues) > 0 {
-		for _, category := range issues {
-			a.addIssue(ExtractedIssue{
-				Category:    category,
-				Text:        video.Title,
-				Source:      "video_title",
-				SourceURL:   video.URL,
-				SourceTitle: video.Title,
-			})
-		}
-	}
-
-	// Analyze description (first 500 chars)
-	desc := video.Description
-	if len(desc) > 500 {
-		desc = desc[:500]
-	}
-	if issues := a.findIssuesInText(desc); len(issues) > 0 {
-		for _, category := range issues {
-			a.addIssue(ExtractedIssue{
-				Category:    category,
-				Text:        desc,
-				Source:      "video_description",
-				SourceURL:   video.URL,
-				SourceTitle: video.Title,
-			})
-		}
-	}
-
-	// Analyze tags
-	tagText := strings.Join(video.Tags, " ")
-	if issues := a.findIssuesInText(tagText); len(issues) > 0 {
-		for _, category := range issues {
-			a.addIssue(ExtractedIssue{
-				Category:    category,
-				Text:        tagText,
-				Source:      "video_tags",
-				SourceURL:   video.URL,
-				SourceTitle: video.Title,
-			})
-		}
+// videoContext captures a video's engagement for issues extracted from it or its comments
+func videoContext(video models.YouTubeVideo) *models.EngagementContext {
+	return &models.EngagementContext{
+		VideoTitle:     video.Title,
+		VideoViewCount: video.ViewCount,
+		VideoLikeCount: video.LikeCount,
 	}
 }
 
-// analyzeComment extracts issues from a comment
-func (a *YouTubeAnalyzer) analyzeComment(comment models.YouTubeComment, videos []models.YouTubeVideo) {
-	if issues := a.findIssuesInText(comment.Text); len(issues) > 0 {
-		// Find the video this comment belongs to
-		var videoURL, videoTitle string
-		for _, v := range videos {
-			if v.VideoID == comment.VideoID {
-				videoURL = v.URL
-				videoTitle = v.Title
-				break
-			}
-		}
-
-		for _, category := range issues {
-			a.addIssue(ExtractedIssue{
-				Category:    category,
-				Text:        comment.Text,
-				Source:      "comment",
-				SourceURL:   videoURL,
-				SourceTitle: videoTitle,
-				Likes:       comment.LikeCount,
-			})
-		}
-	}
+// categoryMatch is a category found in a text and every one of its keywords that matched
+type categoryMatch struct {
+	category string
+	keywords []string
 }
 
-// findIssuesInText searches text for issue keywords and returns matching categories
-func (a *YouTubeAnalyzer) findIssuesInText(text string) []string {
+// findIssuesInText searches text for issue keywords and returns matching categories,
+// sorted by ID. Every matching keyword is kept, not just the first, so keyword
+// effectiveness can credit each of them.
+func findIssuesInText(categories map[string]*IssueCategory, text string) []categoryMatch {
 	textLower := strings.ToLower(text)
-	foundCategories := []string{}
+	matches := []categoryMatch{}
 
-	for categoryName, category := range a.categories {
+	for categoryName, category := range categories {
+		var found []string
 		for _, keyword := range category.Keywords {
 			// Use word boundary matching for more accuracy
 			pattern := `\b` + regexp.QuoteMeta(strings.ToLower(keyword)) + `\b`
 			if matched, _ := regexp.MatchString(pattern, textLower); matched {
-				foundCategories = append(foundCategories, categoryName)
-				break // One match per category is enough
+				found = append(found, keyword)
 			}
 		}
+		if len(found) > 0 {
+			matches = append(matches, categoryMatch{category: categoryName, keywords: found})
+		}
 	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].category < matches[j].category })
 
-	return foundCategories
+	return matches
 }
 
-// addIssue adds an issue and updates category counts
-func (a *YouTubeAnalyzer) addIssue(issue ExtractedIssue) {
-	issue.ID = fmt.Sprintf("issue_%d", len(a.issues)+1)
-	issue.ExtractedAt = time.Now()
-	a.issues = append(a.issues, issue)
-
-	// Update category
-	if cat, exists := a.categories[issue.Category]; exists {
-		cat.Count++
-		// Keep top 5 examples
-		if len(cat.Examples) < 5 {
-			// Truncate long text
-			example := issue.Text
-			if len(example) > 150 {
-				example = example[:150] + "..."
-			}
-			cat.Examples = append(cat.Examples, example)
-		}
+// Categorize returns the categories whose keywords appear in text, sorted by ID.
+// Used for complaints that don't come through a YouTube scrape.
+func Categorize(text string) []string {
+	categories := []string{}
+	for _, match := range findIssuesInText(initCategories(), text) {
+		categories = append(categories, match.category)
 	}
+	sort.Strings(categories)
+	return categories
 }
 
-// buildResult compiles the final analysis result
-func (a *YouTubeAnalyzer) buildResult(videoCount, commentCount int) *AnalysisResult {
-	result := &AnalysisResult{
-		TotalVideos:   videoCount,
-		TotalComments: commentCount,
-		TotalIssues:   len(a.issues),
-		Categories:    a.categories,
-		AnalyzedAt:    time.Now(),
-	}
-
-	// Build category summaries sorted by count
-	summaries := []CategorySummary{}
-	for name, cat := range a.categories {
-		if cat.Count > 0 {
-			percentage := 0.0
-			if len(a.issues) > 0 {
-				percentage = float64(cat.Count) / float64(len(a.issues)) * 100
-			}
-			summaries = append(summaries, CategorySummary{
-				Category:    name,
-				Count:       cat.Count,
-				Percentage:  percentage,
-				TopExamples: cat.Examples,
-			})
-		}
-	}
-
-	// Sort by count descending
-	sort.Slice(summaries, func(i, j int) bool {
-		return summaries[i].Count > summaries[j].Count
-	})
-	result.IssuesByCategory = summaries
-
-	// Get top issues (comments with most likes)
-	sort.Slice(a.issues, func(i, j int) bool {
-		return a.issues[i].Likes > a.issues[j].Likes
-	})
-
-	// Top 20 issues
-	topCount := 20
-	if len(a.issues) < topCount {
-		topCount = len(a.issues)
-	}
-	result.TopIssues = a.issues[:topCount]
-
-	return result
+// Issues returns every issue extracted so far, sorted by likes once a result is built
+func (a *YouTubeAnalyzer) Issues() []ExtractedIssue {
+	return a.issues
 }
 
 // PrintSummary prints a human-readable summary
@@ -395,10 +325,7 @@ func (a *YouTubeAnalyzer) PrintSummary(result *AnalysisResult) {
 		if i >= 5 {
 			break
 		}
-		text := issue.Text
-		if len(text) > 100 {
-			text = text[:100] + "..."
-		}
+		text := textutil.Truncate(issue.Text, config.Truncation.SummaryComment)
 		fmt.Printf("%d. [%s] (👍 %d likes)\n   \"%s\"\n\n", 
 			i+1, 
 			a.categories[issue.Category].Name,
@@ -407,14 +334,14 @@ func (a *YouTubeAnalyzer) PrintSummary(result *AnalysisResult) {
 	}
 }
 
-// SaveResults saves the analysis to a JSON file
+// SaveResults saves the analysis to a JSON file, recording its checksum
 func SaveAnalysisResults(result *AnalysisResult, filepath string) error {
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal results: %w", err)
 	}
 
-	if err := os.WriteFile(filepath, data, 0644); err != nil {
+	if err := integrity.WriteFile(filepath, data); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestFindIssuesInTextOrder checks matches come back sorted by category with every
+// matching keyword, whatever order the category map is iterated in
+func TestFindIssuesInTextOrder(t *testing.T) {
+	categories := map[string]*IssueCategory{
+		"withdrawals": {Keywords: []string{"withdraw", "stuck"}},
+		"app_bugs":    {Keywords: []string{"crash", "freezes"}},
+		"fees":        {Keywords: []string{"fee"}},
+		"support":     {Keywords: []string{"support"}},
+	}
+	text := "The app freezes when I withdraw, then I'm stuck and support won't answer"
+
+	for range 20 {
+		matches := findIssuesInText(categories, text)
+		got := []string{}
+		for _, m := range matches {
+			got = append(got, m.category)
+		}
+		if want := []string{"app_bugs", "support", "withdrawals"}; !slices.Equal(got, want) {
+			t.Fatalf("matched %v, want %v", got, want)
+		}
+		if want := []string{"withdraw", "stuck"}; !slices.Equal(matches[2].keywords, want) {
+			t.Fatalf("withdrawals matched %v, want %v", matches[2].keywords, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// Exchange changelog, blog and status announcements, linked to the resolutions they explain
+package announcements
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// StoreFile is the announcements file inside the data directory
+const StoreFile = "announcements.json"
+
+// Announcement sources
+const (
+	SourceChangelog = "changelog"
+	SourceBlog      = "blog"
+	SourceStatus    = "status"
+)
+
+// ============================================
+// LINKING
+// ============================================
+// A resolution says complaints in a category dropped; an announcement often says
+// why ("fee page redesign", "withdrawal backlog cleared"). An announcement is linked
+// to a resolution when it is from the same exchange, its title or summary matches
+// the resolution's category, and it was published between LinkLeadTime before the
+// measurement window opened and the window's end - a fix usually ships shortly
+// before complaints start falling.
+
+const (
+	// LinkLeadTime is how long before a measurement window an announcement may be published and still be linked
+	LinkLeadTime = 30 * 24 * time.Hour
+	// MaxLinks caps the announcements linked to one resolution
+	MaxLinks = 5
+)
+
+// Announcement is one post from an exchange's changelog, blog or status page
+type Announcement struct {
+	ID          string    `json:"id"`
+	Exchange    string    `json:"exchange"`
+	Source      string    `json:"source"` // "changelog", "blog", "status"
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Summary     string    `json:"summary,omitempty"`
+	Categories  []string  `json:"categories"` // Complaint categories the title and summary match
+	PublishedAt time.Time `json:"published_at"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// Store keeps announcements persisted to a JSON file
+type Store struct {
+	path          string
+	announcements []Announcement
+	mu            sync.RWMutex
+}
+
+// NewStore loads announcements from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, StoreFile)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read announcements: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.announcements); err != nil {
+		return nil, fmt.Errorf("failed to parse announcements: %w", err)
+	}
+	return s, nil
+}
+
+// Add stores announcements, replacing any already stored with the same ID, and
+// returns how many were new
+func (s *Store) Add(announcements []Announcement) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := make(map[string]int, len(s.announcements))
+	for i, a := range s.announcements {
+		index[a.ID] = i
+	}
+
+	added := 0
+	for _, a := range announcements {
+		if i, ok := index[a.ID]; ok {
+			s.announcements[i] = a
+			continue
+		}
+		index[a.ID] = len(s.announcements)
+		s.announcements = append(s.announcements, a)
+		added++
+	}
+	return added, s.save()
+}
+
+// List returns an exchange's announcements, newest first. An empty category
+// returns every category.
+func (s *Store) List(exchange, category string) []Announcement {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := []Announcement{}
+	for _, a := range s.announcements {
+		if a.Exchange != exchange {
+			continue
+		}
+		if category != "" && !slices.Contains(a.Categories, category) {
+			continue
+		}
+		list = append(list, a)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].PublishedAt.After(list[j].PublishedAt) })
+	return list
+}
+
+// Links returns the announcements that may explain a resolution, newest first.
+// It satisfies services.AnnouncementLinker.
+func (s *Store) Links(resolution *models.Resolution) []models.AnnouncementLink {
+	from := resolution.Evidence.MeasurementStart.Add(-LinkLeadTime)
+	to := resolution.Evidence.MeasurementEnd
+
+	links := []models.AnnouncementLink{}
+	for _, a := range s.List(resolution.Exchange, resolution.IssueCategory) {
+		if a.PublishedAt.Before(from) || a.PublishedAt.After(to) {
+			continue
+		}
+		links = append(links, models.AnnouncementLink{
+			Title:       a.Title,
+			URL:         a.URL,
+			Source:      a.Source,
+			PublishedAt: a.PublishedAt,
+		})
+		if len(links) == MaxLinks {
+			break
+		}
+	}
+	return links
+}
+
+// save writes the announcements. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.announcements, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal announcements: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write announcements: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// FEEDS
+// ============================================
+
+// Feed is an RSS or Atom feed of one kind of announcement
+type Feed struct {
+	Source string // "changelog", "blog", "status"
+	URL    string
+}
+
+// DefaultFeeds are Coinbase's announcement feeds, fetched unless ANNOUNCEMENT_FEEDS replaces them
+var DefaultFeeds = []Feed{
+	{Source: SourceStatus, URL: "https://status.coinbase.com/history.rss"},
+	{Source: SourceBlog, URL: "https://www.coinbase.com/blog/rss.xml"},
+}
+
+// FeedsFromEnv reads ANNOUNCEMENT_FEEDS ("source=url,source=url"), falling back to DefaultFeeds
+func FeedsFromEnv() ([]Feed, error) {
+	raw := strings.TrimSpace(os.Getenv("ANNOUNCEMENT_FEEDS"))
+	if raw == "" {
+		return DefaultFeeds, nil
+	}
+
+	feeds := []Feed{}
+	for _, entry := range strings.Split(raw, ",") {
+		source, url, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		source = strings.TrimSpace(source)
+		if !ok || strings.TrimSpace(url) == "" {
+			return nil, fmt.Errorf("ANNOUNCEMENT_FEEDS entry %q must be source=url", entry)
+		}
+		if source != SourceChangelog && source != SourceBlog && source != SourceStatus {
+			return nil, fmt.Errorf("ANNOUNCEMENT_FEEDS source %q must be changelog, blog or status", source)
+		}
+		feeds = append(feeds, Feed{Source: source, URL: strings.TrimSpace(url)})
+	}
+	return feeds, nil
+}
+
+// FetchFeed downloads and parses a feed's announcements for an exchange
+func FetchFeed(ctx context.Context, client *http.Client, exchange string, feed Feed) ([]Announcement, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpclient.Identify(req, "announcements")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", feed.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, health.StatusError("announcement feed", resp.StatusCode, string(snippet))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read announcement feed: %w", err)
+	}
+	return ParseFeed(body, exchange, feed.Source, time.Now())
+}
+
+// rssFeed and atomFeed are the parts of RSS 2.0 and Atom documents announcements come from
+type rssFeed struct {
+	Items []struct {
+		GUID        string `xml:"guid"`
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+type atomFeed struct {
+	Entries []struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// ParseFeed reads the announcements in an RSS 2.0 or Atom document. Entries
+// without a parseable date are skipped, since they can't be placed in a window.
+func ParseFeed(body []byte, exchange, source string, now time.Time) ([]Announcement, error) {
+	var root struct{ XMLName xml.Name }
+	if err := xml.Unmarshal(body, &root); err != nil {
+		return nil, health.NewError(health.ErrorParse, fmt.Errorf("invalid feed: %w", err))
+	}
+
+	announcements := []Announcement{}
+	add := func(guid, title, link, summary, published string) {
+		publishedAt, ok := parseFeedTime(published)
+		if !ok {
+			return
+		}
+		title = strings.TrimSpace(title)
+		summary = strings.TrimSpace(stripTags(summary))
+		if guid == "" {
+			guid = link
+		}
+		announcements = append(announcements, Announcement{
+			ID:          announcementID(exchange, source, guid),
+			Exchange:    exchange,
+			Source:      source,
+			Title:       title,
+			URL:         strings.TrimSpace(link),
+			Summary:     summary,
+			Categories:  analyzer.Categorize(title + "\n" + summary),
+			PublishedAt: publishedAt,
+			FetchedAt:   now,
+		})
+	}
+
+	switch root.XMLName.Local {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, health.NewError(health.ErrorParse, fmt.Errorf("invalid RSS feed: %w", err))
+		}
+		for _, item := range feed.Items {
+			add(item.GUID, item.Title, item.Link, item.Description, item.PubDate)
+		}
+	case "feed":
+		var feed atomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, health.NewError(health.ErrorParse, fmt.Errorf("invalid Atom feed: %w", err))
+		}
+		for _, entry := range feed.Entries {
+			link := ""
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			summary := entry.Summary
+			if summary == "" {
+				summary = entry.Content
+			}
+			published := entry.Published
+			if published == "" {
+				published = entry.Updated
+			}
+			add(entry.ID, entry.Title, link, summary, published)
+		}
+	default:
+		return nil, health.NewError(health.ErrorParse, fmt.Errorf("unsupported feed format <%s>", root.XMLName.Local))
+	}
+	return announcements, nil
+}
+
+// feedTimeLayouts are the date formats RSS and Atom feeds use in practice
+var feedTimeLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST"}
+
+func parseFeedTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// stripTags removes the HTML markup feeds put in descriptions
+func stripTags(html string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range html {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+			b.WriteRune(' ')
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// announcementID identifies an announcement by its feed GUID, so refetching a feed
+// updates entries rather than duplicating them
+func announcementID(exchange, source, guid string) string {
+	sum := sha256.Sum256([]byte(exchange + "|" + source + "|" + guid))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,406 @@
+// Harness for exercising the API handlers with httptest and golden JSON files.
+//
+// A test builds the full API with NewServer, issues requests with Do, and compares
+// responses against testdata/<name>.golden.json with AssertGolden. The test package
+// declares the -update flag and passes it in Options; with it set, AssertGolden
+// rewrites the golden files after an intended response change.
+//
+//	var update = flag.Bool("update", false, "rewrite golden files")
+//
+//	srv := apitest.NewServer(t, apitest.FixtureDir, apitest.Options{Update: *update})
+//	rec := srv.Do(t, "GET", "/api/categories", nil)
+//	srv.AssertGolden(t, rec, "categories")
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/announcements"
+	"github.com/tasnint/coinsights/internal/api/handlers"
+	"github.com/tasnint/coinsights/internal/api/openapi"
+	"github.com/tasnint/coinsights/internal/audit"
+	"github.com/tasnint/coinsights/internal/auth"
+	"github.com/tasnint/coinsights/internal/ingest"
+	"github.com/tasnint/coinsights/internal/ratings"
+	"github.com/tasnint/coinsights/internal/scheduler"
+	"github.com/tasnint/coinsights/internal/scorecards"
+	"github.com/tasnint/coinsights/internal/scrapesettings"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/subscriptions"
+	"github.com/tasnint/coinsights/internal/taxonomy"
+	"github.com/tasnint/coinsights/internal/tenants"
+)
+
+// IngestPartner and IngestKey are the credentials NewServer accepts on /api/ingest/complaints
+const (
+	IngestPartner = "test"
+	IngestKey     = "test-ingest-key"
+)
+
+// API keys NewServer accepts. Reads stay open to anonymous callers, as in the default deployment.
+const (
+	OperatorKey = "test-operator-key" // Attestor role, sees every tenant
+	ReaderKey   = "test-reader-key"   // Reader role
+	TenantID    = "acme"              // Tenant seeded in the tenant store
+	TenantKey   = "test-tenant-key"   // Writer scoped to TenantID
+)
+
+// FixtureDir is the sample data directory shipped with the harness: a small YouTube
+// scrape and Gemini answer set with deterministic timestamps
+var FixtureDir = filepath.Join(packageDir(), "testdata", "data")
+
+// Volatile are response fields that change between runs; AssertGolden masks them
+var Volatile = map[string]bool{
+	"generated_at":   true,
+	"data_loaded_at": true,
+	"analyzed_at":    true,
+	"extracted_at":   true,
+	"scraped_at":     true,
+	"created_at":     true,
+	"first_detected": true,
+	"last_updated":   true,
+	"run_id":         true,
+	"checked_at":     true,
+	"verified_at":    true,
+	"reviewed_at":    true,
+	"archived_at":    true,
+	"updated_at":     true,
+	"loaded_at":      true,
+	"queued_at":      true,
+	"scheduled_at":   true,
+	"next_run":       true,
+	"last_sync_at":   true,
+	"exported_at":    true,
+	"sla":            true, // Deadlines count down from the wall clock
+}
+
+// timestamp matches RFC 3339 times in text bodies (feeds, Markdown), which AssertGolden masks
+var timestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// Options configure NewServer
+type Options struct {
+	Chain   *MockChain // Attest on this chain; nil answers attestation endpoints with 503
+	Primary string     // Serve as a read-only mirror of the API at this URL
+	Update  bool       // Rewrite golden files instead of comparing with them (the test's -update flag)
+
+	BodyLimits *handlers.BodyLimits // Request body limits; nil uses handlers.DefaultBodyLimits
+}
+
+// Server is the full API over a copy of a data directory and a fake resolution service
+type Server struct {
+	Handler    http.Handler
+	Resolution *services.ResolutionService
+	Fixtures   Fixtures
+	Data       *handlers.DataHandler
+	Tenants    *tenants.Store
+	Indexer    *services.AttestationIndexer // Set with a chain
+	Mirror     *services.Mirror             // Set with a primary
+	DataDir    string
+
+	update  bool
+	aliases []string // Pairs of generated value, placeholder
+}
+
+// NewServer copies dataDir into a temp directory, loads it, and mounts every handler
+// behind the OpenAPI validator and API key auth, the same way coinsights serve does.
+// Issues and resolutions come from NewFakeResolutionService.
+func NewServer(t testing.TB, dataDir string, opts Options) *Server {
+	t.Helper()
+
+	dir := t.TempDir()
+	copyDir(t, dataDir, dir)
+
+	var bs *services.BlockchainService
+	if opts.Chain != nil {
+		bs = opts.Chain.Service(t)
+	}
+	rs, fixtures := NewFakeResolutionService(t, bs)
+
+	data := handlers.NewDataHandler(dir)
+	if err := data.Reload(); err != nil {
+		t.Fatalf("failed to load fixture data: %v", err)
+	}
+
+	labels, err := audit.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := handlers.NewAdminHandler(dir)
+	admin.EnableAudits(data, labels)
+	categories, err := taxonomy.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.EnableTaxonomy(categories, data)
+	scraperSettings, err := scrapesettings.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin.EnableScraperSettings(scraperSettings)
+
+	subs, err := subscriptions.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratingStore, err := ratings.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ingestStore, err := ingest.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorecardHistory, err := scorecards.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	announcementStore, err := announcements.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrapeRuns, err := scheduler.NewRunStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evidence, err := services.NewEvidenceStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.SetEvidenceStore(evidence)
+	tenantStore, err := tenants.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tenantStore.Put(tenants.Tenant{ID: TenantID, Name: "Acme Watch"}); err != nil {
+		t.Fatal(err)
+	}
+	signingKey, err := subscriptions.NewSigningKey(make([]byte, 32)) // Fixed seed keeps golden files stable
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &Server{Resolution: rs, Fixtures: fixtures, Data: data, Tenants: tenantStore, DataDir: dir, update: opts.Update}
+	srv.Alias(fixtures.Resolution, "<resolution>")
+	srv.Alias(fixtures.ReviewResolution, "<review-resolution>")
+	if opts.Chain != nil {
+		srv.Alias(opts.Chain.URL, "<rpc>")
+	}
+
+	mux := http.NewServeMux()
+	blockchain := handlers.NewBlockchainHandler(rs, bs)
+	if bs != nil {
+		blockchain.SetAttestationQueue(services.NewAttestationQueue(rs, time.Hour))
+		opts.Chain.Mine(services.IndexConfirmations) // Confirm the seeded attestation for the indexer
+		if srv.Indexer, err = services.NewAttestationIndexer(bs, dir, MockGenesis); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := srv.Indexer.Sync(context.Background()); err != nil {
+			t.Fatalf("failed to index the mock chain: %v", err)
+		}
+		blockchain.SetAttestationIndexer(srv.Indexer)
+	}
+	blockchain.EnableTenants(tenantStore)
+	blockchain.Register(mux)
+	data.Register(mux)
+	admin.Register(mux)
+	handlers.NewSubscriptionHandler(subs, rs).Register(mux)
+	exchanges := handlers.NewExchangeHandler(rs, ratingStore)
+	exchanges.EnableSentiment("coinbase", data)
+	exchanges.EnableHistory(scorecardHistory)
+	exchanges.Register(mux)
+	handlers.NewDashboardHandler(data, rs).Register(mux)
+	ingestHandler := handlers.NewIngestHandler(ingestStore, ingest.Keys{IngestKey: IngestPartner}, data)
+	ingestHandler.SetTenants(tenantStore)
+	ingestHandler.Register(mux)
+	handlers.NewEvidenceHandler(data, rs).Register(mux)
+	handlers.NewSearchHandler(rs).Register(mux)
+	handlers.NewFeedHandler(rs).Register(mux)
+	handlers.NewSigningKeyHandler(signingKey).Register(mux)
+	handlers.NewAnnouncementHandler(announcementStore, rs).Register(mux)
+	handlers.NewScrapeRunHandler(scrapeRuns, nil).Register(mux)
+	handlers.NewTenantHandler(tenantStore, rs).Register(mux)
+	if opts.Primary != "" {
+		if srv.Mirror, err = services.NewMirror(rs, opts.Primary); err != nil {
+			t.Fatal(err)
+		}
+		handlers.NewMirrorHandler(srv.Mirror).Register(mux)
+	}
+
+	limits := handlers.DefaultBodyLimits()
+	if opts.BodyLimits != nil {
+		limits = *opts.BodyLimits
+	}
+	validator, err := openapi.NewValidator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator.SetMaxBodyBytes(limits.Largest())
+	handler := validator.Middleware(handlers.WithTimeouts(mux, handlers.RouteTimeouts))
+	if opts.Primary != "" {
+		handler = handlers.ReadOnly(handler)
+	}
+	authn := auth.New([]auth.KeyEntry{
+		keyEntry("operator", auth.RoleAttestor, "", OperatorKey),
+		keyEntry("reader", auth.RoleReader, "", ReaderKey),
+		keyEntry("ops", auth.RoleWriter, TenantID, TenantKey),
+	}, nil, true)
+	handler = handlers.WithAuth(mux, handler, authn, handlers.DefaultAuthPolicy(tenantStore))
+	srv.Handler = handlers.WithBodyLimits(mux, handler, limits)
+	return srv
+}
+
+// keyEntry stores key the way api_keys.json does
+func keyEntry(name string, role auth.Role, tenant, key string) auth.KeyEntry {
+	sum := sha256.Sum256([]byte(key))
+	return auth.KeyEntry{Name: name, Role: role, Tenant: tenant, KeySHA256: hex.EncodeToString(sum[:])}
+}
+
+// Alias makes golden files show placeholder wherever value, a generated ID, appears
+func (s *Server) Alias(value, placeholder string) {
+	if value != "" {
+		s.aliases = append(s.aliases, value, placeholder)
+	}
+}
+
+// Do sends a request with OperatorKey. A non-nil body is sent as JSON.
+func (s *Server) Do(t testing.TB, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return s.DoAs(t, OperatorKey, method, target, body)
+}
+
+// DoAs sends a request with key; an empty key sends it anonymously
+func (s *Server) DoAs(t testing.TB, key, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, target, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("X-API-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// AssertGolden compares a response's status and JSON body with testdata/<name>.golden.json,
+// masking Volatile fields and aliased IDs. With Options.Update the golden file is rewritten instead.
+func (s *Server) AssertGolden(t testing.TB, rec *httptest.ResponseRecorder, name string) {
+	t.Helper()
+
+	got, err := Canonical(rec.Code, rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	got = []byte(strings.NewReplacer(s.aliases...).Replace(string(got)))
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if s.update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s: %v (run with -update to create it)", name, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s: response differs from %s\n--- got\n%s\n--- want\n%s", name, path, got, want)
+	}
+}
+
+// Canonical renders a status and JSON body as indented JSON with sorted keys and
+// Volatile fields replaced by "<volatile>". Other bodies (Markdown, feeds, CSV) are kept as text.
+func Canonical(status int, body []byte) ([]byte, error) {
+	var value any
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &value); err != nil {
+			value = timestamp.ReplaceAllString(string(body), "<volatile>")
+		}
+	}
+	out, err := json.MarshalIndent(map[string]any{
+		"status": status,
+		"body":   mask(value),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// mask replaces Volatile fields anywhere in a decoded JSON value
+func mask(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if Volatile[key] && field != nil {
+				v[key] = "<volatile>"
+			} else {
+				v[key] = mask(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = mask(v[i])
+		}
+	}
+	return value
+}
+
+// copyDir copies the regular files in src into dst
+func copyDir(t testing.TB, src, dst string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatalf("failed to read fixture directory: %v", err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dst, entry.Name()), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// packageDir is this package's source directory, so FixtureDir works from any test's working directory
+func packageDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
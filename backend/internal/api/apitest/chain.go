@@ -0,0 +1,545 @@
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// ============================================
+// MOCK CHAIN
+// ============================================
+// MockChain is an in-process JSON-RPC node running the ResolutionAttestation
+// contract in memory. It answers the calls BlockchainService makes - fees, gas
+// estimates, raw transactions, receipts, logs and the contract's view functions -
+// and mines each transaction into its own block at a fixed time, so attestation
+// responses are the same on every run.
+
+// Mock chain constants. MockKey is a throwaway key: its address holds MockBalance.
+const (
+	MockNetwork     = "base_sepolia"
+	MockChainID     = 84532
+	MockContract    = "0x00000000000000000000000000000000c0175165"
+	MockKey         = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	MockGenesis     = 1000 // Head block before any transaction
+	MockBlockTime   = 12 * time.Second
+	MockGasEstimate = 100000
+)
+
+var (
+	// MockGenesisTime is the timestamp of block MockGenesis
+	MockGenesisTime = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	// MockBaseFee and MockTip are the fees every block reports
+	MockBaseFee = big.NewInt(1_000_000)
+	MockTip     = big.NewInt(100_000)
+	// MockBalance is the attestor wallet's balance: 1 ETH
+	MockBalance = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+)
+
+// MockChain is a fake attestation chain served over JSON-RPC
+type MockChain struct {
+	URL string
+
+	server       *httptest.Server
+	abi          abi.ABI
+	mu           sync.Mutex
+	head         uint64
+	attestations []mockAttestation
+	byHash       map[[32]byte]uint64
+	latest       map[common.Hash][32]byte // keccak(exchange, category) -> last evidence hash
+	receipts     map[common.Hash]*types.Receipt
+	logs         []*types.Log
+	nonces       map[common.Address]uint64
+}
+
+type mockAttestation struct {
+	evidenceHash  [32]byte
+	previousHash  [32]byte
+	timestamp     uint64
+	blockNumber   uint64
+	exchange      string
+	issueCategory string
+	attestor      common.Address
+}
+
+// NewMockChain starts a mock chain, stopped when the test ends
+func NewMockChain(t testing.TB) *MockChain {
+	t.Helper()
+
+	parsed, err := abi.JSON(strings.NewReader(services.ResolutionAttestationABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &MockChain{
+		abi:      parsed,
+		head:     MockGenesis,
+		byHash:   make(map[[32]byte]uint64),
+		latest:   make(map[common.Hash][32]byte),
+		receipts: make(map[common.Hash]*types.Receipt),
+		nonces:   make(map[common.Address]uint64),
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(c.serveRPC))
+	c.URL = c.server.URL
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+// Service connects a BlockchainService to the chain with MockKey, the way
+// coinsights serve connects to a real one from the environment
+func (c *MockChain) Service(t testing.TB) *services.BlockchainService {
+	t.Helper()
+
+	t.Setenv("BLOCKCHAIN_NETWORK", MockNetwork)
+	t.Setenv("BLOCKCHAIN_RPC_URL", c.URL)
+	t.Setenv("ATTESTATION_CONTRACT_ADDRESS", MockContract)
+	t.Setenv("BLOCKCHAIN_PRIVATE_KEY", MockKey)
+	t.Setenv("ATTESTATION_METADATA_URI", "")
+	t.Setenv("ATTESTATION_GAS_CEILING", "")
+	bs, err := services.NewBlockchainService()
+	if err != nil {
+		t.Fatalf("failed to connect to the mock chain: %v", err)
+	}
+	t.Cleanup(bs.Close)
+	return bs
+}
+
+// Mine adds n empty blocks, e.g. to confirm earlier transactions
+func (c *MockChain) Mine(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head += n
+}
+
+// Count returns how many attestations the contract holds
+func (c *MockChain) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.attestations)
+}
+
+// ============================================
+// JSON-RPC
+// ============================================
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// serveRPC answers single and batched JSON-RPC requests
+func (c *MockChain) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var batch []rpcRequest
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		responses := make([]rpcResponse, len(batch))
+		for i, req := range batch {
+			responses[i] = c.answer(req)
+		}
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(c.answer(req))
+}
+
+// answer runs one JSON-RPC call
+func (c *MockChain) answer(req rpcRequest) rpcResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, err := c.call(req.Method, req.Params)
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+	if err != nil {
+		resp.Result = nil
+		resp.Error = err
+	}
+	return resp
+}
+
+// call dispatches a JSON-RPC method. Must be called with c.mu held.
+func (c *MockChain) call(method string, params []json.RawMessage) (any, *rpcError) {
+	switch method {
+	case "eth_chainId", "net_version":
+		return hexutil.Uint64(MockChainID), nil
+	case "eth_blockNumber":
+		return hexutil.Uint64(c.head), nil
+	case "eth_getBlockByNumber":
+		return c.block(params)
+	case "eth_feeHistory":
+		return c.feeHistory(params)
+	case "eth_maxPriorityFeePerGas":
+		return (*hexutil.Big)(MockTip), nil
+	case "eth_gasPrice":
+		return (*hexutil.Big)(new(big.Int).Add(MockBaseFee, MockTip)), nil
+	case "eth_getBalance":
+		return (*hexutil.Big)(MockBalance), nil
+	case "eth_getTransactionCount":
+		var address common.Address
+		if err := param(params, 0, &address); err != nil {
+			return nil, err
+		}
+		return hexutil.Uint64(c.nonces[address]), nil
+	case "eth_estimateGas":
+		return hexutil.Uint64(MockGasEstimate), nil
+	case "eth_call":
+		return c.ethCall(params)
+	case "eth_sendRawTransaction":
+		return c.sendRawTransaction(params)
+	case "eth_getTransactionReceipt":
+		var hash common.Hash
+		if err := param(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		if receipt, ok := c.receipts[hash]; ok {
+			return receipt, nil
+		}
+		return nil, nil
+	case "eth_getLogs":
+		return c.filterLogs(params)
+	}
+	return nil, &rpcError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", method)}
+}
+
+// param decodes the i-th parameter into out
+func param(params []json.RawMessage, i int, out any) *rpcError {
+	if i >= len(params) {
+		return &rpcError{Code: -32602, Message: fmt.Sprintf("missing value for required argument %d", i)}
+	}
+	if err := json.Unmarshal(params[i], out); err != nil {
+		return &rpcError{Code: -32602, Message: fmt.Sprintf("invalid argument %d: %v", i, err)}
+	}
+	return nil
+}
+
+// blockNumber resolves a block tag or hex number against the head
+func (c *MockChain) blockNumber(tag string) uint64 {
+	switch tag {
+	case "", "latest", "pending", "safe", "finalized":
+		return c.head
+	case "earliest":
+		return 0
+	}
+	n, err := hexutil.DecodeUint64(tag)
+	if err != nil || n > c.head {
+		return c.head
+	}
+	return n
+}
+
+// blockTime is block n's timestamp
+func blockTime(n uint64) uint64 {
+	return uint64(MockGenesisTime.Unix()) + (n-MockGenesis)*uint64(MockBlockTime/time.Second)
+}
+
+// header builds block n's header. Bodies are always empty: receipts and logs carry the transactions.
+func (c *MockChain) header(n uint64) *types.Header {
+	return &types.Header{
+		ParentHash:  common.BigToHash(new(big.Int).SetUint64(n - 1)),
+		UncleHash:   types.EmptyUncleHash,
+		Root:        types.EmptyRootHash,
+		TxHash:      types.EmptyTxsHash,
+		ReceiptHash: types.EmptyReceiptsHash,
+		Difficulty:  new(big.Int),
+		Number:      new(big.Int).SetUint64(n),
+		GasLimit:    30_000_000,
+		Time:        blockTime(n),
+		BaseFee:     MockBaseFee,
+	}
+}
+
+func (c *MockChain) block(params []json.RawMessage) (any, *rpcError) {
+	var tag string
+	if err := param(params, 0, &tag); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(c.header(c.blockNumber(tag)))
+	if err != nil {
+		return nil, &rpcError{Code: -32603, Message: err.Error()}
+	}
+	var block map[string]any
+	if err := json.Unmarshal(encoded, &block); err != nil {
+		return nil, &rpcError{Code: -32603, Message: err.Error()}
+	}
+	block["transactions"] = []any{}
+	block["uncles"] = []any{}
+	return block, nil
+}
+
+func (c *MockChain) feeHistory(params []json.RawMessage) (any, *rpcError) {
+	var count hexutil.Uint64
+	if err := param(params, 0, &count); err != nil {
+		return nil, err
+	}
+	count = min(count, hexutil.Uint64(c.head))
+	baseFees := make([]*hexutil.Big, count+1) // One past the newest block: the next block's base fee
+	rewards := make([][]*hexutil.Big, count)
+	ratios := make([]float64, count)
+	for i := range baseFees {
+		baseFees[i] = (*hexutil.Big)(MockBaseFee)
+	}
+	for i := range rewards {
+		rewards[i] = []*hexutil.Big{(*hexutil.Big)(MockTip)}
+		ratios[i] = 0.5
+	}
+	return map[string]any{
+		"oldestBlock":   hexutil.Uint64(c.head - uint64(count) + 1),
+		"baseFeePerGas": baseFees,
+		"reward":        rewards,
+		"gasUsedRatio":  ratios,
+	}, nil
+}
+
+// ============================================
+// CONTRACT
+// ============================================
+
+// ethCall runs one of the contract's view functions
+func (c *MockChain) ethCall(params []json.RawMessage) (any, *rpcError) {
+	var msg struct {
+		To    *common.Address `json:"to"`
+		Data  hexutil.Bytes   `json:"data"`
+		Input hexutil.Bytes   `json:"input"`
+	}
+	if err := param(params, 0, &msg); err != nil {
+		return nil, err
+	}
+	data := msg.Input
+	if len(data) == 0 {
+		data = msg.Data
+	}
+	if msg.To == nil || *msg.To != common.HexToAddress(MockContract) || len(data) < 4 {
+		return hexutil.Bytes{}, nil // No code at the address
+	}
+
+	method, err := c.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, reverted()
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, reverted()
+	}
+
+	var out []byte
+	switch method.Name {
+	case "attestationCount":
+		out, err = method.Outputs.Pack(big.NewInt(int64(len(c.attestations))))
+	case "verifyHash":
+		id, ok := c.byHash[args[0].([32]byte)]
+		out, err = method.Outputs.Pack(ok, new(big.Int).SetUint64(id))
+	case "getAttestation":
+		id := args[0].(*big.Int)
+		if !id.IsUint64() || id.Uint64() >= uint64(len(c.attestations)) {
+			return nil, reverted()
+		}
+		a := c.attestations[id.Uint64()]
+		out, err = method.Outputs.Pack(a.evidenceHash, a.previousHash, new(big.Int).SetUint64(a.timestamp),
+			new(big.Int).SetUint64(a.blockNumber), a.exchange, a.issueCategory, a.attestor)
+	default:
+		return nil, reverted()
+	}
+	if err != nil {
+		return nil, &rpcError{Code: -32603, Message: err.Error()}
+	}
+	return hexutil.Bytes(out), nil
+}
+
+// reverted is the error a node answers a reverting call with
+func reverted() *rpcError {
+	return &rpcError{Code: 3, Message: "execution reverted"}
+}
+
+// sendRawTransaction runs a signed transaction against the contract and mines it
+func (c *MockChain) sendRawTransaction(params []json.RawMessage) (any, *rpcError) {
+	var raw hexutil.Bytes
+	if err := param(params, 0, &raw); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, &rpcError{Code: -32602, Message: fmt.Sprintf("invalid transaction: %v", err)}
+	}
+	if tx.ChainId().Int64() != MockChainID {
+		return nil, &rpcError{Code: -32000, Message: "invalid chain id"}
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, &rpcError{Code: -32000, Message: fmt.Sprintf("invalid sender: %v", err)}
+	}
+	if tx.Nonce() != c.nonces[sender] {
+		return nil, &rpcError{Code: -32000, Message: "nonce too low"}
+	}
+
+	c.head++
+	c.nonces[sender]++
+	blockHash := common.BigToHash(new(big.Int).SetUint64(c.head))
+	receipt := &types.Receipt{
+		Type:              tx.Type(),
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: MockGasEstimate,
+		GasUsed:           MockGasEstimate,
+		EffectiveGasPrice: new(big.Int).Add(MockBaseFee, MockTip),
+		TxHash:            tx.Hash(),
+		BlockHash:         blockHash,
+		BlockNumber:       new(big.Int).SetUint64(c.head),
+	}
+	if tx.To() != nil && *tx.To() == common.HexToAddress(MockContract) {
+		logs, ok := c.execute(tx.Data(), sender)
+		if !ok {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		for _, log := range logs {
+			log.TxHash = tx.Hash()
+			log.BlockHash = blockHash
+			log.BlockNumber = c.head
+			log.Index = uint(len(c.logs))
+			c.logs = append(c.logs, log)
+		}
+		receipt.Logs = logs
+	}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	c.receipts[tx.Hash()] = receipt
+	return tx.Hash(), nil
+}
+
+// execute applies a contract call to the contract's state, returning the logs it emits
+func (c *MockChain) execute(data []byte, sender common.Address) ([]*types.Log, bool) {
+	if len(data) < 4 {
+		return nil, false
+	}
+	method, err := c.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, false
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, false
+	}
+
+	switch method.Name {
+	case "recordResolution", "recordResolutionWithMetadata":
+		exchange, category, hash := args[0].(string), args[1].(string), args[2].([32]byte)
+		if _, exists := c.byHash[hash]; exists {
+			return nil, false // The contract rejects a hash it already holds
+		}
+		key := crypto.Keccak256Hash([]byte(exchange), []byte(category))
+		a := mockAttestation{
+			evidenceHash:  hash,
+			previousHash:  c.latest[key],
+			timestamp:     blockTime(c.head),
+			blockNumber:   c.head,
+			exchange:      exchange,
+			issueCategory: category,
+			attestor:      sender,
+		}
+		id := uint64(len(c.attestations))
+		c.attestations = append(c.attestations, a)
+		c.byHash[hash] = id
+		c.latest[key] = hash
+
+		contract := common.HexToAddress(MockContract)
+		idTopic := common.BigToHash(new(big.Int).SetUint64(id))
+		recorded := c.abi.Events["ResolutionRecorded"]
+		eventData, err := recorded.Inputs.NonIndexed().Pack(category, a.evidenceHash, a.previousHash,
+			new(big.Int).SetUint64(a.timestamp), sender)
+		if err != nil {
+			return nil, false
+		}
+		logs := []*types.Log{{
+			Address: contract,
+			Topics:  []common.Hash{recorded.ID, idTopic, crypto.Keccak256Hash([]byte(exchange))},
+			Data:    eventData,
+		}}
+		if method.Name == "recordResolutionWithMetadata" {
+			metadata := c.abi.Events["ResolutionMetadata"]
+			eventData, err := metadata.Inputs.NonIndexed().Pack(args[3].(string))
+			if err != nil {
+				return nil, false
+			}
+			logs = append(logs, &types.Log{
+				Address: contract,
+				Topics:  []common.Hash{metadata.ID, idTopic, common.Hash(hash)},
+				Data:    eventData,
+			})
+		}
+		return logs, true
+	case "announceKeyRotation":
+		return nil, true
+	}
+	return nil, false
+}
+
+// filterLogs answers eth_getLogs over the mined blocks
+func (c *MockChain) filterLogs(params []json.RawMessage) (any, *rpcError) {
+	var filter struct {
+		FromBlock string        `json:"fromBlock"`
+		ToBlock   string        `json:"toBlock"`
+		Topics    []interface{} `json:"topics"`
+	}
+	if err := param(params, 0, &filter); err != nil {
+		return nil, err
+	}
+	from, to := c.blockNumber(filter.FromBlock), c.blockNumber(filter.ToBlock)
+	if filter.FromBlock == "" {
+		from = 0
+	}
+
+	var event common.Hash
+	if len(filter.Topics) > 0 {
+		if s, ok := filter.Topics[0].(string); ok {
+			event = common.HexToHash(s)
+		}
+	}
+	logs := []*types.Log{}
+	for _, log := range c.logs {
+		if log.BlockNumber < from || log.BlockNumber > to {
+			continue
+		}
+		if event != (common.Hash{}) && log.Topics[0] != event {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
@@ -0,0 +1,112 @@
+package apitest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// ============================================
+// FAKE RESOLUTION SERVICE
+// ============================================
+// The handlers take a *services.ResolutionService, so the fake is a real one with
+// no persistence and a fixed, seeded state: one issue per lifecycle stage, all
+// with fixed IDs and dates. Resolution IDs are random; Fixtures records them so
+// golden files can refer to them by name.
+
+// Fixture issue IDs seeded by NewFakeResolutionService
+const (
+	OpenIssueID     = "issue-withdrawals" // Active: Coinbase withdrawal delays
+	ResolvedIssueID = "issue-login"       // Resolved with a verified resolution, attested when there is a chain
+	ReviewIssueID   = "issue-support"     // Has a resolution waiting for review
+	ArchivedIssueID = "issue-fees"        // Archived
+	TenantIssueID   = "issue-acme-kyc"    // Belongs to TenantID
+)
+
+// FixtureTime is when the seeded issues were first detected; every other fixture date follows it
+var FixtureTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Fixtures are the generated IDs of the seeded state
+type Fixtures struct {
+	Resolution       string // Verified resolution of ResolvedIssueID
+	ReviewResolution string // needs_review resolution of ReviewIssueID
+	EvidenceHash     string // Evidence hash of Resolution
+	Attestation      *models.Attestation
+}
+
+// NewFakeResolutionService returns a resolution service seeded with the fixture
+// issues and resolutions. With a blockchain service the verified resolution is
+// attested on it; with nil, attesting and verifying fail.
+func NewFakeResolutionService(t testing.TB, blockchain *services.BlockchainService) (*services.ResolutionService, Fixtures) {
+	t.Helper()
+
+	rs := services.NewResolutionService(blockchain)
+	var fixtures Fixtures
+
+	seed := []models.Issue{
+		{ID: OpenIssueID, Exchange: "coinbase", Category: "withdrawal", Title: "Withdrawals stuck pending",
+			Description: "Withdrawals stay pending for several days", ComplaintCount: 42, Severity: "high"},
+		{ID: ResolvedIssueID, Exchange: "coinbase", Category: "login", Title: "Login loops after 2FA",
+			Description: "Users are sent back to the login page after entering a 2FA code", ComplaintCount: 30, Severity: "critical"},
+		{ID: ReviewIssueID, Exchange: "kraken", Category: "support", Title: "Support tickets unanswered",
+			Description: "Support tickets go unanswered for weeks", ComplaintCount: 18, Severity: "medium"},
+		{ID: ArchivedIssueID, Exchange: "kraken", Category: "fees", Title: "Instant buy spread",
+			Description: "Spread on instant buys is higher than advertised", ComplaintCount: 6, Severity: "low"},
+		{ID: TenantIssueID, Tenant: TenantID, Exchange: "coinbase", Category: "account", Title: "KYC reviews stalled",
+			Description: "Identity checks stay in review for weeks", ComplaintCount: 12, Severity: "medium"},
+	}
+	for i := range seed {
+		issue := seed[i]
+		issue.FirstDetected = FixtureTime
+		if _, err := rs.CreateIssue(&issue); err != nil {
+			t.Fatalf("failed to seed issue %s: %v", issue.ID, err)
+		}
+	}
+	if _, err := rs.ArchiveIssue(ArchivedIssueID, "apitest", "duplicate of a Coinbase fee issue"); err != nil {
+		t.Fatal(err)
+	}
+
+	resolution, err := rs.CreateResolution(context.Background(), ResolvedIssueID, fixtureEvidence(30, 3), "Fixed the 2FA redirect")
+	if err != nil {
+		t.Fatalf("failed to seed resolution: %v", err)
+	}
+	fixtures.Resolution = resolution.ID
+	if fixtures.EvidenceHash, err = services.EvidenceHash(&resolution.Evidence); err != nil {
+		t.Fatal(err)
+	}
+	if blockchain != nil {
+		if fixtures.Attestation, err = rs.AttestResolution(context.Background(), resolution.ID); err != nil {
+			t.Fatalf("failed to attest the seeded resolution: %v", err)
+		}
+	}
+
+	// A detected resolution waits for review instead of resolving its issue
+	review, err := rs.ProposeResolution(ReviewIssueID, fixtureEvidence(18, 4), "Hired more support staff")
+	if err != nil {
+		t.Fatalf("failed to seed review resolution: %v", err)
+	}
+	if review == nil {
+		t.Fatal("seeded review resolution doesn't meet the resolution criteria")
+	}
+	fixtures.ReviewResolution = review.ID
+
+	return rs, fixtures
+}
+
+// fixtureEvidence is a two-week measurement window ending 30 days after FixtureTime
+func fixtureEvidence(before, after int) *models.ResolutionEvidence {
+	end := FixtureTime.AddDate(0, 0, 30)
+	return &models.ResolutionEvidence{
+		ComplaintsBefore:   before,
+		ComplaintsAfter:    after,
+		PercentageDecrease: float64(before-after) / float64(before),
+		SentimentShift:     0.4,
+		SampleComplaints:   []string{"yt-fixture-1", "yt-fixture-2"},
+		DataSources:        []string{"youtube", "reddit"},
+		MeasurementStart:   end.AddDate(0, 0, -14),
+		MeasurementEnd:     end,
+	}
+}
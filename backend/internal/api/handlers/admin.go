@@ -0,0 +1,504 @@
+// API for operators to inspect and tune the scraping pipeline
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/audit"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/integrity"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/planner"
+	"github.com/tasnint/coinsights/internal/scrapesettings"
+	"github.com/tasnint/coinsights/internal/taxonomy"
+)
+
+// AdminHandler handles operator-facing endpoints
+type AdminHandler struct {
+	dataDir string
+
+	// Optional: complaint sampling and audit labels
+	data   *DataHandler
+	labels *audit.Store
+
+	// Optional: taxonomy editing; reanalyzes through reload after each change
+	categories *taxonomy.Store
+	reload     *DataHandler
+
+	// Optional: scraper settings presets
+	scraperSettings *scrapesettings.Store
+}
+
+// NewAdminHandler creates a new admin handler reading from dataDir
+func NewAdminHandler(dataDir string) *AdminHandler {
+	return &AdminHandler{
+		dataDir: dataDir,
+	}
+}
+
+// EnableAudits serves complaint samples from data and records audit labels to labels
+func (h *AdminHandler) EnableAudits(data *DataHandler, labels *audit.Store) {
+	h.data = data
+	h.labels = labels
+}
+
+// EnableTaxonomy serves category and keyword editing from categories, reloading data's
+// analysis after every change so it takes effect immediately
+func (h *AdminHandler) EnableTaxonomy(categories *taxonomy.Store, data *DataHandler) {
+	h.categories = categories
+	h.reload = data
+}
+
+// EnableScraperSettings serves the scraper settings presets from settings
+func (h *AdminHandler) EnableScraperSettings(settings *scrapesettings.Store) {
+	h.scraperSettings = settings
+}
+
+// ============================================
+// QUERY PERFORMANCE ENDPOINTS
+// ============================================
+
+// GetQueryStats handles GET /api/admin/queries/stats
+// Optional ?min_runs= controls how many runs a query needs before it can be suggested for pruning
+func (h *AdminHandler) GetQueryStats(w http.ResponseWriter, r *http.Request) {
+	minRuns := planner.DefaultPruneMinRuns
+	if v := r.URL.Query().Get("min_runs"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "min_runs must be a positive integer")
+			return
+		}
+		minRuns = n
+	}
+
+	history, err := planner.LoadHistory(filepath.Join(h.dataDir, planner.HistoryFile))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	stats := history.Stats()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"queries":           stats,
+		"count":             len(stats),
+		"prune_suggestions": history.PruneSuggestions(minRuns),
+	})
+}
+
+// ============================================
+// SCRAPER HEALTH ENDPOINTS
+// ============================================
+
+// GetScraperStatus handles GET /api/admin/scrapers/status
+// Optional ?stale_after= (Go duration, default 36h) sets how old a source's last success may be.
+// Also checks every recorded scrape and analysis file against its checksum.
+func (h *AdminHandler) GetScraperStatus(w http.ResponseWriter, r *http.Request) {
+	staleAfter := health.DefaultStaleAfter
+	if v := r.URL.Query().Get("stale_after"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			respondError(w, http.StatusBadRequest, "stale_after must be a positive duration, e.g. 24h")
+			return
+		}
+		staleAfter = d
+	}
+
+	store, err := health.NewStore(h.dataDir)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	sources := store.Statuses(time.Now(), staleAfter)
+	stale, blocked := 0, 0
+	for _, s := range sources {
+		if s.Stale {
+			stale++
+		}
+		if s.Blocked {
+			blocked++
+		}
+	}
+	problems, err := integrity.Check(h.dataDir)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"sources":   sources,
+		"count":     len(sources),
+		"stale":     stale,
+		"blocked":   blocked,
+		"integrity": problems, // Scrape and analysis files that no longer match their checksums
+	})
+}
+
+// ============================================
+// KEYWORD SUGGESTION ENDPOINTS
+// ============================================
+
+// GetKeywordSuggestions handles GET /api/admin/keywords/suggestions
+// Serves the review file produced by cmd/keywords
+func (h *AdminHandler) GetKeywordSuggestions(w http.ResponseWriter, r *http.Request) {
+	data, err := os.ReadFile(filepath.Join(h.dataDir, analyzer.KeywordSuggestionsFile))
+	if os.IsNotExist(err) {
+		respondError(w, http.StatusNotFound, "No keyword suggestions yet. Run: go run ./cmd/keywords")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var report analyzer.KeywordSuggestionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		respondError(w, http.StatusInternalServerError, "Invalid keyword suggestions file")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetKeywordEffectiveness handles GET /api/admin/keywords/effectiveness
+// How often each category keyword matches and, from audit labels, how often its
+// matches are miscategorized; keywords worth retiring come first
+func (h *AdminHandler) GetKeywordEffectiveness(w http.ResponseWriter, r *http.Request) {
+	if h.data == nil || h.labels == nil {
+		respondError(w, http.StatusServiceUnavailable, "Complaint audits are not enabled")
+		return
+	}
+
+	correct := make(map[string]bool)
+	for _, label := range h.labels.Labels() {
+		correct[label.ComplaintID] = label.Correct
+	}
+	respondJSON(w, http.StatusOK, analyzer.KeywordEffectiveness(h.data.Complaints(), correct))
+}
+
+// ============================================
+// AUDIT SAMPLING ENDPOINTS
+// ============================================
+
+// RecordLabelsRequest is the request body for POST /api/admin/sample/labels
+type RecordLabelsRequest struct {
+	Labels []audit.Label `json:"labels"`
+}
+
+// GetSample handles GET /api/admin/sample
+// ?category= narrows the draw, ?n= sets its size, and ?seed= reproduces an earlier draw
+func (h *AdminHandler) GetSample(w http.ResponseWriter, r *http.Request) {
+	if h.data == nil {
+		respondError(w, http.StatusServiceUnavailable, "Complaint audits are not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	category := query.Get("category")
+	if category != "" {
+		category = models.NormalizeCategory(category)
+	}
+
+	n := audit.DefaultSampleSize
+	if v := query.Get("n"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > audit.MaxSampleSize {
+			respondError(w, http.StatusBadRequest, "n must be an integer between 1 and "+strconv.Itoa(audit.MaxSampleSize))
+			return
+		}
+		n = parsed
+	}
+
+	seed := time.Now().UnixNano()
+	if v := query.Get("seed"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "seed must be an integer")
+			return
+		}
+		seed = parsed
+	}
+
+	respondJSON(w, http.StatusOK, audit.Draw(h.data.Complaints(), category, n, seed))
+}
+
+// RecordLabels handles POST /api/admin/sample/labels
+func (h *AdminHandler) RecordLabels(w http.ResponseWriter, r *http.Request) {
+	if h.labels == nil {
+		respondError(w, http.StatusServiceUnavailable, "Complaint audits are not enabled")
+		return
+	}
+
+	var req RecordLabelsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.Labels) == 0 {
+		respondError(w, http.StatusBadRequest, "labels is required")
+		return
+	}
+	for i := range req.Labels {
+		label := &req.Labels[i]
+		if label.ComplaintID == "" || label.Category == "" || label.LabeledBy == "" {
+			respondError(w, http.StatusBadRequest, "complaint_id, category and labeled_by are required on every label")
+			return
+		}
+		label.Category = models.NormalizeCategory(label.Category)
+		if label.CorrectedCategory != "" {
+			label.CorrectedCategory = models.NormalizeCategory(label.CorrectedCategory)
+		}
+	}
+
+	if err := h.labels.Record(req.Labels); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"recorded":  len(req.Labels),
+		"precision": h.labels.Precision(),
+	})
+}
+
+// ListLabels handles GET /api/admin/sample/labels
+// Returns every audit label with the per-category precision they imply
+func (h *AdminHandler) ListLabels(w http.ResponseWriter, r *http.Request) {
+	if h.labels == nil {
+		respondError(w, http.StatusServiceUnavailable, "Complaint audits are not enabled")
+		return
+	}
+
+	labels := h.labels.Labels()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"labels":    labels,
+		"count":     len(labels),
+		"precision": h.labels.Precision(),
+	})
+}
+
+// ============================================
+// TAXONOMY ENDPOINTS
+// ============================================
+
+// AddKeywordsRequest is the request body for POST /api/admin/categories/{id}/keywords
+type AddKeywordsRequest struct {
+	Keywords []string `json:"keywords"`
+}
+
+// ListTaxonomy handles GET /api/admin/categories
+// Every category with its aliases and the keywords the analyzer matches it by
+func (h *AdminHandler) ListTaxonomy(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	categories := h.categories.List()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"categories": categories,
+		"count":      len(categories),
+	})
+}
+
+// GetTaxonomyCategory handles GET /api/admin/categories/{id}
+func (h *AdminHandler) GetTaxonomyCategory(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	category, err := h.categories.Get(r.PathValue("id"))
+	if err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	respondJSON(w, http.StatusOK, category)
+}
+
+// CreateTaxonomyCategory handles POST /api/admin/categories
+func (h *AdminHandler) CreateTaxonomyCategory(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	var req taxonomy.Category
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	category, err := h.categories.Create(req)
+	if err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	h.respondTaxonomyChange(w, http.StatusCreated, category)
+}
+
+// UpdateTaxonomyCategory handles PUT /api/admin/categories/{id}
+// Replaces the category's name, severity, aliases and keywords
+func (h *AdminHandler) UpdateTaxonomyCategory(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	var req taxonomy.Category
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	id := r.PathValue("id")
+	if req.ID != "" && req.ID != id {
+		respondError(w, http.StatusBadRequest, "Category IDs can't be changed; create a new category instead")
+		return
+	}
+
+	category, err := h.categories.Update(id, req)
+	if err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	h.respondTaxonomyChange(w, http.StatusOK, category)
+}
+
+// DeleteTaxonomyCategory handles DELETE /api/admin/categories/{id}
+func (h *AdminHandler) DeleteTaxonomyCategory(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.categories.Delete(id); err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	h.respondTaxonomyChange(w, http.StatusOK, map[string]interface{}{
+		"deleted": id,
+	})
+}
+
+// AddTaxonomyKeywords handles POST /api/admin/categories/{id}/keywords
+func (h *AdminHandler) AddTaxonomyKeywords(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	var req AddKeywordsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if len(req.Keywords) == 0 {
+		respondError(w, http.StatusBadRequest, "keywords is required")
+		return
+	}
+
+	category, err := h.categories.AddKeywords(r.PathValue("id"), req.Keywords)
+	if err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	h.respondTaxonomyChange(w, http.StatusOK, category)
+}
+
+// RemoveTaxonomyKeyword handles DELETE /api/admin/categories/{id}/keywords/{keyword}
+func (h *AdminHandler) RemoveTaxonomyKeyword(w http.ResponseWriter, r *http.Request) {
+	if h.categories == nil {
+		respondError(w, http.StatusServiceUnavailable, "Taxonomy editing is not enabled")
+		return
+	}
+
+	category, err := h.categories.RemoveKeyword(r.PathValue("id"), r.PathValue("keyword"))
+	if err != nil {
+		respondTaxonomyError(w, err)
+		return
+	}
+	h.respondTaxonomyChange(w, http.StatusOK, category)
+}
+
+// respondTaxonomyChange reanalyzes the loaded data with the edited taxonomy, then responds.
+// The edit is saved either way; a failed reload is reported so the operator can retry.
+func (h *AdminHandler) respondTaxonomyChange(w http.ResponseWriter, status int, body interface{}) {
+	if h.reload != nil {
+		if err := h.reload.Reload(); err != nil {
+			respondError(w, http.StatusInternalServerError, "Taxonomy saved, but reanalyzing failed: "+err.Error())
+			return
+		}
+	}
+	respondJSON(w, status, body)
+}
+
+// respondTaxonomyError maps a taxonomy store error to a response
+func respondTaxonomyError(w http.ResponseWriter, err error) {
+	var invalid *taxonomy.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"success": false,
+			"error":   invalid.Error(),
+			"errors":  invalid.Errors,
+		})
+	case errors.Is(err, taxonomy.ErrNotFound), errors.Is(err, taxonomy.ErrKeywordNotFound):
+		respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, taxonomy.ErrExists), errors.Is(err, taxonomy.ErrProtected):
+		respondError(w, http.StatusConflict, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ============================================
+// SCRAPER SETTINGS ENDPOINTS
+// ============================================
+
+// GetScraperSettings handles GET /api/admin/scraper-settings
+// The active preset and its settings, and every built-in and custom preset with its estimated quota
+func (h *AdminHandler) GetScraperSettings(w http.ResponseWriter, r *http.Request) {
+	if h.scraperSettings == nil {
+		respondError(w, http.StatusServiceUnavailable, "Scraper settings are not enabled")
+		return
+	}
+	respondJSON(w, http.StatusOK, h.scraperSettings.State())
+}
+
+// UpdateScraperSettings handles PUT /api/admin/scraper-settings
+// Saves custom presets (null deletes one) and/or switches the active preset.
+// The scraper picks the change up on its next run.
+func (h *AdminHandler) UpdateScraperSettings(w http.ResponseWriter, r *http.Request) {
+	if h.scraperSettings == nil {
+		respondError(w, http.StatusServiceUnavailable, "Scraper settings are not enabled")
+		return
+	}
+
+	var req scrapesettings.Update
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Active == "" && len(req.Presets) == 0 {
+		respondError(w, http.StatusBadRequest, "active or presets is required")
+		return
+	}
+
+	state, err := h.scraperSettings.Apply(req)
+	var invalid *scrapesettings.ValidationError
+	if errors.As(err, &invalid) {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"success": false,
+			"error":   invalid.Error(),
+			"errors":  invalid.Errors,
+		})
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, state)
+}
@@ -0,0 +1,53 @@
+// API for exchange announcements and the resolutions they're linked to
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/announcements"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// AnnouncementHandler serves fetched exchange announcements and relinks resolutions to them
+type AnnouncementHandler struct {
+	store             *announcements.Store
+	resolutionService *services.ResolutionService
+}
+
+// NewAnnouncementHandler creates a new announcement handler. Pass the same store
+// given to rs.SetAnnouncementLinker.
+func NewAnnouncementHandler(store *announcements.Store, rs *services.ResolutionService) *AnnouncementHandler {
+	return &AnnouncementHandler{store: store, resolutionService: rs}
+}
+
+// ListAnnouncements handles GET /api/announcements
+// Query params: exchange (default "coinbase"), category (optional). Newest first.
+func (h *AnnouncementHandler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	exchange := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("exchange")))
+	if exchange == "" {
+		exchange = "coinbase"
+	}
+	category := strings.TrimSpace(r.URL.Query().Get("category"))
+	if category != "" {
+		category = models.NormalizeCategory(category)
+	}
+
+	list := h.store.List(exchange, category)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"announcements": list,
+		"count":         len(list),
+	})
+}
+
+// RelinkResolution handles POST /api/resolutions/{id}/announcements
+// Re-matches the resolution against the announcements fetched so far
+func (h *AnnouncementHandler) RelinkResolution(w http.ResponseWriter, r *http.Request) {
+	resolution, err := h.resolutionService.RelinkAnnouncements(r.PathValue("id"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, resolution)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tasnint/coinsights/internal/auth"
+	"github.com/tasnint/coinsights/internal/tenants"
+)
+
+// ============================================
+// ROUTE ROLES
+// ============================================
+// Once any API key or JWT secret is configured, every route needs a role: reads
+// need a reader (or nothing, unless API_AUTH_READS is set) and everything else a
+// writer. RouteRoles lists the routes that differ, keyed by the ServeMux pattern
+// the route was registered with, so a new route is protected by default.
+
+// RoutePublic marks a route in RouteRoles that needs no credentials
+const RoutePublic auth.Role = ""
+
+// RouteRoles are the per-route roles that differ from the method's default
+var RouteRoles = map[string]auth.Role{
+	// Sends a transaction paid for by the attestation wallet
+	"POST /api/attestations": auth.RoleAttestor,
+	// Compute or check a result without changing any state
+	"POST /api/attestations/verify":       auth.RoleReader,
+	"POST /api/attestations/verify/batch": auth.RoleReader,
+	"POST /api/blockchain/hash":           auth.RoleReader,
+	// Partners authenticate with their own ingest keys
+	"POST /api/ingest/complaints": RoutePublic,
+	// Publishing the key is its whole point
+	"GET /.well-known/coinsights-signing-key": RoutePublic,
+	"GET /api/openapi.json":                   RoutePublic,
+	// Lists subscriptions by owner, so needs a key even when reads are open
+	"GET /api/subscriptions": auth.RoleWriter,
+	// Operator endpoints expose tenants, settings and labelled samples; reads need
+	// a writer key too. Tenant keys are refused since these aren't TenantRoutes.
+	"GET /api/admin/queries/stats":          auth.RoleWriter,
+	"GET /api/admin/scrapers/status":        auth.RoleWriter,
+	"GET /api/admin/keywords/suggestions":   auth.RoleWriter,
+	"GET /api/admin/keywords/effectiveness": auth.RoleWriter,
+	"GET /api/admin/sample":                 auth.RoleWriter,
+	"GET /api/admin/sample/labels":          auth.RoleWriter,
+	"GET /api/admin/categories":             auth.RoleWriter,
+	"GET /api/admin/categories/{id}":        auth.RoleWriter,
+	"GET /api/admin/scraper-settings":       auth.RoleWriter,
+	"GET /api/admin/tenants":                auth.RoleWriter,
+}
+
+// AuthPolicy decides what each route requires of its callers
+type AuthPolicy struct {
+	Roles        map[string]auth.Role // Per-route roles; see RouteRoles
+	TenantRoutes map[string]bool      // Routes tenant keys may use; see TenantRoutes
+	Tenants      *tenants.Store       // Optional: keys of tenants not in the store are refused
+}
+
+// DefaultAuthPolicy uses RouteRoles and TenantRoutes
+func DefaultAuthPolicy(store *tenants.Store) AuthPolicy {
+	return AuthPolicy{Roles: RouteRoles, TenantRoutes: TenantRoutes, Tenants: store}
+}
+
+// WithAuth requires each request under next to carry credentials granting its
+// route's role from policy, and confines tenant keys to the tenant routes. With a
+// nil authenticator every request passes. Missing or invalid credentials answer
+// 401; a role too low, or a route closed to tenant keys, answers 403.
+func WithAuth(mux *http.ServeMux, next http.Handler, authn *auth.Authenticator, policy AuthPolicy) http.Handler {
+	if authn == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required := auth.RoleWriter
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			required = auth.RoleReader
+			if authn.ReadsOpen() {
+				required = RoutePublic
+			}
+		}
+		_, pattern := mux.Handler(r)
+		if role, ok := policy.Roles[pattern]; ok {
+			if role == RoutePublic {
+				// Always open, and its credentials (e.g. a partner's ingest key) aren't API keys
+				next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{})))
+				return
+			}
+			required = role
+			if required == auth.RoleReader && authn.ReadsOpen() {
+				required = RoutePublic
+			}
+		}
+
+		principal, err := authn.Authenticate(r)
+		if required == RoutePublic && errors.Is(err, auth.ErrNoCredentials) {
+			// Anonymous: handlers show only the deployment's own records
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), auth.Principal{})))
+			return
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="coinsights"`)
+			message := err.Error()
+			if errors.Is(err, auth.ErrNoCredentials) {
+				message = "Missing API key: send Authorization: Bearer <key> or X-API-Key"
+			}
+			respondError(w, http.StatusUnauthorized, message)
+			return
+		}
+		if !principal.Role.Allows(required) {
+			respondError(w, http.StatusForbidden, fmt.Sprintf("%s role required; %q is a %s", required, principal.Name, principal.Role))
+			return
+		}
+		if principal.Tenant != "" {
+			if policy.Tenants != nil {
+				if _, ok := policy.Tenants.Get(principal.Tenant); !ok {
+					respondError(w, http.StatusForbidden, fmt.Sprintf("tenant %s does not exist", principal.Tenant))
+					return
+				}
+			}
+			if !policy.TenantRoutes[pattern] {
+				respondError(w, http.StatusForbidden, "not available to tenant keys")
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
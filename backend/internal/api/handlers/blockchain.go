@@ -3,17 +3,27 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tasnint/coinsights/internal/models"
 	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/tenants"
 )
 
 // BlockchainHandler handles blockchain-related API endpoints
 type BlockchainHandler struct {
 	resolutionService *services.ResolutionService
 	blockchainService *services.BlockchainService
+	attestationQueue  *services.AttestationQueue   // nil = attest immediately
+	indexer           *services.AttestationIndexer // nil = GET /api/attestations answers 503
+	tenants           *tenants.Store               // nil = issues can't be opened for a tenant
 }
 
 // NewBlockchainHandler creates a new blockchain handler
@@ -27,24 +37,94 @@ func NewBlockchainHandler(
 	}
 }
 
+// SetAttestationQueue routes POST /api/attestations through a batching queue
+func (h *BlockchainHandler) SetAttestationQueue(queue *services.AttestationQueue) {
+	h.attestationQueue = queue
+}
+
+// SetAttestationIndexer serves GET /api/attestations from an index of the contract's events
+func (h *BlockchainHandler) SetAttestationIndexer(indexer *services.AttestationIndexer) {
+	h.indexer = indexer
+}
+
+// EnableTenants lets issues be opened for the tenants in store, each confined to
+// its tenant's exchanges and checked against its criteria
+func (h *BlockchainHandler) EnableTenants(store *tenants.Store) {
+	h.tenants = store
+}
+
 // ============================================
 // ISSUE ENDPOINTS
 // ============================================
 
 // CreateIssue handles POST /api/issues
+// An open issue for the same exchange and category with a similar title is a duplicate:
+// the request fails with 409 and the existing issue unless ?merge=true folds the new
+// issue into it (200) or ?force=true creates it anyway. A tenant key's issues belong
+// to its tenant; an operator key may name one in "tenant".
 func (h *BlockchainHandler) CreateIssue(w http.ResponseWriter, r *http.Request) {
 	var issue models.Issue
-	if err := json.NewDecoder(r.Body).Decode(&issue); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &issue) {
+		return
+	}
+	if scope, all := requestScope(r); !all {
+		issue.Tenant = scope
+		if issue.ID != "" {
+			if _, err := h.resolutionService.GetIssue(issue.ID); err == nil {
+				respondError(w, http.StatusConflict, "issue ID already in use: "+issue.ID)
+				return
+			}
+		}
+	}
+	if issue.Tenant != "" {
+		if h.tenants == nil {
+			respondError(w, http.StatusServiceUnavailable, "Tenants not configured")
+			return
+		}
+		tenant, ok := h.tenants.Get(issue.Tenant)
+		if !ok {
+			respondError(w, http.StatusBadRequest, "tenant not found: "+issue.Tenant)
+			return
+		}
+		if !tenant.WatchesExchange(issue.Exchange) {
+			respondError(w, http.StatusForbidden, fmt.Sprintf("tenant %s does not watch %s", tenant.ID, issue.Exchange))
+			return
+		}
+	}
+
+	policy := services.DuplicateReject
+	force := r.URL.Query().Get("force") == "true"
+	merge := r.URL.Query().Get("merge") == "true"
+	switch {
+	case force && merge:
+		respondError(w, http.StatusBadRequest, "force and merge can't both be set")
 		return
+	case force:
+		policy = services.DuplicateForce
+	case merge:
+		policy = services.DuplicateMerge
 	}
 
-	created, err := h.resolutionService.CreateIssue(&issue)
+	created, merged, err := h.resolutionService.CreateIssueWithPolicy(&issue, policy)
+	var duplicate *services.DuplicateIssueError
+	if errors.As(err, &duplicate) {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"success":    false,
+			"error":      duplicate.Error() + "; retry with ?merge=true to add to it or ?force=true to create anyway",
+			"existing":   duplicate.Existing,
+			"similarity": duplicate.Similarity,
+		})
+		return
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if merged {
+		respondJSON(w, http.StatusOK, created)
+		return
+	}
 	respondJSON(w, http.StatusCreated, created)
 }
 
@@ -56,23 +136,121 @@ func (h *BlockchainHandler) GetIssue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.issueVisible(w, r, id) {
+		return
+	}
 	issue, err := h.resolutionService.GetIssue(id)
 	if err != nil {
 		respondError(w, http.StatusNotFound, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, issue)
+	respondJSON(w, http.StatusOK, h.resolutionService.WithSLA(issue, time.Now()))
 }
 
 // ListIssues handles GET /api/issues
+// Optional ?archived=include|only shows soft-deleted issues (hidden by default),
+// and ?overdue=true keeps only open issues past their severity SLA. The shared list
+// parameters filter, sort (count or first_detected) and page the rest.
 func (h *BlockchainHandler) ListIssues(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	issues := h.resolutionService.ListIssues(status)
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"issues": issues,
-		"count":  len(issues),
-	})
+	query, ok := parseListQuery(w, r, issueList)
+	if !ok {
+		return
+	}
+	archived, err := services.ParseArchiveFilter(r.URL.Query().Get("archived"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	overdueOnly := false
+	if v := r.URL.Query().Get("overdue"); v != "" {
+		if overdueOnly, err = strconv.ParseBool(v); err != nil {
+			respondError(w, http.StatusBadRequest, "overdue must be true or false")
+			return
+		}
+	}
+
+	now := time.Now()
+	issues := []models.Issue{}
+	for _, issue := range h.resolutionService.ListIssuesFiltered("", archived) {
+		if !visibleTo(r, issue.Tenant) {
+			continue
+		}
+		view := h.resolutionService.WithSLA(issue, now)
+		if overdueOnly && (view.SLA == nil || !view.SLA.Overdue) {
+			continue
+		}
+		issues = append(issues, view)
+	}
+	page, total := issueList.apply(issues, query)
+	respondJSON(w, http.StatusOK, listResponse("issues", page, total, query))
+}
+
+// GetIssueProjection handles GET /api/issues/{id}/projection
+// The issue's complaint velocity and when, at that rate, it reaches its critical count
+func (h *BlockchainHandler) GetIssueProjection(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !h.issueVisible(w, r, id) {
+		return
+	}
+	projection, err := h.resolutionService.ProjectVelocity(id)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, projection)
+}
+
+// ArchiveIssueRequest is the request body for archiving an issue
+type ArchiveIssueRequest struct {
+	ArchivedBy string `json:"archived_by"`
+	Reason     string `json:"reason"`
+}
+
+// ArchiveIssue handles POST /api/issues/{id}/archive
+func (h *BlockchainHandler) ArchiveIssue(w http.ResponseWriter, r *http.Request) {
+	if !h.issueVisible(w, r, r.PathValue("id")) {
+		return
+	}
+	var req ArchiveIssueRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.ArchivedBy == "" {
+		respondError(w, http.StatusBadRequest, "archived_by is required")
+		return
+	}
+
+	issue, err := h.resolutionService.ArchiveIssue(r.PathValue("id"), req.ArchivedBy, req.Reason)
+	if err != nil {
+		respondError(w, archiveStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, issue)
+}
+
+// UnarchiveIssue handles POST /api/issues/{id}/unarchive
+func (h *BlockchainHandler) UnarchiveIssue(w http.ResponseWriter, r *http.Request) {
+	if !h.issueVisible(w, r, r.PathValue("id")) {
+		return
+	}
+	issue, err := h.resolutionService.UnarchiveIssue(r.PathValue("id"))
+	if err != nil {
+		respondError(w, archiveStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, issue)
+}
+
+// archiveStatus is the status for an archive or unarchive error: 404 for an unknown
+// issue, 400 for one already in the requested state
+func archiveStatus(err error) int {
+	if errors.Is(err, services.ErrIssueNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
 }
 
 // ============================================
@@ -81,16 +259,25 @@ func (h *BlockchainHandler) ListIssues(w http.ResponseWriter, r *http.Request) {
 
 // CreateResolutionRequest is the request body for creating a resolution
 type CreateResolutionRequest struct {
-	IssueID  string                    `json:"issue_id"`
-	Summary  string                    `json:"summary"`
-	Evidence models.ResolutionEvidence `json:"evidence"`
+	IssueID     string                    `json:"issue_id"`
+	Summary     string                    `json:"summary"`
+	Evidence    models.ResolutionEvidence `json:"evidence"`
+	MetadataURI string                    `json:"metadata_uri,omitempty"` // Optional: where the evidence is published, e.g. "ipfs://<cid>"
 }
 
 // CreateResolution handles POST /api/resolutions
 func (h *BlockchainHandler) CreateResolution(w http.ResponseWriter, r *http.Request) {
 	var req CreateResolutionRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.MetadataURI != "" {
+		if err := services.ValidateMetadataURI(req.MetadataURI); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if !h.issueVisible(w, r, req.IssueID) {
 		return
 	}
 
@@ -100,14 +287,75 @@ func (h *BlockchainHandler) CreateResolution(w http.ResponseWriter, r *http.Requ
 		&req.Evidence,
 		req.Summary,
 	)
+	var invalid *services.EvidenceValidationError
+	if errors.As(err, &invalid) {
+		respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"success": false,
+			"error":   invalid.Error(),
+			"errors":  invalid.Errors,
+		})
+		return
+	}
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if req.MetadataURI != "" {
+		if resolution, err = h.resolutionService.SetMetadataURI(resolution.ID, req.MetadataURI); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
 	respondJSON(w, http.StatusCreated, resolution)
 }
 
+// ReviewResolutionRequest is the request body for reviewing a detected resolution
+type ReviewResolutionRequest struct {
+	Approve    bool   `json:"approve"`
+	ReviewedBy string `json:"reviewed_by"`
+	Note       string `json:"note,omitempty"`
+}
+
+// ReviewResolution handles POST /api/resolutions/{id}/review
+func (h *BlockchainHandler) ReviewResolution(w http.ResponseWriter, r *http.Request) {
+	var req ReviewResolutionRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.ReviewedBy == "" {
+		respondError(w, http.StatusBadRequest, "reviewed_by is required")
+		return
+	}
+	if !h.resolutionVisible(w, r, r.PathValue("id")) {
+		return
+	}
+
+	resolution, err := h.resolutionService.ReviewResolution(r.PathValue("id"), req.Approve, req.ReviewedBy, req.Note)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resolution)
+}
+
+// GetPreflight handles GET /api/resolutions/{id}/preflight
+// Always 200 for a known resolution; "ready" says whether attesting would go through
+func (h *BlockchainHandler) GetPreflight(w http.ResponseWriter, r *http.Request) {
+	if !h.resolutionVisible(w, r, r.PathValue("id")) {
+		return
+	}
+	preflight, err := h.resolutionService.Preflight(r.Context(), r.PathValue("id"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preflight)
+}
+
 // GetResolution handles GET /api/resolutions/{id}
 func (h *BlockchainHandler) GetResolution(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -116,6 +364,9 @@ func (h *BlockchainHandler) GetResolution(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !h.resolutionVisible(w, r, id) {
+		return
+	}
 	resolution, err := h.resolutionService.GetResolution(id)
 	if err != nil {
 		respondError(w, http.StatusNotFound, err.Error())
@@ -125,16 +376,73 @@ func (h *BlockchainHandler) GetResolution(w http.ResponseWriter, r *http.Request
 	respondJSON(w, http.StatusOK, resolution)
 }
 
-// ListResolutions handles GET /api/resolutions
+// ListResolutions handles GET /api/resolutions, filtered, sorted (created_at or
+// confidence) and paged by the shared list parameters
 func (h *BlockchainHandler) ListResolutions(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	resolutions := h.resolutionService.ListResolutions(status)
+	query, ok := parseListQuery(w, r, resolutionList)
+	if !ok {
+		return
+	}
+	resolutions := []*models.Resolution{}
+	for _, resolution := range h.resolutionService.ListResolutions("") {
+		if visibleTo(r, resolution.Tenant) {
+			resolutions = append(resolutions, resolution)
+		}
+	}
+	page, total := resolutionList.apply(resolutions, query)
+	respondJSON(w, http.StatusOK, listResponse("resolutions", page, total, query))
+}
+
+// ListMethodologies handles GET /api/resolutions/methodologies
+func (h *BlockchainHandler) ListMethodologies(w http.ResponseWriter, r *http.Request) {
+	templates := services.MethodologyTemplates()
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"resolutions": resolutions,
-		"count":       len(resolutions),
+		"methodologies": templates,
+		"count":         len(templates),
 	})
 }
 
+// GetCriteria handles GET /api/resolutions/criteria
+// The auto-verification criteria, source weights and per-exchange overrides in use;
+// for a tenant key, its tenant's
+func (h *BlockchainHandler) GetCriteria(w http.ResponseWriter, r *http.Request) {
+	scope, _ := requestScope(r)
+	respondJSON(w, http.StatusOK, h.resolutionService.TenantCriteria(scope))
+}
+
+// UpdateCriteria handles PUT /api/resolutions/criteria
+// Replaces the criteria. Omitted thresholds take their defaults; omitted source
+// weights and exchange overrides are cleared. Existing resolutions keep their status.
+// A tenant key replaces its tenant's criteria, leaving everyone else's alone.
+func (h *BlockchainHandler) UpdateCriteria(w http.ResponseWriter, r *http.Request) {
+	criteria := models.DefaultResolutionCriteria()
+	criteria.SourceWeights = nil
+	if !decodeBody(w, r, &criteria) {
+		return
+	}
+	if err := services.ValidateCriteria(criteria); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if scope, _ := requestScope(r); scope != "" {
+		if h.tenants == nil {
+			respondError(w, http.StatusServiceUnavailable, "Tenants not configured")
+			return
+		}
+		if err := h.tenants.SetCriteria(scope, criteria); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.resolutionService.SetTenantCriteria(scope, &criteria)
+		respondJSON(w, http.StatusOK, h.resolutionService.TenantCriteria(scope))
+		return
+	}
+
+	h.resolutionService.SetCriteria(criteria)
+	respondJSON(w, http.StatusOK, h.resolutionService.Criteria())
+}
+
 // ============================================
 // ATTESTATION ENDPOINTS
 // ============================================
@@ -142,14 +450,29 @@ func (h *BlockchainHandler) ListResolutions(w http.ResponseWriter, r *http.Reque
 // AttestResolution handles POST /api/attestations
 func (h *BlockchainHandler) AttestResolution(w http.ResponseWriter, r *http.Request) {
 	var req models.AttestationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !h.resolutionVisible(w, r, req.ResolutionID) {
+		return
+	}
+
+	if h.attestationQueue != nil {
+		if _, scheduledAt, err := h.attestationQueue.Enqueue(req.ResolutionID); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+		} else {
+			respondJSON(w, http.StatusAccepted, models.AttestationResponse{
+				Success:     true,
+				Queued:      true,
+				ScheduledAt: &scheduledAt,
+			})
+		}
 		return
 	}
 
 	attestation, err := h.resolutionService.AttestResolution(r.Context(), req.ResolutionID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, errorStatus(err), err.Error())
 		return
 	}
 
@@ -162,8 +485,7 @@ func (h *BlockchainHandler) AttestResolution(w http.ResponseWriter, r *http.Requ
 // VerifyAttestation handles POST /api/attestations/verify
 func (h *BlockchainHandler) VerifyAttestation(w http.ResponseWriter, r *http.Request) {
 	var req models.VerificationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
@@ -173,6 +495,9 @@ func (h *BlockchainHandler) VerifyAttestation(w http.ResponseWriter, r *http.Req
 	if req.EvidenceHash != "" {
 		response, err = h.resolutionService.VerifyByHash(r.Context(), req.EvidenceHash)
 	} else if req.ResolutionID != "" {
+		if !h.resolutionVisible(w, r, req.ResolutionID) {
+			return
+		}
 		response, err = h.resolutionService.VerifyResolution(r.Context(), req.ResolutionID)
 	} else {
 		respondError(w, http.StatusBadRequest, "Either evidence_hash or resolution_id required")
@@ -180,13 +505,122 @@ func (h *BlockchainHandler) VerifyAttestation(w http.ResponseWriter, r *http.Req
 	}
 
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, errorStatus(err), err.Error())
 		return
 	}
 
 	respondJSON(w, http.StatusOK, response)
 }
 
+// VerifyAttestationBatch handles POST /api/attestations/verify/batch
+// Verifies up to services.MaxBatchVerification evidence hashes and resolution IDs concurrently.
+// Items that can't be checked report an error without failing the batch.
+func (h *BlockchainHandler) VerifyAttestationBatch(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchVerificationRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	total := len(req.EvidenceHashes) + len(req.ResolutionIDs)
+	if total == 0 {
+		respondError(w, http.StatusBadRequest, "evidence_hashes or resolution_ids required")
+		return
+	}
+	if total > services.MaxBatchVerification {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("At most %d items can be verified in one batch", services.MaxBatchVerification))
+		return
+	}
+	if h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured")
+		return
+	}
+
+	results, err := h.resolutionService.VerifyBatch(r.Context(), req)
+	if err != nil {
+		respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	verified, failed := 0, 0
+	for _, result := range results {
+		if result.Verified {
+			verified++
+		}
+		if result.Error != "" {
+			failed++
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"results":  results,
+		"count":    len(results),
+		"verified": verified,
+		"errors":   failed,
+	})
+}
+
+// GetAttestationQueue handles GET /api/attestations/queue
+// Lists the attestations waiting for the next batch and those given up on ("failed"),
+// which POST /api/attestations queues again.
+func (h *BlockchainHandler) GetAttestationQueue(w http.ResponseWriter, r *http.Request) {
+	if h.attestationQueue == nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"enabled": false,
+			"pending": []services.QueuedAttestation{},
+			"count":   0,
+			"failed":  []services.QueuedAttestation{},
+		})
+		return
+	}
+
+	visible := func(items []services.QueuedAttestation) []services.QueuedAttestation {
+		kept := make([]services.QueuedAttestation, 0, len(items))
+		for _, item := range items {
+			if resolution, err := h.resolutionService.GetResolution(item.ResolutionID); err == nil && !visibleTo(r, resolution.Tenant) {
+				continue
+			}
+			kept = append(kept, item)
+		}
+		return kept
+	}
+	queued, nextRun := h.attestationQueue.Pending()
+	pending := visible(queued)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled":  true,
+		"pending":  pending,
+		"count":    len(pending),
+		"failed":   visible(h.attestationQueue.Failed()),
+		"next_run": nextRun,
+	})
+}
+
+// ListAttestations handles GET /api/attestations
+// Every attestation recorded on-chain, including ones this instance didn't create,
+// filtered, sorted (id or block_timestamp, newest first) and paged by the shared
+// list parameters. "indexer" reports how far the index has caught up. Attestations of
+// another tenant's resolutions are left out.
+func (h *BlockchainHandler) ListAttestations(w http.ResponseWriter, r *http.Request) {
+	if h.indexer == nil {
+		respondError(w, http.StatusServiceUnavailable, "Attestation indexer not configured")
+		return
+	}
+	query, ok := parseListQuery(w, r, attestationList)
+	if !ok {
+		return
+	}
+	hidden := make(map[string]bool) // Evidence hashes of resolutions the caller may not see
+	for _, resolution := range h.resolutionService.AttestedResolutions() {
+		if !visibleTo(r, resolution.Tenant) {
+			hidden[strings.ToLower(resolution.Attestation.EvidenceHash)] = true
+		}
+	}
+	attestations := slices.DeleteFunc(h.indexer.List(), func(a models.Attestation) bool {
+		return hidden[strings.ToLower(a.EvidenceHash)]
+	})
+	page, total := attestationList.apply(attestations, query)
+	body := listResponse("attestations", page, total, query)
+	body["indexer"] = h.indexer.Status()
+	respondJSON(w, http.StatusOK, body)
+}
+
 // GetAttestationByResolution handles GET /api/resolutions/{id}/attestation
 func (h *BlockchainHandler) GetAttestationByResolution(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -194,6 +628,9 @@ func (h *BlockchainHandler) GetAttestationByResolution(w http.ResponseWriter, r
 		respondError(w, http.StatusBadRequest, "Resolution ID required")
 		return
 	}
+	if !h.resolutionVisible(w, r, id) {
+		return
+	}
 
 	resolution, err := h.resolutionService.GetResolution(id)
 	if err != nil {
@@ -206,7 +643,13 @@ func (h *BlockchainHandler) GetAttestationByResolution(w http.ResponseWriter, r
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resolution.Attestation)
+	// Explorer failures are not fatal - the stored attestation is still valid
+	attestation, err := h.resolutionService.RefreshAttestation(r.Context(), id)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+
+	respondJSON(w, http.StatusOK, attestation)
 }
 
 // ============================================
@@ -228,12 +671,129 @@ func (h *BlockchainHandler) GetChainInfo(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetAttestors handles GET /api/blockchain/attestors
+// Lists every attestor key with the blocks it was valid for, and the dual-signed rotations between them
+func (h *BlockchainHandler) GetAttestors(w http.ResponseWriter, r *http.Request) {
+	if h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured")
+		return
+	}
+	registry := h.blockchainService.AttestorRegistry()
+	if registry == nil {
+		respondError(w, http.StatusNotFound, "Attestor key registry not configured")
+		return
+	}
+
+	keys := registry.Keys()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"current":   h.blockchainService.GetWalletAddress(),
+		"keys":      keys,
+		"count":     len(keys),
+		"rotations": registry.Rotations(),
+	})
+}
+
+// GetChainAttestation handles GET /api/chain/attestations/{id}
+// Reads the attestation straight from the contract, whatever the local store holds
+func (h *BlockchainHandler) GetChainAttestation(w http.ResponseWriter, r *http.Request) {
+	if h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured")
+		return
+	}
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Attestation ID must be a non-negative integer")
+		return
+	}
+
+	attestation, err := h.blockchainService.ChainAttestation(r.Context(), id)
+	if errors.Is(err, services.ErrAttestationNotFound) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, attestation)
+}
+
+// GetChainCount handles GET /api/chain/count
+// Attestations recorded on the contract, whatever the local store holds
+func (h *BlockchainHandler) GetChainCount(w http.ResponseWriter, r *http.Request) {
+	if h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured")
+		return
+	}
+
+	count, err := h.blockchainService.ChainAttestationCount(r.Context())
+	if err != nil {
+		respondError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	chain := h.blockchainService.GetChainInfo()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"count":            count,
+		"chain_id":         chain.ChainID,
+		"contract_address": chain.ContractAddress,
+	})
+}
+
 // GetStats handles GET /api/blockchain/stats
+// Counts cover the issues and resolutions the caller may see.
 func (h *BlockchainHandler) GetStats(w http.ResponseWriter, r *http.Request) {
-	stats := h.resolutionService.GetStats()
+	stats := h.resolutionService.GetStats(r.Context(), tenantFilter(r))
 	respondJSON(w, http.StatusOK, stats)
 }
 
+// MaxCostEstimateCount caps ?count= on the cost estimator
+const MaxCostEstimateCount = 100000
+
+// GetCostEstimate handles GET /api/blockchain/cost-estimate
+// Optional ?count= (default 1) attestations. What attesting would cost at the current
+// base fee plus priority fee, in ETH and USD. The gas limit is an upper bound; typical
+// fees are lower. max_fee_per_gas_wei is the most a transaction sent now could pay.
+func (h *BlockchainHandler) GetCostEstimate(w http.ResponseWriter, r *http.Request) {
+	if h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured")
+		return
+	}
+	count := 1
+	if v := r.URL.Query().Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxCostEstimateCount {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", MaxCostEstimateCount))
+			return
+		}
+		count = n
+	}
+
+	gasLimit, fees, err := h.blockchainService.AttestationGasQuote(r.Context())
+	if err != nil {
+		respondError(w, errorStatus(err), err.Error())
+		return
+	}
+	gasPrice := fees.Expected()
+	perAttestation := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+	total := new(big.Int).Mul(perAttestation, big.NewInt(int64(count)))
+
+	chain := h.blockchainService.GetChainInfo()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"count":                        count,
+		"gas_limit":                    gasLimit,
+		"gas_price_wei":                gasPrice.String(),
+		"base_fee_wei":                 fees.BaseFee.String(),
+		"max_priority_fee_per_gas_wei": fees.GasTipCap.String(),
+		"max_fee_per_gas_wei":          fees.GasFeeCap.String(),
+		"per_attestation":              h.resolutionService.GasCost(r.Context(), perAttestation),
+		"total":                        h.resolutionService.GasCost(r.Context(), total),
+		"chain":                        chain.Name,
+		"is_testnet":                   chain.IsTestnet,
+	})
+}
+
 // HashEvidence handles POST /api/blockchain/hash
 // Useful for pre-computing hashes before attestation
 func (h *BlockchainHandler) HashEvidence(w http.ResponseWriter, r *http.Request) {
@@ -243,8 +803,7 @@ func (h *BlockchainHandler) HashEvidence(w http.ResponseWriter, r *http.Request)
 	}
 
 	var evidence models.ResolutionEvidence
-	if err := json.NewDecoder(r.Body).Decode(&evidence); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeBody(w, r, &evidence) {
 		return
 	}
 
@@ -276,7 +835,8 @@ func (h *BlockchainHandler) CreateDemoIssueAndResolve(w http.ResponseWriter, r *
 		Severity:       "high",
 	}
 
-	createdIssue, err := h.resolutionService.CreateIssue(issue)
+	// The demo runs repeatedly against the same store
+	createdIssue, _, err := h.resolutionService.CreateIssueWithPolicy(issue, services.DuplicateForce)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to create issue: "+err.Error())
 		return
@@ -284,15 +844,14 @@ func (h *BlockchainHandler) CreateDemoIssueAndResolve(w http.ResponseWriter, r *
 
 	// Step 2: Create resolution with evidence
 	evidence := &models.ResolutionEvidence{
-		ComplaintsBefore:    150,
-		ComplaintsAfter:     22,
-		PercentageDecrease:  0.85,
-		SentimentShift:      0.3,
-		SampleComplaints:    []string{"complaint_001", "complaint_002", "complaint_003"},
-		DataSources:         []string{"youtube", "google", "reddit"},
-		MeasurementStart:    time.Now().AddDate(0, 0, -14),
-		MeasurementEnd:      time.Now().AddDate(0, 0, -7),
-		AnalysisMethodology: "Complaint volume tracking with sentiment analysis over 7-day rolling window",
+		ComplaintsBefore:   150,
+		ComplaintsAfter:    22,
+		PercentageDecrease: 0.85,
+		SentimentShift:     0.3,
+		SampleComplaints:   []string{"complaint_001", "complaint_002", "complaint_003"},
+		DataSources:        []string{"youtube", "google", "reddit"},
+		MeasurementStart:   time.Now().AddDate(0, 0, -14),
+		MeasurementEnd:     time.Now().AddDate(0, 0, -7),
 	}
 
 	resolution, err := h.resolutionService.CreateResolution(
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// ============================================
+// REQUEST BODY LIMITS
+// ============================================
+// Handlers that take evidence accept arbitrary nested JSON, so every JSON body is
+// capped in size, rejected if it nests deeper than a limit (a megabyte of "[[[[" costs
+// the decoder far more than a megabyte), and decoded strictly: an unknown field is
+// an error rather than silently dropped, so a misspelled evidence field can't end up
+// attested without the data it was meant to carry.
+
+const (
+	// DefaultMaxBodyBytes caps request bodies on routes without their own limit
+	DefaultMaxBodyBytes = 1 << 20
+	// DefaultMaxJSONDepth is the deepest nesting of objects and arrays accepted
+	DefaultMaxJSONDepth = 32
+)
+
+// BodyLimits bounds what request bodies may cost to read
+type BodyLimits struct {
+	MaxBytes int64            // Cap on request bodies
+	MaxDepth int              // Deepest JSON nesting accepted
+	Routes   map[string]int64 // MaxBytes overrides, keyed by the ServeMux pattern the route was registered with
+}
+
+// DefaultBodyLimits returns the default limits; bulk ingest gets its own larger cap
+func DefaultBodyLimits() BodyLimits {
+	return BodyLimits{
+		MaxBytes: DefaultMaxBodyBytes,
+		MaxDepth: DefaultMaxJSONDepth,
+		Routes: map[string]int64{
+			"POST /api/ingest/complaints": MaxIngestBodyBytes,
+		},
+	}
+}
+
+// Largest is the largest body any route accepts
+func (l BodyLimits) Largest() int64 {
+	largest := l.MaxBytes
+	for _, n := range l.Routes {
+		largest = max(largest, n)
+	}
+	return largest
+}
+
+// BodyLimitsFromEnv applies the MAX_REQUEST_BODY_BYTES and MAX_JSON_DEPTH overrides to base
+func BodyLimitsFromEnv(base BodyLimits) (BodyLimits, error) {
+	if v := os.Getenv("MAX_REQUEST_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return base, fmt.Errorf("invalid MAX_REQUEST_BODY_BYTES %q: must be a positive integer", v)
+		}
+		base.MaxBytes = n
+	}
+	if v := os.Getenv("MAX_JSON_DEPTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return base, fmt.Errorf("invalid MAX_JSON_DEPTH %q: must be a positive integer", v)
+		}
+		base.MaxDepth = n
+	}
+	return base, nil
+}
+
+// bodyLimitKey carries a request's effective limits to decodeBody
+type bodyLimitKey struct{}
+
+type routeBodyLimit struct {
+	maxBytes int64
+	maxDepth int
+}
+
+// WithBodyLimits caps every request body under next at its route's limit from limits,
+// and passes the JSON depth limit on to the handlers that decode the body
+func WithBodyLimits(mux *http.ServeMux, next http.Handler, limits BodyLimits) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := routeBodyLimit{maxBytes: limits.MaxBytes, maxDepth: limits.MaxDepth}
+		if _, pattern := mux.Handler(r); pattern != "" {
+			if n, ok := limits.Routes[pattern]; ok {
+				limit.maxBytes = n
+			}
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit.maxBytes)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bodyLimitKey{}, limit)))
+	})
+}
+
+// decodeBody strictly decodes the JSON request body into v, writing a 413 or 400
+// and returning false if it is too large, too deeply nested or invalid
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	limit, ok := r.Context().Value(bodyLimitKey{}).(routeBodyLimit)
+	if !ok {
+		// Mounted without WithBodyLimits
+		limit = routeBodyLimit{maxBytes: DefaultMaxBodyBytes, maxDepth: DefaultMaxJSONDepth}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit.maxBytes))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body is larger than %d bytes", maxErr.Limit))
+			return false
+		}
+		respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return false
+	}
+	if exceedsDepth(body, limit.maxDepth) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("Request body nests deeper than %d levels", limit.maxDepth))
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return false
+	}
+	if dec.More() {
+		respondError(w, http.StatusBadRequest, "Invalid request body: unexpected data after the JSON value")
+		return false
+	}
+	return true
+}
+
+// exceedsDepth reports whether JSON nests objects and arrays more than maxDepth deep.
+// It only tracks brackets outside strings, so it is safe to run before decoding.
+func exceedsDepth(data []byte, maxDepth int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+			if depth > maxDepth {
+				return true
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+// Markdown issue briefs for pasting into reports or sending to an exchange's support team
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+const (
+	// DefaultBriefDays is the window a brief's recent counts, trend and quotes cover when ?days= is omitted
+	DefaultBriefDays = 30
+	// BriefQuotes is how many complaints a brief quotes
+	BriefQuotes = 5
+	// briefQuoteChars truncates long complaints so a brief stays readable
+	briefQuoteChars = 280
+)
+
+// sparkBars are the levels of a trend sparkline, lowest first
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// GetIssueBrief handles GET /api/issues/{id}/brief.md
+// Query params: days (default 30, 0 = full history). Summary, counts, the issue's
+// daily complaint trend and its most-liked complaints with links, as Markdown.
+func (h *EvidenceHandler) GetIssueBrief(w http.ResponseWriter, r *http.Request) {
+	days, ok := parseDays(w, r, DefaultBriefDays)
+	if !ok {
+		return
+	}
+	issue, err := h.resolutionService.GetIssue(r.PathValue("id"))
+	if err == nil && !visibleTo(r, issue.Tenant) {
+		err = fmt.Errorf("issue not found: %s", issue.ID)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	now := time.Now()
+	view := h.resolutionService.WithSLA(issue, now)
+	// Full history starts at first detection; an issue without one falls back to the default window
+	start := view.FirstDetected
+	switch {
+	case days > 0:
+		start = now.AddDate(0, 0, -days)
+	case start.IsZero():
+		start = now.AddDate(0, 0, -DefaultBriefDays)
+	}
+	complaints := analyzer.InPeriod(h.data.Complaints(), view.Category, start, now)
+
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(issueBrief(&view, complaints, start, now, requestBaseURL(r))))
+}
+
+// issueBrief renders the brief for an issue from its complaints within [start, now)
+func issueBrief(issue *models.Issue, complaints []models.Complaint, start, now time.Time, base string) string {
+	var b strings.Builder
+	period := analyzer.SummarizePeriod(complaints, start, now)
+	window := fmt.Sprintf("%s to %s", start.UTC().Format("2006-01-02"), now.UTC().Format("2006-01-02"))
+
+	fmt.Fprintf(&b, "# %s\n\n", issue.Title)
+	fmt.Fprintf(&b, "**Exchange:** %s · **Category:** %s · **Severity:** %s · **Status:** %s\n\n",
+		issue.Exchange, models.CategoryName(issue.Category), issue.Severity, issue.Status)
+
+	b.WriteString("## Summary\n\n")
+	if issue.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", issue.Description)
+	}
+	fmt.Fprintf(&b, "First detected %s, last updated %s.\n\n",
+		issue.FirstDetected.UTC().Format("2006-01-02"), issue.LastUpdated.UTC().Format("2006-01-02"))
+
+	b.WriteString("## Counts\n\n")
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Complaints tracked | %d |\n", issue.ComplaintCount)
+	if issue.WeightedCount > 0 {
+		fmt.Fprintf(&b, "| Trust-weighted complaints | %.1f |\n", issue.WeightedCount)
+	}
+	if reach := issue.Reach; reach != nil && reach.Threads > 0 {
+		fmt.Fprintf(&b, "| Estimated reach | %d (views and upvotes across %d videos and threads) |\n", reach.Estimated, reach.Threads)
+	}
+	fmt.Fprintf(&b, "| Complaints %s | %d |\n", window, period.Complaints)
+	if period.Scored > 0 {
+		fmt.Fprintf(&b, "| Mean sentiment %s | %.2f (−1 to 1, %d scored) |\n", window, period.Sentiment, period.Scored)
+	}
+	if len(period.Sources) > 0 {
+		fmt.Fprintf(&b, "| Sources | %s |\n", formatSources(period.Sources))
+	}
+	if sla := issue.SLA; sla != nil {
+		state := fmt.Sprintf("%.1f days left", sla.RemainingDays)
+		if sla.Overdue {
+			state = fmt.Sprintf("**overdue by %.1f days**", -sla.RemainingDays)
+		}
+		fmt.Fprintf(&b, "| SLA (%d days) | due %s, %s |\n", sla.WindowDays, sla.DueAt.UTC().Format("2006-01-02"), state)
+	}
+	b.WriteString("\n")
+
+	curve := analyzer.SentimentCurve(complaints, start, now)
+	if len(curve) > 0 {
+		counts := make([]string, len(curve))
+		for i, point := range curve {
+			counts[i] = fmt.Sprint(point.Complaints)
+		}
+		b.WriteString("## Trend\n\n")
+		fmt.Fprintf(&b, "Daily complaints, %s: `%s`\n\n", window, sparkline(curve))
+		fmt.Fprintf(&b, "Data: %s\n\n", strings.Join(counts, ", "))
+	}
+
+	if quotes := analyzer.SampleComplaints(complaints, BriefQuotes); len(quotes) > 0 {
+		b.WriteString("## Top complaints\n\n")
+		for _, c := range quotes {
+			b.WriteString(briefQuote(c))
+		}
+	}
+
+	if res := issue.Resolution; res != nil {
+		b.WriteString("## Resolution\n\n")
+		fmt.Fprintf(&b, "%s (confidence %.0f%%).", res.Status, res.Confidence*100)
+		if a := issue.Attestation; a != nil && a.ExplorerURL != "" {
+			fmt.Fprintf(&b, " Attested on-chain: [%s](%s).", a.EvidenceHash, a.ExplorerURL)
+		}
+		b.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(&b, "---\n\n_Generated by Coinsights on %s from %s/api/issues/%s_\n",
+		now.UTC().Format("2006-01-02 15:04 MST"), base, issue.ID)
+	return b.String()
+}
+
+// sparkline draws daily complaint counts as bars scaled to the busiest day
+func sparkline(curve []models.CurvePoint) string {
+	peak := 0
+	for _, point := range curve {
+		peak = max(peak, point.Complaints)
+	}
+	bars := make([]rune, len(curve))
+	for i, point := range curve {
+		level := 0
+		if peak > 0 {
+			level = point.Complaints * (len(sparkBars) - 1) / peak
+		}
+		bars[i] = sparkBars[level]
+	}
+	return string(bars)
+}
+
+// briefQuote renders a complaint as a Markdown blockquote with its author and a link to it
+func briefQuote(c models.Complaint) string {
+	text := strings.TrimSpace(c.Description)
+	if text == "" {
+		text = strings.TrimSpace(c.Title)
+	}
+	if runes := []rune(text); len(runes) > briefQuoteChars {
+		text = strings.TrimSpace(string(runes[:briefQuoteChars])) + "…"
+	}
+
+	attribution := analyzer.DataSource(c)
+	if c.Author != "" {
+		attribution = c.Author + ", " + attribution
+	}
+	if !c.PublishedAt.IsZero() {
+		attribution += ", " + c.PublishedAt.UTC().Format("2006-01-02")
+	}
+	if c.URL != "" {
+		attribution = fmt.Sprintf("[%s](%s)", attribution, c.URL)
+	}
+	if c.Likes > 0 {
+		attribution += fmt.Sprintf(" · %d likes", c.Likes)
+	}
+
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ") + "\n>\n> — " + attribution + "\n\n"
+}
+
+// formatSources lists complaint counts per source, largest first
+func formatSources(sources map[string]int) string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if sources[names[i]] != sources[names[j]] {
+			return sources[names[i]] > sources[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, sources[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,156 @@
+// API for the frontend's landing page, bundled into a single response
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/cache"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+const (
+	// DashboardCacheTTL is short because resolutions and attestations change between data reloads
+	DashboardCacheTTL = 30 * time.Second
+	// DashboardListSize caps each list in the dashboard payload
+	DashboardListSize = 5
+)
+
+// severityRank orders issue severities, most severe first
+var severityRank = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// DashboardHandler assembles the dashboard payload server-side so the
+// frontend loads the page with one request instead of five
+type DashboardHandler struct {
+	data              *DataHandler
+	resolutionService *services.ResolutionService
+	cache             *cache.Cache
+}
+
+// Dashboard is the consolidated read-only payload behind GET /api/dashboard
+type Dashboard struct {
+	Stats              map[string]interface{}       `json:"stats"`
+	TopIssues          []*models.Issue              `json:"top_issues"`  // Open issues, most severe and most complained about first
+	Projections        []*models.VelocityProjection `json:"projections"` // Top issues' paths to their critical complaint counts
+	Trends             []DashboardTrend             `json:"trends"`      // Largest emergent topics
+	LatestResolutions  []*models.Resolution         `json:"latest_resolutions"`
+	LatestAttestations []DashboardAttestation       `json:"latest_attestations"`
+	DataLoadedAt       time.Time                    `json:"data_loaded_at"`
+	GeneratedAt        time.Time                    `json:"generated_at"`
+}
+
+// DashboardTrend is an emergent topic with its daily volume
+type DashboardTrend struct {
+	analyzer.Topic
+	Timeline []analyzer.TopicPoint `json:"timeline"`
+}
+
+// DashboardAttestation is an on-chain attestation with the resolution it attests
+type DashboardAttestation struct {
+	ResolutionID  string `json:"resolution_id"`
+	Exchange      string `json:"exchange"`
+	IssueCategory string `json:"issue_category"`
+	*models.Attestation
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(data *DataHandler, rs *services.ResolutionService) *DashboardHandler {
+	return &DashboardHandler{
+		data:              data,
+		resolutionService: rs,
+		cache:             cache.New(DashboardCacheTTL),
+	}
+}
+
+// GetDashboard handles GET /api/dashboard
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	dashboard := Dashboard{
+		Stats:              h.resolutionService.GetStats(r.Context(), tenantFilter(r)),
+		TopIssues:          h.topIssues(r),
+		Trends:             h.trends(),
+		LatestResolutions:  h.latestResolutions(r),
+		LatestAttestations: h.latestAttestations(r),
+		DataLoadedAt:       h.data.LoadedAt(),
+		GeneratedAt:        time.Now(),
+	}
+	dashboard.Stats["complaint_count"] = len(h.data.Complaints())
+	dashboard.Projections = h.projections(dashboard.TopIssues)
+
+	respondJSON(w, http.StatusOK, dashboard)
+}
+
+func (h *DashboardHandler) topIssues(r *http.Request) []*models.Issue {
+	issues := []*models.Issue{}
+	for _, issue := range h.resolutionService.ListIssues("") {
+		if !visibleTo(r, issue.Tenant) {
+			continue
+		}
+		if issue.Status == "active" || issue.Status == "investigating" {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		ri, rj := severityRank[issues[i].Severity], severityRank[issues[j].Severity]
+		if ri != rj {
+			return ri < rj
+		}
+		if issues[i].ComplaintCount != issues[j].ComplaintCount {
+			return issues[i].ComplaintCount > issues[j].ComplaintCount
+		}
+		return issues[i].ID < issues[j].ID
+	})
+	return issues[:min(len(issues), DashboardListSize)]
+}
+
+func (h *DashboardHandler) projections(issues []*models.Issue) []*models.VelocityProjection {
+	projections := []*models.VelocityProjection{}
+	for _, issue := range issues {
+		if projection, err := h.resolutionService.ProjectVelocity(issue.ID); err == nil {
+			projections = append(projections, projection)
+		}
+	}
+	return projections
+}
+
+func (h *DashboardHandler) trends() []DashboardTrend {
+	topics := analyzer.ClusterTopics(h.data.Complaints(), analyzer.MinTopicSize)
+	trends := []DashboardTrend{}
+	for _, topic := range topics[:min(len(topics), DashboardListSize)] {
+		trends = append(trends, DashboardTrend{Topic: topic, Timeline: topic.Timeline()})
+	}
+	return trends
+}
+
+func (h *DashboardHandler) latestResolutions(r *http.Request) []*models.Resolution {
+	resolutions := []*models.Resolution{}
+	for _, resolution := range h.resolutionService.ListResolutions("") {
+		if visibleTo(r, resolution.Tenant) {
+			resolutions = append(resolutions, resolution)
+		}
+	}
+	sort.Slice(resolutions, func(i, j int) bool {
+		return resolutions[i].CreatedAt.After(resolutions[j].CreatedAt)
+	})
+	return resolutions[:min(len(resolutions), DashboardListSize)]
+}
+
+func (h *DashboardHandler) latestAttestations(r *http.Request) []DashboardAttestation {
+	attested := h.resolutionService.AttestedResolutions() // Oldest block first
+	latest := []DashboardAttestation{}
+	for i := len(attested) - 1; i >= 0 && len(latest) < DashboardListSize; i-- {
+		res := attested[i]
+		if !visibleTo(r, res.Tenant) {
+			continue
+		}
+		latest = append(latest, DashboardAttestation{
+			ResolutionID:  res.ID,
+			Exchange:      res.Exchange,
+			IssueCategory: res.IssueCategory,
+			Attestation:   res.Attestation,
+		})
+	}
+	return latest
+}
@@ -0,0 +1,548 @@
+// API for the scraped and analyzed complaint data
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/cache"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/ingest"
+	"github.com/tasnint/coinsights/internal/integrity"
+	"github.com/tasnint/coinsights/internal/jsonstream"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/normalize"
+	"github.com/tasnint/coinsights/internal/scrapers"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/trends"
+)
+
+// Cache TTLs for the heavy data endpoints
+const (
+	AnalysisCacheTTL = 10 * time.Minute
+)
+
+const (
+	// DefaultTrendWindow is how many days GET /api/trends covers when ?window= is omitted
+	DefaultTrendWindow = 30
+	// MaxTrendMovingAverage caps ?ma=
+	MaxTrendMovingAverage = 90
+)
+
+// DataHandler serves scrape and analysis results loaded from the data directory
+type DataHandler struct {
+	dataDir  string
+	cache    *cache.Cache
+	snapshot atomic.Pointer[dataSnapshot] // Swapped whole by Reload; never mutated in place
+	reloadMu sync.Mutex                   // One Reload at a time; readers never wait on it
+
+	// Optional: open tracked issues from each reload's analysis
+	resolutionService *services.ResolutionService
+	exchange          string
+	detection         config.DetectionSettings
+	resolutions       *services.ResolutionDetector // Optional: proposes resolutions when categories drop
+
+	// Optional: daily complaint counts kept across reloads for GET /api/trends
+	trends *trends.Store
+}
+
+// dataSnapshot is everything one Reload produced. Handlers load it once per request,
+// so they always see analysis, Gemini results and complaints from the same reload.
+type dataSnapshot struct {
+	analysis      *analyzer.AnalysisResult
+	geminiResults []scrapers.AIOverviewResult
+	complaints    []models.Complaint // The deployment's own; what the analysis sees
+	tenantIngests []models.Complaint // Ingested for tenants; only GET /api/complaints serves them
+	loadedAt      time.Time
+}
+
+// NewDataHandler creates a new data handler reading from dataDir
+func NewDataHandler(dataDir string) *DataHandler {
+	h := &DataHandler{
+		dataDir: dataDir,
+		cache:   cache.New(AnalysisCacheTTL),
+	}
+	h.snapshot.Store(&dataSnapshot{})
+	return h
+}
+
+// EnableIssueDetection makes every Reload feed the analysis into the resolution
+// service, opening issues for categories that cross their complaint thresholds
+func (h *DataHandler) EnableIssueDetection(rs *services.ResolutionService, exchange string, settings config.DetectionSettings) {
+	h.resolutionService = rs
+	h.exchange = exchange
+	h.detection = settings
+}
+
+// EnableResolutionDetection makes every Reload after issue detection compare the
+// analyzed run with an earlier one and propose resolutions for review.
+// Requires EnableIssueDetection.
+func (h *DataHandler) EnableResolutionDetection(detector *services.ResolutionDetector) {
+	h.resolutions = detector
+}
+
+// EnableTrends records every reload's complaints in store and serves GET /api/trends from it
+func (h *DataHandler) EnableTrends(store *trends.Store) {
+	h.trends = store
+}
+
+// Reload re-reads the data files, re-runs the analyzer and invalidates cached responses
+func (h *DataHandler) Reload() error {
+	h.reloadMu.Lock()
+	defer h.reloadMu.Unlock()
+
+	var analysis *analyzer.AnalysisResult
+	var extracted []analyzer.ExtractedIssue
+	var complaints []models.Complaint
+	youtubePath := filepath.Join(h.dataDir, "youtube_latest_results.json")
+	if _, err := os.Stat(youtubePath); err == nil && verifyArtifact(youtubePath) {
+		classifications, err := analyzer.LoadClassifications(h.dataDir)
+		if err != nil {
+			return err
+		}
+		ytAnalyzer := analyzer.NewYouTubeAnalyzer()
+		ytAnalyzer.SetClassifications(classifications.Classifications)
+		analysis, err = ytAnalyzer.AnalyzeFile(youtubePath)
+		if err != nil {
+			return fmt.Errorf("failed to analyze YouTube data: %w", err)
+		}
+		history, err := analyzer.NewAnalysisHistory(h.dataDir)
+		if err != nil {
+			return err
+		}
+		if err := history.Annotate(analysis); err != nil {
+			return err
+		}
+		extracted = ytAnalyzer.Issues()
+		complaints = normalize.YouTube(extracted, time.Now())
+	}
+
+	var geminiResults []scrapers.AIOverviewResult
+	geminiPath := filepath.Join(h.dataDir, "gemini_latest_results.json")
+	if dec, release, err := openArtifact(geminiPath); err == nil {
+		geminiResults, err = jsonstream.Array(dec, geminiResults)
+		release()
+		if err != nil {
+			return fmt.Errorf("failed to parse Gemini results: %w", err)
+		}
+		geminiComplaints := normalize.Gemini(geminiResults, time.Now())
+		history, err := scrapers.NewComplaintHistory(h.dataDir)
+		if err != nil {
+			return err
+		}
+		history.Apply(geminiComplaints)
+		complaints = append(complaints, geminiComplaints...)
+	}
+
+	redditPath := filepath.Join(h.dataDir, scrapers.RedditResultsFile)
+	if data, err := os.ReadFile(redditPath); err == nil && verifyArtifact(redditPath) {
+		var redditResult scrapers.RedditResult
+		if err := json.Unmarshal(data, &redditResult); err != nil {
+			return fmt.Errorf("failed to parse Reddit results: %w", err)
+		}
+		complaints = append(complaints, normalize.Reddit(&redditResult, time.Now())...)
+	}
+
+	reviewsPath := filepath.Join(h.dataDir, scrapers.ReviewsResultsFile)
+	if data, err := os.ReadFile(reviewsPath); err == nil && verifyArtifact(reviewsPath) {
+		var reviewsResult scrapers.ReviewsResult
+		if err := json.Unmarshal(data, &reviewsResult); err != nil {
+			return fmt.Errorf("failed to parse review results: %w", err)
+		}
+		complaints = append(complaints, normalize.Reviews(&reviewsResult, time.Now())...)
+	}
+
+	ingested, err := ingest.LoadComplaints(h.dataDir)
+	if err != nil {
+		return err
+	}
+	var tenantIngests []models.Complaint
+	for _, complaint := range ingested {
+		if complaint.Tenant != "" {
+			tenantIngests = append(tenantIngests, complaint)
+		} else {
+			complaints = append(complaints, complaint)
+		}
+	}
+
+	h.snapshot.Store(&dataSnapshot{
+		analysis:      analysis,
+		geminiResults: geminiResults,
+		complaints:    complaints,
+		tenantIngests: tenantIngests,
+		loadedAt:      time.Now(),
+	})
+
+	h.cache.Purge()
+
+	if h.trends != nil {
+		if _, err := h.trends.Record(complaints, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if h.resolutionService != nil && analysis != nil {
+		reach := analyzer.ReachByCategory(complaints)
+		report, err := h.resolutionService.DetectIssues(h.exchange, analysis, extracted, reach, h.detection)
+		if err != nil {
+			return fmt.Errorf("failed to detect issues: %w", err)
+		}
+		fmt.Printf("📊 Issue detection: %d opened, %d updated\n", len(report.Created), len(report.Updated))
+		if alerted := h.resolutionService.UpdateVelocity(h.exchange, complaints, time.Now()); len(alerted) > 0 {
+			fmt.Printf("📈 %d issue(s) reached the complaint velocity alert\n", len(alerted))
+		}
+
+		if h.resolutions != nil {
+			candidates, err := h.resolutions.Detect(h.exchange, analysis, complaints)
+			if err != nil {
+				return fmt.Errorf("failed to detect resolutions: %w", err)
+			}
+			if len(candidates) > 0 {
+				fmt.Printf("🔎 Resolution detection: %d candidates need review\n", len(candidates))
+			}
+		}
+	}
+	return nil
+}
+
+// verifyArtifact reports whether a scrape file matches its recorded checksum. One
+// that doesn't is left out of the analysis and listed by GET /api/admin/scrapers/status.
+func verifyArtifact(path string) bool {
+	if err := integrity.Verify(path); err != nil {
+		fmt.Printf("⚠️  Skipping %s: %v\n", filepath.Base(path), err)
+		return false
+	}
+	return true
+}
+
+// openArtifact is jsonstream.Open for a scrape file that passes verifyArtifact
+func openArtifact(path string) (*json.Decoder, func(), error) {
+	if !verifyArtifact(path) {
+		return nil, nil, fmt.Errorf("%s failed its integrity check", filepath.Base(path))
+	}
+	return jsonstream.Open(path)
+}
+
+// Complaints returns the complaints from the last Reload
+func (h *DataHandler) Complaints() []models.Complaint {
+	return h.snapshot.Load().complaints
+}
+
+// LoadedAt returns when the data was last reloaded (zero before the first Reload)
+func (h *DataHandler) LoadedAt() time.Time {
+	return h.snapshot.Load().loadedAt
+}
+
+// ============================================
+// ANALYSIS ENDPOINTS
+// ============================================
+
+// GetYouTubeAnalysis handles GET /api/analysis/youtube, streamed since the analysis
+// of a large corpus runs to megabytes
+func (h *DataHandler) GetYouTubeAnalysis(w http.ResponseWriter, r *http.Request) {
+	analysis := h.snapshot.Load().analysis
+
+	if analysis == nil {
+		respondError(w, http.StatusNotFound, "No YouTube analysis available. Run the scraper first.")
+		return
+	}
+
+	respondJSONStream(w, http.StatusOK, analysis)
+}
+
+// Stats is the headline scrape numbers behind GET /api/stats, with the data
+// sources they're built from
+type Stats struct {
+	VideosAnalyzed   int                    `json:"videos_analyzed"`
+	CommentsAnalyzed int                    `json:"comments_analyzed"`
+	IssuesFound      int                    `json:"issues_found"`
+	Categories       int                    `json:"categories"`
+	Complaints       int                    `json:"complaints"`
+	Sources          []analyzer.SourceStats `json:"sources"` // Largest first
+	DataLoadedAt     time.Time              `json:"data_loaded_at"`
+}
+
+// GetStats handles GET /api/stats
+func (h *DataHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.snapshot.Load()
+
+	stats := Stats{
+		Complaints:   len(snapshot.complaints),
+		Sources:      analyzer.BySource(snapshot.complaints),
+		DataLoadedAt: snapshot.loadedAt,
+	}
+	if analysis := snapshot.analysis; analysis != nil {
+		stats.VideosAnalyzed = analysis.TotalVideos
+		stats.CommentsAnalyzed = analysis.TotalComments
+		stats.IssuesFound = analysis.TotalIssues
+		stats.Categories = len(analysis.Categories)
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// GetSentimentDivergence handles GET /api/analysis/youtube/divergence
+// Optional ?divergent=true keeps only videos flagged as positive framing with angry comments
+func (h *DataHandler) GetSentimentDivergence(w http.ResponseWriter, r *http.Request) {
+	analysis := h.snapshot.Load().analysis
+	if analysis == nil {
+		respondError(w, http.StatusNotFound, "No YouTube analysis available. Run the scraper first.")
+		return
+	}
+
+	divergentOnly := false
+	if v := r.URL.Query().Get("divergent"); v != "" {
+		var err error
+		if divergentOnly, err = strconv.ParseBool(v); err != nil {
+			respondError(w, http.StatusBadRequest, "divergent must be true or false")
+			return
+		}
+	}
+
+	videos := []analyzer.VideoDivergence{}
+	for _, d := range analysis.SentimentDivergence {
+		if !divergentOnly || d.Divergent {
+			videos = append(videos, d)
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"videos": videos,
+		"count":  len(videos),
+	})
+}
+
+// GetGeminiResults handles GET /api/analysis/gemini
+func (h *DataHandler) GetGeminiResults(w http.ResponseWriter, r *http.Request) {
+	results := h.snapshot.Load().geminiResults
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+	})
+}
+
+// ============================================
+// COMPLAINT ENDPOINTS
+// ============================================
+
+// ListCategories handles GET /api/categories
+func (h *DataHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories := models.Categories()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"categories": categories,
+		"count":      len(categories),
+	})
+}
+
+// GetVersionBreakdown handles GET /api/complaints/versions
+// Groups a category's complaints (default app_bugs) by app version and OS
+func (h *DataHandler) GetVersionBreakdown(w http.ResponseWriter, r *http.Request) {
+	category := models.CategoryAppBugs
+	if v := r.URL.Query().Get("category"); v != "" {
+		category = models.NormalizeCategory(v)
+	}
+
+	complaints := h.snapshot.Load().complaints
+
+	breakdown := analyzer.VersionBreakdown(complaints, category)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"category": category,
+		"versions": breakdown,
+		"count":    len(breakdown),
+	})
+}
+
+// ListComplaints handles GET /api/complaints
+// Streams the list (or ND-JSON with ?format=ndjson) since it can hold thousands of records.
+// Each complaint carries highlights of the keywords that put it in its category.
+// A tenant key gets the complaints ingested for its tenant; operator keys get every one.
+func (h *DataHandler) ListComplaints(w http.ResponseWriter, r *http.Request) {
+	category := r.URL.Query().Get("category")
+	if category != "" {
+		category = models.NormalizeCategory(category)
+	}
+
+	snapshot := h.snapshot.Load()
+	complaints := snapshot.complaints
+	if scope, all := requestScope(r); all && len(snapshot.tenantIngests) > 0 {
+		complaints = append(slices.Clip(complaints), snapshot.tenantIngests...)
+	} else if scope != "" {
+		complaints = make([]models.Complaint, 0)
+		for _, c := range snapshot.tenantIngests {
+			if c.Tenant == scope {
+				complaints = append(complaints, c)
+			}
+		}
+	}
+
+	if category != "" {
+		filtered := make([]models.Complaint, 0)
+		for _, c := range complaints {
+			if c.Category == category {
+				filtered = append(filtered, c)
+			}
+		}
+		complaints = filtered
+	}
+
+	item := func(i int) any {
+		c := complaints[i] // A copy; the snapshot is shared
+		c.Highlights = analyzer.Highlights(c)
+		return c
+	}
+	if wantsNDJSON(r) {
+		respondNDJSON(w, len(complaints), item)
+		return
+	}
+	respondJSONList(w, "complaints", len(complaints), item)
+}
+
+// ============================================
+// TOPIC ENDPOINTS
+// ============================================
+
+// GetCategoryCoOccurrence handles GET /api/categories/cooccurrence
+// Optional ?min_count= drops pairs seen in fewer records from the pair list
+func (h *DataHandler) GetCategoryCoOccurrence(w http.ResponseWriter, r *http.Request) {
+	minCount := 1
+	if v := r.URL.Query().Get("min_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "min_count must be a positive integer")
+			return
+		}
+		minCount = n
+	}
+
+	respondJSON(w, http.StatusOK, analyzer.CategoryCoOccurrence(h.snapshot.Load().complaints, minCount))
+}
+
+// GetTopics handles GET /api/topics
+// Optional ?min_size= sets how many complaints a phrase needs to form a topic
+func (h *DataHandler) GetTopics(w http.ResponseWriter, r *http.Request) {
+	topics, ok := h.topics(w, r)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"topics": topics,
+		"count":  len(topics),
+	})
+}
+
+// GetTopicTimeline handles GET /api/topics/{id}/timeline
+func (h *DataHandler) GetTopicTimeline(w http.ResponseWriter, r *http.Request) {
+	topics, ok := h.topics(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	for _, topic := range topics {
+		if topic.ID == id {
+			respondJSON(w, http.StatusOK, map[string]interface{}{
+				"topic":    topic,
+				"timeline": topic.Timeline(),
+			})
+			return
+		}
+	}
+	respondError(w, http.StatusNotFound, "Topic not found")
+}
+
+// GetTrends handles GET /api/trends
+// Query params: category (optional, default every category), window (days, e.g. "30d"
+// or "30", default 30, max 365), ma (moving average days, default 7). Daily complaint
+// counts by the day each complaint was posted, zero-filled, oldest first.
+func (h *DataHandler) GetTrends(w http.ResponseWriter, r *http.Request) {
+	if h.trends == nil {
+		respondError(w, http.StatusServiceUnavailable, "Complaint trends not configured")
+		return
+	}
+	query := r.URL.Query()
+
+	window := DefaultTrendWindow
+	if v := query.Get("window"); v != "" {
+		n, err := strconv.Atoi(strings.TrimSuffix(v, "d"))
+		if err != nil || n < 1 || n > trends.MaxDays {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("window must be between 1d and %dd", trends.MaxDays))
+			return
+		}
+		window = n
+	}
+	movingAverage := trends.DefaultMovingAverage
+	if v := query.Get("ma"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxTrendMovingAverage {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("ma must be between 1 and %d", MaxTrendMovingAverage))
+			return
+		}
+		movingAverage = n
+	}
+	category := strings.TrimSpace(query.Get("category"))
+	if category != "" {
+		category = models.NormalizeCategory(category)
+	}
+
+	respondJSON(w, http.StatusOK, h.trends.Series(category, window, movingAverage, time.Now()))
+}
+
+// GetSubredditTrends handles GET /api/reddit/subreddits
+// Optional ?subreddits=CoinBase,CryptoCurrency picks the venues to compare (default: those two)
+// and ?interval_days= the bucket size (default 7)
+func (h *DataHandler) GetSubredditTrends(w http.ResponseWriter, r *http.Request) {
+	subreddits := analyzer.DefaultSubreddits
+	if v := r.URL.Query().Get("subreddits"); v != "" {
+		subreddits = nil
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimPrefix(strings.TrimSpace(name), "r/"); name != "" {
+				subreddits = append(subreddits, name)
+			}
+		}
+		if len(subreddits) == 0 {
+			respondError(w, http.StatusBadRequest, "subreddits must list at least one subreddit")
+			return
+		}
+	}
+
+	interval := 7
+	if v := r.URL.Query().Get("interval_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "interval_days must be a positive integer")
+			return
+		}
+		interval = n
+	}
+
+	trends := analyzer.SubredditTrends(h.snapshot.Load().complaints, subreddits, interval)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"subreddits":    trends,
+		"count":         len(trends),
+		"interval_days": interval,
+	})
+}
+
+// topics clusters the current complaints, writing a 400 and returning false on a bad ?min_size=
+func (h *DataHandler) topics(w http.ResponseWriter, r *http.Request) ([]analyzer.Topic, bool) {
+	minSize := analyzer.MinTopicSize
+	if v := r.URL.Query().Get("min_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 2 {
+			respondError(w, http.StatusBadRequest, "min_size must be an integer of at least 2")
+			return nil, false
+		}
+		minSize = n
+	}
+	return analyzer.ClusterTopics(h.snapshot.Load().complaints, minSize), true
+}
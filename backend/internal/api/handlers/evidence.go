@@ -0,0 +1,92 @@
+// API for evidence bundles: the measured complaints behind a resolution's evidence hash
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// EvidenceHandler assembles evidence bundles from the loaded complaints and serves stored ones
+type EvidenceHandler struct {
+	data              *DataHandler
+	resolutionService *services.ResolutionService
+}
+
+// NewEvidenceHandler creates a new evidence handler
+func NewEvidenceHandler(data *DataHandler, rs *services.ResolutionService) *EvidenceHandler {
+	return &EvidenceHandler{
+		data:              data,
+		resolutionService: rs,
+	}
+}
+
+// AssembleEvidenceRequest is the request body for assembling an evidence bundle
+type AssembleEvidenceRequest struct {
+	Start time.Time `json:"start"` // RFC3339; start of the measurement window
+	End   time.Time `json:"end"`   // RFC3339; end of the measurement window (exclusive)
+
+	// Optional scope: count only complaints about this app version and/or OS
+	AppVersion string `json:"app_version,omitempty"`
+	OS         string `json:"os,omitempty"`
+}
+
+// AssembleEvidence handles POST /api/issues/{id}/evidence
+// Measures the issue's complaints over the window against an equally long period before it.
+// The bundle's evidence can be submitted as-is with POST /api/resolutions.
+func (h *EvidenceHandler) AssembleEvidence(w http.ResponseWriter, r *http.Request) {
+	var req AssembleEvidenceRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Start.IsZero() || req.End.IsZero() {
+		respondError(w, http.StatusBadRequest, "start and end are required")
+		return
+	}
+	if !req.End.After(req.Start) {
+		respondError(w, http.StatusBadRequest, "end must be after start")
+		return
+	}
+
+	id := r.PathValue("id")
+	issue, err := h.resolutionService.GetIssue(id)
+	if err == nil && !visibleTo(r, issue.Tenant) {
+		err = fmt.Errorf("issue not found: %s", id)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	bundle, err := h.resolutionService.AssembleEvidence(id, h.data.Complaints(), req.Start, req.End, services.EvidenceScope{
+		AppVersion: req.AppVersion,
+		OS:         req.OS,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, bundle)
+}
+
+// GetEvidenceBundle handles GET /api/evidence/{hash}
+// The stored bundle an evidence hash commits to. A bundle for another tenant's issue
+// is reported as not found.
+func (h *EvidenceHandler) GetEvidenceBundle(w http.ResponseWriter, r *http.Request) {
+	hash := r.PathValue("hash")
+	bundle, err := h.resolutionService.EvidenceBundle(hash)
+	if err == nil {
+		if issue, lookupErr := h.resolutionService.GetIssue(bundle.IssueID); lookupErr == nil && !visibleTo(r, issue.Tenant) {
+			err = fmt.Errorf("evidence bundle not found: %s", hash)
+		}
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bundle)
+}
@@ -0,0 +1,164 @@
+// API for per-exchange reputation: open issues, complaint sentiment and review-site rating trends
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/cache"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratings"
+	"github.com/tasnint/coinsights/internal/scorecards"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+const (
+	// DefaultRatingDays is the window rating trends are computed over when ?days= is omitted
+	DefaultRatingDays = 30
+	// DefaultScorecardHistoryDays is how far back scorecard history goes when ?days= is omitted
+	DefaultScorecardHistoryDays = 365
+	// CompareCacheTTL is short because issue counts change between data reloads
+	CompareCacheTTL = 30 * time.Second
+	// MaxCompareExchanges caps the exchanges one comparison builds scorecards for
+	MaxCompareExchanges = 10
+)
+
+// ExchangeHandler handles exchange scorecard endpoints
+type ExchangeHandler struct {
+	scorecards *scorecards.Builder
+	ratings    *ratings.Store
+	history    *scorecards.Store // Optional: daily scorecard snapshots
+	cache      *cache.Cache
+}
+
+// NewExchangeHandler creates a new exchange handler
+func NewExchangeHandler(rs *services.ResolutionService, ratingStore *ratings.Store) *ExchangeHandler {
+	return &ExchangeHandler{
+		scorecards: scorecards.NewBuilder(rs, ratingStore),
+		ratings:    ratingStore,
+		cache:      cache.New(CompareCacheTTL),
+	}
+}
+
+// EnableSentiment adds complaint counts and sentiment from data to exchange's scorecard
+func (h *ExchangeHandler) EnableSentiment(exchange string, data *DataHandler) {
+	h.scorecards.EnableSentiment(exchange, data.Complaints)
+}
+
+// EnableHistory serves scorecard history from store. Keep it filled by running
+// scorecards.RunRecorder with Scorecards().
+func (h *ExchangeHandler) EnableHistory(store *scorecards.Store) {
+	h.history = store
+}
+
+// Scorecards returns the builder the handler computes scorecards with
+func (h *ExchangeHandler) Scorecards() *scorecards.Builder {
+	return h.scorecards
+}
+
+// GetScorecard handles GET /api/exchanges/{exchange}/scorecard
+func (h *ExchangeHandler) GetScorecard(w http.ResponseWriter, r *http.Request) {
+	days, ok := parseDays(w, r, DefaultRatingDays)
+	if !ok {
+		return
+	}
+	respondJSON(w, http.StatusOK, h.scorecards.Build(r.PathValue("exchange"), days, time.Now()))
+}
+
+// CompareExchanges handles GET /api/exchanges/compare
+// Query params: exchanges (required, comma-separated, 2-10), days (default 30, 0 = full history).
+// Scorecards come back in the order the exchanges were asked for.
+func (h *ExchangeHandler) CompareExchanges(w http.ResponseWriter, r *http.Request) {
+	var exchanges []string
+	for _, exchange := range strings.Split(r.URL.Query().Get("exchanges"), ",") {
+		exchange = strings.ToLower(strings.TrimSpace(exchange))
+		if exchange != "" && !slices.Contains(exchanges, exchange) {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+	if len(exchanges) < 2 || len(exchanges) > MaxCompareExchanges {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("exchanges must name 2 to %d different exchanges", MaxCompareExchanges))
+		return
+	}
+	days, ok := parseDays(w, r, DefaultRatingDays)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	cards := make([]models.ExchangeScorecard, len(exchanges))
+	for i, exchange := range exchanges {
+		cards[i] = h.scorecards.Build(exchange, days, now)
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"days":       days,
+		"scorecards": cards,
+		"count":      len(cards),
+	})
+}
+
+// GetScorecardHistory handles GET /api/exchanges/{exchange}/scorecard/history
+// Query params: days (default 365, 0 = full history). Daily snapshots, oldest first.
+func (h *ExchangeHandler) GetScorecardHistory(w http.ResponseWriter, r *http.Request) {
+	if h.history == nil {
+		respondError(w, http.StatusServiceUnavailable, "Scorecard history not configured")
+		return
+	}
+	days, ok := parseDays(w, r, DefaultScorecardHistoryDays)
+	if !ok {
+		return
+	}
+
+	exchange := r.PathValue("exchange")
+	snapshots := h.history.Series(exchange, days)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"exchange":  exchange,
+		"snapshots": snapshots,
+		"count":     len(snapshots),
+	})
+}
+
+// GetRatings handles GET /api/exchanges/{exchange}/ratings
+// Query params: source (required, e.g. "trustpilot"), days (default 30, 0 = full history)
+func (h *ExchangeHandler) GetRatings(w http.ResponseWriter, r *http.Request) {
+	exchange := r.PathValue("exchange")
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		respondError(w, http.StatusBadRequest, "source is required")
+		return
+	}
+	days, ok := parseDays(w, r, DefaultRatingDays)
+	if !ok {
+		return
+	}
+
+	snapshots := []ratings.Snapshot{}
+	if h.ratings != nil {
+		snapshots = h.ratings.Series(exchange, source, days)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"exchange":  exchange,
+		"source":    source,
+		"snapshots": snapshots,
+		"count":     len(snapshots),
+	})
+}
+
+// parseDays reads ?days= (def when omitted), writing a 400 and returning false if it is invalid
+func parseDays(w http.ResponseWriter, r *http.Request, def int) (int, bool) {
+	v := r.URL.Query().Get("days")
+	if v == "" {
+		return def, true
+	}
+	days, err := strconv.Atoi(v)
+	if err != nil || days < 0 {
+		respondError(w, http.StatusBadRequest, "days must be a non-negative integer")
+		return 0, false
+	}
+	return days, true
+}
@@ -0,0 +1,135 @@
+// Attestation export for auditors and compliance consumers
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AttestationExportRecord is one row of the attestation export
+type AttestationExportRecord struct {
+	ResolutionID    string    `json:"resolution_id"`
+	Exchange        string    `json:"exchange"`
+	IssueCategory   string    `json:"issue_category"`
+	Confidence      float64   `json:"confidence"`
+	AttestationID   uint64    `json:"attestation_id"`
+	TransactionHash string    `json:"transaction_hash"`
+	BlockNumber     uint64    `json:"block_number"`
+	BlockTimestamp  time.Time `json:"block_timestamp"`
+	ChainID         int64     `json:"chain_id"`
+	ContractAddress string    `json:"contract_address"`
+	EvidenceHash    string    `json:"evidence_hash"`
+	PreviousHash    string    `json:"previous_hash"`
+	Attestor        string    `json:"attestor"`
+	AttestorName    string    `json:"attestor_name"`
+	ExplorerURL     string    `json:"explorer_url"`
+}
+
+var attestationExportHeader = []string{
+	"resolution_id", "exchange", "issue_category", "confidence",
+	"attestation_id", "transaction_hash", "block_number", "block_timestamp",
+	"chain_id", "contract_address", "evidence_hash", "previous_hash",
+	"attestor", "attestor_name", "explorer_url",
+}
+
+// ExportAttestations handles GET /api/attestations/export
+// Query params: format (json|csv, default json), signed (true adds a detached
+// EIP-191 signature over the exact response bytes in the X-Signature header)
+func (h *BlockchainHandler) ExportAttestations(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		respondError(w, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+	signed, _ := strconv.ParseBool(r.URL.Query().Get("signed"))
+	if signed && h.blockchainService == nil {
+		respondError(w, http.StatusServiceUnavailable, "Blockchain service not configured; cannot sign export")
+		return
+	}
+
+	resolutions := h.resolutionService.AttestedResolutions()
+	records := make([]AttestationExportRecord, 0, len(resolutions))
+	for _, res := range resolutions {
+		if !visibleTo(r, res.Tenant) {
+			continue
+		}
+		a := res.Attestation
+		records = append(records, AttestationExportRecord{
+			ResolutionID:    res.ID,
+			Exchange:        res.Exchange,
+			IssueCategory:   res.IssueCategory,
+			Confidence:      res.Confidence,
+			AttestationID:   a.ID,
+			TransactionHash: a.TransactionHash,
+			BlockNumber:     a.BlockNumber,
+			BlockTimestamp:  a.BlockTimestamp,
+			ChainID:         a.ChainID,
+			ContractAddress: a.ContractAddress,
+			EvidenceHash:    a.EvidenceHash,
+			PreviousHash:    a.PreviousHash,
+			Attestor:        a.Attestor,
+			AttestorName:    a.AttestorName,
+			ExplorerURL:     a.ExplorerURL,
+		})
+	}
+
+	// Render fully before writing so the signature covers the exact bytes sent
+	var body bytes.Buffer
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv"
+		cw := csv.NewWriter(&body)
+		cw.Write(attestationExportHeader)
+		for _, rec := range records {
+			cw.Write([]string{
+				rec.ResolutionID, rec.Exchange, rec.IssueCategory,
+				strconv.FormatFloat(rec.Confidence, 'f', -1, 64),
+				strconv.FormatUint(rec.AttestationID, 10), rec.TransactionHash,
+				strconv.FormatUint(rec.BlockNumber, 10), rec.BlockTimestamp.UTC().Format(time.RFC3339),
+				strconv.FormatInt(rec.ChainID, 10), rec.ContractAddress, rec.EvidenceHash, rec.PreviousHash,
+				rec.Attestor, rec.AttestorName, rec.ExplorerURL,
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to write CSV: "+err.Error())
+			return
+		}
+	default:
+		contentType = "application/json"
+		if err := json.NewEncoder(&body).Encode(map[string]interface{}{
+			"attestations": records,
+			"count":        len(records),
+			"exported_at":  time.Now().UTC(),
+		}); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if signed {
+		signature, err := h.blockchainService.SignMessage(body.Bytes())
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.Header().Set("X-Signature", signature)
+		w.Header().Set("X-Signature-Signer", h.blockchainService.GetWalletAddress())
+		w.Header().Set("X-Signature-Algorithm", "eip191-personal-sign")
+	}
+
+	filename := fmt.Sprintf("attestations-%s.%s", time.Now().UTC().Format("20060102"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
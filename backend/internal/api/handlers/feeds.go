@@ -0,0 +1,268 @@
+// Atom and RSS feeds of tracked issues, filtered by exchange and category, for feed readers and embeds
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+const (
+	// FeedSize caps the entries in one feed, most recently updated first
+	FeedSize = 50
+	// FeedAllExchanges in place of an exchange name matches every exchange
+	FeedAllExchanges = "all"
+)
+
+// FeedHandler serves issue feeds: /feeds/{exchange}.atom for one exchange and
+// /feeds/{exchange}/{category}.atom for one of its complaint categories.
+// Every feed is also available as RSS 2.0 with .rss instead of .atom.
+type FeedHandler struct {
+	resolutionService *services.ResolutionService
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(rs *services.ResolutionService) *FeedHandler {
+	return &FeedHandler{resolutionService: rs}
+}
+
+// GetExchangeFeed handles GET /feeds/{feed}, e.g. /feeds/coinbase.atom
+func (h *FeedHandler) GetExchangeFeed(w http.ResponseWriter, r *http.Request) {
+	exchange, format, ok := splitFeedName(r.PathValue("feed"))
+	if !ok {
+		respondError(w, http.StatusNotFound, "Feed not found; use a .atom or .rss extension")
+		return
+	}
+	h.serveFeed(w, r, exchange, "", format)
+}
+
+// GetCategoryFeed handles GET /feeds/{exchange}/{feed}, e.g. /feeds/coinbase/withdrawal.atom
+func (h *FeedHandler) GetCategoryFeed(w http.ResponseWriter, r *http.Request) {
+	name, format, ok := splitFeedName(r.PathValue("feed"))
+	if !ok {
+		respondError(w, http.StatusNotFound, "Feed not found; use a .atom or .rss extension")
+		return
+	}
+	category, found := models.LookupCategory(name)
+	if !found {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("Unknown category: %s", name))
+		return
+	}
+	h.serveFeed(w, r, r.PathValue("exchange"), category.ID, format)
+}
+
+// serveFeed writes the issues matching exchange and category (empty = any) in format
+func (h *FeedHandler) serveFeed(w http.ResponseWriter, r *http.Request, exchange, category, format string) {
+	var issues []*models.Issue
+	for _, issue := range h.resolutionService.ListIssues("") {
+		if !visibleTo(r, issue.Tenant) {
+			continue
+		}
+		if exchange != FeedAllExchanges && !strings.EqualFold(issue.Exchange, exchange) {
+			continue
+		}
+		if category != "" && issue.Category != category {
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if !issues[i].LastUpdated.Equal(issues[j].LastUpdated) {
+			return issues[i].LastUpdated.After(issues[j].LastUpdated)
+		}
+		return issues[i].ID < issues[j].ID
+	})
+	if len(issues) > FeedSize {
+		issues = issues[:FeedSize]
+	}
+
+	title := "Coinsights: " + exchange + " issues"
+	if exchange == FeedAllExchanges {
+		title = "Coinsights: issues on all exchanges"
+	}
+	if category != "" {
+		title += " — " + models.CategoryName(category)
+	}
+	base := requestBaseURL(r)
+
+	var body any
+	contentType := "application/atom+xml; charset=utf-8"
+	if format == "rss" {
+		body = rssFeed(title, base, r.URL.Path, issues)
+		contentType = "application/rss+xml; charset=utf-8"
+	} else {
+		body = atomFeed(title, base, r.URL.Path, issues)
+	}
+
+	out, err := xml.MarshalIndent(body, "", "  ")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to render feed")
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(out)
+}
+
+// splitFeedName splits "coinbase.atom" into "coinbase" and "atom"
+func splitFeedName(name string) (string, string, bool) {
+	for _, format := range []string{"atom", "rss"} {
+		if base, ok := strings.CutSuffix(name, "."+format); ok && base != "" {
+			return base, format, true
+		}
+	}
+	return "", "", false
+}
+
+// requestBaseURL is the scheme and host the request reached the API on,
+// honouring X-Forwarded-Proto from a reverse proxy
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// feedEntryTitle is "[severity] title (status)"
+func feedEntryTitle(issue *models.Issue) string {
+	return fmt.Sprintf("[%s] %s (%s)", issue.Severity, issue.Title, issue.Status)
+}
+
+// feedEntrySummary describes the issue, and its resolution once there is one
+func feedEntrySummary(issue *models.Issue) string {
+	summary := fmt.Sprintf("%s\n\n%d complaints on %s about %s.",
+		issue.Description, issue.ComplaintCount, issue.Exchange, models.CategoryName(issue.Category))
+	if issue.Resolution != nil && issue.Resolution.Summary != "" {
+		summary += "\n\nResolution: " + issue.Resolution.Summary
+	}
+	if issue.Attestation != nil && issue.Attestation.ExplorerURL != "" {
+		summary += "\n\nAttested on-chain: " + issue.Attestation.ExplorerURL
+	}
+	return summary
+}
+
+// ============================================
+// ATOM
+// ============================================
+
+type atomFeedXML struct {
+	XMLName xml.Name       `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string         `xml:"id"`
+	Title   string         `xml:"title"`
+	Updated string         `xml:"updated"`
+	Links   []atomLinkXML  `xml:"link"`
+	Entries []atomEntryXML `xml:"entry"`
+}
+
+type atomLinkXML struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntryXML struct {
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Updated  string      `xml:"updated"`
+	Link     atomLinkXML `xml:"link"`
+	Category struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+	Summary string `xml:"summary"`
+}
+
+func atomFeed(title, base, path string, issues []*models.Issue) atomFeedXML {
+	feed := atomFeedXML{
+		ID:      base + path,
+		Title:   title,
+		Updated: feedUpdated(issues).Format(time.RFC3339),
+		Links:   []atomLinkXML{{Href: base + path, Rel: "self"}},
+		Entries: make([]atomEntryXML, len(issues)),
+	}
+	for i, issue := range issues {
+		entry := atomEntryXML{
+			ID:      "urn:coinsights:issue:" + issue.ID,
+			Title:   feedEntryTitle(issue),
+			Updated: issue.LastUpdated.UTC().Format(time.RFC3339),
+			Link:    atomLinkXML{Href: base + "/api/issues/" + issue.ID},
+			Summary: feedEntrySummary(issue),
+		}
+		entry.Category.Term = issue.Category
+		feed.Entries[i] = entry
+	}
+	return feed
+}
+
+// ============================================
+// RSS 2.0
+// ============================================
+
+type rssFeedXML struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	Channel rssChannelXML `xml:"channel"`
+}
+
+type rssChannelXML struct {
+	Title         string       `xml:"title"`
+	Link          string       `xml:"link"`
+	Description   string       `xml:"description"`
+	LastBuildDate string       `xml:"lastBuildDate"`
+	Items         []rssItemXML `xml:"item"`
+}
+
+type rssGUIDXML struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItemXML struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	GUID        rssGUIDXML `xml:"guid"`
+	PubDate     string     `xml:"pubDate"`
+	Category    string     `xml:"category"`
+	Description string     `xml:"description"`
+}
+
+func rssFeed(title, base, path string, issues []*models.Issue) rssFeedXML {
+	feed := rssFeedXML{
+		Version: "2.0",
+		Channel: rssChannelXML{
+			Title:         title,
+			Link:          base + path,
+			Description:   title,
+			LastBuildDate: feedUpdated(issues).Format(time.RFC1123Z),
+			Items:         make([]rssItemXML, len(issues)),
+		},
+	}
+	for i, issue := range issues {
+		feed.Channel.Items[i] = rssItemXML{
+			Title:       feedEntryTitle(issue),
+			Link:        base + "/api/issues/" + issue.ID,
+			GUID:        rssGUIDXML{Value: "urn:coinsights:issue:" + issue.ID},
+			PubDate:     issue.LastUpdated.UTC().Format(time.RFC1123Z),
+			Category:    issue.Category,
+			Description: feedEntrySummary(issue),
+		}
+	}
+	return feed
+}
+
+// feedUpdated is when the newest issue in a feed changed, or now for an empty feed
+func feedUpdated(issues []*models.Issue) time.Time {
+	if len(issues) == 0 {
+		return time.Now().UTC()
+	}
+	return issues[0].LastUpdated.UTC()
+}
@@ -0,0 +1,458 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tasnint/coinsights/internal/api/apitest"
+	"github.com/tasnint/coinsights/internal/api/handlers"
+	"github.com/tasnint/coinsights/internal/api/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current responses")
+
+// request is one call in a golden test: the response is compared with testdata/<name>.golden.json
+type request struct {
+	name   string
+	method string
+	path   string
+	body   any
+	key    string // Defaults to apitest.OperatorKey; "-" sends no key
+}
+
+// run sends each request in order against srv and checks its golden file
+func run(t *testing.T, srv *apitest.Server, requests []request) {
+	t.Helper()
+	for _, req := range requests {
+		srv.AssertGolden(t, send(t, srv, req), req.name)
+	}
+}
+
+// send issues one request with its key
+func send(t *testing.T, srv *apitest.Server, req request) *httptest.ResponseRecorder {
+	t.Helper()
+	key := req.key
+	switch key {
+	case "":
+		key = apitest.OperatorKey
+	case "-":
+		key = ""
+	}
+	return srv.DoAs(t, key, req.method, req.path, req.body)
+}
+
+func newServer(t *testing.T, chain bool) *apitest.Server {
+	opts := apitest.Options{Update: *update}
+	if chain {
+		opts.Chain = apitest.NewMockChain(t)
+	}
+	return apitest.NewServer(t, apitest.FixtureDir, opts)
+}
+
+func TestDataEndpoints(t *testing.T) {
+	srv := newServer(t, false)
+	run(t, srv, []request{
+		{name: "data/stats", method: "GET", path: "/api/stats"},
+		{name: "data/youtube", method: "GET", path: "/api/analysis/youtube"},
+		{name: "data/youtube_divergence", method: "GET", path: "/api/analysis/youtube/divergence"},
+		{name: "data/gemini", method: "GET", path: "/api/analysis/gemini"},
+		{name: "data/complaints", method: "GET", path: "/api/complaints"},
+		{name: "data/complaints_versions", method: "GET", path: "/api/complaints/versions"},
+		{name: "data/categories", method: "GET", path: "/api/categories"},
+		{name: "data/categories_cooccurrence", method: "GET", path: "/api/categories/cooccurrence"},
+		{name: "data/topics", method: "GET", path: "/api/topics"},
+		{name: "data/topic_timeline_unknown", method: "GET", path: "/api/topics/nope/timeline"},
+		{name: "data/subreddits", method: "GET", path: "/api/reddit/subreddits"},
+		{name: "data/trends", method: "GET", path: "/api/trends"},
+		{name: "data/reload", method: "POST", path: "/api/admin/reload"},
+		{name: "data/dashboard", method: "GET", path: "/api/dashboard"},
+		{name: "data/search", method: "GET", path: "/api/search?q=withdrawal"},
+		{name: "data/scorecard", method: "GET", path: "/api/exchanges/coinbase/scorecard"},
+		{name: "data/scorecard_history", method: "GET", path: "/api/exchanges/coinbase/scorecard/history"},
+		{name: "data/ratings", method: "GET", path: "/api/exchanges/coinbase/ratings"},
+		{name: "data/compare", method: "GET", path: "/api/exchanges/compare?exchanges=coinbase,kraken"},
+		{name: "data/compare_one", method: "GET", path: "/api/exchanges/compare?exchanges=coinbase"},
+		{name: "data/announcements", method: "GET", path: "/api/announcements"},
+		{name: "data/scrape_runs", method: "GET", path: "/api/scrape-runs"},
+		{name: "data/scrape_run_unknown", method: "GET", path: "/api/scrape-runs/nope/events"},
+		{name: "data/signing_key", method: "GET", path: "/.well-known/coinsights-signing-key", key: "-"},
+	})
+}
+
+// TestYouTubeAnalysisStreams checks the analysis bypasses the response cache, which
+// would buffer the whole body before any of it reached the client
+func TestYouTubeAnalysisStreams(t *testing.T) {
+	srv := newServer(t, false)
+	for range 2 {
+		rec := send(t, srv, request{method: "GET", path: "/api/analysis/youtube"})
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Cache") != "" {
+			t.Fatalf("answered %d with X-Cache %q, want 200 straight from the handler", rec.Code, rec.Header().Get("X-Cache"))
+		}
+	}
+}
+
+func TestIssueEndpoints(t *testing.T) {
+	srv := newServer(t, false)
+	run(t, srv, []request{
+		{name: "issues/list", method: "GET", path: "/api/issues"},
+		{name: "issues/list_archived", method: "GET", path: "/api/issues?archived=only"},
+		{name: "issues/get", method: "GET", path: "/api/issues/" + apitest.OpenIssueID},
+		{name: "issues/get_unknown", method: "GET", path: "/api/issues/nope"},
+		{name: "issues/projection", method: "GET", path: "/api/issues/" + apitest.OpenIssueID + "/projection"},
+		{name: "issues/create", method: "POST", path: "/api/issues", body: map[string]any{
+			"id": "issue-new", "exchange": "coinbase", "category": "fees", "title": "Hidden staking fees",
+			"description": "Staking rewards are lower than the advertised rate", "complaint_count": 9, "severity": "medium",
+		}},
+		{name: "issues/create_invalid", method: "POST", path: "/api/issues", body: map[string]any{"unknown": true}},
+		{name: "issues/archive", method: "POST", path: "/api/issues/issue-new/archive", body: map[string]any{
+			"archived_by": "ops", "reason": "duplicate",
+		}},
+		{name: "issues/unarchive", method: "POST", path: "/api/issues/issue-new/unarchive", body: map[string]any{}},
+		{name: "issues/archive_unknown", method: "POST", path: "/api/issues/nope/archive", body: map[string]any{"archived_by": "ops"}},
+		{name: "issues/unarchive_unknown", method: "POST", path: "/api/issues/nope/unarchive", body: map[string]any{}},
+		{name: "issues/evidence", method: "POST", path: "/api/issues/" + apitest.OpenIssueID + "/evidence", body: map[string]any{
+			"start": "2026-01-08T00:00:00Z", "end": "2026-01-15T00:00:00Z",
+		}},
+		{name: "issues/evidence_bundle_unknown", method: "GET", path: "/api/evidence/0x" + strings.Repeat("ab", 32)},
+		{name: "issues/feed_atom", method: "GET", path: "/feeds/coinbase.atom"},
+		{name: "issues/feed_category_rss", method: "GET", path: "/feeds/coinbase/login.rss"},
+		{name: "issues/feed_unknown", method: "GET", path: "/feeds/coinbase"},
+	})
+
+	// The brief's counts cover the 30 days up to now
+	rec := srv.Do(t, "GET", "/api/issues/"+apitest.ResolvedIssueID+"/brief.md", nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "# Login loops after 2FA\n") ||
+		!strings.Contains(rec.Body.String(), "## Resolution") {
+		t.Errorf("brief answered %d:\n%s", rec.Code, rec.Body)
+	}
+}
+
+func TestResolutionEndpoints(t *testing.T) {
+	srv := newServer(t, false)
+	fixtures := srv.Fixtures
+	run(t, srv, []request{
+		{name: "resolutions/list", method: "GET", path: "/api/resolutions"},
+		{name: "resolutions/methodologies", method: "GET", path: "/api/resolutions/methodologies"},
+		{name: "resolutions/criteria", method: "GET", path: "/api/resolutions/criteria"},
+		{name: "resolutions/criteria_update", method: "PUT", path: "/api/resolutions/criteria", body: map[string]any{
+			"min_complaint_decrease": 0.5,
+		}},
+		{name: "resolutions/get", method: "GET", path: "/api/resolutions/" + fixtures.Resolution},
+		{name: "resolutions/get_unknown", method: "GET", path: "/api/resolutions/nope"},
+		{name: "resolutions/attestation_none", method: "GET", path: "/api/resolutions/" + fixtures.Resolution + "/attestation"},
+		{name: "resolutions/preflight", method: "GET", path: "/api/resolutions/" + fixtures.Resolution + "/preflight"},
+		{name: "resolutions/review", method: "POST", path: "/api/resolutions/" + fixtures.ReviewResolution + "/review", body: map[string]any{
+			"approve": true, "reviewed_by": "ops", "note": "Ticket backlog cleared",
+		}},
+		{name: "resolutions/announcements", method: "POST", path: "/api/resolutions/" + fixtures.Resolution + "/announcements"},
+		{name: "resolutions/create_invalid", method: "POST", path: "/api/resolutions", body: map[string]any{
+			"issue_id": apitest.OpenIssueID, "summary": "Faster withdrawals",
+			"evidence": map[string]any{
+				"complaints_before": 10, "complaints_after": 20, "percentage_decrease": 0,
+				"measurement_start": "2026-01-01T00:00:00Z", "measurement_end": "2026-01-15T00:00:00Z",
+			},
+		}},
+		{name: "resolutions/hash", method: "POST", path: "/api/blockchain/hash", body: map[string]any{
+			"complaints_before": 30, "complaints_after": 3, "percentage_decrease": 0.9,
+			"measurement_start": "2026-01-17T00:00:00Z", "measurement_end": "2026-01-31T00:00:00Z",
+		}},
+	})
+
+	// The spec is served as embedded, so there's no point snapshotting it
+	if rec := send(t, srv, request{method: "GET", path: "/api/openapi.json", key: "-"}); rec.Code != http.StatusOK || !bytes.Equal(rec.Body.Bytes(), openapi.Raw()) {
+		t.Errorf("GET /api/openapi.json answered %d with %d bytes, want the embedded spec", rec.Code, rec.Body.Len())
+	}
+
+	// Snapshots are sorted by ID, and resolution IDs are random
+	rec := srv.Do(t, "GET", "/api/mirror/snapshot", nil)
+	var snapshot struct {
+		Issues      []map[string]any `json:"issues"`
+		Resolutions []map[string]any `json:"resolutions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("snapshot answered %d: %s", rec.Code, rec.Body)
+	}
+	if len(snapshot.Issues) != 5 || len(snapshot.Resolutions) != 2 {
+		t.Errorf("snapshot has %d issues and %d resolutions, want 5 and 2", len(snapshot.Issues), len(snapshot.Resolutions))
+	}
+}
+
+func TestAttestationEndpointsWithoutChain(t *testing.T) {
+	srv := newServer(t, false)
+	run(t, srv, []request{
+		{name: "nochain/attest", method: "POST", path: "/api/attestations", body: map[string]any{"resolution_id": srv.Fixtures.Resolution}},
+		{name: "nochain/verify", method: "POST", path: "/api/attestations/verify", body: map[string]any{"resolution_id": srv.Fixtures.Resolution}},
+		{name: "nochain/info", method: "GET", path: "/api/blockchain/info"},
+		{name: "nochain/chain_count", method: "GET", path: "/api/chain/count"},
+	})
+}
+
+func TestAttestationEndpoints(t *testing.T) {
+	srv := newServer(t, true)
+	fixtures := srv.Fixtures
+	run(t, srv, []request{
+		{name: "chain/attestation", method: "GET", path: "/api/resolutions/" + fixtures.Resolution + "/attestation"},
+		{name: "chain/attest_again", method: "POST", path: "/api/attestations", body: map[string]any{"resolution_id": fixtures.Resolution}},
+		{name: "chain/attest_review", method: "POST", path: "/api/resolutions/" + fixtures.ReviewResolution + "/review", body: map[string]any{
+			"approve": true, "reviewed_by": "ops",
+		}},
+		{name: "chain/attest", method: "POST", path: "/api/attestations", body: map[string]any{"resolution_id": fixtures.ReviewResolution}},
+		{name: "chain/attest_reader", method: "POST", path: "/api/attestations", body: map[string]any{"resolution_id": fixtures.ReviewResolution}, key: apitest.ReaderKey},
+		{name: "chain/verify", method: "POST", path: "/api/attestations/verify", body: map[string]any{"resolution_id": fixtures.Resolution}},
+		{name: "chain/verify_hash", method: "POST", path: "/api/attestations/verify", body: map[string]any{"evidence_hash": fixtures.EvidenceHash}},
+		{name: "chain/verify_batch", method: "POST", path: "/api/attestations/verify/batch", body: map[string]any{
+			"resolution_ids":  []string{fixtures.Resolution, fixtures.ReviewResolution},
+			"evidence_hashes": []string{"0x" + strings.Repeat("00", 32)},
+		}},
+		{name: "chain/list", method: "GET", path: "/api/attestations"},
+		{name: "chain/queue", method: "GET", path: "/api/attestations/queue"},
+		{name: "chain/export", method: "GET", path: "/api/attestations/export"},
+		{name: "chain/info", method: "GET", path: "/api/blockchain/info"},
+		{name: "chain/attestors", method: "GET", path: "/api/blockchain/attestors"},
+		{name: "chain/stats", method: "GET", path: "/api/blockchain/stats"},
+		{name: "chain/cost_estimate", method: "GET", path: "/api/blockchain/cost-estimate"},
+		{name: "chain/chain_attestation", method: "GET", path: "/api/chain/attestations/0"},
+		{name: "chain/chain_attestation_unknown", method: "GET", path: "/api/chain/attestations/9"},
+		{name: "chain/chain_count", method: "GET", path: "/api/chain/count"},
+		{name: "chain/evidence_bundle", method: "GET", path: "/api/evidence/" + fixtures.EvidenceHash},
+	})
+
+	// The demo measures a window ending now, so its evidence hash changes every run
+	rec := srv.Do(t, "POST", "/api/demo/full-workflow", nil)
+	var demo struct {
+		Issue       map[string]any `json:"issue"`
+		Resolution  map[string]any `json:"resolution"`
+		Attestation map[string]any `json:"attestation"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &demo); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("demo workflow answered %d: %s", rec.Code, rec.Body)
+	}
+	if demo.Issue["status"] != "resolved" || demo.Resolution["status"] != "verified" {
+		t.Errorf("demo workflow left issue %v and resolution %v", demo.Issue["status"], demo.Resolution["status"])
+	}
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	srv := newServer(t, false)
+	run(t, srv, []request{
+		{name: "admin/query_stats", method: "GET", path: "/api/admin/queries/stats"},
+		{name: "admin/scrapers_status", method: "GET", path: "/api/admin/scrapers/status"},
+		{name: "admin/keyword_suggestions", method: "GET", path: "/api/admin/keywords/suggestions"},
+		{name: "admin/keyword_effectiveness", method: "GET", path: "/api/admin/keywords/effectiveness"},
+		{name: "admin/sample", method: "GET", path: "/api/admin/sample?n=3&seed=7"},
+		{name: "admin/labels", method: "GET", path: "/api/admin/sample/labels"},
+		{name: "admin/labels_record_invalid", method: "POST", path: "/api/admin/sample/labels", body: map[string]any{"labels": []any{}}},
+		{name: "admin/taxonomy", method: "GET", path: "/api/admin/categories"},
+		{name: "admin/taxonomy_create", method: "POST", path: "/api/admin/categories", body: map[string]any{
+			"id": "staking", "name": "Staking", "severity": "medium", "aliases": []string{"staking rewards"}, "keywords": []string{"staking"},
+		}},
+		{name: "admin/taxonomy_get", method: "GET", path: "/api/admin/categories/staking"},
+		{name: "admin/taxonomy_update", method: "PUT", path: "/api/admin/categories/staking", body: map[string]any{
+			"id": "staking", "name": "Staking rewards", "severity": "low", "aliases": []string{}, "keywords": []string{"staking"},
+		}},
+		{name: "admin/taxonomy_keywords", method: "POST", path: "/api/admin/categories/staking/keywords", body: map[string]any{"keywords": []string{"apy"}}},
+		{name: "admin/taxonomy_keyword_delete", method: "DELETE", path: "/api/admin/categories/staking/keywords/apy"},
+		{name: "admin/taxonomy_delete", method: "DELETE", path: "/api/admin/categories/staking"},
+		{name: "admin/scraper_settings", method: "GET", path: "/api/admin/scraper-settings"},
+		{name: "admin/scraper_settings_invalid", method: "PUT", path: "/api/admin/scraper-settings", body: map[string]any{"unknown": 1}},
+		{name: "admin/preview_invalid", method: "POST", path: "/api/admin/scrape/preview", body: map[string]any{"source": "youtube", "query": " "}},
+		{name: "admin/tenants", method: "GET", path: "/api/admin/tenants"},
+		{name: "admin/tenant_put", method: "PUT", path: "/api/admin/tenants/globex", body: map[string]any{"name": "Globex", "exchanges": []string{"kraken"}}},
+		{name: "admin/tenant_delete", method: "DELETE", path: "/api/admin/tenants/globex"},
+		{name: "admin/reader_forbidden", method: "PUT", path: "/api/admin/tenants/globex", body: map[string]any{"name": "Globex"}, key: apitest.ReaderKey},
+		{name: "admin/anonymous_write", method: "POST", path: "/api/admin/reload", key: "-"},
+	})
+}
+
+// TestAdminReadsNeedOperatorKey checks every documented GET /api/admin/ route refuses
+// anonymous callers, readers and tenant keys even though reads are otherwise open
+func TestAdminReadsNeedOperatorKey(t *testing.T) {
+	srv := newServer(t, false)
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checked := 0
+	for template, ops := range spec.Paths {
+		if _, ok := ops["get"]; !ok || !strings.HasPrefix(template, "/api/admin/") {
+			continue
+		}
+		path := regexp.MustCompile(`\{[^}]+\}`).ReplaceAllString(template, "x")
+		for _, tt := range []struct {
+			key  string
+			want int
+		}{
+			{"-", http.StatusUnauthorized},
+			{apitest.ReaderKey, http.StatusForbidden},
+			{apitest.TenantKey, http.StatusForbidden},
+		} {
+			if rec := send(t, srv, request{method: "GET", path: path, key: tt.key}); rec.Code != tt.want {
+				t.Errorf("GET %s with key %q answered %d, want %d", path, tt.key, rec.Code, tt.want)
+			}
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatal("no GET /api/admin/ routes in the spec")
+	}
+}
+
+// TestBodyLimitAboveDefault checks a MAX_REQUEST_BODY_BYTES above a megabyte lets
+// bodies that size through the spec validator, and still refuses larger ones
+func TestBodyLimitAboveDefault(t *testing.T) {
+	limits := handlers.DefaultBodyLimits()
+	limits.MaxBytes = 4 << 20
+	srv := apitest.NewServer(t, apitest.FixtureDir, apitest.Options{BodyLimits: &limits})
+
+	issue := func(description string) map[string]any {
+		return map[string]any{"exchange": "kraken", "category": "fees", "title": "Large report", "description": description}
+	}
+	rec := send(t, srv, request{method: "POST", path: "/api/issues?force=true", body: issue(strings.Repeat("a", 2<<20))})
+	if rec.Code != http.StatusCreated {
+		t.Errorf("2 MiB body answered %d, want 201: %.200s", rec.Code, rec.Body)
+	}
+	rec = send(t, srv, request{method: "POST", path: "/api/issues?force=true", body: issue(strings.Repeat("a", 5<<20))})
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("5 MiB body answered %d, want 413", rec.Code)
+	}
+}
+
+func TestSubscriptionEndpoints(t *testing.T) {
+	srv := newServer(t, false)
+
+	rec := srv.Do(t, "POST", "/api/issues/"+apitest.OpenIssueID+"/watch", map[string]any{
+		"user": "alice", "channel": "email", "target": "alice@example.com",
+	})
+	created(t, srv, rec, "<watch>")
+	srv.AssertGolden(t, rec, "subscriptions/watch")
+	rec = srv.Do(t, "POST", "/api/subscriptions", map[string]any{
+		"user": "bob", "exchange": "coinbase", "channel": "email", "target": "bob@example.com",
+	})
+	id := created(t, srv, rec, "<subscription>")
+	srv.AssertGolden(t, rec, "subscriptions/create")
+
+	run(t, srv, []request{
+		{name: "subscriptions/watch_unknown", method: "POST", path: "/api/issues/nope/watch", body: map[string]any{
+			"user": "alice", "channel": "email", "target": "alice@example.com",
+		}},
+		{name: "subscriptions/unwatch", method: "POST", path: "/api/issues/" + apitest.OpenIssueID + "/unwatch", body: map[string]any{"user": "alice"}},
+		{name: "subscriptions/list", method: "GET", path: "/api/subscriptions?user=bob"},
+		{name: "subscriptions/list_anonymous", method: "GET", path: "/api/subscriptions?user=bob", key: "-"},
+		{name: "subscriptions/list_other_owner", method: "GET", path: "/api/subscriptions?user=bob", key: apitest.TenantKey},
+		{name: "subscriptions/delete_other_owner", method: "DELETE", path: "/api/subscriptions/" + id, key: apitest.TenantKey},
+		{name: "subscriptions/webhook_invalid", method: "POST", path: "/api/subscriptions", body: map[string]any{
+			"user": "bob", "channel": "webhook", "target": "file:///etc/passwd",
+		}},
+		{name: "subscriptions/email_invalid", method: "POST", path: "/api/subscriptions", body: map[string]any{
+			"user": "bob", "channel": "email", "target": "bob@example.com\r\nBcc: everyone@example.com",
+		}},
+		{name: "subscriptions/delete", method: "DELETE", path: "/api/subscriptions/" + id},
+		{name: "subscriptions/delete_unknown", method: "DELETE", path: "/api/subscriptions/nope"},
+	})
+}
+
+// created returns the ID in a create response and aliases it as placeholder in golden files
+func created(t *testing.T, srv *apitest.Server, rec *httptest.ResponseRecorder, placeholder string) string {
+	t.Helper()
+	var record struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil || record.ID == "" {
+		t.Fatalf("create answered %d without an ID: %s", rec.Code, rec.Body)
+	}
+	srv.Alias(record.ID, placeholder)
+	return record.ID
+}
+
+func TestIngestEndpoint(t *testing.T) {
+	srv := newServer(t, false)
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/ingest/complaints", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	srv.AssertGolden(t, post(apitest.IngestKey,
+		`{"source_id":"p-1","description":"Withdrawal stuck for a week","category":"withdrawal","published_at":"2026-01-10T00:00:00Z"}`+"\n"+
+			`{"source_id":"p-2","description":""}`+"\n"), "ingest/complaints")
+	srv.AssertGolden(t, post("wrong-key", `{"text":"x"}`), "ingest/unauthorized")
+	if rec := post("", `{"text":"x"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("ingest without a key answered %d, want 401", rec.Code)
+	}
+}
+
+func TestTenantScoping(t *testing.T) {
+	srv := newServer(t, false)
+	run(t, srv, []request{
+		{name: "tenants/issues", method: "GET", path: "/api/issues", key: apitest.TenantKey},
+		{name: "tenants/own_issue", method: "GET", path: "/api/issues/" + apitest.TenantIssueID, key: apitest.TenantKey},
+		{name: "tenants/other_issue", method: "GET", path: "/api/issues/" + apitest.OpenIssueID, key: apitest.TenantKey},
+		{name: "tenants/anonymous_issue", method: "GET", path: "/api/issues/" + apitest.TenantIssueID, key: "-"},
+		{name: "tenants/closed_route", method: "GET", path: "/api/dashboard", key: apitest.TenantKey},
+		{name: "tenants/anonymous_stats", method: "GET", path: "/api/blockchain/stats", key: "-"},
+		{name: "tenants/watch_other_issue", method: "POST", path: "/api/issues/" + apitest.OpenIssueID + "/watch", key: apitest.TenantKey,
+			body: map[string]any{"user": "analyst@acme.test", "channel": "email", "target": "analyst@acme.test"}},
+		{name: "tenants/anonymous_watch", method: "POST", path: "/api/issues/" + apitest.TenantIssueID + "/watch", key: "-",
+			body: map[string]any{"user": "someone", "channel": "email", "target": "someone@example.test"}},
+		{name: "tenants/subscribe_other_issue", method: "POST", path: "/api/subscriptions", key: apitest.TenantKey,
+			body: map[string]any{"user": "analyst@acme.test", "issue_id": apitest.OpenIssueID, "channel": "email", "target": "analyst@acme.test"}},
+		{name: "tenants/evidence_other_issue", method: "POST", path: "/api/issues/" + apitest.OpenIssueID + "/evidence", key: apitest.TenantKey,
+			body: map[string]any{"start": "2026-01-01T00:00:00Z", "end": "2026-01-15T00:00:00Z"}},
+	})
+
+	rec := send(t, srv, request{method: "POST", path: "/api/issues/" + apitest.TenantIssueID + "/watch", key: apitest.TenantKey,
+		body: map[string]any{"user": "analyst@acme.test", "channel": "email", "target": "analyst@acme.test"}})
+	created(t, srv, rec, "<watch>")
+	srv.AssertGolden(t, rec, "tenants/watch_own_issue")
+
+	// The operator's cached dashboard includes the tenant's issue; an anonymous caller's must not
+	for _, key := range []string{"", "-", ""} {
+		rec := send(t, srv, request{method: "GET", path: "/api/dashboard", key: key})
+		var dashboard struct {
+			Stats     map[string]any   `json:"stats"`
+			TopIssues []map[string]any `json:"top_issues"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &dashboard); err != nil {
+			t.Fatalf("dashboard answered %d: %s", rec.Code, rec.Body)
+		}
+		tenantIssue := slices.ContainsFunc(dashboard.TopIssues, func(issue map[string]any) bool { return issue["id"] == apitest.TenantIssueID })
+		wantIssues := 5.0
+		if key == "-" {
+			wantIssues = 4
+		}
+		if tenantIssue != (key == "") || dashboard.Stats["total_issues"] != wantIssues {
+			t.Errorf("dashboard for key %q: tenant issue listed %v, %v issues, want %v", key, tenantIssue, dashboard.Stats["total_issues"], wantIssues)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	primary := newServer(t, true)
+	upstream := httptest.NewServer(primary.Handler)
+	defer upstream.Close()
+
+	mirror := apitest.NewServer(t, apitest.FixtureDir, apitest.Options{Primary: upstream.URL, Update: *update})
+	mirror.Alias(upstream.URL, "<primary>")
+	if err := mirror.Mirror.Sync(t.Context()); err != nil {
+		t.Fatalf("mirror sync failed: %v", err)
+	}
+	mirror.Alias(primary.Fixtures.Resolution, "<resolution>")
+	mirror.Alias(primary.Fixtures.ReviewResolution, "<review-resolution>")
+	run(t, mirror, []request{
+		{name: "mirror/status", method: "GET", path: "/api/mirror/status"},
+		{name: "mirror/resolutions", method: "GET", path: "/api/resolutions"},
+		{name: "mirror/write", method: "POST", path: "/api/issues", body: map[string]any{"exchange": "coinbase", "category": "fees"}},
+	})
+}
@@ -0,0 +1,166 @@
+// API for partners to push complaints Coinsights can't scrape itself
+package handlers
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/ingest"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/tenants"
+)
+
+const (
+	// MaxIngestBodyBytes caps one ND-JSON upload
+	MaxIngestBodyBytes = 32 << 20
+	// MaxIngestRecords caps the records in one upload; split larger feeds into several requests
+	MaxIngestRecords = 10000
+	// maxIngestLineBytes caps a single ND-JSON line
+	maxIngestLineBytes = 64 << 10
+	// maxIngestErrors caps the per-line errors echoed back
+	maxIngestErrors = 100
+)
+
+// IngestHandler handles partner complaint ingestion
+type IngestHandler struct {
+	store   *ingest.Store
+	keys    ingest.Keys
+	data    *DataHandler   // Reloaded after each upload so ingested complaints reach the analysis
+	tenants *tenants.Store // Optional: partners' complaints belong to the tenant listing them
+}
+
+// NewIngestHandler creates a new ingest handler. With no keys every request is rejected.
+func NewIngestHandler(store *ingest.Store, keys ingest.Keys, data *DataHandler) *IngestHandler {
+	return &IngestHandler{
+		store: store,
+		keys:  keys,
+		data:  data,
+	}
+}
+
+// SetTenants files each partner's complaints under the tenant in store that lists
+// the partner; they then stay out of the deployment's own analysis
+func (h *IngestHandler) SetTenants(store *tenants.Store) {
+	h.tenants = store
+}
+
+// IngestLineError is a rejected ND-JSON line
+type IngestLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// IngestComplaints handles POST /api/ingest/complaints
+// The body is ND-JSON, one ingest.Record per line. Authenticate with
+// "Authorization: Bearer <key>" or "X-API-Key: <key>"; the key decides the source attribution.
+func (h *IngestHandler) IngestComplaints(w http.ResponseWriter, r *http.Request) {
+	partner, ok := h.partner(r)
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "Missing or invalid API key")
+		return
+	}
+
+	now := time.Now()
+	runID := models.NewRunID(now)
+	records, complaints := 0, []models.Complaint{}
+	lineErrors := []IngestLineError{}
+	rejected := 0
+	tenant := ""
+	if h.tenants != nil {
+		tenant = h.tenants.ForPartner(partner)
+	}
+
+	scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, MaxIngestBodyBytes))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxIngestLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		records++
+		if records > MaxIngestRecords {
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("at most %d records per request", MaxIngestRecords))
+			return
+		}
+
+		var record ingest.Record
+		err := json.Unmarshal([]byte(text), &record)
+		if err == nil {
+			err = record.Validate(now)
+		}
+		if err != nil {
+			rejected++
+			if len(lineErrors) < maxIngestErrors {
+				lineErrors = append(lineErrors, IngestLineError{Line: line, Error: err.Error()})
+			}
+			continue
+		}
+		for _, complaint := range record.Complaints(partner, runID, now) {
+			complaint.Tenant = tenant
+			complaints = append(complaints, complaint)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxErr):
+			respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body is larger than %d bytes", MaxIngestBodyBytes))
+		case errors.Is(err, bufio.ErrTooLong):
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("line %d is longer than %d bytes", line+1, maxIngestLineBytes))
+		default:
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+		}
+		return
+	}
+	if records == 0 {
+		respondError(w, http.StatusBadRequest, "body must contain at least one ND-JSON record")
+		return
+	}
+
+	added, err := h.store.Add(complaints)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if added > 0 && h.data != nil {
+		if err := h.data.Reload(); err != nil {
+			fmt.Printf("⚠️  Reload after ingesting from %s failed: %v\n", partner, err)
+		}
+	}
+
+	fmt.Printf("📥 Ingested %d complaint(s) from %s (%d record(s) rejected)\n", added, partner, rejected)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"source":     ingest.SourcePrefix + partner,
+		"run_id":     runID,
+		"records":    records,
+		"accepted":   added,
+		"duplicates": len(complaints) - added,
+		"rejected":   rejected,
+		"errors":     lineErrors,
+	})
+}
+
+// partner returns the partner the request's API key belongs to
+func (h *IngestHandler) partner(r *http.Request) (string, bool) {
+	key := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); key == "" && strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if key == "" {
+		return "", false
+	}
+	for candidate, partner := range h.keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			return partner, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,190 @@
+// Filtering, sorting and paging shared by the list endpoints
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// LIST QUERIES
+// ============================================
+// GET /api/issues, GET /api/resolutions and GET /api/attestations take the same list
+// parameters: ?category=, ?severity=, ?status=, ?exchange= and ?attestor= (whichever
+// the endpoint's items have) keep the items matching every one
+// given, ?sort= orders them, and ?page= with ?limit= returns one page. Without
+// either paging parameter the whole filtered list is returned, as it was before
+// paging existed, so existing clients see no difference.
+
+const (
+	// DefaultListLimit is the page size when ?page= is given without ?limit=
+	DefaultListLimit = 50
+	// MaxListLimit caps ?limit=
+	MaxListLimit = 500
+)
+
+// listSpec describes how one list endpoint's items are filtered and sorted
+type listSpec[T any] struct {
+	filters     map[string]func(T) string   // Query parameter -> the item's value for it
+	sorts       map[string]func(a, b T) int // ?sort= value -> comparison, first item first
+	defaultSort string
+	id          func(T) string // Breaks sort ties so pages don't shift between requests
+}
+
+// listQuery is a parsed set of list parameters
+type listQuery struct {
+	filters map[string]string
+	sort    string
+	page    int
+	limit   int // 0 = everything
+}
+
+// parseListQuery reads the list parameters spec supports, responding 400 and
+// returning false if one is invalid
+func parseListQuery[T any](w http.ResponseWriter, r *http.Request, spec listSpec[T]) (listQuery, bool) {
+	params := r.URL.Query()
+	q := listQuery{filters: map[string]string{}, sort: spec.defaultSort, page: 1}
+
+	for name := range spec.filters {
+		if v := strings.TrimSpace(params.Get(name)); v != "" {
+			if name == "category" {
+				v = models.NormalizeCategory(v)
+			}
+			q.filters[name] = v
+		}
+	}
+
+	if v := params.Get("sort"); v != "" {
+		if _, ok := spec.sorts[v]; !ok {
+			names := make([]string, 0, len(spec.sorts))
+			for name := range spec.sorts {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			respondError(w, http.StatusBadRequest, "sort must be one of "+strings.Join(names, ", "))
+			return q, false
+		}
+		q.sort = v
+	}
+
+	if v := params.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondError(w, http.StatusBadRequest, "page must be a positive integer")
+			return q, false
+		}
+		q.page = n
+		q.limit = DefaultListLimit
+	}
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxListLimit {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+			return q, false
+		}
+		q.limit = n
+	}
+	return q, true
+}
+
+// apply filters and sorts items, returning the requested page and how many items matched
+func (spec listSpec[T]) apply(items []T, q listQuery) ([]T, int) {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if spec.matches(item, q) {
+			matched = append(matched, item)
+		}
+	}
+
+	compare := spec.sorts[q.sort]
+	slices.SortStableFunc(matched, func(a, b T) int {
+		if c := compare(a, b); c != 0 {
+			return c
+		}
+		return cmp.Compare(spec.id(a), spec.id(b))
+	})
+
+	if q.limit == 0 {
+		return matched, len(matched)
+	}
+	start := min((q.page-1)*q.limit, len(matched))
+	end := min(start+q.limit, len(matched))
+	return matched[start:end], len(matched)
+}
+
+// matches reports whether item has every filtered value, ignoring case
+func (spec listSpec[T]) matches(item T, q listQuery) bool {
+	for name, want := range q.filters {
+		if !strings.EqualFold(spec.filters[name](item), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// listResponse is a list endpoint's body: the page under key, its size, the number
+// of matching items and, when paging, the page and limit used
+func listResponse[T any](key string, page []T, total int, q listQuery) map[string]interface{} {
+	body := map[string]interface{}{
+		key:     page,
+		"count": len(page),
+		"total": total,
+	}
+	if q.limit > 0 {
+		body["page"] = q.page
+		body["limit"] = q.limit
+	}
+	return body
+}
+
+// issueList filters and sorts GET /api/issues: most complaints first by default
+var issueList = listSpec[models.Issue]{
+	filters: map[string]func(models.Issue) string{
+		"category": func(i models.Issue) string { return i.Category },
+		"severity": func(i models.Issue) string { return i.Severity },
+		"status":   func(i models.Issue) string { return i.Status },
+		"exchange": func(i models.Issue) string { return i.Exchange },
+	},
+	sorts: map[string]func(a, b models.Issue) int{
+		"count":          func(a, b models.Issue) int { return cmp.Compare(b.ComplaintCount, a.ComplaintCount) },
+		"first_detected": func(a, b models.Issue) int { return b.FirstDetected.Compare(a.FirstDetected) },
+	},
+	defaultSort: "count",
+	id:          func(i models.Issue) string { return i.ID },
+}
+
+// resolutionList filters and sorts GET /api/resolutions: newest first by default
+var resolutionList = listSpec[*models.Resolution]{
+	filters: map[string]func(*models.Resolution) string{
+		"category": func(r *models.Resolution) string { return r.IssueCategory },
+		"status":   func(r *models.Resolution) string { return r.Status },
+		"exchange": func(r *models.Resolution) string { return r.Exchange },
+	},
+	sorts: map[string]func(a, b *models.Resolution) int{
+		"created_at": func(a, b *models.Resolution) int { return b.CreatedAt.Compare(a.CreatedAt) },
+		"confidence": func(a, b *models.Resolution) int { return cmp.Compare(b.Confidence, a.Confidence) },
+	},
+	defaultSort: "created_at",
+	id:          func(r *models.Resolution) string { return r.ID },
+}
+
+// attestationList filters and sorts GET /api/attestations: newest first by default
+var attestationList = listSpec[models.Attestation]{
+	filters: map[string]func(models.Attestation) string{
+		"category": func(a models.Attestation) string { return models.NormalizeCategory(a.IssueCategory) },
+		"exchange": func(a models.Attestation) string { return a.Exchange },
+		"attestor": func(a models.Attestation) string { return a.Attestor },
+	},
+	sorts: map[string]func(a, b models.Attestation) int{
+		"id":              func(a, b models.Attestation) int { return cmp.Compare(b.ID, a.ID) },
+		"block_timestamp": func(a, b models.Attestation) int { return b.BlockTimestamp.Compare(a.BlockTimestamp) },
+	},
+	defaultSort: "id",
+	id:          func(a models.Attestation) string { return fmt.Sprintf("%020d", a.ID) },
+}
@@ -0,0 +1,72 @@
+// API for read-only mirrors: the snapshot primaries publish and the sync status mirrors report
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// ReadOnlyAllowed are the POST routes a read-only mirror still serves; they compute
+// or check a result without changing any state
+var ReadOnlyAllowed = map[string]bool{
+	"POST /api/attestations/verify":       true,
+	"POST /api/attestations/verify/batch": true,
+	"POST /api/blockchain/hash":           true,
+}
+
+// MirrorHandler reports the state of a read-only mirror
+type MirrorHandler struct {
+	mirror *services.Mirror
+}
+
+// NewMirrorHandler creates a new mirror handler
+func NewMirrorHandler(mirror *services.Mirror) *MirrorHandler {
+	return &MirrorHandler{mirror: mirror}
+}
+
+// GetMirrorSnapshot handles GET /api/mirror/snapshot
+// Every issue and resolution with its attestation, for mirrors to sync from.
+// Tenants' records are included only for operator keys.
+func (h *BlockchainHandler) GetMirrorSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot := h.resolutionService.Snapshot()
+	if _, all := requestScope(r); !all {
+		issues, resolutions := snapshot.Issues[:0], snapshot.Resolutions[:0]
+		for _, issue := range snapshot.Issues {
+			if visibleTo(r, issue.Tenant) {
+				issues = append(issues, issue)
+			}
+		}
+		for _, resolution := range snapshot.Resolutions {
+			if visibleTo(r, resolution.Tenant) {
+				resolutions = append(resolutions, resolution)
+			}
+		}
+		snapshot.Issues, snapshot.Resolutions = issues, resolutions
+	}
+	respondJSON(w, http.StatusOK, snapshot)
+}
+
+// GetStatus handles GET /api/mirror/status
+// The primary being mirrored, when it was last synced, and how many of the copied
+// attestations the mirror could verify itself
+func (h *MirrorHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.mirror.Status())
+}
+
+// ReadOnly wraps a mirror's handler so requests that would change state are refused.
+// Writes belong on the primary; the mirror would lose them on its next sync anyway.
+func ReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if !ReadOnlyAllowed[r.Method+" "+r.URL.Path] {
+				w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+				respondError(w, http.StatusMethodNotAllowed, "This is a read-only mirror; send changes to the primary instance")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+// ============================================
+// SCRAPE PREVIEW
+// ============================================
+// A new query or keyword is only tested by a full scheduled run today, which spends
+// a day's quota to find out it surfaces nothing. A preview runs one query against
+// one source with tiny limits and returns what came back inline - nothing is
+// written to the data directory, query history or scraper health - along with the
+// categories the current keywords would put each result in.
+
+const (
+	// MaxPreviewResults caps the videos, posts or search results a preview fetches
+	MaxPreviewResults = 5
+	// DefaultPreviewResults is the preview size when limit is omitted
+	DefaultPreviewResults = 3
+	// MaxPreviewComments caps the comments fetched per video or post
+	MaxPreviewComments = 5
+	// MaxPreviewQueryLength caps the query text
+	MaxPreviewQueryLength = 200
+)
+
+// Preview sources
+const (
+	PreviewYouTube = "youtube"
+	PreviewReddit  = "reddit"
+	PreviewGoogle  = "google"
+	PreviewGemini  = "gemini"
+)
+
+// ScrapePreviewRequest is the body of POST /api/admin/scrape/preview
+type ScrapePreviewRequest struct {
+	Source    string `json:"source"` // youtube, reddit, google or gemini
+	Query     string `json:"query"`
+	Subreddit string `json:"subreddit,omitempty"` // Reddit only; defaults to the first configured subreddit
+	Limit     int    `json:"limit,omitempty"`     // Results to fetch, 1-MaxPreviewResults
+	Comments  int    `json:"comments,omitempty"`  // YouTube and Reddit: comments per result, 0-MaxPreviewComments
+}
+
+// previewVideo is a previewed YouTube video with its comments
+type previewVideo struct {
+	models.YouTubeVideo
+	Comments []models.YouTubeComment `json:"comments,omitempty"`
+}
+
+// PreviewScrape handles POST /api/admin/scrape/preview
+// Runs one query against one source and returns the raw results without saving
+// anything. "categories" counts the results (and comments) each category's
+// keywords match; "quota_used" is the YouTube quota the preview spent.
+func (h *AdminHandler) PreviewScrape(w http.ResponseWriter, r *http.Request) {
+	var req ScrapePreviewRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	req.Query = strings.TrimSpace(req.Query)
+	switch {
+	case req.Query == "":
+		respondError(w, http.StatusBadRequest, "query is required")
+		return
+	case len(req.Query) > MaxPreviewQueryLength:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("query must be at most %d characters", MaxPreviewQueryLength))
+		return
+	case req.Limit < 0 || req.Limit > MaxPreviewResults:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", MaxPreviewResults))
+		return
+	case req.Comments < 0 || req.Comments > MaxPreviewComments:
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("comments must be between 0 and %d", MaxPreviewComments))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = DefaultPreviewResults
+	}
+
+	started := time.Now()
+	var results interface{}
+	var texts []string // Every result and comment text, for categorizing
+	quota := 0
+
+	switch req.Source {
+	case PreviewYouTube:
+		apiKey := os.Getenv("YOUTUBE_API_KEY")
+		if apiKey == "" {
+			respondError(w, http.StatusServiceUnavailable, "YOUTUBE_API_KEY not set")
+			return
+		}
+		scraper := scrapers.NewYouTubeScraper(apiKey)
+		found, err := scraper.SearchVideos(req.Query, req.Limit)
+		if err != nil {
+			respondError(w, previewErrorStatus(err), err.Error())
+			return
+		}
+		quota += config.YouTubeSearchCost
+		videos := make([]previewVideo, 0, len(found))
+		for _, video := range found {
+			v := previewVideo{YouTubeVideo: video}
+			texts = append(texts, video.Title+" "+video.Description)
+			if req.Comments > 0 {
+				comments, err := scraper.GetVideoComments(video.VideoID, req.Comments)
+				quota += config.YouTubeListCost
+				if err != nil {
+					fmt.Printf("⚠️  Preview: comments on %s: %v\n", video.VideoID, err)
+				}
+				for _, c := range comments {
+					texts = append(texts, c.Text)
+				}
+				v.Comments = comments
+			}
+			videos = append(videos, v)
+		}
+		results = videos
+
+	case PreviewReddit:
+		settings := scrapers.RedditSettingsFromEnv(config.DefaultRedditSettings())
+		subreddit := strings.TrimPrefix(strings.TrimSpace(req.Subreddit), "r/")
+		if subreddit == "" && len(settings.Subreddits) > 0 {
+			subreddit = settings.Subreddits[0]
+		}
+		if subreddit == "" {
+			respondError(w, http.StatusBadRequest, "subreddit is required")
+			return
+		}
+		scraper := scrapers.NewRedditScraperFromEnv()
+		posts, err := scraper.Search(r.Context(), subreddit, req.Query, req.Limit, settings.TimeWindow)
+		if err != nil {
+			respondError(w, previewErrorStatus(err), err.Error())
+			return
+		}
+		for i := range posts {
+			texts = append(texts, posts[i].Title+" "+posts[i].Body)
+			if req.Comments > 0 && posts[i].NumComments > 0 {
+				comments, err := scraper.GetComments(r.Context(), posts[i], req.Comments, 1)
+				if err != nil {
+					fmt.Printf("⚠️  Preview: comments on %s: %v\n", posts[i].ID, err)
+				}
+				for _, c := range comments {
+					texts = append(texts, c.Body)
+				}
+				posts[i].Comments = comments
+			}
+		}
+		results = posts
+
+	case PreviewGoogle:
+		found, err := scrapers.NewGoogleScraper().Search(r.Context(), req.Query, req.Limit)
+		if err != nil {
+			respondError(w, previewErrorStatus(err), err.Error())
+			return
+		}
+		for _, result := range found {
+			texts = append(texts, result.Title+" "+result.Snippet)
+		}
+		results = found
+
+	case PreviewGemini:
+		scraper, err := scrapers.NewGeminiScraper()
+		if err != nil {
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		defer scraper.Close()
+		// Ground the query as a scrape run would; a bad override falls back to the defaults there too
+		grounding, _ := scrapers.GroundingRulesFromEnv(config.DefaultGroundingRules())
+		scraper.SetGrounding(grounding)
+		answer, err := scraper.SearchComplaintsWithAI(r.Context(), req.Query)
+		if err != nil {
+			respondError(w, previewErrorStatus(err), err.Error())
+			return
+		}
+		if len(answer.KeyComplaints) > req.Limit {
+			answer.KeyComplaints = answer.KeyComplaints[:req.Limit]
+		}
+		for _, complaint := range answer.KeyComplaints {
+			texts = append(texts, complaint.Description)
+		}
+		results = answer
+
+	default:
+		respondError(w, http.StatusBadRequest, "source must be one of youtube, reddit, google, gemini")
+		return
+	}
+
+	categories := map[string]int{}
+	for _, text := range texts {
+		for _, category := range analyzer.Categorize(text) {
+			categories[category]++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"source":      req.Source,
+		"query":       req.Query,
+		"results":     results,
+		"texts":       len(texts),
+		"categories":  categories,
+		"quota_used":  quota,
+		"duration_ms": time.Since(started).Milliseconds(),
+		"persisted":   false,
+	})
+}
+
+// previewErrorStatus is 504 when the preview ran out of time and 502 when the
+// source failed: either way the fault is upstream, not in the request
+func previewErrorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
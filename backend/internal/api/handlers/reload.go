@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+// ============================================
+// DATA FILE WATCHING
+// ============================================
+// The API reloads after its own scheduled runs and ingests, but cmd/server and the
+// analyzer write their results from another process, and until now the API only
+// picked those up on restart. Watch polls the scrape files' size and modification
+// time rather than subscribing to filesystem events, which don't arrive for data
+// directories on network or container volumes. A file is only reloaded once it has
+// looked the same for two polls in a row, so a half-written file isn't read.
+// Reload swaps in a whole new snapshot, so requests never see a mix of old and new.
+
+const (
+	// DefaultDataWatchInterval is how often Watch checks the data files
+	DefaultDataWatchInterval = 30 * time.Second
+)
+
+// watchedFiles are the files in the data directory that Reload reads
+var watchedFiles = []string{
+	"youtube_latest_results.json",
+	"gemini_latest_results.json",
+	scrapers.RedditResultsFile,
+	scrapers.ReviewsResultsFile,
+}
+
+// fileStamp is what Watch compares between polls
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// DataWatchIntervalFromEnv reads DATA_WATCH_INTERVAL (e.g. "30s", the default);
+// "0" turns watching off
+func DataWatchIntervalFromEnv() (time.Duration, error) {
+	v := os.Getenv("DATA_WATCH_INTERVAL")
+	if v == "" {
+		return DefaultDataWatchInterval, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid DATA_WATCH_INTERVAL %q: must be a duration, or 0 to turn watching off", v)
+	}
+	return d, nil
+}
+
+// Watch reloads the data whenever a scrape file changes, until ctx is cancelled
+func (h *DataHandler) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, pending := h.stamps(), false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := h.stamps()
+		changed := len(current) != len(last)
+		for name, stamp := range current {
+			if last[name] != stamp {
+				changed = true
+			}
+		}
+		last = current
+		if changed {
+			pending = true // Reload once the files stop changing
+			continue
+		}
+		if !pending {
+			continue
+		}
+		pending = false
+		if err := h.Reload(); err != nil {
+			fmt.Printf("⚠️  Failed to reload changed data files: %v\n", err)
+			continue
+		}
+		fmt.Println("🔄 Data files changed; reloaded")
+	}
+}
+
+// stamps returns the size and modification time of each watched file that exists
+func (h *DataHandler) stamps() map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(watchedFiles))
+	for _, name := range watchedFiles {
+		if info, err := os.Stat(filepath.Join(h.dataDir, name)); err == nil {
+			stamps[name] = fileStamp{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return stamps
+}
+
+// ReloadData handles POST /api/admin/reload
+// Re-reads the data files and re-derives the analysis, issues and stats now
+func (h *DataHandler) ReloadData(w http.ResponseWriter, r *http.Request) {
+	if err := h.Reload(); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	snapshot := h.snapshot.Load()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"loaded_at":  snapshot.loadedAt,
+		"complaints": len(snapshot.complaints),
+	})
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/tasnint/coinsights/internal/api/openapi"
+)
+
+// Register mounts the issue, resolution, attestation and blockchain endpoints
+func (h *BlockchainHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/issues", h.CreateIssue)
+	mux.HandleFunc("GET /api/issues", h.ListIssues)
+	mux.HandleFunc("GET /api/issues/{id}", h.GetIssue)
+	mux.HandleFunc("GET /api/issues/{id}/projection", h.GetIssueProjection)
+	mux.HandleFunc("POST /api/issues/{id}/archive", h.ArchiveIssue)
+	mux.HandleFunc("POST /api/issues/{id}/unarchive", h.UnarchiveIssue)
+
+	mux.HandleFunc("POST /api/resolutions", h.CreateResolution)
+	mux.HandleFunc("GET /api/resolutions", h.ListResolutions)
+	mux.HandleFunc("GET /api/resolutions/methodologies", h.ListMethodologies)
+	mux.HandleFunc("GET /api/resolutions/criteria", h.GetCriteria)
+	mux.HandleFunc("PUT /api/resolutions/criteria", h.UpdateCriteria)
+	mux.HandleFunc("GET /api/resolutions/{id}", h.GetResolution)
+	mux.HandleFunc("GET /api/resolutions/{id}/attestation", h.GetAttestationByResolution)
+	mux.HandleFunc("POST /api/resolutions/{id}/review", h.ReviewResolution)
+	mux.HandleFunc("GET /api/resolutions/{id}/preflight", h.GetPreflight)
+
+	mux.HandleFunc("POST /api/attestations", h.AttestResolution)
+	mux.HandleFunc("GET /api/attestations", h.ListAttestations)
+	mux.HandleFunc("POST /api/attestations/verify", h.VerifyAttestation)
+	mux.HandleFunc("POST /api/attestations/verify/batch", h.VerifyAttestationBatch)
+	mux.HandleFunc("GET /api/attestations/queue", h.GetAttestationQueue)
+	mux.HandleFunc("GET /api/attestations/export", h.ExportAttestations)
+
+	mux.HandleFunc("GET /api/blockchain/info", h.GetChainInfo)
+	mux.HandleFunc("GET /api/blockchain/attestors", h.GetAttestors)
+	mux.HandleFunc("GET /api/blockchain/stats", h.GetStats)
+	mux.HandleFunc("GET /api/blockchain/cost-estimate", h.GetCostEstimate)
+	mux.HandleFunc("POST /api/blockchain/hash", h.HashEvidence)
+
+	mux.HandleFunc("GET /api/chain/attestations/{id}", h.GetChainAttestation)
+	mux.HandleFunc("GET /api/chain/count", h.GetChainCount)
+
+	mux.HandleFunc("GET /api/mirror/snapshot", h.GetMirrorSnapshot)
+
+	mux.HandleFunc("POST /api/demo/full-workflow", h.CreateDemoIssueAndResolve)
+
+	mux.HandleFunc("GET /api/openapi.json", openapi.ServeSpec)
+}
+
+// Register mounts the scrape and analysis data endpoints.
+// Heavy payloads are served through the response cache, which Reload invalidates.
+// The YouTube analysis is streamed instead: the cache would buffer all of it.
+func (h *DataHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/stats", h.cache.Middleware(AnalysisCacheTTL, h.GetStats))
+	mux.HandleFunc("GET /api/analysis/youtube", h.GetYouTubeAnalysis)
+	mux.HandleFunc("GET /api/analysis/youtube/divergence", h.cache.Middleware(AnalysisCacheTTL, h.GetSentimentDivergence))
+	mux.HandleFunc("GET /api/analysis/gemini", h.cache.Middleware(AnalysisCacheTTL, h.GetGeminiResults))
+	mux.HandleFunc("GET /api/complaints", h.ListComplaints)
+	mux.HandleFunc("GET /api/complaints/versions", h.cache.Middleware(AnalysisCacheTTL, h.GetVersionBreakdown))
+	mux.HandleFunc("GET /api/categories", h.ListCategories)
+	mux.HandleFunc("GET /api/categories/cooccurrence", h.cache.Middleware(AnalysisCacheTTL, h.GetCategoryCoOccurrence))
+	mux.HandleFunc("GET /api/topics", h.cache.Middleware(AnalysisCacheTTL, h.GetTopics))
+	mux.HandleFunc("GET /api/topics/{id}/timeline", h.cache.Middleware(AnalysisCacheTTL, h.GetTopicTimeline))
+	mux.HandleFunc("GET /api/reddit/subreddits", h.cache.Middleware(AnalysisCacheTTL, h.GetSubredditTrends))
+	mux.HandleFunc("GET /api/trends", h.cache.Middleware(AnalysisCacheTTL, h.GetTrends))
+	mux.HandleFunc("POST /api/admin/reload", h.ReloadData)
+}
+
+// Register mounts the operator endpoints
+func (h *AdminHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/admin/queries/stats", h.GetQueryStats)
+	mux.HandleFunc("GET /api/admin/scrapers/status", h.GetScraperStatus)
+	mux.HandleFunc("GET /api/admin/keywords/suggestions", h.GetKeywordSuggestions)
+	mux.HandleFunc("GET /api/admin/keywords/effectiveness", h.GetKeywordEffectiveness)
+	mux.HandleFunc("GET /api/admin/sample", h.GetSample)
+	mux.HandleFunc("GET /api/admin/sample/labels", h.ListLabels)
+	mux.HandleFunc("POST /api/admin/sample/labels", h.RecordLabels)
+
+	mux.HandleFunc("GET /api/admin/categories", h.ListTaxonomy)
+	mux.HandleFunc("POST /api/admin/categories", h.CreateTaxonomyCategory)
+	mux.HandleFunc("GET /api/admin/categories/{id}", h.GetTaxonomyCategory)
+	mux.HandleFunc("PUT /api/admin/categories/{id}", h.UpdateTaxonomyCategory)
+	mux.HandleFunc("DELETE /api/admin/categories/{id}", h.DeleteTaxonomyCategory)
+	mux.HandleFunc("POST /api/admin/categories/{id}/keywords", h.AddTaxonomyKeywords)
+	mux.HandleFunc("DELETE /api/admin/categories/{id}/keywords/{keyword}", h.RemoveTaxonomyKeyword)
+
+	mux.HandleFunc("GET /api/admin/scraper-settings", h.GetScraperSettings)
+	mux.HandleFunc("PUT /api/admin/scraper-settings", h.UpdateScraperSettings)
+	mux.HandleFunc("POST /api/admin/scrape/preview", h.PreviewScrape)
+}
+
+// Register mounts the issue watch and subscription endpoints
+func (h *SubscriptionHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/issues/{id}/watch", h.WatchIssue)
+	mux.HandleFunc("POST /api/issues/{id}/unwatch", h.UnwatchIssue)
+
+	mux.HandleFunc("POST /api/subscriptions", h.CreateSubscription)
+	mux.HandleFunc("GET /api/subscriptions", h.ListSubscriptions)
+	mux.HandleFunc("DELETE /api/subscriptions/{id}", h.DeleteSubscription)
+}
+
+// Register mounts the exchange scorecard endpoints. Comparisons, which build a
+// scorecard per exchange, are served through the response cache.
+func (h *ExchangeHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/exchanges/compare", h.cache.Middleware(CompareCacheTTL, h.CompareExchanges))
+	mux.HandleFunc("GET /api/exchanges/{exchange}/scorecard", h.GetScorecard)
+	mux.HandleFunc("GET /api/exchanges/{exchange}/scorecard/history", h.GetScorecardHistory)
+	mux.HandleFunc("GET /api/exchanges/{exchange}/ratings", h.GetRatings)
+}
+
+// Register mounts the dashboard bundle endpoint
+func (h *DashboardHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/dashboard", h.cache.ScopedMiddleware(DashboardCacheTTL, cacheScope, h.GetDashboard))
+}
+
+// Register mounts the mirror status endpoint. A mirror serves the other handlers
+// behind ReadOnly, with its ResolutionService kept in sync by services.Mirror.
+func (h *MirrorHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/mirror/status", h.GetStatus)
+}
+
+// Register mounts the partner ingestion endpoint
+func (h *IngestHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/ingest/complaints", h.IngestComplaints)
+}
+
+// Register mounts the evidence bundle endpoints
+func (h *EvidenceHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/issues/{id}/evidence", h.AssembleEvidence)
+	mux.HandleFunc("GET /api/issues/{id}/brief.md", h.GetIssueBrief)
+	mux.HandleFunc("GET /api/evidence/{hash}", h.GetEvidenceBundle)
+}
+
+// Register mounts the global search endpoint
+func (h *SearchHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/search", h.Search)
+}
+
+// Register mounts the issue feeds
+func (h *FeedHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /feeds/{feed}", h.GetExchangeFeed)
+	mux.HandleFunc("GET /feeds/{exchange}/{feed}", h.GetCategoryFeed)
+}
+
+// Register mounts the webhook signing key endpoint
+func (h *SigningKeyHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /.well-known/coinsights-signing-key", h.GetSigningKey)
+}
+
+// Register mounts the announcement endpoints
+func (h *AnnouncementHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/announcements", h.ListAnnouncements)
+	mux.HandleFunc("POST /api/resolutions/{id}/announcements", h.RelinkResolution)
+}
+
+// Register mounts the scheduled scrape run history
+func (h *ScrapeRunHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/scrape-runs", h.ListScrapeRuns)
+	mux.HandleFunc("GET /api/scrape-runs/{id}/events", h.StreamScrapeRun)
+}
+
+// Register mounts the tenant admin endpoints
+func (h *TenantHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/admin/tenants", h.ListTenants)
+	mux.HandleFunc("PUT /api/admin/tenants/{id}", h.PutTenant)
+	mux.HandleFunc("DELETE /api/admin/tenants/{id}", h.DeleteTenant)
+}
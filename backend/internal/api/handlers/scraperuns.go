@@ -0,0 +1,154 @@
+// API for the scheduled scrape run history
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/scheduler"
+)
+
+const (
+	// DefaultScrapeRunLimit is how many runs GET /api/scrape-runs returns when ?limit= is omitted
+	DefaultScrapeRunLimit = 50
+	// EventStreamKeepalive is how often an idle event stream gets a comment, so
+	// proxies don't close it while a slow query runs
+	EventStreamKeepalive = 15 * time.Second
+)
+
+// ScrapeRunHandler serves the history of scheduled scrape runs
+type ScrapeRunHandler struct {
+	runs      *scheduler.RunStore
+	scheduler *scheduler.Scheduler // Optional: lists the jobs and their next run times
+}
+
+// NewScrapeRunHandler creates a new scrape run handler. sched may be nil when no
+// job is scheduled; the history of earlier runs is still served.
+func NewScrapeRunHandler(runs *scheduler.RunStore, sched *scheduler.Scheduler) *ScrapeRunHandler {
+	return &ScrapeRunHandler{runs: runs, scheduler: sched}
+}
+
+// ListScrapeRuns handles GET /api/scrape-runs
+// Query params: job (optional: youtube, gemini or google), limit (default 50, max 500).
+// Newest runs first, with the scheduled jobs and when each runs next.
+func (h *ScrapeRunHandler) ListScrapeRuns(w http.ResponseWriter, r *http.Request) {
+	limit := DefaultScrapeRunLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > scheduler.MaxRuns {
+			respondError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(scheduler.MaxRuns))
+			return
+		}
+		limit = n
+	}
+
+	jobs := []scheduler.JobInfo{}
+	if h.scheduler != nil {
+		jobs = h.scheduler.Jobs()
+	}
+	runs := h.runs.List(r.URL.Query().Get("job"), limit)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"runs":     runs,
+		"count":    len(runs),
+		"schedule": jobs,
+	})
+}
+
+// StreamScrapeRun handles GET /api/scrape-runs/{id}/events
+// Streams a run's progress as server-sent events: "started", then "query", "video"
+// and "error" events as the run goes, and a final "finished" event, after which the
+// stream ends. Connecting mid-run replays the events so far first. Event IDs are
+// the events' sequence numbers, so they stay right when a slow client misses
+// events, and a reconnecting client's Last-Event-ID skips the ones it has. A run
+// that ended before the server last started gets just its finished event.
+func (h *ScrapeRunHandler) StreamScrapeRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	run, ok := h.runs.Get(id)
+	if !ok {
+		respondError(w, http.StatusNotFound, "scrape run not found: "+id)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	lastID, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+
+	var past []models.ScrapeProgress
+	var events <-chan models.ScrapeProgress
+	cancel := func() {}
+	found := false
+	if h.scheduler != nil {
+		past, events, cancel, found = h.scheduler.Progress().Subscribe(id)
+	}
+	defer cancel()
+	if !found {
+		past = []models.ScrapeProgress{scheduler.FinishedEvent(run)}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	seq := 0
+	finished := false
+	send := func(event models.ScrapeProgress) bool {
+		if event.Seq == 0 { // A finished event made up from the run record follows the last one seen
+			event.Seq = seq + 1
+		}
+		seq = event.Seq
+		finished = event.Type == models.ProgressFinished
+		if event.Seq <= lastID {
+			return true
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data); err != nil {
+			return false // Client went away
+		}
+		flusher.Flush()
+		return true
+	}
+
+	for _, event := range past {
+		if !send(event) {
+			return
+		}
+	}
+	if events == nil {
+		return
+	}
+
+	keepalive := time.NewTicker(EventStreamKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				// A client too slow to keep up can miss the finished event itself
+				if run, ok := h.runs.Get(id); ok && !finished {
+					send(scheduler.FinishedEvent(run))
+				}
+				return
+			}
+			if !send(event) {
+				return
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// API for the dashboard's global search box: issues, resolutions and categories in one ranked list
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/textutil"
+)
+
+const (
+	// DefaultSearchLimit is how many results GET /api/search returns when ?limit= is omitted
+	DefaultSearchLimit = 20
+	// MaxSearchLimit caps ?limit=
+	MaxSearchLimit = 100
+)
+
+// Search result types
+const (
+	SearchTypeIssue      = "issue"
+	SearchTypeResolution = "resolution"
+	SearchTypeCategory   = "category"
+)
+
+// Field weights: a term in a title counts for more than one buried in a description
+const (
+	weightTitle       = 3.0
+	weightName        = 2.0
+	weightBody        = 1.0
+	weightPhraseBonus = 2.0 // The whole query appears verbatim in the title
+)
+
+// SearchHandler searches issues, resolutions and categories
+type SearchHandler struct {
+	resolutionService *services.ResolutionService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(rs *services.ResolutionService) *SearchHandler {
+	return &SearchHandler{resolutionService: rs}
+}
+
+// SearchResult is one ranked match
+type SearchResult struct {
+	Type     string  `json:"type"` // "issue", "resolution" or "category"
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Snippet  string  `json:"snippet,omitempty"`
+	Exchange string  `json:"exchange,omitempty"`
+	Category string  `json:"category,omitempty"`
+	Status   string  `json:"status,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// searchField is a piece of text a document can match on, with its weight
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// Search handles GET /api/search
+// Query params: q (required), type (optional: issue, resolution or category), limit (default 20, max 100).
+// Every term in q must appear somewhere in a result; results are ordered by score.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		respondError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	kind := r.URL.Query().Get("type")
+	switch kind {
+	case "", SearchTypeIssue, SearchTypeResolution, SearchTypeCategory:
+	default:
+		respondError(w, http.StatusBadRequest, "type must be issue, resolution or category")
+		return
+	}
+	limit := DefaultSearchLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxSearchLimit {
+			respondError(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = n
+	}
+
+	phrase := strings.Join(terms, " ")
+	results := []SearchResult{}
+	add := func(result SearchResult, fields ...searchField) {
+		if kind != "" && kind != result.Type {
+			return
+		}
+		if score := scoreMatch(phrase, terms, fields); score > 0 {
+			result.Score = score
+			results = append(results, result)
+		}
+	}
+
+	for _, issue := range h.resolutionService.ListIssues("") {
+		if !visibleTo(r, issue.Tenant) {
+			continue
+		}
+		add(SearchResult{
+			Type:     SearchTypeIssue,
+			ID:       issue.ID,
+			Title:    issue.Title,
+			Snippet:  textutil.Truncate(issue.Description, config.Truncation.SummaryComment),
+			Exchange: issue.Exchange,
+			Category: issue.Category,
+			Status:   issue.Status,
+		},
+			searchField{issue.Title, weightTitle},
+			searchField{issue.Description, weightBody},
+			searchField{issue.Exchange, weightBody},
+		)
+	}
+	for _, resolution := range h.resolutionService.ListResolutions("") {
+		if !visibleTo(r, resolution.Tenant) {
+			continue
+		}
+		add(SearchResult{
+			Type:     SearchTypeResolution,
+			ID:       resolution.ID,
+			Title:    textutil.Truncate(resolution.Summary, config.Truncation.ComplaintTitle),
+			Snippet:  textutil.Truncate(resolution.Summary, config.Truncation.SummaryComment),
+			Exchange: resolution.Exchange,
+			Category: resolution.IssueCategory,
+			Status:   resolution.Status,
+		},
+			searchField{resolution.Summary, weightTitle},
+			searchField{resolution.Exchange, weightBody},
+		)
+	}
+	for _, category := range models.Categories() {
+		add(SearchResult{
+			Type:     SearchTypeCategory,
+			ID:       category.ID,
+			Title:    category.Name,
+			Category: category.ID,
+		},
+			searchField{category.Name, weightTitle},
+			searchField{strings.ReplaceAll(category.ID, "_", " "), weightName},
+			searchField{strings.Join(category.Aliases, " "), weightName},
+		)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].ID < results[j].ID
+	})
+	total := len(results)
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"query":   query,
+		"results": results,
+		"count":   len(results),
+		"total":   total,
+	})
+}
+
+// scoreMatch sums the weight of every field each term appears in, or returns 0
+// if any term appears in none of them
+func scoreMatch(phrase string, terms []string, fields []searchField) float64 {
+	lowered := make([]string, len(fields))
+	for i, f := range fields {
+		lowered[i] = strings.ToLower(f.text)
+	}
+
+	score := 0.0
+	for _, term := range terms {
+		matched := false
+		for i, f := range fields {
+			if strings.Contains(lowered[i], term) {
+				score += f.weight
+				matched = true
+			}
+		}
+		if !matched {
+			return 0
+		}
+	}
+	if len(terms) > 1 && strings.Contains(lowered[0], phrase) {
+		score += weightPhraseBonus
+	}
+	return score
+}
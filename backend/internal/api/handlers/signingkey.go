@@ -0,0 +1,30 @@
+// Public half of the webhook signing key, for receivers to verify notifications with
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/tasnint/coinsights/internal/subscriptions"
+)
+
+// SigningKeyHandler publishes the key webhook payloads are signed with
+type SigningKeyHandler struct {
+	key *subscriptions.SigningKey
+}
+
+// NewSigningKeyHandler creates a new signing key handler. Pass the same key as the
+// subscriptions.WebhookNotifier; with a nil key the endpoint answers 503.
+func NewSigningKeyHandler(key *subscriptions.SigningKey) *SigningKeyHandler {
+	return &SigningKeyHandler{key: key}
+}
+
+// GetSigningKey handles GET /.well-known/coinsights-signing-key
+// The Ed25519 public key, its ID, and which headers carry the signature
+func (h *SigningKeyHandler) GetSigningKey(w http.ResponseWriter, r *http.Request) {
+	if h.key == nil {
+		respondError(w, http.StatusServiceUnavailable, "Webhook signing not configured")
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	respondJSON(w, http.StatusOK, h.key.Document())
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// flushEvery controls how many list items are written between flushes
+const flushEvery = 100
+
+// wantsNDJSON reports whether the client asked for newline-delimited JSON
+// via ?format=ndjson or an Accept: application/x-ndjson header
+func wantsNDJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "ndjson" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/x-ndjson")
+}
+
+// respondNDJSON streams n items as one JSON document per line
+func respondNDJSON(w http.ResponseWriter, n int, item func(i int) any) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	for i := 0; i < n; i++ {
+		if err := enc.Encode(item(i)); err != nil {
+			return // Client went away
+		}
+		if flusher != nil && (i+1)%flushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+}
+
+// respondJSONList streams {"<key>": [...], "count": n} one element at a time,
+// so memory stays flat and the first bytes go out before the whole list is encoded
+func respondJSONList(w http.ResponseWriter, key string, n int, item func(i int) any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	keyJSON, _ := json.Marshal(key)
+
+	w.Write([]byte("{"))
+	w.Write(keyJSON)
+	w.Write([]byte(":["))
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			w.Write([]byte(","))
+		}
+		data, err := json.Marshal(item(i))
+		if err != nil {
+			data = []byte("null")
+		}
+		if _, err := w.Write(data); err != nil {
+			return // Client went away
+		}
+		if flusher != nil && (i+1)%flushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	countJSON, _ := json.Marshal(n)
+	w.Write([]byte(`],"count":`))
+	w.Write(countJSON)
+	w.Write([]byte("}\n"))
+}
+
+// respondJSONStream writes a struct like respondJSON, but field by field with the
+// elements of its slice fields encoded and flushed one at a time, so a large
+// analysis never sits in memory as one encoded buffer. Fields follow encoding/json's
+// names and omitempty; structs with embedded fields or their own MarshalJSON, and
+// anything other than a struct, are encoded whole.
+func respondJSONStream(w http.ResponseWriter, status int, v any) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct || hasEmbedded(value.Type()) || value.Type().Implements(marshalerType) {
+		respondJSON(w, status, v)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	flusher, _ := w.(http.Flusher)
+	written := 0
+	write := func(data []byte) bool {
+		if _, err := w.Write(data); err != nil {
+			return false // Client went away
+		}
+		if written++; flusher != nil && written%flushEvery == 0 {
+			flusher.Flush()
+		}
+		return true
+	}
+	marshal := func(v any) []byte {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return []byte("null")
+		}
+		return data
+	}
+
+	w.Write([]byte("{"))
+	first := true
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fv := value.Field(i)
+		if strings.Contains(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		if !first {
+			w.Write([]byte(","))
+		}
+		first = false
+		w.Write(marshal(name))
+		w.Write([]byte(":"))
+
+		if !streamable(fv) {
+			if !write(marshal(fv.Interface())) {
+				return
+			}
+			continue
+		}
+		w.Write([]byte("["))
+		for j := 0; j < fv.Len(); j++ {
+			if j > 0 {
+				w.Write([]byte(","))
+			}
+			if !write(marshal(fv.Index(j).Interface())) {
+				return
+			}
+		}
+		w.Write([]byte("]"))
+	}
+	w.Write([]byte("}\n"))
+}
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// streamable reports whether a field is a slice respondJSONStream can write element
+// by element: not nil (null), not []byte (base64) and without its own MarshalJSON
+func streamable(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && !v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8 &&
+		!v.Type().Implements(marshalerType)
+}
+
+// hasEmbedded reports whether a struct type has embedded fields, which encoding/json flattens
+func hasEmbedded(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether encoding/json's omitempty leaves v out
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return v.IsZero()
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+// API for analysts to watch issues and follow topics
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/tasnint/coinsights/internal/auth"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/subscriptions"
+)
+
+// SubscriptionHandler handles issue watch and subscription endpoints
+type SubscriptionHandler struct {
+	store             *subscriptions.Store
+	resolutionService *services.ResolutionService
+}
+
+// NewSubscriptionHandler creates a new subscription handler.
+// Register the matching subscriptions.Fanout as a ResolutionService observer to deliver events.
+func NewSubscriptionHandler(store *subscriptions.Store, resolutionService *services.ResolutionService) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		store:             store,
+		resolutionService: resolutionService,
+	}
+}
+
+// WatchIssueRequest is the request body for POST /api/issues/{id}/watch
+type WatchIssueRequest struct {
+	User    string   `json:"user"`
+	Channel string   `json:"channel"`
+	Target  string   `json:"target"`
+	Events  []string `json:"events,omitempty"`
+}
+
+// UnwatchIssueRequest is the request body for POST /api/issues/{id}/unwatch
+type UnwatchIssueRequest struct {
+	User string `json:"user"`
+}
+
+// WatchIssue handles POST /api/issues/{id}/watch
+func (h *SubscriptionHandler) WatchIssue(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !h.issueVisible(w, r, id) {
+		return
+	}
+
+	var req WatchIssueRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	sub, err := h.store.Add(scoped(r, subscriptions.Subscription{
+		User:    req.User,
+		IssueID: id,
+		Events:  req.Events,
+		Channel: req.Channel,
+		Target:  req.Target,
+	}))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sub)
+}
+
+// UnwatchIssue handles POST /api/issues/{id}/unwatch
+// Removes every watch the user holds on the issue
+func (h *SubscriptionHandler) UnwatchIssue(w http.ResponseWriter, r *http.Request) {
+	var req UnwatchIssueRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.User == "" {
+		respondError(w, http.StatusBadRequest, "user is required")
+		return
+	}
+
+	removed, err := h.store.Unwatch(req.User, r.PathValue("id"), ownedBy(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"removed": removed,
+	})
+}
+
+// CreateSubscription handles POST /api/subscriptions
+// Follows every issue matching exchange, category and keyword, e.g. Coinbase withdrawal issues
+func (h *SubscriptionHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req subscriptions.Subscription
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.IssueID != "" && !h.issueVisible(w, r, req.IssueID) {
+		return
+	}
+
+	sub, err := h.store.Add(scoped(r, req))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sub)
+}
+
+// ListSubscriptions handles GET /api/subscriptions
+// Requires ?user=; lists that user's subscriptions made with the caller's credentials
+func (h *SubscriptionHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		respondError(w, http.StatusBadRequest, "user is required")
+		return
+	}
+
+	subs := slices.DeleteFunc(h.store.ForUser(user), func(sub subscriptions.Subscription) bool { return !ownedBy(r)(sub) })
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"subscriptions": subs,
+		"count":         len(subs),
+	})
+}
+
+// DeleteSubscription handles DELETE /api/subscriptions/{id}
+func (h *SubscriptionHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	removed, err := h.store.Remove(r.PathValue("id"), ownedBy(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !removed {
+		respondError(w, http.StatusNotFound, "Subscription not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// issueVisible writes a 404 and returns false unless the issue exists and the caller may see it
+func (h *SubscriptionHandler) issueVisible(w http.ResponseWriter, r *http.Request, id string) bool {
+	issue, err := h.resolutionService.GetIssue(id)
+	if err == nil && !visibleTo(r, issue.Tenant) {
+		err = fmt.Errorf("issue not found: %s", id)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return false
+	}
+	return true
+}
+
+// scoped limits a new subscription to the issues its creator may see, and records the creator as its owner
+func scoped(r *http.Request, sub subscriptions.Subscription) subscriptions.Subscription {
+	sub.Tenant, sub.AllTenants = requestScope(r)
+	sub.Owner = ""
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		sub.Owner = principal.Name
+	}
+	return sub
+}
+
+// ownedBy reports whether the request's caller owns a subscription. Without auth
+// configured everyone does; subscriptions from before owners were recorded belong
+// to the operator keys.
+func ownedBy(r *http.Request) func(subscriptions.Subscription) bool {
+	principal, ok := auth.FromContext(r.Context())
+	return func(sub subscriptions.Subscription) bool {
+		if !ok {
+			return true
+		}
+		if sub.Owner == "" {
+			return principal.Role != "" && principal.Tenant == ""
+		}
+		return sub.Owner == principal.Name && sub.Tenant == principal.Tenant
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tasnint/coinsights/internal/auth"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/services"
+	"github.com/tasnint/coinsights/internal/tenants"
+)
+
+// ============================================
+// TENANT SCOPING
+// ============================================
+// A tenant key only reaches the routes in TenantRoutes, each of which confines
+// what it reads and writes to the key's tenant; every other route answers 403,
+// so a route that doesn't know about tenants can't leak another tenant's records.
+// Operator keys see every tenant. Callers without credentials (reads, unless
+// API_AUTH_READS is set) see only the deployment's own records.
+
+// TenantRoutes are the routes tenant keys may use, keyed by ServeMux pattern
+var TenantRoutes = map[string]bool{
+	"POST /api/issues":                      true,
+	"GET /api/issues":                       true,
+	"GET /api/issues/{id}":                  true,
+	"GET /api/issues/{id}/projection":       true,
+	"POST /api/issues/{id}/archive":         true,
+	"POST /api/issues/{id}/unarchive":       true,
+	"POST /api/issues/{id}/watch":           true,
+	"POST /api/issues/{id}/unwatch":         true,
+	"POST /api/subscriptions":               true,
+	"GET /api/subscriptions":                true,
+	"DELETE /api/subscriptions/{id}":        true,
+	"POST /api/issues/{id}/evidence":        true,
+	"GET /api/evidence/{hash}":              true,
+	"POST /api/resolutions":                 true,
+	"GET /api/resolutions":                  true,
+	"GET /api/resolutions/methodologies":    true,
+	"GET /api/resolutions/criteria":         true,
+	"PUT /api/resolutions/criteria":         true,
+	"GET /api/resolutions/{id}":             true,
+	"GET /api/resolutions/{id}/attestation": true,
+	"POST /api/resolutions/{id}/review":     true,
+	"GET /api/resolutions/{id}/preflight":   true,
+	"POST /api/attestations":                true,
+	"POST /api/attestations/verify":         true,
+	"POST /api/blockchain/hash":             true,
+	"GET /api/complaints":                   true,
+	"GET /api/openapi.json":                 true,
+}
+
+// requestScope returns the tenant a request is confined to, or all=true for an
+// operator key. Without auth configured every caller is an operator.
+func requestScope(r *http.Request) (tenant string, all bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		return "", true
+	}
+	if principal.Role == "" {
+		return "", false // No credentials: the deployment's own records
+	}
+	return principal.Tenant, principal.Tenant == ""
+}
+
+// visibleTo reports whether a record of tenant may be shown to the request's caller
+func visibleTo(r *http.Request, tenant string) bool {
+	scope, all := requestScope(r)
+	return all || tenant == scope
+}
+
+// cacheScope keys cached responses by what the caller may see: every tenant, or one
+func cacheScope(r *http.Request) string {
+	if tenant, all := requestScope(r); !all {
+		return "tenant:" + tenant
+	}
+	return "all"
+}
+
+// tenantFilter is visibleTo for services that filter records by tenant
+func tenantFilter(r *http.Request) func(tenant string) bool {
+	return func(tenant string) bool { return visibleTo(r, tenant) }
+}
+
+// issueVisible writes a 404 and returns false unless the issue exists and the
+// caller may see it. Another tenant's issue is reported as not found.
+func (h *BlockchainHandler) issueVisible(w http.ResponseWriter, r *http.Request, id string) bool {
+	issue, err := h.resolutionService.GetIssue(id)
+	if err == nil && !visibleTo(r, issue.Tenant) {
+		err = fmt.Errorf("issue not found: %s", id)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return false
+	}
+	return true
+}
+
+// resolutionVisible writes a 404 and returns false unless the resolution exists
+// and the caller may see it
+func (h *BlockchainHandler) resolutionVisible(w http.ResponseWriter, r *http.Request, id string) bool {
+	resolution, err := h.resolutionService.GetResolution(id)
+	if err == nil && !visibleTo(r, resolution.Tenant) {
+		err = fmt.Errorf("resolution not found: %s", id)
+	}
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return false
+	}
+	return true
+}
+
+// TenantHandler manages tenants; operator keys only
+type TenantHandler struct {
+	store      *tenants.Store
+	resolution *services.ResolutionService
+}
+
+// NewTenantHandler creates a new tenant handler. Tenants' criteria are applied to resolution.
+func NewTenantHandler(store *tenants.Store, resolution *services.ResolutionService) *TenantHandler {
+	return &TenantHandler{store: store, resolution: resolution}
+}
+
+// ListTenants handles GET /api/admin/tenants
+func (h *TenantHandler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	list := h.store.List()
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tenants": list,
+		"count":   len(list),
+	})
+}
+
+// PutTenantRequest is the request body for creating or replacing a tenant
+type PutTenantRequest struct {
+	Name           string          `json:"name"`
+	Exchanges      []string        `json:"exchanges,omitempty"`
+	IngestPartners []string        `json:"ingest_partners,omitempty"`
+	Criteria       json.RawMessage `json:"criteria,omitempty"` // As for PUT /api/resolutions/criteria
+}
+
+// PutTenant handles PUT /api/admin/tenants/{id}
+// Creates or replaces a tenant. Criteria take the same defaults as PUT
+// /api/resolutions/criteria; omitting them leaves the tenant on the deployment's.
+func (h *TenantHandler) PutTenant(w http.ResponseWriter, r *http.Request) {
+	var req PutTenantRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	tenant := tenants.Tenant{
+		ID:             r.PathValue("id"),
+		Name:           req.Name,
+		Exchanges:      req.Exchanges,
+		IngestPartners: req.IngestPartners,
+	}
+	if len(req.Criteria) > 0 && string(req.Criteria) != "null" {
+		criteria := models.DefaultResolutionCriteria()
+		criteria.SourceWeights = nil
+		if err := json.Unmarshal(req.Criteria, &criteria); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid criteria: "+err.Error())
+			return
+		}
+		if err := services.ValidateCriteria(criteria); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tenant.Criteria = &criteria
+	}
+
+	saved, err := h.store.Put(tenant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.resolution.SetTenantCriteria(saved.ID, saved.Criteria)
+	respondJSON(w, http.StatusOK, saved)
+}
+
+// DeleteTenant handles DELETE /api/admin/tenants/{id}
+// The tenant's keys are refused from then on; its issues and resolutions are kept.
+func (h *TenantHandler) DeleteTenant(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := h.store.Delete(id); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	h.resolution.SetTenantCriteria(id, nil)
+	respondJSON(w, http.StatusOK, map[string]interface{}{"deleted": id})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// ============================================
+// REQUEST TIMEOUTS
+// ============================================
+// Every request context gets a deadline, and handlers pass r.Context() down to
+// blockchain and Gemini calls, so a hung RPC fails the request instead of pinning
+// its goroutine (and, for attestations, the resolution service lock) forever.
+
+const (
+	// DefaultRequestTimeout bounds routes without their own budget
+	DefaultRequestTimeout = 30 * time.Second
+	// AttestationTimeout covers submitting a transaction and waiting for its receipt
+	AttestationTimeout = 3 * time.Minute
+	// PreviewTimeout covers a scrape preview, which can wait on a Gemini answer or rate limiters
+	PreviewTimeout = 90 * time.Second
+	// EventStreamTimeout is how long a scrape run's event stream stays open; clients reconnect with Last-Event-ID
+	EventStreamTimeout = 2 * time.Hour
+)
+
+// RouteTimeouts are the per-route budgets that differ from DefaultRequestTimeout,
+// keyed by the ServeMux pattern the route was registered with
+var RouteTimeouts = map[string]time.Duration{
+	"POST /api/attestations":                AttestationTimeout,
+	"POST /api/attestations/verify":         AttestationTimeout,
+	"POST /api/attestations/verify/batch":   AttestationTimeout,
+	"GET /api/resolutions/{id}/attestation": AttestationTimeout,
+	"POST /api/admin/scrape/preview":        PreviewTimeout,
+	"GET /api/scrape-runs/{id}/events":      EventStreamTimeout,
+}
+
+// WithTimeouts wraps the whole mux, giving each request's context a deadline of its
+// route's budget from timeouts, or DefaultRequestTimeout. Only the context is bounded:
+// responses are not buffered, so streaming endpoints keep flushing as they go.
+func WithTimeouts(mux *http.ServeMux, timeouts map[string]time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := DefaultRequestTimeout
+		if _, pattern := mux.Handler(r); pattern != "" {
+			if t, ok := timeouts[pattern]; ok {
+				timeout = t
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// errorStatus maps a service error to a response status: 504 if the request ran out
+// of time waiting on a downstream call, 500 otherwise
+func errorStatus(err error) int {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxBodyBytes is the default cap on request bodies read for validation
+const MaxBodyBytes = 1 << 20
+
+// Validator rejects requests whose query parameters or JSON bodies don't match the spec
+type Validator struct {
+	spec         *Spec
+	maxBodyBytes int64
+}
+
+// NewValidator creates a validator from the embedded spec
+func NewValidator() (*Validator, error) {
+	spec, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{spec: spec, maxBodyBytes: MaxBodyBytes}, nil
+}
+
+// SetMaxBodyBytes caps the bodies read for validation at n. Set it to the largest
+// body the server accepts (MAX_REQUEST_BODY_BYTES), or bodies below that limit but
+// above the validator's are refused before reaching the handler. Call it before serving.
+func (v *Validator) SetMaxBodyBytes(n int64) {
+	v.maxBodyBytes = n
+}
+
+// Middleware validates documented requests before they reach the handler.
+// Undocumented paths pass through untouched so the mux can answer 404/405 itself.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op, _ := v.spec.FindOperation(r.Method, r.URL.Path)
+		if op == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		errs := v.spec.ValidateQuery(op, r.URL.Query())
+
+		if op.RequestBody != nil {
+			body, err := io.ReadAll(io.LimitReader(r.Body, v.maxBodyBytes+1))
+			r.Body.Close()
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				// The body was already capped by an outer http.MaxBytesReader
+				respondInvalid(w, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+				return
+			}
+			if err != nil {
+				respondInvalid(w, http.StatusBadRequest, "Failed to read request body", nil)
+				return
+			}
+			if int64(len(body)) > v.maxBodyBytes {
+				respondInvalid(w, http.StatusRequestEntityTooLarge, "Request body too large", nil)
+				return
+			}
+			errs = append(errs, v.spec.ValidateBody(op, body)...)
+			// Handlers decode the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		if len(errs) > 0 {
+			respondInvalid(w, http.StatusBadRequest, "Request does not match the API specification", errs)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// respondInvalid writes the same error shape as the API handlers, plus validation details
+func respondInvalid(w http.ResponseWriter, status int, message string, details []string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	body := map[string]interface{}{
+		"success": false,
+		"error":   message,
+	}
+	if len(details) > 0 {
+		body["details"] = details
+	}
+	json.NewEncoder(w).Encode(body)
+}
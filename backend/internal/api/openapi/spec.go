@@ -0,0 +1,181 @@
+// OpenAPI description of the HTTP API and request validation against it
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+// Spec is the subset of an OpenAPI 3 document used for request validation
+type Spec struct {
+	OpenAPI    string                          `json:"openapi"`
+	Paths      map[string]map[string]Operation `json:"paths"` // path template -> lowercase method -> operation
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+
+	templates []string // Paths' templates, most specific first; see FindOperation
+}
+
+// Operation describes one method on a path
+type Operation struct {
+	Summary     string       `json:"summary"`
+	Parameters  []Parameter  `json:"parameters"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // "path", "query"
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's JSON body
+type RequestBody struct {
+	Required bool `json:"required"`
+	Content  map[string]struct {
+		Schema *Schema `json:"schema"`
+	} `json:"content"`
+}
+
+// Schema is the subset of JSON Schema the validator understands
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties *Additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+}
+
+// Additional is a schema's additionalProperties: false, true, or the schema every
+// field not in Properties must match (a map, e.g. counts by source)
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON accepts a boolean or a schema
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// Load parses the embedded API spec
+func Load() (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(specJSON, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	spec.templates = sortedTemplates(spec.Paths)
+	return &spec, nil
+}
+
+// Raw returns the embedded spec document
+func Raw() []byte {
+	return specJSON
+}
+
+// ServeSpec handles GET /api/openapi.json
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(specJSON)
+}
+
+// FindOperation matches a request path against the spec's path templates.
+// Returns the operation and the template it matched, or nil if the path is undocumented.
+// Templates are tried most specific first, so /api/resolutions/criteria matches
+// itself rather than /api/resolutions/{id}.
+func (s *Spec) FindOperation(method, path string) (*Operation, string) {
+	method = strings.ToLower(method)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	templates := s.templates
+	if templates == nil {
+		templates = sortedTemplates(s.Paths)
+	}
+	for _, template := range templates {
+		op, ok := s.Paths[template][method]
+		if !ok {
+			continue
+		}
+		parts := strings.Split(strings.Trim(template, "/"), "/")
+		if len(parts) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, part := range parts {
+			if isParam(part) {
+				if segments[i] == "" {
+					matched = false
+					break
+				}
+				continue
+			}
+			if part != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return &op, template
+		}
+	}
+	return nil, ""
+}
+
+// sortedTemplates orders path templates most specific first: at the first segment
+// where two differ in kind, a literal beats a {param}. Ties fall back to the number
+// of segments and then the template text, so the order is the same on every run.
+func sortedTemplates(paths map[string]map[string]Operation) []string {
+	templates := make([]string, 0, len(paths))
+	for template := range paths {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		a := strings.Split(strings.Trim(templates[i], "/"), "/")
+		b := strings.Split(strings.Trim(templates[j], "/"), "/")
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if pa, pb := isParam(a[k]), isParam(b[k]); pa != pb {
+				return pb
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return templates[i] < templates[j]
+	})
+	return templates
+}
+
+// isParam reports whether a template segment is a {param}
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// resolve follows a "#/components/schemas/Name" reference
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		schema = s.Components.Schemas[name]
+	}
+	return schema
+}
@@ -0,0 +1,29 @@
+package openapi
+
+import "testing"
+
+// TestMapSchemas checks the embedded spec loads with schema-valued
+// additionalProperties, and that map values are validated against that schema
+func TestMapSchemas(t *testing.T) {
+	if _, err := NewValidator(); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &Schema{Ref: "#/components/schemas/ResolutionEvidence"}
+	evidence := map[string]any{
+		"complaints_before": 30.0, "complaints_after": 3.0, "percentage_decrease": 0.9, "data_sources": []any{"reddit", "youtube"},
+		"measurement_start": "2026-01-17T00:00:00Z", "measurement_end": "2026-01-31T00:00:00Z",
+		"sources_before": map[string]any{"reddit": 20.0, "youtube": 10.0},
+	}
+	if errs := spec.ValidateValue(schema, evidence, "body"); len(errs) != 0 {
+		t.Errorf("valid evidence rejected: %v", errs)
+	}
+
+	evidence["sources_before"] = map[string]any{"reddit": "twenty"}
+	if errs := spec.ValidateValue(schema, evidence, "body"); len(errs) != 1 {
+		t.Errorf("got %v for a non-integer source count, want one error", errs)
+	}
+}
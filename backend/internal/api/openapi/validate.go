@@ -0,0 +1,188 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ValidateValue checks a decoded JSON value against a schema and returns every violation.
+// path names the value in messages (e.g. "body.evidence.complaints_before").
+func (s *Spec) ValidateValue(schema *Schema, value any, path string) []string {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+
+	var errs []string
+	if value == nil {
+		return []string{path + ": must not be null"}
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		errs = append(errs, fmt.Sprintf("%s: must be one of %v", path, schema.Enum))
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return append(errs, path+": must be an object")
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		required := make(map[string]bool, len(schema.Required))
+		for _, name := range schema.Required {
+			required[name] = true
+		}
+		for _, k := range keys {
+			// Go clients encode empty optional slices as null
+			if obj[k] == nil && !required[k] {
+				continue
+			}
+			prop, known := schema.Properties[k]
+			if !known {
+				switch extra := schema.AdditionalProperties; {
+				case extra == nil:
+				case !extra.Allowed:
+					errs = append(errs, fmt.Sprintf("%s.%s: is not a recognized field", path, k))
+				case extra.Schema != nil:
+					errs = append(errs, s.ValidateValue(extra.Schema, obj[k], path+"."+k)...)
+				}
+				continue
+			}
+			errs = append(errs, s.ValidateValue(prop, obj[k], path+"."+k)...)
+		}
+
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return append(errs, path+": must be an array")
+		}
+		if schema.MinItems != nil && len(arr) < *schema.MinItems {
+			errs = append(errs, fmt.Sprintf("%s: must have at least %d items", path, *schema.MinItems))
+		}
+		for i, item := range arr {
+			errs = append(errs, s.ValidateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return append(errs, path+": must be a string")
+		}
+		if schema.MinLength != nil && len(str) < *schema.MinLength {
+			errs = append(errs, fmt.Sprintf("%s: must be at least %d characters", path, *schema.MinLength))
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				errs = append(errs, path+": must be an RFC 3339 date-time")
+			}
+		}
+
+	case "integer", "number":
+		num, ok := value.(float64)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: must be a %s", path, schema.Type))
+		}
+		if schema.Type == "integer" && num != math.Trunc(num) {
+			errs = append(errs, path+": must be an integer")
+		}
+		if schema.Minimum != nil && num < *schema.Minimum {
+			errs = append(errs, fmt.Sprintf("%s: must be >= %v", path, *schema.Minimum))
+		}
+		if schema.Maximum != nil && num > *schema.Maximum {
+			errs = append(errs, fmt.Sprintf("%s: must be <= %v", path, *schema.Maximum))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, path+": must be a boolean")
+		}
+	}
+
+	return errs
+}
+
+// ValidateQuery checks query parameters against an operation's parameter list
+func (s *Spec) ValidateQuery(op *Operation, query url.Values) []string {
+	var errs []string
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		raw, present := query[param.Name]
+		if !present || len(raw) == 0 || raw[0] == "" {
+			if param.Required {
+				errs = append(errs, "query."+param.Name+": is required")
+			}
+			continue
+		}
+		errs = append(errs, s.ValidateValue(param.Schema, coerce(s.resolve(param.Schema), raw[0]), "query."+param.Name)...)
+	}
+	return errs
+}
+
+// ValidateBody decodes a JSON body and validates it against the operation's request schema
+func (s *Spec) ValidateBody(op *Operation, body []byte) []string {
+	if op.RequestBody == nil {
+		return nil
+	}
+	content, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return nil
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			return []string{"body: is required"}
+		}
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{"body: invalid JSON: " + err.Error()}
+	}
+	return s.ValidateValue(content.Schema, value, "body")
+}
+
+// coerce converts a query string to the JSON type its schema expects,
+// leaving it as a string (so validation reports the type error) if it doesn't parse
+func coerce(schema *Schema, raw string) any {
+	if schema == nil {
+		return raw
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+// inEnum reports whether value equals one of the allowed values
+func inEnum(allowed []any, value any) bool {
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+// Listener setup for the API server: bind address, port, TLS and the HTTP→HTTPS redirect
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ============================================
+// LISTENER CONFIGURATION
+// ============================================
+// Simple deployments expose the API directly instead of behind a reverse proxy, so
+// the server terminates TLS itself: with a certificate and key from disk, or with
+// certificates obtained and renewed from Let's Encrypt (autocert). A second plain
+// HTTP listener can redirect to HTTPS; with autocert it also answers the ACME
+// HTTP-01 challenges. Settings come from the environment and can be overridden
+// by command-line flags.
+
+const (
+	// DefaultPort is the API port when neither PORT nor -port is given
+	DefaultPort = 8080
+	// DefaultAutocertCache is where autocert keeps certificates between restarts
+	DefaultAutocertCache = "autocert-cache"
+	// ShutdownTimeout is how long in-flight requests get to finish on shutdown
+	ShutdownTimeout = 15 * time.Second
+)
+
+// Server-side timeouts. There is no write timeout: streaming endpoints run as long as
+// their route's budget from handlers.WithTimeouts allows.
+const (
+	ReadHeaderTimeout = 10 * time.Second
+	IdleTimeout       = 2 * time.Minute
+)
+
+// Config is where and how the API server listens
+type Config struct {
+	Bind string // Address to bind, e.g. "127.0.0.1"; empty means all interfaces
+	Port int
+
+	// TLS from files; both or neither
+	CertFile string
+	KeyFile  string
+
+	// TLS from Let's Encrypt for these domains; exclusive with CertFile/KeyFile
+	AutocertDomains []string
+	AutocertCache   string // Directory for obtained certificates
+	AutocertEmail   string // Optional contact for expiry notices
+
+	// RedirectPort serves a plain HTTP listener that redirects to HTTPS (0 = off)
+	RedirectPort int
+}
+
+// ConfigFromEnv reads PORT, BIND_ADDR, TLS_CERT_FILE, TLS_KEY_FILE, AUTOCERT_DOMAINS
+// (comma-separated), AUTOCERT_CACHE_DIR, AUTOCERT_EMAIL and HTTP_REDIRECT_PORT
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Bind:          os.Getenv("BIND_ADDR"),
+		Port:          DefaultPort,
+		CertFile:      os.Getenv("TLS_CERT_FILE"),
+		KeyFile:       os.Getenv("TLS_KEY_FILE"),
+		AutocertCache: os.Getenv("AUTOCERT_CACHE_DIR"),
+		AutocertEmail: os.Getenv("AUTOCERT_EMAIL"),
+	}
+	if cfg.AutocertCache == "" {
+		cfg.AutocertCache = DefaultAutocertCache
+	}
+	for _, domain := range strings.Split(os.Getenv("AUTOCERT_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			cfg.AutocertDomains = append(cfg.AutocertDomains, domain)
+		}
+	}
+
+	var err error
+	if cfg.Port, err = portFromEnv("PORT", DefaultPort); err != nil {
+		return Config{}, err
+	}
+	if cfg.RedirectPort, err = portFromEnv("HTTP_REDIRECT_PORT", 0); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// RegisterFlags adds flags that override cfg's values, so callers load the
+// environment first and parse flags after
+func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&cfg.Bind, "bind", cfg.Bind, "address to bind (empty = all interfaces)")
+	fs.IntVar(&cfg.Port, "port", cfg.Port, "port to listen on")
+	fs.StringVar(&cfg.CertFile, "tls-cert", cfg.CertFile, "TLS certificate file")
+	fs.StringVar(&cfg.KeyFile, "tls-key", cfg.KeyFile, "TLS private key file")
+	fs.Func("autocert-domains", "comma-separated domains to obtain Let's Encrypt certificates for", func(v string) error {
+		cfg.AutocertDomains = nil
+		for _, domain := range strings.Split(v, ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				cfg.AutocertDomains = append(cfg.AutocertDomains, domain)
+			}
+		}
+		return nil
+	})
+	fs.StringVar(&cfg.AutocertCache, "autocert-cache", cfg.AutocertCache, "directory for Let's Encrypt certificates")
+	fs.StringVar(&cfg.AutocertEmail, "autocert-email", cfg.AutocertEmail, "contact email for Let's Encrypt")
+	fs.IntVar(&cfg.RedirectPort, "redirect-port", cfg.RedirectPort, "plain HTTP port redirecting to HTTPS (0 = off)")
+}
+
+// TLS reports whether the server terminates TLS
+func (cfg Config) TLS() bool {
+	return cfg.CertFile != "" || len(cfg.AutocertDomains) > 0
+}
+
+// Addr is the address the API listens on
+func (cfg Config) Addr() string {
+	return net.JoinHostPort(cfg.Bind, strconv.Itoa(cfg.Port))
+}
+
+// Validate checks the settings fit together
+func (cfg Config) Validate() error {
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid port %d", cfg.Port)
+	}
+	if cfg.RedirectPort < 0 || cfg.RedirectPort > 65535 {
+		return fmt.Errorf("invalid redirect port %d", cfg.RedirectPort)
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+	if cfg.CertFile != "" && len(cfg.AutocertDomains) > 0 {
+		return fmt.Errorf("use either a TLS certificate file or autocert domains, not both")
+	}
+	if cfg.RedirectPort != 0 && !cfg.TLS() {
+		return fmt.Errorf("an HTTPS redirect needs TLS to be configured")
+	}
+	if cfg.RedirectPort != 0 && cfg.RedirectPort == cfg.Port {
+		return fmt.Errorf("redirect port must differ from the API port")
+	}
+	return nil
+}
+
+// ============================================
+// SERVING
+// ============================================
+
+// Run serves handler as cfg describes until ctx is cancelled, then shuts down gracefully
+func Run(ctx context.Context, cfg Config, handler http.Handler) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: ReadHeaderTimeout,
+		IdleTimeout:       IdleTimeout,
+	}
+	servers := []*http.Server{srv}
+
+	var redirect http.Handler = RedirectHandler(cfg.Port)
+	if len(cfg.AutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutocertDomains...),
+			Cache:      autocert.DirCache(cfg.AutocertCache),
+			Email:      cfg.AutocertEmail,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		redirect = manager.HTTPHandler(redirect)
+	} else if cfg.CertFile != "" {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.RedirectPort != 0 {
+		servers = append(servers, &http.Server{
+			Addr:              net.JoinHostPort(cfg.Bind, strconv.Itoa(cfg.RedirectPort)),
+			Handler:           redirect,
+			ReadHeaderTimeout: ReadHeaderTimeout,
+			IdleTimeout:       IdleTimeout,
+		})
+	}
+
+	errs := make(chan error, len(servers))
+	go func() {
+		scheme := "http"
+		if cfg.TLS() {
+			scheme = "https"
+		}
+		fmt.Printf("🌐 API listening on %s://%s\n", scheme, srv.Addr)
+		switch {
+		case cfg.CertFile != "":
+			errs <- srv.ListenAndServeTLS(cfg.CertFile, cfg.KeyFile)
+		case cfg.TLS():
+			errs <- srv.ListenAndServeTLS("", "") // Certificates come from TLSConfig
+		default:
+			errs <- srv.ListenAndServe()
+		}
+	}()
+	for _, s := range servers[1:] {
+		go func() {
+			fmt.Printf("↪️  Redirecting http://%s to HTTPS\n", s.Addr)
+			errs <- s.ListenAndServe()
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+	case runErr = <-errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	for _, s := range servers {
+		if err := s.Shutdown(shutdownCtx); err != nil && runErr == nil {
+			runErr = err
+		}
+	}
+	if errors.Is(runErr, http.ErrServerClosed) {
+		return nil
+	}
+	return runErr
+}
+
+// RedirectHandler sends every request to the same host and path over HTTPS on httpsPort
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// portFromEnv reads a port number from key, or returns def if it is unset
+func portFromEnv(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	port, err := strconv.Atoi(v)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid %s %q: must be a port number", key, v)
+	}
+	return port, nil
+}
@@ -0,0 +1,183 @@
+// Reproducible complaint samples and manual audit labels for measuring categorization precision
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// LabelsFile is the audit label file inside the data directory
+const LabelsFile = "audit_labels.json"
+
+// Sample size bounds for GET /api/admin/sample
+const (
+	DefaultSampleSize = 50
+	MaxSampleSize     = 500
+)
+
+// Sample is a reproducible random draw of categorized complaints
+type Sample struct {
+	Category   string             `json:"category,omitempty"` // Empty means all categories
+	Seed       int64              `json:"seed"`               // Pass back to reproduce the same draw
+	Population int                `json:"population"`         // Complaints the sample was drawn from
+	Complaints []models.Complaint `json:"complaints"`
+}
+
+// Label is an auditor's verdict on one sampled complaint's category
+type Label struct {
+	ComplaintID       string    `json:"complaint_id"`
+	Category          string    `json:"category"`                     // Category the analyzer assigned
+	Correct           bool      `json:"correct"`                      // Whether the assigned category is right
+	CorrectedCategory string    `json:"corrected_category,omitempty"` // Auditor's category when Correct is false
+	LabeledBy         string    `json:"labeled_by"`
+	Note              string    `json:"note,omitempty"`
+	LabeledAt         time.Time `json:"labeled_at"`
+}
+
+// Precision is the share of audited complaints whose category was confirmed correct
+type Precision struct {
+	Category  string  `json:"category"`
+	Labeled   int     `json:"labeled"`
+	Correct   int     `json:"correct"`
+	Precision float64 `json:"precision"` // Correct / Labeled
+}
+
+// Draw returns n complaints chosen at random from those in category (all categorized
+// complaints when category is empty). The same complaints, category and seed always
+// produce the same sample.
+func Draw(complaints []models.Complaint, category string, n int, seed int64) Sample {
+	population := make([]models.Complaint, 0)
+	for _, c := range complaints {
+		if c.Category == "" || (category != "" && c.Category != category) {
+			continue
+		}
+		population = append(population, c)
+	}
+	// Fix the order first so the draw doesn't depend on load order
+	sort.Slice(population, func(i, j int) bool { return population[i].ID < population[j].ID })
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(population), func(i, j int) {
+		population[i], population[j] = population[j], population[i]
+	})
+	if n > len(population) {
+		n = len(population)
+	}
+
+	return Sample{
+		Category:   category,
+		Seed:       seed,
+		Population: len(population),
+		Complaints: population[:n],
+	}
+}
+
+// ============================================
+// LABEL STORE
+// ============================================
+
+// Store keeps audit labels persisted to a JSON file
+type Store struct {
+	path   string
+	labels []Label
+	mu     sync.RWMutex
+}
+
+// NewStore loads the audit labels from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, LabelsFile)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit labels: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.labels); err != nil {
+		return nil, fmt.Errorf("failed to parse audit labels: %w", err)
+	}
+	return s, nil
+}
+
+// Record stores labels, replacing any earlier label for the same complaint
+func (s *Store) Record(labels []Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := make(map[string]int, len(s.labels))
+	for i, l := range s.labels {
+		index[l.ComplaintID] = i
+	}
+
+	now := time.Now()
+	for _, label := range labels {
+		if label.LabeledAt.IsZero() {
+			label.LabeledAt = now
+		}
+		if i, ok := index[label.ComplaintID]; ok {
+			s.labels[i] = label
+			continue
+		}
+		index[label.ComplaintID] = len(s.labels)
+		s.labels = append(s.labels, label)
+	}
+
+	return s.save()
+}
+
+// Labels returns all recorded labels
+func (s *Store) Labels() []Label {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	labels := make([]Label, len(s.labels))
+	copy(labels, s.labels)
+	return labels
+}
+
+// Precision returns per-category precision over all labels, sorted by category
+func (s *Store) Precision() []Precision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byCategory := make(map[string]*Precision)
+	for _, l := range s.labels {
+		p, ok := byCategory[l.Category]
+		if !ok {
+			p = &Precision{Category: l.Category}
+			byCategory[l.Category] = p
+		}
+		p.Labeled++
+		if l.Correct {
+			p.Correct++
+		}
+	}
+
+	metrics := make([]Precision, 0, len(byCategory))
+	for _, p := range byCategory {
+		p.Precision = float64(p.Correct) / float64(p.Labeled)
+		metrics = append(metrics, *p)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Category < metrics[j].Category })
+	return metrics
+}
+
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.labels, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit labels: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write audit labels: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// API credentials and the roles they grant
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ============================================
+// API AUTHENTICATION
+// ============================================
+// Anyone who can reach the API could open issues, change resolution criteria or
+// send an attestation transaction paid for by the wallet. Callers authenticate with
+// an API key ("Authorization: Bearer <key>" or "X-API-Key: <key>") or, when
+// API_JWT_SECRET is set, an HS256 JWT carrying a "role" claim. Keys come from
+// API_KEYS and from the api_keys.json file in the data directory, which stores
+// only each key's SHA-256 so the file can be backed up without leaking keys.
+//
+// Roles are ordered: a writer can do everything a reader can, and an attestor
+// everything a writer can. Which role a route needs is decided by the handlers.
+// A key or token may also name a tenant, which confines it to that tenant's
+// records; see internal/tenants.
+
+// Roles
+const (
+	RoleReader   Role = "reader"   // Read endpoints, when reads require a key
+	RoleWriter   Role = "writer"   // Create and change issues, resolutions and settings
+	RoleAttestor Role = "attestor" // Send attestation transactions
+)
+
+const (
+	// KeysFile holds API key hashes inside the data directory
+	KeysFile = "api_keys.json"
+	// JWTLeeway is how far clocks may disagree when checking a JWT's exp and nbf
+	JWTLeeway = time.Minute
+)
+
+// Role is what a caller may do
+type Role string
+
+// rank orders the roles; 0 is not a role
+func (r Role) rank() int {
+	switch r {
+	case RoleReader:
+		return 1
+	case RoleWriter:
+		return 2
+	case RoleAttestor:
+		return 3
+	}
+	return 0
+}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Allows reports whether r grants everything required does
+func (r Role) Allows(required Role) bool {
+	return r.rank() >= required.rank()
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	Name   string `json:"name"` // Key name, or the JWT's "sub"
+	Role   Role   `json:"role"`
+	Tenant string `json:"tenant,omitempty"` // Empty for operator keys, which see every tenant
+}
+
+// KeyEntry is an API key in api_keys.json
+type KeyEntry struct {
+	Name      string `json:"name"`
+	Role      Role   `json:"role"`
+	Tenant    string `json:"tenant,omitempty"`
+	KeySHA256 string `json:"key_sha256"` // Hex SHA-256 of the key
+}
+
+// Authenticator checks request credentials
+type Authenticator struct {
+	keys      map[[sha256.Size]byte]Principal // Keyed by the key's SHA-256
+	jwtSecret []byte                          // Empty = JWTs are not accepted
+	readsOpen bool                            // Read routes need no credentials
+}
+
+// ParseKeys reads "name:role:key,name:role:key" (the API_KEYS format). A name of
+// "tenant/name" scopes the key to that tenant.
+func ParseKeys(s string) ([]KeyEntry, error) {
+	var entries []KeyEntry
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[2]) == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, want name:role:key", entry)
+		}
+		role := Role(strings.ToLower(strings.TrimSpace(parts[1])))
+		if !role.Valid() {
+			return nil, fmt.Errorf("invalid API key entry %q: role must be reader, writer or attestor", entry)
+		}
+		sum := sha256.Sum256([]byte(strings.TrimSpace(parts[2])))
+		key := KeyEntry{Name: strings.TrimSpace(parts[0]), Role: role, KeySHA256: hex.EncodeToString(sum[:])}
+		if tenant, name, ok := strings.Cut(key.Name, "/"); ok {
+			key.Tenant, key.Name = strings.TrimSpace(tenant), strings.TrimSpace(name)
+		}
+		entries = append(entries, key)
+	}
+	return entries, nil
+}
+
+// LoadKeysFile reads api_keys.json from dataDir (no keys if the file doesn't exist)
+func LoadKeysFile(dataDir string) ([]KeyEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, KeysFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	var entries []KeyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name == "" || !entry.Role.Valid() {
+			return nil, fmt.Errorf("invalid API key %q in %s: needs a name and a reader, writer or attestor role", entry.Name, KeysFile)
+		}
+		if sum, err := hex.DecodeString(entry.KeySHA256); err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("invalid API key %q in %s: key_sha256 must be a hex SHA-256", entry.Name, KeysFile)
+		}
+	}
+	return entries, nil
+}
+
+// New creates an authenticator accepting keys and, with a non-empty jwtSecret,
+// JWTs signed with it. readsOpen lets read routes through without credentials.
+func New(keys []KeyEntry, jwtSecret []byte, readsOpen bool) *Authenticator {
+	a := &Authenticator{keys: make(map[[sha256.Size]byte]Principal), jwtSecret: jwtSecret, readsOpen: readsOpen}
+	for _, entry := range keys {
+		var sum [sha256.Size]byte
+		hex.Decode(sum[:], []byte(entry.KeySHA256))
+		a.keys[sum] = Principal{Name: entry.Name, Role: entry.Role, Tenant: entry.Tenant}
+	}
+	return a
+}
+
+// FromEnv builds an authenticator from API_KEYS, dataDir's api_keys.json and
+// API_JWT_SECRET, returning nil if none of them configures a credential.
+// API_AUTH_READS=true requires a key for read routes too.
+func FromEnv(dataDir string) (*Authenticator, error) {
+	keys, err := ParseKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+	stored, err := LoadKeysFile(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, stored...)
+	secret := os.Getenv("API_JWT_SECRET")
+	if len(keys) == 0 && secret == "" {
+		return nil, nil
+	}
+
+	authReads := false
+	if v := os.Getenv("API_AUTH_READS"); v != "" {
+		switch strings.ToLower(v) {
+		case "true", "1", "yes":
+			authReads = true
+		case "false", "0", "no":
+		default:
+			return nil, fmt.Errorf("invalid API_AUTH_READS %q: must be true or false", v)
+		}
+	}
+	return New(keys, []byte(secret), !authReads), nil
+}
+
+// ReadsOpen reports whether read routes are served without credentials
+func (a *Authenticator) ReadsOpen() bool {
+	return a.readsOpen
+}
+
+// ErrNoCredentials means the request carried no API key or token
+var ErrNoCredentials = errors.New("missing API key")
+
+// Authenticate returns who sent the request
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	credential := r.Header.Get("X-API-Key")
+	if header := r.Header.Get("Authorization"); credential == "" && strings.HasPrefix(header, "Bearer ") {
+		credential = strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	if credential == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	sum := sha256.Sum256([]byte(credential))
+	found, ok := Principal{}, false
+	for candidate, principal := range a.keys {
+		// Compare every key so the time taken doesn't say which one came closest
+		if subtle.ConstantTimeCompare(candidate[:], sum[:]) == 1 {
+			found, ok = principal, true
+		}
+	}
+	if ok {
+		return found, nil
+	}
+	if len(a.jwtSecret) > 0 && strings.Count(credential, ".") == 2 {
+		return a.verifyJWT(credential, time.Now())
+	}
+	return Principal{}, errors.New("invalid API key")
+}
+
+// jwtClaims are the claims a token must carry
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Role      Role   `json:"role"`
+	Tenant    string `json:"tenant,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf,omitempty"`
+}
+
+// verifyJWT checks an HS256 token's signature, expiry and role
+func (a *Authenticator) verifyJWT(token string, now time.Time) (Principal, error) {
+	parts := strings.Split(token, ".")
+	invalid := errors.New("invalid token")
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return Principal{}, invalid
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return Principal{}, errors.New("invalid token: only HS256 is accepted")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, invalid
+	}
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return Principal{}, invalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Principal{}, invalid
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Principal{}, invalid
+	}
+	switch {
+	case claims.ExpiresAt == 0:
+		return Principal{}, errors.New("invalid token: exp is required")
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(JWTLeeway)):
+		return Principal{}, errors.New("token expired")
+	case claims.NotBefore != 0 && now.Add(JWTLeeway).Before(time.Unix(claims.NotBefore, 0)):
+		return Principal{}, errors.New("token not valid yet")
+	case !claims.Role.Valid():
+		return Principal{}, errors.New("invalid token: role must be reader, writer or attestor")
+	}
+	return Principal{Name: claims.Subject, Role: claims.Role, Tenant: claims.Tenant}, nil
+}
+
+// principalKey carries the authenticated caller in a request context
+type principalKey struct{}
+
+// WithPrincipal returns ctx carrying p
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the caller a request was authenticated as, if it was
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
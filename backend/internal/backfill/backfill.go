@@ -0,0 +1,229 @@
+// Historical complaint baselines assembled by walking YouTube search month by month
+package backfill
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/integrity"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+const (
+	// BaselineFile is the per-category monthly baseline inside the data directory
+	BaselineFile = "backfill_baseline.json"
+	// ArchiveDir holds the raw monthly scrape results inside the data directory
+	ArchiveDir = "backfill"
+	// MaxMonths caps how far back a backfill may walk
+	MaxMonths = 24
+)
+
+// MonthBaseline is the complaint volume for a single calendar month.
+// Months are assigned by video publish date (the search window), not comment date.
+type MonthBaseline struct {
+	Month         string         `json:"month"` // "2025-01"
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	Videos        int            `json:"videos"`
+	Comments      int            `json:"comments"`
+	TotalIssues   int            `json:"total_issues"`
+	Categories    map[string]int `json:"categories"`
+	QuotaUsed     int            `json:"quota_used"`
+	CompletedAt   time.Time      `json:"completed_at"`
+	ArchivedAsRaw string         `json:"archived_as_raw"` // Path of the raw scrape for this month
+}
+
+// Baseline holds every backfilled month, oldest first
+type Baseline struct {
+	Months    []MonthBaseline `json:"months"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Settings controls how much quota each month consumes
+type Settings struct {
+	Months           int
+	Queries          []string
+	VideosPerQuery   int
+	CommentsPerVideo int
+}
+
+// EstimateQuota returns the YouTube quota units a backfill will spend
+func (s Settings) EstimateQuota() int {
+	perQuery := config.YouTubeQueryCost(s.VideosPerQuery, s.CommentsPerVideo, 0)
+	return s.Months * len(s.Queries) * perQuery
+}
+
+// Runner executes backfills and maintains the baseline file
+type Runner struct {
+	scraper *scrapers.YouTubeScraper
+	dataDir string
+}
+
+// NewRunner creates a backfill runner writing into dataDir
+func NewRunner(scraper *scrapers.YouTubeScraper, dataDir string) *Runner {
+	return &Runner{
+		scraper: scraper,
+		dataDir: dataDir,
+	}
+}
+
+// LoadBaseline reads the baseline file. A missing file yields an empty baseline.
+func LoadBaseline(dataDir string) (*Baseline, error) {
+	baseline := &Baseline{}
+	path := filepath.Join(dataDir, BaselineFile)
+	if err := integrity.Verify(path); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	return baseline, nil
+}
+
+// Run walks back settings.Months full calendar months from now, scraping and analyzing
+// each month separately. Months already in the baseline are skipped, so an
+// interrupted backfill (e.g. out of quota) resumes where it stopped. A month whose
+// scrape hit errors isn't recorded: the run stops there and the month is retried.
+func (r *Runner) Run(settings Settings, now time.Time) (*Baseline, error) {
+	if settings.Months < 1 || settings.Months > MaxMonths {
+		return nil, fmt.Errorf("months must be between 1 and %d", MaxMonths)
+	}
+
+	baseline, err := LoadBaseline(r.dataDir)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool)
+	for _, m := range baseline.Months {
+		done[m.Month] = true
+	}
+
+	archiveDir := filepath.Join(r.dataDir, ArchiveDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := settings.Months; i >= 1; i-- {
+		start := currentMonth.AddDate(0, -i, 0)
+		end := start.AddDate(0, 1, 0)
+		month := start.Format("2006-01")
+
+		if done[month] {
+			fmt.Printf("⏭️  %s already backfilled\n", month)
+			continue
+		}
+		fmt.Printf("\n📅 BACKFILLING %s\n", month)
+
+		plan := make([]models.QueryPlan, len(settings.Queries))
+		for j, q := range settings.Queries {
+			plan[j] = models.QueryPlan{
+				Query:           q,
+				MaxVideos:       settings.VideosPerQuery,
+				PublishedAfter:  start,
+				PublishedBefore: end,
+			}
+		}
+
+		result, err := r.scraper.ScrapePlanned(plan, settings.CommentsPerVideo)
+		if err != nil {
+			return baseline, fmt.Errorf("scrape for %s failed: %w", month, err)
+		}
+
+		rawPath := filepath.Join(archiveDir, fmt.Sprintf("youtube_%s.json", month))
+		if err := writeJSON(rawPath, result); err != nil {
+			return baseline, err
+		}
+
+		// A month missing queries or comments would understate its baseline for good,
+		// as recorded months are skipped: leave it out so the next run retries it
+		if len(result.Errors) > 0 {
+			first := result.Errors[0]
+			return baseline, fmt.Errorf("scrape for %s was incomplete: %d error(s) (%s), first: %s; rerun to retry the month",
+				month, len(result.Errors), errorKinds(result.ErrorCounts), first.Message)
+		}
+
+		ytAnalyzer := analyzer.NewYouTubeAnalyzer()
+		analysis := ytAnalyzer.AnalyzeResult(result)
+
+		entry := MonthBaseline{
+			Month:         month,
+			Start:         start,
+			End:           end,
+			Videos:        analysis.TotalVideos,
+			Comments:      analysis.TotalComments,
+			TotalIssues:   analysis.TotalIssues,
+			Categories:    make(map[string]int),
+			CompletedAt:   time.Now(),
+			ArchivedAsRaw: rawPath,
+		}
+		for _, summary := range analysis.IssuesByCategory {
+			entry.Categories[summary.Category] = summary.Count
+		}
+		for _, stat := range result.QueryStats {
+			entry.QuotaUsed += stat.QuotaUsed
+		}
+
+		baseline.Months = append(baseline.Months, entry)
+		sort.Slice(baseline.Months, func(a, b int) bool {
+			return baseline.Months[a].Month < baseline.Months[b].Month
+		})
+		baseline.UpdatedAt = time.Now()
+
+		// Save after every month so progress survives quota exhaustion
+		if err := writeJSON(filepath.Join(r.dataDir, BaselineFile), baseline); err != nil {
+			return baseline, err
+		}
+		fmt.Printf("✅ %s: %d issues from %d videos (%d quota units)\n", month, entry.TotalIssues, entry.Videos, entry.QuotaUsed)
+	}
+
+	return baseline, nil
+}
+
+// CategorySeries returns the monthly counts for one category, oldest first
+func (b *Baseline) CategorySeries(category string) []int {
+	series := make([]int, len(b.Months))
+	for i, m := range b.Months {
+		series[i] = m.Categories[category]
+	}
+	return series
+}
+
+// errorKinds renders error counts by kind, e.g. "quota: 2, network: 1"
+func errorKinds(counts map[string]int) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for i, kind := range kinds {
+		kinds[i] = fmt.Sprintf("%s: %d", kind, counts[kind])
+	}
+	return strings.Join(kinds, ", ")
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := integrity.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// In-memory TTL cache used in front of expensive API endpoints
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// entry is a single cached value with its expiry
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+// Cache is a concurrency-safe key/value store where every entry expires after a TTL
+type Cache struct {
+	entries    map[string]entry
+	ttl        time.Duration
+	generation uint64 // Bumped by Purge, so values computed before it aren't stored after it
+	mu         sync.RWMutex
+}
+
+// New creates a cache with a default TTL
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		entries: make(map[string]entry),
+		ttl:     ttl,
+	}
+}
+
+// Get returns a cached value if present and not expired
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set stores a value using the default TTL
+func (c *Cache) Set(key string, value any) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores a value with an explicit TTL
+func (c *Cache) SetWithTTL(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry{value: value, expires: time.Now().Add(ttl)}
+	c.evictExpired()
+}
+
+// Generation returns a token that changes on every Purge. Take it before computing
+// a value and store the value with SetIfCurrent.
+func (c *Cache) Generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// SetIfCurrent stores a value with an explicit TTL unless the cache was purged since
+// Generation returned generation, in which case the value may be stale and is dropped
+func (c *Cache) SetIfCurrent(generation uint64, key string, value any, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != generation {
+		return false
+	}
+	c.entries[key] = entry{value: value, expires: time.Now().Add(ttl)}
+	c.evictExpired()
+	return true
+}
+
+// Delete removes a single key
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Purge removes every entry (used when the underlying data is reloaded)
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]entry)
+	c.generation++
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *Cache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// evictExpired drops expired entries. Must be called with c.mu held.
+func (c *Cache) evictExpired() {
+	now := time.Now()
+	for key, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// ============================================
+// HTTP RESPONSE CACHING
+// ============================================
+
+// cachedResponse is a fully serialized HTTP response
+type cachedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// responseRecorder captures a handler's response while still writing it to the client
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware caches successful GET responses of next for ttl, keyed by the request URI.
+// Cached responses are replayed byte-for-byte so the handler neither recomputes
+// nor re-serializes the payload. A response still being written when the cache is
+// purged is served but not cached.
+func (c *Cache) Middleware(ttl time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return c.ScopedMiddleware(ttl, nil, next)
+}
+
+// ScopedMiddleware is Middleware for responses that depend on who is asking: scope
+// names the caller's view (e.g. their tenant) and is part of the key, so one
+// caller's response is never replayed to another
+func (c *Cache) ScopedMiddleware(ttl time.Duration, scope func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+
+		key := "http:" + r.URL.RequestURI()
+		if scope != nil {
+			key = "http[" + scope(r) + "]:" + r.URL.RequestURI()
+		}
+		if v, ok := c.Get(key); ok {
+			cached := v.(*cachedResponse)
+			for k, values := range cached.header {
+				w.Header()[k] = values
+			}
+			w.Header().Set("X-Cache", "HIT")
+			w.WriteHeader(cached.status)
+			w.Write(cached.body)
+			return
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		generation := c.Generation()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status == http.StatusOK {
+			header := w.Header().Clone()
+			header.Del("X-Cache")
+			c.SetIfCurrent(generation, key, &cachedResponse{
+				status: rec.status,
+				header: header,
+				body:   rec.body.Bytes(),
+			}, ttl)
+		}
+	}
+}
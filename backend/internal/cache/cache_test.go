@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestMiddlewareDropsResponsesAfterPurge checks a response computed while the cache
+// is purged isn't cached, so the next request recomputes it from the new data
+func TestMiddlewareDropsResponsesAfterPurge(t *testing.T) {
+	c := New(time.Minute)
+	data := "old"
+	handler := c.Middleware(time.Minute, func(w http.ResponseWriter, r *http.Request) {
+		body := data
+		if body == "old" {
+			data = "new"
+			c.Purge() // A reload lands while the old data is being served
+		}
+		w.Write([]byte(body))
+	})
+
+	get := func() (string, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/trends", nil))
+		return rec.Body.String(), rec.Header().Get("X-Cache")
+	}
+	if body, _ := get(); body != "old" {
+		t.Fatalf("first response %q, want old", body)
+	}
+	if body, hit := get(); body != "new" || hit != "MISS" {
+		t.Fatalf("second response %q (%s), want new from a MISS", body, hit)
+	}
+	if body, hit := get(); body != "new" || hit != "HIT" {
+		t.Fatalf("third response %q (%s), want new from a HIT", body, hit)
+	}
+}
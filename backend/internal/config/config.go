@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // ================================================
 // COINSIGHTS SCRAPER CONFIGURATION
 // ================================================
@@ -84,18 +86,68 @@ var SearchQueries = []string{
 	"coinbase alternatives",
 }
 
+// AISearchQueries - Gemini AI search queries for Coinbase complaints from different sources
+var AISearchQueries = []string{
+	// Query 1: Reddit-focused complaints
+	"coinbase user complaints and problems from reddit discussions 2024 2025",
+	// Query 2: Article/website reviews and complaints
+	"coinbase customer complaints reviews from news articles trustpilot bbb consumer reports",
+	// Query 3: YouTube video content analysis (not comments)
+	"coinbase review video analysis problems issues discussed by youtubers crypto reviewers",
+}
+
+// GoogleSearchQueries - Google web search queries for complaint threads and reviews
+var GoogleSearchQueries = []string{
+	"coinbase complaints",
+	"coinbase withdrawal problems",
+	"coinbase customer support review",
+}
+
+// GoogleResultsPerQuery is how many Google results are kept per query
+const GoogleResultsPerQuery = 10
+
 // ScraperSettings configures how much data to fetch
 type ScraperSettings struct {
-	VideosPerQuery   int // Number of videos to fetch per search query
-	CommentsPerVideo int // Number of comments to fetch per video
-	MaxQueries       int // Max number of queries to run (0 = all)
+	VideosPerQuery   int  `json:"videos_per_query"`   // Number of videos to fetch per search query
+	CommentsPerVideo int  `json:"comments_per_video"` // Number of comments to fetch per video
+	MaxQueries       int  `json:"max_queries"`        // Max number of queries to run (0 = all)
+	Adaptive         bool `json:"adaptive"`           // Allocate quota by past query yield instead of slicing SearchQueries
+	DailyQuota       int  `json:"daily_quota"`        // Quota units the adaptive planner may spend (0 = YouTubeDailyQuota)
+	ExcludeShorts    bool `json:"exclude_shorts"`     // Skip YouTube Shorts - their comments carry a different complaint signal
+	MinVideoSeconds  int  `json:"min_video_seconds"`  // Skip videos shorter than this (0 = no minimum)
+	MaxVideoSeconds  int  `json:"max_video_seconds"`  // Skip videos longer than this (0 = no maximum)
+	MaxPages         int  `json:"max_pages"`          // Pages fetched per search or comment list (0 = as many as the counts above need)
+
+	// Forum sources (Reddit)
+	ThreadDepth       int `json:"thread_depth"`        // Comment levels crawled per thread (0 or 1 = top-level only, MaxThreadDepth = full trees)
+	CommentsPerThread int `json:"comments_per_thread"` // Comments kept per thread across every level (0 = the source's default)
 }
 
+// MaxThreadDepth is the deepest reply level Reddit returns in one thread request
+const MaxThreadDepth = 10
+
+// Forum applies the thread crawl depth and comment cap to a forum source's settings.
+// Deeper crawls see more of each thread but spend the cap on replies, so fewer
+// threads are needed for the same complaint count - the cap keeps one long argument
+// from eating a run's rate limit.
+func (s ScraperSettings) Forum(base RedditSettings) RedditSettings {
+	if s.ThreadDepth > 0 {
+		base.ThreadDepth = s.ThreadDepth
+	}
+	if s.CommentsPerThread > 0 {
+		base.CommentsPerPost = s.CommentsPerThread
+	}
+	return base
+}
+
+// YouTubeDailyQuota is the default YouTube Data API quota per project per day
+const YouTubeDailyQuota = 10000
+
 // DefaultSettings returns the default scraper configuration
 // Calculated for ~5000 quota units/day:
-// - 25 queries × 100 units = 2,500 (search)
+// - 25 queries × 100 units = 2,500 (search, one page each)
 // - 25 queries × 1 unit = 25 (videos.list batched)
-// - 125 videos × 20 comments × 1 unit = 125 (commentThreads)
+// - 125 videos × 1 page of 20 comments × 1 unit = 125 (commentThreads)
 // Total: ~2,650 units (leaves room for retries)
 func DefaultSettings() ScraperSettings {
 	return ScraperSettings{
@@ -123,16 +175,418 @@ func LightSettings() ScraperSettings {
 	}
 }
 
+// Built-in preset names
+const (
+	PresetDefault    = "default"
+	PresetAggressive = "aggressive"
+	PresetLight      = "light"
+)
+
+// BuiltinPresets returns the built-in settings by preset name. Operators can add
+// their own named presets and pick the active one through the admin API.
+func BuiltinPresets() map[string]ScraperSettings {
+	return map[string]ScraperSettings{
+		PresetDefault:    DefaultSettings(),
+		PresetAggressive: AggressiveSettings(),
+		PresetLight:      LightSettings(),
+	}
+}
+
+// MinVideoDuration returns MinVideoSeconds as a time.Duration
+func (s ScraperSettings) MinVideoDuration() time.Duration {
+	return time.Duration(s.MinVideoSeconds) * time.Second
+}
+
+// MaxVideoDuration returns MaxVideoSeconds as a time.Duration
+func (s ScraperSettings) MaxVideoDuration() time.Duration {
+	return time.Duration(s.MaxVideoSeconds) * time.Second
+}
+
 // CalculateQuota estimates API quota usage
 func (s ScraperSettings) CalculateQuota() int {
 	queries := s.MaxQueries
 	if queries == 0 || queries > len(SearchQueries) {
 		queries = len(SearchQueries)
 	}
+	return queries * YouTubeQueryCost(s.VideosPerQuery, s.CommentsPerVideo, s.MaxPages)
+}
+
+// YouTube Data API page sizes and costs. Results past the first page are fetched
+// with nextPageToken; every page is a separate call billed at the full price.
+const (
+	YouTubeSearchPageSize  = 50  // search.list results per page
+	YouTubeCommentPageSize = 100 // commentThreads.list results per page
+	YouTubeVideosBatchSize = 50  // Video IDs per videos.list call
+	YouTubeSearchCost      = 100 // Units per search.list page
+	YouTubeListCost        = 1   // Units per videos.list or commentThreads.list call
+)
+
+// YouTubePages is how many pages of size fetch n results, capped at maxPages (0 = no cap)
+func YouTubePages(n, size, maxPages int) int {
+	pages := (n + size - 1) / size
+	if maxPages > 0 && pages > maxPages {
+		pages = maxPages
+	}
+	return pages
+}
+
+// YouTubeQueryCost estimates the quota units one query spends: the search pages for
+// videos results, their details, and the comment pages for each video
+func YouTubeQueryCost(videos, commentsPerVideo, maxPages int) int {
+	searchPages := YouTubePages(videos, YouTubeSearchPageSize, maxPages)
+	videos = min(videos, searchPages*YouTubeSearchPageSize)
+	detailCalls := YouTubePages(videos, YouTubeVideosBatchSize, 0)
+	commentPages := YouTubePages(commentsPerVideo, YouTubeCommentPageSize, maxPages)
+
+	return searchPages*YouTubeSearchCost + detailCalls*YouTubeListCost + videos*commentPages*YouTubeListCost
+}
+
+// ================================================
+// RATE LIMITS
+// ================================================
+
+// RateLimit throttles outbound requests to one source
+type RateLimit struct {
+	PerSecond float64       // Sustained requests per second (0 = unthrottled)
+	Burst     int           // Requests allowed back-to-back before throttling kicks in (min 1)
+	Jitter    time.Duration // Random extra delay added to each throttled wait
+	DailyCap  int           // Requests per UTC day (0 = no cap)
+}
+
+// RateLimits configures every scraper's throttling in one place, keyed by source.
+// Scrapers for the same source share one limiter, so the budget holds process-wide.
+var RateLimits = map[string]RateLimit{
+	"youtube":      {PerSecond: 2, Burst: 1},                            // Data API quota is budgeted separately by the planner
+	"google":       {PerSecond: 0.5, Burst: 1, Jitter: 1 * time.Second}, // HTML scraping - stay well below bot detection
+	"gemini":       {PerSecond: 0.1, Burst: 1, DailyCap: 1500},          // Free tier: avoids 429s, stays inside the daily request limit
+	"rpc":          {PerSecond: 10, Burst: 5},                           // Chain RPC reads for batch verification - public endpoints throttle bursts
+	"reddit":       {PerSecond: 0.15, Burst: 1},                         // Unauthenticated JSON API allows ~10 requests a minute
+	"reddit_oauth": {PerSecond: 1.5, Burst: 1},                          // OAuth clients get 100 requests a minute
+	"trustpilot":   {PerSecond: 0.2, Burst: 1, Jitter: 2 * time.Second}, // HTML review pages - Cloudflare blocks fast crawls
+	"bbb":          {PerSecond: 0.2, Burst: 1, Jitter: 2 * time.Second}, // HTML complaint pages
+}
+
+// ================================================
+// SCRAPER IDENTITY AND ETIQUETTE
+// ================================================
+// Every scraper introduces itself the same way, so a site operator who sees the
+// traffic can tell who it is and how to reach them: a User-Agent naming the project
+// and linking to it, and the From header (RFC 9110) carrying a contact email when
+// one is configured. How fast each source is crawled is set in RateLimits above.
+
+// ScraperIdentity is how Coinsights introduces itself to the sites it scrapes
+type ScraperIdentity struct {
+	Name    string // Product token in the User-Agent
+	Version string
+	URL     string // Where site operators can read what the scraper is
+	Contact string // Email sent in the From header and the User-Agent (empty = none)
+}
+
+// UserAgent renders the identity as a User-Agent, e.g.
+// "Coinsights/1.0 (+https://github.com/tasnint/Coinsights; ops@example.com)"
+func (id ScraperIdentity) UserAgent() string {
+	details := "+" + id.URL
+	if id.Contact != "" {
+		details += "; " + id.Contact
+	}
+	return id.Name + "/" + id.Version + " (" + details + ")"
+}
+
+// Identity is the scraper identity; SCRAPER_CONTACT_EMAIL sets Contact
+var Identity = ScraperIdentity{
+	Name:    "Coinsights",
+	Version: "1.0",
+	URL:     "https://github.com/tasnint/Coinsights",
+}
+
+// Etiquette is how one source is crawled beyond its rate limit
+type Etiquette struct {
+	UserAgent    string // Replaces Identity.UserAgent() where the source requires its own format
+	OmitContact  bool   // Don't send the From header
+	IgnoreRobots bool   // Don't check robots.txt before HTML scraping
+}
+
+// CrawlEtiquette configures every source's etiquette in one place, keyed by the
+// same source names as RateLimits. Sources not listed get the zero Etiquette:
+// the shared identity, the contact header, and robots.txt respected.
+var CrawlEtiquette = map[string]Etiquette{
+	// robots.txt disallows /search, which is the whole scraper; it is opt-in and throttled hard
+	"google": {IgnoreRobots: true},
+	// Reddit asks for its platform:app:version format
+	"reddit":       {UserAgent: RedditUserAgent},
+	"reddit_oauth": {UserAgent: RedditUserAgent},
+}
+
+// RedditUserAgent identifies the Reddit scraper when REDDIT_USER_AGENT isn't set
+const RedditUserAgent = "web:coinsights:1.0 (complaint research; +https://github.com/tasnint/Coinsights)"
+
+// EtiquetteFor returns a source's etiquette
+func EtiquetteFor(source string) Etiquette {
+	return CrawlEtiquette[source]
+}
+
+// UserAgentFor returns the User-Agent a source is sent
+func UserAgentFor(source string) string {
+	if ua := EtiquetteFor(source).UserAgent; ua != "" {
+		return ua
+	}
+	return Identity.UserAgent()
+}
+
+// ================================================
+// TEXT LIMITS
+// ================================================
+
+// TextLimits caps how much text is kept or shown in each place, in characters (0 = no limit)
+type TextLimits struct {
+	DescriptionScan int // Start of a video description scanned for issues
+	CategoryExample int // Example complaint text kept per category
+	ComplaintTitle  int // Complaint text used in a Gemini complaint's title
+	SummaryComment  int // Comment or complaint text in printed summaries
+	SummaryOverview int // Gemini overview text in printed summaries
+	ErrorMessage    int // Stored scraper error messages
+}
+
+// Truncation is the text limits used by the analyzer, converters and summaries
+var Truncation = TextLimits{
+	DescriptionScan: 500,
+	CategoryExample: 150,
+	ComplaintTitle:  50,
+	SummaryComment:  100,
+	SummaryOverview: 300,
+	ErrorMessage:    300,
+}
+
+// AnalyzerStages is the order the complaint analyzer runs its stages in. A stage
+// registered with analyzer.RegisterStage ("translate", say) runs once named here;
+// ANALYZER_STAGES overrides the order (see analyzer.StagesFromEnv).
+var AnalyzerStages = []string{"normalize", "filter", "classify", "sentiment", "dedup", "aggregate"}
+
+// ================================================
+// ISSUE DETECTION
+// ================================================
+
+// DetectionSettings controls when analysis results automatically open tracked issues
+type DetectionSettings struct {
+	MinComplaints      int            // Complaints a category needs before an issue is opened
+	CategoryThresholds map[string]int // Per-category overrides of MinComplaints
+	MaxLinks           int            // Complaint URLs attached to each issue
+}
+
+// DefaultDetectionSettings returns the default issue detection thresholds
+func DefaultDetectionSettings() DetectionSettings {
+	return DetectionSettings{
+		MinComplaints: 10,
+		CategoryThresholds: map[string]int{
+			"security": 3, // Rare but severe - surface early
+		},
+		MaxLinks: 10,
+	}
+}
+
+// Threshold returns the complaint count that opens an issue for a category
+func (s DetectionSettings) Threshold(category string) int {
+	if t, ok := s.CategoryThresholds[category]; ok {
+		return t
+	}
+	return s.MinComplaints
+}
+
+// ================================================
+// ISSUE SLAS
+// ================================================
+
+// SLASettings sets how long an open issue may go without improving, by severity
+type SLASettings struct {
+	WindowDays map[string]int // Days from first detection, keyed by severity; severities not listed have no SLA
+}
+
+// DefaultSLASettings returns the default SLA windows
+func DefaultSLASettings() SLASettings {
+	return SLASettings{
+		WindowDays: map[string]int{
+			"critical": 14,
+			"high":     21,
+			"medium":   30,
+			"low":      60,
+		},
+	}
+}
 
-	searchUnits := queries * 100                   // search.list = 100 units each
-	videoUnits := queries * 1                      // videos.list = 1 unit (batched per query)
-	commentUnits := queries * s.VideosPerQuery * 1 // commentThreads = 1 unit each
+// Window returns the SLA window for a severity, and false if the severity has none
+func (s SLASettings) Window(severity string) (time.Duration, bool) {
+	days, ok := s.WindowDays[severity]
+	if !ok || days <= 0 {
+		return 0, false
+	}
+	return time.Duration(days) * 24 * time.Hour, true
+}
 
-	return searchUnits + videoUnits + commentUnits
+// ================================================
+// COMPLAINT VELOCITY
+// ================================================
+
+// VelocitySettings controls how fast-growing issues are measured, projected and alerted on
+type VelocitySettings struct {
+	WindowDays         int            // Days of complaints the rate is measured over
+	CriticalComplaints int            // Complaint count the projection counts down to
+	CategoryCritical   map[string]int // Per-category overrides of CriticalComplaints
+	AlertPerDay        float64        // New complaints a day that send a "velocity_alert" (0 = never)
+}
+
+// DefaultVelocitySettings returns the default velocity window and thresholds
+func DefaultVelocitySettings() VelocitySettings {
+	return VelocitySettings{
+		WindowDays:         7,
+		CriticalComplaints: 100,
+		CategoryCritical: map[string]int{
+			"security": 25, // Rare but severe - critical well before it's common
+		},
+		AlertPerDay: 10,
+	}
+}
+
+// Critical returns the complaint count at which an issue in category is critical
+func (s VelocitySettings) Critical(category string) int {
+	if t, ok := s.CategoryCritical[category]; ok {
+		return t
+	}
+	return s.CriticalComplaints
+}
+
+// ================================================
+// GEMINI SOURCE EXPANSION
+// ================================================
+
+// ExpansionSettings controls follow-up queries for thin Gemini answers
+type ExpansionSettings struct {
+	MinComplaints int               // An answer with fewer key complaints is thin (0 = never expand)
+	MinSources    int               // An answer citing fewer sources is thin
+	MaxFollowUps  int               // Follow-up queries per thin answer
+	Platforms     map[string]string // Platform name as Gemini reports it -> domain follow-ups are restricted to
+	WindowDays    int               // Follow-ups only search pages from the last WindowDays days
+}
+
+// DefaultExpansionSettings returns the default thin-answer thresholds
+func DefaultExpansionSettings() ExpansionSettings {
+	return ExpansionSettings{
+		MinComplaints: 3,
+		MinSources:    2,
+		MaxFollowUps:  2,
+		Platforms: map[string]string{
+			"reddit":     "reddit.com",
+			"trustpilot": "trustpilot.com",
+			"twitter":    "x.com",
+			"bbb":        "bbb.org",
+		},
+		WindowDays: 180,
+	}
+}
+
+// ================================================
+// GEMINI SPEND BUDGET
+// ================================================
+
+// GeminiBudget caps what one scrape run may spend on Gemini. Once either cap is
+// reached the remaining queries are skipped and the answers so far are kept.
+type GeminiBudget struct {
+	MaxTokens            int     // Prompt + response tokens per run (0 = no token cap)
+	MaxCostUSD           float64 // Estimated spend per run (0 = no cost cap)
+	InputPerMillionUSD   float64 // Price of 1M prompt tokens
+	OutputPerMillionUSD  float64 // Price of 1M response tokens
+	GroundingPerQueryUSD float64 // Price of one Google Search grounded request
+}
+
+// DefaultGeminiBudget returns the per-run caps, priced for gemini-2.0-flash
+func DefaultGeminiBudget() GeminiBudget {
+	return GeminiBudget{
+		MaxTokens:            500_000,
+		MaxCostUSD:           1.00,
+		InputPerMillionUSD:   0.10,
+		OutputPerMillionUSD:  0.40,
+		GroundingPerQueryUSD: 0.035, // $35 per 1,000 grounded requests
+	}
+}
+
+// Cost estimates the spend for a number of requests and tokens
+func (b GeminiBudget) Cost(requests, promptTokens, responseTokens int) float64 {
+	return float64(requests)*b.GroundingPerQueryUSD +
+		float64(promptTokens)/1e6*b.InputPerMillionUSD +
+		float64(responseTokens)/1e6*b.OutputPerMillionUSD
+}
+
+// ================================================
+// GEMINI GROUNDING DOMAINS
+// ================================================
+// A query about Reddit threads answered from a news roundup, or a review-site query
+// answered from an exchange's own blog, is weak evidence for what users report.
+// Each type of Gemini query can be grounded on its own domains: restricted to them,
+// or only steered toward them. The first rule whose Match a query contains applies;
+// queries matching none search the whole web.
+
+// GroundingRule picks the domains one type of Gemini query is grounded on
+type GroundingRule struct {
+	QueryType string   `json:"query_type"` // Recorded with each answer, e.g. "forum"
+	Match     []string `json:"match"`      // Lowercase query substrings that give a query this type
+	Domains   []string `json:"domains"`    // e.g. "reddit.com"; subdomains count
+	Restrict  bool     `json:"restrict"`   // Drop what other domains grounded, rather than only preferring these
+}
+
+// DefaultGroundingRules keep Reddit queries on Reddit and steer review-site queries
+// to Trustpilot and the BBB
+func DefaultGroundingRules() []GroundingRule {
+	return []GroundingRule{
+		{QueryType: "forum", Match: []string{"reddit"}, Domains: []string{"reddit.com"}, Restrict: true},
+		{QueryType: "reviews", Match: []string{"trustpilot", "bbb"}, Domains: []string{"trustpilot.com", "bbb.org"}},
+	}
+}
+
+// ================================================
+// REDDIT
+// ================================================
+
+// RedditSettings picks what the Reddit scraper searches. Every search term is run
+// inside every subreddit.
+type RedditSettings struct {
+	Subreddits      []string // Without the r/ prefix
+	SearchTerms     []string
+	PostsPerSearch  int    // Newest matching posts per subreddit and term (max 100)
+	CommentsPerPost int    // Comments fetched per post across every level crawled (0 = posts only, max 100)
+	ThreadDepth     int    // Comment levels crawled (0 or 1 = top-level only, max MaxThreadDepth)
+	TimeWindow      string // Reddit's search window: hour, day, week, month, year or all
+}
+
+// DefaultRedditSettings searches the two subreddits most Coinbase complaints land in
+func DefaultRedditSettings() RedditSettings {
+	return RedditSettings{
+		Subreddits: []string{"CoinBase", "CryptoCurrency"},
+		SearchTerms: []string{
+			"coinbase account locked",
+			"coinbase support",
+			"coinbase withdrawal",
+			"coinbase fees",
+		},
+		PostsPerSearch:  25,
+		CommentsPerPost: 20,
+		TimeWindow:      "month",
+	}
+}
+
+// ================================================
+// REVIEW SITES
+// ================================================
+
+// ReviewSettings picks the review pages the review scraper reads
+type ReviewSettings struct {
+	TrustpilotSlug string // Trustpilot company slug, e.g. "www.coinbase.com"
+	Pages          int    // Pages read per site, newest first (Trustpilot shows 20 reviews a page)
+	BBBProfileURL  string // BBB business profile whose complaints are read (empty = Trustpilot only)
+}
+
+// DefaultReviewSettings reads the newest 100 Coinbase reviews on Trustpilot
+func DefaultReviewSettings() ReviewSettings {
+	return ReviewSettings{
+		TrustpilotSlug: "www.coinbase.com",
+		Pages:          5,
+	}
 }
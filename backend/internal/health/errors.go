@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/textutil"
+)
+
+// ============================================
+// SCRAPER ERROR TAXONOMY
+// ============================================
+// Every scraper failure falls into one kind, so "the run found less data" can be
+// traced to quota, credentials, the network, a changed page or a ban after the fact.
+
+// ErrorKind is the category of a scraper failure
+type ErrorKind string
+
+const (
+	ErrorQuota   ErrorKind = "quota"   // API quota, daily cap or rate limit exhausted
+	ErrorAuth    ErrorKind = "auth"    // Missing, invalid or unauthorized credentials
+	ErrorNetwork ErrorKind = "network" // Connection failures, timeouts, 5xx responses
+	ErrorParse   ErrorKind = "parse"   // Response didn't have the expected shape
+	ErrorBlocked ErrorKind = "blocked" // Source is refusing us: captcha, ban, open circuit breaker
+	ErrorOther   ErrorKind = "other"
+)
+
+// ScrapeError is a scraper failure with its kind attached
+type ScrapeError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *ScrapeError) Error() string { return e.Err.Error() }
+func (e *ScrapeError) Unwrap() error { return e.Err }
+
+// NewError tags err with a kind
+func NewError(kind ErrorKind, err error) error {
+	return &ScrapeError{Kind: kind, Err: err}
+}
+
+// StatusError builds the error for a non-2xx API or page response, classified by status and body
+func StatusError(source string, status int, body string) error {
+	err := fmt.Errorf("%s error (status %d): %s", source, status, body)
+	lower := strings.ToLower(body)
+	switch {
+	case status == 429 || strings.Contains(lower, "quota") || strings.Contains(lower, "ratelimitexceeded") ||
+		strings.Contains(lower, "resource_exhausted"):
+		return NewError(ErrorQuota, err)
+	case strings.Contains(lower, "captcha") || strings.Contains(lower, "unusual traffic"):
+		return NewError(ErrorBlocked, err)
+	case status == 401 || (status < 500 && (strings.Contains(lower, "api key") || strings.Contains(lower, "keyinvalid"))):
+		return NewError(ErrorAuth, err)
+	case status == 403:
+		return NewError(ErrorBlocked, err)
+	case status >= 500:
+		return NewError(ErrorNetwork, err)
+	}
+	return NewError(ErrorOther, err)
+}
+
+// Classify returns the kind of a scraper error. Errors not tagged by NewError or
+// StatusError are classified by type and, failing that, by message.
+func Classify(err error) ErrorKind {
+	var scrapeErr *ScrapeError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var netErr net.Error
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &scrapeErr):
+		return scrapeErr.Kind
+	case errors.Is(err, ratelimit.ErrDailyCap):
+		return ErrorQuota
+	case errors.Is(err, httpclient.ErrCircuitOpen):
+		return ErrorBlocked
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return ErrorParse
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr), errors.As(err, &urlErr):
+		return ErrorNetwork
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "status 429") || strings.Contains(msg, "quota") || strings.Contains(msg, "resource_exhausted"):
+		return ErrorQuota
+	case strings.Contains(msg, "captcha") || strings.Contains(msg, "unusual traffic") || strings.Contains(msg, "status 403"):
+		return ErrorBlocked
+	case strings.Contains(msg, "api key") || strings.Contains(msg, "unauthorized") || strings.Contains(msg, "status 401"):
+		return ErrorAuth
+	case strings.Contains(msg, "decode") || strings.Contains(msg, "parse") || strings.Contains(msg, "unmarshal"):
+		return ErrorParse
+	}
+	return ErrorOther
+}
+
+// RunError records a failure during a scrape run, for the run's error breakdown
+func RunError(source, query string, err error) models.RunError {
+	return models.RunError{
+		Source:  source,
+		Kind:    string(Classify(err)),
+		Query:   query,
+		Message: textutil.Truncate(err.Error(), config.Truncation.ErrorMessage),
+		At:      time.Now(),
+	}
+}
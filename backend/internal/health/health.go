@@ -0,0 +1,212 @@
+// Per-source scraper run history, so operators can see which feeds are stale or blocked
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/textutil"
+)
+
+const (
+	// StatusFile is the scraper health file inside the data directory
+	StatusFile = "scraper_health.json"
+	// DefaultStaleAfter flags a source with no successful run in this long (scrapes run daily)
+	DefaultStaleAfter = 36 * time.Hour
+)
+
+// blockedMarkers are error substrings that mean a source is refusing us, not failing transiently
+var blockedMarkers = []string{
+	"status 403", "status 429", "quotaexceeded", "dailylimitexceeded", "ratelimitexceeded",
+	"captcha", "unusual traffic", "resource_exhausted",
+}
+
+// SourceStatus is one scraper source's run history
+type SourceStatus struct {
+	Source            string    `json:"source"`
+	LastRun           time.Time `json:"last_run"`
+	LastSuccess       time.Time `json:"last_success,omitempty"`
+	Successes         int       `json:"successes"`
+	Errors            int       `json:"errors"`
+	ConsecutiveErrors int       `json:"consecutive_errors"`
+	LastError         string    `json:"last_error,omitempty"`
+	LastErrorKind     ErrorKind `json:"last_error_kind,omitempty"`
+	LastErrorAt       time.Time `json:"last_error_at,omitempty"`
+
+	// Errors by kind: over all runs, and in the latest run (including errors a successful run skipped past)
+	ErrorKinds    map[ErrorKind]int `json:"error_kinds,omitempty"`
+	LastRunErrors map[ErrorKind]int `json:"last_run_errors,omitempty"`
+
+	// Blocked is set when the latest failure looks like a ban, quota or rate limit rather than a glitch
+	Blocked       bool   `json:"blocked"`
+	BlockedReason string `json:"blocked_reason,omitempty"`
+
+	// Budget for the current UTC day (QuotaLimit 0 = unlimited)
+	QuotaDay       string `json:"quota_day,omitempty"`
+	QuotaUsed      int    `json:"quota_used"`
+	QuotaLimit     int    `json:"quota_limit"`
+	QuotaRemaining int    `json:"quota_remaining"`
+
+	Stale bool `json:"stale"` // Computed on read: no success within the stale window
+}
+
+// Store keeps per-source status persisted to a JSON file
+type Store struct {
+	path    string
+	sources map[string]*SourceStatus
+	mu      sync.RWMutex
+}
+
+// NewStore loads scraper health from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{
+		path:    filepath.Join(dataDir, StatusFile),
+		sources: make(map[string]*SourceStatus),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scraper health: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.sources); err != nil {
+		return nil, fmt.Errorf("failed to parse scraper health: %w", err)
+	}
+	if s.sources == nil {
+		s.sources = make(map[string]*SourceStatus)
+	}
+	return s, nil
+}
+
+// RecordSuccess records a successful run that spent quotaUsed units of a daily
+// quotaLimit (0 = unlimited). runErrors are failures the run recovered from.
+func (s *Store) RecordSuccess(source string, quotaUsed, quotaLimit int, runErrors []models.RunError) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	status := s.source(source, now)
+	status.LastRun = now
+	status.LastRunErrors = nil
+	for _, e := range runErrors {
+		status.countError(ErrorKind(e.Kind))
+	}
+	status.LastSuccess = now
+	status.Successes++
+	status.ConsecutiveErrors = 0
+	status.Blocked = false
+	status.BlockedReason = ""
+	status.QuotaUsed += quotaUsed
+	status.QuotaLimit = quotaLimit
+	return s.save()
+}
+
+// RecordError records a failed run
+func (s *Store) RecordError(source string, runErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	status := s.source(source, now)
+	status.LastRun = now
+	status.Errors++
+	status.ConsecutiveErrors++
+	status.LastErrorAt = now
+	status.LastError = textutil.Truncate(runErr.Error(), config.Truncation.ErrorMessage)
+	status.LastErrorKind = Classify(runErr)
+	status.LastRunErrors = nil
+	status.countError(status.LastErrorKind)
+	status.Blocked, status.BlockedReason = blockedReason(runErr)
+	return s.save()
+}
+
+// countError adds one error of kind to the overall and latest-run breakdowns
+func (st *SourceStatus) countError(kind ErrorKind) {
+	if st.ErrorKinds == nil {
+		st.ErrorKinds = make(map[ErrorKind]int)
+	}
+	if st.LastRunErrors == nil {
+		st.LastRunErrors = make(map[ErrorKind]int)
+	}
+	st.ErrorKinds[kind]++
+	st.LastRunErrors[kind]++
+}
+
+// Statuses returns every source's status as of now, by source name
+func (s *Store) Statuses(now time.Time, staleAfter time.Duration) []SourceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	today := now.UTC().Format("2006-01-02")
+	statuses := make([]SourceStatus, 0, len(s.sources))
+	for _, status := range s.sources {
+		st := *status
+		if st.QuotaDay != today { // No runs yet today
+			st.QuotaUsed = 0
+		}
+		if st.QuotaLimit > 0 {
+			st.QuotaRemaining = max(st.QuotaLimit-st.QuotaUsed, 0)
+		}
+		st.Stale = st.LastSuccess.IsZero() || now.Sub(st.LastSuccess) > staleAfter
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Source < statuses[j].Source })
+	return statuses
+}
+
+// source returns the status for a source, rolling its quota over at UTC midnight.
+// Must be called with s.mu held.
+func (s *Store) source(name string, now time.Time) *SourceStatus {
+	status, ok := s.sources[name]
+	if !ok {
+		status = &SourceStatus{Source: name}
+		s.sources[name] = status
+	}
+	if today := now.UTC().Format("2006-01-02"); status.QuotaDay != today {
+		status.QuotaDay = today
+		status.QuotaUsed = 0
+	}
+	return status
+}
+
+// save writes the store to disk. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.sources, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scraper health: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write scraper health: %w", err)
+	}
+	return nil
+}
+
+// blockedReason reports whether an error means the source is refusing requests
+func blockedReason(err error) (bool, string) {
+	switch {
+	case errors.Is(err, ratelimit.ErrDailyCap):
+		return true, "daily request cap reached"
+	case errors.Is(err, httpclient.ErrCircuitOpen):
+		return true, "circuit breaker open after repeated failures"
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range blockedMarkers {
+		if strings.Contains(msg, marker) {
+			return true, "source refused requests (" + marker + ")"
+		}
+	}
+	return false, ""
+}
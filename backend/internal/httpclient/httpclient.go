@@ -0,0 +1,205 @@
+// Shared outbound HTTP client for scrapers: timeout tiers, retries and per-host circuit breakers
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/retry"
+)
+
+// Tier picks the overall time budget for a request, retries included
+type Tier time.Duration
+
+// Timeout tiers
+const (
+	Fast     = Tier(10 * time.Second) // Small JSON lookups
+	Standard = Tier(30 * time.Second) // API pages and HTML pages
+	Slow     = Tier(2 * time.Minute)  // Large downloads
+)
+
+// ErrCircuitOpen is returned without contacting the host while its breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// BreakerPolicy controls when a host's circuit breaker opens
+type BreakerPolicy struct {
+	Failures int           // Consecutive failed requests that open the breaker
+	Cooldown time.Duration // How long it stays open before one trial request is let through
+}
+
+// Transport is an http.RoundTripper adding retries, circuit breaking and the scraper
+// identity on requests that don't set their own.
+// Retries only apply to GET and HEAD, so they are always safe to repeat.
+type Transport struct {
+	Base    http.RoundTripper
+	Retry   retry.Policy
+	Breaker BreakerPolicy
+
+	breakers map[string]*breaker // host -> breaker
+	mu       sync.Mutex
+}
+
+// DefaultTransport is shared by every client from New, so breaker state is process-wide
+var DefaultTransport = NewTransport(http.DefaultTransport)
+
+// NewTransport wraps base with the default retry and breaker policies
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base: base,
+		Retry: retry.Policy{
+			MaxRetries: 2,
+			BaseDelay:  500 * time.Millisecond,
+			MaxDelay:   10 * time.Second,
+			Jitter:     500 * time.Millisecond,
+		},
+		Breaker: BreakerPolicy{
+			Failures: 5,
+			Cooldown: time.Minute,
+		},
+		breakers: make(map[string]*breaker),
+	}
+}
+
+// New returns a client with the tier's timeout using DefaultTransport
+func New(tier Tier) *http.Client {
+	return &http.Client{
+		Timeout:   time.Duration(tier),
+		Transport: DefaultTransport,
+	}
+}
+
+// Identify sets the User-Agent and From headers config.CrawlEtiquette gives source,
+// replacing any already set. An unlisted source, or "", gets config.Identity.
+func Identify(req *http.Request, source string) {
+	req.Header.Set("User-Agent", config.UserAgentFor(source))
+	if config.Identity.Contact != "" && !config.EtiquetteFor(source).OmitContact {
+		req.Header.Set("From", config.Identity.Contact)
+	}
+}
+
+// IdentityFromEnv applies SCRAPER_CONTACT_EMAIL to base
+func IdentityFromEnv(base config.ScraperIdentity) (config.ScraperIdentity, error) {
+	contact := strings.TrimSpace(os.Getenv("SCRAPER_CONTACT_EMAIL"))
+	if contact == "" {
+		return base, nil
+	}
+	if !strings.Contains(contact, "@") || strings.ContainsAny(contact, " ;()") {
+		return base, fmt.Errorf("SCRAPER_CONTACT_EMAIL %q is not an email address", contact)
+	}
+	base.Contact = contact
+	return base, nil
+}
+
+// errTransientStatus marks an attempt answered with a status worth retrying
+var errTransientStatus = errors.New("transient status")
+
+// RoundTrip sends req, retrying transient failures while the host's breaker allows it.
+// When the retries run out on a 429 or 5xx, that last response is returned.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		Identify(req, "")
+	}
+
+	b := t.breaker(req.URL.Host)
+	policy := t.Retry
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || (req.Body != nil && req.Body != http.NoBody) {
+		policy.MaxRetries = 0
+	}
+
+	var resp *http.Response
+	err := retry.Do(req.Context(), policy, nil, func(attempt int) error {
+		if resp != nil {
+			resp.Body.Close() // The previous attempt's transient response
+			resp = nil
+		}
+		if !b.allow() {
+			return retry.Permanent(fmt.Errorf("%s: %w", req.URL.Host, ErrCircuitOpen))
+		}
+
+		r, err := t.Base.RoundTrip(req)
+		failed := err != nil || retry.Status(r.StatusCode)
+		b.record(!failed, t.Breaker)
+		if err != nil {
+			return err
+		}
+		resp = r
+		if !retry.Status(r.StatusCode) {
+			return nil
+		}
+		if delay, ok := retry.HeaderDelay(r.Header); ok {
+			return retry.After(errTransientStatus, delay)
+		}
+		return errTransientStatus
+	})
+	if errors.Is(err, errTransientStatus) {
+		return resp, nil
+	}
+	if err != nil && resp != nil {
+		resp.Body.Close()
+		resp = nil
+	}
+	return resp, err
+}
+
+func (t *Transport) breaker(host string) *breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.breakers[host]
+	if !ok {
+		b = &breaker{}
+		t.breakers[host] = b
+	}
+	return b
+}
+
+// ============================================
+// CIRCUIT BREAKER
+// ============================================
+
+// breaker tracks consecutive failures for one host
+type breaker struct {
+	failures  int
+	openUntil time.Time
+	trial     bool // A half-open trial request is in flight
+	mu        sync.Mutex
+}
+
+// allow reports whether a request may go out. Once the cooldown passes, a single
+// trial request is let through; its outcome closes or re-opens the breaker.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// record updates the breaker with a request outcome
+func (b *breaker) record(ok bool, policy BreakerPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if ok {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failures++
+	if policy.Failures > 0 && b.failures >= policy.Failures {
+		b.openUntil = time.Now().Add(policy.Cooldown)
+	}
+}
@@ -0,0 +1,214 @@
+// Complaints pushed by partners from feeds Coinsights can't scrape itself
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/jsonstream"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/normalize"
+)
+
+const (
+	// ComplaintsFile holds every ingested complaint inside the data directory
+	ComplaintsFile = "ingested_complaints.json"
+	// SourcePrefix marks ingested complaints; the partner name follows, e.g. "partner:acme"
+	SourcePrefix = "partner:"
+	// MaxDescriptionLength rejects records that are documents rather than complaints
+	MaxDescriptionLength = 10000
+)
+
+// Record is one externally collected complaint, one JSON object per ND-JSON line
+type Record struct {
+	SourceID    string    `json:"source_id"`             // Partner's own ID for the record (required, used for de-duplication)
+	Title       string    `json:"title,omitempty"`       // e.g. thread or review title
+	Description string    `json:"description"`           // Complaint text (required)
+	URL         string    `json:"url,omitempty"`         // Link to the original, if public
+	Author      string    `json:"author,omitempty"`      // Username at the source
+	PublishedAt time.Time `json:"published_at"`          // When it was posted (required)
+	Category    string    `json:"category,omitempty"`    // Taxonomy ID or alias; inferred from the text if empty
+	Language    string    `json:"language,omitempty"`    // ISO 639-1 code
+	AppVersion  string    `json:"app_version,omitempty"` // Parsed from the text if empty
+	OS          string    `json:"os,omitempty"`
+}
+
+// Validate checks that a record has what the analysis pipeline needs
+func (r Record) Validate(now time.Time) error {
+	switch {
+	case strings.TrimSpace(r.SourceID) == "":
+		return fmt.Errorf("source_id is required")
+	case strings.TrimSpace(r.Description) == "":
+		return fmt.Errorf("description is required")
+	case len(r.Description) > MaxDescriptionLength:
+		return fmt.Errorf("description is longer than %d bytes", MaxDescriptionLength)
+	case r.PublishedAt.IsZero():
+		return fmt.Errorf("published_at is required")
+	case r.PublishedAt.After(now.Add(normalize.MaxFutureSkew)):
+		return fmt.Errorf("published_at is in the future")
+	}
+	if r.URL != "" {
+		if u, err := url.Parse(r.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("url must be an http(s) URL")
+		}
+	}
+	if r.Category != "" {
+		if _, ok := models.LookupCategory(r.Category); !ok {
+			return fmt.Errorf("unknown category %q", r.Category)
+		}
+	}
+	return nil
+}
+
+// Complaints converts a record into the pipeline's complaints: one per category, like
+// the YouTube analyzer. Records that match no category are kept as "other".
+func (r Record) Complaints(partner, runID string, now time.Time) []models.Complaint {
+	source := SourcePrefix + partner
+
+	categories := []string{models.NormalizeCategory(r.Category)}
+	if r.Category == "" {
+		categories = normalize.Categorize(r.Title + "\n" + r.Description)
+	}
+
+	complaints := make([]models.Complaint, 0, len(categories))
+	for _, category := range categories {
+		c, err := normalize.Normalize(models.Complaint{
+			ID:          models.ComplaintID(source, r.SourceID, category),
+			Source:      source,
+			Title:       r.Title,
+			Description: r.Description,
+			URL:         r.URL,
+			Author:      r.Author,
+			PublishedAt: r.PublishedAt,
+			ScrapedAt:   now,
+			Category:    category,
+			Language:    r.Language,
+			AppVersion:  r.AppVersion,
+			OS:          r.OS,
+			RunID:       runID,
+			SourceID:    r.SourceID,
+		}, now)
+		if err != nil {
+			// Validate has already rejected anything Normalize would
+			continue
+		}
+		complaints = append(complaints, c)
+	}
+	return complaints
+}
+
+// ============================================
+// PARTNER KEYS
+// ============================================
+
+// Keys maps API keys to the partner they belong to
+type Keys map[string]string
+
+// ParseKeys reads "partner:key,partner:key" (the INGEST_API_KEYS format)
+func ParseKeys(s string) (Keys, error) {
+	keys := make(Keys)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		partner, key, ok := strings.Cut(entry, ":")
+		partner, key = strings.TrimSpace(partner), strings.TrimSpace(key)
+		if !ok || partner == "" || key == "" {
+			return nil, fmt.Errorf("invalid ingest key entry %q, want partner:key", entry)
+		}
+		keys[key] = strings.ToLower(partner)
+	}
+	return keys, nil
+}
+
+// KeysFromEnv reads partner keys from INGEST_API_KEYS. No keys means ingestion is disabled.
+func KeysFromEnv() (Keys, error) {
+	return ParseKeys(os.Getenv("INGEST_API_KEYS"))
+}
+
+// ============================================
+// STORE
+// ============================================
+
+// Store keeps ingested complaints persisted to a JSON file, de-duplicated by ID
+type Store struct {
+	path       string
+	complaints []models.Complaint
+	ids        map[string]bool
+	mu         sync.RWMutex
+}
+
+// NewStore loads ingested complaints from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{
+		path: filepath.Join(dataDir, ComplaintsFile),
+		ids:  make(map[string]bool),
+	}
+
+	complaints, err := LoadComplaints(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	s.complaints = complaints
+	for _, c := range complaints {
+		s.ids[c.ID] = true
+	}
+	return s, nil
+}
+
+// Add stores the complaints it hasn't seen before and returns how many were new
+func (s *Store) Add(complaints []models.Complaint) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := 0
+	for _, c := range complaints {
+		if s.ids[c.ID] {
+			continue
+		}
+		s.ids[c.ID] = true
+		s.complaints = append(s.complaints, c)
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.save()
+}
+
+// save writes the store to disk. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.complaints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ingested complaints: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ingested complaints: %w", err)
+	}
+	return nil
+}
+
+// LoadComplaints reads the ingested complaints in dataDir (none if the file doesn't exist yet)
+func LoadComplaints(dataDir string) ([]models.Complaint, error) {
+	dec, release, err := jsonstream.Open(filepath.Join(dataDir, ComplaintsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ingested complaints: %w", err)
+	}
+	defer release()
+
+	complaints, err := jsonstream.Array[models.Complaint](dec, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ingested complaints: %w", err)
+	}
+	return complaints, nil
+}
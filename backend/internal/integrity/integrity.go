@@ -0,0 +1,220 @@
+// SHA-256 checksums for the scrape and analysis files the API serves from
+package integrity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================
+// ARTIFACT CHECKSUMS
+// ============================================
+// Resolutions are attested on-chain from evidence built out of the scrape and
+// analysis files, so a file edited by hand or truncated by a bad disk would
+// quietly turn into "verified" evidence. Every artifact is written through
+// WriteFile, which records its SHA-256 in a checksums.json next to it, and
+// checked before it is loaded again. A file with no recorded checksum (written
+// before checksums existed, or copied in by hand) is trusted as before; a file
+// whose checksum doesn't match is reported rather than loaded.
+
+const (
+	// ManifestFile holds the checksums of the artifacts in its directory
+	ManifestFile = "checksums.json"
+)
+
+// Problem kinds
+const (
+	ProblemModified = "modified" // Contents don't match the recorded checksum
+	ProblemMissing  = "missing"  // Recorded but no longer on disk
+)
+
+// Entry is one artifact's recorded checksum
+type Entry struct {
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	WrittenAt time.Time `json:"written_at"`
+}
+
+// Problem is an artifact that failed verification
+type Problem struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"` // "modified" or "missing"
+	Expected string `json:"expected_sha256"`
+	Actual   string `json:"actual_sha256,omitempty"`
+}
+
+// Error reports an artifact that failed verification
+type Error struct {
+	Problem
+}
+
+func (e *Error) Error() string {
+	if e.Kind == ProblemMissing {
+		return fmt.Sprintf("%s is missing (checksum %s recorded)", e.Path, e.Expected)
+	}
+	return fmt.Sprintf("%s failed its integrity check: sha256 %s, expected %s", e.Path, e.Actual, e.Expected)
+}
+
+// mu serializes manifest updates within the process. The scrape commands and the
+// API server write different artifacts, so each file's entry has one writer.
+var mu sync.Mutex
+
+// Sum returns the hex SHA-256 of data
+func Sum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SumFile returns the hex SHA-256 of the file at path
+func SumFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteFile writes data to path and records its checksum in the directory's manifest
+func WriteFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	dir := filepath.Dir(path)
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	manifest[filepath.Base(path)] = Entry{SHA256: Sum(data), Size: int64(len(data)), WrittenAt: time.Now()}
+	return writeManifest(dir, manifest)
+}
+
+// Verify checks the file at path against its recorded checksum, returning an
+// *Error if it doesn't match. Files without a recorded checksum pass.
+func Verify(path string) error {
+	manifest, err := readManifest(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	entry, ok := manifest[filepath.Base(path)]
+	if !ok {
+		return nil
+	}
+	return verifyEntry(path, entry)
+}
+
+// Check verifies every artifact recorded in dir's manifest and in the manifests of
+// its subdirectories
+func Check(dir string) ([]Problem, error) {
+	problems := []Problem{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != ManifestFile {
+			return nil
+		}
+		manifest, err := readManifest(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		for name, entry := range manifest {
+			var invalid *Error
+			err := verifyEntry(filepath.Join(filepath.Dir(path), name), entry)
+			if errors.As(err, &invalid) {
+				invalid.Path, _ = filepath.Rel(dir, invalid.Path)
+				problems = append(problems, invalid.Problem)
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check artifact checksums: %w", err)
+	}
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Path < problems[j].Path })
+	return problems, nil
+}
+
+// Forget drops path's recorded checksum, for artifacts that are deleted on purpose
+func Forget(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	dir := filepath.Dir(path)
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := manifest[filepath.Base(path)]; !ok {
+		return nil
+	}
+	delete(manifest, filepath.Base(path))
+	return writeManifest(dir, manifest)
+}
+
+// verifyEntry checks the file at path against entry
+func verifyEntry(path string, entry Entry) error {
+	actual, err := SumFile(path)
+	if os.IsNotExist(err) {
+		return &Error{Problem{Path: path, Kind: ProblemMissing, Expected: entry.SHA256}}
+	}
+	if err != nil {
+		return err
+	}
+	if actual != entry.SHA256 {
+		return &Error{Problem{Path: path, Kind: ProblemModified, Expected: entry.SHA256, Actual: actual}}
+	}
+	return nil
+}
+
+// readManifest loads dir's manifest (empty if there isn't one yet)
+func readManifest(dir string) (map[string]Entry, error) {
+	manifest := make(map[string]Entry)
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse checksums: %w", err)
+	}
+	if manifest == nil {
+		manifest = make(map[string]Entry)
+	}
+	return manifest, nil
+}
+
+// writeManifest saves dir's manifest
+func writeManifest(dir string, manifest map[string]Entry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checksums: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Streaming JSON decoding for the large data files loaded at startup and on every reload
+package jsonstream
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ============================================
+// STREAMING DECODE
+// ============================================
+// Scrape results and complaint files run to hundreds of megabytes once archives
+// build up. Reading a file whole and then unmarshalling it holds the raw bytes and
+// the decoded values in memory at the same time, and every element of a large
+// array is appended into a slice that keeps doubling. These helpers decode from a
+// pooled read buffer instead, walk big arrays one element at a time, and let
+// callers reuse the backing arrays of slices that only live for one load.
+
+// ReadBufferSize is the size of the pooled read buffers
+const ReadBufferSize = 256 << 10
+
+var readers = sync.Pool{
+	New: func() any { return bufio.NewReaderSize(nil, ReadBufferSize) },
+}
+
+// Open returns a decoder reading the file at path through a pooled buffer.
+// Call the returned function when done to close the file and release the buffer.
+// Errors from opening the file are returned as-is, so os.IsNotExist works on them.
+func Open(path string) (*json.Decoder, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := readers.Get().(*bufio.Reader)
+	r.Reset(f)
+
+	release := func() {
+		r.Reset(nil)
+		readers.Put(r)
+		f.Close()
+	}
+	return json.NewDecoder(r), release, nil
+}
+
+// DecodeFile decodes the JSON file at path into v
+func DecodeFile(path string, v any) error {
+	dec, release, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return dec.Decode(v)
+}
+
+// Array decodes a JSON array (or null) element by element, appending to dst.
+// Elements are decoded in place, so dst's spare capacity must be zeroed.
+func Array[T any](dec *json.Decoder, dst []T) ([]T, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return dst, err
+	}
+	if tok == nil {
+		return dst, nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return dst, fmt.Errorf("expected array, got %v", tok)
+	}
+
+	var zero T
+	for dec.More() {
+		dst = append(dst, zero)
+		if err := dec.Decode(&dst[len(dst)-1]); err != nil {
+			return dst[:len(dst)-1], err
+		}
+	}
+	_, err = dec.Token() // closing ]
+	return dst, err
+}
+
+// Object walks a JSON object, handing the decoder to fields[key] to decode each
+// value. Keys without a field are skipped.
+func Object(dec *json.Decoder, fields map[string]func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		field, ok := fields[key]
+		if !ok {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := field(); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	_, err = dec.Token() // closing }
+	return err
+}
+
+// SlicePool recycles the backing arrays of large slices that are rebuilt on every load
+type SlicePool[T any] struct {
+	pool sync.Pool
+}
+
+// Get returns an empty slice, with the capacity of a previously released one if available
+func (p *SlicePool[T]) Get() []T {
+	if s, ok := p.pool.Get().(*[]T); ok {
+		return (*s)[:0]
+	}
+	return nil
+}
+
+// Put zeroes s and keeps its backing array for the next Get. Values copied out of s
+// stay valid, but nothing may keep a pointer into s or a subslice of it.
+func (p *SlicePool[T]) Put(s []T) {
+	if cap(s) == 0 {
+		return
+	}
+	s = s[:cap(s)]
+	clear(s)
+	p.pool.Put(&s)
+}
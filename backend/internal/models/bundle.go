@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// ============================================
+// EVIDENCE BUNDLES
+// ============================================
+
+// EvidenceBundle is the full evidence behind a resolution: the measurements the
+// ResolutionEvidence summarizes, and the complaints they were taken from. It is
+// stored under the evidence hash, so anyone holding an on-chain attestation can
+// fetch the artifact it commits to and re-derive every number in it.
+type EvidenceBundle struct {
+	EvidenceHash string `json:"evidence_hash"`          // Keccak256 of Evidence, as attested on-chain
+	EvidenceCID  string `json:"evidence_cid,omitempty"` // IPFS CID of Evidence's canonical JSON, once pinned
+	IssueID      string `json:"issue_id"`
+	Exchange     string `json:"exchange"`
+	Category     string `json:"category"`
+	AppVersion   string `json:"app_version,omitempty"` // Version scope: only complaints about it are counted
+	OS           string `json:"os,omitempty"`
+
+	Before BundlePeriod `json:"before"` // Equally long period ending where the window starts
+	After  BundlePeriod `json:"after"`  // The measurement window
+
+	SentimentCurve   []CurvePoint       `json:"sentiment_curve"`   // Daily volume and sentiment across both periods
+	SampleComplaints []Complaint        `json:"sample_complaints"` // The complaints Evidence.SampleComplaints names
+	Sources          map[string]int     `json:"sources"`           // Complaints per data source across both periods
+	Evidence         ResolutionEvidence `json:"evidence"`          // What gets hashed and attested
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BundlePeriod summarizes an issue's complaints over one period
+type BundlePeriod struct {
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Complaints int            `json:"complaints"`
+	Sentiment  float64        `json:"sentiment"` // Mean over complaints that expressed any sentiment, -1 to 1
+	Scored     int            `json:"scored"`    // Complaints that went into Sentiment
+	Sources    map[string]int `json:"sources"`   // Complaints per data source
+}
+
+// CurvePoint is one day of an issue's complaint volume and sentiment
+type CurvePoint struct {
+	Date       string  `json:"date"` // YYYY-MM-DD (UTC)
+	Complaints int     `json:"complaints"`
+	Sentiment  float64 `json:"sentiment"`
+	Scored     int     `json:"scored"`
+}
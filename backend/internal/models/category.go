@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// ============================================
+// COMPLAINT CATEGORY TAXONOMY
+// ============================================
+
+// Category IDs shared by the analyzer, Gemini converter, API and resolution service
+const (
+	CategoryCustomerSupport = "customer_support"
+	CategoryAccountLocked   = "account_locked"
+	CategoryFees            = "fees"
+	CategoryWithdrawal      = "withdrawal"
+	CategorySecurity        = "security"
+	CategoryVerification    = "verification"
+	CategoryAppBugs         = "app_bugs"
+	CategoryDeposits        = "deposits"
+	CategoryTrading         = "trading"
+	CategoryGeneralNegative = "general_negative"
+	CategoryOther           = "other"
+)
+
+// Category is one complaint category in the taxonomy
+type Category struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`     // Display name
+	Severity string   `json:"severity"` // Default issue severity: "high", "medium", "low"
+	Aliases  []string `json:"aliases"`  // Other names sources use for this category
+}
+
+// DefaultCategories is the built-in complaint taxonomy. Aliases cover the names Gemini
+// is prompted with and common variations, so every source maps onto the same IDs.
+var DefaultCategories = []Category{
+	{ID: CategoryCustomerSupport, Name: "Customer Support", Severity: "high",
+		Aliases: []string{"support", "customer_service", "customer service"}},
+	{ID: CategoryAccountLocked, Name: "Account Locked/Frozen", Severity: "high",
+		Aliases: []string{"account_issues", "account issues", "account", "frozen_account", "locked_account"}},
+	{ID: CategoryFees, Name: "High Fees", Severity: "medium",
+		Aliases: []string{"fee", "high_fees", "pricing"}},
+	{ID: CategoryWithdrawal, Name: "Withdrawal Problems", Severity: "high",
+		Aliases: []string{"withdrawals", "withdrawal_problems", "withdrawal problems", "withdrawal_issues"}},
+	{ID: CategorySecurity, Name: "Security Issues", Severity: "high",
+		Aliases: []string{"security_issues", "fraud", "hack", "scam"}},
+	{ID: CategoryVerification, Name: "Verification Issues", Severity: "medium",
+		Aliases: []string{"kyc", "identity_verification", "verification_issues"}},
+	{ID: CategoryAppBugs, Name: "App/Technical Issues", Severity: "medium",
+		Aliases: []string{"app", "bugs", "technical", "technical_issues", "app_issues"}},
+	{ID: CategoryDeposits, Name: "Deposit Problems", Severity: "high",
+		Aliases: []string{"deposit", "deposit_problems", "deposit_issues"}},
+	{ID: CategoryTrading, Name: "Trading Issues", Severity: "medium",
+		Aliases: []string{"trade", "trading_issues", "orders"}},
+	{ID: CategoryGeneralNegative, Name: "General Complaints", Severity: "low",
+		Aliases: []string{"general", "general_complaints"}},
+	{ID: CategoryOther, Name: "Other", Severity: "low"},
+}
+
+// taxonomy is the taxonomy in use when it has been edited (nil = DefaultCategories)
+var taxonomy atomic.Pointer[[]Category]
+
+// Categories returns the taxonomy in use: DefaultCategories unless replaced by SetCategories.
+// Callers must not modify the returned slice.
+func Categories() []Category {
+	if cs := taxonomy.Load(); cs != nil {
+		return *cs
+	}
+	return DefaultCategories
+}
+
+// SetCategories replaces the taxonomy, e.g. with one edited through the admin API
+func SetCategories(categories []Category) {
+	taxonomy.Store(&categories)
+}
+
+// LookupCategory finds a category by ID, display name or alias (case-insensitive)
+func LookupCategory(name string) (Category, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, c := range Categories() {
+		if key == c.ID || key == strings.ToLower(c.Name) {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if key == alias {
+				return c, true
+			}
+		}
+	}
+	return Category{}, false
+}
+
+// NormalizeCategory maps any source's category name to a taxonomy ID,
+// falling back to CategoryOther for names the taxonomy doesn't know
+func NormalizeCategory(name string) string {
+	if c, ok := LookupCategory(name); ok {
+		return c.ID
+	}
+	return CategoryOther
+}
+
+// CategoryName returns the display name for a category ID (the ID itself if unknown)
+func CategoryName(id string) string {
+	if c, ok := LookupCategory(id); ok {
+		return c.Name
+	}
+	return id
+}
@@ -1,20 +1,89 @@
 package models
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
 
 // Complaint represents a user complaint or negative feedback about Coinbase
 type Complaint struct {
-	ID          string    `json:"id"`
-	Source      string    `json:"source"`       // "youtube" or "google"
-	Title       string    `json:"title"`        // Video title or search result title
-	Description string    `json:"description"`  // Comment text or snippet
-	URL         string    `json:"url"`          // Link to source
-	Author      string    `json:"author"`       // Username or channel name
-	PublishedAt time.Time `json:"published_at"` // When it was posted
-	ScrapedAt   time.Time `json:"scraped_at"`   // When we found it
-	Sentiment   string    `json:"sentiment"`    // "negative", "neutral", "positive"
-	Category    string    `json:"category"`     // "fees", "support", "security", etc.
-	Likes       int       `json:"likes"`        // Engagement metric
+	ID          string             `json:"id"`
+	Source      string             `json:"source"`            // "youtube" or "google"
+	Title       string             `json:"title"`             // Video title or search result title
+	Description string             `json:"description"`       // Comment text or snippet
+	URL         string             `json:"url"`               // Link to source
+	Author      string             `json:"author"`            // Username or channel name
+	PublishedAt time.Time          `json:"published_at"`      // When it was posted
+	ScrapedAt   time.Time          `json:"scraped_at"`        // When we found it
+	Sentiment   string             `json:"sentiment"`         // "negative", "neutral", "positive"
+	Category    string             `json:"category"`          // "fees", "support", "security", etc.
+	Likes       int                `json:"likes"`             // Engagement metric
+	Context     *EngagementContext `json:"context,omitempty"` // Where the complaint sat when it was found
+
+	// Set when Description is an English translation
+	OriginalText string `json:"original_text,omitempty"`
+	Language     string `json:"language,omitempty"` // ISO 639-1 code of the original
+
+	// Star rating a review-site complaint came with, on a 0-5 scale (0 = not a rated review)
+	Rating float64 `json:"rating,omitempty"`
+
+	// App build the complaint is about; reported by app-store reviews, parsed from text otherwise
+	AppVersion string `json:"app_version,omitempty"` // e.g. "12.3.1"
+	OS         string `json:"os,omitempty"`          // "ios", "android"
+
+	// Provenance: where the record came from, so evidence can reference it across runs
+	RunID    string `json:"run_id,omitempty"`    // Scrape run that produced it
+	SourceID string `json:"source_id,omitempty"` // Raw ID at the source, e.g. a YouTube comment or video ID
+
+	// Watch program an ingested complaint belongs to; empty for the deployment's own
+	Tenant string `json:"tenant,omitempty"`
+
+	// Domains of the pages Google Search grounded a Gemini complaint on
+	GroundingDomains []string `json:"grounding_domains,omitempty"`
+
+	// Category keywords that put a keyword-categorized complaint in Category
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Sources that re-report the same complaint every run (Gemini) track when it was first and last seen
+	FirstSeen time.Time `json:"first_seen,omitzero"`
+	LastSeen  time.Time `json:"last_seen,omitzero"`
+
+	// Where the keywords behind Category appear; filled in when complaints are served
+	Highlights []Highlight `json:"highlights,omitempty"`
+}
+
+// Highlight is one occurrence of a categorizing keyword in a complaint's title or description
+type Highlight struct {
+	Field   string `json:"field"` // "title" or "description"
+	Keyword string `json:"keyword"`
+	Start   int    `json:"start"` // Offsets into the field in UTF-16 code units, i.e. JavaScript string indexes
+	End     int    `json:"end"`
+	Snippet string `json:"snippet"` // The match with some surrounding text
+}
+
+// ComplaintID returns a stable ID for the complaint a source record yields in a category.
+// The same record always gets the same ID, however many runs re-scrape it.
+func ComplaintID(source, sourceID, category string) string {
+	sum := sha256.Sum256([]byte(source + "\x00" + sourceID + "\x00" + category))
+	return "c_" + hex.EncodeToString(sum[:8])
+}
+
+// NewRunID identifies a scrape run by its start time
+func NewRunID(startedAt time.Time) string {
+	return "run_" + startedAt.UTC().Format("20060102T150405Z")
+}
+
+// EngagementContext preserves the surroundings of a complaint so reviewers can see
+// why it was trusted without re-fetching the source
+type EngagementContext struct {
+	VideoTitle      string `json:"video_title,omitempty"`
+	VideoViewCount  int64  `json:"video_view_count,omitempty"`
+	VideoLikeCount  int64  `json:"video_like_count,omitempty"`
+	CommentPosition int    `json:"comment_position,omitempty"` // 1-based rank in the fetched comment order
+	ReplyCount      int    `json:"reply_count,omitempty"`
+	ThreadURL       string `json:"thread_url,omitempty"`   // Discussion thread the complaint sits in (Reddit)
+	ThreadScore     int    `json:"thread_score,omitempty"` // Net upvotes on that thread
 }
 
 // Thumbnail represents a YouTube thumbnail image
@@ -46,11 +115,14 @@ type YouTubeVideo struct {
 	LiveBroadcastContent string     `json:"live_broadcast_content"` // "upcoming", "live", or "none"
 	URL                  string     `json:"url"`
 	// Statistics from videos.list API
-	ViewCount    int64    `json:"view_count"`
-	LikeCount    int64    `json:"like_count"`
-	CommentCount int64    `json:"comment_count"`
-	Duration     string   `json:"duration"` // ISO 8601 duration (e.g., "PT4M13S")
-	Tags         []string `json:"tags"`     // Video tags
+	ViewCount       int64    `json:"view_count"`
+	LikeCount       int64    `json:"like_count"`
+	CommentCount    int64    `json:"comment_count"`
+	Duration        string   `json:"duration"`               // ISO 8601 duration (e.g., "PT4M13S")
+	DurationSeconds int      `json:"duration_seconds"`       // Duration parsed into seconds
+	Tags            []string `json:"tags"`                   // Video tags
+	SearchQuery     string   `json:"search_query,omitempty"` // Query that surfaced this video
+	IsShort         bool     `json:"is_short"`               // YouTube Shorts (vertical, ≤ 3 minutes)
 }
 
 // YouTubeComment represents a comment on a YouTube video
@@ -60,7 +132,13 @@ type YouTubeComment struct {
 	AuthorName  string    `json:"author_name"`
 	Text        string    `json:"text"`
 	LikeCount   int       `json:"like_count"`
+	ReplyCount  int       `json:"reply_count"`
+	Position    int       `json:"position"` // 1-based rank in the video's comment order (relevance)
 	PublishedAt time.Time `json:"published_at"`
+
+	// Set when Text has been translated to English
+	OriginalText string `json:"original_text,omitempty"`
+	Language     string `json:"language,omitempty"` // ISO 639-1 code of the original
 }
 
 // GoogleResult represents a Google search result
@@ -78,6 +156,127 @@ type ScrapeResult struct {
 	Comments      []YouTubeComment `json:"comments"`
 	GoogleResults []GoogleResult   `json:"google_results"`
 	Complaints    []Complaint      `json:"complaints"`
+	QueryStats    []QueryStat      `json:"query_stats,omitempty"`
+	Errors        []RunError       `json:"errors,omitempty"`       // Failures the run skipped past
+	ErrorCounts   map[string]int   `json:"error_counts,omitempty"` // Errors by kind (quota, auth, network, parse, blocked, other)
+	RunID         string           `json:"run_id,omitempty"`
 	ScrapedAt     time.Time        `json:"scraped_at"`
 	Query         string           `json:"query"`
 }
+
+// QueryStat records how productive a single search query was during a scrape run
+type QueryStat struct {
+	Query           string `json:"query"`
+	VideosFound     int    `json:"videos_found"`
+	NewVideos       int    `json:"new_videos"`       // Not already returned by an earlier query this run
+	DuplicateVideos int    `json:"duplicate_videos"` // Already returned by an earlier query this run
+	CommentsFetched int    `json:"comments_fetched"`
+	QuotaUsed       int    `json:"quota_used"` // YouTube API units spent on this query
+}
+
+// RunError is one failure during a scrape run
+type RunError struct {
+	Source  string    `json:"source"`          // "youtube", "gemini", "google", "trustpilot", ...
+	Kind    string    `json:"kind"`            // "quota", "auth", "network", "parse", "blocked", "other"
+	Query   string    `json:"query,omitempty"` // Query, video or page being fetched
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// CountErrors breaks run errors down by kind
+func CountErrors(errors []RunError) map[string]int {
+	if len(errors) == 0 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, e := range errors {
+		counts[e.Kind]++
+	}
+	return counts
+}
+
+// Scrape progress event types
+const (
+	ProgressStarted  = "started"  // The run began
+	ProgressQuery    = "query"    // A query finished
+	ProgressVideo    = "video"    // A video's comments were fetched
+	ProgressError    = "error"    // A failure the run skipped past
+	ProgressFinished = "finished" // The run ended; Status says how
+)
+
+// ScrapeProgress is one step of a running scrape, streamed to the dashboard
+type ScrapeProgress struct {
+	Type       string    `json:"type"`
+	RunID      string    `json:"run_id"`
+	Seq        int       `json:"seq,omitempty"` // Position in the run's events, from 1; set by the progress hub
+	Source     string    `json:"source,omitempty"`      // "youtube", "gemini", "google"
+	Query      string    `json:"query,omitempty"`       // Query the step belongs to
+	QueryIndex int       `json:"query_index,omitempty"` // 1-based position of Query in the run
+	Queries    int       `json:"queries,omitempty"`     // Queries in the run
+	VideoID    string    `json:"video_id,omitempty"`
+	Title      string    `json:"title,omitempty"`
+	Items      int       `json:"items"`                // Videos (query), comments (video), answers or results this step collected
+	QuotaUsed  int       `json:"quota_used,omitempty"` // API quota the run has spent so far
+	Error      *RunError `json:"error,omitempty"`
+	Status     string    `json:"status,omitempty"` // Finished only: "succeeded" or "failed"
+	At         time.Time `json:"at"`
+}
+
+// ScrapeEffort is how much material a scrape run looked at. Raw complaint counts
+// rise when we simply scrape more, so counts are compared relative to effort.
+type ScrapeEffort struct {
+	CommentsScanned int `json:"comments_scanned"`
+	VideosScanned   int `json:"videos_scanned"`
+	Queries         int `json:"queries"`
+	Sources         int `json:"sources"` // Distinct data sources scanned (youtube, google, ...)
+}
+
+// Effort returns how much material the scrape run covered
+func (r *ScrapeResult) Effort() ScrapeEffort {
+	effort := ScrapeEffort{
+		CommentsScanned: len(r.Comments),
+		VideosScanned:   len(r.Videos),
+		Queries:         len(r.QueryStats),
+	}
+	if effort.Queries == 0 && r.Query != "" {
+		effort.Queries = 1
+	}
+	if len(r.Videos) > 0 || len(r.Comments) > 0 {
+		effort.Sources++
+	}
+	if len(r.GoogleResults) > 0 {
+		effort.Sources++
+	}
+	return effort
+}
+
+// NormalizedCount is a complaint count relative to scrape effort. Rates whose
+// denominator is zero are left at 0.
+type NormalizedCount struct {
+	PerThousandComments float64 `json:"per_1k_comments"`
+	PerQuery            float64 `json:"per_query"`
+	PerSource           float64 `json:"per_source"`
+}
+
+// Normalize expresses a complaint count relative to this effort
+func (e ScrapeEffort) Normalize(count int) NormalizedCount {
+	var n NormalizedCount
+	if e.CommentsScanned > 0 {
+		n.PerThousandComments = float64(count) * 1000 / float64(e.CommentsScanned)
+	}
+	if e.Queries > 0 {
+		n.PerQuery = float64(count) / float64(e.Queries)
+	}
+	if e.Sources > 0 {
+		n.PerSource = float64(count) / float64(e.Sources)
+	}
+	return n
+}
+
+// QueryPlan is a single search query with the number of videos budgeted for it
+type QueryPlan struct {
+	Query           string    `json:"query"`
+	MaxVideos       int       `json:"max_videos"`
+	PublishedAfter  time.Time `json:"published_after,omitzero"`  // Optional lower bound on video publish time
+	PublishedBefore time.Time `json:"published_before,omitzero"` // Optional upper bound on video publish time
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // ============================================
 // RESOLUTION MODELS
@@ -9,16 +12,39 @@ import "time"
 // Resolution represents a resolved issue with evidence
 type Resolution struct {
 	ID               string             `json:"id"`
+	IssueID          string             `json:"issue_id,omitempty"`
+	Tenant           string             `json:"tenant,omitempty"`  // Watch program it belongs to, from its issue
 	Exchange         string             `json:"exchange"`          // "coinbase", "kraken", etc.
 	IssueCategory    string             `json:"issue_category"`    // "withdrawal_delays", "support_issues", etc.
 	Summary          string             `json:"summary"`           // Human-readable resolution summary
 	Evidence         ResolutionEvidence `json:"evidence"`          // Structured evidence
 	Confidence       float64            `json:"confidence"`        // 0.0-1.0 confidence score
 	ResolutionWindow int                `json:"resolution_window"` // Days over which resolution was measured
-	Status           string             `json:"status"`            // "pending", "verified", "on_chain"
+	Status           string             `json:"status"`            // "pending", "verified", "on_chain", "needs_review", "rejected"
 	CreatedAt        time.Time          `json:"created_at"`
 	VerifiedAt       *time.Time         `json:"verified_at,omitempty"`
-	Attestation      *Attestation       `json:"attestation,omitempty"` // On-chain attestation (if recorded)
+	VerifiedVia      string             `json:"verified_via,omitempty"` // "auto" (met the criteria) or "manual" (approved on review)
+	Attestation      *Attestation       `json:"attestation,omitempty"`  // On-chain attestation (if recorded)
+	MetadataURI      string             `json:"metadata_uri,omitempty"` // Where the evidence is published (e.g. "ipfs://<cid>"); emitted on-chain when attested
+
+	// Set on candidates the resolution detector proposed from consecutive scrape runs.
+	// They wait in "needs_review" until someone approves or rejects them.
+	Detected   bool       `json:"detected,omitempty"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+
+	// Exchange announcements published around the measurement window that may explain
+	// the drop. Kept out of Evidence so linking never changes the attested hash.
+	Announcements []AnnouncementLink `json:"announcements,omitempty"`
+}
+
+// AnnouncementLink is an exchange changelog, blog or status post linked to a resolution
+type AnnouncementLink struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Source      string    `json:"source"` // "changelog", "blog", "status"
+	PublishedAt time.Time `json:"published_at"`
 }
 
 // ResolutionEvidence contains the data that gets hashed for on-chain attestation
@@ -32,16 +58,67 @@ type ResolutionEvidence struct {
 	MeasurementStart    time.Time `json:"measurement_start"`
 	MeasurementEnd      time.Time `json:"measurement_end"`
 	AnalysisMethodology string    `json:"analysis_methodology"` // Brief description
+
+	// Optional version scope, e.g. "crashes fixed in 12.3" compares complaints before and after that build
+	AppVersion string `json:"app_version,omitempty"`
+	OS         string `json:"os,omitempty"`
+
+	// Optional scrape effort behind each count; when both are given the counts are
+	// also compared per unit of effort, so scraping less can't pass for a resolution
+	EffortBefore     *ScrapeEffort    `json:"effort_before,omitempty"`
+	EffortAfter      *ScrapeEffort    `json:"effort_after,omitempty"`
+	NormalizedBefore *NormalizedCount `json:"normalized_before,omitempty"` // Set by the server from EffortBefore
+	NormalizedAfter  *NormalizedCount `json:"normalized_after,omitempty"`  // Set by the server from EffortAfter
+
+	// Optional complaint counts per data source behind each count; when given, confidence
+	// weighs every source by its trust weight from ResolutionCriteria.SourceWeights
+	SourcesBefore map[string]int `json:"sources_before,omitempty"`
+	SourcesAfter  map[string]int `json:"sources_after,omitempty"` // Sources missing here had no complaints after
+}
+
+// ValidationError describes one rule a request violated, with a stable code clients can match on
+type ValidationError struct {
+	Code    string `json:"code"`  // e.g. "complaints_increased"
+	Field   string `json:"field"` // JSON field name
+	Message string `json:"message"`
 }
 
+// Verification paths recorded in Resolution.VerifiedVia
+const (
+	VerifiedAuto   = "auto"
+	VerifiedManual = "manual"
+)
+
 // ResolutionCriteria defines thresholds for auto-resolution
 type ResolutionCriteria struct {
 	MinPercentageDecrease    float64 `json:"min_percentage_decrease"` // e.g., 0.70 (70% drop)
 	MinConfidence            float64 `json:"min_confidence"`          // e.g., 0.85
 	MinWindowDays            int     `json:"min_window_days"`         // e.g., 7 days
 	RequirePositiveSentiment bool    `json:"require_positive_sentiment"`
+	AutoVerify               bool    `json:"auto_verify"` // Off: resolutions meeting the criteria wait in "needs_review" instead
+
+	// Per-exchange overrides, keyed by lowercase exchange name. Unset fields inherit the above.
+	Exchanges map[string]ExchangeCriteria `json:"exchanges,omitempty"`
+
+	// Trust weight per complaint source, so one brigaded comment section can't outweigh
+	// regulator complaints. Keys are a full source ("youtube:comment"), its detail
+	// ("trustpilot" in "gemini_search:trustpilot") or its data source ("youtube").
+	SourceWeights map[string]float64 `json:"source_weights,omitempty"`
 }
 
+// ExchangeCriteria overrides the resolution criteria for one exchange, e.g. to
+// always require human review of its resolutions
+type ExchangeCriteria struct {
+	MinPercentageDecrease    *float64 `json:"min_percentage_decrease,omitempty"`
+	MinConfidence            *float64 `json:"min_confidence,omitempty"`
+	MinWindowDays            *int     `json:"min_window_days,omitempty"`
+	RequirePositiveSentiment *bool    `json:"require_positive_sentiment,omitempty"`
+	AutoVerify               *bool    `json:"auto_verify,omitempty"`
+}
+
+// DefaultSourceWeight is the trust weight of a source SourceWeights doesn't list
+const DefaultSourceWeight = 1.0
+
 // DefaultResolutionCriteria returns sensible defaults
 func DefaultResolutionCriteria() ResolutionCriteria {
 	return ResolutionCriteria{
@@ -49,26 +126,104 @@ func DefaultResolutionCriteria() ResolutionCriteria {
 		MinConfidence:            0.85, // 85% confidence
 		MinWindowDays:            7,    // Over 7 days
 		RequirePositiveSentiment: false,
+		AutoVerify:               true,
+		SourceWeights: map[string]float64{
+			"cfpb":       4.0, // Regulator complaints: identified consumers, company responses on record
+			"bbb":        3.0,
+			"trustpilot": 2.0,
+			"reddit":     1.5,
+			"youtube":    1.0, // Comments and video text: easiest to brigade
+		},
 	}
 }
 
+// ForExchange returns the criteria in effect for an exchange: these criteria with
+// the exchange's overrides applied
+func (c ResolutionCriteria) ForExchange(exchange string) ResolutionCriteria {
+	override, ok := c.Exchanges[strings.ToLower(exchange)]
+	if !ok {
+		return c
+	}
+	if override.MinPercentageDecrease != nil {
+		c.MinPercentageDecrease = *override.MinPercentageDecrease
+	}
+	if override.MinConfidence != nil {
+		c.MinConfidence = *override.MinConfidence
+	}
+	if override.MinWindowDays != nil {
+		c.MinWindowDays = *override.MinWindowDays
+	}
+	if override.RequirePositiveSentiment != nil {
+		c.RequirePositiveSentiment = *override.RequirePositiveSentiment
+	}
+	if override.AutoVerify != nil {
+		c.AutoVerify = *override.AutoVerify
+	}
+	return c
+}
+
+// SourceWeight returns the trust weight of a complaint source, looking it up by the
+// full source, then its detail after ":", then its data source before ":".
+// Negative weights count as 0.
+func (c ResolutionCriteria) SourceWeight(source string) float64 {
+	source = strings.ToLower(source)
+	prefix, detail, _ := strings.Cut(source, ":")
+	for _, key := range []string{source, detail, prefix} {
+		if w, ok := c.SourceWeights[key]; ok && key != "" {
+			return max(w, 0)
+		}
+	}
+	return DefaultSourceWeight
+}
+
 // ============================================
 // ON-CHAIN ATTESTATION MODELS
 // ============================================
 
 // Attestation represents an on-chain verification record
 type Attestation struct {
-	ID              uint64    `json:"id"`                      // On-chain attestation ID
-	TransactionHash string    `json:"transaction_hash"`        // Ethereum tx hash
-	BlockNumber     uint64    `json:"block_number"`            // Block number
-	BlockTimestamp  time.Time `json:"block_timestamp"`         // Block timestamp
-	ChainID         int64     `json:"chain_id"`                // Network chain ID
-	ContractAddress string    `json:"contract_address"`        // Attestation contract address
-	EvidenceHash    string    `json:"evidence_hash"`           // Keccak256 hash (hex)
-	PreviousHash    string    `json:"previous_hash,omitempty"` // Previous attestation hash
-	Attestor        string    `json:"attestor"`                // Address that submitted
-	ExplorerURL     string    `json:"explorer_url"`            // Link to block explorer
-	Verified        bool      `json:"verified"`                // Whether verification succeeded
+	ID              uint64    `json:"id"`                       // On-chain attestation ID
+	TransactionHash string    `json:"transaction_hash"`         // Ethereum tx hash
+	BlockNumber     uint64    `json:"block_number"`             // Block number
+	BlockTimestamp  time.Time `json:"block_timestamp"`          // Block timestamp
+	ChainID         int64     `json:"chain_id"`                 // Network chain ID
+	ContractAddress string    `json:"contract_address"`         // Attestation contract address
+	EvidenceHash    string    `json:"evidence_hash"`            // Keccak256 hash (hex)
+	PreviousHash    string    `json:"previous_hash,omitempty"`  // Previous attestation hash
+	MetadataURI     string    `json:"metadata_uri,omitempty"`   // Evidence location emitted in ResolutionMetadata
+	EvidenceCID     string    `json:"evidence_cid,omitempty"`   // IPFS CID of the evidence JSON the hash was taken over
+	Exchange        string    `json:"exchange,omitempty"`       // Exchange as recorded on-chain
+	IssueCategory   string    `json:"issue_category,omitempty"` // Issue category as recorded on-chain
+	Attestor        string    `json:"attestor"`                 // Address that submitted
+	AttestorName    string    `json:"attestor_name,omitempty"`  // ENS or Base name of the attestor, if any
+	ExplorerURL     string    `json:"explorer_url"`             // Link to block explorer
+	Verified        bool      `json:"verified"`                 // Whether verification succeeded
+
+	Explorer *ExplorerDetails `json:"explorer,omitempty"` // Enrichment from the block explorer API
+}
+
+// ExplorerDetails is what the block explorer API reports about an attestation transaction
+type ExplorerDetails struct {
+	TxStatus          string    `json:"tx_status"` // "success", "failed", "pending"
+	Confirmations     uint64    `json:"confirmations"`
+	GasUsed           uint64    `json:"gas_used"`
+	EffectiveGasPrice string    `json:"effective_gas_price_wei"`
+	FeeWei            string    `json:"fee_wei"`
+	ContractVerified  bool      `json:"contract_verified"` // Contract source is published on the explorer
+	ContractName      string    `json:"contract_name,omitempty"`
+	TxURL             string    `json:"tx_url"`       // Only set once the explorer has indexed the tx
+	ContractURL       string    `json:"contract_url"` // Source tab if verified, address page otherwise
+	AttestorURL       string    `json:"attestor_url"`
+	CheckedAt         time.Time `json:"checked_at"`
+}
+
+// GasCost is an amount of gas spend, in USD as well when a price was available
+type GasCost struct {
+	Wei      string    `json:"wei"`
+	ETH      float64   `json:"eth"`
+	USD      *float64  `json:"usd,omitempty"`     // Nil when the price feed is unavailable
+	ETHUSD   float64   `json:"eth_usd,omitempty"` // Price USD was converted at
+	PricedAt time.Time `json:"priced_at,omitzero"`
 }
 
 // AttestationRequest is used to request a new attestation
@@ -82,6 +237,8 @@ type AttestationRequest struct {
 type AttestationResponse struct {
 	Success     bool         `json:"success"`
 	Attestation *Attestation `json:"attestation,omitempty"`
+	Queued      bool         `json:"queued,omitempty"`       // Waiting for the next attestation batch
+	ScheduledAt *time.Time   `json:"scheduled_at,omitempty"` // When the queued batch is submitted
 	Error       string       `json:"error,omitempty"`
 }
 
@@ -92,6 +249,21 @@ type VerificationRequest struct {
 	ResolutionID string `json:"resolution_id"` // Resolution to verify
 }
 
+// BatchVerificationRequest verifies many attestations at once, by evidence hash, resolution ID, or both
+type BatchVerificationRequest struct {
+	EvidenceHashes []string `json:"evidence_hashes,omitempty"`
+	ResolutionIDs  []string `json:"resolution_ids,omitempty"`
+}
+
+// BatchVerificationResult is the outcome for one item of a batch verification
+type BatchVerificationResult struct {
+	EvidenceHash string                `json:"evidence_hash,omitempty"` // Set for items requested by hash
+	ResolutionID string                `json:"resolution_id,omitempty"` // Set for items requested by resolution
+	Verified     bool                  `json:"verified"`
+	Result       *VerificationResponse `json:"result,omitempty"`
+	Error        string                `json:"error,omitempty"` // The item couldn't be checked (unknown resolution, RPC failure)
+}
+
 // VerificationResponse is returned after verification
 type VerificationResponse struct {
 	Verified       bool         `json:"verified"`
@@ -100,6 +272,60 @@ type VerificationResponse struct {
 	HashMatch      bool         `json:"hash_match"`      // Local hash matches on-chain
 	TimestampValid bool         `json:"timestamp_valid"` // Timestamp is reasonable
 	Message        string       `json:"message"`
+	Cached         bool         `json:"cached,omitempty"` // Served from the verification cache
+
+	// Set when an attestor key registry is configured: whether the attestor was a
+	// valid key at the attestation's block
+	AttestorAuthorized *bool `json:"attestor_authorized,omitempty"`
+}
+
+// Preflight check statuses
+const (
+	PreflightPass = "pass"
+	PreflightWarn = "warn"
+	PreflightFail = "fail"
+	PreflightSkip = "skip" // Couldn't run because an earlier check failed
+)
+
+// Preflight is the checklist run before attesting a resolution
+type Preflight struct {
+	ResolutionID string           `json:"resolution_id"`
+	Ready        bool             `json:"ready"` // No check failed
+	Checks       []PreflightCheck `json:"checks"`
+	CheckedAt    time.Time        `json:"checked_at"`
+}
+
+// PreflightCheck is one item on the pre-flight checklist
+type PreflightCheck struct {
+	Name    string   `json:"name"`   // "status", "criteria", "evidence", "blockchain", "rpc", "funds", "hash"
+	Status  string   `json:"status"` // "pass", "warn", "fail", "skip"
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"` // Individual problems, e.g. each evidence validation error
+}
+
+// AttestorKey is an attestation signing key and the blocks it was valid for
+type AttestorKey struct {
+	Address         string    `json:"address"`
+	ValidFromBlock  uint64    `json:"valid_from_block"`
+	ValidUntilBlock uint64    `json:"valid_until_block,omitempty"` // Inclusive; 0 = still valid
+	RegisteredAt    time.Time `json:"registered_at"`
+}
+
+// KeyRotation hands attestation over from one key to another. Both keys sign
+// Statement (EIP-191), so anyone can check that the old key approved its successor
+// and the new key accepted.
+type KeyRotation struct {
+	ChainID                 int64     `json:"chain_id"`
+	ContractAddress         string    `json:"contract_address"`
+	PreviousAttestor        string    `json:"previous_attestor"`
+	NewAttestor             string    `json:"new_attestor"`
+	EffectiveBlock          uint64    `json:"effective_block"`            // First block the new key is valid
+	PreviousValidUntilBlock uint64    `json:"previous_valid_until_block"` // Last block the old key is valid (overlap for in-flight attestations)
+	Statement               string    `json:"statement"`
+	PreviousSignature       string    `json:"previous_signature"`
+	NewSignature            string    `json:"new_signature"`
+	TransactionHash         string    `json:"transaction_hash,omitempty"` // AttestorRotated announcement, if published on-chain
+	CreatedAt               time.Time `json:"created_at"`
 }
 
 // ============================================
@@ -150,6 +376,7 @@ func SupportedChains() map[string]ChainConfig {
 // Issue represents a detected issue being tracked
 type Issue struct {
 	ID             string       `json:"id"`
+	Tenant         string       `json:"tenant,omitempty"` // Watch program it belongs to; empty for the deployment's own
 	Exchange       string       `json:"exchange"`
 	Category       string       `json:"category"`
 	Title          string       `json:"title"`
@@ -157,10 +384,78 @@ type Issue struct {
 	FirstDetected  time.Time    `json:"first_detected"`
 	LastUpdated    time.Time    `json:"last_updated"`
 	ComplaintCount int          `json:"complaint_count"`
+	WeightedCount  float64      `json:"weighted_count,omitempty"` // ComplaintCount with each complaint weighed by its source's trust
+	ComplaintLinks []string     `json:"complaint_links,omitempty"`
 	Severity       string       `json:"severity"` // "critical", "high", "medium", "low"
 	Status         string       `json:"status"`   // "active", "investigating", "resolved", "verified"
 	Resolution     *Resolution  `json:"resolution,omitempty"`
 	Attestation    *Attestation `json:"attestation,omitempty"`
+
+	// Scrape effort behind ComplaintCount, and the count relative to it
+	Effort     *ScrapeEffort    `json:"effort,omitempty"`
+	Normalized *NormalizedCount `json:"normalized,omitempty"`
+
+	// How widely the videos and threads its complaints appear in have been seen
+	Reach *IssueReach `json:"reach,omitempty"`
+
+	// Soft delete: archived issues are hidden from lists but keep their history
+	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
+	ArchivedBy    string     `json:"archived_by,omitempty"`
+	ArchiveReason string     `json:"archive_reason,omitempty"`
+
+	// Severity SLA: SLA is computed whenever the issue is read; SLABreachedAt is set once, when it first went overdue
+	SLA           *IssueSLA  `json:"sla,omitempty"`
+	SLABreachedAt *time.Time `json:"sla_breached_at,omitempty"`
+
+	// How fast new complaints are arriving, measured on every data reload.
+	// VelocityAlertedAt is set when a velocity alert goes out and cleared once the rate drops back.
+	Velocity          *ComplaintVelocity `json:"velocity,omitempty"`
+	VelocityAlertedAt *time.Time         `json:"velocity_alerted_at,omitempty"`
+}
+
+// ComplaintVelocity is how many new complaints about an issue arrive a day
+type ComplaintVelocity struct {
+	WindowDays     int       `json:"window_days"`
+	Complaints     int       `json:"complaints"` // Posted within the window
+	PerDay         float64   `json:"per_day"`
+	PreviousPerDay float64   `json:"previous_per_day"` // Over the window before, to tell speeding up from slowing down
+	MeasuredAt     time.Time `json:"measured_at"`
+}
+
+// VelocityProjection is when an issue reaches its critical complaint count at its current velocity
+type VelocityProjection struct {
+	IssueID         string             `json:"issue_id"`
+	Complaints      int                `json:"complaints"` // ComplaintCount now
+	Threshold       int                `json:"threshold"`
+	Velocity        *ComplaintVelocity `json:"velocity"`
+	Crossed         bool               `json:"crossed"`                     // Already at or past the threshold
+	DaysToThreshold *float64           `json:"days_to_threshold,omitempty"` // Nil when crossed or not growing
+	ProjectedAt     *time.Time         `json:"projected_at,omitempty"`
+	Summary         string             `json:"summary"` // E.g. "At the current rate, crosses 100 complaints in 5 days"
+}
+
+// IssueReach estimates how many people have seen an issue being complained about,
+// from the audiences of the distinct videos and threads its complaints sit in.
+// Each thread counts once however many complaints it holds.
+type IssueReach struct {
+	Estimated int64 `json:"estimated"` // Views plus upvotes; a lower bound, since most readers don't vote
+	Views     int64 `json:"views"`     // YouTube video views
+	Upvotes   int64 `json:"upvotes"`   // Net upvotes on Reddit threads
+	Threads   int   `json:"threads"`   // Distinct videos and threads counted
+}
+
+// IssueSLA is how an open issue is tracking against its severity's SLA window
+type IssueSLA struct {
+	WindowDays    int       `json:"window_days"`
+	DueAt         time.Time `json:"due_at"`
+	ElapsedDays   float64   `json:"elapsed_days"`   // Since first detection
+	RemainingDays float64   `json:"remaining_days"` // Negative once overdue
+	Overdue       bool      `json:"overdue"`
+}
+
+// IsArchived reports whether the issue has been soft-deleted
+func (i *Issue) IsArchived() bool {
+	return i.ArchivedAt != nil
 }
 
 // IssueTimeline represents the history of an issue
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ============================================
+// EXCHANGE SCORECARD MODELS
+// ============================================
+
+// ExchangeScorecard summarizes an exchange's current reputation
+type ExchangeScorecard struct {
+	Exchange         string         `json:"exchange"`
+	OpenIssues       int            `json:"open_issues"`
+	ResolvedIssues   int            `json:"resolved_issues"`
+	IssuesBySeverity map[string]int `json:"issues_by_severity"` // Open issues only
+	Ratings          []RatingTrend  `json:"ratings"`            // Review-site ratings (corroboration signal)
+	Complaints       int            `json:"complaints"`         // Complaints scraped in the window
+	Sentiment        *float64       `json:"sentiment"`          // Their mean sentiment (-1 to 1); null when none could be scored
+	GeneratedAt      time.Time      `json:"generated_at"`
+}
+
+// ScorecardSnapshot is one day's scorecard, kept so reputation can be charted over months
+type ScorecardSnapshot struct {
+	Exchange         string             `json:"exchange"`
+	Date             string             `json:"date"` // "2006-01-02"
+	OpenIssues       int                `json:"open_issues"`
+	ResolvedIssues   int                `json:"resolved_issues"`
+	IssuesBySeverity map[string]int     `json:"issues_by_severity"`
+	Complaints       int                `json:"complaints"`
+	Sentiment        *float64           `json:"sentiment"`
+	Ratings          map[string]float64 `json:"ratings"` // Latest rating per review site
+	RecordedAt       time.Time          `json:"recorded_at"`
+}
+
+// RatingTrend summarizes how a review-site rating moved over a window
+type RatingTrend struct {
+	Source       string  `json:"source"` // "trustpilot", "bbb"
+	Latest       float64 `json:"latest"`
+	LatestDate   string  `json:"latest_date"`
+	Earliest     float64 `json:"earliest"`
+	EarliestDate string  `json:"earliest_date"`
+	Change       float64 `json:"change"` // Latest - earliest; positive means improving
+	ReviewCount  int     `json:"review_count"`
+	Snapshots    int     `json:"snapshots"`
+}
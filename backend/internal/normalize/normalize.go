@@ -0,0 +1,120 @@
+// Complaint normalization: one path from every source's native records to validated models.Complaint
+package normalize
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// NORMALIZATION
+// ============================================
+// Source adapters (YouTube, Google, Gemini, Reddit and review sites here, ingest.Record for partner feeds)
+// only map their native fields onto a draft models.Complaint. Normalize then
+// applies the rules every complaint shares, so sources can't drift apart on them:
+//   - timestamps are UTC; a missing scrape time is the normalization time
+//   - categories are taxonomy IDs; an uncategorized draft is categorized from its text
+//   - URLs are http(s) or empty
+//   - IDs derive from source, source ID and category when the adapter didn't set one
+//   - text is trimmed, sentiment defaults to negative, app builds are parsed from the text
+
+// MaxFutureSkew is how far past the normalization time a publish time may be,
+// allowing for clock skew between us and the source
+const MaxFutureSkew = time.Hour
+
+// Validation errors
+var (
+	ErrNoSource = errors.New("complaint has no source")
+	ErrNoText   = errors.New("complaint has no title or description")
+	ErrFuture   = errors.New("complaint is published in the future")
+)
+
+// Normalize cleans up a draft complaint and checks it has what the analysis pipeline needs
+func Normalize(c models.Complaint, now time.Time) (models.Complaint, error) {
+	c.Source = strings.TrimSpace(c.Source)
+	c.Title = strings.TrimSpace(c.Title)
+	c.Description = strings.TrimSpace(c.Description)
+	c.Author = strings.TrimSpace(c.Author)
+	switch {
+	case c.Source == "":
+		return c, ErrNoSource
+	case c.Title == "" && c.Description == "":
+		return c, ErrNoText
+	}
+
+	c.PublishedAt = utc(c.PublishedAt)
+	c.ScrapedAt = utc(c.ScrapedAt)
+	c.FirstSeen = utc(c.FirstSeen)
+	c.LastSeen = utc(c.LastSeen)
+	if c.ScrapedAt.IsZero() {
+		c.ScrapedAt = now.UTC()
+	}
+	if c.PublishedAt.After(now.Add(MaxFutureSkew)) {
+		return c, fmt.Errorf("%w: %s", ErrFuture, c.PublishedAt.Format(time.RFC3339))
+	}
+
+	if c.Category == "" {
+		c.Category = Categorize(c.Title + "\n" + c.Description)[0]
+	} else {
+		c.Category = models.NormalizeCategory(c.Category)
+	}
+
+	c.URL = cleanURL(c.URL)
+	if c.ID == "" {
+		sourceID := c.SourceID
+		if sourceID == "" {
+			sourceID = c.URL
+		}
+		c.ID = models.ComplaintID(c.Source, sourceID, c.Category)
+	}
+	if c.Sentiment == "" {
+		c.Sentiment = "negative" // Every source only reports complaints
+	}
+	if c.AppVersion == "" {
+		c.AppVersion, c.OS = analyzer.ExtractAppVersion(c.Description)
+	}
+	return c, nil
+}
+
+// Categorize returns the categories text matches by keyword, or just the "other" fallback
+func Categorize(text string) []string {
+	if categories := analyzer.Categorize(text); len(categories) > 0 {
+		return categories
+	}
+	return []string{models.CategoryOther}
+}
+
+// all normalizes drafts, dropping the ones that fail validation
+func all(drafts []models.Complaint, now time.Time) []models.Complaint {
+	complaints := make([]models.Complaint, 0, len(drafts))
+	for _, draft := range drafts {
+		if c, err := Normalize(draft, now); err == nil {
+			complaints = append(complaints, c)
+		}
+	}
+	return complaints
+}
+
+// utc converts t to UTC, leaving the zero time alone
+func utc(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC()
+}
+
+// cleanURL returns raw trimmed if it is an absolute http(s) URL, empty otherwise
+func cleanURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return raw
+}
@@ -0,0 +1,217 @@
+package normalize
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/scrapers"
+	"github.com/tasnint/coinsights/internal/textutil"
+)
+
+// ============================================
+// SOURCE ADAPTERS
+// ============================================
+
+// YouTube converts the issues the analyzer extracted from videos and comments.
+// The analyzer has already categorized them and assigned their IDs.
+func YouTube(issues []analyzer.ExtractedIssue, now time.Time) []models.Complaint {
+	drafts := make([]models.Complaint, 0, len(issues))
+	for _, issue := range issues {
+		drafts = append(drafts, models.Complaint{
+			ID:          issue.ID,
+			Source:      "youtube:" + issue.Source,
+			Title:       issue.SourceTitle,
+			Description: issue.Text,
+			URL:         issue.SourceURL,
+			PublishedAt: issue.PublishedAt,
+			ScrapedAt:   issue.ExtractedAt,
+			Category:    issue.Category,
+			Likes:       issue.Likes,
+			Context:     issue.Context,
+			RunID:       issue.RunID,
+			SourceID:    issue.SourceID,
+			Keywords:    issue.Keywords,
+
+			OriginalText: issue.OriginalText,
+			Language:     issue.Language,
+		})
+	}
+	return all(drafts, now)
+}
+
+// Google converts search results, one complaint per category the title and snippet
+// match (results that match none are kept as "other"). Results are identified by URL.
+func Google(results []models.GoogleResult, runID string, now time.Time) []models.Complaint {
+	drafts := []models.Complaint{}
+	for _, result := range results {
+		source := "google"
+		if result.Source != "" {
+			source += ":" + result.Source
+		}
+		for _, category := range Categorize(result.Title + "\n" + result.Snippet) {
+			drafts = append(drafts, models.Complaint{
+				ID:          models.ComplaintID(source, result.URL, category),
+				Source:      source,
+				Title:       result.Title,
+				Description: result.Snippet,
+				URL:         result.URL,
+				ScrapedAt:   result.ScrapedAt,
+				Category:    category,
+				RunID:       runID,
+				SourceID:    result.URL,
+			})
+		}
+	}
+	return all(drafts, now)
+}
+
+// Gemini converts the key complaints in Gemini answers. Complaints with the same
+// wording and category are merged into one record, whichever query or answer they
+// came from, spanning the times they were seen and every domain that grounded them.
+func Gemini(results []scrapers.AIOverviewResult, now time.Time) []models.Complaint {
+	drafts := []models.Complaint{}
+	index := make(map[string]int) // complaint ID -> position in drafts
+
+	for _, result := range results {
+		for _, kc := range result.KeyComplaints {
+			category := models.NormalizeCategory(kc.Category)
+			// Gemini has no record IDs, so the wording identifies the complaint
+			id := models.ComplaintID("gemini_search", scrapers.ContentHash(kc.Description), category)
+
+			if i, ok := index[id]; ok {
+				if result.GeneratedAt.Before(drafts[i].FirstSeen) {
+					drafts[i].FirstSeen = result.GeneratedAt
+				}
+				if result.GeneratedAt.After(drafts[i].LastSeen) {
+					drafts[i].LastSeen = result.GeneratedAt
+				}
+				drafts[i].GroundingDomains = mergeDomains(drafts[i].GroundingDomains, kc.GroundedBy)
+				continue
+			}
+
+			draft := models.Complaint{
+				ID:          id,
+				Source:      fmt.Sprintf("gemini_search:%s", kc.Platform),
+				Title:       fmt.Sprintf("[%s] %s", category, textutil.Prefix(kc.Description, config.Truncation.ComplaintTitle)),
+				Description: kc.Description,
+				Category:    category,
+				ScrapedAt:   result.GeneratedAt,
+				RunID:       result.RunID,
+				FirstSeen:   result.GeneratedAt,
+				LastSeen:    result.GeneratedAt,
+
+				GroundingDomains: mergeDomains(nil, kc.GroundedBy),
+			}
+			// Gemini cites sources per answer, not per complaint
+			if len(result.Sources) > 0 {
+				draft.URL = result.Sources[0].URL
+			}
+
+			index[id] = len(drafts)
+			drafts = append(drafts, draft)
+		}
+	}
+
+	return all(drafts, now)
+}
+
+// mergeDomains adds more to domains, sorted and without repeats
+func mergeDomains(domains, more []string) []string {
+	merged := append(slices.Clone(domains), more...)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+// Reddit converts posts and their comments, one complaint per category each one's
+// own text matches (a comment is categorized without its post's title, so it isn't
+// counted under whatever the post complained about). Unlike search results, a
+// subreddit search surfaces plenty that isn't a complaint, so text matching no
+// category is dropped. A comment's title is its post's title.
+func Reddit(result *scrapers.RedditResult, now time.Time) []models.Complaint {
+	drafts := []models.Complaint{}
+	add := func(draft models.Complaint, text string) {
+		draft.ScrapedAt = result.ScrapedAt
+		draft.RunID = result.RunID
+		for _, category := range analyzer.Categorize(text) {
+			draft.ID = models.ComplaintID(draft.Source, draft.SourceID, category)
+			draft.Category = category
+			drafts = append(drafts, draft)
+		}
+	}
+
+	for _, post := range result.Posts {
+		add(models.Complaint{
+			Source:      "reddit:post",
+			SourceID:    "t3_" + post.ID,
+			Title:       post.Title,
+			Description: post.Body,
+			URL:         post.URL,
+			Author:      post.Author,
+			PublishedAt: post.CreatedAt,
+			Likes:       post.Score,
+			Context:     &models.EngagementContext{ThreadURL: post.URL, ThreadScore: post.Score},
+		}, post.Title+"\n"+post.Body)
+
+		for _, comment := range post.Comments {
+			add(models.Complaint{
+				Source:      "reddit:comment",
+				SourceID:    "t1_" + comment.ID,
+				Title:       post.Title,
+				Description: comment.Body,
+				URL:         comment.URL,
+				Author:      comment.Author,
+				PublishedAt: comment.CreatedAt,
+				Likes:       comment.Score,
+				Context: &models.EngagementContext{
+					CommentPosition: comment.Position,
+					ReplyCount:      comment.Replies,
+					ThreadURL:       post.URL,
+					ThreadScore:     post.Score,
+				},
+			}, comment.Body)
+		}
+	}
+	return all(drafts, now)
+}
+
+// MaxComplaintRating is the highest star rating a review is read as a complaint at;
+// better-rated reviews are praise, however many category keywords they mention
+const MaxComplaintRating = 3
+
+// Reviews converts review-site reviews and BBB complaints, one complaint per
+// category the title and text match (reviews matching none are kept as "other",
+// since a low rating is a complaint whatever it's about). Unrated records, like BBB
+// complaints, are always complaints.
+func Reviews(result *scrapers.ReviewsResult, now time.Time) []models.Complaint {
+	drafts := []models.Complaint{}
+	for _, review := range result.Reviews {
+		if review.Rating > MaxComplaintRating {
+			continue
+		}
+		source := review.Source
+		if review.Source == "bbb" {
+			source = "bbb:complaint"
+		}
+		for _, category := range Categorize(review.Title + "\n" + review.Text) {
+			drafts = append(drafts, models.Complaint{
+				ID:          models.ComplaintID(source, review.ID, category),
+				Source:      source,
+				Title:       review.Title,
+				Description: review.Text,
+				URL:         review.URL,
+				Author:      review.Author,
+				PublishedAt: review.PublishedAt,
+				ScrapedAt:   result.ScrapedAt,
+				Category:    category,
+				Rating:      review.Rating,
+				RunID:       result.RunID,
+				SourceID:    review.ID,
+			})
+		}
+	}
+	return all(drafts, now)
+}
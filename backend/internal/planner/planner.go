@@ -0,0 +1,324 @@
+// Quota-aware planning of YouTube search queries based on past yield
+package planner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+const (
+	// HistoryFile is the file name of the yield history inside the data directory
+	HistoryFile = "query_yield.json"
+	// DefaultPruneMinRuns is how many runs a query gets before it can be suggested for pruning
+	DefaultPruneMinRuns = 3
+	// SkipDuplicateRate skips queries whose results are mostly videos other queries already found
+	SkipDuplicateRate = 0.8
+	// MinRunsBeforeSkip avoids skipping a query on a single unlucky run
+	MinRunsBeforeSkip = 2
+	// yieldSmoothing weights the latest run when updating the moving average
+	yieldSmoothing = 0.5
+)
+
+// QueryYield is the moving-average productivity of a search query across runs
+type QueryYield struct {
+	Query         string    `json:"query"`
+	Runs          int       `json:"runs"`
+	Complaints    float64   `json:"complaints"`     // Complaints extracted per run
+	Yield         float64   `json:"yield"`          // Complaints per quota unit
+	DuplicateRate float64   `json:"duplicate_rate"` // Share of videos already found by other queries
+	LastRun       time.Time `json:"last_run"`
+
+	// Lifetime totals across all runs
+	TotalVideos           int `json:"total_videos"`
+	TotalDuplicateVideos  int `json:"total_duplicate_videos"`
+	TotalComplaints       int `json:"total_complaints"`
+	TotalUniqueComplaints int `json:"total_unique_complaints"` // Distinct complaint texts, across runs
+	TotalQuotaUsed        int `json:"total_quota_used"`
+}
+
+// PruneSuggestion recommends dropping a query from config.SearchQueries
+type PruneSuggestion struct {
+	Query  string `json:"query"`
+	Reason string `json:"reason"`
+}
+
+// History holds yield records for every query that has been run
+type History struct {
+	Queries map[string]*QueryYield `json:"queries"`
+
+	// Keys of the complaint texts each query has found, so a complaint scraped again
+	// in a later run isn't counted as unique twice
+	Seen map[string][]string `json:"seen,omitempty"`
+}
+
+// NewHistory creates an empty yield history
+func NewHistory() *History {
+	return &History{Queries: make(map[string]*QueryYield), Seen: make(map[string][]string)}
+}
+
+// LoadHistory reads the yield history from path. A missing file yields an empty history.
+func LoadHistory(path string) (*History, error) {
+	history := NewHistory()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("failed to parse query history: %w", err)
+	}
+	if history.Queries == nil {
+		history.Queries = make(map[string]*QueryYield)
+	}
+	if history.Seen == nil {
+		history.Seen = make(map[string][]string)
+	}
+	return history, nil
+}
+
+// Save writes the yield history to path
+func (h *History) Save(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal query history: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write query history: %w", err)
+	}
+	return nil
+}
+
+// Record folds a scrape run's query stats and the issues extracted from it into the history
+func (h *History) Record(result *models.ScrapeResult, issues []analyzer.ExtractedIssue) {
+	// Attribute each complaint to the query that surfaced its video
+	queryByURL := make(map[string]string, len(result.Videos))
+	for _, v := range result.Videos {
+		queryByURL[v.URL] = v.SearchQuery
+	}
+	complaintsByQuery := make(map[string]int)
+	uniqueByQuery := make(map[string]map[string]bool)
+	for _, issue := range issues {
+		query := queryByURL[issue.SourceURL]
+		if query == "" {
+			continue
+		}
+		complaintsByQuery[query]++
+		if uniqueByQuery[query] == nil {
+			uniqueByQuery[query] = make(map[string]bool)
+		}
+		uniqueByQuery[query][issue.Text] = true
+	}
+
+	for _, stat := range result.QueryStats {
+		y, ok := h.Queries[stat.Query]
+		if !ok {
+			y = &QueryYield{Query: stat.Query}
+			h.Queries[stat.Query] = y
+		}
+
+		complaints := float64(complaintsByQuery[stat.Query])
+		yield := 0.0
+		if stat.QuotaUsed > 0 {
+			yield = complaints / float64(stat.QuotaUsed)
+		}
+		dupRate := 0.0
+		if stat.VideosFound > 0 {
+			dupRate = float64(stat.DuplicateVideos) / float64(stat.VideosFound)
+		}
+
+		if y.Runs == 0 {
+			y.Complaints, y.Yield, y.DuplicateRate = complaints, yield, dupRate
+		} else {
+			y.Complaints = smooth(y.Complaints, complaints)
+			y.Yield = smooth(y.Yield, yield)
+			y.DuplicateRate = smooth(y.DuplicateRate, dupRate)
+		}
+		y.Runs++
+		y.LastRun = result.ScrapedAt
+
+		y.TotalVideos += stat.VideosFound
+		y.TotalDuplicateVideos += stat.DuplicateVideos
+		y.TotalComplaints += complaintsByQuery[stat.Query]
+		y.TotalUniqueComplaints += h.markSeen(stat.Query, uniqueByQuery[stat.Query])
+		y.TotalQuotaUsed += stat.QuotaUsed
+	}
+}
+
+// markSeen adds texts to the complaints query has found and returns how many are new
+func (h *History) markSeen(query string, texts map[string]bool) int {
+	seen := make(map[string]bool, len(h.Seen[query]))
+	for _, key := range h.Seen[query] {
+		seen[key] = true
+	}
+	added := 0
+	for text := range texts {
+		if key := complaintKey(text); !seen[key] {
+			seen[key] = true
+			h.Seen[query] = append(h.Seen[query], key)
+			added++
+		}
+	}
+	sort.Strings(h.Seen[query])
+	return added
+}
+
+// complaintKey identifies a complaint text regardless of case and surrounding space
+func complaintKey(text string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(text))))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Stats returns every query's record sorted by yield, highest first
+func (h *History) Stats() []QueryYield {
+	stats := make([]QueryYield, 0, len(h.Queries))
+	for _, y := range h.Queries {
+		stats = append(stats, *y)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Yield != stats[j].Yield {
+			return stats[i].Yield > stats[j].Yield
+		}
+		return stats[i].Query < stats[j].Query
+	})
+	return stats
+}
+
+// PruneSuggestions lists queries that have had enough runs to judge and
+// either found nothing, mostly re-found other queries' videos, or yield
+// far below the median
+func (h *History) PruneSuggestions(minRuns int) []PruneSuggestion {
+	var yields []float64
+	for _, y := range h.Queries {
+		if y.Runs > 0 {
+			yields = append(yields, y.Yield)
+		}
+	}
+	median := medianOf(yields)
+
+	suggestions := []PruneSuggestion{}
+	for _, y := range h.Stats() {
+		if y.Runs < minRuns {
+			continue
+		}
+		switch {
+		case y.TotalComplaints == 0:
+			suggestions = append(suggestions, PruneSuggestion{y.Query,
+				fmt.Sprintf("no complaints found in %d runs", y.Runs)})
+		case y.DuplicateRate >= SkipDuplicateRate:
+			suggestions = append(suggestions, PruneSuggestion{y.Query,
+				fmt.Sprintf("%.0f%% of videos already found by other queries", y.DuplicateRate*100)})
+		case median > 0 && y.Yield < median*0.1:
+			suggestions = append(suggestions, PruneSuggestion{y.Query,
+				fmt.Sprintf("yield %.4f is under 10%% of median %.4f", y.Yield, median)})
+		}
+	}
+	return suggestions
+}
+
+// smooth applies an exponential moving average
+func smooth(previous, latest float64) float64 {
+	return previous*(1-yieldSmoothing) + latest*yieldSmoothing
+}
+
+// ============================================
+// PLANNING
+// ============================================
+
+// Plan allocates the quota budget across queries:
+// high-yield queries get more videos, low-yield ones fewer, queries that keep
+// returning duplicates are skipped, and untried queries are explored at the median yield.
+func (h *History) Plan(queries []string, settings config.ScraperSettings) []models.QueryPlan {
+	budget := settings.DailyQuota
+	if budget <= 0 {
+		budget = config.YouTubeDailyQuota
+	}
+
+	type candidate struct {
+		query string
+		score float64
+	}
+
+	var known []float64
+	for _, q := range queries {
+		if y, ok := h.Queries[q]; ok && y.Runs > 0 {
+			known = append(known, y.Yield)
+		}
+	}
+	median := medianOf(known)
+
+	candidates := make([]candidate, 0, len(queries))
+	for _, q := range queries {
+		y, ok := h.Queries[q]
+		if !ok || y.Runs == 0 {
+			candidates = append(candidates, candidate{q, median})
+			continue
+		}
+		if y.Runs >= MinRunsBeforeSkip && y.DuplicateRate >= SkipDuplicateRate {
+			continue
+		}
+		candidates = append(candidates, candidate{q, y.Yield})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	plan := []models.QueryPlan{}
+	quartile := len(candidates) / 4
+	for i, c := range candidates {
+		videos := settings.VideosPerQuery
+		switch {
+		case quartile > 0 && i < quartile:
+			videos = settings.VideosPerQuery * 2
+		case quartile > 0 && i >= len(candidates)-quartile:
+			videos = settings.VideosPerQuery / 2
+		}
+		if videos < 1 {
+			videos = 1
+		}
+		if settings.MaxPages > 0 {
+			videos = min(videos, settings.MaxPages*config.YouTubeSearchPageSize)
+		}
+
+		cost := QueryCost(videos, settings)
+		if cost > budget {
+			continue
+		}
+		budget -= cost
+		plan = append(plan, models.QueryPlan{Query: c.query, MaxVideos: videos})
+	}
+
+	return plan
+}
+
+// QueryCost estimates the quota units a query costs with the given video budget
+func QueryCost(videos int, settings config.ScraperSettings) int {
+	return config.YouTubeQueryCost(videos, settings.CommentsPerVideo, settings.MaxPages)
+}
+
+// medianOf returns the median of values, or 0 if empty
+func medianOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
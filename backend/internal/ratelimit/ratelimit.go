@@ -0,0 +1,233 @@
+// Per-source request throttling shared by all scrapers
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+)
+
+// ErrDailyCap is returned by Wait once a source has used its requests for the day
+var ErrDailyCap = errors.New("daily request cap reached")
+
+// Limiter is a token bucket with an optional daily request cap.
+// A nil *Limiter never throttles.
+type Limiter struct {
+	source string
+	limit  config.RateLimit
+	tokens float64
+	last   time.Time
+	day    string // UTC date the count belongs to
+	count  int
+	mu     sync.Mutex
+}
+
+// New creates a limiter for source with the given limits
+func New(source string, limit config.RateLimit) *Limiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &Limiter{
+		source: source,
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+var (
+	registry   = make(map[string]*Limiter)
+	registryMu sync.Mutex
+)
+
+// ForSource returns the process-wide limiter for source, configured from config.RateLimits.
+// Sources without an entry are unthrottled.
+func ForSource(source string) *Limiter {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if l, ok := registry[source]; ok {
+		return l
+	}
+	l := New(source, config.RateLimits[source])
+	if saved, ok := savedCounts[source]; ok && saved.Day == today() {
+		l.day, l.count = saved.Day, saved.Count
+	}
+	registry[source] = l
+	return l
+}
+
+// ============================================
+// PERSISTED DAILY COUNTS
+// ============================================
+// Daily caps are provider quotas, so a restart mustn't hand a source a fresh day's
+// budget. Once LoadState is called, the counts of capped sources are saved to
+// StateFile after every request and picked up again by ForSource.
+
+// StateFile holds today's request counts of capped sources, inside the data directory
+const StateFile = "rate_limits.json"
+
+// savedCount is a source's entry in StateFile
+type savedCount struct {
+	Day   string `json:"day"` // UTC date the count belongs to
+	Count int    `json:"count"`
+}
+
+var (
+	statePath   string                // Empty until LoadState: counts aren't persisted
+	savedCounts map[string]savedCount // Counts read from StateFile, guarded by registryMu
+	stateMu     sync.Mutex            // Serializes writes to StateFile
+)
+
+// LoadState reads the daily counts saved in dataDir and saves them there from now on.
+// Call it before the first ForSource; limiters created earlier start from zero.
+func LoadState(dataDir string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	path := filepath.Join(dataDir, StateFile)
+	counts := map[string]savedCount{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read rate limit state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &counts); err != nil {
+			return fmt.Errorf("failed to parse rate limit state: %w", err)
+		}
+	}
+	statePath, savedCounts = path, counts
+	return nil
+}
+
+// saveState writes the daily counts of every capped source to StateFile
+func saveState() {
+	registryMu.Lock()
+	path := statePath
+	if path == "" {
+		registryMu.Unlock()
+		return
+	}
+	counts := maps.Clone(savedCounts) // Keeps sources this process hasn't used yet
+	for source, l := range registry {
+		if l.limit.DailyCap <= 0 {
+			continue
+		}
+		l.mu.Lock()
+		counts[source] = savedCount{Day: l.day, Count: l.count}
+		l.mu.Unlock()
+	}
+	registryMu.Unlock()
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	data, err := json.MarshalIndent(counts, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Failed to save rate limit state: %v\n", err)
+	}
+}
+
+// today is the current UTC date, the key daily counts are kept under
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// Wait blocks until the next request to the source may go out.
+// Returns ErrDailyCap once the day's cap is used, or ctx's error if it is cancelled first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if day := today(); day != l.day {
+		l.day = day
+		l.count = 0
+	}
+	if l.limit.DailyCap > 0 && l.count >= l.limit.DailyCap {
+		l.mu.Unlock()
+		return fmt.Errorf("%s: %w (%d)", l.source, ErrDailyCap, l.limit.DailyCap)
+	}
+	l.count++
+	day := l.day
+
+	var wait time.Duration
+	if l.limit.PerSecond > 0 {
+		// Refill for the time since the last request, then reserve a token.
+		// Tokens may go negative: each waiter reserves its own slot in the queue.
+		l.tokens += now.Sub(l.last).Seconds() * l.limit.PerSecond
+		if burst := float64(l.limit.Burst); l.tokens > burst {
+			l.tokens = burst
+		}
+		l.last = now
+		l.tokens--
+		if l.tokens < 0 {
+			wait = time.Duration(-l.tokens / l.limit.PerSecond * float64(time.Second))
+			if l.limit.Jitter > 0 {
+				wait += time.Duration(rand.Int63n(int64(l.limit.Jitter)))
+			}
+		}
+	}
+	l.mu.Unlock()
+	if l.limit.DailyCap > 0 {
+		saveState()
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(day)
+		return ctx.Err()
+	}
+}
+
+// release gives back the token and daily request reserved by a Wait that was
+// cancelled before its request went out
+func (l *Limiter) release(day string) {
+	l.mu.Lock()
+	if l.limit.PerSecond > 0 {
+		l.tokens++
+	}
+	if l.day == day && l.count > 0 {
+		l.count--
+	}
+	l.mu.Unlock()
+	if l.limit.DailyCap > 0 {
+		saveState()
+	}
+}
+
+// Used returns how many requests the source has made today
+func (l *Limiter) Used() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.day != today() {
+		return 0
+	}
+	return l.count
+}
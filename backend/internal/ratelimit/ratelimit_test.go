@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+)
+
+// TestDailyCountSurvivesRestart checks a cancelled Wait gives its request back, and
+// that the day's count is read back by a limiter created after LoadState
+func TestDailyCountSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadState(dir); err != nil {
+		t.Fatal(err)
+	}
+	config.RateLimits["test"] = config.RateLimit{PerSecond: 1, Burst: 1, DailyCap: 10}
+	t.Cleanup(func() {
+		delete(config.RateLimits, "test")
+		registryMu.Lock()
+		delete(registry, "test")
+		statePath, savedCounts = "", nil
+		registryMu.Unlock()
+	})
+
+	l := ForSource("test")
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Fatal("throttled Wait returned before its context was cancelled")
+	}
+	if used := l.Used(); used != 1 {
+		t.Fatalf("%d requests used after a cancelled Wait, want 1", used)
+	}
+
+	// A restart: the limiter is created afresh from the saved state
+	registryMu.Lock()
+	delete(registry, "test")
+	registryMu.Unlock()
+	if err := LoadState(dir); err != nil {
+		t.Fatal(err)
+	}
+	if used := ForSource("test").Used(); used != 1 {
+		t.Errorf("%d requests used after a restart, want 1", used)
+	}
+}
@@ -0,0 +1,227 @@
+// Daily snapshots of review-site aggregate ratings (Trustpilot, BBB)
+package ratings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// HistoryFile is the rating history file inside the data directory
+const HistoryFile = "rating_history.json"
+
+// Snapshot is one day's aggregate rating for an exchange on a review site
+type Snapshot struct {
+	Exchange    string    `json:"exchange"`
+	Source      string    `json:"source"` // "trustpilot", "bbb"
+	Date        string    `json:"date"`   // "2006-01-02"
+	Rating      float64   `json:"rating"` // Normalized to a 0-5 scale
+	ReviewCount int       `json:"review_count"`
+	URL         string    `json:"url"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+// Store keeps rating snapshots persisted to a JSON file
+type Store struct {
+	path      string
+	snapshots []Snapshot
+	mu        sync.RWMutex
+}
+
+// NewStore loads the rating history from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, HistoryFile)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rating history: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse rating history: %w", err)
+	}
+	return s, nil
+}
+
+// Record stores a snapshot, replacing any earlier snapshot for the same exchange/source/day
+func (s *Store) Record(snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snapshot.RecordedAt.IsZero() {
+		snapshot.RecordedAt = time.Now()
+	}
+	if snapshot.Date == "" {
+		snapshot.Date = snapshot.RecordedAt.Format("2006-01-02")
+	}
+
+	replaced := false
+	for i, existing := range s.snapshots {
+		if existing.Exchange == snapshot.Exchange && existing.Source == snapshot.Source && existing.Date == snapshot.Date {
+			s.snapshots[i] = snapshot
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		s.snapshots = append(s.snapshots, snapshot)
+	}
+
+	return s.save()
+}
+
+// Series returns an exchange's snapshots for a source within the last `days` days, oldest first.
+// days <= 0 returns the full history.
+func (s *Store) Series(exchange, source string, days int) []Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cutoff := ""
+	if days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+
+	series := []Snapshot{}
+	for _, snap := range s.snapshots {
+		if snap.Exchange == exchange && snap.Source == source && snap.Date >= cutoff {
+			series = append(series, snap)
+		}
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Date < series[j].Date })
+	return series
+}
+
+// Sources returns the review sites that have snapshots for an exchange
+func (s *Store) Sources(exchange string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	sources := []string{}
+	for _, snap := range s.snapshots {
+		if snap.Exchange == exchange && !seen[snap.Source] {
+			seen[snap.Source] = true
+			sources = append(sources, snap.Source)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// Trends summarizes every source's rating movement for an exchange over `days` days
+func (s *Store) Trends(exchange string, days int) []models.RatingTrend {
+	trends := []models.RatingTrend{}
+	for _, source := range s.Sources(exchange) {
+		series := s.Series(exchange, source, days)
+		if len(series) == 0 {
+			continue
+		}
+		first, last := series[0], series[len(series)-1]
+		trends = append(trends, models.RatingTrend{
+			Source:       source,
+			Latest:       last.Rating,
+			LatestDate:   last.Date,
+			Earliest:     first.Rating,
+			EarliestDate: first.Date,
+			Change:       last.Rating - first.Rating,
+			ReviewCount:  last.ReviewCount,
+			Snapshots:    len(series),
+		})
+	}
+	return trends
+}
+
+// save writes the history. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.snapshots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rating history: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rating history: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// FETCHING
+// ============================================
+
+var (
+	ratingValuePattern = regexp.MustCompile(`"ratingValue"\s*:\s*"?([0-9.]+)"?`)
+	reviewCountPattern = regexp.MustCompile(`"(?:reviewCount|ratingCount)"\s*:\s*"?([0-9]+)"?`)
+	bestRatingPattern  = regexp.MustCompile(`"bestRating"\s*:\s*"?([0-9.]+)"?`)
+)
+
+// TrustpilotURL returns the review page for a Trustpilot company slug (e.g. "www.coinbase.com")
+func TrustpilotURL(slug string) string {
+	return "https://www.trustpilot.com/review/" + slug
+}
+
+// FetchAggregateRating reads the schema.org aggregateRating embedded in a review page's
+// JSON-LD, which both Trustpilot and BBB publish for search engines. source ("trustpilot",
+// "bbb") picks the crawl etiquette the request is sent with.
+func FetchAggregateRating(ctx context.Context, client *http.Client, source, pageURL string) (rating float64, reviewCount int, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpclient.Identify(req, source)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, 0, health.StatusError("review page", resp.StatusCode, string(snippet))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read review page: %w", err)
+	}
+
+	return ParseAggregateRating(string(body))
+}
+
+// ParseAggregateRating extracts rating and review count from page HTML, normalizing to 0-5
+func ParseAggregateRating(html string) (float64, int, error) {
+	m := ratingValuePattern.FindStringSubmatch(html)
+	if m == nil {
+		return 0, 0, health.NewError(health.ErrorParse, fmt.Errorf("no aggregate rating found on page"))
+	}
+	rating, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, 0, health.NewError(health.ErrorParse, fmt.Errorf("invalid rating value %q", m[1]))
+	}
+
+	if b := bestRatingPattern.FindStringSubmatch(html); b != nil {
+		if best, err := strconv.ParseFloat(b[1], 64); err == nil && best > 0 && best != 5 {
+			rating = rating / best * 5
+		}
+	}
+
+	count := 0
+	if c := reviewCountPattern.FindStringSubmatch(html); c != nil {
+		count, _ = strconv.Atoi(c[1])
+	}
+	return rating, count, nil
+}
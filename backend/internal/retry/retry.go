@@ -0,0 +1,134 @@
+// Shared retry loop: exponential backoff with jitter, retryable-error predicates and context awareness
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy controls how often and how patiently an operation is retried
+type Policy struct {
+	MaxRetries int           // Retries after the first attempt
+	BaseDelay  time.Duration // Doubled on every retry
+	MaxDelay   time.Duration // Cap on a single backoff, and on a delay the server asked for (0 = uncapped)
+	Jitter     time.Duration // Up to this much random delay is added to each backoff, so clients don't retry in lockstep
+
+	// OnRetry, if set, is called before each wait with the attempt that failed (0-based)
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Do calls fn until it succeeds, fails with an error retryable rejects, or the
+// policy's retries run out, backing off between attempts. A nil retryable retries
+// every error Transient accepts. Permanent errors and context errors are never
+// retried, and cancelling ctx during a backoff returns ctx.Err().
+func Do(ctx context.Context, p Policy, retryable func(error) bool, fn func(attempt int) error) error {
+	if retryable == nil {
+		retryable = Transient
+	}
+	for attempt := 0; ; attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.MaxRetries || !Transient(err) || !retryable(err) || ctx.Err() != nil {
+			return unwrapPermanent(err)
+		}
+
+		delay := p.Backoff(attempt, err)
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Backoff returns the delay after the given failed attempt: the delay err asked for
+// (see After), or BaseDelay doubled per attempt plus jitter, capped at MaxDelay
+func (p Policy) Backoff(attempt int, err error) time.Duration {
+	var after *afterError
+	delay := p.BaseDelay << attempt
+	if errors.As(err, &after) {
+		delay = after.delay
+	} else if p.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(p.Jitter) + 1))
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	return delay
+}
+
+// ============================================
+// ERROR MARKERS AND PREDICATES
+// ============================================
+
+// permanentError marks an error that must not be retried
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying. Do returns the original error.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// unwrapPermanent strips the Permanent marker so callers see the error fn returned
+func unwrapPermanent(err error) error {
+	var p *permanentError
+	if errors.As(err, &p) && err == error(p) {
+		return p.err
+	}
+	return err
+}
+
+// afterError carries the delay a server asked for before the next attempt
+type afterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *afterError) Error() string { return e.err.Error() }
+func (e *afterError) Unwrap() error { return e.err }
+
+// After wraps err with the delay to wait before retrying, e.g. from a Retry-After header
+func After(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &afterError{err, delay}
+}
+
+// Transient reports whether err may succeed on another attempt: anything but a
+// Permanent error or the context being cancelled or timing out
+func Transient(err error) bool {
+	var p *permanentError
+	return !errors.As(err, &p) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Status reports whether an HTTP status is worth retrying: rate limited or a server error
+func Status(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// HeaderDelay reads a Retry-After header given in seconds
+func HeaderDelay(h http.Header) (time.Duration, bool) {
+	secs, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// CRON EXPRESSIONS
+// ============================================
+// Standard five-field expressions - minute hour day-of-month month day-of-week -
+// with *, lists (1,15), ranges (1-5), steps (*/15, 0-30/10), and the shorthands
+// @hourly, @daily, @weekly and @monthly. Times are evaluated in UTC. As in cron,
+// when both day fields are restricted a day matching either one runs.
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr             string
+	minute, hour     uint64
+	dom, month, dow  uint64
+	domStar, dowStar bool
+}
+
+var cronShorthands = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cronField is the range of values one field accepts
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+// ParseCron parses a five-field cron expression or shorthand
+func ParseCron(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if full, ok := cronShorthands[spec]; ok {
+		spec = full
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday)", expr)
+	}
+
+	var sets [5]uint64
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		sets[i] = set
+	}
+	// Fold Sunday-as-7 onto 0
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		expr:    expr,
+		minute:  sets[0],
+		hour:    sets[1],
+		dom:     sets[2],
+		month:   sets[3],
+		dow:     sets[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField turns one field into a bit set of the values it matches
+func parseCronField(part string, field cronField) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(part, ",") {
+		rng, stepText, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid %s step %q", field.name, stepText)
+			}
+			step = n
+		}
+
+		lo, hi := field.min, field.max
+		if rng != "*" {
+			loText, hiText, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loText); err != nil {
+				return 0, fmt.Errorf("invalid %s %q", field.name, item)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiText); err != nil {
+					return 0, fmt.Errorf("invalid %s %q", field.name, item)
+				}
+			} else if hasStep {
+				hi = field.max // "5/15" means from 5 to the end, every 15
+			}
+		}
+		if lo < field.min || hi > field.max || lo > hi {
+			return 0, fmt.Errorf("%s %q outside %d-%d", field.name, item, field.min, field.max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first matching minute strictly after t, in UTC. It returns the
+// zero time if nothing matches within five years (e.g. "0 0 31 2 *").
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			// Jump straight to the next matching minute in this hour, if any
+			rest := s.minute >> uint(t.Minute())
+			if rest == 0 {
+				t = t.Truncate(time.Hour).Add(time.Hour)
+			} else {
+				t = t.Add(time.Duration(bits.TrailingZeros64(rest)) * time.Minute)
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: with both day fields restricted, either may match
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowOK
+	case s.dowStar:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
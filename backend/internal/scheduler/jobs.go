@@ -0,0 +1,284 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/integrity"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/normalize"
+	"github.com/tasnint/coinsights/internal/planner"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/scrapers"
+	"github.com/tasnint/coinsights/internal/scrapesettings"
+	"github.com/tasnint/coinsights/internal/translate"
+)
+
+// ============================================
+// SCRAPE JOBS
+// ============================================
+// The jobs do what cmd/server does for each source, against the same data
+// directory: results replace the latest files the API reads, scraper health and
+// query history are recorded, and each run's results are also kept under its run ID.
+
+// Job names
+const (
+	JobYouTube = "youtube"
+	JobGemini  = "gemini"
+	JobGoogle  = "google"
+)
+
+// NewFromEnv schedules every job with a SCRAPE_SCHEDULE_<JOB> expression, returning
+// nil if none has one
+func NewFromEnv(dataDir string, runs *RunStore) (*Scheduler, error) {
+	jobs := map[string]func(string) (JobFunc, error){
+		JobYouTube: YouTubeJob,
+		JobGemini:  GeminiJob,
+		JobGoogle:  GoogleJob,
+	}
+
+	var s *Scheduler
+	for _, name := range []string{JobYouTube, JobGemini, JobGoogle} {
+		schedule, err := ScheduleFromEnv(name)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			continue
+		}
+		run, err := jobs[name](dataDir)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled %s scrape: %w", name, err)
+		}
+		if s == nil {
+			s = New(runs)
+		}
+		s.Add(Job{Name: name, Schedule: schedule, Run: run})
+	}
+	return s, nil
+}
+
+// YouTubeJob scrapes YouTube with the active scraper settings preset
+func YouTubeJob(dataDir string) (JobFunc, error) {
+	apiKey := os.Getenv("YOUTUBE_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("YOUTUBE_API_KEY not set")
+	}
+
+	return func(ctx context.Context, runID string, progress scrapers.ProgressFunc) (*Output, error) {
+		presets, err := scrapesettings.NewStore(dataDir)
+		if err != nil {
+			return nil, err
+		}
+		_, settings := presets.Active()
+
+		historyPath := filepath.Join(dataDir, planner.HistoryFile)
+		queryHistory, err := planner.LoadHistory(historyPath)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			queryHistory = planner.NewHistory()
+		}
+		var plan []models.QueryPlan
+		if settings.Adaptive {
+			plan = queryHistory.Plan(config.SearchQueries, settings)
+		} else {
+			queries := config.SearchQueries
+			if settings.MaxQueries > 0 && settings.MaxQueries < len(queries) {
+				queries = queries[:settings.MaxQueries]
+			}
+			for _, q := range queries {
+				plan = append(plan, models.QueryPlan{Query: q, MaxVideos: settings.VideosPerQuery})
+			}
+		}
+
+		scraper := scrapers.NewYouTubeScraper(apiKey)
+		scraper.Filter = scrapers.VideoFilter{
+			ExcludeShorts: settings.ExcludeShorts,
+			MinDuration:   settings.MinVideoDuration(),
+			MaxDuration:   settings.MaxVideoDuration(),
+		}
+		scraper.MaxPages = settings.MaxPages
+		scraper.Progress = progress
+
+		result, err := scraper.ScrapePlanned(plan, settings.CommentsPerVideo)
+		quota := 0
+		var runErrors []models.RunError
+		if result != nil {
+			for _, stat := range result.QueryStats {
+				quota += stat.QuotaUsed
+			}
+			runErrors = result.Errors
+		}
+		recordHealth(dataDir, JobYouTube, err, runErrors, quota, config.YouTubeDailyQuota)
+		if err != nil {
+			return nil, err
+		}
+		result.RunID = runID
+
+		// Translate non-English comments so keyword analysis doesn't undercount them
+		if translate.Enabled() {
+			translateComments(ctx, result)
+		}
+
+		if err := updateLatest(dataDir, func(latest *models.ScrapeResult) {
+			result.GoogleResults = latest.GoogleResults // Google runs on its own schedule
+			*latest = *result
+		}); err != nil {
+			return nil, err
+		}
+
+		runAnalyzer := analyzer.NewYouTubeAnalyzer()
+		runAnalyzer.AnalyzeResult(result)
+		queryHistory.Record(result, runAnalyzer.Issues())
+		if err := queryHistory.Save(historyPath); err != nil {
+			fmt.Printf("⚠️  Failed to save query history: %v\n", err)
+		}
+
+		return &Output{Data: result, Items: len(result.Videos) + len(result.Comments), RunErrors: result.Errors}, nil
+	}, nil
+}
+
+// translateComments replaces non-English comment text with an English translation,
+// keeping the original in OriginalText. Failures leave the comments untranslated.
+func translateComments(ctx context.Context, result *models.ScrapeResult) {
+	translator, err := translate.NewGeminiTranslator(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  Translation unavailable: %v\n", err)
+		return
+	}
+	count, err := translate.Comments(ctx, translator, result.Comments)
+	if err != nil {
+		fmt.Printf("⚠️  Translation error: %v\n", err)
+		return
+	}
+	fmt.Printf("🌐 Translated %d comments\n", count)
+}
+
+// GeminiJob asks Gemini the AI search queries, reusing cached answers while fresh
+func GeminiJob(dataDir string) (JobFunc, error) {
+	if os.Getenv("GEMINI_API_KEY") == "" && os.Getenv("GOOGLE_API_KEY") == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY not set")
+	}
+	budget, err := scrapers.GeminiBudgetFromEnv(config.DefaultGeminiBudget())
+	if err != nil {
+		return nil, err
+	}
+	grounding, err := scrapers.GroundingRulesFromEnv(config.DefaultGroundingRules())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, runID string, progress scrapers.ProgressFunc) (*Output, error) {
+		scraper, err := scrapers.NewGeminiScraper()
+		if err != nil {
+			return nil, err
+		}
+		defer scraper.Close()
+
+		if answers, err := scrapers.NewAnswerCacheFromEnv(dataDir); err != nil {
+			fmt.Printf("⚠️  Gemini answer cache disabled: %v\n", err)
+		} else {
+			scraper.EnableAnswerCache(answers)
+		}
+		scraper.EnableExpansion(config.DefaultExpansionSettings())
+		scraper.SetBudget(budget)
+		scraper.SetGrounding(grounding)
+		scraper.SetProgress(progress)
+
+		results, err := scraper.SearchMultipleQueries(ctx, config.AISearchQueries)
+		recordHealth(dataDir, JobGemini, err, scraper.RunErrors(), ratelimit.ForSource("gemini").Used(), config.RateLimits["gemini"].DailyCap)
+		if err != nil {
+			return nil, err
+		}
+		for i := range results {
+			results[i].RunID = runID
+		}
+
+		if err := writeJSON(filepath.Join(dataDir, "gemini_latest_results.json"), results); err != nil {
+			return nil, err
+		}
+		history, err := scrapers.NewComplaintHistory(dataDir)
+		if err == nil {
+			err = history.Record(normalize.Gemini(results, time.Now()))
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Failed to update Gemini complaint history: %v\n", err)
+		}
+
+		return &Output{Data: results, Items: len(results), RunErrors: scraper.RunErrors()}, nil
+	}, nil
+}
+
+// GoogleJob searches Google for the web search queries. Results replace the Google
+// results in the latest YouTube results file, where the analyzer reads them.
+func GoogleJob(dataDir string) (JobFunc, error) {
+	return func(ctx context.Context, runID string, progress scrapers.ProgressFunc) (*Output, error) {
+		scraper := scrapers.NewGoogleScraper()
+		scraper.Progress = progress
+		results, err := scraper.ScrapeAll(ctx, config.GoogleSearchQueries, config.GoogleResultsPerQuery)
+		recordHealth(dataDir, JobGoogle, err, scraper.RunErrors(), len(config.GoogleSearchQueries), 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := updateLatest(dataDir, func(latest *models.ScrapeResult) {
+			latest.GoogleResults = results
+		}); err != nil {
+			return nil, err
+		}
+		return &Output{Data: results, Items: len(results), RunErrors: scraper.RunErrors()}, nil
+	}, nil
+}
+
+// updateLatest applies update to the latest YouTube results file, starting from an
+// empty result if there isn't one yet
+func updateLatest(dataDir string, update func(latest *models.ScrapeResult)) error {
+	path := filepath.Join(dataDir, "youtube_latest_results.json")
+	latest := &models.ScrapeResult{}
+	// Rewriting a tampered file would record a fresh checksum over it
+	if err := integrity.Verify(path); err != nil {
+		return fmt.Errorf("refusing to update %s: %w", filepath.Base(path), err)
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, latest); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	update(latest)
+	return writeJSON(path, latest)
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := integrity.WriteFile(path, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// recordHealth records a run in the health file behind GET /api/admin/scrapers/status
+func recordHealth(dataDir, source string, runErr error, runErrors []models.RunError, quotaUsed, quotaLimit int) {
+	store, err := health.NewStore(dataDir)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load scraper health: %v\n", err)
+		return
+	}
+	if runErr != nil {
+		err = store.RecordError(source, runErr)
+	} else {
+		err = store.RecordSuccess(source, quotaUsed, quotaLimit, runErrors)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  Failed to record %s health: %v\n", source, err)
+	}
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// LIVE RUN PROGRESS
+// ============================================
+// A scheduled run can take most of an hour, and until now the only sign of life
+// was the run record flipping to succeeded or failed. Jobs report each query,
+// video and error as they happen; the hub keeps every event of recent runs in
+// memory and fans them out to subscribers, so a dashboard that connects mid-run
+// first gets what it missed and then follows along.
+
+const (
+	// MaxProgressRuns is how many runs' events the hub keeps; the oldest run is dropped first
+	MaxProgressRuns = 20
+	// MaxProgressEvents caps the events kept per run; later events are still sent to subscribers
+	MaxProgressEvents = 2000
+	// progressBuffer is each subscriber's channel size. A subscriber that falls this
+	// far behind misses events rather than stalling the run.
+	progressBuffer = 256
+)
+
+// runProgress is the events and subscribers of one run
+type runProgress struct {
+	seq         int // Sequence number of the last event
+	events      []models.ScrapeProgress
+	subscribers map[chan models.ScrapeProgress]struct{}
+	finished    bool
+}
+
+// ProgressHub keeps recent runs' progress events and sends new ones to subscribers
+type ProgressHub struct {
+	runs  map[string]*runProgress
+	order []string // Run IDs, oldest first
+	mu    sync.Mutex
+}
+
+// NewProgressHub creates an empty hub
+func NewProgressHub() *ProgressHub {
+	return &ProgressHub{runs: make(map[string]*runProgress)}
+}
+
+// publish numbers an event within its run, records it and sends it to the run's subscribers
+func (h *ProgressHub) publish(event models.ScrapeProgress) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	run, ok := h.runs[event.RunID]
+	if !ok {
+		run = &runProgress{subscribers: make(map[chan models.ScrapeProgress]struct{})}
+		h.runs[event.RunID] = run
+		h.order = append(h.order, event.RunID)
+		if len(h.order) > MaxProgressRuns {
+			h.drop(h.order[0])
+		}
+	}
+	if run.finished {
+		return
+	}
+	run.seq++
+	event.Seq = run.seq
+	if len(run.events) < MaxProgressEvents {
+		run.events = append(run.events, event)
+	}
+	for ch := range run.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	if event.Type == models.ProgressFinished {
+		run.finished = true
+		for ch := range run.subscribers {
+			close(ch)
+		}
+		run.subscribers = nil
+	}
+}
+
+// Subscribe returns the events a run has had so far and, while it is still
+// running, a channel of the events to come, closed once it finishes. ok is false
+// if the hub has nothing for the run. cancel must be called when done.
+func (h *ProgressHub) Subscribe(runID string) (past []models.ScrapeProgress, events <-chan models.ScrapeProgress, cancel func(), ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	run, ok := h.runs[runID]
+	if !ok {
+		return nil, nil, func() {}, false
+	}
+	past = append([]models.ScrapeProgress(nil), run.events...)
+	if run.finished {
+		return past, nil, func() {}, true
+	}
+
+	ch := make(chan models.ScrapeProgress, progressBuffer)
+	run.subscribers[ch] = struct{}{}
+	cancel = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, subscribed := run.subscribers[ch]; subscribed {
+			delete(run.subscribers, ch)
+			close(ch)
+		}
+	}
+	return past, ch, cancel, true
+}
+
+// drop forgets a run, closing its subscribers. Must be called with h.mu held.
+func (h *ProgressHub) drop(runID string) {
+	if run, ok := h.runs[runID]; ok {
+		for ch := range run.subscribers {
+			close(ch)
+		}
+		run.subscribers = nil
+		delete(h.runs, runID)
+	}
+	for i, id := range h.order {
+		if id == runID {
+			h.order = append(h.order[:i], h.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// FinishedEvent is the finished event for a run that has ended
+func FinishedEvent(run Run) models.ScrapeProgress {
+	event := models.ScrapeProgress{Type: models.ProgressFinished, RunID: run.ID, Source: run.Job, Items: run.Items, Status: run.Status, At: time.Now()}
+	if run.FinishedAt != nil {
+		event.At = *run.FinishedAt
+	}
+	if run.Error != "" {
+		event.Error = &models.RunError{Source: run.Job, Kind: "other", Message: run.Error, At: event.At}
+	}
+	return event
+}
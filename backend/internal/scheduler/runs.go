@@ -0,0 +1,228 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/integrity"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+const (
+	// RunsFile is the scheduled run history inside the data directory
+	RunsFile = "scrape_runs.json"
+	// ResultsDir holds each run's results, one file per run ID, inside the data directory
+	ResultsDir = "scrape_runs"
+	// MaxRuns caps the history; the oldest runs and their result files are dropped first
+	MaxRuns = 500
+)
+
+// Run statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Run is one scheduled scrape
+type Run struct {
+	ID         string            `json:"id"`
+	Job        string            `json:"job"` // "youtube", "gemini", "google"
+	Schedule   string            `json:"schedule"`
+	Status     string            `json:"status"` // "running", "succeeded", "failed"
+	StartedAt  time.Time         `json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	Items      int               `json:"items"`                   // Videos, answers or search results the run collected
+	ResultFile string            `json:"result_file,omitempty"`   // Relative to the data directory
+	ResultSHA  string            `json:"result_sha256,omitempty"` // Checked against the result file on load
+	Error      string            `json:"error,omitempty"`
+	RunErrors  []models.RunError `json:"run_errors,omitempty"` // Failures the run skipped past
+
+	// Integrity is set on load when the result file no longer matches ResultSHA:
+	// "modified" or "missing"
+	Integrity string `json:"integrity,omitempty"`
+}
+
+// RunStore keeps the run history persisted to a JSON file, and each run's results
+// in a file of its own
+type RunStore struct {
+	dataDir string
+	runs    []Run
+	mu      sync.RWMutex
+}
+
+// NewRunStore loads the run history from dataDir (empty if the file doesn't exist yet)
+func NewRunStore(dataDir string) (*RunStore, error) {
+	s := &RunStore{dataDir: dataDir}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, RunsFile))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrape runs: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.runs); err != nil {
+		return nil, fmt.Errorf("failed to parse scrape runs: %w", err)
+	}
+	// A run still marked running was cut off when the server last stopped
+	for i := range s.runs {
+		if s.runs[i].Status == StatusRunning {
+			s.runs[i].Status = StatusFailed
+			s.runs[i].Error = "interrupted: the server stopped during the run"
+		}
+		s.verify(&s.runs[i])
+	}
+	return s, nil
+}
+
+// verify flags a run whose result file doesn't match the checksum recorded with it
+func (s *RunStore) verify(run *Run) {
+	run.Integrity = ""
+	if run.ResultFile == "" || run.ResultSHA == "" {
+		return
+	}
+	sum, err := integrity.SumFile(filepath.Join(s.dataDir, run.ResultFile))
+	switch {
+	case os.IsNotExist(err):
+		run.Integrity = integrity.ProblemMissing
+	case err != nil:
+		fmt.Printf("⚠️  Failed to verify %s: %v\n", run.ResultFile, err)
+	case sum != run.ResultSHA:
+		run.Integrity = integrity.ProblemModified
+	}
+	if run.Integrity != "" {
+		fmt.Printf("⚠️  Scrape run %s: result file %s is %s\n", run.ID, run.ResultFile, run.Integrity)
+	}
+}
+
+// start records a run as running
+func (s *RunStore) start(run Run) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run.Status = StatusRunning
+	s.runs = append(s.runs, run)
+	if len(s.runs) > MaxRuns {
+		for _, old := range s.runs[:len(s.runs)-MaxRuns] {
+			if old.ResultFile != "" {
+				path := filepath.Join(s.dataDir, old.ResultFile)
+				os.Remove(path)
+				integrity.Forget(path)
+			}
+		}
+		s.runs = append([]Run(nil), s.runs[len(s.runs)-MaxRuns:]...)
+	}
+	return s.save()
+}
+
+// finish writes a run's results under its ID and records how it ended
+func (s *RunStore) finish(id string, out *Output, runErr error) (Run, error) {
+	resultFile, resultSHA := "", ""
+	var writeErr error
+	if out != nil && out.Data != nil {
+		resultFile = filepath.Join(ResultsDir, id+".json")
+		resultSHA, writeErr = s.writeResult(resultFile, out.Data)
+		if writeErr != nil {
+			resultFile = ""
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.runs {
+		if s.runs[i].ID != id {
+			continue
+		}
+		run := &s.runs[i]
+		now := time.Now()
+		run.FinishedAt = &now
+		run.ResultFile = resultFile
+		run.ResultSHA = resultSHA
+		run.Status = StatusSucceeded
+		if out != nil {
+			run.Items = out.Items
+			run.RunErrors = out.RunErrors
+		}
+		if err := firstError(runErr, writeErr); err != nil {
+			run.Status = StatusFailed
+			run.Error = err.Error()
+		}
+		return *run, s.save()
+	}
+	return Run{}, fmt.Errorf("scrape run not found: %s", id)
+}
+
+// writeResult saves a run's results to path, relative to the data directory, and
+// returns their checksum
+func (s *RunStore) writeResult(path string, data any) (string, error) {
+	if err := os.MkdirAll(filepath.Join(s.dataDir, ResultsDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", ResultsDir, err)
+	}
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal run results: %w", err)
+	}
+	if err := integrity.WriteFile(filepath.Join(s.dataDir, path), body); err != nil {
+		return "", fmt.Errorf("failed to write run results: %w", err)
+	}
+	return integrity.Sum(body), nil
+}
+
+// List returns up to limit runs, newest first. An empty job lists every job's runs;
+// limit <= 0 returns them all.
+func (s *RunStore) List(job string, limit int) []Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := []Run{}
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		if job != "" && s.runs[i].Job != job {
+			continue
+		}
+		runs = append(runs, s.runs[i])
+		if limit > 0 && len(runs) == limit {
+			break
+		}
+	}
+	return runs
+}
+
+// Get returns the run with the given ID
+func (s *RunStore) Get(id string) (Run, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		if s.runs[i].ID == id {
+			return s.runs[i], true
+		}
+	}
+	return Run{}, false
+}
+
+// save writes the history. Must be called with s.mu held.
+func (s *RunStore) save() error {
+	data, err := json.MarshalIndent(s.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scrape runs: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dataDir, RunsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write scrape runs: %w", err)
+	}
+	return nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
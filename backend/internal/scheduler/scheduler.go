@@ -0,0 +1,199 @@
+// Built-in cron runner for the scrapers, with a persisted history of every run
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/scrapers"
+)
+
+// ============================================
+// SCHEDULED SCRAPING
+// ============================================
+// Each job runs on its own cron expression, set with SCRAPE_SCHEDULE_<JOB>
+// (e.g. SCRAPE_SCHEDULE_YOUTUBE="0 6 * * *"). Jobs run one at a time - they share
+// rate limiters and API quotas - so a job that comes due while another is running
+// starts when it finishes. A run that was due while the server was down is not
+// made up; the job waits for its next time. Every run is recorded with its run ID,
+// status and result file, and GET /api/scrape-runs lists them.
+
+// Job is a named scrape run on a schedule
+type Job struct {
+	Name     string
+	Schedule *Schedule
+	Run      JobFunc
+}
+
+// JobFunc runs a scrape under runID, which its results should carry, telling
+// progress about each step as it goes
+type JobFunc func(ctx context.Context, runID string, progress scrapers.ProgressFunc) (*Output, error)
+
+// Output is what a job collected
+type Output struct {
+	Data      any // Saved under the run ID; nil saves nothing
+	Items     int
+	RunErrors []models.RunError
+}
+
+// JobInfo describes a scheduled job and when it next runs
+type JobInfo struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	NextRun  time.Time `json:"next_run"`
+}
+
+// Scheduler runs jobs on their schedules
+type Scheduler struct {
+	runs     *RunStore
+	progress *ProgressHub
+	jobs     []Job
+	next     map[string]time.Time
+	mu       sync.Mutex
+
+	// OnRun, if set, is called after every run, e.g. to reload the API's data
+	OnRun func(run Run)
+}
+
+// New creates a scheduler recording runs in runs
+func New(runs *RunStore) *Scheduler {
+	return &Scheduler{runs: runs, progress: NewProgressHub(), next: make(map[string]time.Time)}
+}
+
+// Progress returns the hub recent runs' progress events are published to
+func (s *Scheduler) Progress() *ProgressHub {
+	return s.progress
+}
+
+// Add schedules a job
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, job)
+	s.next[job.Name] = job.Schedule.Next(time.Now())
+}
+
+// Jobs returns the scheduled jobs with their next run times, soonest first
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, JobInfo{Name: job.Name, Schedule: job.Schedule.String(), NextRun: s.next[job.Name]})
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].NextRun.Before(jobs[j].NextRun) })
+	return jobs
+}
+
+// Run runs jobs as they come due until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		due, wait := s.due(time.Now())
+		for _, job := range due {
+			if ctx.Err() != nil {
+				return
+			}
+			s.runJob(ctx, job)
+		}
+		if len(due) > 0 {
+			continue
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// due returns the jobs whose time has come, in schedule order, advancing each to
+// its next time, or how long until the soonest job if none has
+func (s *Scheduler) due(now time.Time) ([]Job, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Job
+	dueAt := make(map[string]time.Time)
+	wait := time.Hour
+	for _, job := range s.jobs {
+		next := s.next[job.Name]
+		if next.IsZero() {
+			continue // Expression never matches
+		}
+		if !next.After(now) {
+			due = append(due, job)
+			dueAt[job.Name] = next
+			s.next[job.Name] = job.Schedule.Next(now)
+			continue
+		}
+		wait = min(wait, next.Sub(now))
+	}
+	sort.SliceStable(due, func(i, j int) bool { return dueAt[due[i].Name].Before(dueAt[due[j].Name]) })
+	return due, wait
+}
+
+// runJob runs one job and records it
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	started := time.Now()
+	run := Run{
+		ID:        models.NewRunID(started) + "_" + job.Name,
+		Job:       job.Name,
+		Schedule:  job.Schedule.String(),
+		StartedAt: started,
+	}
+	// Published before the run is recorded, so a client that sees it running always finds its events
+	s.progress.publish(models.ScrapeProgress{Type: models.ProgressStarted, RunID: run.ID, Source: job.Name, At: started})
+	if err := s.runs.start(run); err != nil {
+		fmt.Printf("⚠️  Failed to record %s run: %v\n", job.Name, err)
+	}
+	fmt.Printf("⏰ Scheduled %s scrape started (%s)\n", job.Name, run.ID)
+
+	out, err := job.Run(ctx, run.ID, func(p models.ScrapeProgress) {
+		p.RunID = run.ID
+		s.progress.publish(p)
+	})
+	finished, recordErr := s.runs.finish(run.ID, out, err)
+	if recordErr != nil {
+		fmt.Printf("⚠️  Failed to record %s run: %v\n", job.Name, recordErr)
+		if finished.ID == "" { // The run was never recorded
+			finished = run
+			finished.Status = StatusFailed
+			finished.Error = recordErr.Error()
+		}
+		s.progress.publish(FinishedEvent(finished))
+		return
+	}
+	s.progress.publish(FinishedEvent(finished))
+	if finished.Status == StatusFailed {
+		fmt.Printf("⚠️  Scheduled %s scrape failed: %s\n", job.Name, finished.Error)
+	} else {
+		fmt.Printf("✅ Scheduled %s scrape collected %d items in %s\n", job.Name, finished.Items, time.Since(started).Round(time.Second))
+	}
+	if s.OnRun != nil {
+		s.OnRun(finished)
+	}
+}
+
+// ScheduleFromEnv reads SCRAPE_SCHEDULE_<JOB>, returning nil if it isn't set
+func ScheduleFromEnv(job string) (*Schedule, error) {
+	key := "SCRAPE_SCHEDULE_" + strings.ToUpper(job)
+	expr := strings.TrimSpace(os.Getenv(key))
+	if expr == "" {
+		return nil, nil
+	}
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return schedule, nil
+}
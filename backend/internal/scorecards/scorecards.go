@@ -0,0 +1,254 @@
+// Exchange scorecards, live and as daily snapshots for charting reputation over months
+package scorecards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratings"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+const (
+	// HistoryFile is the scorecard history file inside the data directory
+	HistoryFile = "scorecard_history.json"
+	// SnapshotWindowDays is the window a snapshot's complaints, sentiment and ratings cover
+	SnapshotWindowDays = 7
+	// DefaultSnapshotInterval is how often RunRecorder snapshots; a day's snapshot is
+	// replaced until the day is over, so the last one of the day is what's kept
+	DefaultSnapshotInterval = time.Hour
+)
+
+// ============================================
+// BUILDER
+// ============================================
+
+// Builder computes scorecards from tracked issues, review-site ratings and,
+// when configured, the complaints of the exchange the scraper follows
+type Builder struct {
+	resolution *services.ResolutionService
+	ratings    *ratings.Store // Optional
+
+	// Optional: scraped complaints, all about complaintsExchange
+	complaints         func() []models.Complaint
+	complaintsExchange string
+}
+
+// NewBuilder creates a builder; ratingStore may be nil
+func NewBuilder(rs *services.ResolutionService, ratingStore *ratings.Store) *Builder {
+	return &Builder{resolution: rs, ratings: ratingStore}
+}
+
+// EnableSentiment adds complaint counts and sentiment to exchange's scorecards.
+// complaints returns the currently loaded complaints, which are all about exchange.
+func (b *Builder) EnableSentiment(exchange string, complaints func() []models.Complaint) {
+	b.complaintsExchange = exchange
+	b.complaints = complaints
+}
+
+// Build returns an exchange's scorecard, with ratings and sentiment over the last days days
+// (days <= 0 covers the full history)
+func (b *Builder) Build(exchange string, days int, now time.Time) models.ExchangeScorecard {
+	scorecard := models.ExchangeScorecard{
+		Exchange:         exchange,
+		IssuesBySeverity: make(map[string]int),
+		Ratings:          []models.RatingTrend{},
+		GeneratedAt:      now,
+	}
+
+	for _, issue := range b.resolution.ListIssues("") {
+		if issue.Tenant != "" || !strings.EqualFold(issue.Exchange, exchange) {
+			continue
+		}
+		switch issue.Status {
+		case "resolved", "verified":
+			scorecard.ResolvedIssues++
+		default:
+			scorecard.OpenIssues++
+			scorecard.IssuesBySeverity[issue.Severity]++
+		}
+	}
+
+	if b.ratings != nil {
+		scorecard.Ratings = b.ratings.Trends(exchange, days)
+	}
+
+	if b.complaints != nil && strings.EqualFold(exchange, b.complaintsExchange) {
+		start := time.Time{}
+		if days > 0 {
+			start = now.AddDate(0, 0, -days)
+		}
+		period := analyzer.SummarizePeriod(analyzer.InPeriod(b.complaints(), "", start, now), start, now)
+		scorecard.Complaints = period.Complaints
+		if period.Scored > 0 {
+			sentiment := period.Sentiment
+			scorecard.Sentiment = &sentiment
+		}
+	}
+
+	return scorecard
+}
+
+// Snapshot returns the scorecard to record for an exchange on now's day
+func (b *Builder) Snapshot(exchange string, now time.Time) models.ScorecardSnapshot {
+	scorecard := b.Build(exchange, SnapshotWindowDays, now)
+	snapshot := models.ScorecardSnapshot{
+		Exchange:         strings.ToLower(exchange),
+		Date:             now.Format("2006-01-02"),
+		OpenIssues:       scorecard.OpenIssues,
+		ResolvedIssues:   scorecard.ResolvedIssues,
+		IssuesBySeverity: scorecard.IssuesBySeverity,
+		Complaints:       scorecard.Complaints,
+		Sentiment:        scorecard.Sentiment,
+		Ratings:          make(map[string]float64, len(scorecard.Ratings)),
+		RecordedAt:       now,
+	}
+	for _, trend := range scorecard.Ratings {
+		snapshot.Ratings[trend.Source] = trend.Latest
+	}
+	return snapshot
+}
+
+// Exchanges returns every exchange with a tracked issue, plus the one complaints are about
+func (b *Builder) Exchanges() []string {
+	seen := make(map[string]bool)
+	if b.complaintsExchange != "" {
+		seen[strings.ToLower(b.complaintsExchange)] = true
+	}
+	for _, issue := range b.resolution.ListIssues("") {
+		if issue.Tenant == "" { // Scorecards are public; tenants' issues stay private
+			seen[strings.ToLower(issue.Exchange)] = true
+		}
+	}
+	delete(seen, "")
+
+	exchanges := make([]string, 0, len(seen))
+	for exchange := range seen {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	return exchanges
+}
+
+// ============================================
+// HISTORY STORE
+// ============================================
+
+// Store keeps scorecard snapshots persisted to a JSON file
+type Store struct {
+	path      string
+	snapshots []models.ScorecardSnapshot
+	mu        sync.RWMutex
+}
+
+// NewStore loads the scorecard history from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, HistoryFile)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scorecard history: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse scorecard history: %w", err)
+	}
+	return s, nil
+}
+
+// Record stores snapshots, replacing any earlier snapshot for the same exchange and day
+func (s *Store) Record(snapshots ...models.ScorecardSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		replaced := false
+		for i, existing := range s.snapshots {
+			if existing.Exchange == snapshot.Exchange && existing.Date == snapshot.Date {
+				s.snapshots[i] = snapshot
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.snapshots = append(s.snapshots, snapshot)
+		}
+	}
+
+	return s.save()
+}
+
+// Series returns an exchange's snapshots within the last `days` days, oldest first.
+// days <= 0 returns the full history.
+func (s *Store) Series(exchange string, days int) []models.ScorecardSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cutoff := ""
+	if days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	}
+
+	series := []models.ScorecardSnapshot{}
+	for _, snap := range s.snapshots {
+		if strings.EqualFold(snap.Exchange, exchange) && snap.Date >= cutoff {
+			series = append(series, snap)
+		}
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Date < series[j].Date })
+	return series
+}
+
+// save writes the history. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.snapshots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scorecard history: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write scorecard history: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// RECORDER
+// ============================================
+
+// RecordAll snapshots every exchange the builder knows of
+func RecordAll(b *Builder, store *Store, now time.Time) error {
+	exchanges := b.Exchanges()
+	snapshots := make([]models.ScorecardSnapshot, len(exchanges))
+	for i, exchange := range exchanges {
+		snapshots[i] = b.Snapshot(exchange, now)
+	}
+	return store.Record(snapshots...)
+}
+
+// RunRecorder snapshots every exchange immediately and then every interval until ctx is cancelled
+func RunRecorder(ctx context.Context, b *Builder, store *Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := RecordAll(b, store, time.Now()); err != nil {
+			fmt.Printf("⚠️  Failed to record scorecard snapshots: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,19 +3,35 @@ package scrapers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+	"github.com/tasnint/coinsights/internal/retry"
 	"google.golang.org/genai"
 )
 
 // GeminiScraper uses Gemini AI with Google Search grounding to find complaints
 type GeminiScraper struct {
-	client *genai.Client
-	apiKey string
+	client    *genai.Client
+	apiKey    string
+	limiter   *ratelimit.Limiter
+	answers   *AnswerCache              // Optional: reuse fresh answers instead of re-asking
+	expand    *config.ExpansionSettings // Optional: follow up on thin answers
+	budget    *config.GeminiBudget      // Optional: per-run token and cost caps
+	grounding []config.GroundingRule    // Optional: domains each type of query is grounded on
+	progress  ProgressFunc              // Optional: told about each query as SearchMultipleQueries runs
+
+	runErrors []models.RunError // Failures from the last SearchMultipleQueries
+	usage     GeminiUsage       // Spend since the last SearchMultipleQueries started
+	usageMu   sync.Mutex
 }
 
 // AIOverviewResult represents the structured output from Gemini
@@ -25,7 +41,13 @@ type AIOverviewResult struct {
 	KeyComplaints      []ExtractedComplaint `json:"key_complaints"`
 	Sources            []SourceReference    `json:"sources"`
 	SentimentBreakdown SentimentStats       `json:"sentiment_breakdown"`
+	RunID              string               `json:"run_id,omitempty"` // Set by SearchMultipleQueries
 	GeneratedAt        time.Time            `json:"generated_at"`
+	Cached             bool                 `json:"cached,omitempty"`            // Reused from the answer cache rather than asked again
+	FollowUps          []string             `json:"follow_up_queries,omitempty"` // Refined queries merged in because the answer was thin
+	QueryType          string               `json:"query_type,omitempty"`        // Grounding rule the query matched
+	GroundingDomains   []string             `json:"grounding_domains,omitempty"` // Domains the rule grounded the query on
+	Grounding          []SourceReference    `json:"grounding,omitempty"`         // Pages Google Search grounded the answer on
 }
 
 // ExtractedComplaint represents a complaint extracted by Gemini
@@ -34,6 +56,9 @@ type ExtractedComplaint struct {
 	Description string `json:"description"` // The complaint text
 	Frequency   string `json:"frequency"`   // "common", "occasional", "rare"
 	Platform    string `json:"platform"`    // Where it was found (reddit, twitter, etc.)
+
+	// Domains of the grounding pages behind the answer's statement of this complaint
+	GroundedBy []string `json:"grounded_by,omitempty"`
 }
 
 // SourceReference tracks where information came from
@@ -73,8 +98,9 @@ func NewGeminiScraper() (*GeminiScraper, error) {
 	}
 
 	return &GeminiScraper{
-		client: client,
-		apiKey: apiKey,
+		client:  client,
+		apiKey:  apiKey,
+		limiter: ratelimit.ForSource("gemini"),
 	}, nil
 }
 
@@ -83,11 +109,80 @@ func (gs *GeminiScraper) Close() {
 	// New SDK doesn't require explicit close
 }
 
-// SearchComplaintsWithAI searches for complaints using Gemini with Google Search grounding
+// EnableAnswerCache reuses answers from cache while they are fresh
+func (gs *GeminiScraper) EnableAnswerCache(cache *AnswerCache) {
+	gs.answers = cache
+}
+
+// CachedAnswers splits queries into those with a fresh cached answer and those still to ask
+func (gs *GeminiScraper) CachedAnswers(queries []string) (cached []AIOverviewResult, missing []string) {
+	now := time.Now()
+	for _, query := range queries {
+		if result, ok := gs.answers.Get(query, gs.groundingFor(query), now); ok {
+			cached = append(cached, *result)
+		} else {
+			missing = append(missing, query)
+		}
+	}
+	return cached, missing
+}
+
+// CacheAnswers stores freshly generated answers in the answer cache, if enabled
+func (gs *GeminiScraper) CacheAnswers(results []AIOverviewResult) {
+	for _, result := range results {
+		if err := gs.answers.Put(result, gs.groundingFor(result.Query)); err != nil {
+			fmt.Printf("⚠️  Failed to cache Gemini answer: %v\n", err)
+			return
+		}
+	}
+}
+
+// SearchComplaintsWithAI searches for complaints using Gemini with Google Search grounding.
+// A fresh cached answer is returned without calling Gemini.
 func (gs *GeminiScraper) SearchComplaintsWithAI(ctx context.Context, query string) (*AIOverviewResult, error) {
+	rule, grounded := GroundingRuleFor(query, gs.grounding)
+	if cached, ok := gs.answers.Get(query, groundingInstruction(rule, grounded), time.Now()); ok {
+		fmt.Printf("♻️  Reusing Gemini answer from %s: %s\n", cached.GeneratedAt.Format("2006-01-02 15:04"), query)
+		return cached, nil
+	}
 	fmt.Printf("🤖 Searching with Gemini AI: %s\n", query)
 
-	prompt := fmt.Sprintf(`You are a research assistant analyzing user complaints about cryptocurrency platforms.
+	result, err := gs.client.Models.GenerateContent(
+		ctx,
+		geminiModel,
+		genai.Text(searchPrompt(query, groundingInstruction(rule, grounded))),
+		searchConfig(),
+	)
+	if err != nil {
+		var apiErr genai.APIError
+		if errors.As(err, &apiErr) {
+			return nil, health.StatusError("Gemini API", apiErr.Code, apiErr.Status+": "+apiErr.Message)
+		}
+		return nil, fmt.Errorf("Gemini API error: %w", err)
+	}
+	gs.recordUsage(result)
+
+	// Extract text from response using the new SDK's Text() method
+	aiResult, err := parseSearchResponse(query, result.Text())
+	if err != nil {
+		return nil, err
+	}
+	applyGrounding(aiResult, result, rule, grounded)
+	fmt.Printf("✅ Gemini found %d key complaints from %d sources\n",
+		len(aiResult.KeyComplaints), len(aiResult.Sources))
+	gs.CacheAnswers([]AIOverviewResult{*aiResult})
+
+	return aiResult, nil
+}
+
+// geminiModel is used for search and classification.
+// gemini-2.0-flash is recommended for speed.
+const geminiModel = "gemini-2.0-flash"
+
+// searchPrompt asks Gemini to research query and answer in the AIOverviewResult shape.
+// grounding is the query's groundingInstruction.
+func searchPrompt(query, grounding string) string {
+	return fmt.Sprintf(`You are a research assistant analyzing user complaints about cryptocurrency platforms.
 
 Search the web for: "%s"
 
@@ -97,7 +192,7 @@ Analyze the search results and provide a comprehensive analysis in the following
 	"summary": "A 2-3 sentence summary of the main complaints found",
 	"key_complaints": [
 		{
-			"category": "category name (fees, customer_support, security, account_issues, withdrawal_problems, verification, app_bugs, other)",
+			"category": "one of: %s",
 			"description": "Brief description of the complaint",
 			"frequency": "common/occasional/rare",
 			"platform": "where this was found (reddit, twitter, trustpilot, bbb, etc.)"
@@ -122,35 +217,24 @@ Focus on:
 2. Common recurring issues
 3. Reddit discussions, review sites, social media, and forums
 4. Be objective and factual
+%s
+Return ONLY valid JSON, no markdown code blocks or explanation.`, query, query, categoryIDs(), grounding)
+}
 
-Return ONLY valid JSON, no markdown code blocks or explanation.`, query, query)
-
-	// Use the new SDK API with Google Search tool for grounding
-	// Model: gemini-2.0-flash is recommended for speed
-	modelName := "gemini-2.0-flash"
-
-	// Create config with Google Search tool enabled
-	// NOTE: Cannot use ResponseMIMEType with Google Search tool
-	config := &genai.GenerateContentConfig{
+// searchConfig enables the Google Search tool for grounding.
+// NOTE: Cannot use ResponseMIMEType with Google Search tool
+func searchConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
 		Tools: []*genai.Tool{
 			{GoogleSearch: &genai.GoogleSearch{}},
 		},
 	}
+}
 
-	result, err := gs.client.Models.GenerateContent(
-		ctx,
-		modelName,
-		genai.Text(prompt),
-		config,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("Gemini API error: %w", err)
-	}
-
-	// Extract text from response using the new SDK's Text() method
-	responseText := result.Text()
+// parseSearchResponse turns Gemini's answer for query into an AIOverviewResult
+func parseSearchResponse(query, responseText string) (*AIOverviewResult, error) {
 	if responseText == "" {
-		return nil, fmt.Errorf("no response from Gemini")
+		return nil, health.NewError(health.ErrorParse, fmt.Errorf("no response from Gemini"))
 	}
 
 	// Clean up the response - remove markdown code blocks if present
@@ -169,80 +253,112 @@ Return ONLY valid JSON, no markdown code blocks or explanation.`, query, query)
 	}
 
 	aiResult.GeneratedAt = time.Now()
-	fmt.Printf("✅ Gemini found %d key complaints from %d sources\n",
-		len(aiResult.KeyComplaints), len(aiResult.Sources))
-
 	return &aiResult, nil
 }
 
-// SearchMultipleQueries searches for multiple queries and aggregates results
+// geminiMaxRetries is how many times a rate-limited query is asked again
+const geminiMaxRetries = 2
+
+// geminiRetry waits out Gemini rate limiting: 30s, then 60s
+var geminiRetry = retry.Policy{
+	MaxRetries: geminiMaxRetries,
+	BaseDelay:  30 * time.Second,
+	OnRetry: func(attempt int, err error, delay time.Duration) {
+		fmt.Printf("Rate limited, waiting %v before retry %d/%d...\n", delay, attempt+1, geminiMaxRetries)
+	},
+}
+
+// isQuotaError reports whether err is rate limiting, the only failure worth retrying
+func isQuotaError(err error) bool {
+	return health.Classify(err) == health.ErrorQuota
+}
+
+// SearchMultipleQueries searches for multiple queries and aggregates results.
+// Queries left when ctx is cancelled, or that would need Gemini once the budget is
+// spent, are skipped and recorded as run errors; the answers so far are returned.
 func (gs *GeminiScraper) SearchMultipleQueries(ctx context.Context, queries []string) ([]AIOverviewResult, error) {
 	results := []AIOverviewResult{}
+	runID := models.NewRunID(time.Now())
+	gs.runErrors = nil
+	gs.resetUsage()
 
-	for i, query := range queries {
-		// Retry logic for rate limiting
-		var result *AIOverviewResult
-		var err error
-		maxRetries := 3
+	fail := func(e models.RunError) {
+		gs.runErrors = append(gs.runErrors, e)
+		gs.progress.reportError(e)
+	}
+	done := func(i int, answer *AIOverviewResult) {
+		p := models.ScrapeProgress{Type: models.ProgressQuery, Source: "gemini", Query: queries[i], QueryIndex: i + 1, Queries: len(queries)}
+		if answer != nil {
+			p.Items = len(answer.KeyComplaints)
+		}
+		gs.progress.report(p)
+	}
 
-		for retry := 0; retry < maxRetries; retry++ {
-			result, err = gs.SearchComplaintsWithAI(ctx, query)
-			if err == nil {
-				break
+	for i, query := range queries {
+		if err := ctx.Err(); err != nil {
+			fmt.Printf("⏹️  Gemini search cancelled, skipping %d remaining queries\n", len(queries)-i)
+			for _, skipped := range queries[i:] {
+				fail(health.RunError("gemini", skipped, err))
 			}
+			break
+		}
 
-			// Check if it's a rate limit error
-			if strings.Contains(err.Error(), "429") || strings.Contains(err.Error(), "RESOURCE_EXHAUSTED") {
-				waitTime := time.Duration((retry+1)*30) * time.Second
-				fmt.Printf("Rate limited, waiting %v before retry %d/%d...\n", waitTime, retry+1, maxRetries)
-				time.Sleep(waitTime)
-			} else {
-				break // Non-rate-limit error, don't retry
-			}
+		// Fresh answers don't spend rate limit budget
+		if cached, ok := gs.answers.Get(query, gs.groundingFor(query), time.Now()); ok {
+			fmt.Printf("♻️  Reusing Gemini answer from %s: %s\n", cached.GeneratedAt.Format("2006-01-02 15:04"), query)
+			cached = gs.expandThin(ctx, cached)
+			cached.RunID = runID
+			results = append(results, *cached)
+			done(i, cached)
+			continue
 		}
 
+		if err := gs.overBudget(); err != nil {
+			fmt.Printf("💸 Skipping '%s': %v\n", query, err)
+			fail(health.RunError("gemini", query, err))
+			done(i, nil)
+			continue
+		}
+
+		// Only rate limiting is retried; other errors won't go away by waiting
+		var result *AIOverviewResult
+		err := retry.Do(ctx, geminiRetry, isQuotaError, func(int) error {
+			if err := gs.limiter.Wait(ctx); err != nil {
+				return retry.Permanent(err)
+			}
+			var err error
+			result, err = gs.SearchComplaintsWithAI(ctx, query)
+			return err
+		})
+
 		if err != nil {
 			fmt.Printf("⚠️  Error searching '%s': %v\n", query, err)
+			fail(health.RunError("gemini", query, err))
+			done(i, nil)
 			continue
 		}
+		result = gs.expandThin(ctx, result)
+		result.RunID = runID
 		results = append(results, *result)
-
-		// Rate limiting between queries (10 seconds to avoid 429 errors)
-		if i < len(queries)-1 {
-			fmt.Println("⏳ Waiting 10 seconds before next query...")
-			time.Sleep(10 * time.Second)
-		}
+		done(i, result)
 	}
 
 	return results, nil
 }
 
-// ConvertToComplaints converts AIOverviewResults to standard Complaint models
-func ConvertToComplaints(aiResults []AIOverviewResult) []models.Complaint {
-	complaints := []models.Complaint{}
-
-	for _, result := range aiResults {
-		for i, kc := range result.KeyComplaints {
-			complaint := models.Complaint{
-				ID:          fmt.Sprintf("gemini-%s-%d", result.GeneratedAt.Format("20060102150405"), i),
-				Source:      fmt.Sprintf("gemini_search:%s", kc.Platform),
-				Title:       fmt.Sprintf("[%s] %s", kc.Category, truncateString(kc.Description, 50)),
-				Description: kc.Description,
-				Category:    kc.Category,
-				Sentiment:   "negative", // Complaints are inherently negative
-				ScrapedAt:   result.GeneratedAt,
-			}
-
-			// Add URL if available from sources
-			if len(result.Sources) > 0 {
-				complaint.URL = result.Sources[0].URL
-			}
+// RunErrors returns the failures SearchMultipleQueries skipped past in its last run
+func (gs *GeminiScraper) RunErrors() []models.RunError {
+	return gs.runErrors
+}
 
-			complaints = append(complaints, complaint)
-		}
+// categoryIDs lists the taxonomy IDs Gemini should classify complaints into
+func categoryIDs() string {
+	categories := models.Categories()
+	ids := make([]string, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
 	}
-
-	return complaints
+	return strings.Join(ids, ", ")
 }
 
 // GetDefaultComplaintQueries returns default queries for Coinbase complaints
@@ -259,14 +375,6 @@ func GetDefaultComplaintQueries() []string {
 	}
 }
 
-// truncateString truncates a string to maxLen characters
-func truncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen]
-}
-
 // cleanJSONResponse removes markdown code blocks from Gemini response
 func cleanJSONResponse(response string) string {
 	// Remove ```json and ``` markers if present
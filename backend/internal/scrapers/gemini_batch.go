@@ -0,0 +1,240 @@
+package scrapers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+	"google.golang.org/genai"
+)
+
+// ============================================
+// GEMINI BATCH MODE
+// ============================================
+// Large jobs go through the Gemini Batch API instead of one call every 10 seconds.
+// Batches run asynchronously (usually within hours, at half the price), so the
+// submitted job is saved to disk and a later run can pick up the results.
+
+// BatchJobFile is the pending batch job file inside the data directory
+const BatchJobFile = "gemini_batch_job.json"
+
+// ClassifyBatchSize is how many comments are classified per batched request
+const ClassifyBatchSize = 50
+
+// DefaultBatchPollInterval is how often WaitBatch checks on a running job
+const DefaultBatchPollInterval = time.Minute
+
+// Batch job kinds
+const (
+	BatchKindSearch   = "search"
+	BatchKindClassify = "classify"
+)
+
+// BatchJob is a submitted Gemini batch and the inputs needed to merge its results
+type BatchJob struct {
+	Name        string    `json:"name"` // Gemini resource name, e.g. "batches/123"
+	Kind        string    `json:"kind"` // "search" or "classify"
+	RunID       string    `json:"run_id"`
+	Inputs      []string  `json:"inputs"` // Queries, or comment texts for classification
+	SubmittedAt time.Time `json:"submitted_at"`
+
+	// Grounding rules the search queries were submitted with, so the answers are
+	// checked against the same domains whenever the job is collected
+	Grounding []config.GroundingRule `json:"grounding,omitempty"`
+}
+
+// SubmitSearchBatch submits one grounded search request per query as a single batch job
+func (gs *GeminiScraper) SubmitSearchBatch(ctx context.Context, queries []string) (*BatchJob, error) {
+	requests := make([]*genai.InlinedRequest, len(queries))
+	for i, query := range queries {
+		requests[i] = &genai.InlinedRequest{
+			Contents: genai.Text(searchPrompt(query, gs.groundingFor(query))),
+			Config:   searchConfig(),
+		}
+	}
+	job, err := gs.submitBatch(ctx, BatchKindSearch, queries, requests)
+	if err != nil {
+		return nil, err
+	}
+	job.Grounding = gs.grounding
+	return job, nil
+}
+
+// SubmitClassifyBatch submits comment texts for category classification,
+// ClassifyBatchSize comments per request
+func (gs *GeminiScraper) SubmitClassifyBatch(ctx context.Context, texts []string) (*BatchJob, error) {
+	var requests []*genai.InlinedRequest
+	for start := 0; start < len(texts); start += ClassifyBatchSize {
+		end := min(start+ClassifyBatchSize, len(texts))
+		input, err := json.Marshal(texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode comments: %w", err)
+		}
+		requests = append(requests, &genai.InlinedRequest{
+			Contents: genai.Text(classifyPrompt(string(input))),
+			Config:   &genai.GenerateContentConfig{ResponseMIMEType: "application/json"},
+		})
+	}
+	return gs.submitBatch(ctx, BatchKindClassify, texts, requests)
+}
+
+func (gs *GeminiScraper) submitBatch(ctx context.Context, kind string, inputs []string, requests []*genai.InlinedRequest) (*BatchJob, error) {
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("nothing to submit")
+	}
+	if err := gs.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	runID := models.NewRunID(time.Now())
+	job, err := gs.client.Batches.Create(ctx, geminiModel, &genai.BatchJobSource{InlinedRequests: requests},
+		&genai.CreateBatchJobConfig{DisplayName: "coinsights-" + kind + "-" + runID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini batch: %w", err)
+	}
+
+	fmt.Printf("📦 Submitted Gemini %s batch %s (%d requests)\n", kind, job.Name, len(requests))
+	return &BatchJob{
+		Name:        job.Name,
+		Kind:        kind,
+		RunID:       runID,
+		Inputs:      inputs,
+		SubmittedAt: time.Now(),
+	}, nil
+}
+
+// WaitBatch polls until the job finishes and returns its responses, one per request in order.
+// Responses that failed individually are nil.
+func (gs *GeminiScraper) WaitBatch(ctx context.Context, job *BatchJob, poll time.Duration) ([]*genai.GenerateContentResponse, error) {
+	for {
+		remote, err := gs.client.Batches.Get(ctx, job.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll Gemini batch: %w", err)
+		}
+
+		switch remote.State {
+		case genai.JobStateSucceeded, genai.JobStatePartiallySucceeded:
+			if remote.Dest == nil {
+				return nil, fmt.Errorf("batch %s finished without inline responses", job.Name)
+			}
+			responses := make([]*genai.GenerateContentResponse, len(remote.Dest.InlinedResponses))
+			for i, r := range remote.Dest.InlinedResponses {
+				if r.Error != nil {
+					fmt.Printf("⚠️  Batch request %d failed: %s\n", i, r.Error.Message)
+					continue
+				}
+				responses[i] = r.Response
+			}
+			return responses, nil
+		case genai.JobStateFailed, genai.JobStateCancelled, genai.JobStateExpired:
+			msg := string(remote.State)
+			if remote.Error != nil {
+				msg += ": " + remote.Error.Message
+			}
+			return nil, fmt.Errorf("batch %s did not complete: %s", job.Name, msg)
+		}
+
+		fmt.Printf("⏳ Batch %s is %s, checking again in %v...\n", job.Name, remote.State, poll)
+		timer := time.NewTimer(poll)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// SearchBatchResults merges a search batch's responses into one result per answered query
+func SearchBatchResults(job *BatchJob, responses []*genai.GenerateContentResponse) []AIOverviewResult {
+	results := []AIOverviewResult{}
+	for i, resp := range responses {
+		if resp == nil || i >= len(job.Inputs) {
+			continue
+		}
+		result, err := parseSearchResponse(job.Inputs[i], resp.Text())
+		if err != nil {
+			fmt.Printf("⚠️  Error in batch answer for '%s': %v\n", job.Inputs[i], err)
+			continue
+		}
+		rule, grounded := GroundingRuleFor(job.Inputs[i], job.Grounding)
+		applyGrounding(result, resp, rule, grounded)
+		result.RunID = job.RunID
+		results = append(results, *result)
+	}
+	return results
+}
+
+// ClassifyBatchResults maps each classified comment text to its category ID.
+// Comments whose request failed are left out.
+func ClassifyBatchResults(job *BatchJob, responses []*genai.GenerateContentResponse) map[string]string {
+	categories := make(map[string]string, len(job.Inputs))
+	for i, resp := range responses {
+		start := i * ClassifyBatchSize
+		if resp == nil || start >= len(job.Inputs) {
+			continue
+		}
+		texts := job.Inputs[start:min(start+ClassifyBatchSize, len(job.Inputs))]
+
+		var ids []string
+		if err := json.Unmarshal([]byte(cleanJSONResponse(resp.Text())), &ids); err != nil || len(ids) != len(texts) {
+			fmt.Printf("⚠️  Skipping malformed classification for comments %d-%d\n", start, start+len(texts)-1)
+			continue
+		}
+		for j, text := range texts {
+			categories[text] = models.NormalizeCategory(ids[j])
+		}
+	}
+	return categories
+}
+
+// classifyPrompt asks for one category ID per comment in a JSON array of comments
+func classifyPrompt(comments string) string {
+	return `Classify each of the following user comments about a cryptocurrency exchange into exactly one category.
+Allowed category IDs: ` + categoryIDs() + `
+Use "other" if no category fits.
+Return a JSON array of category IDs with exactly one entry per comment, in the same order.
+
+Comments:
+` + comments
+}
+
+// ============================================
+// PENDING JOB FILE
+// ============================================
+
+// SaveBatchJob records a submitted job so a later run can collect its results
+func SaveBatchJob(path string, job *BatchJob) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch job: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write batch job: %w", err)
+	}
+	return nil
+}
+
+// LoadBatchJob reads a pending job. Returns nil (and no error) if there is none.
+func LoadBatchJob(path string) (*BatchJob, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch job: %w", err)
+	}
+	var job BatchJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to parse batch job: %w", err)
+	}
+	if strings.TrimSpace(job.Name) == "" {
+		return nil, fmt.Errorf("batch job file %s has no job name", path)
+	}
+	return &job, nil
+}
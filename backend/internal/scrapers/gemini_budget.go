@@ -0,0 +1,113 @@
+package scrapers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"google.golang.org/genai"
+)
+
+// ============================================
+// SPEND BUDGET
+// ============================================
+// A prompt change that makes answers balloon, or a thin-answer loop that keeps
+// following up, shouldn't show up as a surprise bill. Every Gemini response's
+// token counts are added to the run's usage, and once the run's budget is spent
+// the remaining queries are skipped and recorded as run errors; the answers
+// gathered so far are returned as usual.
+
+// ErrBudgetExceeded marks queries skipped because the run's Gemini budget is spent
+var ErrBudgetExceeded = errors.New("Gemini run budget exceeded")
+
+// GeminiUsage is what the current run has spent on Gemini
+type GeminiUsage struct {
+	Requests       int     `json:"requests"`
+	PromptTokens   int     `json:"prompt_tokens"`   // Including grounding tool prompts
+	ResponseTokens int     `json:"response_tokens"` // Including thinking tokens
+	CostUSD        float64 `json:"cost_usd"`        // Estimated from the budget's prices
+}
+
+// Tokens is prompt plus response tokens
+func (u GeminiUsage) Tokens() int {
+	return u.PromptTokens + u.ResponseTokens
+}
+
+// SetBudget caps what SearchMultipleQueries (including thin-answer follow-ups) may
+// spend per run. The cap is checked before each request, so a run overshoots by at
+// most one response. Batch jobs are billed at batch rates and aren't capped.
+func (gs *GeminiScraper) SetBudget(budget config.GeminiBudget) {
+	gs.budget = &budget
+}
+
+// GeminiBudgetFromEnv applies the GEMINI_MAX_TOKENS and GEMINI_MAX_COST_USD overrides
+// to base; 0 lifts that cap
+func GeminiBudgetFromEnv(base config.GeminiBudget) (config.GeminiBudget, error) {
+	if v := os.Getenv("GEMINI_MAX_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return base, fmt.Errorf("invalid GEMINI_MAX_TOKENS %q: must be a non-negative integer", v)
+		}
+		base.MaxTokens = n
+	}
+	if v := os.Getenv("GEMINI_MAX_COST_USD"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return base, fmt.Errorf("invalid GEMINI_MAX_COST_USD %q: must be a non-negative number", v)
+		}
+		base.MaxCostUSD = f
+	}
+	return base, nil
+}
+
+// Usage returns what the current run has spent so far
+func (gs *GeminiScraper) Usage() GeminiUsage {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+	return gs.usage
+}
+
+// resetUsage starts a new run's usage
+func (gs *GeminiScraper) resetUsage() {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+	gs.usage = GeminiUsage{}
+}
+
+// recordUsage adds one response to the run's usage
+func (gs *GeminiScraper) recordUsage(resp *genai.GenerateContentResponse) {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+
+	gs.usage.Requests++
+	if meta := resp.UsageMetadata; meta != nil {
+		gs.usage.PromptTokens += int(meta.PromptTokenCount + meta.ToolUsePromptTokenCount)
+		gs.usage.ResponseTokens += int(meta.CandidatesTokenCount + meta.ThoughtsTokenCount)
+	}
+	pricing := config.DefaultGeminiBudget()
+	if gs.budget != nil {
+		pricing = *gs.budget
+	}
+	gs.usage.CostUSD = pricing.Cost(gs.usage.Requests, gs.usage.PromptTokens, gs.usage.ResponseTokens)
+}
+
+// overBudget returns ErrBudgetExceeded, tagged as a quota error, once the run has
+// reached either cap; nil while there is budget left or no budget is set
+func (gs *GeminiScraper) overBudget() error {
+	if gs.budget == nil {
+		return nil
+	}
+	usage := gs.Usage()
+	switch {
+	case gs.budget.MaxTokens > 0 && usage.Tokens() >= gs.budget.MaxTokens:
+		return health.NewError(health.ErrorQuota,
+			fmt.Errorf("%w: %d of %d tokens used", ErrBudgetExceeded, usage.Tokens(), gs.budget.MaxTokens))
+	case gs.budget.MaxCostUSD > 0 && usage.CostUSD >= gs.budget.MaxCostUSD:
+		return health.NewError(health.ErrorQuota,
+			fmt.Errorf("%w: $%.2f of $%.2f spent", ErrBudgetExceeded, usage.CostUSD, gs.budget.MaxCostUSD))
+	}
+	return nil
+}
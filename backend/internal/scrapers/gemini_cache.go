@@ -0,0 +1,135 @@
+package scrapers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================
+// ANSWER CACHE
+// ============================================
+// Grounded Gemini answers don't change much within a day, so repeated pipeline
+// runs and API-triggered refreshes reuse a fresh answer instead of re-spending
+// tokens on the identical question.
+
+const (
+	// AnswerCacheFile holds cached Gemini answers inside the data directory
+	AnswerCacheFile = "gemini_answer_cache.json"
+	// DefaultAnswerTTL is how long an answer stays fresh
+	DefaultAnswerTTL = 24 * time.Hour
+)
+
+// AnswerCache stores AIOverviewResults by query, persisted to a JSON file.
+// A nil *AnswerCache is valid and never hits.
+type AnswerCache struct {
+	path    string
+	ttl     time.Duration
+	entries map[string]AIOverviewResult // answerKey -> answer
+	mu      sync.Mutex
+}
+
+// NewAnswerCache loads cached answers from dataDir (empty if the file doesn't exist yet)
+func NewAnswerCache(dataDir string, ttl time.Duration) (*AnswerCache, error) {
+	c := &AnswerCache{
+		path:    filepath.Join(dataDir, AnswerCacheFile),
+		ttl:     ttl,
+		entries: make(map[string]AIOverviewResult),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answer cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse answer cache: %w", err)
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]AIOverviewResult)
+	}
+	return c, nil
+}
+
+// NewAnswerCacheFromEnv creates a cache with the GEMINI_CACHE_TTL freshness window
+// (e.g. "24h", the default). "0" disables caching and returns nil.
+func NewAnswerCacheFromEnv(dataDir string) (*AnswerCache, error) {
+	ttl := DefaultAnswerTTL
+	if v := os.Getenv("GEMINI_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid GEMINI_CACHE_TTL %q: must be a non-negative duration", v)
+		}
+		ttl = d
+	}
+	if ttl == 0 {
+		return nil, nil
+	}
+	return NewAnswerCache(dataDir, ttl)
+}
+
+// Get returns the cached answer for query, asked with the grounding instruction, if
+// it was generated within the freshness window
+func (c *AnswerCache) Get(query, grounding string, now time.Time) (*AIOverviewResult, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.entries[answerKey(query, grounding)]
+	if !ok || now.Sub(result.GeneratedAt) >= c.ttl {
+		return nil, false
+	}
+	result.Cached = true
+	return &result, true
+}
+
+// Put stores a fresh answer asked with the grounding instruction, dropping expired
+// ones, and writes the cache to disk
+func (c *AnswerCache) Put(result AIOverviewResult, grounding string) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.Sub(entry.GeneratedAt) >= c.ttl {
+			delete(c.entries, key)
+		}
+	}
+	result.Cached = false
+	c.entries[answerKey(result.Query, grounding)] = result
+	return c.save()
+}
+
+// save writes the cache to disk. Must be called with c.mu held.
+func (c *AnswerCache) save() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal answer cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write answer cache: %w", err)
+	}
+	return nil
+}
+
+// answerKey identifies an answer by model and the exact prompt sent, so changing
+// either (e.g. adding a category or a grounding domain) invalidates old answers.
+// Queries are compared case- and whitespace-insensitively.
+func answerKey(query, grounding string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	sum := sha256.Sum256([]byte(geminiModel + "\x00" + searchPrompt(normalized, grounding)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,179 @@
+package scrapers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+)
+
+// ============================================
+// THIN ANSWER EXPANSION
+// ============================================
+// One grounded answer with a single complaint and one source shouldn't be the
+// only evidence for a category. When an answer is thin, the scraper asks again
+// with refined queries, restricted to one complaint platform and recent pages,
+// and merges what comes back into the original answer.
+
+// SetProgress has SearchMultipleQueries report each query to progress as it finishes
+func (gs *GeminiScraper) SetProgress(progress ProgressFunc) {
+	gs.progress = progress
+}
+
+// EnableExpansion issues follow-up queries for answers below the settings' thresholds.
+// It applies to SearchMultipleQueries; batch jobs return their answers as they are.
+func (gs *GeminiScraper) EnableExpansion(settings config.ExpansionSettings) {
+	gs.expand = &settings
+}
+
+// IsThin reports whether result has too few complaints or sources to stand on its own
+func IsThin(result *AIOverviewResult, settings config.ExpansionSettings) bool {
+	if settings.MinComplaints <= 0 {
+		return false
+	}
+	return len(result.KeyComplaints) < settings.MinComplaints || len(result.Sources) < settings.MinSources
+}
+
+// FollowUpQueries refines query for a thin result: one query per platform the result
+// has nothing from, restricted to that platform's domain and to pages newer than
+// settings.WindowDays. Platforms are tried in name order for stable queries.
+func FollowUpQueries(query string, result *AIOverviewResult, settings config.ExpansionSettings, now time.Time) []string {
+	covered := make(map[string]bool)
+	for _, c := range result.KeyComplaints {
+		covered[strings.ToLower(c.Platform)] = true
+	}
+	for _, s := range result.Sources {
+		for platform, domain := range settings.Platforms {
+			if strings.HasSuffix(strings.ToLower(s.Domain), domain) {
+				covered[platform] = true
+			}
+		}
+	}
+
+	platforms := make([]string, 0, len(settings.Platforms))
+	for platform := range settings.Platforms {
+		if !covered[platform] {
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+
+	var since string
+	if settings.WindowDays > 0 {
+		since = " after:" + now.AddDate(0, 0, -settings.WindowDays).Format("2006-01-02")
+	}
+	var queries []string
+	for _, platform := range platforms {
+		if len(queries) == settings.MaxFollowUps {
+			break
+		}
+		queries = append(queries, fmt.Sprintf("%s site:%s%s", query, settings.Platforms[platform], since))
+	}
+	return queries
+}
+
+// MergeAnswers adds follow-up answers' complaints and sources to base, skipping
+// complaints with the same wording and category and sources (and grounding pages)
+// with the same URL.
+// Sentiment is averaged over every answer that reported one.
+func MergeAnswers(base AIOverviewResult, followUps []AIOverviewResult) AIOverviewResult {
+	seenComplaints := make(map[string]bool)
+	for _, c := range base.KeyComplaints {
+		seenComplaints[c.Category+":"+ContentHash(c.Description)] = true
+	}
+	seenSources := make(map[string]bool)
+	for _, s := range base.Sources {
+		seenSources[s.URL] = true
+	}
+	seenGrounding := make(map[string]bool)
+	for _, s := range base.Grounding {
+		seenGrounding[s.URL] = true
+	}
+
+	base.KeyComplaints = append([]ExtractedComplaint(nil), base.KeyComplaints...)
+	base.Sources = append([]SourceReference(nil), base.Sources...)
+	base.Grounding = append([]SourceReference(nil), base.Grounding...)
+	sentiments := []SentimentStats{}
+	if base.SentimentBreakdown != (SentimentStats{}) {
+		sentiments = append(sentiments, base.SentimentBreakdown)
+	}
+
+	for _, answer := range followUps {
+		base.FollowUps = append(base.FollowUps, answer.Query)
+		for _, c := range answer.KeyComplaints {
+			key := c.Category + ":" + ContentHash(c.Description)
+			if !seenComplaints[key] {
+				seenComplaints[key] = true
+				base.KeyComplaints = append(base.KeyComplaints, c)
+			}
+		}
+		for _, s := range answer.Sources {
+			if !seenSources[s.URL] {
+				seenSources[s.URL] = true
+				base.Sources = append(base.Sources, s)
+			}
+		}
+		for _, s := range answer.Grounding {
+			if !seenGrounding[s.URL] {
+				seenGrounding[s.URL] = true
+				base.Grounding = append(base.Grounding, s)
+			}
+		}
+		if answer.SentimentBreakdown != (SentimentStats{}) {
+			sentiments = append(sentiments, answer.SentimentBreakdown)
+		}
+	}
+
+	if len(sentiments) > 0 {
+		var sum SentimentStats
+		for _, s := range sentiments {
+			sum.Negative += s.Negative
+			sum.Neutral += s.Neutral
+			sum.Positive += s.Positive
+		}
+		n := float64(len(sentiments))
+		base.SentimentBreakdown = SentimentStats{Negative: sum.Negative / n, Neutral: sum.Neutral / n, Positive: sum.Positive / n}
+	}
+	return base
+}
+
+// expandThin runs follow-up queries for a thin result and merges their answers.
+// Failed follow-ups are recorded as run errors and otherwise ignored.
+func (gs *GeminiScraper) expandThin(ctx context.Context, result *AIOverviewResult) *AIOverviewResult {
+	if gs.expand == nil || !IsThin(result, *gs.expand) || len(result.FollowUps) > 0 {
+		return result
+	}
+	queries := FollowUpQueries(result.Query, result, *gs.expand, time.Now())
+	if len(queries) == 0 {
+		return result
+	}
+	fmt.Printf("🔎 Thin answer (%d complaints, %d sources), following up: %s\n",
+		len(result.KeyComplaints), len(result.Sources), result.Query)
+
+	var answers []AIOverviewResult
+	for _, query := range queries {
+		if err := gs.overBudget(); err != nil {
+			gs.runErrors = append(gs.runErrors, health.RunError("gemini", query, err))
+			break
+		}
+		if err := gs.limiter.Wait(ctx); err != nil {
+			gs.runErrors = append(gs.runErrors, health.RunError("gemini", query, err))
+			break
+		}
+		answer, err := gs.SearchComplaintsWithAI(ctx, query)
+		if err != nil {
+			fmt.Printf("⚠️  Error in follow-up '%s': %v\n", query, err)
+			gs.runErrors = append(gs.runErrors, health.RunError("gemini", query, err))
+			continue
+		}
+		answer.Query = query
+		answers = append(answers, *answer)
+	}
+
+	merged := MergeAnswers(*result, answers)
+	return &merged
+}
@@ -0,0 +1,222 @@
+package scrapers
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"google.golang.org/genai"
+)
+
+// ============================================
+// GROUNDING DOMAINS
+// ============================================
+// The Gemini API's search tool can't be limited to chosen sites (its ExcludeDomains
+// option is Vertex-only), so a query's grounding rule is applied twice: the prompt
+// names the sites to search, with site: operators when the rule restricts, and the
+// response's grounding metadata - the pages Google Search actually returned - is
+// checked afterwards. Each complaint records the domains of the pages grounding
+// the sentence it came from. Under a restricting rule, sources and complaints
+// grounded only elsewhere are dropped.
+
+// minSupportText is the shortest grounded segment matched against a complaint;
+// shorter ones ("{", a lone category) would match every complaint
+const minSupportText = 12
+
+// SetGrounding grounds each query on the domains of the first rule it matches
+func (gs *GeminiScraper) SetGrounding(rules []config.GroundingRule) {
+	gs.grounding = rules
+}
+
+// GroundingRulesFromEnv applies GEMINI_GROUNDING_DOMAINS to base, e.g.
+// "forum=reddit.com,reviews=trustpilot.com+bbb.org". Each entry replaces the
+// domains of the rule with that query type; an empty list ("reviews=") turns it off.
+func GroundingRulesFromEnv(base []config.GroundingRule) ([]config.GroundingRule, error) {
+	v := os.Getenv("GEMINI_GROUNDING_DOMAINS")
+	if strings.TrimSpace(v) == "" {
+		return base, nil
+	}
+
+	rules := slices.Clone(base)
+	for _, entry := range strings.Split(v, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		queryType, domains, ok := strings.Cut(entry, "=")
+		i := slices.IndexFunc(rules, func(r config.GroundingRule) bool { return r.QueryType == strings.TrimSpace(queryType) })
+		if !ok || i < 0 {
+			types := make([]string, len(base))
+			for j, rule := range base {
+				types[j] = rule.QueryType
+			}
+			return base, fmt.Errorf("invalid GEMINI_GROUNDING_DOMAINS entry %q: want type=domain+domain, with a type of %s", entry, strings.Join(types, ", "))
+		}
+		rules[i].Domains = nil
+		for _, domain := range strings.Split(domains, "+") {
+			if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+				rules[i].Domains = append(rules[i].Domains, domain)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// GroundingRuleFor returns the first rule matching query. Rules without domains are
+// skipped, and so are queries carrying their own site: operator (thin-answer
+// follow-ups), whose site has already been picked.
+func GroundingRuleFor(query string, rules []config.GroundingRule) (config.GroundingRule, bool) {
+	lower := strings.ToLower(query)
+	if strings.Contains(lower, "site:") {
+		return config.GroundingRule{}, false
+	}
+	for _, rule := range rules {
+		if len(rule.Domains) == 0 {
+			continue
+		}
+		for _, match := range rule.Match {
+			if strings.Contains(lower, match) {
+				return rule, true
+			}
+		}
+	}
+	return config.GroundingRule{}, false
+}
+
+// groundingFor returns the grounding instruction query is asked with
+func (gs *GeminiScraper) groundingFor(query string) string {
+	return groundingInstruction(GroundingRuleFor(query, gs.grounding))
+}
+
+// groundingInstruction is the search prompt's instruction for a query's rule
+func groundingInstruction(rule config.GroundingRule, ok bool) string {
+	if !ok {
+		return ""
+	}
+	domains := strings.Join(rule.Domains, ", ")
+	if !rule.Restrict {
+		return fmt.Sprintf("\nPrefer pages from %s wherever they have relevant results.\n", domains)
+	}
+	sites := make([]string, len(rule.Domains))
+	for i, domain := range rule.Domains {
+		sites[i] = "site:" + domain
+	}
+	return fmt.Sprintf("\nOnly use pages from %s: search with %s, and leave out anything found elsewhere.\n",
+		domains, strings.Join(sites, " OR "))
+}
+
+// applyGrounding records what grounded result: the rule the query matched, the pages
+// in resp's grounding metadata and each complaint's grounding domains. Under a
+// restricting rule, sources, pages and complaints from other domains are dropped.
+func applyGrounding(result *AIOverviewResult, resp *genai.GenerateContentResponse, rule config.GroundingRule, ok bool) {
+	if ok {
+		result.QueryType = rule.QueryType
+		result.GroundingDomains = rule.Domains
+	}
+
+	var meta *genai.GroundingMetadata
+	if resp != nil && len(resp.Candidates) > 0 && resp.Candidates[0] != nil {
+		meta = resp.Candidates[0].GroundingMetadata
+	}
+	if meta != nil {
+		chunkDomains := make([]string, len(meta.GroundingChunks)) // By chunk index, for the supports
+		seen := make(map[string]bool)
+		for i, chunk := range meta.GroundingChunks {
+			if chunk == nil || chunk.Web == nil {
+				continue
+			}
+			chunkDomains[i] = chunkDomain(chunk.Web)
+			if !seen[chunk.Web.URI] {
+				seen[chunk.Web.URI] = true
+				result.Grounding = append(result.Grounding, SourceReference{Title: chunk.Web.Title, URL: chunk.Web.URI, Domain: chunkDomains[i]})
+			}
+		}
+		for i := range result.KeyComplaints {
+			result.KeyComplaints[i].GroundedBy = supportingDomains(result.KeyComplaints[i].Description, meta.GroundingSupports, chunkDomains)
+		}
+	}
+
+	if !ok || !rule.Restrict {
+		return
+	}
+	allowed := func(domain string) bool { return onDomains(domain, rule.Domains) }
+	result.Sources = slices.DeleteFunc(result.Sources, func(s SourceReference) bool { return !allowed(sourceDomain(s)) })
+	result.Grounding = slices.DeleteFunc(result.Grounding, func(s SourceReference) bool { return !allowed(s.Domain) })
+
+	before := len(result.KeyComplaints)
+	result.KeyComplaints = slices.DeleteFunc(result.KeyComplaints, func(c ExtractedComplaint) bool {
+		if len(c.GroundedBy) > 0 {
+			return !slices.ContainsFunc(c.GroundedBy, allowed)
+		}
+		// Ungrounded: trust the platform Gemini named, if it is one of the domains
+		platform := strings.ToLower(strings.TrimSpace(c.Platform))
+		return platform == "" || !slices.ContainsFunc(rule.Domains, func(domain string) bool {
+			return domain == platform || strings.HasPrefix(domain, platform+".")
+		})
+	})
+	if dropped := before - len(result.KeyComplaints); dropped > 0 {
+		fmt.Printf("🧭 Dropped %d complaints grounded outside %s\n", dropped, strings.Join(rule.Domains, ", "))
+	}
+}
+
+// supportingDomains returns the domains of the chunks grounding the parts of the
+// answer that state description, sorted
+func supportingDomains(description string, supports []*genai.GroundingSupport, chunkDomains []string) []string {
+	description = strings.ToLower(strings.TrimSpace(description))
+	if description == "" {
+		return nil
+	}
+	var domains []string
+	for _, support := range supports {
+		if support == nil || support.Segment == nil {
+			continue
+		}
+		segment := strings.ToLower(strings.TrimSpace(support.Segment.Text))
+		if len(segment) < minSupportText || (!strings.Contains(segment, description) && !strings.Contains(description, segment)) {
+			continue
+		}
+		for _, i := range support.GroundingChunkIndices {
+			if int(i) < len(chunkDomains) && chunkDomains[i] != "" {
+				domains = append(domains, chunkDomains[i])
+			}
+		}
+	}
+	slices.Sort(domains)
+	return slices.Compact(domains)
+}
+
+// chunkDomain returns a grounding page's domain. The Gemini API leaves Domain empty
+// and puts the site's domain in Title, as the URI is a Google redirect.
+func chunkDomain(web *genai.GroundingChunkWeb) string {
+	if web.Domain != "" {
+		return strings.ToLower(web.Domain)
+	}
+	if title := strings.ToLower(strings.TrimSpace(web.Title)); strings.Contains(title, ".") && !strings.ContainsAny(title, " /") {
+		return strings.TrimPrefix(title, "www.")
+	}
+	return sourceDomain(SourceReference{URL: web.URI})
+}
+
+// sourceDomain returns a source's domain, from its URL when Gemini didn't give one
+func sourceDomain(s SourceReference) string {
+	domain := s.Domain
+	if domain == "" {
+		if u, err := url.Parse(s.URL); err == nil {
+			domain = u.Hostname()
+		}
+	}
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), "www.")
+}
+
+// onDomains reports whether domain is one of domains or a subdomain of one
+func onDomains(domain string, domains []string) bool {
+	for _, d := range domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package scrapers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// COMPLAINT HISTORY
+// ============================================
+// Gemini reports the same complaints run after run, so AI-extracted complaints are
+// identified by their content rather than the query or run that produced them.
+// The history remembers when each one was first and last seen, so a complaint that
+// keeps coming back is one long-lived record, not a new complaint every run.
+
+// ComplaintHistoryFile holds first/last seen times of Gemini complaints inside the data directory
+const ComplaintHistoryFile = "gemini_complaint_history.json"
+
+// Sighting is when a Gemini complaint has been seen across runs
+type Sighting struct {
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Runs      int       `json:"runs"` // Runs the complaint appeared in
+}
+
+// ComplaintHistory stores Sightings by complaint ID, persisted to a JSON file.
+// A nil *ComplaintHistory is valid and records nothing.
+type ComplaintHistory struct {
+	path      string
+	sightings map[string]Sighting // complaint ID -> sighting
+	mu        sync.Mutex
+}
+
+// NewComplaintHistory loads the history from dataDir (empty if the file doesn't exist yet)
+func NewComplaintHistory(dataDir string) (*ComplaintHistory, error) {
+	h := &ComplaintHistory{
+		path:      filepath.Join(dataDir, ComplaintHistoryFile),
+		sightings: make(map[string]Sighting),
+	}
+
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read complaint history: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.sightings); err != nil {
+		return nil, fmt.Errorf("failed to parse complaint history: %w", err)
+	}
+	if h.sightings == nil {
+		h.sightings = make(map[string]Sighting)
+	}
+	return h, nil
+}
+
+// Record notes one run's complaints as seen and writes the history to disk
+func (h *ComplaintHistory) Record(complaints []models.Complaint) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, c := range complaints {
+		seen, ok := h.sightings[c.ID]
+		if !ok || c.FirstSeen.Before(seen.FirstSeen) {
+			seen.FirstSeen = c.FirstSeen
+		}
+		if c.LastSeen.After(seen.LastSeen) {
+			seen.LastSeen = c.LastSeen
+		}
+		seen.Runs++
+		h.sightings[c.ID] = seen
+	}
+	return h.save()
+}
+
+// Apply widens each complaint's FirstSeen/LastSeen to cover earlier runs
+func (h *ComplaintHistory) Apply(complaints []models.Complaint) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range complaints {
+		seen, ok := h.sightings[complaints[i].ID]
+		if !ok {
+			continue
+		}
+		if seen.FirstSeen.Before(complaints[i].FirstSeen) {
+			complaints[i].FirstSeen = seen.FirstSeen
+		}
+		if seen.LastSeen.After(complaints[i].LastSeen) {
+			complaints[i].LastSeen = seen.LastSeen
+		}
+	}
+}
+
+// save writes the history to disk. Must be called with h.mu held.
+func (h *ComplaintHistory) save() error {
+	data, err := json.MarshalIndent(h.sightings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal complaint history: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write complaint history: %w", err)
+	}
+	return nil
+}
+
+// ContentHash identifies a complaint by its wording, ignoring case, punctuation and spacing
+func ContentHash(description string) string {
+	normalized := strings.Join(strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,37 +1,39 @@
 package scrapers
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/gocolly/colly/v2"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
 )
 
 // GoogleScraper handles Google search scraping
 type GoogleScraper struct {
 	Collector *colly.Collector
-	Delay     time.Duration
+	Limiter   *ratelimit.Limiter // Throttles every page visit (nil = unthrottled)
+	Progress  ProgressFunc       // Optional: told about each query as ScrapeAll runs
+
+	runErrors []models.RunError // Failures from the last ScrapeAll
 }
 
 // NewGoogleScraper creates a new Google scraper instance
 func NewGoogleScraper() *GoogleScraper {
-	c := colly.NewCollector(
+	c := colly.NewCollector(append(
+		collectorIdentity("google"),
 		colly.AllowedDomains("www.google.com", "google.com"),
-		colly.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	)
-
-	// Rate limiting
-	c.Limit(&colly.LimitRule{
-		DomainGlob:  "*google.*",
-		Delay:       2 * time.Second,
-		RandomDelay: 1 * time.Second,
-	})
+	)...)
+	c.WithTransport(httpclient.DefaultTransport)
+	c.SetRequestTimeout(time.Duration(httpclient.Standard))
 
 	return &GoogleScraper{
 		Collector: c,
-		Delay:     2 * time.Second,
+		Limiter:   ratelimit.ForSource("google"),
 	}
 }
 
@@ -43,11 +45,20 @@ type GoogleSearchResult struct {
 }
 
 // Search performs a Google search and returns results
-func (gs *GoogleScraper) Search(query string, maxResults int) ([]models.GoogleResult, error) {
+func (gs *GoogleScraper) Search(ctx context.Context, query string, maxResults int) ([]models.GoogleResult, error) {
 	results := []models.GoogleResult{}
 
 	// Clone collector for each search to avoid state issues
 	c := gs.Collector.Clone()
+	c.Context = ctx
+
+	// Clones don't inherit callbacks, so throttle every request (including redirects) here
+	c.OnRequest(func(r *colly.Request) {
+		if err := gs.Limiter.Wait(ctx); err != nil {
+			fmt.Printf("⚠️  Google request skipped: %v\n", err)
+			r.Abort()
+		}
+	})
 
 	// Handle search result items
 	c.OnHTML("div.g", func(e *colly.HTMLElement) {
@@ -77,8 +88,12 @@ func (gs *GoogleScraper) Search(query string, maxResults int) ([]models.GoogleRe
 		results = append(results, result)
 	})
 
+	var statusErr error
 	c.OnError(func(r *colly.Response, err error) {
 		fmt.Printf("⚠️  Google scraping error: %v\n", err)
+		if r != nil && r.StatusCode > 0 {
+			statusErr = health.StatusError("Google", r.StatusCode, string(r.Body))
+		}
 	})
 
 	// Build search URL
@@ -89,6 +104,9 @@ func (gs *GoogleScraper) Search(query string, maxResults int) ([]models.GoogleRe
 	fmt.Printf("🔍 Searching Google for: %s\n", query)
 
 	err := c.Visit(searchURL)
+	if statusErr != nil {
+		err = statusErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to search Google: %w", err)
 	}
@@ -100,24 +118,36 @@ func (gs *GoogleScraper) Search(query string, maxResults int) ([]models.GoogleRe
 }
 
 // ScrapeAll searches Google for multiple queries
-func (gs *GoogleScraper) ScrapeAll(queries []string, resultsPerQuery int) ([]models.GoogleResult, error) {
+func (gs *GoogleScraper) ScrapeAll(ctx context.Context, queries []string, resultsPerQuery int) ([]models.GoogleResult, error) {
 	allResults := []models.GoogleResult{}
+	gs.runErrors = nil
 
-	for _, query := range queries {
-		results, err := gs.Search(query, resultsPerQuery)
+	for i, query := range queries {
+		if ctx.Err() != nil {
+			return allResults, ctx.Err()
+		}
+		results, err := gs.Search(ctx, query, resultsPerQuery)
 		if err != nil {
 			fmt.Printf("⚠️  Error searching for '%s': %v\n", query, err)
-			continue
+			runErr := health.RunError("google", query, err)
+			gs.runErrors = append(gs.runErrors, runErr)
+			gs.Progress.reportError(runErr)
 		}
 		allResults = append(allResults, results...)
-
-		// Be respectful with rate limiting
-		time.Sleep(gs.Delay)
+		gs.Progress.report(models.ScrapeProgress{
+			Type: models.ProgressQuery, Source: "google", Query: query, QueryIndex: i + 1, Queries: len(queries),
+			Items: len(results),
+		})
 	}
 
 	return allResults, nil
 }
 
+// RunErrors returns the failures ScrapeAll skipped past in its last run
+func (gs *GoogleScraper) RunErrors() []models.RunError {
+	return gs.runErrors
+}
+
 // extractDomain extracts the domain name from a URL
 func extractDomain(urlStr string) string {
 	// Simple extraction - remove protocol and path
@@ -0,0 +1,20 @@
+package scrapers
+
+import (
+	"github.com/gocolly/colly/v2"
+	"github.com/tasnint/coinsights/internal/config"
+)
+
+// collectorIdentity gives a colly scraper the identity and etiquette config sets for
+// its source - the colly counterpart of httpclient.Identify. Clones keep them.
+func collectorIdentity(source string) []colly.CollectorOption {
+	etiquette := config.EtiquetteFor(source)
+	options := []colly.CollectorOption{
+		colly.UserAgent(config.UserAgentFor(source)),
+		func(c *colly.Collector) { c.IgnoreRobotsTxt = etiquette.IgnoreRobots },
+	}
+	if config.Identity.Contact != "" && !etiquette.OmitContact {
+		options = append(options, colly.Headers(map[string]string{"From": config.Identity.Contact}))
+	}
+	return options
+}
@@ -0,0 +1,27 @@
+package scrapers
+
+import (
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ProgressFunc is told about each step of a multi-query scrape as it happens, e.g.
+// to stream a scheduled run's progress to the dashboard
+type ProgressFunc func(models.ScrapeProgress)
+
+// report sends p to the progress func, if there is one, stamped with the time
+func (progress ProgressFunc) report(p models.ScrapeProgress) {
+	if progress == nil {
+		return
+	}
+	if p.At.IsZero() {
+		p.At = time.Now()
+	}
+	progress(p)
+}
+
+// reportError sends a run error as an error event
+func (progress ProgressFunc) reportError(e models.RunError) {
+	progress.report(models.ScrapeProgress{Type: models.ProgressError, Source: e.Source, Query: e.Query, Error: &e})
+}
@@ -0,0 +1,458 @@
+package scrapers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+)
+
+// ============================================
+// REDDIT SCRAPER
+// ============================================
+// Posts and their comment threads from subreddit searches, through Reddit's JSON API.
+// Threads are crawled to the configured depth: top-level comments only by default,
+// or reply trees down to config.MaxThreadDepth. Collapsed "load more" branches aren't
+// expanded - each would cost another request against the rate limit.
+// Without credentials the public www.reddit.com endpoints are used; with a Reddit
+// "script" or "web" app's client ID and secret the scraper gets an application-only
+// OAuth token and the higher OAuth rate limit. Reddit rejects generic user agents,
+// so every request identifies the app.
+
+// RedditResultsFile is where the scraper saves its last run inside the data directory
+const RedditResultsFile = "reddit_latest_results.json"
+
+// MaxRedditListing is the most items one listing request returns
+const MaxRedditListing = 100
+
+const (
+	redditPublicURL = "https://www.reddit.com"
+	redditOAuthURL  = "https://oauth.reddit.com"
+	redditTokenURL  = "https://www.reddit.com/api/v1/access_token"
+)
+
+// RedditPost is a post that matched a search, with the comments fetched for it
+type RedditPost struct {
+	ID          string          `json:"id"` // Base-36 ID without the t3_ prefix
+	Subreddit   string          `json:"subreddit"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"` // Self-post text; empty for link posts
+	Author      string          `json:"author"`
+	URL         string          `json:"url"` // Permalink to the thread
+	Score       int             `json:"score"`
+	NumComments int             `json:"num_comments"`
+	CreatedAt   time.Time       `json:"created_at"`
+	SearchTerm  string          `json:"search_term"` // First term that surfaced the post this run
+	Comments    []RedditComment `json:"comments,omitempty"`
+}
+
+// RedditComment is a comment on a post, top-level or a reply
+type RedditComment struct {
+	ID        string    `json:"id"` // Base-36 ID without the t1_ prefix
+	PostID    string    `json:"post_id"`
+	ParentID  string    `json:"parent_id,omitempty"` // Comment replied to; empty for top-level comments
+	Depth     int       `json:"depth,omitempty"`     // Reply level (0 = top-level)
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"` // Permalink to the comment
+	Score     int       `json:"score"`
+	Replies   int       `json:"replies"`
+	Position  int       `json:"position"` // 1-based rank in the thread's reading order (each comment, then its replies)
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RedditResult is one scrape run
+type RedditResult struct {
+	Posts     []RedditPost      `json:"posts"`
+	Errors    []models.RunError `json:"errors,omitempty"` // Failures the run skipped past
+	RunID     string            `json:"run_id"`
+	ScrapedAt time.Time         `json:"scraped_at"`
+}
+
+// RedditScraper handles Reddit JSON API requests
+type RedditScraper struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	TokenURL   string
+	UserAgent  string
+	Limiter    *ratelimit.Limiter // Throttles every API call (nil = unthrottled)
+
+	clientID     string
+	clientSecret string
+	token        string
+	tokenExpiry  time.Time
+	tokenMu      sync.Mutex
+
+	runErrors []models.RunError // Failures from the last ScrapeAll
+}
+
+// NewRedditScraper creates a Reddit scraper. With an empty clientID the public,
+// unauthenticated endpoints are used.
+func NewRedditScraper(clientID, clientSecret string) *RedditScraper {
+	rs := &RedditScraper{
+		HTTPClient:   httpclient.New(httpclient.Standard),
+		BaseURL:      redditPublicURL,
+		TokenURL:     redditTokenURL,
+		UserAgent:    config.UserAgentFor("reddit"),
+		Limiter:      ratelimit.ForSource("reddit"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+	if clientID != "" {
+		rs.BaseURL = redditOAuthURL
+		rs.Limiter = ratelimit.ForSource("reddit_oauth")
+	}
+	return rs
+}
+
+// NewRedditScraperFromEnv creates a scraper using REDDIT_CLIENT_ID and REDDIT_CLIENT_SECRET
+// when set, and REDDIT_USER_AGENT if given
+func NewRedditScraperFromEnv() *RedditScraper {
+	rs := NewRedditScraper(os.Getenv("REDDIT_CLIENT_ID"), os.Getenv("REDDIT_CLIENT_SECRET"))
+	if ua := os.Getenv("REDDIT_USER_AGENT"); ua != "" {
+		rs.UserAgent = ua
+	}
+	return rs
+}
+
+// RedditSettingsFromEnv applies the REDDIT_SUBREDDITS and REDDIT_SEARCH_TERMS overrides
+// (comma-separated) to base
+func RedditSettingsFromEnv(base config.RedditSettings) config.RedditSettings {
+	if v := splitList(os.Getenv("REDDIT_SUBREDDITS")); len(v) > 0 {
+		base.Subreddits = v
+	}
+	if v := splitList(os.Getenv("REDDIT_SEARCH_TERMS")); len(v) > 0 {
+		base.SearchTerms = v
+	}
+	return base
+}
+
+// ============================================
+// Reddit API Response Structures
+// ============================================
+
+// redditListing is a page of things: search results, or one half of a comment thread
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Kind string      `json:"kind"` // "t3" = post, "t1" = comment, "more" = collapsed comments
+			Data redditThing `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// redditThing holds the fields used from posts and comments
+type redditThing struct {
+	ID          string          `json:"id"`
+	Subreddit   string          `json:"subreddit"`
+	Title       string          `json:"title"`
+	Selftext    string          `json:"selftext"`
+	Body        string          `json:"body"`
+	Author      string          `json:"author"`
+	Permalink   string          `json:"permalink"`
+	Score       int             `json:"score"`
+	NumComments int             `json:"num_comments"`
+	CreatedUTC  float64         `json:"created_utc"`
+	Replies     json.RawMessage `json:"replies"` // A listing, or "" when there are none
+}
+
+// created converts Reddit's fractional Unix timestamp
+func (t redditThing) created() time.Time {
+	return time.Unix(int64(t.CreatedUTC), 0).UTC()
+}
+
+// replies decodes a comment's replies listing, which is "" when there are none
+func (t redditThing) replies() redditListing {
+	var replies redditListing
+	if len(t.Replies) > 0 {
+		json.Unmarshal(t.Replies, &replies)
+	}
+	return replies
+}
+
+// replyCount counts the direct replies in a comment's replies listing
+func (t redditThing) replyCount() int {
+	n := 0
+	for _, child := range t.replies().Data.Children {
+		if child.Kind == "t1" {
+			n++
+		}
+	}
+	return n
+}
+
+// ============================================
+// Requests
+// ============================================
+
+// Search returns the newest posts in subreddit matching term within window
+// (hour, day, week, month, year or all)
+func (rs *RedditScraper) Search(ctx context.Context, subreddit, term string, limit int, window string) ([]RedditPost, error) {
+	params := url.Values{}
+	params.Add("q", term)
+	params.Add("restrict_sr", "1")
+	params.Add("sort", "new")
+	params.Add("t", window)
+	params.Add("limit", fmt.Sprintf("%d", min(limit, MaxRedditListing)))
+	params.Add("raw_json", "1") // Unescaped text instead of HTML entities
+
+	var listing redditListing
+	if err := rs.get(ctx, "/r/"+url.PathEscape(subreddit)+"/search", params, &listing); err != nil {
+		return nil, fmt.Errorf("failed to search r/%s: %w", subreddit, err)
+	}
+
+	posts := make([]RedditPost, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		if child.Kind != "t3" {
+			continue
+		}
+		t := child.Data
+		posts = append(posts, RedditPost{
+			ID:          t.ID,
+			Subreddit:   t.Subreddit,
+			Title:       t.Title,
+			Body:        t.Selftext,
+			Author:      t.Author,
+			URL:         redditPublicURL + t.Permalink,
+			Score:       t.Score,
+			NumComments: t.NumComments,
+			CreatedAt:   t.created(),
+			SearchTerm:  term,
+		})
+	}
+	return posts, nil
+}
+
+// GetComments returns up to limit comments on a post, crawling depth levels of the
+// thread (0 or 1 = top-level only) highest-voted first, each comment followed by its
+// replies. Deleted and removed comments are skipped, and so are their replies.
+func (rs *RedditScraper) GetComments(ctx context.Context, post RedditPost, limit, depth int) ([]RedditComment, error) {
+	depth = max(1, min(depth, config.MaxThreadDepth))
+	params := url.Values{}
+	params.Add("limit", fmt.Sprintf("%d", min(limit, MaxRedditListing)))
+	params.Add("depth", fmt.Sprintf("%d", depth))
+	params.Add("sort", "top")
+	params.Add("raw_json", "1")
+
+	// A thread is two listings: the post, then its comments
+	var thread []redditListing
+	path := "/r/" + url.PathEscape(post.Subreddit) + "/comments/" + url.PathEscape(post.ID)
+	if err := rs.get(ctx, path, params, &thread); err != nil {
+		return nil, fmt.Errorf("failed to fetch comments on %s: %w", post.ID, err)
+	}
+	if len(thread) < 2 {
+		return nil, fmt.Errorf("failed to fetch comments on %s: unexpected thread shape", post.ID)
+	}
+
+	comments := []RedditComment{}
+	collectComments(thread[1], post.ID, "", 0, depth, limit, &comments)
+	return comments, nil
+}
+
+// collectComments walks a comment listing depth-first, appending each comment and
+// then its replies until limit comments are collected or depth levels are walked
+func collectComments(listing redditListing, postID, parentID string, level, depth, limit int, comments *[]RedditComment) {
+	for _, child := range listing.Data.Children {
+		if len(*comments) >= limit {
+			return
+		}
+		t := child.Data
+		if child.Kind != "t1" || t.Body == "[deleted]" || t.Body == "[removed]" {
+			continue
+		}
+		*comments = append(*comments, RedditComment{
+			ID:        t.ID,
+			PostID:    postID,
+			ParentID:  parentID,
+			Depth:     level,
+			Author:    t.Author,
+			Body:      t.Body,
+			URL:       redditPublicURL + t.Permalink,
+			Score:     t.Score,
+			Replies:   t.replyCount(),
+			Position:  len(*comments) + 1,
+			CreatedAt: t.created(),
+		})
+		if level+1 < depth {
+			collectComments(t.replies(), postID, t.ID, level+1, depth, limit, comments)
+		}
+	}
+}
+
+// ScrapeAll runs every search term in every subreddit and fetches comments for the
+// posts found. A post surfaced by several searches is kept once.
+func (rs *RedditScraper) ScrapeAll(ctx context.Context, settings config.RedditSettings) (*RedditResult, error) {
+	start := time.Now()
+	result := &RedditResult{
+		Posts:     []RedditPost{},
+		RunID:     models.NewRunID(start),
+		ScrapedAt: start,
+	}
+	rs.runErrors = nil
+	seen := make(map[string]bool)
+
+	for _, subreddit := range settings.Subreddits {
+		for _, term := range settings.SearchTerms {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			fmt.Printf("🔍 Searching r/%s for: %s\n", subreddit, term)
+			posts, err := rs.Search(ctx, subreddit, term, settings.PostsPerSearch, settings.TimeWindow)
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				rs.runErrors = append(rs.runErrors, health.RunError("reddit", "r/"+subreddit+" "+term, err))
+				continue
+			}
+			for _, post := range posts {
+				if !seen[post.ID] {
+					seen[post.ID] = true
+					result.Posts = append(result.Posts, post)
+				}
+			}
+		}
+	}
+
+	if settings.CommentsPerPost > 0 {
+		for i := range result.Posts {
+			post := &result.Posts[i]
+			if post.NumComments == 0 {
+				continue
+			}
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			comments, err := rs.GetComments(ctx, *post, settings.CommentsPerPost, settings.ThreadDepth)
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				rs.runErrors = append(rs.runErrors, health.RunError("reddit", post.URL, err))
+				continue
+			}
+			post.Comments = comments
+		}
+	}
+
+	result.Errors = rs.runErrors
+	fmt.Printf("✅ Found %d Reddit posts\n", len(result.Posts))
+	return result, nil
+}
+
+// RunErrors returns the failures ScrapeAll skipped past in its last run
+func (rs *RedditScraper) RunErrors() []models.RunError {
+	return rs.runErrors
+}
+
+// get waits for the rate limiter, then GETs path and decodes the JSON response into v
+func (rs *RedditScraper) get(ctx context.Context, path string, params url.Values, v any) error {
+	if err := rs.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	reqURL := rs.BaseURL + path
+	if rs.clientID == "" {
+		reqURL += ".json" // The public endpoints serve HTML without the suffix
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	rs.identify(req)
+	if rs.clientID != "" {
+		token, err := rs.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := rs.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return health.StatusError("Reddit API", resp.StatusCode, string(body))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// identify sets the scraper's identity headers, keeping a REDDIT_USER_AGENT override
+func (rs *RedditScraper) identify(req *http.Request) {
+	httpclient.Identify(req, "reddit")
+	req.Header.Set("User-Agent", rs.UserAgent)
+}
+
+// accessToken returns an application-only OAuth token, fetching a new one when the
+// cached token is about to expire
+func (rs *RedditScraper) accessToken(ctx context.Context) (string, error) {
+	rs.tokenMu.Lock()
+	defer rs.tokenMu.Unlock()
+
+	if rs.token != "" && time.Now().Before(rs.tokenExpiry) {
+		return rs.token, nil
+	}
+
+	form := url.Values{}
+	form.Add("grant_type", "client_credentials")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rs.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(rs.clientID, rs.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rs.identify(req)
+
+	resp, err := rs.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Reddit access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", health.StatusError("Reddit OAuth", resp.StatusCode, string(body))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"` // Seconds
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode Reddit access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", health.NewError(health.ErrorAuth, fmt.Errorf("Reddit OAuth error: %s", token.Error))
+	}
+
+	rs.token = token.AccessToken
+	// Refresh a minute early so a request never goes out with an expired token
+	rs.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return rs.token, nil
+}
+
+// splitList splits a comma-separated list, dropping blank entries
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,582 @@
+package scrapers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+)
+
+// ============================================
+// REVIEW SITE SCRAPER
+// ============================================
+// Reviews from a company's Trustpilot pages and, when a BBB profile is configured,
+// the complaints filed with the BBB. Neither site has a public API, so the pages are
+// read as HTML, and both have reworked their markup before. Each page is read the
+// most structured way that works: Trustpilot's embedded Next.js page data, then the
+// schema.org JSON-LD the sites publish for search engines, then the review cards'
+// markup. Which parser read a review is kept with it, so a run that fell back to
+// markup shows the page changed before it breaks outright.
+// Both sites sit behind bot protection, so pages are fetched slowly (see
+// config.RateLimits) and a refused page ends that site's crawl for the run.
+
+// ReviewsResultsFile is where the scraper saves its last run inside the data directory
+const ReviewsResultsFile = "reviews_latest_results.json"
+
+const trustpilotBaseURL = "https://www.trustpilot.com"
+
+// Parsers a review page can be read with, most structured first
+const (
+	ReviewParserNextData = "next_data" // Trustpilot's __NEXT_DATA__ page props
+	ReviewParserJSONLD   = "json_ld"   // schema.org Review objects
+	ReviewParserMarkup   = "markup"    // The review cards' HTML
+)
+
+// Review is a review or complaint from a review site
+type Review struct {
+	ID          string    `json:"id"`               // The site's review ID, or a hash of the text where the page shows none
+	Source      string    `json:"source"`           // "trustpilot" or "bbb"
+	Rating      float64   `json:"rating,omitempty"` // Stars on a 0-5 scale; BBB complaints are unrated
+	Title       string    `json:"title,omitempty"`  // Review headline; a BBB complaint's type
+	Text        string    `json:"text"`
+	Author      string    `json:"author,omitempty"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+	Parser      string    `json:"parser"` // Which of the ReviewParser* read it
+}
+
+// ReviewsResult is one scrape run
+type ReviewsResult struct {
+	Reviews   []Review          `json:"reviews"`
+	Errors    []models.RunError `json:"errors,omitempty"` // Failures the run skipped past
+	RunID     string            `json:"run_id"`
+	ScrapedAt time.Time         `json:"scraped_at"`
+}
+
+// ReviewScraper reads Trustpilot review pages and BBB complaint pages
+type ReviewScraper struct {
+	HTTPClient        *http.Client
+	TrustpilotBaseURL string
+	TrustpilotLimiter *ratelimit.Limiter // Throttles Trustpilot pages (nil = unthrottled)
+	BBBLimiter        *ratelimit.Limiter // Throttles BBB pages (nil = unthrottled)
+
+	runErrors []models.RunError // Failures from the last ScrapeAll
+}
+
+// NewReviewScraper creates a review scraper
+func NewReviewScraper() *ReviewScraper {
+	return &ReviewScraper{
+		HTTPClient:        httpclient.New(httpclient.Standard),
+		TrustpilotBaseURL: trustpilotBaseURL,
+		TrustpilotLimiter: ratelimit.ForSource("trustpilot"),
+		BBBLimiter:        ratelimit.ForSource("bbb"),
+	}
+}
+
+// ReviewSettingsFromEnv applies the TRUSTPILOT_SLUG, REVIEW_PAGES and BBB_PROFILE_URL
+// overrides to base
+func ReviewSettingsFromEnv(base config.ReviewSettings) (config.ReviewSettings, error) {
+	if v := strings.TrimSpace(os.Getenv("TRUSTPILOT_SLUG")); v != "" {
+		base.TrustpilotSlug = v
+	}
+	if v := os.Getenv("REVIEW_PAGES"); v != "" {
+		pages, err := strconv.Atoi(v)
+		if err != nil || pages < 1 {
+			return base, fmt.Errorf("invalid REVIEW_PAGES %q: must be a positive number of pages", v)
+		}
+		base.Pages = pages
+	}
+	if v := strings.TrimSpace(os.Getenv("BBB_PROFILE_URL")); v != "" {
+		base.BBBProfileURL = v
+	}
+	return base, nil
+}
+
+// TrustpilotPageURL returns page (1-based) of a company's reviews, newest first
+func (rs *ReviewScraper) TrustpilotPageURL(slug string, page int) string {
+	pageURL := rs.TrustpilotBaseURL + "/review/" + url.PathEscape(slug) + "?sort=recency"
+	if page > 1 {
+		pageURL += "&page=" + strconv.Itoa(page)
+	}
+	return pageURL
+}
+
+// BBBPageURL returns page (1-based) of the complaints filed against a BBB profile
+func BBBPageURL(profileURL string, page int) string {
+	pageURL := strings.TrimRight(profileURL, "/") + "/complaints"
+	if page > 1 {
+		pageURL += "?page=" + strconv.Itoa(page)
+	}
+	return pageURL
+}
+
+// ScrapeAll reads settings.Pages pages of Trustpilot reviews and, with a BBB profile
+// configured, as many pages of BBB complaints. A review seen on two pages (the list
+// shifts when new reviews arrive mid-crawl) is kept once.
+func (rs *ReviewScraper) ScrapeAll(ctx context.Context, settings config.ReviewSettings) (*ReviewsResult, error) {
+	start := time.Now()
+	result := &ReviewsResult{
+		Reviews:   []Review{},
+		RunID:     models.NewRunID(start),
+		ScrapedAt: start,
+	}
+	rs.runErrors = nil
+	seen := make(map[string]bool)
+
+	crawl := func(source string, pageURL func(page int) string, read func(ctx context.Context, pageURL string) ([]Review, error)) error {
+		for page := 1; page <= settings.Pages; page++ {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Printf("🔍 Reading %s page %d\n", source, page)
+			reviews, err := read(ctx, pageURL(page))
+			if err != nil && page > 1 && (errors.Is(err, errPageNotFound) || health.Classify(err) == health.ErrorParse) {
+				// Page 1 parsed, so an empty or missing later page is past the last one
+				return nil
+			}
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				rs.runErrors = append(rs.runErrors, health.RunError(source, pageURL(page), err))
+				return nil
+			}
+			fresh := 0
+			for _, review := range reviews {
+				if key := review.Source + ":" + review.ID; !seen[key] {
+					seen[key] = true
+					result.Reviews = append(result.Reviews, review)
+					fresh++
+				}
+			}
+			if fresh == 0 {
+				return nil // Sites past their last page can repeat the first
+			}
+		}
+		return nil
+	}
+
+	if settings.TrustpilotSlug != "" {
+		if err := crawl("trustpilot", func(page int) string {
+			return rs.TrustpilotPageURL(settings.TrustpilotSlug, page)
+		}, rs.TrustpilotPage); err != nil {
+			result.Errors = rs.runErrors
+			return result, err
+		}
+	}
+	if settings.BBBProfileURL != "" {
+		if err := crawl("bbb", func(page int) string {
+			return BBBPageURL(settings.BBBProfileURL, page)
+		}, rs.BBBPage); err != nil {
+			result.Errors = rs.runErrors
+			return result, err
+		}
+	}
+
+	result.Errors = rs.runErrors
+	fmt.Printf("✅ Found %d reviews\n", len(result.Reviews))
+	return result, nil
+}
+
+// RunErrors returns the failures ScrapeAll skipped past in its last run
+func (rs *ReviewScraper) RunErrors() []models.RunError {
+	return rs.runErrors
+}
+
+// TrustpilotPage fetches and parses one Trustpilot review page
+func (rs *ReviewScraper) TrustpilotPage(ctx context.Context, pageURL string) ([]Review, error) {
+	page, err := rs.fetch(ctx, rs.TrustpilotLimiter, "trustpilot", pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTrustpilotPage(page, rs.TrustpilotBaseURL)
+}
+
+// BBBPage fetches and parses one page of BBB complaints
+func (rs *ReviewScraper) BBBPage(ctx context.Context, pageURL string) ([]Review, error) {
+	page, err := rs.fetch(ctx, rs.BBBLimiter, "bbb", pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBBBPage(page, pageURL)
+}
+
+// errPageNotFound is a page the site answered 404 for
+var errPageNotFound = errors.New("page not found")
+
+// fetch waits for limiter, then GETs a page's HTML
+func (rs *ReviewScraper) fetch(ctx context.Context, limiter *ratelimit.Limiter, source, pageURL string) (string, error) {
+	if err := limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	httpclient.Identify(req, source)
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := rs.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, errPageNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", health.StatusError(source+" page", resp.StatusCode, string(snippet))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+	return string(body), nil
+}
+
+// ============================================
+// Parsing
+// ============================================
+
+// ParseTrustpilotPage extracts the reviews on a Trustpilot review page. baseURL
+// resolves the review links the markup fallback finds.
+func ParseTrustpilotPage(page, baseURL string) ([]Review, error) {
+	if reviews := parseNextData(page, baseURL); len(reviews) > 0 {
+		return reviews, nil
+	}
+	if reviews := parseJSONLDReviews(page, "trustpilot", baseURL); len(reviews) > 0 {
+		return reviews, nil
+	}
+	if reviews := trustpilotMarkup.parse(page, "trustpilot", baseURL); len(reviews) > 0 {
+		return reviews, nil
+	}
+	return nil, health.NewError(health.ErrorParse, fmt.Errorf("no reviews found on Trustpilot page"))
+}
+
+// ParseBBBPage extracts the complaints on a BBB complaints page. BBB complaints have
+// no links of their own, so each one's URL is the page it was found on.
+func ParseBBBPage(page, pageURL string) ([]Review, error) {
+	if reviews := parseJSONLDReviews(page, "bbb", pageURL); len(reviews) > 0 {
+		return reviews, nil
+	}
+	if reviews := bbbMarkup.parse(page, "bbb", pageURL); len(reviews) > 0 {
+		return reviews, nil
+	}
+	return nil, health.NewError(health.ErrorParse, fmt.Errorf("no complaints found on BBB page"))
+}
+
+var (
+	nextDataPattern = regexp.MustCompile(`(?s)<script[^>]*id="__NEXT_DATA__"[^>]*>(.*?)</script>`)
+	jsonLDPattern   = regexp.MustCompile(`(?s)<script[^>]*type="application/ld\+json"[^>]*>(.*?)</script>`)
+)
+
+// trustpilotNextData is the part of Trustpilot's Next.js page data holding the reviews
+type trustpilotNextData struct {
+	Props struct {
+		PageProps struct {
+			Reviews []struct {
+				ID     string  `json:"id"`
+				Title  string  `json:"title"`
+				Text   string  `json:"text"`
+				Rating float64 `json:"rating"`
+				Dates  struct {
+					PublishedDate time.Time `json:"publishedDate"`
+				} `json:"dates"`
+				Consumer struct {
+					DisplayName string `json:"displayName"`
+				} `json:"consumer"`
+			} `json:"reviews"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// parseNextData reads the reviews from Trustpilot's __NEXT_DATA__ script
+func parseNextData(page, baseURL string) []Review {
+	m := nextDataPattern.FindStringSubmatch(page)
+	if m == nil {
+		return nil
+	}
+	var data trustpilotNextData
+	if err := json.Unmarshal([]byte(m[1]), &data); err != nil {
+		return nil
+	}
+
+	reviews := []Review{}
+	for _, r := range data.Props.PageProps.Reviews {
+		if r.ID == "" || strings.TrimSpace(r.Text) == "" {
+			continue
+		}
+		reviews = append(reviews, Review{
+			ID:          r.ID,
+			Source:      "trustpilot",
+			Rating:      r.Rating,
+			Title:       strings.TrimSpace(r.Title),
+			Text:        strings.TrimSpace(r.Text),
+			Author:      r.Consumer.DisplayName,
+			URL:         baseURL + "/reviews/" + r.ID,
+			PublishedAt: r.Dates.PublishedDate,
+			Parser:      ReviewParserNextData,
+		})
+	}
+	return reviews
+}
+
+// parseJSONLDReviews reads the schema.org Review objects in a page's JSON-LD scripts,
+// wherever they are nested (Trustpilot puts them in an @graph, others under the
+// business's "review" list). Relative review URLs are resolved against baseURL.
+func parseJSONLDReviews(page, source, baseURL string) []Review {
+	reviews := []Review{}
+	for _, m := range jsonLDPattern.FindAllStringSubmatch(page, -1) {
+		var doc any
+		if err := json.Unmarshal([]byte(m[1]), &doc); err != nil {
+			continue
+		}
+		walkJSONLD(doc, func(obj map[string]any) {
+			if review, ok := jsonLDReview(obj, source, baseURL); ok {
+				reviews = append(reviews, review)
+			}
+		})
+	}
+	return reviews
+}
+
+// walkJSONLD calls visit with every object in a JSON-LD document
+func walkJSONLD(node any, visit func(map[string]any)) {
+	switch v := node.(type) {
+	case map[string]any:
+		visit(v)
+		for _, child := range v {
+			walkJSONLD(child, visit)
+		}
+	case []any:
+		for _, child := range v {
+			walkJSONLD(child, visit)
+		}
+	}
+}
+
+// jsonLDReview converts a schema.org Review object, normalizing its rating to 0-5
+func jsonLDReview(obj map[string]any, source, baseURL string) (Review, bool) {
+	if !jsonLDIsType(obj["@type"], "Review") {
+		return Review{}, false
+	}
+	text := strings.TrimSpace(html.UnescapeString(jsonLDString(obj["reviewBody"])))
+	if text == "" {
+		return Review{}, false
+	}
+
+	review := Review{
+		Source: source,
+		Title:  strings.TrimSpace(html.UnescapeString(jsonLDString(obj["headline"]))),
+		Text:   text,
+		Author: jsonLDString(obj["author"]),
+		URL:    resolveURL(baseURL, jsonLDString(obj["url"])),
+		Parser: ReviewParserJSONLD,
+	}
+	if review.Title == "" {
+		review.Title = strings.TrimSpace(jsonLDString(obj["name"]))
+	}
+	if review.URL == "" {
+		review.URL = baseURL
+	}
+	review.PublishedAt, _ = parseReviewTime(jsonLDString(obj["datePublished"]))
+
+	// "@id" ends in the site's review ID, e.g. ".../schema/Review/www.coinbase.com/<id>"
+	if id := jsonLDString(obj["@id"]); id != "" {
+		review.ID = id[strings.LastIndexAny(id, "/#")+1:]
+	}
+	if review.ID == "" {
+		review.ID = ContentHash(review.PublishedAt.Format("2006-01-02") + "|" + text)
+	}
+
+	if rating, ok := obj["reviewRating"].(map[string]any); ok {
+		value, _ := strconv.ParseFloat(jsonLDString(rating["ratingValue"]), 64)
+		if best, err := strconv.ParseFloat(jsonLDString(rating["bestRating"]), 64); err == nil && best > 0 && best != 5 {
+			value = value / best * 5
+		}
+		review.Rating = value
+	}
+	return review, true
+}
+
+// jsonLDIsType reports whether an "@type" value (a string or a list) includes want
+func jsonLDIsType(value any, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == want
+	case []any:
+		for _, t := range v {
+			if t == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDString reads a JSON-LD value as a string: strings and numbers as themselves,
+// and objects (an author Person, say) by their name
+func jsonLDString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		return jsonLDString(v["name"])
+	}
+	return ""
+}
+
+// reviewMarkup locates the parts of a site's review cards in its HTML. Each field
+// pattern's first group is the value; text takes the longest match in the card.
+type reviewMarkup struct {
+	card        *regexp.Regexp // Start of each review card
+	rating      *regexp.Regexp // Optional
+	date        *regexp.Regexp
+	dateLayouts []string       // Tried after RFC 3339
+	title       *regexp.Regexp // Optional
+	text        *regexp.Regexp
+	author      *regexp.Regexp // Optional
+	link        *regexp.Regexp // Optional: the card's own page
+}
+
+// trustpilotMarkup matches Trustpilot's review cards, which tag each part with a
+// data-service-review-* or data-consumer-* attribute
+var trustpilotMarkup = reviewMarkup{
+	card:   regexp.MustCompile(`<article[\s>]`),
+	rating: regexp.MustCompile(`(?:data-service-review-rating="|alt="Rated )([0-9.]+)`),
+	date:   regexp.MustCompile(`<time[^>]*datetime="([^"]+)"`),
+	title:  regexp.MustCompile(`(?s)data-service-review-title-typography[^>]*>(.*?)</h2>`),
+	text:   regexp.MustCompile(`(?s)data-service-review-text-typography[^>]*>(.*?)</p>`),
+	author: regexp.MustCompile(`(?s)data-consumer-name-typography[^>]*>(.*?)</span>`),
+	link:   regexp.MustCompile(`href="(/reviews/[0-9a-fA-F]+)"`),
+}
+
+// bbbMarkup matches BBB's complaint cards: the complaint type and status under
+// headings, a MM/DD/YYYY date, and the complaint itself in paragraphs
+var bbbMarkup = reviewMarkup{
+	card:        regexp.MustCompile(`<li[^>]*class="[^"]*complaint[^"]*"`),
+	date:        regexp.MustCompile(`\b(\d{1,2}/\d{1,2}/\d{4})\b`),
+	dateLayouts: []string{"1/2/2006"},
+	title:       regexp.MustCompile(`(?s)Complaint Type:\s*(?:<[^>]+>\s*)*([^<]+)`),
+	text:        regexp.MustCompile(`(?s)<p[^>]*>(.*?)</p>`),
+}
+
+// parse reads the review cards in page. Cards without text are skipped.
+func (rm reviewMarkup) parse(page, source, baseURL string) []Review {
+	starts := rm.card.FindAllStringIndex(page, -1)
+	reviews := []Review{}
+	for i, start := range starts {
+		end := len(page)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		card := page[start[0]:end]
+
+		text := ""
+		for _, m := range rm.text.FindAllStringSubmatch(card, -1) {
+			if t := pageText(m[1]); len(t) > len(text) {
+				text = t
+			}
+		}
+		if text == "" {
+			continue
+		}
+
+		review := Review{
+			Source: source,
+			Title:  pageText(firstGroup(rm.title, card)),
+			Text:   text,
+			Author: pageText(firstGroup(rm.author, card)),
+			URL:    baseURL,
+			Parser: ReviewParserMarkup,
+		}
+		review.Rating, _ = strconv.ParseFloat(firstGroup(rm.rating, card), 64)
+		review.PublishedAt, _ = parseReviewTime(firstGroup(rm.date, card), rm.dateLayouts...)
+		if link := firstGroup(rm.link, card); link != "" {
+			review.URL = resolveURL(baseURL, link)
+			review.ID = link[strings.LastIndex(link, "/")+1:]
+		} else {
+			review.ID = ContentHash(review.PublishedAt.Format("2006-01-02") + "|" + text)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews
+}
+
+// firstGroup returns pattern's first group in s, or "" (also for a nil pattern)
+func firstGroup(pattern *regexp.Regexp, s string) string {
+	if pattern == nil {
+		return ""
+	}
+	if m := pattern.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// pageText turns an HTML fragment into plain text: line breaks kept, other tags
+// dropped, entities decoded and runs of spaces collapsed
+func pageText(fragment string) string {
+	fragment = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n").Replace(fragment)
+	var b strings.Builder
+	inTag := false
+	for _, r := range fragment {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	lines := strings.Split(html.UnescapeString(b.String()), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// parseReviewTime parses an RFC 3339 timestamp, a bare date, or one of layouts
+func parseReviewTime(value string, layouts ...string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range append([]string{time.RFC3339, "2006-01-02"}, layouts...) {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resolveURL resolves ref against base, returning "" when ref is empty or invalid
+func resolveURL(base, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return b.ResolveReference(r).String()
+}
@@ -1,6 +1,7 @@
 package scrapers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,7 +9,11 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
 )
 
 // YouTubeScraper handles YouTube Data API requests
@@ -16,19 +21,30 @@ type YouTubeScraper struct {
 	APIKey     string
 	HTTPClient *http.Client
 	BaseURL    string
+	Filter     VideoFilter        // Applied after video details are fetched
+	Limiter    *ratelimit.Limiter // Throttles every API call (nil = unthrottled)
+	MaxPages   int                // Pages per search or comment list (0 = as many as maxResults needs)
+	Progress   ProgressFunc       // Optional: told about each query and video as ScrapePlanned runs
 }
 
 // NewYouTubeScraper creates a new YouTube scraper instance
 func NewYouTubeScraper(apiKey string) *YouTubeScraper {
 	return &YouTubeScraper{
-		APIKey:  apiKey,
-		BaseURL: "https://www.googleapis.com/youtube/v3",
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		APIKey:     apiKey,
+		BaseURL:    "https://www.googleapis.com/youtube/v3",
+		HTTPClient: httpclient.New(httpclient.Standard),
+		Limiter:    ratelimit.ForSource("youtube"),
 	}
 }
 
+// get waits for the rate limiter, then issues a GET to the Data API
+func (ys *YouTubeScraper) get(reqURL string) (*http.Response, error) {
+	if err := ys.Limiter.Wait(context.Background()); err != nil {
+		return nil, err
+	}
+	return ys.HTTPClient.Get(reqURL)
+}
+
 // ============================================
 // YouTube API Response Structures
 // Based on official YouTube Data API docs
@@ -197,30 +213,73 @@ type VideoListResponse struct {
 // SearchVideos searches for YouTube videos matching the query
 // Uses: GET https://www.googleapis.com/youtube/v3/search
 func (ys *YouTubeScraper) SearchVideos(query string, maxResults int) ([]models.YouTubeVideo, error) {
+	return ys.SearchVideosInRange(query, maxResults, time.Time{}, time.Time{})
+}
+
+// SearchVideosInRange searches for videos published within [after, before).
+// Zero times leave that side of the range open. Results past one page are
+// fetched with nextPageToken, up to MaxPages pages; if a later page fails the
+// videos already found are returned with the error.
+func (ys *YouTubeScraper) SearchVideosInRange(query string, maxResults int, after, before time.Time) ([]models.YouTubeVideo, error) {
+	videos, _, err := ys.searchPages(query, maxResults, after, before)
+	return videos, err
+}
+
+// searchPages runs SearchVideosInRange, also returning how many pages it requested
+func (ys *YouTubeScraper) searchPages(query string, maxResults int, after, before time.Time) ([]models.YouTubeVideo, int, error) {
+	videos := []models.YouTubeVideo{}
+	pageToken := ""
+	pages := 0
+	for len(videos) < maxResults && (ys.MaxPages == 0 || pages < ys.MaxPages) {
+		pages++
+		page, next, err := ys.searchPage(query, min(maxResults-len(videos), config.YouTubeSearchPageSize), after, before, pageToken)
+		if err != nil {
+			return videos, pages, err
+		}
+		videos = append(videos, page...)
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	return videos, pages, nil
+}
+
+// searchPage fetches one search.list page, returning the token for the next one
+func (ys *YouTubeScraper) searchPage(query string, maxResults int, after, before time.Time, pageToken string) ([]models.YouTubeVideo, string, error) {
 	params := url.Values{}
 	params.Add("part", "snippet")
 	params.Add("q", query)
 	params.Add("type", "video") // Only return videos
 	params.Add("maxResults", fmt.Sprintf("%d", maxResults))
 	params.Add("order", "relevance") // Can be: date, rating, relevance, title, viewCount
+	if !after.IsZero() {
+		params.Add("publishedAfter", after.UTC().Format(time.RFC3339))
+	}
+	if !before.IsZero() {
+		params.Add("publishedBefore", before.UTC().Format(time.RFC3339))
+	}
+	if pageToken != "" {
+		params.Add("pageToken", pageToken)
+	}
 	params.Add("key", ys.APIKey)
 
 	reqURL := fmt.Sprintf("%s/search?%s", ys.BaseURL, params.Encode())
 
-	resp, err := ys.HTTPClient.Get(reqURL)
+	resp, err := ys.get(reqURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search videos: %w", err)
+		return nil, "", fmt.Errorf("failed to search videos: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("YouTube API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, "", health.StatusError("YouTube API", resp.StatusCode, string(body))
 	}
 
 	var searchResp SearchListResponse
 	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Convert API response to our model
@@ -247,40 +306,70 @@ func (ys *YouTubeScraper) SearchVideos(query string, maxResults int) ([]models.Y
 		videos = append(videos, video)
 	}
 
-	return videos, nil
+	return videos, searchResp.NextPageToken, nil
 }
 
-// GetVideoComments fetches comments for a specific video
+// GetVideoComments fetches comments for a specific video, paging like SearchVideosInRange
 // Uses: GET https://www.googleapis.com/youtube/v3/commentThreads
 func (ys *YouTubeScraper) GetVideoComments(videoID string, maxResults int) ([]models.YouTubeComment, error) {
+	comments, _, err := ys.commentPages(videoID, maxResults)
+	return comments, err
+}
+
+// commentPages runs GetVideoComments, also returning how many pages it requested
+func (ys *YouTubeScraper) commentPages(videoID string, maxResults int) ([]models.YouTubeComment, int, error) {
+	comments := []models.YouTubeComment{}
+	pageToken := ""
+	pages := 0
+	for len(comments) < maxResults && (ys.MaxPages == 0 || pages < ys.MaxPages) {
+		pages++
+		page, next, err := ys.commentPage(videoID, min(maxResults-len(comments), config.YouTubeCommentPageSize), pageToken, len(comments))
+		if err != nil {
+			return comments, pages, err
+		}
+		comments = append(comments, page...)
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+	return comments, pages, nil
+}
+
+// commentPage fetches one commentThreads.list page. Positions continue from offset,
+// the number of comments on earlier pages.
+func (ys *YouTubeScraper) commentPage(videoID string, maxResults int, pageToken string, offset int) ([]models.YouTubeComment, string, error) {
 	params := url.Values{}
 	params.Add("part", "snippet")
 	params.Add("videoId", videoID)
 	params.Add("maxResults", fmt.Sprintf("%d", maxResults))
 	params.Add("order", "relevance") // Can be: time, relevance
 	params.Add("textFormat", "plainText")
+	if pageToken != "" {
+		params.Add("pageToken", pageToken)
+	}
 	params.Add("key", ys.APIKey)
 
 	reqURL := fmt.Sprintf("%s/commentThreads?%s", ys.BaseURL, params.Encode())
 
-	resp, err := ys.HTTPClient.Get(reqURL)
+	resp, err := ys.get(reqURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+		return nil, "", fmt.Errorf("failed to fetch comments: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("YouTube API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, "", health.StatusError("YouTube API", resp.StatusCode, string(body))
 	}
 
 	var commentsResp CommentThreadListResponse
 	if err := json.NewDecoder(resp.Body).Decode(&commentsResp); err != nil {
-		return nil, fmt.Errorf("failed to decode comments: %w", err)
+		return nil, "", fmt.Errorf("failed to decode comments: %w", err)
 	}
 
 	comments := make([]models.YouTubeComment, 0, len(commentsResp.Items))
-	for _, item := range commentsResp.Items {
+	for i, item := range commentsResp.Items {
 		snippet := item.Snippet.TopLevelComment.Snippet
 		publishedAt, _ := time.Parse(time.RFC3339, snippet.PublishedAt)
 
@@ -290,23 +379,45 @@ func (ys *YouTubeScraper) GetVideoComments(videoID string, maxResults int) ([]mo
 			AuthorName:  snippet.AuthorDisplayName,
 			Text:        snippet.TextOriginal,
 			LikeCount:   snippet.LikeCount,
+			ReplyCount:  item.Snippet.TotalReplyCount,
+			Position:    offset + i + 1,
 			PublishedAt: publishedAt,
 		}
 		comments = append(comments, comment)
 	}
 
-	return comments, nil
+	return comments, commentsResp.NextPageToken, nil
 }
 
 // GetVideoDetails fetches detailed information for multiple videos
 // Uses: GET https://www.googleapis.com/youtube/v3/videos
 // This enriches search results with stats (views, likes) and full description
 func (ys *YouTubeScraper) GetVideoDetails(videoIDs []string) (map[string]*VideoResource, error) {
-	if len(videoIDs) == 0 {
-		return make(map[string]*VideoResource), nil
+	videoMap, _, err := ys.videoDetails(videoIDs)
+	return videoMap, err
+}
+
+// videoDetails runs GetVideoDetails, also returning how many calls it made.
+// YouTube allows up to 50 video IDs per request, so longer lists are batched;
+// if a batch fails the details already fetched are returned with the error.
+func (ys *YouTubeScraper) videoDetails(videoIDs []string) (map[string]*VideoResource, int, error) {
+	videoMap := make(map[string]*VideoResource)
+	calls := 0
+	for start := 0; start < len(videoIDs); start += config.YouTubeVideosBatchSize {
+		calls++
+		batch, err := ys.videoDetailsBatch(videoIDs[start:min(start+config.YouTubeVideosBatchSize, len(videoIDs))])
+		if err != nil {
+			return videoMap, calls, err
+		}
+		for id, details := range batch {
+			videoMap[id] = details
+		}
 	}
+	return videoMap, calls, nil
+}
 
-	// YouTube allows up to 50 video IDs per request
+// videoDetailsBatch fetches details for at most 50 videos in one call
+func (ys *YouTubeScraper) videoDetailsBatch(videoIDs []string) (map[string]*VideoResource, error) {
 	params := url.Values{}
 	params.Add("part", "snippet,statistics,contentDetails")
 	params.Add("id", joinStrings(videoIDs, ","))
@@ -314,7 +425,7 @@ func (ys *YouTubeScraper) GetVideoDetails(videoIDs []string) (map[string]*VideoR
 
 	reqURL := fmt.Sprintf("%s/videos?%s", ys.BaseURL, params.Encode())
 
-	resp, err := ys.HTTPClient.Get(reqURL)
+	resp, err := ys.get(reqURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch video details: %w", err)
 	}
@@ -322,7 +433,7 @@ func (ys *YouTubeScraper) GetVideoDetails(videoIDs []string) (map[string]*VideoR
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("YouTube API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, health.StatusError("YouTube API", resp.StatusCode, string(body))
 	}
 
 	var videosResp VideoListResponse
@@ -341,21 +452,67 @@ func (ys *YouTubeScraper) GetVideoDetails(videoIDs []string) (map[string]*VideoR
 
 // ScrapeAll searches videos, enriches with details, and fetches comments
 func (ys *YouTubeScraper) ScrapeAll(queries []string, videosPerQuery int, commentsPerVideo int) (*models.ScrapeResult, error) {
+	plan := make([]models.QueryPlan, len(queries))
+	for i, query := range queries {
+		plan[i] = models.QueryPlan{Query: query, MaxVideos: videosPerQuery}
+	}
+	return ys.ScrapePlanned(plan, commentsPerVideo)
+}
+
+// ScrapePlanned runs a query plan where each query has its own video budget,
+// recording per-query yield stats on the result
+func (ys *YouTubeScraper) ScrapePlanned(plan []models.QueryPlan, commentsPerVideo int) (*models.ScrapeResult, error) {
 	result := &models.ScrapeResult{
 		Videos:    []models.YouTubeVideo{},
 		Comments:  []models.YouTubeComment{},
 		ScrapedAt: time.Now(),
 	}
+	result.RunID = models.NewRunID(result.ScrapedAt)
+	seenVideos := make(map[string]bool)
+	quota := 0 // Spent by the queries before this one
+	fail := func(e models.RunError) {
+		result.Errors = append(result.Errors, e)
+		ys.Progress.reportError(e)
+	}
 
-	for _, query := range queries {
+	for i, planned := range plan {
+		query := planned.Query
 		fmt.Printf("Searching YouTube for: %s\n", query)
 
-		videos, err := ys.SearchVideos(query, videosPerQuery)
+		stat := models.QueryStat{Query: query}
+
+		videos, pages, err := ys.searchPages(query, planned.MaxVideos, planned.PublishedAfter, planned.PublishedBefore)
+		stat.QuotaUsed += pages * config.YouTubeSearchCost // search.list = 100 units per page
 		if err != nil {
 			fmt.Printf("Error searching for '%s': %v\n", query, err)
-			continue
+			fail(health.RunError("youtube", query, err))
+			if len(videos) == 0 {
+				result.QueryStats = append(result.QueryStats, stat)
+				quota += stat.QuotaUsed
+				ys.Progress.report(models.ScrapeProgress{
+					Type: models.ProgressQuery, Source: "youtube", Query: query, QueryIndex: i + 1, Queries: len(plan),
+					QuotaUsed: quota,
+				})
+				continue
+			}
+			// A later page failed - keep what the earlier pages found
 		}
 		fmt.Printf("Found %d videos\n", len(videos))
+		stat.VideosFound = len(videos)
+
+		// Skip videos an earlier query already returned - they cost quota but add nothing
+		newVideos := make([]models.YouTubeVideo, 0, len(videos))
+		for _, v := range videos {
+			if seenVideos[v.VideoID] {
+				stat.DuplicateVideos++
+				continue
+			}
+			seenVideos[v.VideoID] = true
+			v.SearchQuery = query
+			newVideos = append(newVideos, v)
+		}
+		videos = newVideos
+		stat.NewVideos = len(videos)
 
 		// Collect video IDs for batch details fetch
 		videoIDs := make([]string, len(videos))
@@ -363,12 +520,14 @@ func (ys *YouTubeScraper) ScrapeAll(queries []string, videosPerQuery int, commen
 			videoIDs[i] = v.VideoID
 		}
 
-		// Fetch detailed stats for all videos in one API call
+		// Fetch detailed stats in batches of 50 videos per API call
 		fmt.Printf("Fetching video statistics...\n")
-		videoDetails, err := ys.GetVideoDetails(videoIDs)
+		videoDetails, calls, err := ys.videoDetails(videoIDs)
 		if err != nil {
 			fmt.Printf("Error fetching video details: %v\n", err)
+			fail(health.RunError("youtube", query, err))
 		}
+		stat.QuotaUsed += calls * config.YouTubeListCost // videos.list = 1 unit per batch
 
 		// Enrich videos with statistics
 		for i := range videos {
@@ -377,34 +536,61 @@ func (ys *YouTubeScraper) ScrapeAll(queries []string, videosPerQuery int, commen
 				videos[i].LikeCount = parseCount(details.Statistics.LikeCount)
 				videos[i].CommentCount = parseCount(details.Statistics.CommentCount)
 				videos[i].Duration = details.ContentDetails.Duration
+				videos[i].DurationSeconds = DurationSeconds(details.ContentDetails.Duration)
 				videos[i].Tags = details.Snippet.Tags
 				// Use full description from videos.list (not truncated)
 				if details.Snippet.Description != "" {
 					videos[i].Description = details.Snippet.Description
 				}
+				videos[i].IsShort = isShort(videos[i])
 			}
 		}
 
+		// Drop Shorts / out-of-range lengths before spending quota on their comments
+		kept := videos[:0]
+		for _, v := range videos {
+			if ys.Filter.Allows(v) {
+				kept = append(kept, v)
+			}
+		}
+		if skipped := len(videos) - len(kept); skipped > 0 {
+			fmt.Printf("Skipped %d videos by length/Shorts filter\n", skipped)
+		}
+		videos = kept
+
 		result.Videos = append(result.Videos, videos...)
 
 		// Fetch comments for each video
 		for _, video := range videos {
 			fmt.Printf("Fetching comments for: %s\n", video.Title)
 
-			comments, err := ys.GetVideoComments(video.VideoID, commentsPerVideo)
+			comments, pages, err := ys.commentPages(video.VideoID, commentsPerVideo)
+			stat.QuotaUsed += pages * config.YouTubeListCost // commentThreads.list = 1 unit per page
 			if err != nil {
 				fmt.Printf("Error fetching comments for %s: %v\n", video.VideoID, err)
-				continue
+				fail(health.RunError("youtube", video.VideoID, err))
 			}
 
 			result.Comments = append(result.Comments, comments...)
-			fmt.Printf("Found %d comments\n", len(comments))
-
-			// Rate limiting - be nice to the API
-			time.Sleep(500 * time.Millisecond)
+			stat.CommentsFetched += len(comments)
+			if err == nil || len(comments) > 0 {
+				fmt.Printf("Found %d comments\n", len(comments))
+			}
+			ys.Progress.report(models.ScrapeProgress{
+				Type: models.ProgressVideo, Source: "youtube", Query: query, QueryIndex: i + 1, Queries: len(plan),
+				VideoID: video.VideoID, Title: video.Title, Items: len(comments), QuotaUsed: quota + stat.QuotaUsed,
+			})
 		}
+
+		result.QueryStats = append(result.QueryStats, stat)
+		quota += stat.QuotaUsed
+		ys.Progress.report(models.ScrapeProgress{
+			Type: models.ProgressQuery, Source: "youtube", Query: query, QueryIndex: i + 1, Queries: len(plan),
+			Items: len(videos), QuotaUsed: quota,
+		})
 	}
 
+	result.ErrorCounts = models.CountErrors(result.Errors)
 	return result, nil
 }
 
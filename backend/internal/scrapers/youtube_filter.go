@@ -0,0 +1,97 @@
+package scrapers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ShortsMaxDuration is the longest a YouTube Short can be (raised from 60s in October 2024)
+const ShortsMaxDuration = 3 * time.Minute
+
+// VideoFilter decides which videos are kept after their details are fetched.
+// Filtering happens before comments are fetched so skipped videos cost no comment quota.
+type VideoFilter struct {
+	ExcludeShorts bool
+	MinDuration   time.Duration // 0 = no minimum
+	MaxDuration   time.Duration // 0 = no maximum
+}
+
+// Allows reports whether a video passes the filter
+func (f VideoFilter) Allows(video models.YouTubeVideo) bool {
+	if f.ExcludeShorts && video.IsShort {
+		return false
+	}
+
+	duration, ok := ParseISODuration(video.Duration)
+	if !ok {
+		return true // Unknown length - don't drop data we can't judge
+	}
+	if f.MinDuration > 0 && duration < f.MinDuration {
+		return false
+	}
+	if f.MaxDuration > 0 && duration > f.MaxDuration {
+		return false
+	}
+	return true
+}
+
+var isoDurationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// ParseISODuration parses a YouTube contentDetails duration such as "PT4M13S",
+// "PT1H2M" or "P1DT3H" (long live streams). "P0D" (upcoming/live) parses as zero.
+func ParseISODuration(s string) (time.Duration, bool) {
+	m := isoDurationPattern.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, false
+	}
+
+	var total time.Duration
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, false
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, true
+}
+
+// DurationSeconds converts an ISO 8601 duration to whole seconds (0 if unparseable)
+func DurationSeconds(s string) int {
+	d, ok := ParseISODuration(s)
+	if !ok {
+		return 0
+	}
+	return int(d / time.Second)
+}
+
+// isShort reports whether a video is a YouTube Short: short enough and,
+// since the API has no Shorts flag, either tagged #shorts or at most a minute long
+func isShort(video models.YouTubeVideo) bool {
+	duration, ok := ParseISODuration(video.Duration)
+	if !ok || duration == 0 || duration > ShortsMaxDuration {
+		return false
+	}
+	if duration <= time.Minute {
+		return true
+	}
+
+	text := strings.ToLower(video.Title + " " + video.Description)
+	if strings.Contains(text, "#shorts") || strings.Contains(text, "#short ") {
+		return true
+	}
+	for _, tag := range video.Tags {
+		if strings.EqualFold(tag, "shorts") || strings.EqualFold(tag, "#shorts") {
+			return true
+		}
+	}
+	return false
+}
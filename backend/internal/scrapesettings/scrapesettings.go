@@ -0,0 +1,265 @@
+// Scraper settings presets: the built-in ones, operator-defined ones and which one is active, persisted to the data directory
+package scrapesettings
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SETTINGS STORE
+// ============================================
+// The built-in presets live in config.BuiltinPresets. Operators add their own named
+// presets and switch the active one through the admin API; the choice is saved to
+// File and read by the scraper at the start of every run, so the quota strategy
+// changes without a code edit or redeploy.
+
+// File holds the custom presets and the active preset name inside the data directory
+const File = "scraper_settings.json"
+
+// Validation error codes
+const (
+	ErrCodeInvalidName     = "invalid_name"
+	ErrCodeBuiltinPreset   = "builtin_preset"
+	ErrCodeInvalidSettings = "invalid_settings"
+	ErrCodeUnknownPreset   = "unknown_preset"
+	ErrCodeActiveDeleted   = "active_preset_deleted"
+)
+
+const (
+	// MaxVideosPerQuery caps a query's videos at ten search.list pages
+	MaxVideosPerQuery = 10 * config.YouTubeSearchPageSize
+	// MaxCommentsPerVideo caps a video's comments at ten commentThreads.list pages
+	MaxCommentsPerVideo = 10 * config.YouTubeCommentPageSize
+	// MaxCommentsPerThread is the most comments one Reddit thread request returns
+	MaxCommentsPerThread = 100
+	// MaxCustomPresets caps the operator-defined presets
+	MaxCustomPresets = 20
+)
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,39}$`)
+
+// Preset is a named set of scraper settings
+type Preset struct {
+	Name           string                 `json:"name"`
+	Builtin        bool                   `json:"builtin"`
+	Settings       config.ScraperSettings `json:"settings"`
+	EstimatedQuota int                    `json:"estimated_quota"` // YouTube quota units per run
+}
+
+// State is the active preset and every preset to choose from
+type State struct {
+	Active   string                 `json:"active"`
+	Settings config.ScraperSettings `json:"settings"` // The active preset's settings
+	Presets  []Preset               `json:"presets"`  // Built-in presets first, then custom ones by name
+}
+
+// Update changes the custom presets and/or the active preset. A preset set to null
+// is deleted; the active preset is checked after the preset changes are applied.
+type Update struct {
+	Active  string                             `json:"active,omitempty"`
+	Presets map[string]*config.ScraperSettings `json:"presets,omitempty"`
+}
+
+// ValidationError lists every rule an update broke
+type ValidationError struct {
+	Errors []models.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, len(e.Errors))
+	for i, v := range e.Errors {
+		codes[i] = v.Code
+	}
+	return fmt.Sprintf("invalid scraper settings: %s", strings.Join(codes, ", "))
+}
+
+// saved is what File holds
+type saved struct {
+	Active string                            `json:"active"`
+	Custom map[string]config.ScraperSettings `json:"custom"`
+}
+
+// Store holds the presets and persists changes to a JSON file
+type Store struct {
+	path   string
+	active string
+	custom map[string]config.ScraperSettings
+	mu     sync.Mutex
+}
+
+// NewStore loads the presets from dataDir. Without the file the default preset is active.
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{
+		path:   filepath.Join(dataDir, File),
+		active: config.PresetDefault,
+		custom: make(map[string]config.ScraperSettings),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scraper settings: %w", err)
+	}
+	var file saved
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse scraper settings: %w", err)
+	}
+	if file.Custom != nil {
+		s.custom = file.Custom
+	}
+	if _, ok := s.lookup(file.Active); !ok {
+		return nil, fmt.Errorf("scraper settings: active preset %q does not exist", file.Active)
+	}
+	s.active = file.Active
+	return s, nil
+}
+
+// Active returns the active preset's name and settings
+func (s *Store) Active() (string, config.ScraperSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, _ := s.lookup(s.active)
+	return s.active, settings
+}
+
+// State returns the active preset and every preset
+func (s *Store) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state()
+}
+
+// Apply validates and saves an update, returning the new state
+func (s *Store) Apply(update Update) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []models.ValidationError
+	add := func(code, field, format string, args ...any) {
+		errs = append(errs, models.ValidationError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	builtin := config.BuiltinPresets()
+	custom := maps.Clone(s.custom)
+	for name, settings := range update.Presets {
+		field := "presets." + name
+		if !namePattern.MatchString(name) {
+			add(ErrCodeInvalidName, field, "preset names must be lowercase letters, digits, - and _, starting with a letter (max 40)")
+			continue
+		}
+		if _, ok := builtin[name]; ok {
+			add(ErrCodeBuiltinPreset, field, "%s is a built-in preset and can't be changed", name)
+			continue
+		}
+		if settings == nil {
+			delete(custom, name)
+			continue
+		}
+		for _, msg := range check(*settings) {
+			add(ErrCodeInvalidSettings, field, "%s", msg)
+		}
+		custom[name] = *settings
+	}
+	if len(custom) > MaxCustomPresets {
+		add(ErrCodeInvalidSettings, "presets", "at most %d custom presets can be saved", MaxCustomPresets)
+	}
+
+	active := s.active
+	if update.Active != "" {
+		active = update.Active
+	}
+	_, isBuiltin := builtin[active]
+	_, isCustom := custom[active]
+	switch {
+	case isBuiltin || isCustom:
+	case update.Active != "":
+		add(ErrCodeUnknownPreset, "active", "no preset named %q", active)
+	default:
+		add(ErrCodeActiveDeleted, "active", "%s is the active preset; activate another before deleting it", active)
+	}
+
+	if len(errs) > 0 {
+		return State{}, &ValidationError{Errors: errs}
+	}
+
+	data, err := json.MarshalIndent(saved{Active: active, Custom: custom}, "", "  ")
+	if err != nil {
+		return State{}, fmt.Errorf("failed to marshal scraper settings: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return State{}, fmt.Errorf("failed to write scraper settings: %w", err)
+	}
+	s.active, s.custom = active, custom
+	return s.state(), nil
+}
+
+// state builds the State. Must be called with s.mu held.
+func (s *Store) state() State {
+	settings, _ := s.lookup(s.active)
+	state := State{Active: s.active, Settings: settings, Presets: []Preset{}}
+
+	builtin := config.BuiltinPresets()
+	for _, name := range []string{config.PresetDefault, config.PresetAggressive, config.PresetLight} {
+		state.Presets = append(state.Presets, preset(name, true, builtin[name]))
+	}
+	names := make([]string, 0, len(s.custom))
+	for name := range s.custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		state.Presets = append(state.Presets, preset(name, false, s.custom[name]))
+	}
+	return state
+}
+
+// lookup finds a built-in or custom preset by name. Must be called with s.mu held.
+func (s *Store) lookup(name string) (config.ScraperSettings, bool) {
+	if settings, ok := config.BuiltinPresets()[name]; ok {
+		return settings, true
+	}
+	settings, ok := s.custom[name]
+	return settings, ok
+}
+
+func preset(name string, builtin bool, settings config.ScraperSettings) Preset {
+	return Preset{Name: name, Builtin: builtin, Settings: settings, EstimatedQuota: settings.CalculateQuota()}
+}
+
+// check returns what is wrong with a set of settings
+func check(s config.ScraperSettings) []string {
+	var problems []string
+	if s.VideosPerQuery < 1 || s.VideosPerQuery > MaxVideosPerQuery {
+		problems = append(problems, fmt.Sprintf("videos_per_query must be 1 to %d", MaxVideosPerQuery))
+	}
+	if s.CommentsPerVideo < 1 || s.CommentsPerVideo > MaxCommentsPerVideo {
+		problems = append(problems, fmt.Sprintf("comments_per_video must be 1 to %d", MaxCommentsPerVideo))
+	}
+	if s.MaxQueries < 0 || s.DailyQuota < 0 || s.MinVideoSeconds < 0 || s.MaxVideoSeconds < 0 || s.MaxPages < 0 {
+		problems = append(problems, "max_queries, daily_quota, min_video_seconds, max_video_seconds and max_pages can't be negative")
+	}
+	if s.ThreadDepth < 0 || s.ThreadDepth > config.MaxThreadDepth {
+		problems = append(problems, fmt.Sprintf("thread_depth must be 0 to %d", config.MaxThreadDepth))
+	}
+	if s.CommentsPerThread < 0 || s.CommentsPerThread > MaxCommentsPerThread {
+		problems = append(problems, fmt.Sprintf("comments_per_thread must be 0 to %d", MaxCommentsPerThread))
+	}
+	if s.MaxVideoSeconds > 0 && s.MaxVideoSeconds < s.MinVideoSeconds {
+		problems = append(problems, "max_video_seconds must be at least min_video_seconds")
+	}
+	return problems
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ============================================
+// ATTESTATION QUEUE
+// ============================================
+
+const (
+	// DefaultAttestationWindow is how long approved resolutions wait before a batch is submitted
+	DefaultAttestationWindow = time.Hour
+	// MaxAttestationAttempts is how many batches try a resolution before it is moved to the failed list
+	MaxAttestationAttempts = 5
+)
+
+// QueuedAttestation is a resolution waiting for the next batch
+type QueuedAttestation struct {
+	ResolutionID string     `json:"resolution_id"`
+	QueuedAt     time.Time  `json:"queued_at"`
+	Attempts     int        `json:"attempts"`
+	LastError    string     `json:"last_error,omitempty"`
+	FailedAt     *time.Time `json:"failed_at,omitempty"` // Set when it was given up on
+}
+
+// AttestationQueue collects approved resolutions and submits them together once
+// per window. Submissions are sequential from a single goroutine, so transactions
+// from the attestor wallet never race for a nonce and gas spend is spread out.
+// Resolutions that fail permanently (no longer attestable, reverted) or
+// MaxAttestationAttempts times are moved to a failed list until enqueued again.
+type AttestationQueue struct {
+	rs       *ResolutionService
+	window   time.Duration
+	pending  []*QueuedAttestation
+	inFlight map[string]*QueuedAttestation // Taken by the running Flush
+	failed   []*QueuedAttestation
+	nextRun  time.Time
+	mu       sync.Mutex
+}
+
+// NewAttestationQueue creates a queue that flushes every window
+func NewAttestationQueue(rs *ResolutionService, window time.Duration) *AttestationQueue {
+	if window <= 0 {
+		window = DefaultAttestationWindow
+	}
+	return &AttestationQueue{
+		rs:       rs,
+		window:   window,
+		inFlight: make(map[string]*QueuedAttestation),
+		nextRun:  time.Now().Add(window),
+	}
+}
+
+// NewAttestationQueueFromEnv creates a queue when ATTESTATION_BATCH_WINDOW is set
+// (e.g. "1h", "15m"). Returns nil otherwise, meaning attest immediately.
+func NewAttestationQueueFromEnv(rs *ResolutionService) (*AttestationQueue, error) {
+	v := os.Getenv("ATTESTATION_BATCH_WINDOW")
+	if v == "" {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(v)
+	if err != nil || window <= 0 {
+		return nil, fmt.Errorf("invalid ATTESTATION_BATCH_WINDOW %q: must be a positive duration", v)
+	}
+	return NewAttestationQueue(rs, window), nil
+}
+
+// Enqueue schedules an approved (verified) resolution for the next batch.
+// Enqueuing a resolution that is already queued or being submitted is a no-op;
+// enqueuing a failed one retries it with a fresh count of attempts.
+func (q *AttestationQueue) Enqueue(resolutionID string) (*QueuedAttestation, time.Time, error) {
+	resolution, err := q.rs.GetResolution(resolutionID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if resolution.Attestation != nil {
+		return nil, time.Time{}, fmt.Errorf("resolution already attested: %s", resolutionID)
+	}
+	if resolution.Status != "verified" {
+		return nil, time.Time{}, fmt.Errorf("resolution %s is %s; only verified resolutions can be queued", resolutionID, resolution.Status)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if item, ok := q.inFlight[resolutionID]; ok {
+		copied := *item
+		return &copied, q.nextRun, nil
+	}
+	for _, item := range q.pending {
+		if item.ResolutionID == resolutionID {
+			copied := *item
+			return &copied, q.nextRun, nil
+		}
+	}
+	q.failed = slices.DeleteFunc(q.failed, func(item *QueuedAttestation) bool { return item.ResolutionID == resolutionID })
+	item := &QueuedAttestation{ResolutionID: resolutionID, QueuedAt: time.Now()}
+	q.pending = append(q.pending, item)
+	copied := *item
+	return &copied, q.nextRun, nil
+}
+
+// Pending returns the queued attestations, including any being submitted, and when the next batch runs
+func (q *AttestationQueue) Pending() ([]QueuedAttestation, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]QueuedAttestation, 0, len(q.inFlight)+len(q.pending))
+	for _, item := range q.inFlight {
+		items = append(items, *item)
+	}
+	slices.SortFunc(items, func(a, b QueuedAttestation) int { return a.QueuedAt.Compare(b.QueuedAt) })
+	for _, item := range q.pending {
+		items = append(items, *item)
+	}
+	return items, q.nextRun
+}
+
+// Failed returns the attestations given up on, oldest failure first
+func (q *AttestationQueue) Failed() []QueuedAttestation {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]QueuedAttestation, len(q.failed))
+	for i, item := range q.failed {
+		items[i] = *item
+	}
+	return items
+}
+
+// Run flushes the queue every window until ctx is cancelled
+func (q *AttestationQueue) Run(ctx context.Context) {
+	ticker := time.NewTicker(q.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.Flush(ctx)
+		}
+	}
+}
+
+// Flush submits every queued attestation, one transaction at a time.
+// Failures stay queued for the next window with their error recorded, unless they
+// are permanent or out of attempts, which moves them to the failed list.
+func (q *AttestationQueue) Flush(ctx context.Context) (attested int, failed int) {
+	q.mu.Lock()
+	batch := q.pending
+	q.pending = nil
+	for _, item := range batch {
+		q.inFlight[item.ResolutionID] = item
+	}
+	q.nextRun = time.Now().Add(q.window)
+	q.mu.Unlock()
+
+	if len(batch) == 0 {
+		return 0, 0
+	}
+	fmt.Printf("⛓️  Submitting attestation batch of %d\n", len(batch))
+
+	var retry, dead []*QueuedAttestation
+	for _, item := range batch {
+		if ctx.Err() != nil {
+			retry = append(retry, item)
+			continue
+		}
+		q.mu.Lock()
+		item.Attempts++
+		q.mu.Unlock()
+		_, err := q.rs.AttestResolution(ctx, item.ResolutionID)
+		if err == nil {
+			attested++
+			continue
+		}
+
+		failed++
+		now := time.Now()
+		q.mu.Lock()
+		item.LastError = err.Error()
+		giveUp := permanentAttestationError(err) || item.Attempts >= MaxAttestationAttempts
+		if giveUp {
+			item.FailedAt = &now
+		}
+		q.mu.Unlock()
+		if giveUp {
+			dead = append(dead, item)
+			fmt.Printf("❌ Attestation for %s failed for good (attempt %d): %v\n", item.ResolutionID, item.Attempts, err)
+		} else {
+			retry = append(retry, item)
+			fmt.Printf("⚠️  Attestation for %s failed (attempt %d): %v\n", item.ResolutionID, item.Attempts, err)
+		}
+	}
+
+	q.mu.Lock()
+	for _, item := range batch {
+		delete(q.inFlight, item.ResolutionID)
+	}
+	q.pending = append(retry, q.pending...)
+	q.failed = append(q.failed, dead...)
+	q.mu.Unlock()
+	fmt.Printf("✅ Attestation batch done: %d attested, %d failed\n", attested, failed)
+	return attested, failed
+}
+
+// permanentAttestationError reports whether retrying an attestation can't help:
+// the resolution is no longer attestable, or the contract rejected it
+func permanentAttestationError(err error) bool {
+	return errors.Is(err, ErrNotAttestable) || errors.Is(err, ErrTransactionReverted) || errors.Is(err, ErrResolutionNotFound)
+}
@@ -0,0 +1,42 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/api/apitest"
+	"github.com/tasnint/coinsights/internal/services"
+)
+
+// TestAttestationQueueGivesUp checks a resolution that keeps failing moves to the
+// failed list after MaxAttestationAttempts, and that enqueuing it again retries it
+func TestAttestationQueueGivesUp(t *testing.T) {
+	rs, fixtures := apitest.NewFakeResolutionService(t, nil) // No chain: every attempt fails
+	queue := services.NewAttestationQueue(rs, time.Hour)
+
+	if _, _, err := queue.Enqueue(fixtures.Resolution); err != nil {
+		t.Fatal(err)
+	}
+	for attempt := 1; attempt <= services.MaxAttestationAttempts; attempt++ {
+		if attested, failed := queue.Flush(context.Background()); attested != 0 || failed != 1 {
+			t.Fatalf("attempt %d: %d attested, %d failed, want 0 and 1", attempt, attested, failed)
+		}
+	}
+
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("%d still pending after %d attempts, want 0", len(pending), services.MaxAttestationAttempts)
+	}
+	failed := queue.Failed()
+	if len(failed) != 1 || failed[0].ResolutionID != fixtures.Resolution || failed[0].FailedAt == nil {
+		t.Fatalf("failed list %+v, want the resolution with its failure time", failed)
+	}
+
+	item, _, err := queue.Enqueue(fixtures.Resolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Attempts != 0 || len(queue.Failed()) != 0 {
+		t.Errorf("requeued with %d attempts and %d failed, want a fresh start", item.Attempts, len(queue.Failed()))
+	}
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ATTESTOR KEY ROTATION
+// ============================================
+// Attestations are only as trustworthy as the key that signed them, so the key
+// has to be replaceable without orphaning past attestations. The registry records
+// which key was valid over which blocks; a rotation is a statement signed by both
+// the outgoing and incoming key, optionally announced on-chain. Verification then
+// accepts any attestor that was valid at the attestation's block.
+
+const (
+	// AttestorKeysFile is the attestor key registry inside the data directory
+	AttestorKeysFile = "attestor_keys.json"
+	// DefaultRotationOverlapBlocks keeps the old key valid after a rotation so attestations
+	// already in flight (or sent before the server restarts on the new key) still verify.
+	// About an hour on Base's 2s blocks.
+	DefaultRotationOverlapBlocks = 1800
+)
+
+// AttestorRegistry records attestor keys and the rotations between them, persisted to a JSON file
+type AttestorRegistry struct {
+	path      string
+	keys      []models.AttestorKey
+	rotations []models.KeyRotation
+	mu        sync.RWMutex
+}
+
+// attestorRegistryFile is the on-disk format of the registry
+type attestorRegistryFile struct {
+	Keys      []models.AttestorKey `json:"keys"`
+	Rotations []models.KeyRotation `json:"rotations"`
+}
+
+// NewAttestorRegistry loads the registry from dataDir (empty if the file doesn't exist yet)
+func NewAttestorRegistry(dataDir string) (*AttestorRegistry, error) {
+	r := &AttestorRegistry{path: filepath.Join(dataDir, AttestorKeysFile)}
+
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestor keys: %w", err)
+	}
+	var file attestorRegistryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse attestor keys: %w", err)
+	}
+	r.keys, r.rotations = file.Keys, file.Rotations
+	return r, nil
+}
+
+// Bootstrap registers address as valid since block 0 if the registry has no keys yet,
+// so deployments that predate the registry keep verifying their history
+func (r *AttestorRegistry) Bootstrap(address string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.keys) > 0 {
+		return nil
+	}
+	r.keys = append(r.keys, models.AttestorKey{
+		Address:      common.HexToAddress(address).Hex(),
+		RegisteredAt: time.Now(),
+	})
+	return r.save()
+}
+
+// Keys returns every registered key, oldest first
+func (r *AttestorRegistry) Keys() []models.AttestorKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]models.AttestorKey{}, r.keys...)
+}
+
+// Rotations returns every recorded rotation, oldest first
+func (r *AttestorRegistry) Rotations() []models.KeyRotation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]models.KeyRotation{}, r.rotations...)
+}
+
+// ValidAt returns the key an attestor held at block, if it was valid then
+func (r *AttestorRegistry) ValidAt(address string, block uint64) (models.AttestorKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if !strings.EqualFold(key.Address, address) || block < key.ValidFromBlock {
+			continue
+		}
+		if key.ValidUntilBlock == 0 || block <= key.ValidUntilBlock {
+			return key, true
+		}
+	}
+	return models.AttestorKey{}, false
+}
+
+// ApplyRotation checks a rotation's signatures, closes the previous key's window
+// and registers the new key
+func (r *AttestorRegistry) ApplyRotation(rotation models.KeyRotation) error {
+	if err := VerifyKeyRotation(rotation); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := -1
+	for i, key := range r.keys {
+		if strings.EqualFold(key.Address, rotation.PreviousAttestor) && key.ValidUntilBlock == 0 {
+			previous = i
+		}
+		if strings.EqualFold(key.Address, rotation.NewAttestor) {
+			return fmt.Errorf("attestor %s is already registered", rotation.NewAttestor)
+		}
+	}
+	if previous < 0 {
+		return fmt.Errorf("attestor %s is not a currently valid key", rotation.PreviousAttestor)
+	}
+	if rotation.EffectiveBlock <= r.keys[previous].ValidFromBlock {
+		return fmt.Errorf("rotation must take effect after block %d", r.keys[previous].ValidFromBlock)
+	}
+
+	r.keys[previous].ValidUntilBlock = rotation.PreviousValidUntilBlock
+	r.keys = append(r.keys, models.AttestorKey{
+		Address:        common.HexToAddress(rotation.NewAttestor).Hex(),
+		ValidFromBlock: rotation.EffectiveBlock,
+		RegisteredAt:   time.Now(),
+	})
+	r.rotations = append(r.rotations, rotation)
+	return r.save()
+}
+
+// save writes the registry to disk. Must be called with r.mu held.
+func (r *AttestorRegistry) save() error {
+	data, err := json.MarshalIndent(attestorRegistryFile{Keys: r.keys, Rotations: r.rotations}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestor keys: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write attestor keys: %w", err)
+	}
+	return nil
+}
+
+// KeyRotationStatement is the message both keys sign to approve a rotation
+func KeyRotationStatement(rotation models.KeyRotation) string {
+	return fmt.Sprintf("Coinsights attestor key rotation\n"+
+		"Chain ID: %d\nContract: %s\nPrevious attestor: %s\nNew attestor: %s\n"+
+		"Effective block: %d\nPrevious key valid until block: %d",
+		rotation.ChainID,
+		common.HexToAddress(rotation.ContractAddress).Hex(),
+		common.HexToAddress(rotation.PreviousAttestor).Hex(),
+		common.HexToAddress(rotation.NewAttestor).Hex(),
+		rotation.EffectiveBlock,
+		rotation.PreviousValidUntilBlock,
+	)
+}
+
+// VerifyKeyRotation checks that a rotation's statement matches its fields and was
+// signed by both the previous and the new attestor
+func VerifyKeyRotation(rotation models.KeyRotation) error {
+	if rotation.PreviousValidUntilBlock+1 < rotation.EffectiveBlock {
+		return fmt.Errorf("previous key must stay valid until the new key takes effect")
+	}
+	if rotation.Statement != KeyRotationStatement(rotation) {
+		return fmt.Errorf("rotation statement does not match its fields")
+	}
+	for _, signed := range []struct{ signer, signature string }{
+		{rotation.PreviousAttestor, rotation.PreviousSignature},
+		{rotation.NewAttestor, rotation.NewSignature},
+	} {
+		recovered, err := RecoverSigner([]byte(rotation.Statement), signed.signature)
+		if err != nil {
+			return err
+		}
+		if recovered != common.HexToAddress(signed.signer) {
+			return fmt.Errorf("rotation signature from %s recovers to %s", signed.signer, recovered.Hex())
+		}
+	}
+	return nil
+}
+
+// RecoverSigner returns the address that produced an EIP-191 signature (as made by SignMessage)
+func RecoverSigner(message []byte, signature string) (common.Address, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("invalid signature format")
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// ============================================
+// ROTATION ON THE BLOCKCHAIN SERVICE
+// ============================================
+
+// SetAttestorRegistry makes verification check attestors against the registry.
+// An empty registry is bootstrapped with the current attestor key.
+func (bs *BlockchainService) SetAttestorRegistry(registry *AttestorRegistry) error {
+	if err := registry.Bootstrap(bs.publicAddress.Hex()); err != nil {
+		return err
+	}
+	bs.attestors = registry
+	return nil
+}
+
+// AttestorRegistry returns the attestor key registry, or nil if none is configured
+func (bs *BlockchainService) AttestorRegistry() *AttestorRegistry {
+	return bs.attestors
+}
+
+// PrepareKeyRotation builds a rotation from the current attestor key to newKey, effective
+// from the next block, with the old key kept valid for overlapBlocks more. Both keys sign it.
+func (bs *BlockchainService) PrepareKeyRotation(
+	ctx context.Context,
+	newKey *ecdsa.PrivateKey,
+	overlapBlocks uint64,
+) (*models.KeyRotation, error) {
+	head, err := bs.client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block number: %w", err)
+	}
+
+	rotation := &models.KeyRotation{
+		ChainID:                 bs.chainConfig.ChainID,
+		ContractAddress:         bs.contractAddress.Hex(),
+		PreviousAttestor:        bs.publicAddress.Hex(),
+		NewAttestor:             crypto.PubkeyToAddress(newKey.PublicKey).Hex(),
+		EffectiveBlock:          head + 1,
+		PreviousValidUntilBlock: head + overlapBlocks,
+		CreatedAt:               time.Now(),
+	}
+	if rotation.NewAttestor == rotation.PreviousAttestor {
+		return nil, fmt.Errorf("new key is the current attestor key")
+	}
+	rotation.Statement = KeyRotationStatement(*rotation)
+
+	if rotation.PreviousSignature, err = bs.SignMessage([]byte(rotation.Statement)); err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(accounts.TextHash([]byte(rotation.Statement)), newKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Ethereum V convention
+	rotation.NewSignature = "0x" + hex.EncodeToString(sig)
+
+	return rotation, nil
+}
+
+// AnnounceKeyRotation publishes a rotation on-chain (AttestorRotated event), sent from
+// the previous attestor key, and sets its TransactionHash
+func (bs *BlockchainService) AnnounceKeyRotation(ctx context.Context, rotation *models.KeyRotation) error {
+	previousSig, err := hex.DecodeString(strings.TrimPrefix(rotation.PreviousSignature, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid previous signature: %w", err)
+	}
+	newSig, err := hex.DecodeString(strings.TrimPrefix(rotation.NewSignature, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid new signature: %w", err)
+	}
+
+	txData, err := bs.contractABI.Pack(
+		"announceKeyRotation",
+		common.HexToAddress(rotation.NewAttestor),
+		new(big.Int).SetUint64(rotation.EffectiveBlock),
+		new(big.Int).SetUint64(rotation.PreviousValidUntilBlock),
+		previousSig,
+		newSig,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pack transaction data: %w", err)
+	}
+
+	signedTx, err := bs.sendContractTx(ctx, txData)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := bs.waitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to get transaction receipt: %w", err)
+	}
+	if receipt.Status == 0 {
+		return ErrTransactionReverted
+	}
+
+	rotation.TransactionHash = signedTx.Hash().Hex()
+	return nil
+}
@@ -6,19 +6,25 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tasnint/coinsights/internal/cache"
 	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/retry"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -38,6 +44,31 @@ const ResolutionAttestationABI = `[
 		"stateMutability": "nonpayable",
 		"type": "function"
 	},
+	{
+		"inputs": [
+			{"internalType": "string", "name": "exchange", "type": "string"},
+			{"internalType": "string", "name": "issueCategory", "type": "string"},
+			{"internalType": "bytes32", "name": "evidenceHash", "type": "bytes32"},
+			{"internalType": "string", "name": "metadataURI", "type": "string"}
+		],
+		"name": "recordResolutionWithMetadata",
+		"outputs": [{"internalType": "uint256", "name": "attestationId", "type": "uint256"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"internalType": "address", "name": "newAttestor", "type": "address"},
+			{"internalType": "uint256", "name": "effectiveBlock", "type": "uint256"},
+			{"internalType": "uint256", "name": "previousValidUntilBlock", "type": "uint256"},
+			{"internalType": "bytes", "name": "previousSignature", "type": "bytes"},
+			{"internalType": "bytes", "name": "newSignature", "type": "bytes"}
+		],
+		"name": "announceKeyRotation",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
 	{
 		"inputs": [{"internalType": "bytes32", "name": "evidenceHash", "type": "bytes32"}],
 		"name": "verifyHash",
@@ -83,6 +114,29 @@ const ResolutionAttestationABI = `[
 		],
 		"name": "ResolutionRecorded",
 		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "uint256", "name": "attestationId", "type": "uint256"},
+			{"indexed": true, "internalType": "bytes32", "name": "evidenceHash", "type": "bytes32"},
+			{"indexed": false, "internalType": "string", "name": "metadataURI", "type": "string"}
+		],
+		"name": "ResolutionMetadata",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "internalType": "address", "name": "previousAttestor", "type": "address"},
+			{"indexed": true, "internalType": "address", "name": "newAttestor", "type": "address"},
+			{"indexed": false, "internalType": "uint256", "name": "effectiveBlock", "type": "uint256"},
+			{"indexed": false, "internalType": "uint256", "name": "previousValidUntilBlock", "type": "uint256"},
+			{"indexed": false, "internalType": "bytes", "name": "previousSignature", "type": "bytes"},
+			{"indexed": false, "internalType": "bytes", "name": "newSignature", "type": "bytes"}
+		],
+		"name": "AttestorRotated",
+		"type": "event"
 	}
 ]`
 
@@ -98,8 +152,24 @@ type BlockchainService struct {
 	contractABI     abi.ABI
 	privateKey      *ecdsa.PrivateKey
 	publicAddress   common.Address
+	verifyCache     *cache.Cache      // Evidence hash -> *models.VerificationResponse
+	readCache       *cache.Cache      // Direct contract reads: attestations by ID and the count
+	metadataURI     string            // Optional template, e.g. "https://api.example.com/api/resolutions/{id}"
+	gasCeiling      uint64            // Most gas one transaction may use (ATTESTATION_GAS_CEILING)
+	attestors       *AttestorRegistry // Optional: attestor keys valid per block, checked on verification
+	sendMu          sync.Mutex        // Held from picking a nonce until the transaction is sent
 }
 
+// Verification cache TTLs. Deeply confirmed attestations can't be reorged away,
+// so they are cached much longer than fresh ones or misses (which may be attested any moment).
+const (
+	VerifyCacheMissTTL    = 10 * time.Second
+	VerifyCacheShallowTTL = 30 * time.Second
+	VerifyCacheFinalTTL   = time.Hour
+	// FinalityDepth is the confirmations after which an attestation is treated as final
+	FinalityDepth = 64
+)
+
 // NewBlockchainService creates a new blockchain service
 func NewBlockchainService() (*BlockchainService, error) {
 	// Get chain configuration
@@ -159,6 +229,11 @@ func NewBlockchainService() (*BlockchainService, error) {
 	}
 	publicAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
 
+	gasCeiling, err := GasCeilingFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	return &BlockchainService{
 		client:          client,
 		chainConfig:     chainConfig,
@@ -166,6 +241,10 @@ func NewBlockchainService() (*BlockchainService, error) {
 		contractABI:     parsedABI,
 		privateKey:      privateKey,
 		publicAddress:   publicAddress,
+		verifyCache:     cache.New(VerifyCacheMissTTL),
+		readCache:       cache.New(ChainCountCacheTTL),
+		metadataURI:     os.Getenv("ATTESTATION_METADATA_URI"),
+		gasCeiling:      gasCeiling,
 	}, nil
 }
 
@@ -193,10 +272,15 @@ func (bs *BlockchainService) GetWalletAddress() string {
 // HashEvidence creates a Keccak256 hash of the resolution evidence
 // This is the hash that gets stored on-chain
 func (bs *BlockchainService) HashEvidence(evidence *models.ResolutionEvidence) (string, error) {
-	// Serialize evidence to canonical JSON
-	jsonBytes, err := json.Marshal(evidence)
+	return EvidenceHash(evidence)
+}
+
+// EvidenceHash is HashEvidence without a blockchain connection, for mirrors and
+// verifiers recomputing a hash from published evidence
+func EvidenceHash(evidence *models.ResolutionEvidence) (string, error) {
+	jsonBytes, err := CanonicalEvidence(evidence)
 	if err != nil {
-		return "", fmt.Errorf("failed to serialize evidence: %w", err)
+		return "", err
 	}
 
 	// Compute Keccak256 hash (same as Solidity's keccak256)
@@ -207,13 +291,23 @@ func (bs *BlockchainService) HashEvidence(evidence *models.ResolutionEvidence) (
 	return "0x" + hex.EncodeToString(hashBytes), nil
 }
 
+// CanonicalEvidence serializes evidence to the canonical JSON the evidence hash is
+// taken over; publishing exactly these bytes lets anyone re-hash them
+func CanonicalEvidence(evidence *models.ResolutionEvidence) ([]byte, error) {
+	jsonBytes, err := json.Marshal(evidence)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize evidence: %w", err)
+	}
+	return jsonBytes, nil
+}
+
 // HashEvidenceBytes returns the raw 32-byte hash
 func (bs *BlockchainService) HashEvidenceBytes(evidence *models.ResolutionEvidence) ([32]byte, error) {
 	var hashArray [32]byte
 
-	jsonBytes, err := json.Marshal(evidence)
+	jsonBytes, err := CanonicalEvidence(evidence)
 	if err != nil {
-		return hashArray, fmt.Errorf("failed to serialize evidence: %w", err)
+		return hashArray, err
 	}
 
 	hash := sha3.NewLegacyKeccak256()
@@ -223,10 +317,26 @@ func (bs *BlockchainService) HashEvidenceBytes(evidence *models.ResolutionEviden
 	return hashArray, nil
 }
 
+// SignMessage signs data with the attestor key using EIP-191 personal_sign,
+// so anyone can recover the signer with standard wallet tooling.
+// Returns the 65-byte signature as 0x-prefixed hex.
+func (bs *BlockchainService) SignMessage(data []byte) (string, error) {
+	hash := accounts.TextHash(data)
+	sig, err := crypto.Sign(hash, bs.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign message: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // Ethereum V convention
+	return "0x" + hex.EncodeToString(sig), nil
+}
+
 // ============================================
 // ON-CHAIN OPERATIONS
 // ============================================
 
+// ErrTransactionReverted is returned when a mined transaction failed
+var ErrTransactionReverted = errors.New("transaction reverted")
+
 // RecordAttestation records a resolution on the blockchain
 func (bs *BlockchainService) RecordAttestation(
 	ctx context.Context,
@@ -241,54 +351,38 @@ func (bs *BlockchainService) RecordAttestation(
 	}
 	fmt.Printf("   Evidence hash: 0x%x\n", evidenceHash)
 
-	// Get nonce
-	nonce, err := bs.client.PendingNonceAt(ctx, bs.publicAddress)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %w", err)
-	}
-
-	// Get gas price
-	gasPrice, err := bs.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	// Build transaction data. With a metadata URI the contract also emits
+	// ResolutionMetadata, so on-chain consumers can find the full evidence.
+	metadataURI := bs.MetadataURI(resolution, evidenceHash)
+	var txData []byte
+	if metadataURI != "" {
+		fmt.Printf("   Metadata URI: %s\n", metadataURI)
+		txData, err = bs.contractABI.Pack(
+			"recordResolutionWithMetadata",
+			resolution.Exchange,
+			resolution.IssueCategory,
+			evidenceHash,
+			metadataURI,
+		)
+	} else {
+		txData, err = bs.contractABI.Pack(
+			"recordResolution",
+			resolution.Exchange,
+			resolution.IssueCategory,
+			evidenceHash,
+		)
 	}
-
-	// Build transaction data
-	txData, err := bs.contractABI.Pack(
-		"recordResolution",
-		resolution.Exchange,
-		resolution.IssueCategory,
-		evidenceHash,
-	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack transaction data: %w", err)
 	}
 
-	// Estimate gas
-	gasLimit := uint64(150000) // Conservative estimate
-
-	// Create transaction
-	tx := types.NewTransaction(
-		nonce,
-		bs.contractAddress,
-		big.NewInt(0), // No ETH value
-		gasLimit,
-		gasPrice,
-		txData,
-	)
-
-	// Sign transaction
-	chainID := big.NewInt(bs.chainConfig.ChainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), bs.privateKey)
+	// Build, sign and send an EIP-1559 transaction; gas estimation fails here, before
+	// anything is sent, if the call would revert
+	signedTx, err := bs.sendContractTx(ctx, txData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign transaction: %w", err)
-	}
-
-	// Send transaction
-	err = bs.client.SendTransaction(ctx, signedTx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send transaction: %w", err)
+		return nil, err
 	}
+	fmt.Printf("   Gas limit: %d, max fee: %s wei, priority fee: %s wei\n", signedTx.Gas(), signedTx.GasFeeCap(), signedTx.GasTipCap())
 
 	txHash := signedTx.Hash().Hex()
 	fmt.Printf("   Transaction sent: %s\n", txHash)
@@ -300,11 +394,13 @@ func (bs *BlockchainService) RecordAttestation(
 	}
 
 	if receipt.Status == 0 {
-		return nil, fmt.Errorf("transaction reverted")
+		return nil, ErrTransactionReverted
 	}
 
 	// Get block timestamp
-	block, err := bs.client.BlockByNumber(ctx, receipt.BlockNumber)
+	block, err := withRPCRetry(ctx, func() (*types.Block, error) {
+		return bs.client.BlockByNumber(ctx, receipt.BlockNumber)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block: %w", err)
 	}
@@ -317,7 +413,10 @@ func (bs *BlockchainService) RecordAttestation(
 		ChainID:         bs.chainConfig.ChainID,
 		ContractAddress: bs.contractAddress.Hex(),
 		EvidenceHash:    "0x" + hex.EncodeToString(evidenceHash[:]),
+		Exchange:        resolution.Exchange,
+		IssueCategory:   resolution.IssueCategory,
 		Attestor:        bs.publicAddress.Hex(),
+		MetadataURI:     metadataURI,
 		ExplorerURL:     fmt.Sprintf("%s/tx/%s", bs.chainConfig.ExplorerURL, txHash),
 		Verified:        true,
 	}
@@ -325,16 +424,105 @@ func (bs *BlockchainService) RecordAttestation(
 	// Try to get attestation ID from logs
 	attestation.ID = bs.parseAttestationID(receipt.Logs)
 
+	// A cached "not found" for this hash, and the cached count, are now stale
+	bs.verifyCache.Delete(attestation.EvidenceHash)
+	bs.readCache.Delete(chainCountKey)
+
 	fmt.Printf("   ✅ Attestation recorded! Block: %d\n", attestation.BlockNumber)
 	fmt.Printf("   🔗 Explorer: %s\n", attestation.ExplorerURL)
 
 	return attestation, nil
 }
 
-// VerifyAttestation verifies an attestation exists on-chain
+// Attestation gas budget for quotes. Sent attestations use eth_estimateGas; quotes
+// can't estimate a call for a resolution that doesn't exist yet, so they use this
+// conservative upper bound (capped at the gas ceiling) instead.
+const (
+	AttestationGasLimit   = 150000
+	MetadataGasPerURIByte = 50 // Event data for the metadata URI
+)
+
+// attestationGasLimit returns the gas an attestation emitting metadataURI is quoted with
+func (bs *BlockchainService) attestationGasLimit(metadataURI string) uint64 {
+	limit := uint64(AttestationGasLimit)
+	if metadataURI != "" {
+		limit += uint64(len(metadataURI)) * MetadataGasPerURIByte
+	}
+	return min(limit, bs.gasCeiling)
+}
+
+// AttestationGasQuote returns the gas limit and current fees the next attestation
+// would be sent with. The metadata URI is sized from the ATTESTATION_METADATA_URI template.
+func (bs *BlockchainService) AttestationGasQuote(ctx context.Context) (uint64, *FeeQuote, error) {
+	fees, err := bs.SuggestFees(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	return bs.attestationGasLimit(bs.metadataURI), fees, nil
+}
+
+// MetadataURI returns where on-chain consumers can fetch a resolution's evidence.
+// A URI already on the resolution (e.g. an IPFS CID pinned by the caller) wins; otherwise
+// the ATTESTATION_METADATA_URI template is filled in ({id} = resolution ID, {hash} =
+// evidence hash). Empty means the attestation is recorded without metadata.
+func (bs *BlockchainService) MetadataURI(resolution *models.Resolution, evidenceHash [32]byte) string {
+	if resolution.MetadataURI != "" {
+		return resolution.MetadataURI
+	}
+	if bs.metadataURI == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{id}", resolution.ID,
+		"{hash}", "0x"+hex.EncodeToString(evidenceHash[:]),
+	).Replace(bs.metadataURI)
+}
+
+// VerifyAttestation verifies an attestation exists on-chain.
+// Results are cached by evidence hash; the TTL grows with confirmation depth.
 func (bs *BlockchainService) VerifyAttestation(
 	ctx context.Context,
 	evidenceHash string,
+) (*models.VerificationResponse, error) {
+	key := "0x" + strings.ToLower(strings.TrimPrefix(evidenceHash, "0x"))
+	if cached, ok := bs.verifyCache.Get(key); ok {
+		response := copyVerification(cached.(*models.VerificationResponse))
+		response.Cached = true
+		return response, nil
+	}
+
+	response, err := bs.verifyOnChain(ctx, evidenceHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := VerifyCacheMissTTL
+	if response.OnChain && response.Attestation != nil {
+		ttl = VerifyCacheShallowTTL
+		if head, err := bs.client.BlockNumber(ctx); err == nil && head >= response.Attestation.BlockNumber &&
+			head-response.Attestation.BlockNumber+1 >= FinalityDepth {
+			ttl = VerifyCacheFinalTTL
+		}
+	}
+	bs.verifyCache.SetWithTTL(key, copyVerification(response), ttl)
+
+	return response, nil
+}
+
+// copyVerification copies a response so cached entries can't be mutated by callers
+func copyVerification(response *models.VerificationResponse) *models.VerificationResponse {
+	copied := *response
+	if response.Attestation != nil {
+		attestation := *response.Attestation
+		copied.Attestation = &attestation
+	}
+	return &copied
+}
+
+// verifyOnChain calls verifyHash on the contract and loads the matching attestation
+func (bs *BlockchainService) verifyOnChain(
+	ctx context.Context,
+	evidenceHash string,
 ) (*models.VerificationResponse, error) {
 	// Convert hex string to bytes32
 	hashBytes, err := hex.DecodeString(strings.TrimPrefix(evidenceHash, "0x"))
@@ -351,10 +539,7 @@ func (bs *BlockchainService) VerifyAttestation(
 		return nil, fmt.Errorf("failed to pack call data: %w", err)
 	}
 
-	result, err := bs.client.CallContract(ctx, ethereum.CallMsg{
-		To:   &bs.contractAddress,
-		Data: callData,
-	}, nil)
+	result, err := bs.callContract(ctx, callData)
 	if err != nil {
 		return nil, fmt.Errorf("contract call failed: %w", err)
 	}
@@ -385,6 +570,17 @@ func (bs *BlockchainService) VerifyAttestation(
 		if err == nil {
 			response.Attestation = attestation
 			response.TimestampValid = true
+
+			// Any key valid at the attestation's block counts, so rotations don't orphan history
+			if bs.attestors != nil {
+				_, authorized := bs.attestors.ValidAt(attestation.Attestor, attestation.BlockNumber)
+				response.AttestorAuthorized = &authorized
+				if !authorized {
+					response.Verified = false
+					response.Message += fmt.Sprintf(", but attestor %s was not a valid key at block %d",
+						attestation.Attestor, attestation.BlockNumber)
+				}
+			}
 		}
 	} else {
 		response.Message = "Hash not found on-chain"
@@ -403,10 +599,7 @@ func (bs *BlockchainService) GetAttestationByID(
 		return nil, fmt.Errorf("failed to pack call data: %w", err)
 	}
 
-	result, err := bs.client.CallContract(ctx, ethereum.CallMsg{
-		To:   &bs.contractAddress,
-		Data: callData,
-	}, nil)
+	result, err := bs.callContract(ctx, callData)
 	if err != nil {
 		return nil, fmt.Errorf("contract call failed: %w", err)
 	}
@@ -420,8 +613,8 @@ func (bs *BlockchainService) GetAttestationByID(
 	previousHash := outputs[1].([32]byte)
 	timestamp := outputs[2].(*big.Int)
 	blockNumber := outputs[3].(*big.Int)
-	// exchange := outputs[4].(string) // We could use these if needed
-	// issueCategory := outputs[5].(string)
+	exchange := outputs[4].(string)
+	issueCategory := outputs[5].(string)
 	attestor := outputs[6].(common.Address)
 
 	return &models.Attestation{
@@ -432,6 +625,8 @@ func (bs *BlockchainService) GetAttestationByID(
 		ContractAddress: bs.contractAddress.Hex(),
 		EvidenceHash:    "0x" + hex.EncodeToString(evidenceHash[:]),
 		PreviousHash:    "0x" + hex.EncodeToString(previousHash[:]),
+		Exchange:        exchange,
+		IssueCategory:   issueCategory,
 		Attestor:        attestor.Hex(),
 		ExplorerURL:     fmt.Sprintf("%s/address/%s", bs.chainConfig.ExplorerURL, bs.contractAddress.Hex()),
 		Verified:        true,
@@ -445,10 +640,7 @@ func (bs *BlockchainService) GetAttestationCount(ctx context.Context) (uint64, e
 		return 0, fmt.Errorf("failed to pack call data: %w", err)
 	}
 
-	result, err := bs.client.CallContract(ctx, ethereum.CallMsg{
-		To:   &bs.contractAddress,
-		Data: callData,
-	}, nil)
+	result, err := bs.callContract(ctx, callData)
 	if err != nil {
 		return 0, fmt.Errorf("contract call failed: %w", err)
 	}
@@ -466,6 +658,48 @@ func (bs *BlockchainService) GetAttestationCount(ctx context.Context) (uint64, e
 // HELPER FUNCTIONS
 // ============================================
 
+// rpcRetry backs off briefly: RPC reads are quick, and a provider that is still
+// failing after a few seconds is better reported than waited on
+var rpcRetry = retry.Policy{
+	MaxRetries: 2,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+	Jitter:     250 * time.Millisecond,
+}
+
+// rpcRetryable retries network failures and rate-limited or failing RPC endpoints,
+// not errors the node answered with - a reverted call stays reverted
+func rpcRetryable(err error) bool {
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return retry.Status(httpErr.StatusCode)
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// withRPCRetry calls fn, a read from the RPC node, retrying transient failures.
+// Transactions aren't sent through it: a resend is the attestation queue's call.
+func withRPCRetry[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var out T
+	err := retry.Do(ctx, rpcRetry, rpcRetryable, func(int) error {
+		var err error
+		out, err = fn()
+		return err
+	})
+	return out, err
+}
+
+// callContract runs a read-only call against the attestation contract
+func (bs *BlockchainService) callContract(ctx context.Context, data []byte) ([]byte, error) {
+	return withRPCRetry(ctx, func() ([]byte, error) {
+		return bs.client.CallContract(ctx, ethereum.CallMsg{
+			To:   &bs.contractAddress,
+			Data: data,
+		}, nil)
+	})
+}
+
 // waitForReceipt waits for a transaction receipt with timeout
 func (bs *BlockchainService) waitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
 	timeout := time.After(2 * time.Minute)
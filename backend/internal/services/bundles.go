@@ -0,0 +1,210 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// EVIDENCE BUNDLES
+// ============================================
+// AssembleEvidence measures an issue's complaints before and during a resolution
+// window and packages everything into one EvidenceBundle. The bundle's Evidence is
+// what gets submitted, hashed and attested; the bundle itself is stored under that
+// hash, so the artifact behind any attestation can be fetched and checked.
+
+const (
+	// EvidenceBundlesFile holds evidence bundles inside the data directory
+	EvidenceBundlesFile = "evidence_bundles.json"
+	// BundleSamplesPerPeriod is how many sample complaints a bundle takes from each period
+	BundleSamplesPerPeriod = 5
+)
+
+// EvidenceStore keeps evidence bundles by evidence hash, persisted to a JSON file
+type EvidenceStore struct {
+	path    string
+	bundles map[string]*models.EvidenceBundle // evidence hash -> bundle
+	pinner  *IPFSPinner                       // Optional: pins attested evidence to IPFS
+	mu      sync.RWMutex
+}
+
+// NewEvidenceStore loads evidence bundles from dataDir (empty if the file doesn't exist yet)
+func NewEvidenceStore(dataDir string) (*EvidenceStore, error) {
+	s := &EvidenceStore{
+		path:    filepath.Join(dataDir, EvidenceBundlesFile),
+		bundles: make(map[string]*models.EvidenceBundle),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read evidence bundles: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.bundles); err != nil {
+		return nil, fmt.Errorf("failed to parse evidence bundles: %w", err)
+	}
+	if s.bundles == nil {
+		s.bundles = make(map[string]*models.EvidenceBundle)
+	}
+	return s, nil
+}
+
+// Get returns the bundle stored under an evidence hash
+func (s *EvidenceStore) Get(evidenceHash string) (*models.EvidenceBundle, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bundle, ok := s.bundles[strings.ToLower(evidenceHash)]
+	return bundle, ok
+}
+
+// Put stores a bundle under its evidence hash and writes the store to disk.
+// Identical evidence hashes identically, so re-assembling a bundle replaces it.
+func (s *EvidenceStore) Put(bundle *models.EvidenceBundle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bundles[strings.ToLower(bundle.EvidenceHash)] = bundle
+	data, err := json.MarshalIndent(s.bundles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal evidence bundles: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write evidence bundles: %w", err)
+	}
+	return nil
+}
+
+// SetEvidenceStore makes AssembleEvidence store the bundles it builds
+func (rs *ResolutionService) SetEvidenceStore(store *EvidenceStore) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.evidence = store
+}
+
+// EvidenceBundle returns the stored bundle behind an evidence hash
+func (rs *ResolutionService) EvidenceBundle(evidenceHash string) (*models.EvidenceBundle, error) {
+	rs.mu.RLock()
+	store := rs.evidence
+	rs.mu.RUnlock()
+
+	if store == nil {
+		return nil, fmt.Errorf("evidence store not configured")
+	}
+	bundle, ok := store.Get(evidenceHash)
+	if !ok {
+		return nil, fmt.Errorf("evidence bundle not found: %s", evidenceHash)
+	}
+	return bundle, nil
+}
+
+// EvidenceScope narrows an evidence bundle to the complaints about one app version
+// and/or OS, e.g. to show crashes fixed in 12.3 on Android. Empty fields match any.
+type EvidenceScope struct {
+	AppVersion string
+	OS         string
+}
+
+// AssembleEvidence builds the evidence bundle for an issue over the window [start, end):
+// complaints in the window are compared with an equally long period just before it.
+// With a scope, both periods count only the complaints about that version and OS.
+// The bundle is stored under its evidence hash when an evidence store is configured.
+func (rs *ResolutionService) AssembleEvidence(issueID string, complaints []models.Complaint, start, end time.Time, scope EvidenceScope) (*models.EvidenceBundle, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("window end must be after its start")
+	}
+	issue, err := rs.GetIssue(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	rs.mu.RLock()
+	store := rs.evidence
+	criteria := rs.criteria
+	rs.mu.RUnlock()
+
+	complaints = analyzer.InVersion(complaints, scope.AppVersion, scope.OS)
+	beforeStart := start.Add(-end.Sub(start))
+	beforeComplaints := analyzer.InPeriod(complaints, issue.Category, beforeStart, start)
+	afterComplaints := analyzer.InPeriod(complaints, issue.Category, start, end)
+
+	bundle := &models.EvidenceBundle{
+		IssueID:        issue.ID,
+		Exchange:       issue.Exchange,
+		Category:       issue.Category,
+		AppVersion:     scope.AppVersion,
+		OS:             scope.OS,
+		Before:         analyzer.SummarizePeriod(beforeComplaints, beforeStart, start),
+		After:          analyzer.SummarizePeriod(afterComplaints, start, end),
+		SentimentCurve: analyzer.SentimentCurve(append(beforeComplaints, afterComplaints...), beforeStart, end),
+		Sources:        make(map[string]int),
+		CreatedAt:      time.Now(),
+	}
+	bundle.SampleComplaints = append(
+		analyzer.SampleComplaints(beforeComplaints, BundleSamplesPerPeriod),
+		analyzer.SampleComplaints(afterComplaints, BundleSamplesPerPeriod)...)
+	for _, period := range []models.BundlePeriod{bundle.Before, bundle.After} {
+		for source, n := range period.Sources {
+			bundle.Sources[source] += n
+		}
+	}
+
+	bundle.Evidence = bundleEvidence(bundle, criteria)
+	hash, err := EvidenceHash(&bundle.Evidence)
+	if err != nil {
+		return nil, err
+	}
+	bundle.EvidenceHash = hash
+
+	if store != nil {
+		if err := store.Put(bundle); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+// bundleEvidence derives the attestable summary from a bundle's measurements
+func bundleEvidence(bundle *models.EvidenceBundle, criteria models.ResolutionCriteria) models.ResolutionEvidence {
+	evidence := models.ResolutionEvidence{
+		ComplaintsBefore: bundle.Before.Complaints,
+		ComplaintsAfter:  bundle.After.Complaints,
+		SampleComplaints: make([]string, len(bundle.SampleComplaints)),
+		DataSources:      make([]string, 0, len(bundle.Sources)),
+		MeasurementStart: bundle.After.Start,
+		MeasurementEnd:   bundle.After.End,
+		SourcesBefore:    bundle.Before.Sources,
+		SourcesAfter:     bundle.After.Sources,
+		AppVersion:       bundle.AppVersion,
+		OS:               bundle.OS,
+	}
+	if evidence.ComplaintsBefore > 0 && evidence.ComplaintsAfter < evidence.ComplaintsBefore {
+		decrease := float64(evidence.ComplaintsBefore-evidence.ComplaintsAfter) / float64(evidence.ComplaintsBefore)
+		evidence.PercentageDecrease = math.Round(decrease*10000) / 10000
+	}
+	if bundle.Before.Scored > 0 && bundle.After.Scored > 0 {
+		shift := bundle.After.Sentiment - bundle.Before.Sentiment
+		evidence.SentimentShift = math.Max(-1, math.Min(1, math.Round(shift*10000)/10000))
+	}
+	for i, c := range bundle.SampleComplaints {
+		evidence.SampleComplaints[i] = c.ID
+	}
+	for source := range bundle.Sources {
+		evidence.DataSources = append(evidence.DataSources, source)
+	}
+	sort.Strings(evidence.DataSources)
+
+	evidence.AnalysisMethodology = SelectMethodology(&evidence, criteria).Render(&evidence)
+	return evidence
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// DIRECT CHAIN READS
+// ============================================
+// Attestations and the attestation count read straight from the contract, so the
+// public can check chain state through the API even when the local store is empty
+// or out of sync with it. Reads are cached like verifications: attestations until
+// they are final, the count only briefly since every attestation changes it.
+
+// ChainCountCacheTTL is how long the on-chain attestation count is cached
+const ChainCountCacheTTL = 15 * time.Second
+
+// ErrAttestationNotFound is returned for attestation IDs the contract hasn't assigned yet
+var ErrAttestationNotFound = errors.New("attestation does not exist on-chain")
+
+const chainCountKey = "count"
+
+// ChainAttestationCount returns the contract's attestation count
+func (bs *BlockchainService) ChainAttestationCount(ctx context.Context) (uint64, error) {
+	if cached, ok := bs.readCache.Get(chainCountKey); ok {
+		return cached.(uint64), nil
+	}
+	return bs.refreshChainCount(ctx)
+}
+
+// ChainAttestation returns an attestation as recorded on-chain
+func (bs *BlockchainService) ChainAttestation(ctx context.Context, id uint64) (*models.Attestation, error) {
+	key := "attestation:" + strconv.FormatUint(id, 10)
+	if cached, ok := bs.readCache.Get(key); ok {
+		attestation := *cached.(*models.Attestation)
+		return &attestation, nil
+	}
+
+	count, err := bs.ChainAttestationCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if id >= count {
+		// The cached count may predate the attestation; check again before giving up
+		if count, err = bs.refreshChainCount(ctx); err != nil {
+			return nil, err
+		}
+		if id >= count {
+			return nil, ErrAttestationNotFound
+		}
+	}
+
+	attestation, err := bs.GetAttestationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := VerifyCacheShallowTTL
+	if head, err := bs.client.BlockNumber(ctx); err == nil && head >= attestation.BlockNumber &&
+		head-attestation.BlockNumber+1 >= FinalityDepth {
+		ttl = VerifyCacheFinalTTL
+	}
+	cached := *attestation
+	bs.readCache.SetWithTTL(key, &cached, ttl)
+
+	return attestation, nil
+}
+
+// refreshChainCount reads the attestation count from the contract and caches it
+func (bs *BlockchainService) refreshChainCount(ctx context.Context) (uint64, error) {
+	count, err := bs.GetAttestationCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	bs.readCache.SetWithTTL(chainCountKey, count, ChainCountCacheTTL)
+	return count, nil
+}
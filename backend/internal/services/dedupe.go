@@ -0,0 +1,175 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ISSUE DEDUPLICATION
+// ============================================
+// An issue is a duplicate when an open issue for the same exchange and category
+// already exists under a similar title. CreateIssue refuses duplicates by default;
+// callers can merge the new report into the existing issue or create it anyway.
+
+// DuplicateTitleSimilarity is the title similarity (0-1) at which two issues for
+// the same exchange and category count as duplicates
+const DuplicateTitleSimilarity = 0.5
+
+// DuplicatePolicy says what CreateIssueWithPolicy does when a similar open issue exists
+type DuplicatePolicy string
+
+const (
+	DuplicateReject DuplicatePolicy = ""      // Default: fail with a *DuplicateIssueError
+	DuplicateMerge  DuplicatePolicy = "merge" // Fold the new issue into the existing one
+	DuplicateForce  DuplicatePolicy = "force" // Create the issue anyway
+)
+
+// DuplicateIssueError is returned when an issue would duplicate an open one
+type DuplicateIssueError struct {
+	Existing   *models.Issue
+	Similarity float64 // Title similarity, 0-1
+}
+
+func (e *DuplicateIssueError) Error() string {
+	return fmt.Sprintf("a similar open issue already exists: %s (%q)", e.Existing.ID, e.Existing.Title)
+}
+
+// severityOrder ranks severities so a merge keeps the more severe one
+var severityOrder = []string{"low", "medium", "high", "critical"}
+
+// CreateIssueWithPolicy creates an issue unless it duplicates an open one, in which
+// case policy decides. merged reports that the returned issue is the existing one.
+// The duplicate check and the create or merge happen under one lock, so two similar
+// reports arriving together can't both create an issue.
+func (rs *ResolutionService) CreateIssueWithPolicy(issue *models.Issue, policy DuplicatePolicy) (result *models.Issue, merged bool, err error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if policy != DuplicateForce {
+		if existing, similarity := rs.findDuplicate(issue); existing != nil {
+			if policy != DuplicateMerge {
+				return nil, false, &DuplicateIssueError{Existing: existing, Similarity: similarity}
+			}
+			return rs.mergeIssue(existing, issue), true, nil
+		}
+	}
+	return rs.createIssue(issue), false, nil
+}
+
+// FindDuplicate returns the open issue most similar to issue, with its title
+// similarity, or nil if none reaches DuplicateTitleSimilarity
+func (rs *ResolutionService) FindDuplicate(issue *models.Issue) (*models.Issue, float64) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.findDuplicate(issue)
+}
+
+// findDuplicate is FindDuplicate. Must be called with rs.mu held.
+func (rs *ResolutionService) findDuplicate(issue *models.Issue) (*models.Issue, float64) {
+	category := issue.Category
+	if c, ok := models.LookupCategory(category); ok {
+		category = c.ID
+	}
+
+	var best *models.Issue
+	bestScore := 0.0
+	for _, existing := range rs.issues {
+		if existing.ID == issue.ID || existing.Tenant != issue.Tenant || existing.IsArchived() {
+			continue
+		}
+		if existing.Status != "active" && existing.Status != "investigating" {
+			continue
+		}
+		if !strings.EqualFold(existing.Exchange, issue.Exchange) || existing.Category != category {
+			continue
+		}
+		score := TitleSimilarity(existing.Title, issue.Title)
+		if score < DuplicateTitleSimilarity {
+			continue
+		}
+		if best == nil || score > bestScore || (score == bestScore && existing.ID < best.ID) {
+			best, bestScore = existing, score
+		}
+	}
+	return best, bestScore
+}
+
+// mergeIssue folds a new report into an existing issue: complaints and links add up,
+// the more severe severity wins, and a missing description is filled in.
+// Must be called with rs.mu held.
+func (rs *ResolutionService) mergeIssue(issue, report *models.Issue) *models.Issue {
+	issue.ComplaintCount += report.ComplaintCount
+	issue.WeightedCount += report.WeightedCount
+	for _, link := range report.ComplaintLinks {
+		if !slices.Contains(issue.ComplaintLinks, link) {
+			issue.ComplaintLinks = append(issue.ComplaintLinks, link)
+		}
+	}
+	if slices.Index(severityOrder, report.Severity) > slices.Index(severityOrder, issue.Severity) {
+		issue.Severity = report.Severity
+	}
+	if issue.Description == "" {
+		issue.Description = report.Description
+	}
+	if !report.FirstDetected.IsZero() && report.FirstDetected.Before(issue.FirstDetected) {
+		issue.FirstDetected = report.FirstDetected
+	}
+	if issue.Effort != nil {
+		normalized := issue.Effort.Normalize(issue.ComplaintCount)
+		issue.Normalized = &normalized
+	}
+	issue.LastUpdated = time.Now()
+
+	rs.notify("updated", issue)
+	return issue
+}
+
+// TitleSimilarity is the Sørensen–Dice coefficient of two titles' character bigrams,
+// ignoring case, punctuation and spacing: 1 for the same wording, 0 for nothing shared.
+// Two empty titles are identical.
+func TitleSimilarity(a, b string) float64 {
+	x, y := bigrams(a), bigrams(b)
+	if len(x) == 0 && len(y) == 0 {
+		return 1
+	}
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+
+	shared := 0
+	counts := make(map[string]int, len(x))
+	for _, g := range x {
+		counts[g]++
+	}
+	for _, g := range y {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(x)+len(y))
+}
+
+// bigrams returns the adjacent letter/digit pairs of each word in s, lowercased
+func bigrams(s string) []string {
+	var grams []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		if len(runes) == 1 {
+			grams = append(grams, word)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			grams = append(grams, string(runes[i:i+2]))
+		}
+	}
+	return grams
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ISSUE DETECTION
+// ============================================
+
+// DetectionReport lists the issues a detection pass opened or refreshed
+type DetectionReport struct {
+	Created []*models.Issue `json:"created"`
+	Updated []*models.Issue `json:"updated"`
+}
+
+// DetectIssues opens an issue for every analyzer category whose complaint count
+// crosses its threshold and has no open issue for the exchange yet. Categories
+// that already have an open issue get their complaint count and links refreshed,
+// when the counts have changed; an unchanged issue is left alone and not reported.
+// FirstDetected is the publish time of the earliest complaint in the category.
+// Thresholds apply to the count with each complaint weighed by its source's trust.
+// Counts are stored alongside their rate per unit of scrape effort, and each issue
+// gets its category's engagement reach (from analyzer.ReachByCategory; may be nil).
+func (rs *ResolutionService) DetectIssues(exchange string, result *analyzer.AnalysisResult, issues []analyzer.ExtractedIssue, reach map[string]models.IssueReach, settings config.DetectionSettings) (*DetectionReport, error) {
+	report := &DetectionReport{
+		Created: []*models.Issue{},
+		Updated: []*models.Issue{},
+	}
+	if result == nil {
+		return report, nil
+	}
+
+	byCategory := make(map[string][]analyzer.ExtractedIssue)
+	for _, issue := range issues {
+		byCategory[issue.Category] = append(byCategory[issue.Category], issue)
+	}
+
+	// Deterministic order so issue creation (and tracker events) is stable across runs
+	keys := make([]string, 0, len(result.Categories))
+	for key := range result.Categories {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	criteria := rs.Criteria()
+	for _, key := range keys {
+		category := result.Categories[key]
+
+		// Thresholds apply to the source-weighted count, falling back to the raw
+		// count when the caller has no extracted complaints to weigh
+		weighted := float64(category.Count)
+		if len(byCategory[key]) > 0 {
+			weighted = WeightedCount(byCategory[key], criteria)
+		}
+		if weighted < float64(settings.Threshold(key)) {
+			continue
+		}
+		links := complaintLinks(byCategory[key], settings.MaxLinks)
+		effort := result.Effort
+		var categoryReach *models.IssueReach
+		if r, ok := reach[models.NormalizeCategory(key)]; ok { // Complaints carry taxonomy IDs
+			categoryReach = &r
+		}
+
+		if existing := rs.findOpenIssue(exchange, key); existing != nil {
+			if existing.IsArchived() {
+				continue // Archived on purpose (e.g. a false positive): don't reopen it as a new issue
+			}
+			if existing.ComplaintCount == category.Count && existing.WeightedCount == weighted {
+				continue // Same data reloaded: no "updated" event for observers to act on
+			}
+			updated, err := rs.UpdateIssue(existing.ID, &models.Issue{
+				ComplaintCount: category.Count,
+				WeightedCount:  weighted,
+				ComplaintLinks: links,
+				Effort:         &effort,
+				Reach:          categoryReach,
+			})
+			if err != nil {
+				return report, fmt.Errorf("failed to update issue %s: %w", existing.ID, err)
+			}
+			report.Updated = append(report.Updated, updated)
+			continue
+		}
+
+		created, err := rs.CreateIssue(&models.Issue{
+			Exchange:       exchange,
+			Category:       key,
+			Title:          category.Name,
+			Description:    fmt.Sprintf("%d complaints about %s detected in YouTube videos and comments", category.Count, category.Name),
+			FirstDetected:  firstPublished(byCategory[key]),
+			ComplaintCount: category.Count,
+			WeightedCount:  weighted,
+			ComplaintLinks: links,
+			Effort:         &effort,
+			Reach:          categoryReach,
+			Severity:       category.Severity,
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to create issue for %s: %w", key, err)
+		}
+		report.Created = append(report.Created, created)
+	}
+
+	return report, nil
+}
+
+// findOpenIssue returns a copy of the unresolved issue for an exchange and category,
+// if any, preferring one that isn't archived. An archived one still counts, so
+// detection doesn't open a duplicate of it.
+func (rs *ResolutionService) findOpenIssue(exchange, category string) *models.Issue {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	var found *models.Issue
+	for _, issue := range rs.issues {
+		// Tenants' issues are their own; detection only tracks the deployment's
+		if issue.Tenant != "" || issue.Exchange != exchange || issue.Category != category {
+			continue
+		}
+		if issue.Status != "active" && issue.Status != "investigating" {
+			continue
+		}
+		if found == nil || (found.IsArchived() && !issue.IsArchived()) {
+			found = issue
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	open := *found
+	return &open
+}
+
+// complaintLinks returns up to max distinct source URLs, most-liked complaints first
+func complaintLinks(issues []analyzer.ExtractedIssue, max int) []string {
+	sorted := append([]analyzer.ExtractedIssue(nil), issues...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Likes > sorted[j].Likes })
+
+	seen := make(map[string]bool)
+	links := []string{}
+	for _, issue := range sorted {
+		if max > 0 && len(links) >= max {
+			break
+		}
+		if issue.SourceURL == "" || seen[issue.SourceURL] {
+			continue
+		}
+		seen[issue.SourceURL] = true
+		links = append(links, issue.SourceURL)
+	}
+	return links
+}
+
+// firstPublished returns the earliest publish time among complaints (zero if unknown)
+func firstPublished(issues []analyzer.ExtractedIssue) time.Time {
+	var first time.Time
+	for _, issue := range issues {
+		if issue.PublishedAt.IsZero() {
+			continue
+		}
+		if first.IsZero() || issue.PublishedAt.Before(first) {
+			first = issue.PublishedAt
+		}
+	}
+	return first
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// EVIDENCE VALIDATION
+// ============================================
+
+// Evidence validation error codes
+const (
+	ErrCodeNegativeCount        = "negative_complaint_count"
+	ErrCodeNoBaseline           = "no_baseline_complaints"
+	ErrCodeComplaintsIncreased  = "complaints_increased"
+	ErrCodePercentageOutOfRange = "percentage_out_of_range"
+	ErrCodePercentageMismatch   = "percentage_mismatch"
+	ErrCodeSentimentOutOfRange  = "sentiment_out_of_range"
+	ErrCodeMeasurementMissing   = "measurement_window_missing"
+	ErrCodeMeasurementInverted  = "measurement_window_inverted"
+	ErrCodeMeasurementInFuture  = "measurement_window_in_future"
+	ErrCodeNoDataSources        = "no_data_sources"
+	ErrCodeEffortIncomplete     = "effort_incomplete"
+	ErrCodeNormalizedIncreased  = "normalized_complaints_increased"
+	ErrCodeSourcesIncomplete    = "source_counts_incomplete"
+	ErrCodeSourcesMismatch      = "source_counts_mismatch"
+)
+
+const (
+	// percentageMismatchTolerance allows rounding in the reported decrease
+	percentageMismatchTolerance = 0.01
+	// measurementClockSkewAllowed tolerates a measurement_end slightly ahead of the server clock
+	measurementClockSkewAllowed = 5 * time.Minute
+)
+
+// EvidenceValidationError lists every rule a piece of resolution evidence broke
+type EvidenceValidationError struct {
+	Errors []models.ValidationError
+}
+
+func (e *EvidenceValidationError) Error() string {
+	codes := make([]string, len(e.Errors))
+	for i, v := range e.Errors {
+		codes[i] = v.Code
+	}
+	return fmt.Sprintf("invalid resolution evidence: %s", strings.Join(codes, ", "))
+}
+
+// ValidateEvidence checks that evidence is internally consistent: counts are
+// non-negative and actually decreased, the reported percentage matches the
+// counts, sentiment is within -1..1, and the measurement window is ordered and
+// not in the future. When scrape effort is given for both counts, the complaint
+// rate per unit of effort must not have risen either. Per-source counts, when
+// given, must add up to the totals. Returns
+// *EvidenceValidationError, or nil if valid.
+func ValidateEvidence(evidence *models.ResolutionEvidence, now time.Time) error {
+	var errs []models.ValidationError
+	add := func(code, field, format string, args ...any) {
+		errs = append(errs, models.ValidationError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if evidence.ComplaintsBefore < 0 {
+		add(ErrCodeNegativeCount, "complaints_before", "complaints_before is %d", evidence.ComplaintsBefore)
+	}
+	if evidence.ComplaintsAfter < 0 {
+		add(ErrCodeNegativeCount, "complaints_after", "complaints_after is %d", evidence.ComplaintsAfter)
+	}
+	countsValid := evidence.ComplaintsBefore >= 0 && evidence.ComplaintsAfter >= 0
+
+	switch {
+	case countsValid && evidence.ComplaintsBefore == 0:
+		add(ErrCodeNoBaseline, "complaints_before", "a resolution needs complaints to resolve")
+	case countsValid && evidence.ComplaintsAfter > evidence.ComplaintsBefore:
+		add(ErrCodeComplaintsIncreased, "complaints_after",
+			"complaints rose from %d to %d", evidence.ComplaintsBefore, evidence.ComplaintsAfter)
+	}
+
+	if evidence.PercentageDecrease < 0 || evidence.PercentageDecrease > 1 {
+		add(ErrCodePercentageOutOfRange, "percentage_decrease",
+			"percentage_decrease %.4f must be between 0 and 1", evidence.PercentageDecrease)
+	} else if countsValid && evidence.ComplaintsBefore > 0 {
+		actual := float64(evidence.ComplaintsBefore-evidence.ComplaintsAfter) / float64(evidence.ComplaintsBefore)
+		if math.Abs(actual-evidence.PercentageDecrease) > percentageMismatchTolerance {
+			add(ErrCodePercentageMismatch, "percentage_decrease",
+				"percentage_decrease %.4f does not match counts (%.4f)", evidence.PercentageDecrease, actual)
+		}
+	}
+
+	if evidence.SentimentShift < -1 || evidence.SentimentShift > 1 {
+		add(ErrCodeSentimentOutOfRange, "sentiment_shift",
+			"sentiment_shift %.4f must be between -1 and 1", evidence.SentimentShift)
+	}
+
+	switch {
+	case evidence.MeasurementStart.IsZero() || evidence.MeasurementEnd.IsZero():
+		add(ErrCodeMeasurementMissing, "measurement_start", "measurement_start and measurement_end are required")
+	case !evidence.MeasurementEnd.After(evidence.MeasurementStart):
+		add(ErrCodeMeasurementInverted, "measurement_end", "measurement_end must be after measurement_start")
+	case evidence.MeasurementEnd.After(now.Add(measurementClockSkewAllowed)):
+		add(ErrCodeMeasurementInFuture, "measurement_end", "measurement_end is in the future")
+	}
+
+	switch {
+	case (evidence.EffortBefore == nil) != (evidence.EffortAfter == nil):
+		add(ErrCodeEffortIncomplete, "effort_before", "effort_before and effort_after must be given together")
+	case evidence.EffortBefore != nil && countsValid:
+		before, after, unit, ok := comparableRates(evidence)
+		if ok && after > before {
+			add(ErrCodeNormalizedIncreased, "effort_after",
+				"complaints %s rose from %.2f to %.2f", unit, before, after)
+		}
+	}
+
+	switch {
+	case len(evidence.SourcesBefore) == 0 && len(evidence.SourcesAfter) > 0:
+		add(ErrCodeSourcesIncomplete, "sources_before", "sources_after requires sources_before")
+	case len(evidence.SourcesBefore) > 0:
+		for _, counts := range []struct {
+			field  string
+			counts map[string]int
+			total  int
+		}{
+			{"sources_before", evidence.SourcesBefore, evidence.ComplaintsBefore},
+			{"sources_after", evidence.SourcesAfter, evidence.ComplaintsAfter},
+		} {
+			sum := 0
+			for _, n := range counts.counts {
+				sum += n
+			}
+			if sum != counts.total {
+				add(ErrCodeSourcesMismatch, counts.field,
+					"%s adds up to %d complaints, not %d", counts.field, sum, counts.total)
+			}
+		}
+	}
+
+	if len(evidence.DataSources) == 0 {
+		add(ErrCodeNoDataSources, "data_sources", "at least one data source is required")
+	}
+
+	if len(errs) > 0 {
+		return &EvidenceValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// NormalizeEvidence fills in the normalized counts for evidence that carries scrape effort
+func NormalizeEvidence(evidence *models.ResolutionEvidence) {
+	evidence.NormalizedBefore, evidence.NormalizedAfter = nil, nil
+	if evidence.EffortBefore != nil {
+		n := evidence.EffortBefore.Normalize(evidence.ComplaintsBefore)
+		evidence.NormalizedBefore = &n
+	}
+	if evidence.EffortAfter != nil {
+		n := evidence.EffortAfter.Normalize(evidence.ComplaintsAfter)
+		evidence.NormalizedAfter = &n
+	}
+}
+
+// comparableRates returns the before and after complaint rates in the finest unit
+// of effort both measurements recorded: per 1,000 comments, per query, then per source
+func comparableRates(evidence *models.ResolutionEvidence) (before, after float64, unit string, ok bool) {
+	eb, ea := *evidence.EffortBefore, *evidence.EffortAfter
+	nb, na := eb.Normalize(evidence.ComplaintsBefore), ea.Normalize(evidence.ComplaintsAfter)
+	switch {
+	case eb.CommentsScanned > 0 && ea.CommentsScanned > 0:
+		return nb.PerThousandComments, na.PerThousandComments, "per 1,000 comments", true
+	case eb.Queries > 0 && ea.Queries > 0:
+		return nb.PerQuery, na.PerQuery, "per query", true
+	case eb.Sources > 0 && ea.Sources > 0:
+		return nb.PerSource, na.PerSource, "per source", true
+	}
+	return 0, 0, "", false
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// BLOCK EXPLORER API
+// ============================================
+
+// EtherscanV2APIURL is the unified Etherscan API; one key covers Ethereum, Base and their testnets
+const EtherscanV2APIURL = "https://api.etherscan.io/v2/api"
+
+// ExplorerClient enriches attestations from a Basescan/Etherscan-compatible API
+type ExplorerClient struct {
+	APIURL     string
+	APIKey     string
+	ChainID    int64
+	BrowserURL string // e.g. https://sepolia.basescan.org
+	HTTPClient *http.Client
+}
+
+// NewExplorerClient creates an explorer client for a chain
+func NewExplorerClient(apiKey string, chain models.ChainConfig) *ExplorerClient {
+	return &ExplorerClient{
+		APIURL:     EtherscanV2APIURL,
+		APIKey:     apiKey,
+		ChainID:    chain.ChainID,
+		BrowserURL: chain.ExplorerURL,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// NewExplorerClientFromEnv creates a client when EXPLORER_API_KEY is set, nil otherwise
+func NewExplorerClientFromEnv(chain models.ChainConfig) *ExplorerClient {
+	apiKey := os.Getenv("EXPLORER_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+	client := NewExplorerClient(apiKey, chain)
+	if apiURL := os.Getenv("EXPLORER_API_URL"); apiURL != "" {
+		client.APIURL = apiURL
+	}
+	return client
+}
+
+// TxURL links to a transaction on the explorer
+func (e *ExplorerClient) TxURL(txHash string) string {
+	return fmt.Sprintf("%s/tx/%s", e.BrowserURL, txHash)
+}
+
+// AddressURL links to an address on the explorer
+func (e *ExplorerClient) AddressURL(address string) string {
+	return fmt.Sprintf("%s/address/%s", e.BrowserURL, address)
+}
+
+// Enrich looks up an attestation's transaction and contract on the explorer
+func (e *ExplorerClient) Enrich(ctx context.Context, attestation *models.Attestation) (*models.ExplorerDetails, error) {
+	details := &models.ExplorerDetails{
+		TxStatus:    "pending",
+		ContractURL: e.AddressURL(attestation.ContractAddress),
+		AttestorURL: e.AddressURL(attestation.Attestor),
+		CheckedAt:   time.Now(),
+	}
+
+	if attestation.TransactionHash != "" {
+		var receipt struct {
+			Status            string `json:"status"`
+			BlockNumber       string `json:"blockNumber"`
+			GasUsed           string `json:"gasUsed"`
+			EffectiveGasPrice string `json:"effectiveGasPrice"`
+		}
+		found, err := e.call(ctx, url.Values{
+			"module": {"proxy"},
+			"action": {"eth_getTransactionReceipt"},
+			"txhash": {attestation.TransactionHash},
+		}, &receipt)
+		if err != nil {
+			return nil, err
+		}
+
+		if found && receipt.BlockNumber != "" {
+			details.TxURL = e.TxURL(attestation.TransactionHash)
+			if receipt.Status == "0x1" {
+				details.TxStatus = "success"
+			} else {
+				details.TxStatus = "failed"
+			}
+			details.GasUsed = hexUint(receipt.GasUsed)
+			if price, ok := new(big.Int).SetString(strings.TrimPrefix(receipt.EffectiveGasPrice, "0x"), 16); ok {
+				details.EffectiveGasPrice = price.String()
+				details.FeeWei = new(big.Int).Mul(price, new(big.Int).SetUint64(details.GasUsed)).String()
+			}
+
+			var latest string
+			if _, err := e.call(ctx, url.Values{"module": {"proxy"}, "action": {"eth_blockNumber"}}, &latest); err == nil {
+				if head, block := hexUint(latest), hexUint(receipt.BlockNumber); head >= block {
+					details.Confirmations = head - block + 1
+				}
+			}
+		}
+	}
+
+	var source []struct {
+		SourceCode   string `json:"SourceCode"`
+		ContractName string `json:"ContractName"`
+	}
+	if _, err := e.call(ctx, url.Values{
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {attestation.ContractAddress},
+	}, &source); err == nil && len(source) > 0 && source[0].SourceCode != "" {
+		details.ContractVerified = true
+		details.ContractName = source[0].ContractName
+		details.ContractURL = details.ContractURL + "#code"
+	}
+
+	return details, nil
+}
+
+// call performs an explorer API request and decodes "result" into out.
+// Returns false if the explorer has no result (e.g. tx not indexed yet).
+func (e *ExplorerClient) call(ctx context.Context, params url.Values, out any) (bool, error) {
+	params.Set("chainid", strconv.FormatInt(e.ChainID, 10))
+	params.Set("apikey", e.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.APIURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build explorer request: %w", err)
+	}
+	resp, err := e.HTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("explorer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("explorer returned status %d", resp.StatusCode)
+	}
+
+	// Module endpoints wrap results as {status, message, result}; proxy endpoints
+	// use JSON-RPC {jsonrpc, id, result, error}
+	var envelope struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+		Error   *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return false, fmt.Errorf("failed to decode explorer response: %w", err)
+	}
+	if envelope.Error != nil {
+		return false, fmt.Errorf("explorer error: %s", envelope.Error.Message)
+	}
+	if envelope.Status == "0" {
+		return false, fmt.Errorf("explorer error: %s", envelope.Message)
+	}
+	if len(envelope.Result) == 0 || string(envelope.Result) == "null" {
+		return false, nil
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return false, fmt.Errorf("failed to decode explorer result: %w", err)
+	}
+	return true, nil
+}
+
+// hexUint parses a 0x-prefixed quantity, returning 0 if invalid
+func hexUint(s string) uint64 {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
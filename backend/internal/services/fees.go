@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ============================================
+// EIP-1559 FEES AND GAS
+// ============================================
+// Transactions are EIP-1559 dynamic fee transactions. The priority fee is the median
+// tip paid over recent blocks (from eth_feeHistory) and the fee cap leaves room for
+// the base fee to double before the transaction is included; only base fee plus tip
+// is actually charged. The gas limit comes from eth_estimateGas plus a margin, so a
+// call that would revert fails before anything is sent, and is capped by
+// ATTESTATION_GAS_CEILING so a misbehaving estimate can't spend without bound.
+
+const (
+	// DefaultGasCeiling is the most gas one transaction may use unless ATTESTATION_GAS_CEILING is set
+	DefaultGasCeiling = 500000
+	// GasEstimateMarginPercent is added to eth_estimateGas, since state can change before inclusion
+	GasEstimateMarginPercent = 20
+	// FeeHistoryBlocks is how many recent blocks the priority fee is taken from
+	FeeHistoryBlocks = 10
+	// FeeHistoryPercentile is the tip percentile read from each block
+	FeeHistoryPercentile = 50
+	// BaseFeeHeadroom multiplies the next block's base fee in the fee cap
+	BaseFeeHeadroom = 2
+)
+
+// FeeQuote is what a dynamic fee transaction sent now would offer to pay per gas
+type FeeQuote struct {
+	BaseFee   *big.Int // Next block's base fee
+	GasTipCap *big.Int // maxPriorityFeePerGas
+	GasFeeCap *big.Int // maxFeePerGas
+}
+
+// Expected is the price per gas the transaction is expected to pay: base fee plus tip
+func (f *FeeQuote) Expected() *big.Int {
+	return new(big.Int).Add(f.BaseFee, f.GasTipCap)
+}
+
+// GasCeilingFromEnv reads ATTESTATION_GAS_CEILING, falling back to DefaultGasCeiling
+func GasCeilingFromEnv() (uint64, error) {
+	v := os.Getenv("ATTESTATION_GAS_CEILING")
+	if v == "" {
+		return DefaultGasCeiling, nil
+	}
+	ceiling, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || ceiling < 21000 {
+		return 0, fmt.Errorf("invalid ATTESTATION_GAS_CEILING %q: must be a gas amount of at least 21000", v)
+	}
+	return ceiling, nil
+}
+
+// SuggestFees quotes the fees for a transaction sent now
+func (bs *BlockchainService) SuggestFees(ctx context.Context) (*FeeQuote, error) {
+	history, err := withRPCRetry(ctx, func() (*ethereum.FeeHistory, error) {
+		return bs.client.FeeHistory(ctx, FeeHistoryBlocks, nil, []float64{FeeHistoryPercentile})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1] == nil {
+		return nil, fmt.Errorf("fee history has no base fee; the chain may not support EIP-1559")
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1] // The entry after the last block is the next block's
+
+	var tips []*big.Int
+	for _, rewards := range history.Reward {
+		if len(rewards) > 0 && rewards[0] != nil {
+			tips = append(tips, rewards[0])
+		}
+	}
+	var tip *big.Int
+	if len(tips) > 0 {
+		sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+		tip = tips[len(tips)/2]
+	} else {
+		tip, err = withRPCRetry(ctx, func() (*big.Int, error) {
+			return bs.client.SuggestGasTipCap(ctx)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get priority fee: %w", err)
+		}
+	}
+
+	feeCap := new(big.Int).Mul(baseFee, big.NewInt(BaseFeeHeadroom))
+	feeCap.Add(feeCap, tip)
+	return &FeeQuote{BaseFee: baseFee, GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+// estimateGas returns the gas limit for calling the contract with data: the node's
+// estimate plus GasEstimateMarginPercent, capped at the gas ceiling
+func (bs *BlockchainService) estimateGas(ctx context.Context, data []byte, fees *FeeQuote) (uint64, error) {
+	estimate, err := withRPCRetry(ctx, func() (uint64, error) {
+		return bs.client.EstimateGas(ctx, ethereum.CallMsg{
+			From:      bs.publicAddress,
+			To:        &bs.contractAddress,
+			GasFeeCap: fees.GasFeeCap,
+			GasTipCap: fees.GasTipCap,
+			Data:      data,
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas (the transaction would likely revert): %w", err)
+	}
+	if estimate > bs.gasCeiling {
+		return 0, fmt.Errorf("transaction needs %d gas, above the gas ceiling of %d (ATTESTATION_GAS_CEILING)", estimate, bs.gasCeiling)
+	}
+	return min(estimate+estimate*GasEstimateMarginPercent/100, bs.gasCeiling), nil
+}
+
+// signedContractTx builds and signs a dynamic fee transaction calling the contract with data
+func (bs *BlockchainService) signedContractTx(ctx context.Context, data []byte) (*types.Transaction, error) {
+	nonce, err := withRPCRetry(ctx, func() (uint64, error) {
+		return bs.client.PendingNonceAt(ctx, bs.publicAddress)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+	fees, err := bs.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := bs.estimateGas(ctx, data, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := big.NewInt(bs.chainConfig.ChainID)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		Gas:       gasLimit,
+		To:        &bs.contractAddress,
+		Value:     big.NewInt(0), // No ETH value
+		Data:      data,
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), bs.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// sendContractTx signs a transaction calling the contract with data and sends it.
+// Sends are serialized so concurrent attestations don't pick the same nonce.
+func (bs *BlockchainService) sendContractTx(ctx context.Context, data []byte) (*types.Transaction, error) {
+	bs.sendMu.Lock()
+	defer bs.sendMu.Unlock()
+
+	signedTx, err := bs.signedContractTx(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := bs.client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	return signedTx, nil
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ATTESTATION INDEXER
+// ============================================
+// The resolution store only knows attestations this instance recorded. The indexer
+// reads every ResolutionRecorded (and ResolutionMetadata) event the contract has
+// emitted, so the API can list everything on-chain - attestations from other
+// instances, earlier deployments or other attestors included. It polls FilterLogs
+// rather than subscribing, which works against plain HTTP RPC endpoints, backfills
+// from ATTESTATION_INDEX_FROM_BLOCK (the contract's deployment block) on first run,
+// and saves its progress so restarts pick up where it stopped.
+//
+// Blocks are only indexed once IndexConfirmations deep, so a reorg can't leave an
+// attestation in the index that is no longer on-chain.
+
+const (
+	// AttestationIndexFile holds indexed attestations inside the data directory
+	AttestationIndexFile = "attestation_index.json"
+	// DefaultIndexInterval is how often the indexer polls for new events
+	DefaultIndexInterval = 30 * time.Second
+	// IndexBlockRange is the most blocks one FilterLogs call covers; RPC providers cap the range
+	IndexBlockRange = 2000
+	// IndexConfirmations is how deep a block must be before its events are indexed
+	IndexConfirmations = 5
+)
+
+// IndexerStatus reports the indexer's progress
+type IndexerStatus struct {
+	FromBlock    uint64    `json:"from_block"`
+	IndexedBlock uint64    `json:"indexed_block"` // Last block scanned; 0 before the first scan
+	Attestations int       `json:"attestations"`
+	LastSyncAt   time.Time `json:"last_sync_at,omitzero"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at,omitzero"`
+}
+
+// attestationIndex is what the indexer saves
+type attestationIndex struct {
+	ChainID         int64                `json:"chain_id"`
+	ContractAddress string               `json:"contract_address"`
+	IndexedBlock    uint64               `json:"indexed_block"`
+	Attestations    []models.Attestation `json:"attestations"`
+}
+
+// AttestationIndexer keeps an index of every attestation the contract has recorded
+type AttestationIndexer struct {
+	bs           *BlockchainService
+	path         string
+	fromBlock    uint64
+	indexedBlock uint64
+	attestations map[uint64]*models.Attestation
+	status       IndexerStatus
+	mu           sync.RWMutex
+}
+
+// NewAttestationIndexer loads the index from dataDir. An index of another chain or
+// contract is discarded, as is one that hasn't reached fromBlock.
+func NewAttestationIndexer(bs *BlockchainService, dataDir string, fromBlock uint64) (*AttestationIndexer, error) {
+	ix := &AttestationIndexer{
+		bs:           bs,
+		path:         filepath.Join(dataDir, AttestationIndexFile),
+		fromBlock:    fromBlock,
+		attestations: make(map[uint64]*models.Attestation),
+	}
+
+	data, err := os.ReadFile(ix.path)
+	if os.IsNotExist(err) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation index: %w", err)
+	}
+	var saved attestationIndex
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse attestation index: %w", err)
+	}
+	if saved.ChainID != bs.chainConfig.ChainID || !strings.EqualFold(saved.ContractAddress, bs.contractAddress.Hex()) ||
+		saved.IndexedBlock < fromBlock {
+		return ix, nil
+	}
+	ix.indexedBlock = saved.IndexedBlock
+	for i := range saved.Attestations {
+		attestation := saved.Attestations[i]
+		ix.attestations[attestation.ID] = &attestation
+	}
+	return ix, nil
+}
+
+// IndexFromBlockFromEnv reads ATTESTATION_INDEX_FROM_BLOCK, the block to backfill from (default 0)
+func IndexFromBlockFromEnv() (uint64, error) {
+	v := os.Getenv("ATTESTATION_INDEX_FROM_BLOCK")
+	if v == "" {
+		return 0, nil
+	}
+	block, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ATTESTATION_INDEX_FROM_BLOCK %q: must be a block number", v)
+	}
+	return block, nil
+}
+
+// Sync indexes the events of every confirmed block not scanned yet, saving after
+// each range so an interrupted backfill resumes where it stopped. It returns how
+// many attestations were added.
+func (ix *AttestationIndexer) Sync(ctx context.Context) (int, error) {
+	head, err := withRPCRetry(ctx, func() (uint64, error) {
+		return ix.bs.client.BlockNumber(ctx)
+	})
+	if err != nil {
+		return 0, ix.recordError(fmt.Errorf("failed to get latest block: %w", err))
+	}
+	if head < IndexConfirmations {
+		return 0, nil
+	}
+	target := head - IndexConfirmations
+
+	ix.mu.RLock()
+	from := max(ix.indexedBlock+1, ix.fromBlock)
+	ix.mu.RUnlock()
+
+	added := 0
+	for from <= target {
+		to := min(from+IndexBlockRange-1, target)
+		attestations, err := ix.scan(ctx, from, to)
+		if err != nil {
+			return added, ix.recordError(err)
+		}
+
+		ix.mu.Lock()
+		for _, attestation := range attestations {
+			if _, ok := ix.attestations[attestation.ID]; !ok {
+				added++
+			}
+			ix.attestations[attestation.ID] = attestation
+		}
+		ix.indexedBlock = to
+		err = ix.save()
+		ix.mu.Unlock()
+		if err != nil {
+			return added, ix.recordError(err)
+		}
+		from = to + 1
+	}
+
+	ix.mu.Lock()
+	ix.status.LastSyncAt = time.Now()
+	ix.mu.Unlock()
+	return added, nil
+}
+
+// Run syncs immediately, backfilling on first start, and then every interval until ctx is cancelled
+func (ix *AttestationIndexer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if added, err := ix.Sync(ctx); err != nil {
+			fmt.Printf("⚠️  Attestation indexing failed: %v\n", err)
+		} else if added > 0 {
+			fmt.Printf("📇 Indexed %d new attestation(s)\n", added)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// List returns the indexed attestations, oldest first
+func (ix *AttestationIndexer) List() []models.Attestation {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	attestations := make([]models.Attestation, 0, len(ix.attestations))
+	for _, attestation := range ix.attestations {
+		attestations = append(attestations, *attestation)
+	}
+	sort.Slice(attestations, func(i, j int) bool { return attestations[i].ID < attestations[j].ID })
+	return attestations
+}
+
+// Status returns the indexer's progress
+func (ix *AttestationIndexer) Status() IndexerStatus {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	status := ix.status
+	status.FromBlock = ix.fromBlock
+	status.IndexedBlock = ix.indexedBlock
+	status.Attestations = len(ix.attestations)
+	return status
+}
+
+// scan reads the attestation events emitted in blocks from through to
+func (ix *AttestationIndexer) scan(ctx context.Context, from, to uint64) ([]*models.Attestation, error) {
+	bs := ix.bs
+	recorded := bs.contractABI.Events["ResolutionRecorded"]
+	metadata := bs.contractABI.Events["ResolutionMetadata"]
+
+	logs, err := withRPCRetry(ctx, func() ([]types.Log, error) {
+		return bs.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{bs.contractAddress},
+			Topics:    [][]common.Hash{{recorded.ID, metadata.ID}},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation events in blocks %d-%d: %w", from, to, err)
+	}
+
+	byID := make(map[uint64]*models.Attestation)
+	metadataURIs := make(map[uint64]string)
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) < 2 {
+			continue
+		}
+		id := new(big.Int).SetBytes(log.Topics[1].Bytes()).Uint64()
+
+		switch log.Topics[0] {
+		case metadata.ID:
+			values, err := metadata.Inputs.NonIndexed().Unpack(log.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unpack ResolutionMetadata in %s: %w", log.TxHash.Hex(), err)
+			}
+			metadataURIs[id] = values[0].(string)
+
+		case recorded.ID:
+			values, err := recorded.Inputs.NonIndexed().Unpack(log.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unpack ResolutionRecorded in %s: %w", log.TxHash.Hex(), err)
+			}
+			evidenceHash := values[1].([32]byte)
+			previousHash := values[2].([32]byte)
+			timestamp := values[3].(*big.Int)
+			attestor := values[4].(common.Address)
+
+			byID[id] = &models.Attestation{
+				ID:              id,
+				TransactionHash: log.TxHash.Hex(),
+				BlockNumber:     log.BlockNumber,
+				BlockTimestamp:  time.Unix(timestamp.Int64(), 0),
+				ChainID:         bs.chainConfig.ChainID,
+				ContractAddress: bs.contractAddress.Hex(),
+				EvidenceHash:    "0x" + hex.EncodeToString(evidenceHash[:]),
+				PreviousHash:    "0x" + hex.EncodeToString(previousHash[:]),
+				IssueCategory:   values[0].(string),
+				Attestor:        attestor.Hex(),
+				ExplorerURL:     fmt.Sprintf("%s/tx/%s", bs.chainConfig.ExplorerURL, log.TxHash.Hex()),
+				Verified:        true,
+			}
+		}
+	}
+
+	attestations := make([]*models.Attestation, 0, len(byID))
+	for id, attestation := range byID {
+		attestation.MetadataURI = metadataURIs[id]
+		// The exchange is an indexed string, so the event only carries its hash;
+		// the contract's record has the name
+		stored, err := bs.GetAttestationByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attestation #%d: %w", id, err)
+		}
+		attestation.Exchange = stored.Exchange
+		attestations = append(attestations, attestation)
+	}
+	return attestations, nil
+}
+
+// recordError notes a failed sync in the status and returns err
+func (ix *AttestationIndexer) recordError(err error) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.status.LastError = err.Error()
+	ix.status.LastErrorAt = time.Now()
+	return err
+}
+
+// save writes the index. Must be called with ix.mu held.
+func (ix *AttestationIndexer) save() error {
+	saved := attestationIndex{
+		ChainID:         ix.bs.chainConfig.ChainID,
+		ContractAddress: ix.bs.contractAddress.Hex(),
+		IndexedBlock:    ix.indexedBlock,
+		Attestations:    make([]models.Attestation, 0, len(ix.attestations)),
+	}
+	for _, attestation := range ix.attestations {
+		saved.Attestations = append(saved.Attestations, *attestation)
+	}
+	sort.Slice(saved.Attestations, func(i, j int) bool { return saved.Attestations[i].ID < saved.Attestations[j].ID })
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal attestation index: %w", err)
+	}
+	if err := os.WriteFile(ix.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write attestation index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// IPFS EVIDENCE PINNING
+// ============================================
+// Only the evidence hash goes on-chain, so without the evidence JSON itself nobody
+// can check what was attested. Pinning the exact bytes that were hashed to IPFS
+// publishes them under a content address no one can quietly change: anyone can
+// fetch the CID from any gateway, Keccak256 the body, and compare it with the
+// on-chain hash. The JSON is uploaded as a file rather than as a JSON object,
+// because pinning services re-serialize JSON objects and the bytes would no
+// longer hash to the attested value.
+
+// PinataPinFileURL is Pinata's upload-and-pin endpoint, used unless IPFS_PINNING_URL is set
+const PinataPinFileURL = "https://api.pinata.cloud/pinning/pinFileToIPFS"
+
+// IPFSPinner uploads files to an IPFS pinning API that takes multipart uploads
+// (Pinata's pinFileToIPFS, or a Kubo node's /api/v0/add?pin=true)
+type IPFSPinner struct {
+	APIURL     string
+	Token      string // Sent as a bearer token
+	HTTPClient *http.Client
+}
+
+// NewIPFSPinner creates a pinner for a pinning API
+func NewIPFSPinner(apiURL, token string) *IPFSPinner {
+	return &IPFSPinner{
+		APIURL:     apiURL,
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// NewIPFSPinnerFromEnv creates a pinner when IPFS_PINNING_TOKEN is set, nil otherwise.
+// IPFS_PINNING_URL overrides the Pinata endpoint.
+func NewIPFSPinnerFromEnv() (*IPFSPinner, error) {
+	token := os.Getenv("IPFS_PINNING_TOKEN")
+	if token == "" {
+		return nil, nil
+	}
+	apiURL := PinataPinFileURL
+	if v := os.Getenv("IPFS_PINNING_URL"); v != "" {
+		if !strings.HasPrefix(v, "https://") && !strings.HasPrefix(v, "http://") {
+			return nil, fmt.Errorf("invalid IPFS_PINNING_URL %q: must be an http(s) URL", v)
+		}
+		apiURL = v
+	}
+	return NewIPFSPinner(apiURL, token), nil
+}
+
+// Pin uploads data as a file called name and returns its CID
+func (p *IPFSPinner) Pin(ctx context.Context, name string, data []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return "", fmt.Errorf("failed to build pin request: %w", err)
+	}
+	part.Write(data)
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build pin request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.APIURL, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to build pin request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to pin %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("pinning API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var pinned struct {
+		IpfsHash string // Pinata
+		Hash     string // Kubo
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pinned); err != nil {
+		return "", fmt.Errorf("failed to parse pinning API response: %w", err)
+	}
+	cid := pinned.IpfsHash
+	if cid == "" {
+		cid = pinned.Hash
+	}
+	if cid == "" {
+		return "", fmt.Errorf("pinning API response has no CID")
+	}
+	return cid, nil
+}
+
+// EnablePinning makes PinEvidence upload evidence to IPFS through pinner
+func (s *EvidenceStore) EnablePinning(pinner *IPFSPinner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pinner = pinner
+}
+
+// Pinning reports whether PinEvidence uploads to IPFS
+func (s *EvidenceStore) Pinning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pinner != nil
+}
+
+// PinEvidence pins evidence's canonical JSON - the bytes EvidenceHash hashes - to
+// IPFS and returns its CID. A stored bundle for the evidence records the CID too.
+func (s *EvidenceStore) PinEvidence(ctx context.Context, evidence *models.ResolutionEvidence) (string, error) {
+	s.mu.RLock()
+	pinner := s.pinner
+	s.mu.RUnlock()
+	if pinner == nil {
+		return "", fmt.Errorf("IPFS pinning not configured")
+	}
+
+	data, err := CanonicalEvidence(evidence)
+	if err != nil {
+		return "", err
+	}
+	hash, err := EvidenceHash(evidence)
+	if err != nil {
+		return "", err
+	}
+	cid, err := pinner.Pin(ctx, "evidence-"+hash+".json", data)
+	if err != nil {
+		return "", err
+	}
+
+	if bundle, ok := s.Get(hash); ok && bundle.EvidenceCID != cid {
+		updated := *bundle
+		updated.EvidenceCID = cid
+		if err := s.Put(&updated); err != nil {
+			fmt.Printf("⚠️  Failed to record evidence CID: %v\n", err)
+		}
+	}
+	return cid, nil
+}
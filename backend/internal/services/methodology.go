@@ -0,0 +1,170 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// RESOLUTION METHODOLOGY TEMPLATES
+// ============================================
+// Every resolution records how it was measured. Filling AnalysisMethodology from a
+// fixed template library (instead of free text) keeps the strings standardized, so
+// resolutions measured the same way can be grouped and compared.
+//
+// Rendered methodologies look like:
+//
+//	complaint_volume_decrease/v1: Complaint volume before vs after the fix | window_days=7 sources=google,youtube before=150 after=22 decrease=0.8533 sentiment_shift=0.30
+
+// Methodology template IDs
+const (
+	MethodologyComplaintVolume   = "complaint_volume_decrease"
+	MethodologySentimentRecovery = "sentiment_recovery"
+	MethodologyRatingRecovery    = "rating_recovery"
+	MethodologyOutageDecline     = "outage_report_decline"
+)
+
+// Data sources that select the rating and outage templates
+var (
+	ratingSources = []string{"trustpilot", "bbb"}
+	outageSources = []string{"downdetector", "statuspage"}
+)
+
+// MethodologyTemplate describes one standard way of measuring a resolution
+type MethodologyTemplate struct {
+	ID          string `json:"id"`
+	Version     int    `json:"version"` // Bumped whenever the rendered format changes
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// methodologyTemplates is the template library, in selection priority order
+var methodologyTemplates = []MethodologyTemplate{
+	{
+		ID:          MethodologyOutageDecline,
+		Version:     1,
+		Name:        "Outage-report decline",
+		Description: "Outage reports before vs after the incident was resolved",
+	},
+	{
+		ID:          MethodologyRatingRecovery,
+		Version:     1,
+		Name:        "Rating recovery",
+		Description: "Negative review-site reviews before vs after the fix",
+	},
+	{
+		ID:          MethodologySentimentRecovery,
+		Version:     1,
+		Name:        "Sentiment recovery",
+		Description: "Complaint sentiment before vs after the fix",
+	},
+	{
+		ID:          MethodologyComplaintVolume,
+		Version:     1,
+		Name:        "Complaint-volume decrease",
+		Description: "Complaint volume before vs after the fix",
+	},
+}
+
+// MethodologyTemplates returns the template library
+func MethodologyTemplates() []MethodologyTemplate {
+	return slices.Clone(methodologyTemplates)
+}
+
+// GetMethodologyTemplate looks up a template by ID
+func GetMethodologyTemplate(id string) (MethodologyTemplate, bool) {
+	for _, t := range methodologyTemplates {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return MethodologyTemplate{}, false
+}
+
+// SelectMethodology picks the template that matches how the evidence was gathered:
+// outage trackers and review sites have their own templates, a real sentiment gain
+// without the required volume drop is a sentiment recovery, and anything else is
+// measured by complaint volume.
+func SelectMethodology(evidence *models.ResolutionEvidence, criteria models.ResolutionCriteria) MethodologyTemplate {
+	id := MethodologyComplaintVolume
+	switch {
+	case hasAnySource(evidence.DataSources, outageSources):
+		id = MethodologyOutageDecline
+	case onlySources(evidence.DataSources, ratingSources):
+		id = MethodologyRatingRecovery
+	case evidence.PercentageDecrease < criteria.MinPercentageDecrease && evidence.SentimentShift > 0:
+		id = MethodologySentimentRecovery
+	}
+	t, _ := GetMethodologyTemplate(id)
+	return t
+}
+
+// Render fills the template in with the evidence's measurements
+func (t MethodologyTemplate) Render(evidence *models.ResolutionEvidence) string {
+	sources := slices.Clone(evidence.DataSources)
+	slices.Sort(sources)
+
+	params := []string{
+		fmt.Sprintf("window_days=%d", int(evidence.MeasurementEnd.Sub(evidence.MeasurementStart).Hours()/24)),
+		"sources=" + strings.Join(sources, ","),
+		fmt.Sprintf("before=%d", evidence.ComplaintsBefore),
+		fmt.Sprintf("after=%d", evidence.ComplaintsAfter),
+		fmt.Sprintf("decrease=%.4f", evidence.PercentageDecrease),
+		fmt.Sprintf("sentiment_shift=%.2f", evidence.SentimentShift),
+	}
+	if evidence.AppVersion != "" {
+		params = append(params, "app_version="+evidence.AppVersion)
+	}
+	if evidence.OS != "" {
+		params = append(params, "os="+evidence.OS)
+	}
+	if evidence.NormalizedBefore != nil && evidence.NormalizedAfter != nil {
+		params = append(params,
+			fmt.Sprintf("before_per_1k_comments=%.2f", evidence.NormalizedBefore.PerThousandComments),
+			fmt.Sprintf("after_per_1k_comments=%.2f", evidence.NormalizedAfter.PerThousandComments))
+	}
+	return fmt.Sprintf("%s/v%d: %s | %s", t.ID, t.Version, t.Description, strings.Join(params, " "))
+}
+
+// ParseMethodology returns the template ID and version a methodology string was
+// rendered from. ok is false for free-text methodologies.
+func ParseMethodology(methodology string) (id string, version int, ok bool) {
+	head, _, found := strings.Cut(methodology, ": ")
+	if !found {
+		return "", 0, false
+	}
+	id, v, found := strings.Cut(head, "/v")
+	if !found {
+		return "", 0, false
+	}
+	if _, err := fmt.Sscanf(v, "%d", &version); err != nil {
+		return "", 0, false
+	}
+	if _, known := GetMethodologyTemplate(id); !known {
+		return "", 0, false
+	}
+	return id, version, true
+}
+
+// hasAnySource reports whether any of the evidence sources is in set
+func hasAnySource(sources, set []string) bool {
+	for _, s := range sources {
+		if slices.Contains(set, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlySources reports whether there is at least one source and every source is in set
+func onlySources(sources, set []string) bool {
+	for _, s := range sources {
+		if !slices.Contains(set, strings.ToLower(s)) {
+			return false
+		}
+	}
+	return len(sources) > 0
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// READ-ONLY MIRRORS
+// ============================================
+// A mirror is a second deployment that copies issues, resolutions and attestations
+// from a primary instance's API and serves them read-only. It re-hashes every
+// attested resolution's evidence and, when it has its own chain connection, checks
+// the hash on-chain itself, so third parties can host a copy of the data without
+// trusting the primary's word that it was attested.
+
+const (
+	// MirrorSnapshotPath is the primary's endpoint a mirror syncs from
+	MirrorSnapshotPath = "/api/mirror/snapshot"
+	// DefaultMirrorSyncInterval is how often a mirror re-syncs
+	DefaultMirrorSyncInterval = 5 * time.Minute
+)
+
+// MirrorSnapshot is the full issue and resolution state a mirror copies
+type MirrorSnapshot struct {
+	Issues      []models.Issue      `json:"issues"`
+	Resolutions []models.Resolution `json:"resolutions"`
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// Snapshot returns a copy of every issue and resolution, ordered by ID
+func (rs *ResolutionService) Snapshot() MirrorSnapshot {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	snapshot := MirrorSnapshot{
+		Issues:      make([]models.Issue, 0, len(rs.issues)),
+		Resolutions: make([]models.Resolution, 0, len(rs.resolutions)),
+		GeneratedAt: time.Now(),
+	}
+	for _, issue := range rs.issues {
+		snapshot.Issues = append(snapshot.Issues, *issue)
+	}
+	for _, resolution := range rs.resolutions {
+		snapshot.Resolutions = append(snapshot.Resolutions, *resolution)
+	}
+	sort.Slice(snapshot.Issues, func(i, j int) bool { return snapshot.Issues[i].ID < snapshot.Issues[j].ID })
+	sort.Slice(snapshot.Resolutions, func(i, j int) bool { return snapshot.Resolutions[i].ID < snapshot.Resolutions[j].ID })
+	return snapshot
+}
+
+// ReplaceAll swaps the whole store for a snapshot. Observers are not notified:
+// a mirror repeats the primary's state, it doesn't produce events of its own.
+func (rs *ResolutionService) ReplaceAll(snapshot MirrorSnapshot) {
+	resolutions := make(map[string]*models.Resolution, len(snapshot.Resolutions))
+	for i := range snapshot.Resolutions {
+		resolution := snapshot.Resolutions[i]
+		resolutions[resolution.ID] = &resolution
+	}
+	issues := make(map[string]*models.Issue, len(snapshot.Issues))
+	for i := range snapshot.Issues {
+		issue := snapshot.Issues[i]
+		// Point the issue at the stored resolution so both show the same attestation
+		if issue.Resolution != nil {
+			if resolution, ok := resolutions[issue.Resolution.ID]; ok {
+				issue.Resolution = resolution
+				if issue.Attestation != nil {
+					issue.Attestation = resolution.Attestation
+				}
+			}
+		}
+		issues[issue.ID] = &issue
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.issues = issues
+	rs.resolutions = resolutions
+}
+
+// MirrorStatus reports how a mirror's last sync went
+type MirrorStatus struct {
+	Primary      string    `json:"primary"`
+	LastSyncAt   time.Time `json:"last_sync_at,omitzero"`
+	LastError    string    `json:"last_error,omitempty"`
+	LastErrorAt  time.Time `json:"last_error_at,omitzero"`
+	Issues       int       `json:"issues"`
+	Resolutions  int       `json:"resolutions"`
+	Attestations int       `json:"attestations"`
+
+	// Verification of the copied attestations by the mirror itself
+	HashMismatches int  `json:"hash_mismatches"` // Evidence doesn't hash to the attested value
+	ChainVerified  int  `json:"chain_verified"`  // Found on-chain by the mirror's own connection
+	ChainMissing   int  `json:"chain_missing"`   // Not found on-chain, or the lookup failed
+	ChainChecked   bool `json:"chain_checked"`   // False when the mirror has no chain connection
+}
+
+// Mirror keeps a ResolutionService in sync with a primary instance
+type Mirror struct {
+	primary    string
+	rs         *ResolutionService
+	httpClient *http.Client
+	status     MirrorStatus
+	mu         sync.Mutex
+}
+
+// NewMirror creates a mirror of the primary at primaryURL (e.g. https://coinsights.example.com)
+func NewMirror(rs *ResolutionService, primaryURL string) (*Mirror, error) {
+	u, err := url.Parse(primaryURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid mirror primary URL %q: must be an http(s) URL", primaryURL)
+	}
+	primary := strings.TrimRight(primaryURL, "/")
+	return &Mirror{
+		primary:    primary,
+		rs:         rs,
+		httpClient: httpclient.New(httpclient.Slow),
+		status:     MirrorStatus{Primary: primary},
+	}, nil
+}
+
+// NewMirrorFromEnv creates a mirror when MIRROR_PRIMARY_URL is set, nil otherwise
+func NewMirrorFromEnv(rs *ResolutionService) (*Mirror, error) {
+	primary := os.Getenv("MIRROR_PRIMARY_URL")
+	if primary == "" {
+		return nil, nil
+	}
+	return NewMirror(rs, primary)
+}
+
+// MirrorSyncIntervalFromEnv reads MIRROR_SYNC_INTERVAL (e.g. "5m", the default)
+func MirrorSyncIntervalFromEnv() (time.Duration, error) {
+	v := os.Getenv("MIRROR_SYNC_INTERVAL")
+	if v == "" {
+		return DefaultMirrorSyncInterval, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid MIRROR_SYNC_INTERVAL %q: must be a positive duration", v)
+	}
+	return d, nil
+}
+
+// Sync fetches the primary's snapshot, verifies its attestations and replaces the local store
+func (m *Mirror) Sync(ctx context.Context) error {
+	snapshot, err := m.fetch(ctx)
+	if err != nil {
+		m.recordError(err)
+		return err
+	}
+
+	status := m.verify(ctx, snapshot)
+	m.rs.ReplaceAll(*snapshot)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status.LastError, status.LastErrorAt = m.status.LastError, m.status.LastErrorAt
+	m.status = status
+	return nil
+}
+
+// Run syncs immediately and then every interval until ctx is cancelled
+func (m *Mirror) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Sync(ctx); err != nil {
+			fmt.Printf("⚠️  Mirror sync from %s failed: %v\n", m.primary, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns the outcome of the last sync
+func (m *Mirror) Status() MirrorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// fetch downloads the primary's snapshot
+func (m *Mirror) fetch(ctx context.Context) (*MirrorSnapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.primary+MirrorSnapshotPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mirror request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mirror request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("primary returned status %d", resp.StatusCode)
+	}
+	var snapshot MirrorSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode mirror snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// verify re-hashes each attested resolution's evidence and, with a chain connection,
+// looks the hash up on-chain. Attestations that fail either check are marked unverified.
+func (m *Mirror) verify(ctx context.Context, snapshot *MirrorSnapshot) MirrorStatus {
+	status := MirrorStatus{
+		Primary:      m.primary,
+		LastSyncAt:   time.Now(),
+		Issues:       len(snapshot.Issues),
+		Resolutions:  len(snapshot.Resolutions),
+		ChainChecked: m.rs.blockchain != nil,
+	}
+
+	for i := range snapshot.Resolutions {
+		attestation := snapshot.Resolutions[i].Attestation
+		if attestation == nil {
+			continue
+		}
+		status.Attestations++
+
+		hash, err := EvidenceHash(&snapshot.Resolutions[i].Evidence)
+		if err != nil || !strings.EqualFold(hash, attestation.EvidenceHash) {
+			status.HashMismatches++
+			attestation.Verified = false
+			continue
+		}
+		if !status.ChainChecked {
+			continue
+		}
+
+		response, err := m.rs.verifyOnChain(ctx, hash)
+		if err != nil || !response.Verified {
+			status.ChainMissing++
+			attestation.Verified = false
+			continue
+		}
+		status.ChainVerified++
+		attestation.Verified = true
+	}
+	return status
+}
+
+// recordError notes a failed sync without discarding the last good state
+func (m *Mirror) recordError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status.LastError = err.Error()
+	m.status.LastErrorAt = time.Now()
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/tasnint/coinsights/internal/cache"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ENS / BASENAME RESOLUTION
+// ============================================
+
+const (
+	// ENSRegistryAddress is the ENS registry on Ethereum mainnet
+	ENSRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+	// BasenameRegistryAddress is the Basenames registry on Base mainnet
+	BasenameRegistryAddress = "0xb94704422c2a1e396835a571837aa5ae53285a95"
+	// baseReverseCoinType is the ENSIP-11 coin type for Base (0x80000000 | 8453)
+	baseReverseCoinType = "80002105"
+	// nameCacheTTL also caches misses, so addresses without names aren't re-queried every request
+	nameCacheTTL = 24 * time.Hour
+)
+
+const nameResolutionABI = `[
+	{"inputs":[{"name":"node","type":"bytes32"}],"name":"resolver","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"node","type":"bytes32"}],"name":"name","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"node","type":"bytes32"}],"name":"addr","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}
+]`
+
+// nameRegistry is one naming system: a registry contract on a chain and the
+// reverse-record namespace addresses are looked up under
+type nameRegistry struct {
+	label    string // "ens", "basename"
+	client   *ethclient.Client
+	registry common.Address
+	reverse  string // "addr.reverse" or "80002105.reverse"
+}
+
+// NameResolver turns attestor addresses into human-readable ENS or Base names.
+// A reverse record is only trusted if the name resolves forward to the same address.
+type NameResolver struct {
+	registries []nameRegistry
+	contract   abi.ABI
+	cache      *cache.Cache
+}
+
+// NewNameResolverFromEnv connects to the registries whose RPC URL is configured:
+// ENS_RPC_URL (Ethereum mainnet) and BASENAME_RPC_URL (Base mainnet).
+// Returns nil if neither is set.
+func NewNameResolverFromEnv() (*NameResolver, error) {
+	parsed, err := abi.JSON(strings.NewReader(nameResolutionABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse name resolution ABI: %w", err)
+	}
+	nr := &NameResolver{contract: parsed, cache: cache.New(nameCacheTTL)}
+
+	// Basenames first: attestations live on Base, so a Base name is the more specific identity
+	if rpcURL := os.Getenv("BASENAME_RPC_URL"); rpcURL != "" {
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Base RPC: %w", err)
+		}
+		nr.registries = append(nr.registries, nameRegistry{
+			label:    "basename",
+			client:   client,
+			registry: common.HexToAddress(BasenameRegistryAddress),
+			reverse:  baseReverseCoinType + ".reverse",
+		})
+	}
+	if rpcURL := os.Getenv("ENS_RPC_URL"); rpcURL != "" {
+		client, err := ethclient.Dial(rpcURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ENS RPC: %w", err)
+		}
+		nr.registries = append(nr.registries, nameRegistry{
+			label:    "ens",
+			client:   client,
+			registry: common.HexToAddress(ENSRegistryAddress),
+			reverse:  "addr.reverse",
+		})
+	}
+
+	if len(nr.registries) == 0 {
+		return nil, nil
+	}
+	return nr, nil
+}
+
+// Close closes the RPC connections
+func (nr *NameResolver) Close() {
+	for _, r := range nr.registries {
+		r.client.Close()
+	}
+}
+
+// LookupAddress returns the verified primary name for an address, or "" if it has none
+func (nr *NameResolver) LookupAddress(ctx context.Context, address string) string {
+	if !common.IsHexAddress(address) {
+		return ""
+	}
+	addr := common.HexToAddress(address)
+	key := strings.ToLower(addr.Hex())
+	if name, ok := nr.cache.Get(key); ok {
+		return name.(string)
+	}
+
+	name := ""
+	failed := false
+	for _, r := range nr.registries {
+		found, err := nr.reverseLookup(ctx, r, addr)
+		if err != nil {
+			fmt.Printf("⚠️  %s lookup for %s failed: %v\n", r.label, addr.Hex(), err)
+			failed = true
+			continue
+		}
+		if found != "" {
+			name = found
+			break
+		}
+	}
+
+	// A miss is only cached when every registry answered: one that errored may have a name
+	if name != "" || !failed {
+		nr.cache.Set(key, name)
+	}
+	return name
+}
+
+// Annotate fills in the attestor's name on an attestation (no-op on a nil resolver)
+func (nr *NameResolver) Annotate(ctx context.Context, attestation *models.Attestation) {
+	if nr == nil || attestation == nil || attestation.AttestorName != "" {
+		return
+	}
+	attestation.AttestorName = nr.LookupAddress(ctx, attestation.Attestor)
+}
+
+// reverseLookup reads the reverse record and checks it resolves back to addr
+func (nr *NameResolver) reverseLookup(ctx context.Context, r nameRegistry, addr common.Address) (string, error) {
+	reverseNode := namehash(strings.ToLower(hex.EncodeToString(addr.Bytes())) + "." + r.reverse)
+
+	resolver, err := nr.resolverFor(ctx, r, reverseNode)
+	if err != nil || resolver == (common.Address{}) {
+		return "", err
+	}
+
+	var name string
+	if err := nr.call(ctx, r.client, resolver, "name", reverseNode, &name); err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	// Forward-verify: anyone can set a reverse record claiming any name
+	node := namehash(name)
+	forwardResolver, err := nr.resolverFor(ctx, r, node)
+	if err != nil || forwardResolver == (common.Address{}) {
+		return "", err
+	}
+	var resolved common.Address
+	if err := nr.call(ctx, r.client, forwardResolver, "addr", node, &resolved); err != nil {
+		return "", err
+	}
+	if resolved != addr {
+		return "", nil
+	}
+	return name, nil
+}
+
+// resolverFor returns the resolver contract registered for a node
+func (nr *NameResolver) resolverFor(ctx context.Context, r nameRegistry, node [32]byte) (common.Address, error) {
+	var resolver common.Address
+	err := nr.call(ctx, r.client, r.registry, "resolver", node, &resolver)
+	return resolver, err
+}
+
+// call invokes a single-argument view method and unpacks its single return value
+func (nr *NameResolver) call(ctx context.Context, client *ethclient.Client, to common.Address, method string, node [32]byte, out any) error {
+	data, err := nr.contract.Pack(method, node)
+	if err != nil {
+		return fmt.Errorf("failed to pack %s call: %w", method, err)
+	}
+	result, err := client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		return fmt.Errorf("%s call failed: %w", method, err)
+	}
+	if len(result) == 0 {
+		return nil // No contract / empty record
+	}
+	outputs, err := nr.contract.Unpack(method, result)
+	if err != nil {
+		return fmt.Errorf("failed to unpack %s result: %w", method, err)
+	}
+
+	switch v := out.(type) {
+	case *string:
+		*v = outputs[0].(string)
+	case *common.Address:
+		*v = outputs[0].(common.Address)
+	}
+	return nil
+}
+
+// namehash implements the ENS name hashing algorithm (EIP-137)
+func namehash(name string) [32]byte {
+	var node [32]byte
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		copy(node[:], crypto.Keccak256(node[:], labelHash))
+	}
+	return node
+}
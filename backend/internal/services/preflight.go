@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// ATTESTATION PRE-FLIGHT
+// ============================================
+// Attesting spends gas and can't be undone, and most failures only surface once
+// the transaction is sent. The pre-flight checklist runs everything that can be
+// checked up front - the resolution's state, criteria and evidence, the RPC
+// endpoint, the wallet balance, and whether the evidence hash is already on-chain -
+// so admins see every blocker at once before clicking "attest".
+
+const (
+	// PreflightTimeout bounds the RPC calls the checklist makes
+	PreflightTimeout = 15 * time.Second
+	// MaxHeadAge is how old the RPC endpoint's latest block may be before it is reported as lagging
+	MaxHeadAge = 5 * time.Minute
+	// PreflightFundsMargin is how many attestations' worth of gas the wallet should hold
+	// before a balance that covers only this one is a warning
+	PreflightFundsMargin = 3
+)
+
+// WalletBalance returns the attestation wallet's balance in wei
+func (bs *BlockchainService) WalletBalance(ctx context.Context) (*big.Int, error) {
+	balance, err := withRPCRetry(ctx, func() (*big.Int, error) {
+		return bs.client.BalanceAt(ctx, bs.publicAddress, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+	return balance, nil
+}
+
+// ChainHead checks the RPC endpoint serves the configured chain and returns its
+// latest block number and time
+func (bs *BlockchainService) ChainHead(ctx context.Context) (uint64, time.Time, error) {
+	chainID, err := bs.client.ChainID(ctx)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if chainID.Int64() != bs.chainConfig.ChainID {
+		return 0, time.Time{}, fmt.Errorf("RPC endpoint serves chain %d, expected %d (%s)", chainID.Int64(), bs.chainConfig.ChainID, bs.chainConfig.Name)
+	}
+	header, err := bs.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	return header.Number.Uint64(), time.Unix(int64(header.Time), 0), nil
+}
+
+// Preflight runs the checklist for attesting a resolution. Every check runs even
+// when an earlier one fails, except chain checks without a working chain connection.
+func (rs *ResolutionService) Preflight(ctx context.Context, resolutionID string) (*models.Preflight, error) {
+	rs.mu.RLock()
+	stored, ok := rs.resolutions[resolutionID]
+	if !ok {
+		rs.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
+	}
+	resolution := *stored
+	unmet := rs.unmetCriteria(&resolution)
+	rs.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, PreflightTimeout)
+	defer cancel()
+
+	preflight := &models.Preflight{ResolutionID: resolutionID, Checks: []models.PreflightCheck{}, CheckedAt: time.Now()}
+	add := func(name, status, message string, details ...string) {
+		preflight.Checks = append(preflight.Checks, models.PreflightCheck{Name: name, Status: status, Message: message, Details: details})
+	}
+
+	switch {
+	case resolution.Attestation != nil:
+		add("status", models.PreflightFail, fmt.Sprintf("already attested in transaction %s", resolution.Attestation.TransactionHash))
+	case resolution.Status == "needs_review" || resolution.Status == "rejected":
+		add("status", models.PreflightFail, fmt.Sprintf("resolution is %s", resolution.Status))
+	default:
+		add("status", models.PreflightPass, fmt.Sprintf("resolution is %s", resolution.Status))
+	}
+
+	if len(unmet) > 0 {
+		add("criteria", models.PreflightFail, "resolution does not meet the resolution criteria", unmet...)
+	} else {
+		add("criteria", models.PreflightPass, "resolution meets the resolution criteria")
+	}
+
+	var invalid *EvidenceValidationError
+	if err := ValidateEvidence(&resolution.Evidence, time.Now()); errors.As(err, &invalid) {
+		details := make([]string, len(invalid.Errors))
+		for i, e := range invalid.Errors {
+			details[i] = e.Message
+		}
+		add("evidence", models.PreflightFail, "evidence does not validate", details...)
+	} else if err != nil {
+		add("evidence", models.PreflightFail, err.Error())
+	} else {
+		add("evidence", models.PreflightPass, "evidence validates")
+	}
+
+	chainChecks := []string{"rpc", "funds", "hash"}
+	if rs.blockchain == nil {
+		add("blockchain", models.PreflightFail, "blockchain service not configured")
+		for _, name := range chainChecks {
+			add(name, models.PreflightSkip, "no blockchain connection")
+		}
+		return preflight, nil
+	}
+	chain := rs.blockchain.GetChainInfo()
+	add("blockchain", models.PreflightPass, fmt.Sprintf("attesting on %s from %s", chain.Name, rs.blockchain.GetWalletAddress()))
+
+	head, headTime, err := rs.blockchain.ChainHead(ctx)
+	if err != nil {
+		add("rpc", models.PreflightFail, err.Error())
+		for _, name := range chainChecks[1:] {
+			add(name, models.PreflightSkip, "RPC endpoint unavailable")
+		}
+		return preflight, nil
+	}
+	if age := time.Since(headTime); age > MaxHeadAge {
+		add("rpc", models.PreflightWarn, fmt.Sprintf("latest block %d is %s old; the endpoint may be lagging", head, age.Round(time.Second)))
+	} else {
+		add("rpc", models.PreflightPass, fmt.Sprintf("latest block %d", head))
+	}
+
+	rs.preflightFunds(ctx, add)
+
+	hash, err := EvidenceHash(&resolution.Evidence)
+	if err == nil {
+		var verification *models.VerificationResponse
+		if verification, err = rs.blockchain.VerifyAttestation(ctx, hash); err == nil {
+			if verification.OnChain {
+				message := fmt.Sprintf("evidence hash %s is already on-chain", hash)
+				if verification.Attestation != nil {
+					message += fmt.Sprintf(" as attestation #%d", verification.Attestation.ID)
+				}
+				add("hash", models.PreflightFail, message)
+			} else {
+				add("hash", models.PreflightPass, fmt.Sprintf("evidence hash %s is not on-chain yet", hash))
+			}
+		}
+	}
+	if err != nil {
+		add("hash", models.PreflightFail, err.Error())
+	}
+
+	preflight.Ready = true
+	for _, check := range preflight.Checks {
+		if check.Status == models.PreflightFail {
+			preflight.Ready = false
+		}
+	}
+	return preflight, nil
+}
+
+// preflightFunds checks the wallet can pay for the attestation at the current max fee,
+// which nodes require the balance to cover before they accept the transaction
+func (rs *ResolutionService) preflightFunds(ctx context.Context, add func(name, status, message string, details ...string)) {
+	balance, err := rs.blockchain.WalletBalance(ctx)
+	if err != nil {
+		add("funds", models.PreflightFail, err.Error())
+		return
+	}
+	gasLimit, fees, err := rs.blockchain.AttestationGasQuote(ctx)
+	if err != nil {
+		add("funds", models.PreflightFail, err.Error())
+		return
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), fees.GasFeeCap)
+
+	message := fmt.Sprintf("wallet holds %.6f ETH; attesting costs up to %.6f ETH", weiToETH(balance), weiToETH(cost))
+	switch {
+	case balance.Cmp(cost) < 0:
+		add("funds", models.PreflightFail, message)
+	case balance.Cmp(new(big.Int).Mul(cost, big.NewInt(PreflightFundsMargin))) < 0:
+		add("funds", models.PreflightWarn, message+"; top up soon")
+	default:
+		add("funds", models.PreflightPass, message)
+	}
+}
+
+func weiToETH(wei *big.Int) float64 {
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), weiPerETH).Float64()
+	return eth
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/cache"
+	"github.com/tasnint/coinsights/internal/health"
+	"github.com/tasnint/coinsights/internal/httpclient"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// FIAT PRICE FEED
+// ============================================
+// Gas is paid in ETH on Base, but budgets are in dollars. The ETH/USD price comes
+// from CoinGecko's simple price API and is cached, so stats and cost estimates
+// don't hit the API (or its rate limit) on every request. Testnet ETH has no market
+// value; its spend is converted at the mainnet price as an estimate of what the
+// same activity would cost on mainnet.
+
+const (
+	// CoinGeckoAPIURL is CoinGecko's public API
+	CoinGeckoAPIURL = "https://api.coingecko.com/api/v3"
+	// DefaultPriceCacheTTL is how long a fetched price is reused
+	DefaultPriceCacheTTL = 5 * time.Minute
+)
+
+// weiPerETH converts wei amounts to ETH
+var weiPerETH = new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+
+// ethPrice is a fetched ETH/USD price
+type ethPrice struct {
+	usd       float64
+	fetchedAt time.Time
+}
+
+// PriceFeed reads the ETH/USD price from CoinGecko
+type PriceFeed struct {
+	APIURL     string
+	APIKey     string // Optional demo API key, for a higher rate limit
+	HTTPClient *http.Client
+	cache      *cache.Cache
+}
+
+// NewPriceFeed creates a price feed that reuses prices for ttl
+func NewPriceFeed(apiKey string, ttl time.Duration) *PriceFeed {
+	if ttl <= 0 {
+		ttl = DefaultPriceCacheTTL
+	}
+	return &PriceFeed{
+		APIURL:     CoinGeckoAPIURL,
+		APIKey:     apiKey,
+		HTTPClient: httpclient.New(httpclient.Standard),
+		cache:      cache.New(ttl),
+	}
+}
+
+// NewPriceFeedFromEnv creates a price feed using COINGECKO_API_KEY if set and
+// PRICE_CACHE_TTL (e.g. "5m", the default)
+func NewPriceFeedFromEnv() (*PriceFeed, error) {
+	ttl := DefaultPriceCacheTTL
+	if v := os.Getenv("PRICE_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid PRICE_CACHE_TTL %q: must be a positive duration", v)
+		}
+		ttl = d
+	}
+	return NewPriceFeed(os.Getenv("COINGECKO_API_KEY"), ttl), nil
+}
+
+// ETHUSD returns the ETH/USD price and when it was fetched. Failed lookups aren't
+// cached, so the next request retries.
+func (p *PriceFeed) ETHUSD(ctx context.Context) (float64, time.Time, error) {
+	if cached, ok := p.cache.Get("ethereum:usd"); ok {
+		price := cached.(ethPrice)
+		return price.usd, price.fetchedAt, nil
+	}
+
+	params := url.Values{}
+	params.Add("ids", "ethereum")
+	params.Add("vs_currencies", "usd")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.APIURL+"/simple/price?"+params.Encode(), nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if p.APIKey != "" {
+		req.Header.Set("x-cg-demo-api-key", p.APIKey)
+	}
+
+	resp, err := p.HTTPClient.Do(req)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to fetch ETH price: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, time.Time{}, health.StatusError("CoinGecko", resp.StatusCode, string(body))
+	}
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to decode ETH price: %w", err)
+	}
+	usd := prices["ethereum"]["usd"]
+	if usd <= 0 {
+		return 0, time.Time{}, fmt.Errorf("CoinGecko returned no ETH price")
+	}
+
+	price := ethPrice{usd: usd, fetchedAt: time.Now()}
+	p.cache.Set("ethereum:usd", price)
+	return price.usd, price.fetchedAt, nil
+}
+
+// GasCost converts a wei amount to ETH and, if the price is available, USD.
+// A nil feed converts to ETH only.
+func (p *PriceFeed) GasCost(ctx context.Context, wei *big.Int) models.GasCost {
+	eth, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), weiPerETH).Float64()
+	cost := models.GasCost{Wei: wei.String(), ETH: eth}
+	if p == nil {
+		return cost
+	}
+	price, fetchedAt, err := p.ETHUSD(ctx)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return cost
+	}
+	usd := eth * price
+	cost.USD = &usd
+	cost.ETHUSD = price
+	cost.PricedAt = fetchedAt
+	return cost
+}
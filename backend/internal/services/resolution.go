@@ -5,22 +5,52 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/tasnint/coinsights/internal/config"
 	"github.com/tasnint/coinsights/internal/models"
 )
 
 // ResolutionService manages issue resolutions and their attestations
 type ResolutionService struct {
 	blockchain  *BlockchainService
+	explorer    *ExplorerClient               // Optional: enriches attestations from Basescan/Etherscan
+	names       *NameResolver                 // Optional: ENS/Base names for attestor display
+	prices      *PriceFeed                    // Optional: converts gas spend to USD
 	resolutions map[string]*models.Resolution // In-memory store (replace with DB)
 	issues      map[string]*models.Issue      // In-memory store (replace with DB)
 	criteria    models.ResolutionCriteria
+	tenants     map[string]models.ResolutionCriteria // Tenants' own criteria, replacing criteria for their resolutions
+	sla         config.SLASettings
+	velocity    config.VelocitySettings
+	observers   []IssueObserver
+	deliveries  map[deliveryKey][]issueEvent // Events waiting for each observer, per issue
+	deliveryMu  sync.Mutex
+	evidence    *EvidenceStore     // Optional: stores evidence bundles by hash
+	linker      AnnouncementLinker // Optional: links resolutions to exchange announcements
+	attesting   map[string]bool    // Resolutions with an attestation being submitted
 	mu          sync.RWMutex
 }
 
+// IssueObserver is notified whenever a tracked issue changes state.
+// Event is one of "detected", "updated", "resolved", "attested", "archived", "unarchived",
+// "sla_breached", "velocity_alert".
+type IssueObserver interface {
+	IssueChanged(ctx context.Context, event string, issue models.Issue)
+}
+
+// AnnouncementLinker finds the exchange announcements that may explain a resolution
+type AnnouncementLinker interface {
+	Links(resolution *models.Resolution) []models.AnnouncementLink
+}
+
 // NewResolutionService creates a new resolution service
 func NewResolutionService(blockchain *BlockchainService) *ResolutionService {
 	return &ResolutionService{
@@ -28,29 +58,109 @@ func NewResolutionService(blockchain *BlockchainService) *ResolutionService {
 		resolutions: make(map[string]*models.Resolution),
 		issues:      make(map[string]*models.Issue),
 		criteria:    models.DefaultResolutionCriteria(),
+		tenants:     make(map[string]models.ResolutionCriteria),
+		deliveries:  make(map[deliveryKey][]issueEvent),
+		attesting:   make(map[string]bool),
+		sla:         config.DefaultSLASettings(),
+		velocity:    config.DefaultVelocitySettings(),
 	}
 }
 
+// AddObserver registers an observer for issue lifecycle events
+func (rs *ResolutionService) AddObserver(observer IssueObserver) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.observers = append(rs.observers, observer)
+}
+
+// SetExplorer enables block explorer enrichment of attestations
+func (rs *ResolutionService) SetExplorer(explorer *ExplorerClient) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.explorer = explorer
+}
+
+// SetNameResolver enables ENS/Base name display for attestors
+func (rs *ResolutionService) SetNameResolver(names *NameResolver) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.names = names
+}
+
+// SetPriceFeed enables USD conversion of gas spend in stats and cost estimates
+func (rs *ResolutionService) SetPriceFeed(prices *PriceFeed) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.prices = prices
+}
+
+// SetAnnouncementLinker links new resolutions to exchange announcements
+func (rs *ResolutionService) SetAnnouncementLinker(linker AnnouncementLinker) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.linker = linker
+}
+
+// GasCost converts a wei amount to ETH and, with a price feed, USD
+func (rs *ResolutionService) GasCost(ctx context.Context, wei *big.Int) models.GasCost {
+	rs.mu.RLock()
+	prices := rs.prices
+	rs.mu.RUnlock()
+	return prices.GasCost(ctx, wei)
+}
+
 // ============================================
 // ISSUE MANAGEMENT
 // ============================================
 
+var (
+	// ErrIssueNotFound is returned for an unknown issue ID
+	ErrIssueNotFound = errors.New("issue not found")
+	// ErrResolutionNotFound is returned for an unknown resolution ID
+	ErrResolutionNotFound = errors.New("resolution not found")
+	// ErrNotAttestable is returned for attesting a resolution waiting for review or rejected
+	ErrNotAttestable = errors.New("can't be attested")
+	// ErrAttestationInProgress is returned for attesting a resolution whose attestation is being submitted
+	ErrAttestationInProgress = errors.New("attestation already in progress")
+)
+
 // CreateIssue creates a new issue being tracked
 func (rs *ResolutionService) CreateIssue(issue *models.Issue) (*models.Issue, error) {
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
+	return rs.createIssue(issue), nil
+}
 
+// createIssue stores a new issue. Must be called with rs.mu held.
+func (rs *ResolutionService) createIssue(issue *models.Issue) *models.Issue {
 	// Generate ID if not set
 	if issue.ID == "" {
 		issue.ID = generateID()
 	}
 
-	issue.FirstDetected = time.Now()
-	issue.LastUpdated = time.Now()
+	// Map category aliases onto the shared taxonomy ID
+	if c, ok := models.LookupCategory(issue.Category); ok {
+		issue.Category = c.ID
+	}
+
+	// Keep a detection time supplied by the caller (e.g. when the first complaint was
+	// posted), but not one in the future: SLAs and velocity count from it
+	now := time.Now()
+	if issue.FirstDetected.IsZero() || issue.FirstDetected.After(now) {
+		issue.FirstDetected = now
+	}
+	// Normalized counts are always derived from the stored effort
+	issue.Normalized = nil
+	if issue.Effort != nil {
+		normalized := issue.Effort.Normalize(issue.ComplaintCount)
+		issue.Normalized = &normalized
+	}
+	issue.LastUpdated = now
 	issue.Status = "active"
 
 	rs.issues[issue.ID] = issue
-	return issue, nil
+	rs.notify("detected", issue)
+	return issue
 }
 
 // GetIssue retrieves an issue by ID
@@ -65,20 +175,99 @@ func (rs *ResolutionService) GetIssue(id string) (*models.Issue, error) {
 	return issue, nil
 }
 
-// ListIssues returns all tracked issues
+// ArchiveFilter selects whether archived issues appear in issue lists
+type ArchiveFilter string
+
+const (
+	ArchivedExclude ArchiveFilter = ""        // Default: hide archived issues
+	ArchivedInclude ArchiveFilter = "include" // Archived and live issues
+	ArchivedOnly    ArchiveFilter = "only"    // Archived issues only
+)
+
+// ParseArchiveFilter validates an ?archived= query value
+func ParseArchiveFilter(v string) (ArchiveFilter, error) {
+	switch f := ArchiveFilter(v); f {
+	case ArchivedExclude, ArchivedInclude, ArchivedOnly:
+		return f, nil
+	case "exclude":
+		return ArchivedExclude, nil
+	default:
+		return "", fmt.Errorf("archived must be one of: exclude, include, only")
+	}
+}
+
+// ListIssues returns all tracked issues that are not archived
 func (rs *ResolutionService) ListIssues(status string) []*models.Issue {
+	return rs.ListIssuesFiltered(status, ArchivedExclude)
+}
+
+// ListIssuesFiltered returns tracked issues by status and archive state
+func (rs *ResolutionService) ListIssuesFiltered(status string, archived ArchiveFilter) []*models.Issue {
 	rs.mu.RLock()
 	defer rs.mu.RUnlock()
 
 	var results []*models.Issue
 	for _, issue := range rs.issues {
-		if status == "" || issue.Status == status {
-			results = append(results, issue)
+		if status != "" && issue.Status != status {
+			continue
 		}
+		switch {
+		case archived == ArchivedExclude && issue.IsArchived():
+			continue
+		case archived == ArchivedOnly && !issue.IsArchived():
+			continue
+		}
+		results = append(results, issue)
 	}
 	return results
 }
 
+// ArchiveIssue soft-deletes an issue. It stays retrievable by ID, and its
+// resolutions and attestations are untouched, but it is hidden from default lists.
+func (rs *ResolutionService) ArchiveIssue(id, archivedBy, reason string) (*models.Issue, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	issue, ok := rs.issues[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, id)
+	}
+	if issue.IsArchived() {
+		return nil, fmt.Errorf("issue already archived: %s", id)
+	}
+
+	now := time.Now()
+	issue.ArchivedAt = &now
+	issue.ArchivedBy = archivedBy
+	issue.ArchiveReason = reason
+	issue.LastUpdated = now
+
+	rs.notify("archived", issue)
+	return issue, nil
+}
+
+// UnarchiveIssue restores an archived issue to the default lists
+func (rs *ResolutionService) UnarchiveIssue(id string) (*models.Issue, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	issue, ok := rs.issues[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, id)
+	}
+	if !issue.IsArchived() {
+		return nil, fmt.Errorf("issue is not archived: %s", id)
+	}
+
+	issue.ArchivedAt = nil
+	issue.ArchivedBy = ""
+	issue.ArchiveReason = ""
+	issue.LastUpdated = time.Now()
+
+	rs.notify("unarchived", issue)
+	return issue, nil
+}
+
 // UpdateIssue updates an existing issue
 func (rs *ResolutionService) UpdateIssue(id string, update *models.Issue) (*models.Issue, error) {
 	rs.mu.Lock()
@@ -93,6 +282,9 @@ func (rs *ResolutionService) UpdateIssue(id string, update *models.Issue) (*mode
 	if update.ComplaintCount > 0 {
 		issue.ComplaintCount = update.ComplaintCount
 	}
+	if update.WeightedCount > 0 {
+		issue.WeightedCount = update.WeightedCount
+	}
 	if update.Severity != "" {
 		issue.Severity = update.Severity
 	}
@@ -102,8 +294,22 @@ func (rs *ResolutionService) UpdateIssue(id string, update *models.Issue) (*mode
 	if update.Description != "" {
 		issue.Description = update.Description
 	}
+	if len(update.ComplaintLinks) > 0 {
+		issue.ComplaintLinks = update.ComplaintLinks
+	}
+	if update.Effort != nil {
+		issue.Effort = update.Effort
+	}
+	if update.Reach != nil {
+		issue.Reach = update.Reach
+	}
+	if issue.Effort != nil {
+		normalized := issue.Effort.Normalize(issue.ComplaintCount)
+		issue.Normalized = &normalized
+	}
 	issue.LastUpdated = time.Now()
 
+	rs.notify("updated", issue)
 	return issue, nil
 }
 
@@ -118,6 +324,12 @@ func (rs *ResolutionService) CreateResolution(
 	evidence *models.ResolutionEvidence,
 	summary string,
 ) (*models.Resolution, error) {
+	// Reject nonsense evidence before it can affect confidence scoring or reach the chain
+	if err := ValidateEvidence(evidence, time.Now()); err != nil {
+		return nil, err
+	}
+	NormalizeEvidence(evidence)
+
 	rs.mu.Lock()
 	defer rs.mu.Unlock()
 
@@ -127,36 +339,62 @@ func (rs *ResolutionService) CreateResolution(
 		return nil, fmt.Errorf("issue not found: %s", issueID)
 	}
 
-	// Calculate confidence score
-	confidence := rs.calculateConfidence(evidence)
+	resolution := rs.newResolution(issue, evidence, summary)
+
+	// Check if meets criteria for auto-verification. Exchanges with auto-verification
+	// off send it to review instead, and the issue stays open until it is approved.
+	if rs.meetsResolutionCriteria(resolution) {
+		if rs.criteriaFor(resolution.Tenant).ForExchange(resolution.Exchange).AutoVerify {
+			resolution.Status = "verified"
+			resolution.VerifiedVia = models.VerifiedAuto
+			now := time.Now()
+			resolution.VerifiedAt = &now
+		} else {
+			resolution.Status = "needs_review"
+		}
+	}
+
+	rs.resolutions[resolution.ID] = resolution
+	if resolution.Status != "needs_review" {
+		rs.resolveIssue(issue, resolution)
+	}
+	return resolution, nil
+}
+
+// newResolution builds a pending resolution of issue from validated, normalized
+// evidence. Must be called with rs.mu held.
+func (rs *ResolutionService) newResolution(issue *models.Issue, evidence *models.ResolutionEvidence, summary string) *models.Resolution {
+	// Standardize the methodology unless the caller described a custom one
+	if strings.TrimSpace(evidence.AnalysisMethodology) == "" {
+		evidence.AnalysisMethodology = SelectMethodology(evidence, rs.criteria).Render(evidence)
+	}
 
 	resolution := &models.Resolution{
 		ID:               generateID(),
+		IssueID:          issue.ID,
+		Tenant:           issue.Tenant,
 		Exchange:         issue.Exchange,
 		IssueCategory:    issue.Category,
 		Summary:          summary,
 		Evidence:         *evidence,
-		Confidence:       confidence,
+		Confidence:       rs.calculateConfidence(evidence),
 		ResolutionWindow: int(evidence.MeasurementEnd.Sub(evidence.MeasurementStart).Hours() / 24),
 		Status:           "pending",
 		CreatedAt:        time.Now(),
 	}
-
-	// Check if meets criteria for auto-verification
-	if rs.meetsResolutionCriteria(resolution) {
-		resolution.Status = "verified"
-		now := time.Now()
-		resolution.VerifiedAt = &now
+	if rs.linker != nil {
+		resolution.Announcements = rs.linker.Links(resolution)
 	}
+	return resolution
+}
 
-	rs.resolutions[resolution.ID] = resolution
-
-	// Update issue status
+// resolveIssue marks issue resolved by resolution. Must be called with rs.mu held.
+func (rs *ResolutionService) resolveIssue(issue *models.Issue, resolution *models.Resolution) {
 	issue.Status = "resolved"
 	issue.Resolution = resolution
 	issue.LastUpdated = time.Now()
 
-	return resolution, nil
+	rs.notify("resolved", issue)
 }
 
 // GetResolution retrieves a resolution by ID
@@ -166,7 +404,7 @@ func (rs *ResolutionService) GetResolution(id string) (*models.Resolution, error
 
 	resolution, ok := rs.resolutions[id]
 	if !ok {
-		return nil, fmt.Errorf("resolution not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, id)
 	}
 	return resolution, nil
 }
@@ -185,37 +423,148 @@ func (rs *ResolutionService) ListResolutions(status string) []*models.Resolution
 	return results
 }
 
+// ValidateMetadataURI checks that a metadata URI is an ipfs:// CID or an http(s) URL
+func ValidateMetadataURI(uri string) error {
+	if !strings.HasPrefix(uri, "ipfs://") && !strings.HasPrefix(uri, "https://") && !strings.HasPrefix(uri, "http://") {
+		return fmt.Errorf("metadata_uri must be an ipfs://, https:// or http:// URI")
+	}
+	return nil
+}
+
+// SetMetadataURI records where a resolution's evidence is published (an ipfs:// CID or
+// an http(s) URL). It is emitted on-chain with the attestation, so it can't change once
+// an attestation is being submitted or afterwards.
+func (rs *ResolutionService) SetMetadataURI(resolutionID, uri string) (*models.Resolution, error) {
+	if err := ValidateMetadataURI(uri); err != nil {
+		return nil, err
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	resolution, ok := rs.resolutions[resolutionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
+	}
+	if resolution.Attestation != nil {
+		return nil, fmt.Errorf("resolution %s is already attested", resolutionID)
+	}
+	if rs.attesting[resolutionID] {
+		return nil, fmt.Errorf("%w: %s", ErrAttestationInProgress, resolutionID)
+	}
+	resolution.MetadataURI = uri
+	return resolution, nil
+}
+
+// RelinkAnnouncements replaces a resolution's linked announcements with those the
+// linker finds now, e.g. after new announcements are fetched. Announcements aren't
+// part of the evidence, so attested resolutions can be relinked too.
+func (rs *ResolutionService) RelinkAnnouncements(resolutionID string) (*models.Resolution, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.linker == nil {
+		return nil, fmt.Errorf("announcement linking not configured")
+	}
+	resolution, ok := rs.resolutions[resolutionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
+	}
+	resolution.Announcements = rs.linker.Links(resolution)
+	return resolution, nil
+}
+
 // ============================================
 // ON-CHAIN ATTESTATION
 // ============================================
 
-// AttestResolution records a resolution on the blockchain
+// AttestResolution records a resolution on the blockchain. Pinning the evidence,
+// the transaction and the explorer and name lookups run without holding the lock;
+// the resolution is marked as being attested meanwhile, so a second call can't
+// submit it twice, and is re-read before the attestation is stored.
 func (rs *ResolutionService) AttestResolution(ctx context.Context, resolutionID string) (*models.Attestation, error) {
 	rs.mu.Lock()
-	defer rs.mu.Unlock()
-
 	resolution, ok := rs.resolutions[resolutionID]
 	if !ok {
-		return nil, fmt.Errorf("resolution not found: %s", resolutionID)
+		rs.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
 	}
 
 	// Check if already attested
 	if resolution.Attestation != nil {
+		rs.mu.Unlock()
 		return resolution.Attestation, nil
 	}
+	if resolution.Status == "needs_review" || resolution.Status == "rejected" {
+		rs.mu.Unlock()
+		return nil, fmt.Errorf("resolution %s is %s and %w", resolutionID, resolution.Status, ErrNotAttestable)
+	}
 
 	// Check if blockchain service is available
 	if rs.blockchain == nil {
+		rs.mu.Unlock()
 		return nil, fmt.Errorf("blockchain service not configured")
 	}
+	if rs.attesting[resolutionID] {
+		rs.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrAttestationInProgress, resolutionID)
+	}
+	rs.attesting[resolutionID] = true
+	pending := cloneResolution(resolution)
+	blockchain, evidence, names, explorer := rs.blockchain, rs.evidence, rs.names, rs.explorer
+	rs.mu.Unlock()
+
+	defer func() {
+		rs.mu.Lock()
+		delete(rs.attesting, resolutionID)
+		rs.mu.Unlock()
+	}()
+
+	// Publish the evidence first, so the on-chain record can point at it
+	evidenceCID := ""
+	if evidence != nil && evidence.Pinning() {
+		cid, err := evidence.PinEvidence(ctx, &pending.Evidence)
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish evidence: %w", err)
+		}
+		evidenceCID = cid
+		if pending.MetadataURI == "" {
+			pending.MetadataURI = "ipfs://" + cid
+		}
+	}
 
 	// Record attestation
-	attestation, err := rs.blockchain.RecordAttestation(ctx, resolution)
+	attestation, err := blockchain.RecordAttestation(ctx, pending)
 	if err != nil {
 		return nil, fmt.Errorf("failed to record attestation: %w", err)
 	}
+	attestation.EvidenceCID = evidenceCID
+
+	names.Annotate(ctx, attestation)
+
+	// Best effort: the explorer may not have indexed the tx yet, RefreshAttestation retries later
+	if explorer != nil {
+		if details, err := explorer.Enrich(ctx, attestation); err == nil {
+			attestation.Explorer = details
+		} else {
+			fmt.Printf("⚠️  Explorer enrichment failed: %v\n", err)
+		}
+	}
 
-	// Update resolution
+	// Store it against the resolution as it is now. The transaction is on-chain
+	// either way, so only a resolution that has gone away loses it.
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	resolution, ok = rs.resolutions[resolutionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (attested in %s)", ErrResolutionNotFound, resolutionID, attestation.TransactionHash)
+	}
+	if resolution.Attestation != nil {
+		return resolution.Attestation, nil
+	}
+	if resolution.MetadataURI == "" {
+		resolution.MetadataURI = pending.MetadataURI
+	}
 	resolution.Attestation = attestation
 	resolution.Status = "on_chain"
 
@@ -224,6 +573,7 @@ func (rs *ResolutionService) AttestResolution(ctx context.Context, resolutionID
 		if issue.Resolution != nil && issue.Resolution.ID == resolutionID {
 			issue.Attestation = attestation
 			issue.Status = "verified"
+			rs.notify("attested", issue)
 			break
 		}
 	}
@@ -231,6 +581,77 @@ func (rs *ResolutionService) AttestResolution(ctx context.Context, resolutionID
 	return attestation, nil
 }
 
+// AttestedResolutions returns every resolution with an on-chain attestation, oldest block first
+func (rs *ResolutionService) AttestedResolutions() []models.Resolution {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	var results []models.Resolution
+	for _, resolution := range rs.resolutions {
+		if resolution.Attestation != nil {
+			results = append(results, *resolution)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Attestation.BlockNumber != results[j].Attestation.BlockNumber {
+			return results[i].Attestation.BlockNumber < results[j].Attestation.BlockNumber
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
+
+// ExplorerRefreshInterval limits how often an attestation is re-checked on the explorer
+const ExplorerRefreshInterval = time.Minute
+
+// RefreshAttestation re-reads a resolution's attestation from the block explorer
+// (confirmations, gas, contract verification). Without an explorer, or if the
+// details are fresh, the stored attestation is returned unchanged.
+func (rs *ResolutionService) RefreshAttestation(ctx context.Context, resolutionID string) (*models.Attestation, error) {
+	rs.mu.RLock()
+	resolution, ok := rs.resolutions[resolutionID]
+	if !ok {
+		rs.mu.RUnlock()
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
+	}
+	attestation := resolution.Attestation
+	explorer := rs.explorer
+	names := rs.names
+	rs.mu.RUnlock()
+
+	if attestation == nil {
+		return nil, fmt.Errorf("resolution not yet attested: %s", resolutionID)
+	}
+	needsName := names != nil && attestation.AttestorName == ""
+	needsExplorer := explorer != nil && (attestation.Explorer == nil || time.Since(attestation.Explorer.CheckedAt) >= ExplorerRefreshInterval)
+	if !needsName && !needsExplorer {
+		return attestation, nil
+	}
+
+	// Query the explorer and name registries without holding the lock
+	refreshed := *attestation
+	if needsName {
+		names.Annotate(ctx, &refreshed)
+	}
+	if needsExplorer {
+		details, err := explorer.Enrich(ctx, attestation)
+		if err != nil {
+			return attestation, fmt.Errorf("failed to refresh attestation: %w", err)
+		}
+		refreshed.Explorer = details
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	resolution.Attestation = &refreshed
+	for _, issue := range rs.issues {
+		if issue.Resolution != nil && issue.Resolution.ID == resolutionID {
+			issue.Attestation = &refreshed
+		}
+	}
+	return &refreshed, nil
+}
+
 // VerifyResolution verifies an attestation exists on-chain
 func (rs *ResolutionService) VerifyResolution(ctx context.Context, resolutionID string) (*models.VerificationResponse, error) {
 	resolution, err := rs.GetResolution(resolutionID)
@@ -249,7 +670,7 @@ func (rs *ResolutionService) VerifyResolution(ctx context.Context, resolutionID
 	}
 
 	// Verify on chain
-	return rs.blockchain.VerifyAttestation(ctx, evidenceHash)
+	return rs.verifyOnChain(ctx, evidenceHash)
 }
 
 // VerifyByHash verifies an attestation by evidence hash
@@ -258,7 +679,20 @@ func (rs *ResolutionService) VerifyByHash(ctx context.Context, evidenceHash stri
 		return nil, fmt.Errorf("blockchain service not configured")
 	}
 
-	return rs.blockchain.VerifyAttestation(ctx, evidenceHash)
+	return rs.verifyOnChain(ctx, evidenceHash)
+}
+
+// verifyOnChain checks a hash on-chain and names the attestor of any match
+func (rs *ResolutionService) verifyOnChain(ctx context.Context, evidenceHash string) (*models.VerificationResponse, error) {
+	response, err := rs.blockchain.VerifyAttestation(ctx, evidenceHash)
+	if err != nil {
+		return nil, err
+	}
+	rs.mu.RLock()
+	names := rs.names
+	rs.mu.RUnlock()
+	names.Annotate(ctx, response.Attestation)
+	return response, nil
 }
 
 // ============================================
@@ -269,12 +703,17 @@ func (rs *ResolutionService) VerifyByHash(ctx context.Context, evidenceHash stri
 func (rs *ResolutionService) calculateConfidence(evidence *models.ResolutionEvidence) float64 {
 	confidence := 0.0
 
-	// Base confidence from percentage decrease
-	if evidence.PercentageDecrease >= 0.9 {
+	// Base confidence from percentage decrease, weighing sources by trust when the
+	// evidence breaks its counts down per source
+	decrease := evidence.PercentageDecrease
+	if weighted, ok := WeightedDecrease(evidence, rs.criteria); ok {
+		decrease = weighted
+	}
+	if decrease >= 0.9 {
 		confidence = 0.95
-	} else if evidence.PercentageDecrease >= 0.7 {
+	} else if decrease >= 0.7 {
 		confidence = 0.85
-	} else if evidence.PercentageDecrease >= 0.5 {
+	} else if decrease >= 0.5 {
 		confidence = 0.70
 	} else {
 		confidence = 0.50
@@ -285,8 +724,8 @@ func (rs *ResolutionService) calculateConfidence(evidence *models.ResolutionEvid
 		confidence += 0.05
 	}
 
-	// Bonus for multiple data sources
-	if len(evidence.DataSources) >= 3 {
+	// Bonus for multiple data sources, or fewer trusted ones
+	if SourceTrust(evidence.DataSources, rs.criteria) >= 3 {
 		confidence += 0.03
 	}
 
@@ -300,27 +739,116 @@ func (rs *ResolutionService) calculateConfidence(evidence *models.ResolutionEvid
 
 // meetsResolutionCriteria checks if a resolution meets auto-verification criteria
 func (rs *ResolutionService) meetsResolutionCriteria(resolution *models.Resolution) bool {
+	return len(rs.unmetCriteria(resolution)) == 0
+}
+
+// unmetCriteria describes each auto-verification criterion in effect for the
+// resolution's exchange that it falls short of
+func (rs *ResolutionService) unmetCriteria(resolution *models.Resolution) []string {
+	var unmet []string
+	criteria := rs.criteriaFor(resolution.Tenant).ForExchange(resolution.Exchange)
+
 	// Check percentage decrease
-	if resolution.Evidence.PercentageDecrease < rs.criteria.MinPercentageDecrease {
-		return false
+	if resolution.Evidence.PercentageDecrease < criteria.MinPercentageDecrease {
+		unmet = append(unmet, fmt.Sprintf("complaints fell %.0f%%, criteria require %.0f%%",
+			resolution.Evidence.PercentageDecrease*100, criteria.MinPercentageDecrease*100))
 	}
 
 	// Check confidence
-	if resolution.Confidence < rs.criteria.MinConfidence {
-		return false
+	if resolution.Confidence < criteria.MinConfidence {
+		unmet = append(unmet, fmt.Sprintf("confidence %.2f is below %.2f", resolution.Confidence, criteria.MinConfidence))
 	}
 
 	// Check window duration
-	if resolution.ResolutionWindow < rs.criteria.MinWindowDays {
-		return false
+	if resolution.ResolutionWindow < criteria.MinWindowDays {
+		unmet = append(unmet, fmt.Sprintf("measured over %d days, criteria require %d", resolution.ResolutionWindow, criteria.MinWindowDays))
 	}
 
 	// Check sentiment if required
-	if rs.criteria.RequirePositiveSentiment && resolution.Evidence.SentimentShift <= 0 {
-		return false
+	if criteria.RequirePositiveSentiment && resolution.Evidence.SentimentShift <= 0 {
+		unmet = append(unmet, "sentiment did not improve")
+	}
+
+	return unmet
+}
+
+// deliveryKey is one observer's event queue for one issue
+type deliveryKey struct {
+	observer int
+	issueID  string
+}
+
+type issueEvent struct {
+	event string
+	issue models.Issue
+}
+
+// notify fans an issue event out to all observers.
+// Must be called with rs.mu held; observers receive their own deep copy and run
+// asynchronously so slow external integrations never block API requests. Each
+// observer gets an issue's events in the order they happened.
+func (rs *ResolutionService) notify(event string, issue *models.Issue) {
+	for i, observer := range rs.observers {
+		rs.enqueue(deliveryKey{observer: i, issueID: issue.ID}, observer, issueEvent{event: event, issue: cloneIssue(issue)})
 	}
+}
 
-	return true
+// enqueue queues an event for an observer, starting a worker for the queue if none is running
+func (rs *ResolutionService) enqueue(key deliveryKey, observer IssueObserver, ev issueEvent) {
+	rs.deliveryMu.Lock()
+	defer rs.deliveryMu.Unlock()
+
+	queue, running := rs.deliveries[key]
+	rs.deliveries[key] = append(queue, ev)
+	if !running {
+		go rs.deliver(key, observer)
+	}
+}
+
+// deliver hands a queue's events to its observer one at a time, until the queue is empty
+func (rs *ResolutionService) deliver(key deliveryKey, observer IssueObserver) {
+	for {
+		rs.deliveryMu.Lock()
+		queue := rs.deliveries[key]
+		if len(queue) == 0 {
+			delete(rs.deliveries, key)
+			rs.deliveryMu.Unlock()
+			return
+		}
+		ev := queue[0]
+		rs.deliveries[key] = queue[1:]
+		rs.deliveryMu.Unlock()
+
+		observer.IssueChanged(context.Background(), ev.event, ev.issue)
+	}
+}
+
+// cloneIssue deep-copies an issue, so an observer can't see (or race with) later
+// changes to its resolution, attestation or velocity
+func cloneIssue(issue *models.Issue) models.Issue {
+	var clone models.Issue
+	data, err := json.Marshal(issue)
+	if err == nil {
+		err = json.Unmarshal(data, &clone)
+	}
+	if err != nil {
+		// Plain data always round-trips; keep the top level apart at least
+		return *issue
+	}
+	return clone
+}
+
+// cloneResolution copies a resolution, like cloneIssue, so it can be read without the lock
+func cloneResolution(resolution *models.Resolution) *models.Resolution {
+	var clone models.Resolution
+	data, err := json.Marshal(resolution)
+	if err == nil {
+		err = json.Unmarshal(data, &clone)
+	}
+	if err != nil {
+		clone = *resolution
+	}
+	return &clone
 }
 
 // generateID generates a random ID
@@ -334,36 +862,59 @@ func generateID() string {
 // STATISTICS
 // ============================================
 
-// GetStats returns resolution statistics
-func (rs *ResolutionService) GetStats() map[string]interface{} {
-	rs.mu.RLock()
-	defer rs.mu.RUnlock()
-
-	stats := map[string]interface{}{
-		"total_issues":      len(rs.issues),
-		"total_resolutions": len(rs.resolutions),
-		"issues_by_status":  make(map[string]int),
-		"attestation_count": 0,
+// GetStats returns resolution statistics over the issues and resolutions whose tenant
+// visible accepts (nil: all of them). Gas spent covers the attestations the block
+// explorer has reported a fee for.
+func (rs *ResolutionService) GetStats(ctx context.Context, visible func(tenant string) bool) map[string]interface{} {
+	if visible == nil {
+		visible = func(string) bool { return true }
 	}
 
-	issuesByStatus := stats["issues_by_status"].(map[string]int)
-	attestationCount := 0
-
+	rs.mu.RLock()
+	issueCount := 0
+	issuesByStatus := make(map[string]int)
 	for _, issue := range rs.issues {
+		if !visible(issue.Tenant) {
+			continue
+		}
+		issueCount++
 		issuesByStatus[issue.Status]++
 	}
 
+	resolutionCount, attestationCount, pricedCount := 0, 0, 0
+	gasSpent := new(big.Int)
 	for _, resolution := range rs.resolutions {
-		if resolution.Attestation != nil {
-			attestationCount++
+		if !visible(resolution.Tenant) {
+			continue
+		}
+		resolutionCount++
+		if resolution.Attestation == nil {
+			continue
+		}
+		attestationCount++
+		if explorer := resolution.Attestation.Explorer; explorer != nil {
+			if fee, ok := new(big.Int).SetString(explorer.FeeWei, 10); ok {
+				gasSpent.Add(gasSpent, fee)
+				pricedCount++
+			}
 		}
 	}
 
-	stats["attestation_count"] = attestationCount
+	stats := map[string]interface{}{
+		"total_issues":      issueCount,
+		"total_resolutions": resolutionCount,
+		"issues_by_status":  issuesByStatus,
+		"attestation_count": attestationCount,
+	}
+	rs.mu.RUnlock()
+
+	// Network lookups run without the lock
+	stats["gas_spent"] = rs.GasCost(ctx, gasSpent)
+	stats["gas_spent_attestations"] = pricedCount
 
 	// Get on-chain count if available
 	if rs.blockchain != nil {
-		if count, err := rs.blockchain.GetAttestationCount(context.Background()); err == nil {
+		if count, err := rs.blockchain.GetAttestationCount(ctx); err == nil {
 			stats["on_chain_attestation_count"] = count
 		}
 	}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// RESOLUTION DETECTION
+// ============================================
+// Issues open themselves when a category's complaints cross a threshold, but until
+// now someone had to notice the drop and POST a resolution by hand. The detector
+// keeps each scrape run's complaint count, sentiment and sources per category, and
+// after every run compares open issues' categories with an earlier run. A drop that
+// meets the resolution criteria becomes a candidate resolution in "needs_review":
+// it doesn't resolve the issue or become attestable until someone approves it.
+//
+// Runs a day apart can't show a drop over the criteria's MinWindowDays, so the
+// baseline is the latest earlier run at least that long before the current one.
+
+const (
+	// DetectionRunsFile holds per-run category counts inside the data directory
+	DetectionRunsFile = "resolution_detection.json"
+	// MaxDetectionRuns is how many runs the detector keeps
+	MaxDetectionRuns = 90
+)
+
+// DetectionRun is one scrape run's complaints, summarized per category
+type DetectionRun struct {
+	RunID      string                       `json:"run_id"`
+	At         time.Time                    `json:"at"` // Latest scrape time among the run's complaints
+	Effort     *models.ScrapeEffort         `json:"effort,omitempty"`
+	Categories map[string]DetectionCategory `json:"categories"` // Taxonomy category ID -> summary
+}
+
+// DetectionCategory is one category's complaints in a run
+type DetectionCategory struct {
+	Complaints int            `json:"complaints"`
+	Sentiment  float64        `json:"sentiment"` // Mean over complaints that expressed any sentiment, -1 to 1
+	Scored     int            `json:"scored"`
+	Sources    map[string]int `json:"sources"`
+	Samples    []string       `json:"samples"` // Representative complaint IDs
+}
+
+// ResolutionDetector proposes resolutions from the drop in complaints between scrape runs
+type ResolutionDetector struct {
+	rs   *ResolutionService
+	path string
+	runs []DetectionRun // Oldest first
+	mu   sync.Mutex
+}
+
+// NewResolutionDetector loads the run history from dataDir (empty if the file doesn't exist yet)
+func NewResolutionDetector(rs *ResolutionService, dataDir string) (*ResolutionDetector, error) {
+	d := &ResolutionDetector{rs: rs, path: filepath.Join(dataDir, DetectionRunsFile)}
+
+	data, err := os.ReadFile(d.path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resolution detection runs: %w", err)
+	}
+	if err := json.Unmarshal(data, &d.runs); err != nil {
+		return nil, fmt.Errorf("failed to parse resolution detection runs: %w", err)
+	}
+	return d, nil
+}
+
+// Detect records the analyzed run's complaints and proposes a candidate resolution
+// for each open issue of the exchange whose category dropped enough since the
+// baseline run. Analyzing the same run again replaces its record. Analyses without
+// a run ID can't be told apart and are ignored.
+func (d *ResolutionDetector) Detect(exchange string, analysis *analyzer.AnalysisResult, complaints []models.Complaint) ([]*models.Resolution, error) {
+	candidates := []*models.Resolution{}
+	if analysis == nil || analysis.RunID == "" {
+		return candidates, nil
+	}
+	run := summarizeRun(analysis, complaints)
+
+	d.mu.Lock()
+	minWindow := time.Duration(d.rs.Criteria().MinWindowDays) * 24 * time.Hour
+	var baseline *DetectionRun
+	for i := len(d.runs) - 1; i >= 0; i-- {
+		if d.runs[i].RunID != run.RunID && !d.runs[i].At.After(run.At.Add(-minWindow)) {
+			baseline = &d.runs[i]
+			break
+		}
+	}
+	var before DetectionRun
+	if baseline != nil {
+		before = *baseline
+	}
+	if i := slices.IndexFunc(d.runs, func(r DetectionRun) bool { return r.RunID == run.RunID }); i >= 0 {
+		d.runs[i] = run
+	} else {
+		d.runs = append(d.runs, run)
+	}
+	if len(d.runs) > MaxDetectionRuns {
+		d.runs = append([]DetectionRun(nil), d.runs[len(d.runs)-MaxDetectionRuns:]...)
+	}
+	err := d.save()
+	d.mu.Unlock()
+	if err != nil {
+		return candidates, err
+	}
+	if baseline == nil {
+		return candidates, nil
+	}
+
+	for _, issue := range d.rs.ListIssues("") {
+		if issue.Tenant != "" || issue.Exchange != exchange || issue.IsArchived() || (issue.Status != "active" && issue.Status != "investigating") {
+			continue
+		}
+		evidence, ok := runEvidence(before, run, models.NormalizeCategory(issue.Category))
+		if !ok {
+			continue
+		}
+		summary := fmt.Sprintf("%s complaints fell %.0f%% between scrape runs %s and %s (detected automatically)",
+			issue.Category, evidence.PercentageDecrease*100, before.RunID, run.RunID)
+		candidate, err := d.rs.ProposeResolution(issue.ID, evidence, summary)
+		var invalid *EvidenceValidationError
+		if errors.As(err, &invalid) {
+			continue // e.g. the drop came from scraping less
+		}
+		if err != nil {
+			return candidates, err
+		}
+		if candidate != nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].IssueCategory < candidates[j].IssueCategory })
+	return candidates, nil
+}
+
+// summarizeRun reduces the analyzed run's complaints to per-category summaries
+func summarizeRun(analysis *analyzer.AnalysisResult, complaints []models.Complaint) DetectionRun {
+	effort := analysis.Effort
+	run := DetectionRun{
+		RunID:      analysis.RunID,
+		At:         analysis.AnalyzedAt,
+		Effort:     &effort,
+		Categories: make(map[string]DetectionCategory),
+	}
+
+	byCategory := make(map[string][]models.Complaint)
+	latest := time.Time{}
+	for _, c := range complaints {
+		if c.RunID != analysis.RunID {
+			continue
+		}
+		byCategory[c.Category] = append(byCategory[c.Category], c)
+		if c.ScrapedAt.After(latest) {
+			latest = c.ScrapedAt
+		}
+	}
+	if !latest.IsZero() {
+		run.At = latest
+	}
+
+	for category, inCategory := range byCategory {
+		period := analyzer.SummarizePeriod(inCategory, run.At, run.At)
+		summary := DetectionCategory{
+			Complaints: period.Complaints,
+			Sentiment:  period.Sentiment,
+			Scored:     period.Scored,
+			Sources:    period.Sources,
+			Samples:    []string{},
+		}
+		for _, c := range analyzer.SampleComplaints(inCategory, BundleSamplesPerPeriod) {
+			summary.Samples = append(summary.Samples, c.ID)
+		}
+		run.Categories[category] = summary
+	}
+	return run
+}
+
+// runEvidence builds the evidence for a category's drop from before to after, or
+// false if its complaints didn't fall
+func runEvidence(before, after DetectionRun, category string) (*models.ResolutionEvidence, bool) {
+	from, to := before.Categories[category], after.Categories[category]
+	if from.Complaints == 0 || to.Complaints >= from.Complaints {
+		return nil, false
+	}
+
+	evidence := &models.ResolutionEvidence{
+		ComplaintsBefore: from.Complaints,
+		ComplaintsAfter:  to.Complaints,
+		SampleComplaints: []string{},
+		DataSources:      []string{},
+		MeasurementStart: before.At,
+		MeasurementEnd:   after.At,
+		SourcesBefore:    from.Sources,
+		SourcesAfter:     to.Sources,
+		EffortBefore:     before.Effort,
+		EffortAfter:      after.Effort,
+	}
+	if evidence.SourcesAfter == nil {
+		evidence.SourcesAfter = map[string]int{}
+	}
+	decrease := float64(from.Complaints-to.Complaints) / float64(from.Complaints)
+	evidence.PercentageDecrease = math.Round(decrease*10000) / 10000
+	if from.Scored > 0 && to.Scored > 0 {
+		shift := to.Sentiment - from.Sentiment
+		evidence.SentimentShift = math.Max(-1, math.Min(1, math.Round(shift*10000)/10000))
+	}
+	for _, id := range append(append([]string{}, from.Samples...), to.Samples...) {
+		if !slices.Contains(evidence.SampleComplaints, id) { // A complaint re-scraped by both runs is sampled once
+			evidence.SampleComplaints = append(evidence.SampleComplaints, id)
+		}
+	}
+	for _, sources := range []map[string]int{from.Sources, to.Sources} {
+		for source := range sources {
+			if !slices.Contains(evidence.DataSources, source) {
+				evidence.DataSources = append(evidence.DataSources, source)
+			}
+		}
+	}
+	sort.Strings(evidence.DataSources)
+	return evidence, true
+}
+
+// save writes the run history. Must be called with d.mu held.
+func (d *ResolutionDetector) save() error {
+	data, err := json.MarshalIndent(d.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal resolution detection runs: %w", err)
+	}
+	if err := os.WriteFile(d.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write resolution detection runs: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// CANDIDATE REVIEW
+// ============================================
+
+// ProposeResolution adds a candidate resolution of an issue in "needs_review". It
+// returns nil without a candidate when the evidence doesn't meet the resolution
+// criteria, the issue already has a candidate awaiting review, or a rejected
+// candidate's window overlaps this one (the reviewer already saw that data).
+func (rs *ResolutionService) ProposeResolution(issueID string, evidence *models.ResolutionEvidence, summary string) (*models.Resolution, error) {
+	if err := ValidateEvidence(evidence, time.Now()); err != nil {
+		return nil, err
+	}
+	NormalizeEvidence(evidence)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	issue, ok := rs.issues[issueID]
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", issueID)
+	}
+	for _, existing := range rs.resolutions {
+		if existing.IssueID != issueID || !existing.Detected {
+			continue
+		}
+		if existing.Status == "needs_review" {
+			return nil, nil
+		}
+		if existing.Status == "rejected" && existing.Evidence.MeasurementEnd.After(evidence.MeasurementStart) {
+			return nil, nil
+		}
+	}
+
+	resolution := rs.newResolution(issue, evidence, summary)
+	if !rs.meetsResolutionCriteria(resolution) {
+		return nil, nil
+	}
+	resolution.Status = "needs_review"
+	resolution.Detected = true
+	rs.resolutions[resolution.ID] = resolution
+	return resolution, nil
+}
+
+// ReviewResolution approves or rejects a resolution awaiting review: a detected
+// candidate, or one for an exchange with auto-verification off. Approving verifies it
+// and resolves its issue; rejecting keeps it for the record without touching the issue.
+func (rs *ResolutionService) ReviewResolution(resolutionID string, approve bool, reviewedBy, note string) (*models.Resolution, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	resolution, ok := rs.resolutions[resolutionID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrResolutionNotFound, resolutionID)
+	}
+	if resolution.Status != "needs_review" {
+		return nil, fmt.Errorf("resolution %s is %s, not awaiting review", resolutionID, resolution.Status)
+	}
+
+	now := time.Now()
+	resolution.ReviewedBy = reviewedBy
+	resolution.ReviewedAt = &now
+	resolution.ReviewNote = note
+	if !approve {
+		resolution.Status = "rejected"
+		return resolution, nil
+	}
+
+	resolution.Status = "verified"
+	resolution.VerifiedVia = models.VerifiedManual
+	resolution.VerifiedAt = &now
+	if issue, ok := rs.issues[resolution.IssueID]; ok {
+		rs.resolveIssue(issue, resolution)
+	}
+	return resolution, nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// TestMetadataURIFixedOnceAttesting checks the metadata URI can't change under an
+// attestation being submitted, since it's read before the transaction is sent
+func TestMetadataURIFixedOnceAttesting(t *testing.T) {
+	rs := NewResolutionService(nil)
+	rs.resolutions["res-1"] = &models.Resolution{ID: "res-1"}
+
+	if _, err := rs.SetMetadataURI("res-1", "https://example.com/a.json"); err != nil {
+		t.Fatal(err)
+	}
+	rs.attesting["res-1"] = true
+	if _, err := rs.SetMetadataURI("res-1", "https://example.com/b.json"); !errors.Is(err, ErrAttestationInProgress) {
+		t.Fatalf("got %v while attesting, want ErrAttestationInProgress", err)
+	}
+	if uri := rs.resolutions["res-1"].MetadataURI; uri != "https://example.com/a.json" {
+		t.Errorf("metadata URI %q, want it unchanged", uri)
+	}
+}
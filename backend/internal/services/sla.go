@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SEVERITY SLAS
+// ============================================
+// Every open issue is expected to show improvement within its severity's SLA
+// window, counted from first detection. The first time an issue goes overdue,
+// observers get an "sla_breached" event.
+
+// DefaultSLACheckInterval is how often RunSLAMonitor looks for newly overdue issues
+const DefaultSLACheckInterval = time.Hour
+
+// SetSLASettings replaces the SLA windows
+func (rs *ResolutionService) SetSLASettings(settings config.SLASettings) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.sla = settings
+}
+
+// IssueSLA returns how an issue is tracking against its SLA at now.
+// Returns nil for closed or archived issues and severities without an SLA.
+func (rs *ResolutionService) IssueSLA(issue *models.Issue, now time.Time) *models.IssueSLA {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.issueSLA(issue, now)
+}
+
+// WithSLA returns a copy of the issue with its SLA filled in
+func (rs *ResolutionService) WithSLA(issue *models.Issue, now time.Time) models.Issue {
+	view := *issue
+	view.SLA = rs.IssueSLA(issue, now)
+	return view
+}
+
+// issueSLA is IssueSLA for callers already holding rs.mu
+func (rs *ResolutionService) issueSLA(issue *models.Issue, now time.Time) *models.IssueSLA {
+	if issue.IsArchived() || (issue.Status != "active" && issue.Status != "investigating") {
+		return nil
+	}
+	window, ok := rs.sla.Window(issue.Severity)
+	if !ok || issue.FirstDetected.IsZero() {
+		return nil
+	}
+
+	due := issue.FirstDetected.Add(window)
+	return &models.IssueSLA{
+		WindowDays:    int(window.Hours() / 24),
+		DueAt:         due,
+		ElapsedDays:   now.Sub(issue.FirstDetected).Hours() / 24,
+		RemainingDays: due.Sub(now).Hours() / 24,
+		Overdue:       now.After(due),
+	}
+}
+
+// CheckSLAs marks every issue that has gone overdue since the last check and
+// sends each one "sla_breached". Returns the newly breached issues.
+func (rs *ResolutionService) CheckSLAs(now time.Time) []*models.Issue {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	breached := []*models.Issue{}
+	for _, issue := range rs.issues {
+		if issue.SLABreachedAt != nil {
+			continue
+		}
+		sla := rs.issueSLA(issue, now)
+		if sla == nil || !sla.Overdue {
+			continue
+		}
+
+		breachedAt := now
+		issue.SLABreachedAt = &breachedAt
+		view := *issue
+		view.SLA = sla
+		rs.notify("sla_breached", &view)
+		breached = append(breached, issue)
+	}
+	return breached
+}
+
+// RunSLAMonitor checks SLAs every interval until ctx is cancelled
+func (rs *ResolutionService) RunSLAMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if breached := rs.CheckSLAs(now); len(breached) > 0 {
+				fmt.Printf("⏰ %d issue(s) went past their SLA\n", len(breached))
+			}
+		}
+	}
+}
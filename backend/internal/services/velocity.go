@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/config"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// COMPLAINT VELOCITY
+// ============================================
+// A complaint count says how big an issue is, not where it's heading: forty
+// complaints over a quiet month and forty since Monday are different problems.
+// Each data reload measures every open issue's new complaints a day, and the
+// projection turns that into when the issue will reach its critical count. When
+// the rate reaches the alert threshold, observers get a "velocity_alert" event;
+// the alert re-arms once the rate drops back below it.
+
+// SetVelocitySettings replaces the velocity window and thresholds
+func (rs *ResolutionService) SetVelocitySettings(settings config.VelocitySettings) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.velocity = settings
+}
+
+// UpdateVelocity measures the velocity of the exchange's open issues from
+// complaints and sends "velocity_alert" for each that has newly reached the alert
+// threshold. Returns the issues alerted on.
+func (rs *ResolutionService) UpdateVelocity(exchange string, complaints []models.Complaint, now time.Time) []*models.Issue {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	alerted := []*models.Issue{}
+	if rs.velocity.WindowDays <= 0 {
+		return alerted
+	}
+	for _, issue := range rs.issues {
+		// Tenants' issues aren't fed by the deployment's complaints
+		if issue.Tenant != "" || issue.IsArchived() || !strings.EqualFold(issue.Exchange, exchange) {
+			continue
+		}
+		if issue.Status != "active" && issue.Status != "investigating" {
+			continue
+		}
+
+		velocity := analyzer.Velocity(complaints, models.NormalizeCategory(issue.Category), rs.velocity.WindowDays, now)
+		issue.Velocity = &velocity
+
+		alert := rs.velocity.AlertPerDay
+		switch {
+		case alert <= 0 || velocity.PerDay < alert:
+			issue.VelocityAlertedAt = nil
+		case issue.VelocityAlertedAt == nil:
+			alertedAt := now
+			issue.VelocityAlertedAt = &alertedAt
+			rs.notify("velocity_alert", issue)
+			alerted = append(alerted, issue)
+		}
+	}
+	return alerted
+}
+
+// ProjectVelocity returns when an issue will reach its critical complaint count
+// if complaints keep arriving at its last measured velocity
+func (rs *ResolutionService) ProjectVelocity(id string) (*models.VelocityProjection, error) {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	issue, ok := rs.issues[id]
+	if !ok {
+		return nil, fmt.Errorf("issue not found: %s", id)
+	}
+	if issue.Velocity == nil {
+		return nil, fmt.Errorf("issue %s has no velocity yet: it is measured for open issues on each data reload", id)
+	}
+
+	velocity := *issue.Velocity
+	threshold := rs.velocity.Critical(models.NormalizeCategory(issue.Category))
+	projection := &models.VelocityProjection{
+		IssueID:    issue.ID,
+		Complaints: issue.ComplaintCount,
+		Threshold:  threshold,
+		Velocity:   &velocity,
+		Crossed:    issue.ComplaintCount >= threshold,
+	}
+	switch {
+	case projection.Crossed:
+		projection.Summary = fmt.Sprintf("Already past the critical threshold of %d complaints", threshold)
+	case velocity.PerDay <= 0:
+		projection.Summary = fmt.Sprintf("No new complaints in the last %d days; not on course to reach %d", velocity.WindowDays, threshold)
+	default:
+		days := float64(threshold-issue.ComplaintCount) / velocity.PerDay
+		at := velocity.MeasuredAt.Add(time.Duration(days * float64(24*time.Hour)))
+		projection.DaysToThreshold = &days
+		projection.ProjectedAt = &at
+		projection.Summary = fmt.Sprintf("At the current rate of %.1f a day, crosses the critical threshold of %d complaints in %s",
+			velocity.PerDay, threshold, formatDays(days))
+	}
+	return projection, nil
+}
+
+// formatDays renders a day count for a projection summary
+func formatDays(days float64) string {
+	if days < 1 {
+		return "under a day"
+	}
+	if n := int(math.Round(days)); n != 1 {
+		return fmt.Sprintf("%d days", n)
+	}
+	return "1 day"
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/ratelimit"
+)
+
+// ============================================
+// BATCH VERIFICATION
+// ============================================
+// Auditors check the whole ledger at once rather than one attestation per request.
+// Items are verified by a few concurrent workers, and every RPC call waits on the
+// shared "rpc" limiter from config.RateLimits, so a large batch can't trip the RPC
+// provider's throttling for the attestation path.
+
+const (
+	// MaxBatchVerification caps the items in one batch
+	MaxBatchVerification = 200
+	// BatchVerificationWorkers is how many items are verified concurrently
+	BatchVerificationWorkers = 8
+)
+
+// VerifyBatch verifies every evidence hash and resolution ID in req. Results come back
+// in request order, hashes first; an item that can't be checked carries an Error
+// instead of failing the batch.
+func (rs *ResolutionService) VerifyBatch(ctx context.Context, req models.BatchVerificationRequest) ([]models.BatchVerificationResult, error) {
+	if rs.blockchain == nil {
+		return nil, fmt.Errorf("blockchain service not configured")
+	}
+	total := len(req.EvidenceHashes) + len(req.ResolutionIDs)
+	if total == 0 {
+		return nil, fmt.Errorf("evidence_hashes or resolution_ids required")
+	}
+	if total > MaxBatchVerification {
+		return nil, fmt.Errorf("at most %d items can be verified in one batch", MaxBatchVerification)
+	}
+
+	results := make([]models.BatchVerificationResult, 0, total)
+	for _, hash := range req.EvidenceHashes {
+		results = append(results, models.BatchVerificationResult{EvidenceHash: hash})
+	}
+	for _, id := range req.ResolutionIDs {
+		results = append(results, models.BatchVerificationResult{ResolutionID: id})
+	}
+
+	limiter := ratelimit.ForSource("rpc")
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(BatchVerificationWorkers, total) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rs.verifyBatchItem(ctx, limiter, &results[i])
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+// verifyBatchItem verifies one item in place, after waiting for the RPC limiter
+func (rs *ResolutionService) verifyBatchItem(ctx context.Context, limiter *ratelimit.Limiter, item *models.BatchVerificationResult) {
+	if err := limiter.Wait(ctx); err != nil {
+		item.Error = err.Error()
+		return
+	}
+
+	var response *models.VerificationResponse
+	var err error
+	if item.EvidenceHash != "" {
+		response, err = rs.VerifyByHash(ctx, item.EvidenceHash)
+	} else {
+		response, err = rs.VerifyResolution(ctx, item.ResolutionID)
+	}
+	if err != nil {
+		item.Error = err.Error()
+		return
+	}
+	item.Result = response
+	item.Verified = response.Verified
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// SOURCE WEIGHTING
+// ============================================
+// Complaints don't all carry the same weight: a CFPB filing names a consumer and
+// gets a company response on record, while a comment section can be brigaded in
+// an afternoon. Issue detection and resolution confidence weigh each complaint by
+// its source's trust weight from ResolutionCriteria.SourceWeights.
+
+// SetCriteria replaces the auto-verification criteria and source weights.
+// Per-exchange overrides are keyed by lowercase exchange name.
+func (rs *ResolutionService) SetCriteria(criteria models.ResolutionCriteria) {
+	criteria = normalizeCriteria(criteria)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.criteria = criteria
+}
+
+// normalizeCriteria lowercases the exchange names criteria's overrides are keyed by
+func normalizeCriteria(criteria models.ResolutionCriteria) models.ResolutionCriteria {
+	if len(criteria.Exchanges) > 0 {
+		exchanges := make(map[string]models.ExchangeCriteria, len(criteria.Exchanges))
+		for exchange, override := range criteria.Exchanges {
+			exchanges[strings.ToLower(exchange)] = override
+		}
+		criteria.Exchanges = exchanges
+	}
+	return criteria
+}
+
+// ValidateCriteria checks that criteria's thresholds, and every exchange's overrides, are in range
+func ValidateCriteria(criteria models.ResolutionCriteria) error {
+	check := func(scope string, c models.ResolutionCriteria) error {
+		if c.MinPercentageDecrease < 0 || c.MinPercentageDecrease > 1 {
+			return fmt.Errorf("%smin_percentage_decrease must be between 0 and 1", scope)
+		}
+		if c.MinConfidence < 0 || c.MinConfidence > 1 {
+			return fmt.Errorf("%smin_confidence must be between 0 and 1", scope)
+		}
+		if c.MinWindowDays < 0 {
+			return fmt.Errorf("%smin_window_days must not be negative", scope)
+		}
+		return nil
+	}
+	criteria = normalizeCriteria(criteria)
+	if err := check("", criteria); err != nil {
+		return err
+	}
+	for exchange := range criteria.Exchanges {
+		if err := check(fmt.Sprintf("exchanges.%s: ", exchange), criteria.ForExchange(exchange)); err != nil {
+			return err
+		}
+	}
+	for source, weight := range criteria.SourceWeights {
+		if weight < 0 {
+			return fmt.Errorf("source_weights.%s must not be negative", source)
+		}
+	}
+	return nil
+}
+
+// Criteria returns the auto-verification criteria and source weights in use
+func (rs *ResolutionService) Criteria() models.ResolutionCriteria {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.criteria
+}
+
+// SetTenantCriteria gives a tenant's resolutions criteria of their own; nil
+// returns them to the deployment's criteria
+func (rs *ResolutionService) SetTenantCriteria(tenant string, criteria *models.ResolutionCriteria) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if criteria == nil {
+		delete(rs.tenants, tenant)
+		return
+	}
+	rs.tenants[tenant] = normalizeCriteria(*criteria)
+}
+
+// TenantCriteria returns the criteria a tenant's resolutions are checked against
+func (rs *ResolutionService) TenantCriteria(tenant string) models.ResolutionCriteria {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.criteriaFor(tenant)
+}
+
+// criteriaFor returns a tenant's criteria, or the deployment's. Must be called with rs.mu held.
+func (rs *ResolutionService) criteriaFor(tenant string) models.ResolutionCriteria {
+	if criteria, ok := rs.tenants[tenant]; ok && tenant != "" {
+		return criteria
+	}
+	return rs.criteria
+}
+
+// WeightedCount sums the trust weights of extracted complaints' sources
+func WeightedCount(issues []analyzer.ExtractedIssue, criteria models.ResolutionCriteria) float64 {
+	total := 0.0
+	for _, issue := range issues {
+		// Same source naming as normalize.YouTube
+		total += criteria.SourceWeight("youtube:" + issue.Source)
+	}
+	return total
+}
+
+// WeightedDecrease is the drop in complaints with every data source weighed by its
+// trust weight. ok is false unless the evidence breaks its counts down per source.
+func WeightedDecrease(evidence *models.ResolutionEvidence, criteria models.ResolutionCriteria) (decrease float64, ok bool) {
+	before := weighSources(evidence.SourcesBefore, criteria)
+	if before <= 0 {
+		return 0, false
+	}
+	after := weighSources(evidence.SourcesAfter, criteria)
+	return max(0, (before-after)/before), true
+}
+
+// SourceTrust sums the trust weights of a list of data sources
+func SourceTrust(sources []string, criteria models.ResolutionCriteria) float64 {
+	total := 0.0
+	for _, source := range sources {
+		total += criteria.SourceWeight(source)
+	}
+	return total
+}
+
+// weighSources sums per-source complaint counts times their sources' weights
+func weighSources(counts map[string]int, criteria models.ResolutionCriteria) float64 {
+	total := 0.0
+	for source, n := range counts {
+		total += float64(n) * criteria.SourceWeight(source)
+	}
+	return total
+}
@@ -0,0 +1,383 @@
+package subscriptions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+	"github.com/tasnint/coinsights/internal/retry"
+)
+
+// Notifier delivers an issue event to one subscriber over one channel
+type Notifier interface {
+	// Channel is the Subscription.Channel this notifier handles
+	Channel() string
+	// Notify sends the event to sub.Target
+	Notify(ctx context.Context, sub Subscription, event string, issue models.Issue) error
+}
+
+// ============================================
+// FAN-OUT
+// ============================================
+
+// Fanout sends issue events to every matching subscription.
+// It implements services.IssueObserver.
+type Fanout struct {
+	store     *Store
+	notifiers map[string]Notifier // channel -> notifier
+}
+
+// NewFanout creates a fan-out over store using the given channel notifiers
+func NewFanout(store *Store, notifiers ...Notifier) *Fanout {
+	f := &Fanout{
+		store:     store,
+		notifiers: make(map[string]Notifier, len(notifiers)),
+	}
+	for _, n := range notifiers {
+		f.notifiers[n.Channel()] = n
+	}
+	return f
+}
+
+// NewFanoutFromEnv creates a fan-out with the webhook notifier and, if SMTP_HOST is set,
+// the email notifier
+func NewFanoutFromEnv(store *Store) *Fanout {
+	notifiers := []Notifier{NewWebhookNotifierFromEnv()}
+	if n := NewEmailNotifierFromEnv(); n != nil {
+		notifiers = append(notifiers, n)
+	}
+	return NewFanout(store, notifiers...)
+}
+
+// Channels returns the channels subscriptions can be delivered on
+func (f *Fanout) Channels() []string {
+	channels := make([]string, 0, len(f.notifiers))
+	for channel := range f.notifiers {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// IssueChanged notifies every subscriber whose subscription matches the event.
+// Subscribers only hear about issues of the tenant their subscription was made for.
+func (f *Fanout) IssueChanged(ctx context.Context, event string, issue models.Issue) {
+	for _, sub := range f.store.Matching(event, issue) {
+		if !sub.Sees(issue) {
+			continue
+		}
+		n, ok := f.notifiers[sub.Channel]
+		if !ok {
+			fmt.Printf("⚠️  subscriptions: no %s notifier configured for subscription %s\n", sub.Channel, sub.ID)
+			continue
+		}
+		if err := n.Notify(ctx, sub, event, issue); err != nil {
+			fmt.Printf("⚠️  subscriptions: failed to notify %s of %s on issue %s: %v\n", sub.User, event, issue.ID, err)
+		}
+	}
+}
+
+// ============================================
+// WEBHOOK
+// ============================================
+
+// WebhookPayload is the JSON body POSTed to subscriber webhooks
+type WebhookPayload struct {
+	Event          string       `json:"event"`
+	SubscriptionID string       `json:"subscription_id"`
+	User           string       `json:"user"`
+	Issue          models.Issue `json:"issue"`
+	SentAt         time.Time    `json:"sent_at"`
+}
+
+// WebhookNotifier POSTs events to the subscription's URL.
+// Subscribers pick the URL, so deliveries never reach loopback, private, link-local
+// (e.g. cloud metadata) or other non-public addresses unless AllowPrivate is set;
+// the check runs on the address actually dialed, after DNS and any redirect.
+type WebhookNotifier struct {
+	Secret       string      // Optional: signs the body in X-Coinsights-Signature (hex HMAC-SHA256)
+	SigningKey   *SigningKey // Optional: signs the body with the key published at /.well-known/coinsights-signing-key
+	AllowedHosts []string    // Optional: the only hosts (and their subdomains) webhooks may go to
+	AllowPrivate bool        // Deliver to non-public addresses too, e.g. for local development
+	HTTPClient   *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier; secret may be empty
+func NewWebhookNotifier(secret string) *WebhookNotifier {
+	n := &WebhookNotifier{Secret: secret}
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error { return n.checkAddress(address) },
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would dial the target on our behalf, past the address check
+	transport.DialContext = dialer.DialContext
+	n.HTTPClient = &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return n.checkHost(req.URL.Hostname())
+		},
+	}
+	return n
+}
+
+// NewWebhookNotifierFromEnv reads the optional WEBHOOK_SECRET, WEBHOOK_SIGNING_KEY,
+// WEBHOOK_ALLOWED_HOSTS (comma-separated) and WEBHOOK_ALLOW_PRIVATE.
+// An invalid signing key is reported and webhooks go out without the Ed25519 signature.
+func NewWebhookNotifierFromEnv() *WebhookNotifier {
+	n := NewWebhookNotifier(os.Getenv("WEBHOOK_SECRET"))
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_ALLOWED_HOSTS"), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			n.AllowedHosts = append(n.AllowedHosts, host)
+		}
+	}
+	n.AllowPrivate, _ = strconv.ParseBool(os.Getenv("WEBHOOK_ALLOW_PRIVATE"))
+	key, err := SigningKeyFromEnv()
+	if err != nil {
+		fmt.Printf("⚠️  subscriptions: %v\n", err)
+	}
+	n.SigningKey = key
+	return n
+}
+
+// Channel returns "webhook"
+func (n *WebhookNotifier) Channel() string {
+	return ChannelWebhook
+}
+
+// Notify POSTs a WebhookPayload to sub.Target
+func (n *WebhookNotifier) Notify(ctx context.Context, sub Subscription, event string, issue models.Issue) error {
+	if !sub.Sees(issue) {
+		return fmt.Errorf("issue %s belongs to another tenant", issue.ID)
+	}
+	body, err := json.Marshal(WebhookPayload{
+		Event:          event,
+		SubscriptionID: sub.ID,
+		User:           sub.User,
+		Issue:          issue,
+		SentAt:         time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return retry.Do(ctx, WebhookRetry, nil, func(int) error {
+		return n.post(ctx, sub.Target, event, body)
+	})
+}
+
+// WebhookRetry redelivers a webhook the subscriber couldn't take: network failures,
+// 429 and 5xx. Other statuses mean the subscriber rejected it.
+var WebhookRetry = retry.Policy{
+	MaxRetries: 3,
+	BaseDelay:  time.Second,
+	MaxDelay:   30 * time.Second,
+	Jitter:     500 * time.Millisecond,
+}
+
+// ErrWebhookTarget is returned for a webhook URL the notifier won't deliver to
+var ErrWebhookTarget = errors.New("webhook target not allowed")
+
+// checkHost refuses a target host outside AllowedHosts
+func (n *WebhookNotifier) checkHost(host string) error {
+	if len(n.AllowedHosts) == 0 {
+		return nil
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range n.AllowedHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host %s is not in the allowed hosts", ErrWebhookTarget, host)
+}
+
+// checkAddress refuses to dial a non-public address, unless AllowPrivate is set
+func (n *WebhookNotifier) checkAddress(address string) error {
+	if n.AllowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: address %s is not an IP", ErrWebhookTarget, host)
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: address %s is not public", ErrWebhookTarget, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is carrier-grade NAT space (RFC 6598), not reachable from the internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// post makes one delivery attempt, signed at the time it is sent
+func (n *WebhookNotifier) post(ctx context.Context, target, event string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("failed to build webhook request: %w", err))
+	}
+	if err := n.checkHost(req.URL.Hostname()); err != nil {
+		return retry.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Coinsights-Event", event)
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Coinsights-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+	if n.SigningKey != nil {
+		now := time.Now()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(HeaderKeyID, n.SigningKey.ID)
+		req.Header.Set(HeaderSignature, n.SigningKey.Sign(now, body))
+	}
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrWebhookTarget) {
+			return retry.Permanent(err)
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("webhook error (status %d): %s", resp.StatusCode, string(respBody))
+		if !retry.Status(resp.StatusCode) {
+			return retry.Permanent(err)
+		}
+		if delay, ok := retry.HeaderDelay(resp.Header); ok {
+			return retry.After(err, delay)
+		}
+		return err
+	}
+	return nil
+}
+
+// ============================================
+// EMAIL
+// ============================================
+
+// EmailNotifier sends plain-text emails over SMTP
+type EmailNotifier struct {
+	Addr     string // host:port
+	Username string // Optional: PLAIN auth is skipped when empty
+	Password string
+	From     string
+}
+
+// NewEmailNotifier creates an email notifier for the SMTP server at addr
+func NewEmailNotifier(addr, username, password, from string) *EmailNotifier {
+	return &EmailNotifier{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+// NewEmailNotifierFromEnv reads SMTP_HOST, SMTP_FROM and optionally SMTP_PORT (default 587),
+// SMTP_USERNAME and SMTP_PASSWORD. Returns nil if SMTP_HOST or SMTP_FROM is unset.
+func NewEmailNotifierFromEnv() *EmailNotifier {
+	host := os.Getenv("SMTP_HOST")
+	from := os.Getenv("SMTP_FROM")
+	if host == "" || from == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return NewEmailNotifier(net.JoinHostPort(host, port), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+}
+
+// Channel returns "email"
+func (n *EmailNotifier) Channel() string {
+	return ChannelEmail
+}
+
+// Notify emails the event to sub.Target
+func (n *EmailNotifier) Notify(ctx context.Context, sub Subscription, event string, issue models.Issue) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, _ := net.SplitHostPort(n.Addr)
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", sub.Target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", headerText(fmt.Sprintf("[Coinsights] %s: %s", issue.Title, event))))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(emailBody(event, issue))
+
+	if err := smtp.SendMail(n.Addr, auth, n.From, []string{sub.Target}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// headerText makes text safe for a header value: line breaks, which would end the
+// header and start another, become spaces
+func headerText(text string) string {
+	return strings.Join(strings.FieldsFunc(text, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
+
+// emailBody renders the plain-text summary of an issue event
+func emailBody(event string, issue models.Issue) string {
+	var b strings.Builder
+	switch event {
+	case "sla_breached":
+		fmt.Fprintf(&b, "%s is past its SLA.\r\n\r\n", issue.Title)
+	case "velocity_alert":
+		fmt.Fprintf(&b, "Complaints about %s are arriving fast.\r\n\r\n", issue.Title)
+	default:
+		fmt.Fprintf(&b, "%s was %s.\r\n\r\n", issue.Title, event)
+	}
+	fmt.Fprintf(&b, "Exchange: %s\r\n", issue.Exchange)
+	fmt.Fprintf(&b, "Category: %s\r\n", issue.Category)
+	fmt.Fprintf(&b, "Status: %s\r\n", issue.Status)
+	fmt.Fprintf(&b, "Severity: %s\r\n", issue.Severity)
+	fmt.Fprintf(&b, "Complaints: %d\r\n", issue.ComplaintCount)
+	if v := issue.Velocity; v != nil {
+		fmt.Fprintf(&b, "Velocity: %.1f new complaints a day over %d days (%.1f the %d days before)\r\n", v.PerDay, v.WindowDays, v.PreviousPerDay, v.WindowDays)
+	}
+	if issue.SLA != nil {
+		fmt.Fprintf(&b, "SLA: due %s (%d-day window)\r\n", issue.SLA.DueAt.Format(time.RFC3339), issue.SLA.WindowDays)
+	}
+	if issue.Resolution != nil {
+		fmt.Fprintf(&b, "\r\nResolution: %s (confidence %.0f%%)\r\n", issue.Resolution.Summary, issue.Resolution.Confidence*100)
+	}
+	if issue.Attestation != nil {
+		fmt.Fprintf(&b, "On-chain attestation: %s\r\n", issue.Attestation.ExplorerURL)
+	}
+	fmt.Fprintf(&b, "\r\nCoinsights issue ID: %s\r\n", issue.ID)
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package subscriptions
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// WEBHOOK SIGNING
+// ============================================
+// The shared-secret HMAC (WEBHOOK_SECRET) only proves a webhook came from someone
+// holding the secret, and every receiver holds it. Webhooks are therefore also signed
+// with a dedicated Ed25519 key whose public half is published at
+// /.well-known/coinsights-signing-key, so any receiver can check that a
+// "resolution verified on-chain" notification genuinely came from this instance.
+
+// Headers set on signed webhook requests
+const (
+	HeaderSignature = "X-Coinsights-Signature-Ed25519" // Base64 Ed25519 signature of "<timestamp>.<body>"
+	HeaderTimestamp = "X-Coinsights-Timestamp"         // Unix seconds when the request was signed
+	HeaderKeyID     = "X-Coinsights-Key-Id"            // SigningKey.ID of the key that signed it
+)
+
+// SigningKey is the Ed25519 key webhook payloads are signed with
+type SigningKey struct {
+	ID      string // First 8 bytes of the public key's SHA-256, hex
+	private ed25519.PrivateKey
+}
+
+// NewSigningKey creates a signing key from a 32-byte Ed25519 seed
+func NewSigningKey(seed []byte) (*SigningKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key seed must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	sum := sha256.Sum256(private.Public().(ed25519.PublicKey))
+	return &SigningKey{ID: hex.EncodeToString(sum[:8]), private: private}, nil
+}
+
+// GenerateSigningKey creates a signing key from a random seed
+func GenerateSigningKey() (*SigningKey, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	return NewSigningKey(seed)
+}
+
+// SigningKeyFromEnv reads WEBHOOK_SIGNING_KEY, a base64 32-byte seed
+// (e.g. from `openssl rand -base64 32`). Returns nil if it is unset.
+func SigningKeyFromEnv() (*SigningKey, error) {
+	encoded := strings.TrimSpace(os.Getenv("WEBHOOK_SIGNING_KEY"))
+	if encoded == "" {
+		return nil, nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("WEBHOOK_SIGNING_KEY is not valid base64: %w", err)
+	}
+	return NewSigningKey(seed)
+}
+
+// PublicKey returns the key receivers verify signatures with
+func (k *SigningKey) PublicKey() ed25519.PublicKey {
+	return k.private.Public().(ed25519.PublicKey)
+}
+
+// Sign returns the base64 signature of "<timestamp>.<body>"
+func (k *SigningKey) Sign(timestamp time.Time, body []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(k.private, signedContent(timestamp.Unix(), body)))
+}
+
+// VerifyWebhook checks a webhook's signature and timestamp headers against the
+// published public key. Requests signed more than maxAge before now are rejected,
+// so a captured notification can't be replayed later.
+func VerifyWebhook(publicKey ed25519.PublicKey, signature, timestamp string, body []byte, maxAge time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %q", HeaderTimestamp, timestamp)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); maxAge > 0 && (age > maxAge || age < -maxAge) {
+		return fmt.Errorf("webhook signed %s ago, outside the %s window", age.Round(time.Second), maxAge)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", HeaderSignature, err)
+	}
+	if !ed25519.Verify(publicKey, signedContent(unix, body), sig) {
+		return fmt.Errorf("webhook signature does not match")
+	}
+	return nil
+}
+
+// signedContent is what a webhook signature covers: "<unix timestamp>.<body>"
+func signedContent(unix int64, body []byte) []byte {
+	return append([]byte(strconv.FormatInt(unix, 10)+"."), body...)
+}
+
+// SigningKeyDocument is what /.well-known/coinsights-signing-key publishes
+type SigningKeyDocument struct {
+	KeyID         string            `json:"key_id"`
+	Algorithm     string            `json:"algorithm"`  // "Ed25519"
+	PublicKey     string            `json:"public_key"` // Base64 raw 32-byte public key
+	JWK           map[string]string `json:"jwk"`        // The same key as an RFC 8037 JSON Web Key
+	Headers       map[string]string `json:"headers"`    // Which request header carries what
+	SignedContent string            `json:"signed_content"`
+}
+
+// Document describes the key and the signature scheme for receivers
+func (k *SigningKey) Document() SigningKeyDocument {
+	public := k.PublicKey()
+	return SigningKeyDocument{
+		KeyID:     k.ID,
+		Algorithm: "Ed25519",
+		PublicKey: base64.StdEncoding.EncodeToString(public),
+		JWK: map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   base64.RawURLEncoding.EncodeToString(public),
+			"kid": k.ID,
+			"use": "sig",
+		},
+		Headers: map[string]string{
+			"signature": HeaderSignature,
+			"timestamp": HeaderTimestamp,
+			"key_id":    HeaderKeyID,
+		},
+		SignedContent: "<" + HeaderTimestamp + ">.<raw request body>",
+	}
+}
@@ -0,0 +1,251 @@
+// Per-user issue subscriptions: watch a single issue or follow a topic such as "Coinbase withdrawals"
+package subscriptions
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// SubscriptionsFile is the subscription file inside the data directory
+const SubscriptionsFile = "subscriptions.json"
+
+// Delivery channels
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+)
+
+// Subscription routes issue events to one user over one channel.
+// A subscription with IssueID set watches that issue only; otherwise it follows every
+// issue matching Exchange, Category and Keyword (empty filters match anything).
+type Subscription struct {
+	ID       string   `json:"id"`
+	User     string   `json:"user"`
+	IssueID  string   `json:"issue_id,omitempty"`
+	Exchange string   `json:"exchange,omitempty"`
+	Category string   `json:"category,omitempty"`
+	Keyword  string   `json:"keyword,omitempty"` // Case-insensitive match on title or description
+	Events   []string `json:"events,omitempty"`  // Empty means every event
+	Channel  string   `json:"channel"`           // "webhook" or "email"
+	Target   string   `json:"target"`            // Webhook URL or email address
+
+	// Issues the subscriber may see, set by the server from their credentials:
+	// those of Tenant (empty: the deployment's own), or every tenant's with AllTenants
+	Tenant     string `json:"tenant,omitempty"`
+	AllTenants bool   `json:"all_tenants,omitempty"`
+	// The API key or token subject that created it, and alone may list or remove it
+	Owner string `json:"owner,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sees reports whether the subscriber may be told about the issue
+func (s Subscription) Sees(issue models.Issue) bool {
+	return s.AllTenants || s.Tenant == issue.Tenant
+}
+
+// Matches reports whether the subscription wants this event for this issue
+func (s Subscription) Matches(event string, issue models.Issue) bool {
+	if !s.Sees(issue) {
+		return false
+	}
+	if len(s.Events) > 0 && !contains(s.Events, event) {
+		return false
+	}
+	if s.IssueID != "" {
+		return s.IssueID == issue.ID
+	}
+	if s.Exchange != "" && !strings.EqualFold(s.Exchange, issue.Exchange) {
+		return false
+	}
+	if s.Category != "" && s.Category != models.NormalizeCategory(issue.Category) {
+		return false
+	}
+	if s.Keyword != "" {
+		keyword := strings.ToLower(s.Keyword)
+		if !strings.Contains(strings.ToLower(issue.Title), keyword) &&
+			!strings.Contains(strings.ToLower(issue.Description), keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the fields every subscription needs
+func (s Subscription) Validate() error {
+	if s.User == "" {
+		return fmt.Errorf("user is required")
+	}
+	switch s.Channel {
+	case ChannelWebhook:
+		u, err := url.Parse(s.Target)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+			return fmt.Errorf("webhook target must be an http(s) URL")
+		}
+	case ChannelEmail:
+		// A bare address only: it goes into the To header as-is
+		if addr, err := mail.ParseAddress(s.Target); err != nil || addr.Address != s.Target {
+			return fmt.Errorf("email target must be an email address")
+		}
+	default:
+		return fmt.Errorf("channel must be %q or %q", ChannelWebhook, ChannelEmail)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================
+// SUBSCRIPTION STORE
+// ============================================
+
+// Store keeps subscriptions persisted to a JSON file
+type Store struct {
+	path          string
+	subscriptions []Subscription
+	mu            sync.RWMutex
+}
+
+// NewStore loads the subscriptions from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, SubscriptionsFile)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to parse subscriptions: %w", err)
+	}
+	return s, nil
+}
+
+// Add validates and stores a subscription, assigning its ID and creation time
+func (s *Store) Add(sub Subscription) (Subscription, error) {
+	if sub.Category != "" {
+		sub.Category = models.NormalizeCategory(sub.Category)
+	}
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub.ID = generateID()
+	sub.CreatedAt = time.Now()
+	s.subscriptions = append(s.subscriptions, sub)
+	if err := s.save(); err != nil {
+		s.subscriptions = s.subscriptions[:len(s.subscriptions)-1]
+		return Subscription{}, err
+	}
+	return sub, nil
+}
+
+// Remove deletes a subscription by ID if owned accepts it. Returns false if it
+// doesn't exist or isn't owned.
+func (s *Store) Remove(id string, owned func(Subscription) bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.subscriptions[:0:0]
+	for _, sub := range s.subscriptions {
+		if sub.ID != id || !owned(sub) {
+			kept = append(kept, sub)
+		}
+	}
+	if len(kept) == len(s.subscriptions) {
+		return false, nil
+	}
+	s.subscriptions = kept
+	return true, s.save()
+}
+
+// Unwatch removes every subscription user holds on a single issue that owned accepts,
+// and returns how many were removed
+func (s *Store) Unwatch(user, issueID string, owned func(Subscription) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.subscriptions[:0:0]
+	for _, sub := range s.subscriptions {
+		if sub.User != user || sub.IssueID != issueID || !owned(sub) {
+			kept = append(kept, sub)
+		}
+	}
+	removed := len(s.subscriptions) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	s.subscriptions = kept
+	return removed, s.save()
+}
+
+// ForUser returns a user's subscriptions, oldest first (all subscriptions when user is empty)
+func (s *Store) ForUser(user string) []Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subs := make([]Subscription, 0)
+	for _, sub := range s.subscriptions {
+		if user == "" || sub.User == user {
+			subs = append(subs, sub)
+		}
+	}
+	sort.SliceStable(subs, func(i, j int) bool { return subs[i].CreatedAt.Before(subs[j].CreatedAt) })
+	return subs
+}
+
+// Matching returns the subscriptions that want this event for this issue
+func (s *Store) Matching(event string, issue models.Issue) []Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var subs []Subscription
+	for _, sub := range s.subscriptions {
+		if sub.Matches(event, issue) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.subscriptions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscriptions: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write subscriptions: %w", err)
+	}
+	return nil
+}
+
+// generateID generates a random subscription ID
+func generateID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
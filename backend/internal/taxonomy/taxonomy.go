@@ -0,0 +1,347 @@
+// Editable complaint taxonomy: categories and the keywords that signal them, persisted to the data directory
+package taxonomy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// TAXONOMY STORE
+// ============================================
+// The built-in taxonomy lives in models.DefaultCategories and analyzer.DefaultKeywords.
+// Once an operator edits it, the whole taxonomy is saved to File and installed with
+// models.SetCategories and analyzer.SetKeywords, so every source, the analyzer and
+// the API pick up the change without a code edit or restart.
+
+// File holds the edited taxonomy inside the data directory
+const File = "categories.json"
+
+// Validation error codes
+const (
+	ErrCodeInvalidID       = "invalid_id"
+	ErrCodeNameRequired    = "name_required"
+	ErrCodeInvalidSeverity = "invalid_severity"
+	ErrCodeInvalidKeyword  = "invalid_keyword"
+	ErrCodeInvalidAlias    = "invalid_alias"
+	ErrCodeAliasConflict   = "alias_conflict"
+)
+
+const (
+	// MaxKeywordLength caps a single keyword or alias
+	MaxKeywordLength = 60
+	// MaxKeywords caps the keywords on one category
+	MaxKeywords = 200
+)
+
+var (
+	// ErrNotFound is returned for an unknown category ID
+	ErrNotFound = errors.New("category not found")
+	// ErrExists is returned when creating a category whose ID is taken
+	ErrExists = errors.New("category already exists")
+	// ErrKeywordNotFound is returned when removing a keyword the category doesn't have
+	ErrKeywordNotFound = errors.New("keyword not found")
+	// ErrProtected is returned when deleting the fallback category
+	ErrProtected = errors.New("the other category is the fallback for unknown names and can't be deleted")
+)
+
+var (
+	idPattern  = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+	severities = []string{"critical", "high", "medium", "low"}
+)
+
+// Category is a taxonomy category with the keywords the analyzer matches it by.
+// Categories without keywords are only reached through Gemini, ingestion or aliases.
+type Category struct {
+	models.Category
+	Keywords []string `json:"keywords"`
+}
+
+// ValidationError lists every rule a category broke
+type ValidationError struct {
+	Errors []models.ValidationError
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, len(e.Errors))
+	for i, v := range e.Errors {
+		codes[i] = v.Code
+	}
+	return fmt.Sprintf("invalid category: %s", strings.Join(codes, ", "))
+}
+
+// Store holds the taxonomy and persists edits to a JSON file
+type Store struct {
+	path       string
+	categories []Category
+	mu         sync.Mutex
+}
+
+// NewStore loads the edited taxonomy from dataDir and installs it. Without the
+// file the store starts from the built-in taxonomy and changes nothing.
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, File)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.categories = defaults()
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read taxonomy: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.categories); err != nil {
+		return nil, fmt.Errorf("failed to parse taxonomy: %w", err)
+	}
+	s.install()
+	return s, nil
+}
+
+// List returns every category in taxonomy order
+func (s *Store) List() []Category {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.categories)
+}
+
+// Get returns a category by ID
+func (s *Store) Get(id string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return Category{}, ErrNotFound
+	}
+	return s.categories[i], nil
+}
+
+// Create adds a category at the end of the taxonomy, before the "other" fallback
+func (s *Store) Create(c Category) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c = normalize(c)
+	if s.index(c.ID) >= 0 {
+		return Category{}, ErrExists
+	}
+	if err := s.validate(c); err != nil {
+		return Category{}, err
+	}
+
+	categories := slices.Clone(s.categories)
+	at := len(categories)
+	if i := s.index(models.CategoryOther); i >= 0 {
+		at = i
+	}
+	categories = slices.Insert(categories, at, c)
+	return c, s.commit(categories)
+}
+
+// Update replaces a category's name, severity, aliases and keywords. The ID can't change.
+func (s *Store) Update(id string, c Category) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return Category{}, ErrNotFound
+	}
+	c.ID = id
+	c = normalize(c)
+	if err := s.validate(c); err != nil {
+		return Category{}, err
+	}
+
+	categories := slices.Clone(s.categories)
+	categories[i] = c
+	return c, s.commit(categories)
+}
+
+// Delete removes a category. Complaints already filed under it keep its ID but are
+// no longer matched to it by the analyzer.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id == models.CategoryOther {
+		return ErrProtected
+	}
+	i := s.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	return s.commit(slices.Delete(slices.Clone(s.categories), i, i+1))
+}
+
+// AddKeywords adds keywords to a category, skipping ones it already has
+func (s *Store) AddKeywords(id string, keywords []string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return Category{}, ErrNotFound
+	}
+	c := s.categories[i]
+	c.Keywords = append(slices.Clone(c.Keywords), keywords...)
+	c = normalize(c)
+	if err := s.validate(c); err != nil {
+		return Category{}, err
+	}
+
+	categories := slices.Clone(s.categories)
+	categories[i] = c
+	return c, s.commit(categories)
+}
+
+// RemoveKeyword removes one keyword from a category
+func (s *Store) RemoveKeyword(id, keyword string) (Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.index(id)
+	if i < 0 {
+		return Category{}, ErrNotFound
+	}
+	c := s.categories[i]
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	at := slices.Index(c.Keywords, keyword)
+	if at < 0 {
+		return Category{}, ErrKeywordNotFound
+	}
+	c.Keywords = slices.Delete(slices.Clone(c.Keywords), at, at+1)
+
+	categories := slices.Clone(s.categories)
+	categories[i] = c
+	return c, s.commit(categories)
+}
+
+// commit saves categories and installs them. Must be called with s.mu held.
+func (s *Store) commit(categories []Category) error {
+	data, err := json.MarshalIndent(categories, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal taxonomy: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write taxonomy: %w", err)
+	}
+	s.categories = categories
+	s.install()
+	return nil
+}
+
+// install makes the store's taxonomy the one every package uses. Must be called with s.mu held.
+func (s *Store) install() {
+	categories := make([]models.Category, len(s.categories))
+	keywords := make(map[string][]string, len(s.categories))
+	for i, c := range s.categories {
+		categories[i] = c.Category
+		if len(c.Keywords) > 0 {
+			keywords[c.ID] = c.Keywords
+		}
+	}
+	models.SetCategories(categories)
+	analyzer.SetKeywords(keywords)
+}
+
+// index returns the position of the category with id, or -1
+func (s *Store) index(id string) int {
+	return slices.IndexFunc(s.categories, func(c Category) bool { return c.ID == id })
+}
+
+// validate checks c on its own and against the other categories' names and aliases
+func (s *Store) validate(c Category) error {
+	var errs []models.ValidationError
+	add := func(code, field, format string, args ...any) {
+		errs = append(errs, models.ValidationError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !idPattern.MatchString(c.ID) {
+		add(ErrCodeInvalidID, "id", "id must be lowercase letters, digits and underscores, starting with a letter (max 40)")
+	}
+	if c.Name == "" {
+		add(ErrCodeNameRequired, "name", "name is required")
+	}
+	if !slices.Contains(severities, c.Severity) {
+		add(ErrCodeInvalidSeverity, "severity", "severity must be one of %s", strings.Join(severities, ", "))
+	}
+	if len(c.Keywords) > MaxKeywords {
+		add(ErrCodeInvalidKeyword, "keywords", "a category can have at most %d keywords", MaxKeywords)
+	}
+	for _, k := range c.Keywords {
+		if k == "" || len(k) > MaxKeywordLength {
+			add(ErrCodeInvalidKeyword, "keywords", "keywords must be 1 to %d characters", MaxKeywordLength)
+			break
+		}
+	}
+	for _, a := range c.Aliases {
+		if a == "" || len(a) > MaxKeywordLength {
+			add(ErrCodeInvalidAlias, "aliases", "aliases must be 1 to %d characters", MaxKeywordLength)
+			break
+		}
+	}
+
+	// Every name must map to exactly one category, or NormalizeCategory becomes order-dependent
+	names := append([]string{c.ID, strings.ToLower(c.Name)}, c.Aliases...)
+	for _, other := range s.categories {
+		if other.ID == c.ID {
+			continue
+		}
+		taken := append([]string{other.ID, strings.ToLower(other.Name)}, other.Aliases...)
+		for _, name := range names {
+			if name != "" && slices.Contains(taken, name) {
+				add(ErrCodeAliasConflict, "aliases", "%q already refers to category %s", name, other.ID)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// normalize trims names, lowercases aliases and keywords, and drops duplicates
+func normalize(c Category) Category {
+	c.ID = strings.TrimSpace(c.ID)
+	c.Name = strings.TrimSpace(c.Name)
+	c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+	c.Aliases = normalizeList(c.Aliases)
+	c.Keywords = normalizeList(c.Keywords)
+	return c
+}
+
+// normalizeList lowercases and trims every entry, keeping the first of any duplicates
+func normalizeList(list []string) []string {
+	out := []string{}
+	for _, v := range list {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// defaults is the built-in taxonomy with its keywords
+func defaults() []Category {
+	categories := make([]Category, len(models.DefaultCategories))
+	for i, c := range models.DefaultCategories {
+		categories[i] = Category{Category: c, Keywords: slices.Clone(analyzer.DefaultKeywords[c.ID])}
+		if categories[i].Keywords == nil {
+			categories[i].Keywords = []string{}
+		}
+	}
+	return categories
+}
@@ -0,0 +1,217 @@
+// Tenants: isolated watch programs sharing one deployment
+package tenants
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// TENANTS
+// ============================================
+// One deployment can run watch programs for several clients. Each tenant has its
+// own issues, resolutions and ingested complaints, its own API keys (keys and JWTs
+// name their tenant) and its own resolution criteria, and only ever sees its own
+// records. Records without a tenant belong to the deployment's own program, which
+// the scrapers and issue detection feed. Keys without a tenant are operator keys
+// and see every tenant.
+
+const (
+	// TenantsFile holds the tenants inside the data directory
+	TenantsFile = "tenants.json"
+)
+
+// idPattern is what a tenant ID may look like; IDs go in key names and JWT claims
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant is one client's watch program
+type Tenant struct {
+	ID             string                     `json:"id"`
+	Name           string                     `json:"name"`
+	Exchanges      []string                   `json:"exchanges,omitempty"`       // Exchanges it may open issues for; empty allows any
+	IngestPartners []string                   `json:"ingest_partners,omitempty"` // Ingest partners whose complaints belong to it
+	Criteria       *models.ResolutionCriteria `json:"criteria,omitempty"`        // Resolution criteria; nil uses the deployment's
+	CreatedAt      time.Time                  `json:"created_at"`
+	UpdatedAt      time.Time                  `json:"updated_at"`
+}
+
+// WatchesExchange reports whether the tenant may open issues for exchange
+func (t *Tenant) WatchesExchange(exchange string) bool {
+	if len(t.Exchanges) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(t.Exchanges, func(e string) bool { return strings.EqualFold(e, exchange) })
+}
+
+// normalize lowercases ingest partner names, as ingest keys are
+func (t *Tenant) normalize() {
+	for i, partner := range t.IngestPartners {
+		t.IngestPartners[i] = strings.ToLower(strings.TrimSpace(partner))
+	}
+}
+
+// Validate checks a tenant's ID and settings
+func (t *Tenant) Validate() error {
+	if !idPattern.MatchString(t.ID) {
+		return fmt.Errorf("invalid tenant ID %q: use lowercase letters, digits, _ and -", t.ID)
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("tenant %s needs a name", t.ID)
+	}
+	return nil
+}
+
+// Store keeps the tenants persisted to a JSON file
+type Store struct {
+	path    string
+	tenants map[string]*Tenant
+	mu      sync.RWMutex
+}
+
+// NewStore loads the tenants from dataDir (none if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{path: filepath.Join(dataDir, TenantsFile), tenants: make(map[string]*Tenant)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	var tenants []*Tenant
+	if err := json.Unmarshal(data, &tenants); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants: %w", err)
+	}
+	for _, tenant := range tenants {
+		tenant.normalize()
+		if err := tenant.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", TenantsFile, err)
+		}
+		s.tenants[tenant.ID] = tenant
+	}
+	return s, nil
+}
+
+// Get returns a tenant by ID
+func (s *Store) Get(id string) (Tenant, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenant, ok := s.tenants[id]
+	if !ok {
+		return Tenant{}, false
+	}
+	return *tenant, true
+}
+
+// List returns every tenant, by ID
+func (s *Store) List() []Tenant {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := make([]Tenant, 0, len(s.tenants))
+	for _, tenant := range s.tenants {
+		tenants = append(tenants, *tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants
+}
+
+// Put creates or replaces a tenant, keeping its creation time
+func (s *Store) Put(tenant Tenant) (Tenant, error) {
+	tenant.normalize()
+	if err := tenant.Validate(); err != nil {
+		return Tenant{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	tenant.CreatedAt = now
+	if existing, ok := s.tenants[tenant.ID]; ok {
+		tenant.CreatedAt = existing.CreatedAt
+	}
+	tenant.UpdatedAt = now
+	for partner, owner := range s.partners() {
+		if owner != tenant.ID && slices.Contains(tenant.IngestPartners, partner) {
+			return Tenant{}, fmt.Errorf("ingest partner %s already belongs to tenant %s", partner, owner)
+		}
+	}
+	s.tenants[tenant.ID] = &tenant
+	return tenant, s.save()
+}
+
+// Delete removes a tenant. Its issues and resolutions are kept, still scoped to it.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tenants[id]; !ok {
+		return fmt.Errorf("tenant not found: %s", id)
+	}
+	delete(s.tenants, id)
+	return s.save()
+}
+
+// SetCriteria replaces a tenant's resolution criteria
+func (s *Store) SetCriteria(id string, criteria models.ResolutionCriteria) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenant, ok := s.tenants[id]
+	if !ok {
+		return fmt.Errorf("tenant not found: %s", id)
+	}
+	tenant.Criteria = &criteria
+	tenant.UpdatedAt = time.Now()
+	return s.save()
+}
+
+// ForPartner returns the tenant an ingest partner's complaints belong to ("" for
+// the deployment's own program)
+func (s *Store) ForPartner(partner string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.partners()[partner]
+}
+
+// partners maps each ingest partner to its tenant. Must be called with s.mu held.
+func (s *Store) partners() map[string]string {
+	partners := make(map[string]string)
+	for _, tenant := range s.tenants {
+		for _, partner := range tenant.IngestPartners {
+			partners[partner] = tenant.ID
+		}
+	}
+	return partners
+}
+
+// save writes the tenants. Must be called with s.mu held.
+func (s *Store) save() error {
+	tenants := make([]*Tenant, 0, len(s.tenants))
+	for _, tenant := range s.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+
+	data, err := json.MarshalIndent(tenants, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenants: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write tenants: %w", err)
+	}
+	return nil
+}
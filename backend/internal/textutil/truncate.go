@@ -0,0 +1,37 @@
+package textutil
+
+import "unicode/utf8"
+
+// ============================================
+// SAFE TRUNCATION
+// ============================================
+// Comments are full of emoji and non-Latin text, so cutting at a byte offset
+// (text[:100]) can split a character and leave invalid UTF-8 in stored results
+// and API responses. These helpers count and cut in runes instead.
+
+// Ellipsis marks text that was truncated
+const Ellipsis = "..."
+
+// Prefix returns the first max characters of s. max <= 0 means no limit.
+func Prefix(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == max {
+			return s[:i]
+		}
+		n++
+	}
+	return s
+}
+
+// Truncate returns the first max characters of s followed by Ellipsis, or s
+// unchanged if it already fits. max <= 0 means no limit.
+func Truncate(s string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return Prefix(s, max) + Ellipsis
+}
@@ -0,0 +1,95 @@
+package trackers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// GitHubTracker opens issues in a GitHub repository
+type GitHubTracker struct {
+	Token      string
+	Repo       string // "owner/name"
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewGitHubTracker creates a new GitHub Issues tracker
+func NewGitHubTracker(token, repo string) *GitHubTracker {
+	return &GitHubTracker{
+		Token:      token,
+		Repo:       repo,
+		BaseURL:    "https://api.github.com",
+		HTTPClient: newHTTPClient(),
+	}
+}
+
+// NewGitHubTrackerFromEnv reads GITHUB_TRACKER_TOKEN and GITHUB_TRACKER_REPO.
+// Returns nil if either is unset.
+func NewGitHubTrackerFromEnv() *GitHubTracker {
+	token := os.Getenv("GITHUB_TRACKER_TOKEN")
+	repo := os.Getenv("GITHUB_TRACKER_REPO")
+	if token == "" || repo == "" {
+		return nil
+	}
+	return NewGitHubTracker(token, repo)
+}
+
+// Name returns the tracker name
+func (t *GitHubTracker) Name() string {
+	return "github"
+}
+
+// OpenIssue handles POST /repos/{owner}/{repo}/issues
+func (t *GitHubTracker) OpenIssue(ctx context.Context, issue models.Issue) (string, error) {
+	var created struct {
+		Number int `json:"number"`
+	}
+	err := doJSON(ctx, t.HTTPClient, http.MethodPost, t.issuesURL(), t.headers(), map[string]any{
+		"title":  issue.Title,
+		"body":   issueBody(issue),
+		"labels": Labels(issue),
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(created.Number), nil
+}
+
+// UpdateIssue syncs labels, closes or reopens the issue on lifecycle events, and
+// comments on the event. Other events leave the state alone, so an issue a
+// maintainer closed by hand stays closed.
+func (t *GitHubTracker) UpdateIssue(ctx context.Context, ref string, event string, issue models.Issue) error {
+	update := map[string]any{"labels": Labels(issue)}
+	switch event {
+	case "attested", "archived":
+		update["state"] = "closed"
+	case "detected", "unarchived":
+		update["state"] = "open" // The problem is back
+	}
+
+	issueURL := fmt.Sprintf("%s/%s", t.issuesURL(), ref)
+	err := doJSON(ctx, t.HTTPClient, http.MethodPatch, issueURL, t.headers(), update, nil)
+	if err != nil {
+		return err
+	}
+
+	return doJSON(ctx, t.HTTPClient, http.MethodPost, issueURL+"/comments", t.headers(), map[string]string{
+		"body": eventComment(event, issue),
+	}, nil)
+}
+
+func (t *GitHubTracker) issuesURL() string {
+	return fmt.Sprintf("%s/repos/%s/issues", t.BaseURL, t.Repo)
+}
+
+func (t *GitHubTracker) headers() map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + t.Token,
+		"Accept":        "application/vnd.github+json",
+	}
+}
@@ -0,0 +1,108 @@
+package trackers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// JiraTracker opens issues in a Jira project via the REST v2 API
+type JiraTracker struct {
+	BaseURL    string // e.g. https://yourcompany.atlassian.net
+	Email      string
+	APIToken   string
+	ProjectKey string
+	IssueType  string
+	HTTPClient *http.Client
+}
+
+// NewJiraTracker creates a new Jira tracker
+func NewJiraTracker(baseURL, email, apiToken, projectKey string) *JiraTracker {
+	return &JiraTracker{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		Email:      email,
+		APIToken:   apiToken,
+		ProjectKey: projectKey,
+		IssueType:  "Task",
+		HTTPClient: newHTTPClient(),
+	}
+}
+
+// NewJiraTrackerFromEnv reads JIRA_BASE_URL, JIRA_EMAIL, JIRA_API_TOKEN, JIRA_PROJECT_KEY
+// and optionally JIRA_ISSUE_TYPE. Returns nil if any required value is unset.
+func NewJiraTrackerFromEnv() *JiraTracker {
+	baseURL := os.Getenv("JIRA_BASE_URL")
+	email := os.Getenv("JIRA_EMAIL")
+	token := os.Getenv("JIRA_API_TOKEN")
+	project := os.Getenv("JIRA_PROJECT_KEY")
+	if baseURL == "" || email == "" || token == "" || project == "" {
+		return nil
+	}
+	t := NewJiraTracker(baseURL, email, token, project)
+	if issueType := os.Getenv("JIRA_ISSUE_TYPE"); issueType != "" {
+		t.IssueType = issueType
+	}
+	return t
+}
+
+// Name returns the tracker name
+func (t *JiraTracker) Name() string {
+	return "jira"
+}
+
+// OpenIssue handles POST /rest/api/2/issue
+func (t *JiraTracker) OpenIssue(ctx context.Context, issue models.Issue) (string, error) {
+	var created struct {
+		Key string `json:"key"`
+	}
+	err := doJSON(ctx, t.HTTPClient, http.MethodPost, t.BaseURL+"/rest/api/2/issue", t.headers(), map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": t.ProjectKey},
+			"summary":     issue.Title,
+			"description": issueBody(issue),
+			"issuetype":   map[string]string{"name": t.IssueType},
+			"labels":      jiraLabels(issue),
+		},
+	}, &created)
+	if err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// UpdateIssue syncs labels and comments on the event.
+// Workflow transitions are project-specific, so closing is left to Jira automation.
+func (t *JiraTracker) UpdateIssue(ctx context.Context, ref string, event string, issue models.Issue) error {
+	issueURL := fmt.Sprintf("%s/rest/api/2/issue/%s", t.BaseURL, ref)
+	err := doJSON(ctx, t.HTTPClient, http.MethodPut, issueURL, t.headers(), map[string]any{
+		"fields": map[string]any{"labels": jiraLabels(issue)},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return doJSON(ctx, t.HTTPClient, http.MethodPost, issueURL+"/comment", t.headers(), map[string]string{
+		"body": eventComment(event, issue),
+	}, nil)
+}
+
+func (t *JiraTracker) headers() map[string]string {
+	auth := base64.StdEncoding.EncodeToString([]byte(t.Email + ":" + t.APIToken))
+	return map[string]string{
+		"Authorization": "Basic " + auth,
+	}
+}
+
+// jiraLabels converts labels to Jira's format (no spaces allowed)
+func jiraLabels(issue models.Issue) []string {
+	labels := Labels(issue)
+	for i, l := range labels {
+		labels[i] = strings.ReplaceAll(l, " ", "_")
+	}
+	return labels
+}
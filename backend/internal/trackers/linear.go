@@ -0,0 +1,129 @@
+package trackers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// LinearTracker opens issues in a Linear team via the GraphQL API.
+// Linear labels are ID-based, so severity maps to Linear priority instead.
+type LinearTracker struct {
+	APIKey      string
+	TeamID      string
+	DoneStateID string // Optional workflow state used once a resolution is attested or the issue archived
+	BaseURL     string
+	HTTPClient  *http.Client
+}
+
+// NewLinearTracker creates a new Linear tracker
+func NewLinearTracker(apiKey, teamID string) *LinearTracker {
+	return &LinearTracker{
+		APIKey:     apiKey,
+		TeamID:     teamID,
+		BaseURL:    "https://api.linear.app/graphql",
+		HTTPClient: newHTTPClient(),
+	}
+}
+
+// NewLinearTrackerFromEnv reads LINEAR_API_KEY, LINEAR_TEAM_ID and LINEAR_DONE_STATE_ID.
+// Returns nil if the key or team is unset.
+func NewLinearTrackerFromEnv() *LinearTracker {
+	apiKey := os.Getenv("LINEAR_API_KEY")
+	teamID := os.Getenv("LINEAR_TEAM_ID")
+	if apiKey == "" || teamID == "" {
+		return nil
+	}
+	t := NewLinearTracker(apiKey, teamID)
+	t.DoneStateID = os.Getenv("LINEAR_DONE_STATE_ID")
+	return t
+}
+
+// Name returns the tracker name
+func (t *LinearTracker) Name() string {
+	return "linear"
+}
+
+// OpenIssue runs the issueCreate mutation
+func (t *LinearTracker) OpenIssue(ctx context.Context, issue models.Issue) (string, error) {
+	var resp struct {
+		Data struct {
+			IssueCreate struct {
+				Success bool `json:"success"`
+				Issue   struct {
+					ID string `json:"id"`
+				} `json:"issue"`
+			} `json:"issueCreate"`
+		} `json:"data"`
+	}
+
+	err := t.graphql(ctx, `mutation($input: IssueCreateInput!) {
+		issueCreate(input: $input) { success issue { id } }
+	}`, map[string]any{
+		"input": map[string]any{
+			"teamId":      t.TeamID,
+			"title":       issue.Title,
+			"description": issueBody(issue),
+			"priority":    linearPriority(issue.Severity),
+		},
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	if !resp.Data.IssueCreate.Success {
+		return "", fmt.Errorf("linear issueCreate was not successful")
+	}
+	return resp.Data.IssueCreate.Issue.ID, nil
+}
+
+// UpdateIssue syncs priority, moves to the done state once attested or archived, and comments on the event
+func (t *LinearTracker) UpdateIssue(ctx context.Context, ref string, event string, issue models.Issue) error {
+	input := map[string]any{
+		"priority": linearPriority(issue.Severity),
+	}
+	if (event == "attested" || event == "archived") && t.DoneStateID != "" {
+		input["stateId"] = t.DoneStateID
+	}
+
+	err := t.graphql(ctx, `mutation($id: String!, $input: IssueUpdateInput!) {
+		issueUpdate(id: $id, input: $input) { success }
+	}`, map[string]any{"id": ref, "input": input}, nil)
+	if err != nil {
+		return err
+	}
+
+	return t.graphql(ctx, `mutation($input: CommentCreateInput!) {
+		commentCreate(input: $input) { success }
+	}`, map[string]any{
+		"input": map[string]any{"issueId": ref, "body": eventComment(event, issue)},
+	}, nil)
+}
+
+// graphql posts a query and decodes the response
+func (t *LinearTracker) graphql(ctx context.Context, query string, variables map[string]any, out any) error {
+	return doJSON(ctx, t.HTTPClient, http.MethodPost, t.BaseURL, map[string]string{
+		"Authorization": t.APIKey,
+	}, map[string]any{
+		"query":     query,
+		"variables": variables,
+	}, out)
+}
+
+// linearPriority maps Coinsights severity to Linear priority (1 = urgent, 4 = low)
+func linearPriority(severity string) int {
+	switch severity {
+	case "critical":
+		return 1
+	case "high":
+		return 2
+	case "medium":
+		return 3
+	case "low":
+		return 4
+	default:
+		return 0 // No priority
+	}
+}
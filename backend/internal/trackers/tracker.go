@@ -0,0 +1,322 @@
+// Pushes Coinsights issue lifecycle events into external issue trackers
+package trackers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// Tracker is an external issue tracker (GitHub Issues, Linear, Jira, ...)
+type Tracker interface {
+	// Name identifies the tracker in logs and stored references
+	Name() string
+	// OpenIssue creates a tracker issue and returns its external reference
+	OpenIssue(ctx context.Context, issue models.Issue) (string, error)
+	// UpdateIssue posts an event update to an existing tracker issue
+	UpdateIssue(ctx context.Context, ref string, event string, issue models.Issue) error
+}
+
+// ============================================
+// DISPATCHER
+// ============================================
+// Issue IDs are generated per process, so tracker issues are remembered by the
+// problem they're about - exchange and category - and the references are saved to
+// TrackerRefsFile. After a restart, the same problem updates the tracker issue it
+// already has instead of opening another.
+
+// TrackerRefsFile holds the external references of tracker issues inside the data directory
+const TrackerRefsFile = "tracker_refs.json"
+
+// Dispatcher forwards issue events to every configured tracker.
+// It implements services.IssueObserver.
+type Dispatcher struct {
+	trackers []Tracker
+	refs     map[string]map[string]string // refKey -> tracker name -> external ref
+	path     string                       // Empty: references are kept in memory only
+	mu       sync.Mutex
+}
+
+// NewDispatcher creates a dispatcher for the given trackers, keeping references in memory
+func NewDispatcher(trackers ...Tracker) *Dispatcher {
+	return &Dispatcher{
+		trackers: trackers,
+		refs:     make(map[string]map[string]string),
+	}
+}
+
+// LoadRefs reads the references saved in dataDir (none if the file doesn't exist
+// yet) and saves them there from now on
+func (d *Dispatcher) LoadRefs(dataDir string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.path = filepath.Join(dataDir, TrackerRefsFile)
+	data, err := os.ReadFile(d.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tracker references: %w", err)
+	}
+	refs := make(map[string]map[string]string)
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return fmt.Errorf("failed to parse tracker references: %w", err)
+	}
+	d.refs = refs
+	return nil
+}
+
+// NewDispatcherFromEnv creates a dispatcher with every tracker that has credentials
+// set, with its references saved in dataDir. Returns nil if no tracker is configured.
+func NewDispatcherFromEnv(dataDir string) (*Dispatcher, error) {
+	var configured []Tracker
+	if t := NewGitHubTrackerFromEnv(); t != nil {
+		configured = append(configured, t)
+	}
+	if t := NewLinearTrackerFromEnv(); t != nil {
+		configured = append(configured, t)
+	}
+	if t := NewJiraTrackerFromEnv(); t != nil {
+		configured = append(configured, t)
+	}
+	if len(configured) == 0 {
+		return nil, nil
+	}
+	d := NewDispatcher(configured...)
+	if err := d.LoadRefs(dataDir); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Trackers returns the names of the configured trackers
+func (d *Dispatcher) Trackers() []string {
+	names := make([]string, len(d.trackers))
+	for i, t := range d.trackers {
+		names[i] = t.Name()
+	}
+	return names
+}
+
+// IssueChanged opens or updates the issue in every tracker. The trackers are the
+// deployment's, so tenants' issues never reach them.
+func (d *Dispatcher) IssueChanged(ctx context.Context, event string, issue models.Issue) {
+	if issue.Tenant != "" {
+		return
+	}
+	key := refKey(issue)
+	for _, t := range d.trackers {
+		ref := d.ref(key, t.Name())
+
+		if ref == "" {
+			newRef, err := t.OpenIssue(ctx, issue)
+			if err != nil {
+				fmt.Printf("⚠️  %s: failed to open issue %s: %v\n", t.Name(), issue.ID, err)
+				continue
+			}
+			d.setRef(key, t.Name(), newRef)
+			// The creation already reflects the current state
+			if event == "detected" {
+				continue
+			}
+			ref = newRef
+		}
+
+		if err := t.UpdateIssue(ctx, ref, event, issue); err != nil {
+			fmt.Printf("⚠️  %s: failed to update issue %s: %v\n", t.Name(), issue.ID, err)
+		}
+	}
+}
+
+// Refs returns the external references recorded for an issue's exchange and category
+func (d *Dispatcher) Refs(issue models.Issue) map[string]string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := refKey(issue)
+	refs := make(map[string]string, len(d.refs[key]))
+	for name, ref := range d.refs[key] {
+		refs[name] = ref
+	}
+	return refs
+}
+
+// refKey is what tracker issues are remembered by: the exchange and category,
+// which stay the same across restarts while issue IDs don't
+func refKey(issue models.Issue) string {
+	return strings.ToLower(issue.Exchange) + "/" + issue.Category
+}
+
+func (d *Dispatcher) ref(key, tracker string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.refs[key][tracker]
+}
+
+func (d *Dispatcher) setRef(key, tracker, ref string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.refs[key] == nil {
+		d.refs[key] = make(map[string]string)
+	}
+	d.refs[key][tracker] = ref
+	if err := d.save(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// save writes the references. Must be called with d.mu held.
+func (d *Dispatcher) save() error {
+	if d.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(d.refs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tracker references: %w", err)
+	}
+	if err := os.WriteFile(d.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write tracker references: %w", err)
+	}
+	return nil
+}
+
+// ============================================
+// LABEL MAPPING
+// ============================================
+
+// Labels maps an issue's exchange, category, and severity to tracker labels
+func Labels(issue models.Issue) []string {
+	labels := []string{"coinsights"}
+	if issue.Exchange != "" {
+		labels = append(labels, "exchange:"+issue.Exchange)
+	}
+	if issue.Category != "" {
+		labels = append(labels, "category:"+issue.Category)
+	}
+	if issue.Severity != "" {
+		labels = append(labels, "severity:"+issue.Severity)
+	}
+	if issue.Status != "" && issue.Status != "active" {
+		labels = append(labels, "status:"+issue.Status)
+	}
+	return labels
+}
+
+// issueBody renders the description posted to trackers
+func issueBody(issue models.Issue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", issue.Description)
+	fmt.Fprintf(&b, "- Exchange: %s\n", issue.Exchange)
+	fmt.Fprintf(&b, "- Category: %s\n", issue.Category)
+	fmt.Fprintf(&b, "- Severity: %s\n", issue.Severity)
+	fmt.Fprintf(&b, "- Complaints: %d\n", issue.ComplaintCount)
+	fmt.Fprintf(&b, "- First detected: %s\n", issue.FirstDetected.Format(time.RFC3339))
+	fmt.Fprintf(&b, "\n_Coinsights issue ID: %s_\n", issue.ID)
+	return b.String()
+}
+
+// eventComment renders the comment posted when an issue changes
+func eventComment(event string, issue models.Issue) string {
+	switch event {
+	case "resolved":
+		msg := "Coinsights detected a resolution for this issue."
+		if issue.Resolution != nil {
+			msg += fmt.Sprintf("\n\n%s\n\nConfidence: %.0f%%", issue.Resolution.Summary, issue.Resolution.Confidence*100)
+		}
+		return msg
+	case "attested":
+		msg := "The resolution for this issue was attested on-chain."
+		if issue.Attestation != nil {
+			msg += fmt.Sprintf("\n\nTransaction: %s", issue.Attestation.ExplorerURL)
+		}
+		return msg
+	case "archived":
+		msg := "This issue was archived in Coinsights"
+		if issue.ArchivedBy != "" {
+			msg += " by " + issue.ArchivedBy
+		}
+		msg += "."
+		if issue.ArchiveReason != "" {
+			msg += "\n\nReason: " + issue.ArchiveReason
+		}
+		return msg
+	case "unarchived":
+		return "This issue was restored from the Coinsights archive."
+	case "velocity_alert":
+		msg := "Complaints about this issue are arriving fast"
+		if v := issue.Velocity; v != nil {
+			msg += fmt.Sprintf(": %.1f a day over the last %d days, against %.1f the %d days before", v.PerDay, v.WindowDays, v.PreviousPerDay, v.WindowDays)
+		}
+		return msg + "."
+	case "sla_breached":
+		msg := fmt.Sprintf("This %s severity issue is past its SLA", issue.Severity)
+		if issue.SLA != nil {
+			msg += fmt.Sprintf(": expected to improve within %d days, now %.0f days since first detection", issue.SLA.WindowDays, issue.SLA.ElapsedDays)
+		}
+		return msg + "."
+	default:
+		return fmt.Sprintf("Issue updated: status %s, severity %s, %d complaints.",
+			issue.Status, issue.Severity, issue.ComplaintCount)
+	}
+}
+
+// ============================================
+// HTTP HELPER
+// ============================================
+
+// doJSON sends a JSON request and decodes the JSON response into out (if non-nil)
+func doJSON(ctx context.Context, client *http.Client, method, url string, headers map[string]string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// newHTTPClient returns the client shared by tracker implementations
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 15 * time.Second}
+}
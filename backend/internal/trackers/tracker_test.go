@@ -0,0 +1,99 @@
+package trackers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// fakeGitHub records the issues opened and the state sent with each update
+type fakeGitHub struct {
+	mu      sync.Mutex
+	opened  int
+	updates []map[string]any // PATCH bodies, in order
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/issues"):
+		f.opened++
+		json.NewEncoder(w).Encode(map[string]int{"number": f.opened})
+	case r.Method == http.MethodPatch:
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		f.updates = append(f.updates, body)
+		w.Write([]byte("{}"))
+	default:
+		w.Write([]byte("{}"))
+	}
+}
+
+func newGitHub(t *testing.T) (*fakeGitHub, *GitHubTracker) {
+	fake := &fakeGitHub{}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	tracker := NewGitHubTracker("token", "owner/repo")
+	tracker.BaseURL = srv.URL
+	return fake, tracker
+}
+
+// TestRefsSurviveRestart checks the same problem, under a new issue ID after a
+// restart, updates the tracker issue it already has instead of opening another
+func TestRefsSurviveRestart(t *testing.T) {
+	fake, tracker := newGitHub(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	before := NewDispatcher(tracker)
+	if err := before.LoadRefs(dir); err != nil {
+		t.Fatal(err)
+	}
+	before.IssueChanged(ctx, "detected", models.Issue{ID: "issue-1", Exchange: "Coinbase", Category: "withdrawals"})
+
+	after := NewDispatcher(tracker)
+	if err := after.LoadRefs(dir); err != nil {
+		t.Fatal(err)
+	}
+	issue := models.Issue{ID: "issue-2", Exchange: "coinbase", Category: "withdrawals"}
+	after.IssueChanged(ctx, "updated", issue)
+
+	if fake.opened != 1 {
+		t.Errorf("opened %d tracker issues, want 1", fake.opened)
+	}
+	if refs := after.Refs(issue); refs["github"] != "1" {
+		t.Errorf("refs %v after a restart, want github issue 1", refs)
+	}
+}
+
+// TestGitHubStateOnlyOnLifecycleEvents checks updates send a state only when the
+// issue is closed or reopened, so a hand-closed issue isn't reopened by a comment
+func TestGitHubStateOnlyOnLifecycleEvents(t *testing.T) {
+	fake, tracker := newGitHub(t)
+	issue := models.Issue{ID: "issue-1", Exchange: "coinbase", Category: "withdrawals"}
+
+	want := map[string]any{
+		"updated":        nil,
+		"velocity_alert": nil,
+		"resolved":       nil,
+		"archived":       "closed",
+		"unarchived":     "open",
+		"attested":       "closed",
+	}
+	for event, state := range want {
+		fake.updates = nil
+		if err := tracker.UpdateIssue(context.Background(), "1", event, issue); err != nil {
+			t.Fatal(err)
+		}
+		if len(fake.updates) != 1 || fake.updates[0]["state"] != state {
+			t.Errorf("%s sent %v, want state %v", event, fake.updates, state)
+		}
+	}
+}
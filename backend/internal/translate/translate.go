@@ -0,0 +1,260 @@
+// Translation of non-English complaints into English before keyword analysis
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tasnint/coinsights/internal/analyzer"
+	"github.com/tasnint/coinsights/internal/models"
+	"google.golang.org/genai"
+)
+
+// BatchSize is how many texts are sent per translation request
+const BatchSize = 40
+
+// Enabled reports whether TRANSLATE_COMMENTS turns comment translation on
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("TRANSLATE_COMMENTS"))
+	return enabled
+}
+
+// Translation is one translated text
+type Translation struct {
+	Language string `json:"language"` // ISO 639-1 code of the source text, e.g. "es"
+	Text     string `json:"text"`     // English translation (the original if already English)
+}
+
+// Translator translates texts to English, returning one Translation per input in order
+type Translator interface {
+	Translate(ctx context.Context, texts []string) ([]Translation, error)
+}
+
+// ============================================
+// GEMINI TRANSLATOR
+// ============================================
+
+// GeminiTranslator translates with Gemini in batched JSON requests
+type GeminiTranslator struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiTranslator creates a translator using GEMINI_API_KEY (or GOOGLE_API_KEY)
+func NewGeminiTranslator(ctx context.Context) (*GeminiTranslator, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		apiKey = os.Getenv("GOOGLE_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY or GOOGLE_API_KEY environment variable not set")
+	}
+
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: apiKey, Backend: genai.BackendGeminiAPI})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+	return &GeminiTranslator{client: client, model: "gemini-2.0-flash"}, nil
+}
+
+// Translate sends texts to Gemini and expects a JSON array of {language, text} back
+func (g *GeminiTranslator) Translate(ctx context.Context, texts []string) ([]Translation, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode texts: %w", err)
+	}
+
+	prompt := `Translate each of the following user comments into English.
+Return a JSON array with exactly one object per input, in the same order:
+[{"language": "<ISO 639-1 code of the original>", "text": "<English translation>"}]
+Keep slang, product names and amounts intact. If a comment is already English, return it unchanged with language "en".
+
+Comments:
+` + string(input)
+
+	result, err := g.client.Models.GenerateContent(ctx, g.model, genai.Text(prompt), &genai.GenerateContentConfig{
+		ResponseMIMEType: "application/json",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API error: %w", err)
+	}
+
+	var translations []Translation
+	if err := json.Unmarshal([]byte(result.Text()), &translations); err != nil {
+		return nil, fmt.Errorf("failed to parse translations: %w", err)
+	}
+	if len(translations) != len(texts) {
+		return nil, fmt.Errorf("expected %d translations, got %d", len(texts), len(translations))
+	}
+	return translations, nil
+}
+
+// ============================================
+// PIPELINE
+// ============================================
+
+// Comments translates non-English comments in place: Text becomes the English
+// translation and the original moves to OriginalText. Comments that already look
+// English are never sent. Returns how many comments were translated; a failed batch
+// is skipped (its comments keep their original text) rather than aborting the run.
+func Comments(ctx context.Context, t Translator, comments []models.YouTubeComment) (int, error) {
+	texts := make([]string, len(comments))
+	for i, c := range comments {
+		if c.OriginalText == "" {
+			texts[i] = c.Text
+		}
+	}
+	return translateEach(ctx, t, texts, func(i int, r Translation) {
+		comments[i].OriginalText = comments[i].Text
+		comments[i].Language = r.Language
+		comments[i].Text = r.Text
+	})
+}
+
+// Records translates the analyzer's comment Records in place, the way Comments does
+func Records(ctx context.Context, t Translator, records []analyzer.Record) (int, error) {
+	texts := make([]string, len(records))
+	for i, record := range records {
+		if record.Source == "comment" && record.OriginalText == "" {
+			texts[i] = record.Text
+		}
+	}
+	return translateEach(ctx, t, texts, func(i int, r Translation) {
+		records[i].OriginalText = records[i].Text
+		records[i].Language = r.Language
+		records[i].Text = r.Text
+	})
+}
+
+// translateEach sends the texts that don't look English in batches and calls apply
+// with the index of each one that came back translated. Empty texts are skipped.
+func translateEach(ctx context.Context, t Translator, texts []string, apply func(i int, r Translation)) (int, error) {
+	var pending []int
+	for i, text := range texts {
+		if text != "" && !LooksEnglish(text) {
+			pending = append(pending, i)
+		}
+	}
+
+	translated := 0
+	var lastErr error
+	for start := 0; start < len(pending); start += BatchSize {
+		end := min(start+BatchSize, len(pending))
+		batch := pending[start:end]
+
+		batchTexts := make([]string, len(batch))
+		for j, idx := range batch {
+			batchTexts[j] = texts[idx]
+		}
+
+		results, err := t.Translate(ctx, batchTexts)
+		if err != nil {
+			fmt.Printf("⚠️  Translation batch failed: %v\n", err)
+			lastErr = err
+			continue
+		}
+
+		for j, idx := range batch {
+			r := results[j]
+			if r.Language == "" || r.Language == "en" || r.Text == "" {
+				continue
+			}
+			apply(idx, r)
+			translated++
+		}
+	}
+
+	if translated == 0 && lastErr != nil {
+		return 0, fmt.Errorf("translation failed: %w", lastErr)
+	}
+	return translated, nil
+}
+
+// ============================================
+// ANALYZER STAGE
+// ============================================
+// Scrapes translated as they're scraped (TRANSLATE_COMMENTS) reach the analyzer
+// already in English. Naming "translate" in the analyzer's stages, after normalize,
+// also translates comments of scrapes that weren't, such as ones saved before
+// translation was turned on.
+
+func init() {
+	analyzer.RegisterStage("translate", func() analyzer.Stage { return &stage{} })
+}
+
+// stage translates a pass's comment Records, creating its translator on first use
+type stage struct {
+	translator Translator
+	failed     bool // No translator could be created; the stage does nothing
+}
+
+// Run translates the pass's comment Records that haven't been translated yet
+func (s *stage) Run(p *analyzer.Pass) {
+	ctx := context.Background()
+	if s.translator == nil && !s.failed {
+		translator, err := NewGeminiTranslator(ctx)
+		if err != nil {
+			fmt.Printf("⚠️  Translation unavailable: %v\n", err)
+			s.failed = true
+			return
+		}
+		s.translator = translator
+	}
+	if s.failed {
+		return
+	}
+
+	count, err := Records(ctx, s.translator, p.Records)
+	if err != nil {
+		fmt.Printf("⚠️  Translation error: %v\n", err)
+		return
+	}
+	if count > 0 {
+		fmt.Printf("🌐 Translated %d comments\n", count)
+	}
+}
+
+// englishMarkers are very common English words; their presence suggests English text
+var englishMarkers = map[string]bool{
+	"the": true, "and": true, "is": true, "to": true, "my": true, "it": true,
+	"you": true, "i": true, "a": true, "of": true, "for": true, "this": true,
+	"not": true, "can't": true, "they": true, "have": true, "was": true, "with": true,
+}
+
+// LooksEnglish is a cheap pre-filter so only likely non-English text is sent for translation.
+// Mostly non-Latin script, or Latin text with no common English words, counts as non-English.
+func LooksEnglish(text string) bool {
+	letters, latin := 0, 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if r < unicode.MaxLatin1 {
+				latin++
+			}
+		}
+	}
+	if letters == 0 {
+		return true // Emoji, numbers, links - nothing to translate
+	}
+	if float64(latin)/float64(letters) < 0.8 {
+		return false
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) < 4 {
+		return true // Too short to judge; keyword matching still works on short English-like text
+	}
+	for _, w := range words {
+		if englishMarkers[w] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+// Daily complaint volume per category across scrape runs, for trend charts
+package trends
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// ============================================
+// COMPLAINT TRENDS
+// ============================================
+// Each scrape only holds what its queries surfaced that day, so a chart built from
+// the latest results can't show whether a category is getting better or worse. The
+// store keeps every complaint seen across runs, bucketed by the day it was posted
+// and its category. Buckets hold complaint IDs rather than counts, so a complaint
+// re-scraped by later runs is only counted once.
+
+const (
+	// StoreFile is the trend store inside the data directory
+	StoreFile = "complaint_trends.json"
+	// MaxDays is how many days of buckets the store keeps
+	MaxDays = 365
+	// DefaultMovingAverage is the moving average window, in days, when none is given
+	DefaultMovingAverage = 7
+)
+
+// Directions a series can be heading
+const (
+	DirectionWorsening = "worsening"
+	DirectionImproving = "improving"
+	DirectionFlat      = "flat"
+)
+
+// flatThreshold is the relative change in moving average still called flat
+const flatThreshold = 0.1
+
+// Point is one day of a series
+type Point struct {
+	Date          string  `json:"date"` // "2006-01-02", UTC
+	Count         int     `json:"count"`
+	MovingAverage float64 `json:"moving_average"` // Mean count over the MovingAverage days ending on Date
+}
+
+// Series is a category's daily complaint counts over a window
+type Series struct {
+	Category      string  `json:"category"` // Empty = every category
+	Days          int     `json:"days"`
+	MovingAverage int     `json:"moving_average"` // Days averaged per point
+	Points        []Point `json:"points"`         // Oldest first, one per day, zero-filled
+	Total         int     `json:"total"`
+	Change        float64 `json:"change"`    // Last moving average minus the first
+	Direction     string  `json:"direction"` // "worsening", "improving" or "flat"
+}
+
+// Store keeps complaint IDs bucketed by day and category, persisted to a JSON file
+type Store struct {
+	path string
+	days map[string]map[string][]string // date -> category -> sorted complaint IDs
+	mu   sync.RWMutex
+}
+
+// NewStore loads the trend store from dataDir (empty if the file doesn't exist yet)
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{
+		path: filepath.Join(dataDir, StoreFile),
+		days: make(map[string]map[string][]string),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read complaint trends: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.days); err != nil {
+		return nil, fmt.Errorf("failed to parse complaint trends: %w", err)
+	}
+	return s, nil
+}
+
+// Record adds complaints to their day's buckets, ignoring ones already recorded and
+// ones older than MaxDays, and returns how many were new
+func (s *Store) Record(complaints []models.Complaint, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.UTC().AddDate(0, 0, -MaxDays).Format(time.DateOnly)
+	added := 0
+	for _, c := range complaints {
+		day := postedAt(c).UTC().Format(time.DateOnly)
+		if c.ID == "" || c.Category == "" || day < cutoff {
+			continue
+		}
+		categories := s.days[day]
+		if categories == nil {
+			categories = make(map[string][]string)
+			s.days[day] = categories
+		}
+		ids := categories[c.Category]
+		i, found := slices.BinarySearch(ids, c.ID)
+		if found {
+			continue
+		}
+		categories[c.Category] = slices.Insert(ids, i, c.ID)
+		added++
+	}
+
+	for day := range s.days {
+		if day < cutoff {
+			delete(s.days, day)
+		}
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.save()
+}
+
+// postedAt is when a complaint was made, as near as its source says
+func postedAt(c models.Complaint) time.Time {
+	switch {
+	case !c.PublishedAt.IsZero():
+		return c.PublishedAt
+	case !c.FirstSeen.IsZero():
+		return c.FirstSeen
+	default:
+		return c.ScrapedAt
+	}
+}
+
+// Series returns a category's daily counts for the days days ending today, each with
+// its movingAverage-day moving average. An empty category counts every category.
+func (s *Store) Series(category string, days, movingAverage int, now time.Time) Series {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if movingAverage < 1 {
+		movingAverage = DefaultMovingAverage
+	}
+
+	// Count the moving average's lead-in days too, so the first point is averaged over a full window
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -(days + movingAverage - 2))
+	counts := make([]int, 0, days+movingAverage-1)
+	for d := first; !d.After(today); d = d.AddDate(0, 0, 1) {
+		counts = append(counts, s.count(d.Format(time.DateOnly), category))
+	}
+
+	series := Series{Category: category, Days: days, MovingAverage: movingAverage, Points: []Point{}}
+	sum := 0
+	for i, count := range counts {
+		sum += count
+		if i >= movingAverage {
+			sum -= counts[i-movingAverage]
+		}
+		if i < movingAverage-1 {
+			continue
+		}
+		series.Points = append(series.Points, Point{
+			Date:          first.AddDate(0, 0, i).Format(time.DateOnly),
+			Count:         count,
+			MovingAverage: float64(sum) / float64(movingAverage),
+		})
+		series.Total += count
+	}
+
+	series.Direction = DirectionFlat
+	if n := len(series.Points); n > 1 {
+		start, end := series.Points[0].MovingAverage, series.Points[n-1].MovingAverage
+		series.Change = end - start
+		if base := max(start, 1); series.Change > base*flatThreshold {
+			series.Direction = DirectionWorsening
+		} else if series.Change < -base*flatThreshold {
+			series.Direction = DirectionImproving
+		}
+	}
+	return series
+}
+
+// count is how many complaints a day's bucket holds for category ("" = all). Must be called with s.mu held.
+func (s *Store) count(day, category string) int {
+	if category != "" {
+		return len(s.days[day][category])
+	}
+	total := 0
+	for _, ids := range s.days[day] {
+		total += len(ids)
+	}
+	return total
+}
+
+// save writes the store. Must be called with s.mu held.
+func (s *Store) save() error {
+	data, err := json.MarshalIndent(s.days, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal complaint trends: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write complaint trends: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+// Go client for the Coinsights API
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tasnint/coinsights/internal/retry"
+)
+
+// Client talks to a Coinsights API server
+type Client struct {
+	BaseURL    string // e.g. http://localhost:8080/api
+	HTTPClient *http.Client
+	MaxRetries int           // Retries for network errors, 429 and 5xx responses, on requests safe to repeat
+	RetryDelay time.Duration // Base delay, doubled on each retry
+}
+
+// APIError is returned when the server responds with a non-2xx status
+type APIError struct {
+	StatusCode int               `json:"-"`
+	Message    string            `json:"error"`
+	Errors     []ValidationError `json:"errors,omitempty"` // Rule violations, e.g. rejected resolution evidence
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("coinsights API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// New creates a new API client
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		MaxRetries: 3,
+		RetryDelay: 500 * time.Millisecond,
+	}
+}
+
+// ============================================
+// RESPONSE TYPES
+// ============================================
+
+// IssueList is returned by ListIssues
+type IssueList struct {
+	Issues []*Issue `json:"issues"`
+	Count  int      `json:"count"`
+}
+
+// ResolutionList is returned by ListResolutions
+type ResolutionList struct {
+	Resolutions []*Resolution `json:"resolutions"`
+	Count       int           `json:"count"`
+}
+
+// CreateResolutionRequest is the request body for CreateResolution
+type CreateResolutionRequest struct {
+	IssueID  string             `json:"issue_id"`
+	Summary  string             `json:"summary"`
+	Evidence ResolutionEvidence `json:"evidence"`
+}
+
+// ChainInfo is returned by GetChainInfo
+type ChainInfo struct {
+	Chain           ChainConfig            `json:"chain"`
+	WalletAddress   string                 `json:"wallet_address"`
+	SupportedChains map[string]ChainConfig `json:"supported_chains"`
+}
+
+// ============================================
+// ISSUES
+// ============================================
+
+// ListIssues calls GET /issues, optionally filtered by status
+func (c *Client) ListIssues(ctx context.Context, status string) (*IssueList, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	var out IssueList
+	if err := c.do(ctx, http.MethodGet, "/issues", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetIssue calls GET /issues/{id}
+func (c *Client) GetIssue(ctx context.Context, id string) (*Issue, error) {
+	var out Issue
+	if err := c.do(ctx, http.MethodGet, "/issues/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateIssue calls POST /issues.
+// Creating an issue is not idempotent, so it is never retried.
+func (c *Client) CreateIssue(ctx context.Context, issue *Issue) (*Issue, error) {
+	var out Issue
+	if err := c.doOnce(ctx, http.MethodPost, "/issues", nil, issue, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ArchiveIssue calls POST /issues/{id}/archive
+func (c *Client) ArchiveIssue(ctx context.Context, id, archivedBy, reason string) (*Issue, error) {
+	body := map[string]string{"archived_by": archivedBy, "reason": reason}
+	var out Issue
+	if err := c.do(ctx, http.MethodPost, "/issues/"+url.PathEscape(id)+"/archive", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnarchiveIssue calls POST /issues/{id}/unarchive
+func (c *Client) UnarchiveIssue(ctx context.Context, id string) (*Issue, error) {
+	var out Issue
+	if err := c.do(ctx, http.MethodPost, "/issues/"+url.PathEscape(id)+"/unarchive", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================
+// RESOLUTIONS
+// ============================================
+
+// ListResolutions calls GET /resolutions, optionally filtered by status
+func (c *Client) ListResolutions(ctx context.Context, status string) (*ResolutionList, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+	var out ResolutionList
+	if err := c.do(ctx, http.MethodGet, "/resolutions", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetResolution calls GET /resolutions/{id}
+func (c *Client) GetResolution(ctx context.Context, id string) (*Resolution, error) {
+	var out Resolution
+	if err := c.do(ctx, http.MethodGet, "/resolutions/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateResolution calls POST /resolutions.
+// Creating a resolution is not idempotent, so it is never retried.
+func (c *Client) CreateResolution(ctx context.Context, req CreateResolutionRequest) (*Resolution, error) {
+	var out Resolution
+	if err := c.doOnce(ctx, http.MethodPost, "/resolutions", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================
+// ATTESTATIONS
+// ============================================
+
+// AttestResolution calls POST /attestations.
+// Attestation is not idempotent on the wire, so it is never retried.
+func (c *Client) AttestResolution(ctx context.Context, resolutionID string) (*AttestationResponse, error) {
+	var out AttestationResponse
+	req := attestationRequest{ResolutionID: resolutionID}
+	if err := c.doOnce(ctx, http.MethodPost, "/attestations", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAttestation calls GET /resolutions/{id}/attestation
+func (c *Client) GetAttestation(ctx context.Context, resolutionID string) (*Attestation, error) {
+	var out Attestation
+	path := "/resolutions/" + url.PathEscape(resolutionID) + "/attestation"
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyByHash calls POST /attestations/verify with an evidence hash
+func (c *Client) VerifyByHash(ctx context.Context, evidenceHash string) (*VerificationResponse, error) {
+	return c.verify(ctx, verificationRequest{EvidenceHash: evidenceHash})
+}
+
+// VerifyResolution calls POST /attestations/verify with a resolution ID
+func (c *Client) VerifyResolution(ctx context.Context, resolutionID string) (*VerificationResponse, error) {
+	return c.verify(ctx, verificationRequest{ResolutionID: resolutionID})
+}
+
+func (c *Client) verify(ctx context.Context, req verificationRequest) (*VerificationResponse, error) {
+	var out VerificationResponse
+	if err := c.do(ctx, http.MethodPost, "/attestations/verify", nil, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ============================================
+// BLOCKCHAIN INFO
+// ============================================
+
+// GetChainInfo calls GET /blockchain/info
+func (c *Client) GetChainInfo(ctx context.Context) (*ChainInfo, error) {
+	var out ChainInfo
+	if err := c.do(ctx, http.MethodGet, "/blockchain/info", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStats calls GET /blockchain/stats
+func (c *Client) GetStats(ctx context.Context) (map[string]any, error) {
+	var out map[string]any
+	if err := c.do(ctx, http.MethodGet, "/blockchain/stats", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HashEvidence calls POST /blockchain/hash
+func (c *Client) HashEvidence(ctx context.Context, evidence ResolutionEvidence) (string, error) {
+	var out struct {
+		Hash string `json:"hash"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/blockchain/hash", nil, evidence, &out); err != nil {
+		return "", err
+	}
+	return out.Hash, nil
+}
+
+// ============================================
+// TRANSPORT
+// ============================================
+
+// do sends a request, retrying transient failures with exponential backoff
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	policy := retry.Policy{MaxRetries: c.MaxRetries, BaseDelay: c.RetryDelay}
+	return retry.Do(ctx, policy, isRetryable, func(int) error {
+		return c.doOnce(ctx, method, path, query, body, out)
+	})
+}
+
+// doOnce sends a single request and decodes the JSON response into out
+func (c *Client) doOnce(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	reqURL := c.BaseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// isRetryable reports whether a request error is worth retrying
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return retry.Status(apiErr.StatusCode)
+	}
+	// Network-level failure
+	return retry.Transient(err)
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tasnint/coinsights/internal/models"
+)
+
+// TestTypesMirrorModels checks every mirror type has the JSON fields of the model it copies
+func TestTypesMirrorModels(t *testing.T) {
+	pairs := []struct{ mirror, model any }{
+		{Issue{}, models.Issue{}},
+		{IssueReach{}, models.IssueReach{}},
+		{IssueSLA{}, models.IssueSLA{}},
+		{ComplaintVelocity{}, models.ComplaintVelocity{}},
+		{Resolution{}, models.Resolution{}},
+		{AnnouncementLink{}, models.AnnouncementLink{}},
+		{ResolutionEvidence{}, models.ResolutionEvidence{}},
+		{ScrapeEffort{}, models.ScrapeEffort{}},
+		{NormalizedCount{}, models.NormalizedCount{}},
+		{ValidationError{}, models.ValidationError{}},
+		{Attestation{}, models.Attestation{}},
+		{ExplorerDetails{}, models.ExplorerDetails{}},
+		{AttestationResponse{}, models.AttestationResponse{}},
+		{VerificationResponse{}, models.VerificationResponse{}},
+		{ChainConfig{}, models.ChainConfig{}},
+	}
+	for _, pair := range pairs {
+		mirror, model := jsonFields(pair.mirror), jsonFields(pair.model)
+		if !slices.Equal(mirror, model) {
+			t.Errorf("%T fields %v, want those of %T: %v", pair.mirror, mirror, pair.model, model)
+		}
+	}
+}
+
+// jsonFields returns the JSON tags of a struct's fields, sorted
+func jsonFields(v any) []string {
+	typ := reflect.TypeOf(v)
+	fields := make([]string, 0, typ.NumField())
+	for i := range typ.NumField() {
+		fields = append(fields, typ.Field(i).Tag.Get("json"))
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// TestRetries checks reads are retried and creates are sent once
+func TestRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.RetryDelay = 0
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		call  func() error
+		calls int32
+	}{
+		{"GetIssue", func() error { _, err := c.GetIssue(ctx, "issue-1"); return err }, int32(c.MaxRetries) + 1},
+		{"CreateIssue", func() error { _, err := c.CreateIssue(ctx, &Issue{Title: "t"}); return err }, 1},
+		{"CreateResolution", func() error {
+			_, err := c.CreateResolution(ctx, CreateResolutionRequest{IssueID: "issue-1"})
+			return err
+		}, 1},
+		{"AttestResolution", func() error { _, err := c.AttestResolution(ctx, "res-1"); return err }, 1},
+	}
+	for _, tt := range tests {
+		calls.Store(0)
+		err := tt.call()
+		if err == nil || !strings.Contains(err.Error(), "503") {
+			t.Errorf("%s: got error %v, want a 503", tt.name, err)
+		}
+		if got := calls.Load(); got != tt.calls {
+			t.Errorf("%s: sent %d requests, want %d", tt.name, got, tt.calls)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package client
+
+import "time"
+
+// ============================================
+// API TYPES
+// ============================================
+// Wire types of the API, mirrored from the server's internal models so callers
+// outside this module can name them. They carry the same JSON fields; a test keeps
+// them in step with the server.
+
+// Issue is a detected issue being tracked
+type Issue struct {
+	ID             string       `json:"id"`
+	Tenant         string       `json:"tenant,omitempty"` // Watch program it belongs to; empty for the deployment's own
+	Exchange       string       `json:"exchange"`
+	Category       string       `json:"category"`
+	Title          string       `json:"title"`
+	Description    string       `json:"description"`
+	FirstDetected  time.Time    `json:"first_detected"`
+	LastUpdated    time.Time    `json:"last_updated"`
+	ComplaintCount int          `json:"complaint_count"`
+	WeightedCount  float64      `json:"weighted_count,omitempty"`
+	ComplaintLinks []string     `json:"complaint_links,omitempty"`
+	Severity       string       `json:"severity"` // "critical", "high", "medium", "low"
+	Status         string       `json:"status"`   // "active", "investigating", "resolved", "verified"
+	Resolution     *Resolution  `json:"resolution,omitempty"`
+	Attestation    *Attestation `json:"attestation,omitempty"`
+
+	Effort     *ScrapeEffort    `json:"effort,omitempty"`
+	Normalized *NormalizedCount `json:"normalized,omitempty"`
+	Reach      *IssueReach      `json:"reach,omitempty"`
+
+	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
+	ArchivedBy    string     `json:"archived_by,omitempty"`
+	ArchiveReason string     `json:"archive_reason,omitempty"`
+
+	SLA           *IssueSLA  `json:"sla,omitempty"`
+	SLABreachedAt *time.Time `json:"sla_breached_at,omitempty"`
+
+	Velocity          *ComplaintVelocity `json:"velocity,omitempty"`
+	VelocityAlertedAt *time.Time         `json:"velocity_alerted_at,omitempty"`
+}
+
+// IssueReach estimates how many people have seen an issue being complained about
+type IssueReach struct {
+	Estimated int64 `json:"estimated"`
+	Views     int64 `json:"views"`
+	Upvotes   int64 `json:"upvotes"`
+	Threads   int   `json:"threads"`
+}
+
+// IssueSLA is how an open issue is tracking against its severity's SLA window
+type IssueSLA struct {
+	WindowDays    int       `json:"window_days"`
+	DueAt         time.Time `json:"due_at"`
+	ElapsedDays   float64   `json:"elapsed_days"`
+	RemainingDays float64   `json:"remaining_days"` // Negative once overdue
+	Overdue       bool      `json:"overdue"`
+}
+
+// ComplaintVelocity is how many new complaints about an issue arrive a day
+type ComplaintVelocity struct {
+	WindowDays     int       `json:"window_days"`
+	Complaints     int       `json:"complaints"`
+	PerDay         float64   `json:"per_day"`
+	PreviousPerDay float64   `json:"previous_per_day"`
+	MeasuredAt     time.Time `json:"measured_at"`
+}
+
+// Resolution is a resolved issue with evidence
+type Resolution struct {
+	ID               string             `json:"id"`
+	IssueID          string             `json:"issue_id,omitempty"`
+	Tenant           string             `json:"tenant,omitempty"`
+	Exchange         string             `json:"exchange"`
+	IssueCategory    string             `json:"issue_category"`
+	Summary          string             `json:"summary"`
+	Evidence         ResolutionEvidence `json:"evidence"`
+	Confidence       float64            `json:"confidence"`        // 0.0-1.0
+	ResolutionWindow int                `json:"resolution_window"` // Days
+	Status           string             `json:"status"`            // "pending", "verified", "on_chain", "needs_review", "rejected"
+	CreatedAt        time.Time          `json:"created_at"`
+	VerifiedAt       *time.Time         `json:"verified_at,omitempty"`
+	VerifiedVia      string             `json:"verified_via,omitempty"` // "auto" or "manual"
+	Attestation      *Attestation       `json:"attestation,omitempty"`
+	MetadataURI      string             `json:"metadata_uri,omitempty"`
+
+	Detected   bool       `json:"detected,omitempty"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+
+	Announcements []AnnouncementLink `json:"announcements,omitempty"`
+}
+
+// AnnouncementLink is an exchange changelog, blog or status post linked to a resolution
+type AnnouncementLink struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Source      string    `json:"source"` // "changelog", "blog", "status"
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ResolutionEvidence is the data that gets hashed for on-chain attestation
+type ResolutionEvidence struct {
+	ComplaintsBefore    int       `json:"complaints_before"`
+	ComplaintsAfter     int       `json:"complaints_after"`
+	PercentageDecrease  float64   `json:"percentage_decrease"`
+	SentimentShift      float64   `json:"sentiment_shift"` // -1 to 1
+	SampleComplaints    []string  `json:"sample_complaints"`
+	DataSources         []string  `json:"data_sources"`
+	MeasurementStart    time.Time `json:"measurement_start"`
+	MeasurementEnd      time.Time `json:"measurement_end"`
+	AnalysisMethodology string    `json:"analysis_methodology"`
+
+	AppVersion string `json:"app_version,omitempty"`
+	OS         string `json:"os,omitempty"`
+
+	EffortBefore     *ScrapeEffort    `json:"effort_before,omitempty"`
+	EffortAfter      *ScrapeEffort    `json:"effort_after,omitempty"`
+	NormalizedBefore *NormalizedCount `json:"normalized_before,omitempty"` // Set by the server
+	NormalizedAfter  *NormalizedCount `json:"normalized_after,omitempty"`  // Set by the server
+
+	SourcesBefore map[string]int `json:"sources_before,omitempty"`
+	SourcesAfter  map[string]int `json:"sources_after,omitempty"`
+}
+
+// ScrapeEffort is how much material a scrape run covered
+type ScrapeEffort struct {
+	CommentsScanned int `json:"comments_scanned"`
+	VideosScanned   int `json:"videos_scanned"`
+	Queries         int `json:"queries"`
+	Sources         int `json:"sources"`
+}
+
+// NormalizedCount is a complaint count relative to scrape effort
+type NormalizedCount struct {
+	PerThousandComments float64 `json:"per_1k_comments"`
+	PerQuery            float64 `json:"per_query"`
+	PerSource           float64 `json:"per_source"`
+}
+
+// ValidationError describes one rule a request violated, with a stable code to match on
+type ValidationError struct {
+	Code    string `json:"code"` // e.g. "complaints_increased"
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Attestation is an on-chain verification record
+type Attestation struct {
+	ID              uint64    `json:"id"`
+	TransactionHash string    `json:"transaction_hash"`
+	BlockNumber     uint64    `json:"block_number"`
+	BlockTimestamp  time.Time `json:"block_timestamp"`
+	ChainID         int64     `json:"chain_id"`
+	ContractAddress string    `json:"contract_address"`
+	EvidenceHash    string    `json:"evidence_hash"`
+	PreviousHash    string    `json:"previous_hash,omitempty"`
+	MetadataURI     string    `json:"metadata_uri,omitempty"`
+	EvidenceCID     string    `json:"evidence_cid,omitempty"`
+	Exchange        string    `json:"exchange,omitempty"`
+	IssueCategory   string    `json:"issue_category,omitempty"`
+	Attestor        string    `json:"attestor"`
+	AttestorName    string    `json:"attestor_name,omitempty"`
+	ExplorerURL     string    `json:"explorer_url"`
+	Verified        bool      `json:"verified"`
+
+	Explorer *ExplorerDetails `json:"explorer,omitempty"`
+}
+
+// ExplorerDetails is what the block explorer reports about an attestation transaction
+type ExplorerDetails struct {
+	TxStatus          string    `json:"tx_status"` // "success", "failed", "pending"
+	Confirmations     uint64    `json:"confirmations"`
+	GasUsed           uint64    `json:"gas_used"`
+	EffectiveGasPrice string    `json:"effective_gas_price_wei"`
+	FeeWei            string    `json:"fee_wei"`
+	ContractVerified  bool      `json:"contract_verified"`
+	ContractName      string    `json:"contract_name,omitempty"`
+	TxURL             string    `json:"tx_url"`
+	ContractURL       string    `json:"contract_url"`
+	AttestorURL       string    `json:"attestor_url"`
+	CheckedAt         time.Time `json:"checked_at"`
+}
+
+// AttestationResponse is returned by AttestResolution
+type AttestationResponse struct {
+	Success     bool         `json:"success"`
+	Attestation *Attestation `json:"attestation,omitempty"`
+	Queued      bool         `json:"queued,omitempty"`       // Waiting for the next attestation batch
+	ScheduledAt *time.Time   `json:"scheduled_at,omitempty"` // When the queued batch is submitted
+	Error       string       `json:"error,omitempty"`
+}
+
+// VerificationResponse is returned by VerifyByHash and VerifyResolution
+type VerificationResponse struct {
+	Verified       bool         `json:"verified"`
+	OnChain        bool         `json:"on_chain"`
+	Attestation    *Attestation `json:"attestation,omitempty"`
+	HashMatch      bool         `json:"hash_match"`
+	TimestampValid bool         `json:"timestamp_valid"`
+	Message        string       `json:"message"`
+	Cached         bool         `json:"cached,omitempty"`
+
+	AttestorAuthorized *bool `json:"attestor_authorized,omitempty"`
+}
+
+// ChainConfig describes a blockchain network
+type ChainConfig struct {
+	Name            string `json:"name"`
+	ChainID         int64  `json:"chain_id"`
+	RPCURL          string `json:"rpc_url"`
+	ExplorerURL     string `json:"explorer_url"`
+	ContractAddress string `json:"contract_address"`
+	IsTestnet       bool   `json:"is_testnet"`
+}
+
+// attestationRequest and verificationRequest are request bodies
+type attestationRequest struct {
+	ResolutionID string `json:"resolution_id"`
+}
+
+type verificationRequest struct {
+	EvidenceHash string `json:"evidence_hash,omitempty"`
+	ResolutionID string `json:"resolution_id,omitempty"`
+}
@@ -67,6 +67,31 @@ contract ResolutionAttestation {
         address attestor
     );
     
+    /**
+     * @dev Emitted next to ResolutionRecorded when the attestor publishes where the
+     * full evidence can be fetched (IPFS CID or API URL). Kept in the event log rather
+     * than storage: consumers index it off-chain, and storing strings is expensive.
+     */
+    event ResolutionMetadata(
+        uint256 indexed attestationId,
+        bytes32 indexed evidenceHash,
+        string metadataURI
+    );
+    
+    /**
+     * @dev Emitted when an attestor hands over to a new signing key. Both keys sign the
+     * rotation statement off-chain; the signatures are published here so the handover is
+     * auditable without trusting Coinsights' own records. Checking them is left to verifiers.
+     */
+    event AttestorRotated(
+        address indexed previousAttestor,
+        address indexed newAttestor,
+        uint256 effectiveBlock,
+        uint256 previousValidUntilBlock,
+        bytes previousSignature,
+        bytes newSignature
+    );
+    
     /**
      * @dev Emitted when batch attestations are recorded (gas optimization)
      */
@@ -111,6 +136,34 @@ contract ResolutionAttestation {
         string calldata issueCategory,
         bytes32 evidenceHash
     ) external returns (uint256 attestationId) {
+        return _recordResolution(exchange, issueCategory, evidenceHash);
+    }
+    
+    /**
+     * @dev Record a resolution attestation along with where its evidence can be retrieved
+     * @param exchange Name of the exchange (e.g., "coinbase")
+     * @param issueCategory Category of the issue (e.g., "withdrawal_delays")
+     * @param evidenceHash Keccak256 hash of the resolution evidence JSON
+     * @param metadataURI Location of the evidence JSON (e.g., "ipfs://<cid>" or an API URL)
+     * @return attestationId The ID of the newly created attestation
+     */
+    function recordResolutionWithMetadata(
+        string calldata exchange,
+        string calldata issueCategory,
+        bytes32 evidenceHash,
+        string calldata metadataURI
+    ) external returns (uint256 attestationId) {
+        require(bytes(metadataURI).length > 0, "Empty metadata URI");
+        attestationId = _recordResolution(exchange, issueCategory, evidenceHash);
+        emit ResolutionMetadata(attestationId, evidenceHash, metadataURI);
+        return attestationId;
+    }
+    
+    function _recordResolution(
+        string calldata exchange,
+        string calldata issueCategory,
+        bytes32 evidenceHash
+    ) internal returns (uint256 attestationId) {
         // Get the issue key for chain-of-custody tracking
         bytes32 issueKey = keccak256(abi.encodePacked(exchange, issueCategory));
         bytes32 previousHash = latestHashByIssue[issueKey];
@@ -193,6 +246,33 @@ contract ResolutionAttestation {
         return (startId, endId);
     }
     
+    /**
+     * @dev Announce an attestor key rotation, sent from the outgoing key
+     * @param newAttestor Address of the incoming key
+     * @param effectiveBlock First block attestations from the new key are valid
+     * @param previousValidUntilBlock Last block attestations from the outgoing key are valid
+     * @param previousSignature EIP-191 signature of the rotation statement by the outgoing key
+     * @param newSignature EIP-191 signature of the rotation statement by the incoming key
+     */
+    function announceKeyRotation(
+        address newAttestor,
+        uint256 effectiveBlock,
+        uint256 previousValidUntilBlock,
+        bytes calldata previousSignature,
+        bytes calldata newSignature
+    ) external {
+        require(newAttestor != address(0) && newAttestor != msg.sender, "Invalid new attestor");
+        require(previousValidUntilBlock + 1 >= effectiveBlock, "Validity gap");
+        emit AttestorRotated(
+            msg.sender,
+            newAttestor,
+            effectiveBlock,
+            previousValidUntilBlock,
+            previousSignature,
+            newSignature
+        );
+    }
+    
     // ============================================
     // VIEW FUNCTIONS
     // ============================================
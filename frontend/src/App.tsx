@@ -22,13 +22,26 @@ interface Resolution {
   created_at: string;
 }
 
+interface SourceStats {
+  source: string;
+  complaints: number;
+  categories: number;
+  last_updated?: string;
+  coverage_from?: string;
+  coverage_to?: string;
+}
+
 interface Stats {
   videos_analyzed: number;
   comments_analyzed: number;
   issues_found: number;
   categories: number;
+  complaints: number;
+  sources: SourceStats[];
 }
 
+const formatDate = (value?: string) => (value ? new Date(value).toLocaleDateString() : 'unknown');
+
 function App() {
   const [issues, setIssues] = useState<Issue[]>([]);
   const [resolutions, setResolutions] = useState<Resolution[]>([]);
@@ -102,6 +115,20 @@ function App() {
             <li>Total Issues Found: <strong>{stats.issues_found}</strong></li>
             <li>Categories Identified: <strong>{stats.categories}</strong></li>
           </ul>
+          {stats.sources && stats.sources.length > 0 && (
+            <>
+              <h3>Based on {stats.complaints} complaints from</h3>
+              <ul>
+                {stats.sources.map((source) => (
+                  <li key={source.source}>
+                    <strong>{source.source}</strong>: {source.complaints} complaints in {source.categories} categories,
+                    posted {formatDate(source.coverage_from)} to {formatDate(source.coverage_to)}
+                    {' '}(last updated {formatDate(source.last_updated)})
+                  </li>
+                ))}
+              </ul>
+            </>
+          )}
         </>
       )}
 